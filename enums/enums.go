@@ -401,6 +401,15 @@ const (
 	FPDF_FORMFLAG_READONLY FPDF_FORMFLAG = (1 << 0)
 	FPDF_FORMFLAG_REQUIRED FPDF_FORMFLAG = (1 << 1)
 	FPDF_FORMFLAG_NOEXPORT FPDF_FORMFLAG = (1 << 2)
+
+	// Form flags for text fields.
+	FPDF_FORMFLAG_TEXT_MULTILINE FPDF_FORMFLAG = (1 << 12)
+	FPDF_FORMFLAG_TEXT_PASSWORD  FPDF_FORMFLAG = (1 << 13)
+
+	// Form flags for choice fields.
+	FPDF_FORMFLAG_CHOICE_COMBO        FPDF_FORMFLAG = (1 << 17)
+	FPDF_FORMFLAG_CHOICE_EDIT         FPDF_FORMFLAG = (1 << 18)
+	FPDF_FORMFLAG_CHOICE_MULTI_SELECT FPDF_FORMFLAG = (1 << 21)
 )
 
 type FXCT int
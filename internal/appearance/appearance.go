@@ -0,0 +1,107 @@
+// Package appearance generates the content streams of annotation
+// appearances. The content streams are meant to be installed with
+// FPDFAnnot_SetAP, which uses the rect of the annotation as bounding box, so
+// the content is drawn in page coordinates. When the annotation is
+// transparent, FPDFAnnot_SetAP adds the opacity as the /GS graphics state.
+package appearance
+
+import (
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/klippa-app/go-pdfium/structs"
+)
+
+// contentStream builds a content stream.
+type contentStream struct {
+	builder strings.Builder
+}
+
+func (c *contentStream) op(operator string, operands ...float32) {
+	for i := range operands {
+		c.builder.WriteString(formatNumber(operands[i]))
+		c.builder.WriteByte(' ')
+	}
+	c.builder.WriteString(operator)
+	c.builder.WriteByte('\n')
+}
+
+// opacity selects the graphics state with the opacity of the annotation
+// when the annotation is transparent.
+func (c *contentStream) opacity(alpha uint) {
+	if alpha < 255 {
+		c.builder.WriteString("/GS gs\n")
+	}
+}
+
+func (c *contentStream) fillColor(color structs.FPDF_COLOR) {
+	c.op("rg", colorComponent(color.R), colorComponent(color.G), colorComponent(color.B))
+}
+
+func (c *contentStream) strokeColor(color structs.FPDF_COLOR) {
+	c.op("RG", colorComponent(color.R), colorComponent(color.G), colorComponent(color.B))
+}
+
+func (c *contentStream) moveTo(point point) {
+	c.op("m", point.x, point.y)
+}
+
+func (c *contentStream) lineTo(point point) {
+	c.op("l", point.x, point.y)
+}
+
+func (c *contentStream) curveTo(control1, control2, end point) {
+	c.op("c", control1.x, control1.y, control2.x, control2.y, end.x, end.y)
+}
+
+func (c *contentStream) rect(rect structs.FPDF_FS_RECTF) {
+	c.op("re", rect.Left, rect.Bottom, rect.Right-rect.Left, rect.Top-rect.Bottom)
+}
+
+func (c *contentStream) String() string {
+	return c.builder.String()
+}
+
+type point struct {
+	x float32
+	y float32
+}
+
+func colorComponent(value uint) float32 {
+	return float32(value) / 255
+}
+
+func formatNumber(value float32) string {
+	// Round to 3 decimals, that's precise enough for a content stream.
+	rounded := math.Round(float64(value)*1000) / 1000
+	if rounded == 0 {
+		// Prevent -0.
+		rounded = 0
+	}
+	return strconv.FormatFloat(rounded, 'f', -1, 64)
+}
+
+// QuadPointsRect returns the rect that encloses all the given quad points.
+func QuadPointsRect(quadPoints []structs.FPDF_FS_QUADPOINTSF) structs.FPDF_FS_RECTF {
+	rect := structs.FPDF_FS_RECTF{}
+	for i, quad := range quadPoints {
+		xs := []float32{quad.X1, quad.X2, quad.X3, quad.X4}
+		ys := []float32{quad.Y1, quad.Y2, quad.Y3, quad.Y4}
+		for j := range xs {
+			if (i == 0 && j == 0) || xs[j] < rect.Left {
+				rect.Left = xs[j]
+			}
+			if (i == 0 && j == 0) || xs[j] > rect.Right {
+				rect.Right = xs[j]
+			}
+			if (i == 0 && j == 0) || ys[j] < rect.Bottom {
+				rect.Bottom = ys[j]
+			}
+			if (i == 0 && j == 0) || ys[j] > rect.Top {
+				rect.Top = ys[j]
+			}
+		}
+	}
+	return rect
+}
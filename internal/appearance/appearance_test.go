@@ -0,0 +1,185 @@
+package appearance
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/klippa-app/go-pdfium/enums"
+	"github.com/klippa-app/go-pdfium/structs"
+)
+
+var testQuadPoints = []structs.FPDF_FS_QUADPOINTSF{
+	{X1: 10, Y1: 30, X2: 110, Y2: 30, X3: 10, Y3: 16, X4: 110, Y4: 16},
+	{X1: 10.5, Y1: 12, X2: 50, Y2: 12, X3: 10.5, Y3: -2, X4: 50, Y4: -2},
+}
+
+func TestQuadPointsRect(t *testing.T) {
+	rect := QuadPointsRect(testQuadPoints)
+	want := structs.FPDF_FS_RECTF{Left: 10, Top: 30, Right: 110, Bottom: -2}
+	if rect != want {
+		t.Fatalf("QuadPointsRect resulted in wrong rect, got %+v, want %+v", rect, want)
+	}
+}
+
+func TestTextMarkup(t *testing.T) {
+	content, err := TextMarkup(enums.FPDF_ANNOT_SUBTYPE_HIGHLIGHT, testQuadPoints, structs.FPDF_COLOR{R: 255, G: 255, B: 0, A: 128})
+	if err != nil {
+		t.Fatalf("TextMarkup resulted in error: %s", err.Error())
+	}
+
+	want := "q\n/GS gs\n1 1 0 rg\n10 16 m\n110 16 l\n110 30 l\n10 30 l\nh\nf\n10.5 -2 m\n50 -2 l\n50 12 l\n10.5 12 l\nh\nf\nQ\n"
+	if content != want {
+		t.Fatalf("TextMarkup resulted in wrong highlight, got %q, want %q", content, want)
+	}
+
+	content, err = TextMarkup(enums.FPDF_ANNOT_SUBTYPE_STRIKEOUT, testQuadPoints[:1], structs.FPDF_COLOR{R: 255, G: 0, B: 0, A: 255})
+	if err != nil {
+		t.Fatalf("TextMarkup resulted in error: %s", err.Error())
+	}
+
+	want = "q\n1 0 0 RG\n1 w\n10 23 m\n110 23 l\nS\nQ\n"
+	if content != want {
+		t.Fatalf("TextMarkup resulted in wrong strikeout, got %q, want %q", content, want)
+	}
+
+	for _, subtype := range []enums.FPDF_ANNOTATION_SUBTYPE{enums.FPDF_ANNOT_SUBTYPE_UNDERLINE, enums.FPDF_ANNOT_SUBTYPE_SQUIGGLY} {
+		content, err = TextMarkup(subtype, testQuadPoints, structs.FPDF_COLOR{R: 0, G: 0, B: 255, A: 255})
+		if err != nil {
+			t.Fatalf("TextMarkup resulted in error: %s", err.Error())
+		}
+
+		if strings.Count(content, "S\n") != 2 || !strings.HasSuffix(content, "Q\n") {
+			t.Fatalf("TextMarkup resulted in wrong lines for subtype %d, got %q", subtype, content)
+		}
+	}
+
+	_, err = TextMarkup(enums.FPDF_ANNOT_SUBTYPE_SQUARE, testQuadPoints, structs.FPDF_COLOR{})
+	if err == nil || err.Error() != "subtype 5 is not a text markup subtype" {
+		t.Fatalf("TextMarkup didn't reject the subtype, got %v", err)
+	}
+}
+
+func TestShapes(t *testing.T) {
+	style := Style{
+		Color:         &structs.FPDF_COLOR{R: 255, G: 0, B: 0, A: 255},
+		InteriorColor: &structs.FPDF_COLOR{R: 0, G: 255, B: 0, A: 255},
+		BorderWidth:   2,
+		Opacity:       255,
+	}
+
+	content := Square(structs.FPDF_FS_RECTF{Left: 10, Top: 50, Right: 110, Bottom: 10}, style)
+	want := "q\n1 0 0 RG\n2 w\n0 1 0 rg\n11 11 98 38 re\nB\nQ\n"
+	if content != want {
+		t.Fatalf("Square resulted in wrong content, got %q, want %q", content, want)
+	}
+
+	content = Circle(structs.FPDF_FS_RECTF{Left: 0, Top: 20, Right: 20, Bottom: 0}, Style{Color: style.Color, BorderWidth: 0, Opacity: 128})
+	if !strings.HasPrefix(content, "q\n/GS gs\n20 10 m\n") || strings.Count(content, " c\n") != 4 || !strings.HasSuffix(content, "h\nn\nQ\n") {
+		t.Fatalf("Circle resulted in wrong content, got %q", content)
+	}
+
+	content = Line(structs.FPDF_FS_POINTF{X: 1, Y: 2}, structs.FPDF_FS_POINTF{X: 3, Y: 4}, style)
+	want = "q\n1 0 0 RG\n2 w\n1 2 m\n3 4 l\nS\nQ\n"
+	if content != want {
+		t.Fatalf("Line resulted in wrong content, got %q, want %q", content, want)
+	}
+
+	content, err := Polygon([]structs.FPDF_FS_POINTF{{X: 0, Y: 0}, {X: 10, Y: 0}, {X: 5, Y: 10}}, true, style)
+	if err != nil {
+		t.Fatalf("Polygon resulted in error: %s", err.Error())
+	}
+	want = "q\n1 0 0 RG\n2 w\n0 1 0 rg\n0 0 m\n10 0 l\n5 10 l\nh\nB\nQ\n"
+	if content != want {
+		t.Fatalf("Polygon resulted in wrong content, got %q, want %q", content, want)
+	}
+
+	content, err = Polygon([]structs.FPDF_FS_POINTF{{X: 0, Y: 0}, {X: 10, Y: 0}}, false, style)
+	if err != nil {
+		t.Fatalf("Polygon resulted in error: %s", err.Error())
+	}
+	want = "q\n1 0 0 RG\n2 w\n0 0 m\n10 0 l\nS\nQ\n"
+	if content != want {
+		t.Fatalf("Polygon resulted in wrong polyline, got %q, want %q", content, want)
+	}
+
+	_, err = Polygon([]structs.FPDF_FS_POINTF{{X: 0, Y: 0}}, true, style)
+	if err == nil || err.Error() != "at least 2 vertices are needed" {
+		t.Fatalf("Polygon didn't reject the vertices, got %v", err)
+	}
+
+	content, err = Ink([][]structs.FPDF_FS_POINTF{{{X: 0, Y: 0}, {X: 10, Y: 10}}, {{X: 5, Y: 5}}}, style)
+	if err != nil {
+		t.Fatalf("Ink resulted in error: %s", err.Error())
+	}
+	want = "q\n1 0 0 RG\n2 w\n1 J\n1 j\n0 0 m\n10 10 l\nS\n5 5 m\n5 5 l\nS\nQ\n"
+	if content != want {
+		t.Fatalf("Ink resulted in wrong content, got %q, want %q", content, want)
+	}
+
+	_, err = Ink(nil, style)
+	if err == nil || err.Error() != "no ink paths given" {
+		t.Fatalf("Ink didn't reject the paths, got %v", err)
+	}
+}
+
+func TestFreeText(t *testing.T) {
+	// Every glyph is a square of half the font size, spaces are empty.
+	glyph := func(r rune) (*Glyph, error) {
+		if r == ' ' {
+			return &Glyph{Width: 5}, nil
+		}
+		return &Glyph{
+			Width: 5,
+			Segments: []GlyphSegment{
+				{Type: enums.FPDF_SEGMENT_MOVETO, X: 0, Y: 0},
+				{Type: enums.FPDF_SEGMENT_LINETO, X: 0.5, Y: 0},
+				{Type: enums.FPDF_SEGMENT_LINETO, X: 0.5, Y: 0.5},
+				{Type: enums.FPDF_SEGMENT_LINETO, X: 0, Y: 0.5, Close: true},
+			},
+		}, nil
+	}
+
+	text := FreeTextText{
+		Text:     "aa bb cccccccccccc\ndd",
+		FontSize: 10,
+		Ascent:   8,
+		Descent:  -2,
+		Color:    structs.FPDF_COLOR{R: 0, G: 0, B: 0, A: 255},
+		Glyph:    glyph,
+	}
+
+	lines, err := wrapText(text, 30)
+	if err != nil {
+		t.Fatalf("wrapText resulted in error: %s", err.Error())
+	}
+
+	lineLengths := []int{}
+	for _, line := range lines {
+		lineLengths = append(lineLengths, len(line.glyphs))
+	}
+	wantLengths := []int{5, 6, 6, 2}
+	if !reflect.DeepEqual(lineLengths, wantLengths) {
+		t.Fatalf("wrapText resulted in wrong lines, got %v, want %v", lineLengths, wantLengths)
+	}
+
+	content, err := FreeText(structs.FPDF_FS_RECTF{Left: 0, Top: 100, Right: 34, Bottom: 0}, Style{BorderWidth: 1, Opacity: 255}, text)
+	if err != nil {
+		t.Fatalf("FreeText resulted in error: %s", err.Error())
+	}
+
+	// The first glyph starts at the padding, the baseline is the ascent
+	// below the padding.
+	if !strings.Contains(content, "2 2 30 96 re\nW\nn\n0 0 0 rg\n2 90 m\n7 90 l\n7 95 l\n2 95 l\nh\n") || strings.Count(content, "f\n") != 4 {
+		t.Fatalf("FreeText resulted in wrong content, got %q", content)
+	}
+
+	text.Glyph = func(r rune) (*Glyph, error) {
+		return nil, errors.New("no glyph")
+	}
+	_, err = FreeText(structs.FPDF_FS_RECTF{Left: 0, Top: 100, Right: 34, Bottom: 0}, Style{}, text)
+	if err == nil || err.Error() != "no glyph" {
+		t.Fatalf("FreeText didn't return the glyph error, got %v", err)
+	}
+}
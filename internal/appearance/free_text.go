@@ -0,0 +1,228 @@
+package appearance
+
+import (
+	"strings"
+
+	"github.com/klippa-app/go-pdfium/enums"
+	"github.com/klippa-app/go-pdfium/structs"
+)
+
+// GlyphSegment is a segment of the path of a glyph, in text space of a font
+// size of 1.
+type GlyphSegment struct {
+	Type  enums.FPDF_SEGMENT
+	X     float32
+	Y     float32
+	Close bool // Whether the segment closes the figure.
+}
+
+// Glyph is the outline of a character.
+type Glyph struct {
+	Width    float32 // The advance width at the font size of the text.
+	Segments []GlyphSegment
+}
+
+// TextAlignment is the alignment (Q) of the text of a free text annotation.
+type TextAlignment int
+
+const (
+	TextAlignmentLeft   TextAlignment = 0
+	TextAlignmentCenter TextAlignment = 1
+	TextAlignmentRight  TextAlignment = 2
+)
+
+// FreeTextText is the text of a free text annotation.
+type FreeTextText struct {
+	Text      string
+	FontSize  float32
+	Ascent    float32 // The ascent of the font at the font size.
+	Descent   float32 // The descent of the font at the font size, negative when below the baseline.
+	Color     structs.FPDF_COLOR
+	Alignment TextAlignment
+	Glyph     func(r rune) (*Glyph, error) // Returns the glyph of a character.
+}
+
+// FreeText returns the content stream of the appearance of a free text
+// annotation. The text is wrapped to fit the width of the rect and drawn as
+// the outlines of the glyphs, so that the appearance doesn't need font
+// resources. Text that doesn't fit the height of the rect is clipped.
+func FreeText(rect structs.FPDF_FS_RECTF, style Style, text FreeTextText) (string, error) {
+	rect = normalizeRect(rect)
+
+	content := &contentStream{}
+	style.begin(content)
+	inset := float32(0)
+	if style.stroked() {
+		inset = style.BorderWidth / 2
+	}
+	content.rect(insetRect(rect, inset))
+	style.paint(content)
+
+	padding := float32(2)
+	if style.stroked() {
+		padding += style.BorderWidth
+	}
+	textRect := insetRect(rect, padding)
+
+	lines, err := wrapText(text, textRect.Right-textRect.Left)
+	if err != nil {
+		return "", err
+	}
+
+	content.rect(textRect)
+	content.op("W")
+	content.op("n")
+	content.fillColor(text.Color)
+
+	lineHeight := text.Ascent - text.Descent
+	baseline := textRect.Top - text.Ascent
+	for _, line := range lines {
+		if baseline+text.Ascent < textRect.Bottom {
+			// The line is outside of the clip.
+			break
+		}
+
+		x := textRect.Left
+		switch text.Alignment {
+		case TextAlignmentCenter:
+			x += (textRect.Right - textRect.Left - line.width) / 2
+		case TextAlignmentRight:
+			x += textRect.Right - textRect.Left - line.width
+		}
+
+		drawn := false
+		for _, glyph := range line.glyphs {
+			if drawGlyph(content, glyph, text.FontSize, x, baseline) {
+				drawn = true
+			}
+			x += glyph.Width
+		}
+		if drawn {
+			content.op("f")
+		}
+
+		baseline -= lineHeight
+	}
+
+	content.op("Q")
+	return content.String(), nil
+}
+
+// drawGlyph adds the outline of a glyph at the given origin to the path,
+// it returns whether the glyph has an outline.
+func drawGlyph(content *contentStream, glyph *Glyph, fontSize float32, x float32, y float32) bool {
+	transform := func(segment GlyphSegment) point {
+		return point{x: x + segment.X*fontSize, y: y + segment.Y*fontSize}
+	}
+
+	drawn := false
+	for i := 0; i < len(glyph.Segments); i++ {
+		segment := glyph.Segments[i]
+		switch segment.Type {
+		case enums.FPDF_SEGMENT_MOVETO:
+			content.moveTo(transform(segment))
+		case enums.FPDF_SEGMENT_LINETO:
+			content.lineTo(transform(segment))
+			drawn = true
+		case enums.FPDF_SEGMENT_BEZIERTO:
+			// A bezier curve is given as 3 segments, the 2 control points
+			// and the end point.
+			if i+2 >= len(glyph.Segments) {
+				return drawn
+			}
+			content.curveTo(transform(segment), transform(glyph.Segments[i+1]), transform(glyph.Segments[i+2]))
+			i += 2
+			segment = glyph.Segments[i]
+			drawn = true
+		default:
+			continue
+		}
+
+		if segment.Close {
+			content.op("h")
+		}
+	}
+
+	return drawn
+}
+
+type textLine struct {
+	glyphs []*Glyph
+	width  float32
+}
+
+// wrapText splits the text in lines that fit the given width. Lines are
+// broken at spaces, words that don't fit a line are broken anywhere.
+func wrapText(text FreeTextText, width float32) ([]textLine, error) {
+	glyphs := map[rune]*Glyph{}
+	getGlyph := func(r rune) (*Glyph, error) {
+		if glyph, ok := glyphs[r]; ok {
+			return glyph, nil
+		}
+		glyph, err := text.Glyph(r)
+		if err != nil {
+			return nil, err
+		}
+		glyphs[r] = glyph
+		return glyph, nil
+	}
+
+	normalized := strings.ReplaceAll(strings.ReplaceAll(text.Text, "\r\n", "\n"), "\r", "\n")
+
+	lines := []textLine{}
+	for _, paragraph := range strings.Split(normalized, "\n") {
+		line := textLine{}
+		for i, word := range strings.Split(paragraph, " ") {
+			wordLine := textLine{}
+			if i > 0 {
+				word = " " + word
+			}
+
+			for _, r := range word {
+				glyph, err := getGlyph(r)
+				if err != nil {
+					return nil, err
+				}
+				wordLine.glyphs = append(wordLine.glyphs, glyph)
+				wordLine.width += glyph.Width
+			}
+
+			if line.width+wordLine.width <= width || len(line.glyphs) == 0 && i == 0 {
+				line.glyphs = append(line.glyphs, wordLine.glyphs...)
+				line.width += wordLine.width
+			} else {
+				lines = append(lines, line)
+				line = textLine{}
+
+				// Don't start the new line with the space.
+				if i > 0 {
+					wordLine.glyphs = wordLine.glyphs[1:]
+					wordLine.width = 0
+					for _, glyph := range wordLine.glyphs {
+						wordLine.width += glyph.Width
+					}
+				}
+				line.glyphs = wordLine.glyphs
+				line.width = wordLine.width
+			}
+
+			// Break words that don't fit a line anywhere.
+			for line.width > width && len(line.glyphs) > 1 {
+				fitting := textLine{}
+				for _, glyph := range line.glyphs {
+					if fitting.width+glyph.Width > width && len(fitting.glyphs) > 0 {
+						break
+					}
+					fitting.glyphs = append(fitting.glyphs, glyph)
+					fitting.width += glyph.Width
+				}
+				lines = append(lines, fitting)
+				line.glyphs = line.glyphs[len(fitting.glyphs):]
+				line.width -= fitting.width
+			}
+		}
+		lines = append(lines, line)
+	}
+
+	return lines, nil
+}
@@ -0,0 +1,199 @@
+package appearance
+
+import (
+	"errors"
+
+	"github.com/klippa-app/go-pdfium/structs"
+)
+
+// Style is how the shape of an annotation is drawn.
+type Style struct {
+	Color         *structs.FPDF_COLOR // The color of the border or line, nil to not draw it.
+	InteriorColor *structs.FPDF_COLOR // The color to fill the shape with, nil to not fill it.
+	BorderWidth   float32             // The width of the border or line.
+	Opacity       uint                // The opacity of the annotation, 0 to 255.
+}
+
+func (s Style) stroked() bool {
+	return s.Color != nil && s.BorderWidth > 0
+}
+
+func (s Style) filled() bool {
+	return s.InteriorColor != nil
+}
+
+// begin writes the graphics state of the style.
+func (s Style) begin(content *contentStream) {
+	content.op("q")
+	content.opacity(s.Opacity)
+	if s.stroked() {
+		content.strokeColor(*s.Color)
+		content.op("w", s.BorderWidth)
+	}
+	if s.filled() {
+		content.fillColor(*s.InteriorColor)
+	}
+}
+
+// paint paints the current path with the style.
+func (s Style) paint(content *contentStream) {
+	switch {
+	case s.stroked() && s.filled():
+		content.op("B")
+	case s.stroked():
+		content.op("S")
+	case s.filled():
+		content.op("f")
+	default:
+		content.op("n")
+	}
+}
+
+// normalizeRect makes sure that the top of a rect is above the bottom and
+// the right is right of the left.
+func normalizeRect(rect structs.FPDF_FS_RECTF) structs.FPDF_FS_RECTF {
+	if rect.Top < rect.Bottom {
+		rect.Top, rect.Bottom = rect.Bottom, rect.Top
+	}
+	if rect.Right < rect.Left {
+		rect.Left, rect.Right = rect.Right, rect.Left
+	}
+	return rect
+}
+
+// insetRect returns the rect that is the given distance inside the rect.
+func insetRect(rect structs.FPDF_FS_RECTF, distance float32) structs.FPDF_FS_RECTF {
+	rect = normalizeRect(rect)
+	rect.Left += distance
+	rect.Bottom += distance
+	rect.Right -= distance
+	rect.Top -= distance
+	if rect.Right < rect.Left {
+		rect.Left = (rect.Left + rect.Right) / 2
+		rect.Right = rect.Left
+	}
+	if rect.Top < rect.Bottom {
+		rect.Bottom = (rect.Bottom + rect.Top) / 2
+		rect.Top = rect.Bottom
+	}
+	return rect
+}
+
+// Square returns the content stream of the appearance of a square
+// annotation, the border is drawn inside the rect.
+func Square(rect structs.FPDF_FS_RECTF, style Style) string {
+	content := &contentStream{}
+	style.begin(content)
+	inset := float32(0)
+	if style.stroked() {
+		inset = style.BorderWidth / 2
+	}
+	content.rect(insetRect(rect, inset))
+	style.paint(content)
+	content.op("Q")
+	return content.String()
+}
+
+// Circle returns the content stream of the appearance of a circle
+// annotation, an ellipse that fits in the rect with the border drawn inside
+// the rect.
+func Circle(rect structs.FPDF_FS_RECTF, style Style) string {
+	content := &contentStream{}
+	style.begin(content)
+	inset := float32(0)
+	if style.stroked() {
+		inset = style.BorderWidth / 2
+	}
+	rect = insetRect(rect, inset)
+
+	// The distance of the control points of a bezier curve that
+	// approximates a quarter of an ellipse.
+	const kappa = 0.5522847498
+	centerX, centerY := (rect.Left+rect.Right)/2, (rect.Bottom+rect.Top)/2
+	radiusX, radiusY := (rect.Right-rect.Left)/2, (rect.Top-rect.Bottom)/2
+	controlX, controlY := radiusX*kappa, radiusY*kappa
+
+	content.moveTo(point{x: centerX + radiusX, y: centerY})
+	content.curveTo(point{x: centerX + radiusX, y: centerY + controlY}, point{x: centerX + controlX, y: centerY + radiusY}, point{x: centerX, y: centerY + radiusY})
+	content.curveTo(point{x: centerX - controlX, y: centerY + radiusY}, point{x: centerX - radiusX, y: centerY + controlY}, point{x: centerX - radiusX, y: centerY})
+	content.curveTo(point{x: centerX - radiusX, y: centerY - controlY}, point{x: centerX - controlX, y: centerY - radiusY}, point{x: centerX, y: centerY - radiusY})
+	content.curveTo(point{x: centerX + controlX, y: centerY - radiusY}, point{x: centerX + radiusX, y: centerY - controlY}, point{x: centerX + radiusX, y: centerY})
+	content.op("h")
+	style.paint(content)
+	content.op("Q")
+	return content.String()
+}
+
+// Line returns the content stream of the appearance of a line annotation.
+// Line endings are not drawn.
+func Line(start structs.FPDF_FS_POINTF, end structs.FPDF_FS_POINTF, style Style) string {
+	// A line can't be filled.
+	style.InteriorColor = nil
+
+	content := &contentStream{}
+	style.begin(content)
+	content.moveTo(point{x: start.X, y: start.Y})
+	content.lineTo(point{x: end.X, y: end.Y})
+	style.paint(content)
+	content.op("Q")
+	return content.String()
+}
+
+// Polygon returns the content stream of the appearance of a polygon
+// annotation, or of a polyline annotation when it's not closed. A polyline
+// can't be filled.
+func Polygon(vertices []structs.FPDF_FS_POINTF, closed bool, style Style) (string, error) {
+	if len(vertices) < 2 {
+		return "", errors.New("at least 2 vertices are needed")
+	}
+
+	if !closed {
+		style.InteriorColor = nil
+	}
+
+	content := &contentStream{}
+	style.begin(content)
+	content.moveTo(point{x: vertices[0].X, y: vertices[0].Y})
+	for _, vertex := range vertices[1:] {
+		content.lineTo(point{x: vertex.X, y: vertex.Y})
+	}
+	if closed {
+		content.op("h")
+	}
+	style.paint(content)
+	content.op("Q")
+	return content.String(), nil
+}
+
+// Ink returns the content stream of the appearance of an ink annotation,
+// the paths are drawn with round caps and joins.
+func Ink(paths [][]structs.FPDF_FS_POINTF, style Style) (string, error) {
+	if len(paths) == 0 {
+		return "", errors.New("no ink paths given")
+	}
+
+	// Ink can't be filled.
+	style.InteriorColor = nil
+
+	content := &contentStream{}
+	style.begin(content)
+	content.op("J", 1)
+	content.op("j", 1)
+	for _, path := range paths {
+		if len(path) == 0 {
+			continue
+		}
+
+		content.moveTo(point{x: path[0].X, y: path[0].Y})
+		if len(path) == 1 {
+			// Draw a dot for a single point.
+			content.lineTo(point{x: path[0].X, y: path[0].Y})
+		}
+		for _, pathPoint := range path[1:] {
+			content.lineTo(point{x: pathPoint.X, y: pathPoint.Y})
+		}
+		style.paint(content)
+	}
+	content.op("Q")
+	return content.String(), nil
+}
@@ -0,0 +1,141 @@
+package appearance
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/klippa-app/go-pdfium/enums"
+	"github.com/klippa-app/go-pdfium/structs"
+)
+
+// IsTextMarkup returns whether the given subtype is a text markup subtype.
+func IsTextMarkup(subtype enums.FPDF_ANNOTATION_SUBTYPE) bool {
+	switch subtype {
+	case enums.FPDF_ANNOT_SUBTYPE_HIGHLIGHT, enums.FPDF_ANNOT_SUBTYPE_UNDERLINE, enums.FPDF_ANNOT_SUBTYPE_SQUIGGLY, enums.FPDF_ANNOT_SUBTYPE_STRIKEOUT:
+		return true
+	}
+	return false
+}
+
+// textQuad is a quad of text, with the position of the points given by the
+// distance along the baseline and the distance above the baseline, so that
+// rotated text is supported.
+type textQuad struct {
+	origin point
+	along  point // The unit vector along the baseline.
+	up     point // The unit vector from the bottom to the top of the text.
+	width  float32
+	height float32
+}
+
+func newTextQuad(quad structs.FPDF_FS_QUADPOINTSF) textQuad {
+	// The first two points are the top of the text, the last two points
+	// the bottom, from left to right.
+	textQuad := textQuad{
+		origin: point{x: quad.X3, y: quad.Y3},
+	}
+
+	alongX, alongY := quad.X4-quad.X3, quad.Y4-quad.Y3
+	textQuad.width = float32(math.Hypot(float64(alongX), float64(alongY)))
+	if textQuad.width > 0 {
+		textQuad.along = point{x: alongX / textQuad.width, y: alongY / textQuad.width}
+	} else {
+		textQuad.along = point{x: 1}
+	}
+
+	upX, upY := quad.X1-quad.X3, quad.Y1-quad.Y3
+	textQuad.height = float32(math.Hypot(float64(upX), float64(upY)))
+	if textQuad.height > 0 {
+		textQuad.up = point{x: upX / textQuad.height, y: upY / textQuad.height}
+	} else {
+		textQuad.up = point{x: -textQuad.along.y, y: textQuad.along.x}
+	}
+
+	return textQuad
+}
+
+// point returns the point at the given distance along the baseline and
+// above the baseline.
+func (q textQuad) point(along, up float32) point {
+	return point{
+		x: q.origin.x + along*q.along.x + up*q.up.x,
+		y: q.origin.y + along*q.along.y + up*q.up.y,
+	}
+}
+
+// lineWidth returns the width of lines that mark the text.
+func (q textQuad) lineWidth() float32 {
+	return float32(math.Max(float64(q.height/14), 0.5))
+}
+
+// TextMarkup returns the content stream of the appearance of a text markup
+// annotation (highlight, underline, squiggly or strikeout) that marks the
+// given quad points. Highlights are drawn over the text, so they need a
+// transparent color to keep the text readable.
+func TextMarkup(subtype enums.FPDF_ANNOTATION_SUBTYPE, quadPoints []structs.FPDF_FS_QUADPOINTSF, color structs.FPDF_COLOR) (string, error) {
+	if !IsTextMarkup(subtype) {
+		return "", fmt.Errorf("subtype %d is not a text markup subtype", subtype)
+	}
+
+	content := &contentStream{}
+	content.op("q")
+	content.opacity(color.A)
+
+	if subtype == enums.FPDF_ANNOT_SUBTYPE_HIGHLIGHT {
+		content.fillColor(color)
+	} else {
+		content.strokeColor(color)
+	}
+
+	for i := range quadPoints {
+		quad := newTextQuad(quadPoints[i])
+
+		switch subtype {
+		case enums.FPDF_ANNOT_SUBTYPE_HIGHLIGHT:
+			content.moveTo(quad.point(0, 0))
+			content.lineTo(quad.point(quad.width, 0))
+			content.lineTo(quad.point(quad.width, quad.height))
+			content.lineTo(quad.point(0, quad.height))
+			content.op("h")
+			content.op("f")
+		case enums.FPDF_ANNOT_SUBTYPE_UNDERLINE, enums.FPDF_ANNOT_SUBTYPE_STRIKEOUT:
+			lineWidth := quad.lineWidth()
+			up := lineWidth / 2
+			if subtype == enums.FPDF_ANNOT_SUBTYPE_STRIKEOUT {
+				up = quad.height / 2
+			}
+			content.op("w", lineWidth)
+			content.moveTo(quad.point(0, up))
+			content.lineTo(quad.point(quad.width, up))
+			content.op("S")
+		case enums.FPDF_ANNOT_SUBTYPE_SQUIGGLY:
+			lineWidth := quad.lineWidth()
+			amplitude := float32(math.Max(float64(quad.height/12), float64(lineWidth)))
+			step := amplitude * 2
+			content.op("w", lineWidth)
+			content.moveTo(quad.point(0, lineWidth/2))
+			up := true
+			for along := step; ; along += step {
+				if along > quad.width {
+					along = quad.width
+				}
+
+				height := lineWidth / 2
+				if up {
+					height += amplitude
+				}
+				content.lineTo(quad.point(along, height))
+				up = !up
+
+				if along == quad.width {
+					break
+				}
+			}
+			content.op("S")
+		}
+	}
+
+	content.op("Q")
+
+	return content.String(), nil
+}
@@ -12,6 +12,14 @@ import (
 
 type Pdfium interface {
 	Ping() (string, error)
+	AddHeaderFooter(*requests.AddHeaderFooter) (*responses.AddHeaderFooter, error)
+	CheckAccessibility(*requests.CheckAccessibility) (*responses.CheckAccessibility, error)
+	CompareDocuments(*requests.CompareDocuments) (*responses.CompareDocuments, error)
+	CompareDocumentsText(*requests.CompareDocumentsText) (*responses.CompareDocumentsText, error)
+	CreateTextMarkupAnnotations(*requests.CreateTextMarkupAnnotations) (*responses.CreateTextMarkupAnnotations, error)
+	CropPages(*requests.CropPages) (*responses.CropPages, error)
+	ExportAnnotations(*requests.ExportAnnotations) (*responses.ExportAnnotations, error)
+	ExportFormData(*requests.ExportFormData) (*responses.ExportFormData, error)
 	FORM_CanRedo(*requests.FORM_CanRedo) (*responses.FORM_CanRedo, error)
 	FORM_CanUndo(*requests.FORM_CanUndo) (*responses.FORM_CanUndo, error)
 	FORM_DoDocumentAAction(*requests.FORM_DoDocumentAAction) (*responses.FORM_DoDocumentAAction, error)
@@ -439,25 +447,123 @@ type Pdfium interface {
 	FSDK_SetLocaltimeFunction(*requests.FSDK_SetLocaltimeFunction) (*responses.FSDK_SetLocaltimeFunction, error)
 	FSDK_SetTimeFunction(*requests.FSDK_SetTimeFunction) (*responses.FSDK_SetTimeFunction, error)
 	FSDK_SetUnSpObjProcessHandler(*requests.FSDK_SetUnSpObjProcessHandler) (*responses.FSDK_SetUnSpObjProcessHandler, error)
+	FillFormFields(*requests.FillFormFields) (*responses.FillFormFields, error)
+	FlattenDocument(*requests.FlattenDocument) (*responses.FlattenDocument, error)
+	GenerateAnnotationAppearance(*requests.GenerateAnnotationAppearance) (*responses.GenerateAnnotationAppearance, error)
 	GetActionInfo(*requests.GetActionInfo) (*responses.GetActionInfo, error)
+	GetAnnotations(*requests.GetAnnotations) (*responses.GetAnnotations, error)
 	GetAttachments(*requests.GetAttachments) (*responses.GetAttachments, error)
 	GetBookmarks(*requests.GetBookmarks) (*responses.GetBookmarks, error)
 	GetDestInfo(*requests.GetDestInfo) (*responses.GetDestInfo, error)
+	GetFonts(*requests.GetFonts) (*responses.GetFonts, error)
+	GetFormFields(*requests.GetFormFields) (*responses.GetFormFields, error)
 	GetJavaScriptActions(*requests.GetJavaScriptActions) (*responses.GetJavaScriptActions, error)
 	GetMetaData(*requests.GetMetaData) (*responses.GetMetaData, error)
 	GetPageSize(*requests.GetPageSize) (*responses.GetPageSize, error)
 	GetPageSizeInPixels(*requests.GetPageSizeInPixels) (*responses.GetPageSizeInPixels, error)
 	GetPageText(*requests.GetPageText) (*responses.GetPageText, error)
 	GetPageTextStructured(*requests.GetPageTextStructured) (*responses.GetPageTextStructured, error)
+	GetPageThumbnail(*requests.GetPageThumbnail) (*responses.GetPageThumbnail, error)
+	GetStructTree(*requests.GetStructTree) (*responses.GetStructTree, error)
+	ImportAnnotations(*requests.ImportAnnotations) (*responses.ImportAnnotations, error)
+	ImportFormData(*requests.ImportFormData) (*responses.ImportFormData, error)
 	OpenDocument(*requests.OpenDocument) (*responses.OpenDocument, error)
+	OptimizeDocument(*requests.OptimizeDocument) (*responses.OptimizeDocument, error)
+	Preflight(*requests.Preflight) (*responses.Preflight, error)
 	RenderPageInDPI(*requests.RenderPageInDPI) (*responses.RenderPageInDPI, error)
 	RenderPageInPixels(*requests.RenderPageInPixels) (*responses.RenderPageInPixels, error)
 	RenderPagesInDPI(*requests.RenderPagesInDPI) (*responses.RenderPagesInDPI, error)
 	RenderPagesInPixels(*requests.RenderPagesInPixels) (*responses.RenderPagesInPixels, error)
 	RenderToFile(*requests.RenderToFile) (*responses.RenderToFile, error)
+	ResizePages(*requests.ResizePages) (*responses.ResizePages, error)
+	SetBookmarks(*requests.SetBookmarks) (*responses.SetBookmarks, error)
+	SetMetaData(*requests.SetMetaData) (*responses.SetMetaData, error)
+	SetPageLabels(*requests.SetPageLabels) (*responses.SetPageLabels, error)
+	SignDocument(*requests.SignDocument) (*responses.SignDocument, error)
+	VerifySignatures(*requests.VerifySignatures) (*responses.VerifySignatures, error)
 	Close() error
 }
 
+func (g *PdfiumRPC) AddHeaderFooter(request *requests.AddHeaderFooter) (*responses.AddHeaderFooter, error) {
+	resp := &responses.AddHeaderFooter{}
+	err := g.client.Call("Plugin.AddHeaderFooter", request, resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+func (g *PdfiumRPC) CheckAccessibility(request *requests.CheckAccessibility) (*responses.CheckAccessibility, error) {
+	resp := &responses.CheckAccessibility{}
+	err := g.client.Call("Plugin.CheckAccessibility", request, resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+func (g *PdfiumRPC) CompareDocuments(request *requests.CompareDocuments) (*responses.CompareDocuments, error) {
+	resp := &responses.CompareDocuments{}
+	err := g.client.Call("Plugin.CompareDocuments", request, resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+func (g *PdfiumRPC) CompareDocumentsText(request *requests.CompareDocumentsText) (*responses.CompareDocumentsText, error) {
+	resp := &responses.CompareDocumentsText{}
+	err := g.client.Call("Plugin.CompareDocumentsText", request, resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+func (g *PdfiumRPC) CreateTextMarkupAnnotations(request *requests.CreateTextMarkupAnnotations) (*responses.CreateTextMarkupAnnotations, error) {
+	resp := &responses.CreateTextMarkupAnnotations{}
+	err := g.client.Call("Plugin.CreateTextMarkupAnnotations", request, resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+func (g *PdfiumRPC) CropPages(request *requests.CropPages) (*responses.CropPages, error) {
+	resp := &responses.CropPages{}
+	err := g.client.Call("Plugin.CropPages", request, resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+func (g *PdfiumRPC) ExportAnnotations(request *requests.ExportAnnotations) (*responses.ExportAnnotations, error) {
+	resp := &responses.ExportAnnotations{}
+	err := g.client.Call("Plugin.ExportAnnotations", request, resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+func (g *PdfiumRPC) ExportFormData(request *requests.ExportFormData) (*responses.ExportFormData, error) {
+	resp := &responses.ExportFormData{}
+	err := g.client.Call("Plugin.ExportFormData", request, resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
 func (g *PdfiumRPC) FORM_CanRedo(request *requests.FORM_CanRedo) (*responses.FORM_CanRedo, error) {
 	resp := &responses.FORM_CanRedo{}
 	err := g.client.Call("Plugin.FORM_CanRedo", request, resp)
@@ -4728,6 +4834,36 @@ func (g *PdfiumRPC) FSDK_SetUnSpObjProcessHandler(request *requests.FSDK_SetUnSp
 	return resp, nil
 }
 
+func (g *PdfiumRPC) FillFormFields(request *requests.FillFormFields) (*responses.FillFormFields, error) {
+	resp := &responses.FillFormFields{}
+	err := g.client.Call("Plugin.FillFormFields", request, resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+func (g *PdfiumRPC) FlattenDocument(request *requests.FlattenDocument) (*responses.FlattenDocument, error) {
+	resp := &responses.FlattenDocument{}
+	err := g.client.Call("Plugin.FlattenDocument", request, resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+func (g *PdfiumRPC) GenerateAnnotationAppearance(request *requests.GenerateAnnotationAppearance) (*responses.GenerateAnnotationAppearance, error) {
+	resp := &responses.GenerateAnnotationAppearance{}
+	err := g.client.Call("Plugin.GenerateAnnotationAppearance", request, resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
 func (g *PdfiumRPC) GetActionInfo(request *requests.GetActionInfo) (*responses.GetActionInfo, error) {
 	resp := &responses.GetActionInfo{}
 	err := g.client.Call("Plugin.GetActionInfo", request, resp)
@@ -4738,6 +4874,16 @@ func (g *PdfiumRPC) GetActionInfo(request *requests.GetActionInfo) (*responses.G
 	return resp, nil
 }
 
+func (g *PdfiumRPC) GetAnnotations(request *requests.GetAnnotations) (*responses.GetAnnotations, error) {
+	resp := &responses.GetAnnotations{}
+	err := g.client.Call("Plugin.GetAnnotations", request, resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
 func (g *PdfiumRPC) GetAttachments(request *requests.GetAttachments) (*responses.GetAttachments, error) {
 	resp := &responses.GetAttachments{}
 	err := g.client.Call("Plugin.GetAttachments", request, resp)
@@ -4768,6 +4914,26 @@ func (g *PdfiumRPC) GetDestInfo(request *requests.GetDestInfo) (*responses.GetDe
 	return resp, nil
 }
 
+func (g *PdfiumRPC) GetFonts(request *requests.GetFonts) (*responses.GetFonts, error) {
+	resp := &responses.GetFonts{}
+	err := g.client.Call("Plugin.GetFonts", request, resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+func (g *PdfiumRPC) GetFormFields(request *requests.GetFormFields) (*responses.GetFormFields, error) {
+	resp := &responses.GetFormFields{}
+	err := g.client.Call("Plugin.GetFormFields", request, resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
 func (g *PdfiumRPC) GetJavaScriptActions(request *requests.GetJavaScriptActions) (*responses.GetJavaScriptActions, error) {
 	resp := &responses.GetJavaScriptActions{}
 	err := g.client.Call("Plugin.GetJavaScriptActions", request, resp)
@@ -4828,6 +4994,46 @@ func (g *PdfiumRPC) GetPageTextStructured(request *requests.GetPageTextStructure
 	return resp, nil
 }
 
+func (g *PdfiumRPC) GetPageThumbnail(request *requests.GetPageThumbnail) (*responses.GetPageThumbnail, error) {
+	resp := &responses.GetPageThumbnail{}
+	err := g.client.Call("Plugin.GetPageThumbnail", request, resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+func (g *PdfiumRPC) GetStructTree(request *requests.GetStructTree) (*responses.GetStructTree, error) {
+	resp := &responses.GetStructTree{}
+	err := g.client.Call("Plugin.GetStructTree", request, resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+func (g *PdfiumRPC) ImportAnnotations(request *requests.ImportAnnotations) (*responses.ImportAnnotations, error) {
+	resp := &responses.ImportAnnotations{}
+	err := g.client.Call("Plugin.ImportAnnotations", request, resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+func (g *PdfiumRPC) ImportFormData(request *requests.ImportFormData) (*responses.ImportFormData, error) {
+	resp := &responses.ImportFormData{}
+	err := g.client.Call("Plugin.ImportFormData", request, resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
 func (g *PdfiumRPC) OpenDocument(request *requests.OpenDocument) (*responses.OpenDocument, error) {
 	resp := &responses.OpenDocument{}
 	err := g.client.Call("Plugin.OpenDocument", request, resp)
@@ -4838,6 +5044,26 @@ func (g *PdfiumRPC) OpenDocument(request *requests.OpenDocument) (*responses.Ope
 	return resp, nil
 }
 
+func (g *PdfiumRPC) OptimizeDocument(request *requests.OptimizeDocument) (*responses.OptimizeDocument, error) {
+	resp := &responses.OptimizeDocument{}
+	err := g.client.Call("Plugin.OptimizeDocument", request, resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+func (g *PdfiumRPC) Preflight(request *requests.Preflight) (*responses.Preflight, error) {
+	resp := &responses.Preflight{}
+	err := g.client.Call("Plugin.Preflight", request, resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
 func (g *PdfiumRPC) RenderPageInDPI(request *requests.RenderPageInDPI) (*responses.RenderPageInDPI, error) {
 	resp := &responses.RenderPageInDPI{}
 	err := g.client.Call("Plugin.RenderPageInDPI", request, resp)
@@ -4888,14 +5114,74 @@ func (g *PdfiumRPC) RenderToFile(request *requests.RenderToFile) (*responses.Ren
 	return resp, nil
 }
 
-func (s *PdfiumRPCServer) FORM_CanRedo(request *requests.FORM_CanRedo, resp *responses.FORM_CanRedo) (err error) {
+func (g *PdfiumRPC) ResizePages(request *requests.ResizePages) (*responses.ResizePages, error) {
+	resp := &responses.ResizePages{}
+	err := g.client.Call("Plugin.ResizePages", request, resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+func (g *PdfiumRPC) SetBookmarks(request *requests.SetBookmarks) (*responses.SetBookmarks, error) {
+	resp := &responses.SetBookmarks{}
+	err := g.client.Call("Plugin.SetBookmarks", request, resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+func (g *PdfiumRPC) SetMetaData(request *requests.SetMetaData) (*responses.SetMetaData, error) {
+	resp := &responses.SetMetaData{}
+	err := g.client.Call("Plugin.SetMetaData", request, resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+func (g *PdfiumRPC) SetPageLabels(request *requests.SetPageLabels) (*responses.SetPageLabels, error) {
+	resp := &responses.SetPageLabels{}
+	err := g.client.Call("Plugin.SetPageLabels", request, resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+func (g *PdfiumRPC) SignDocument(request *requests.SignDocument) (*responses.SignDocument, error) {
+	resp := &responses.SignDocument{}
+	err := g.client.Call("Plugin.SignDocument", request, resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+func (g *PdfiumRPC) VerifySignatures(request *requests.VerifySignatures) (*responses.VerifySignatures, error) {
+	resp := &responses.VerifySignatures{}
+	err := g.client.Call("Plugin.VerifySignatures", request, resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+func (s *PdfiumRPCServer) AddHeaderFooter(request *requests.AddHeaderFooter, resp *responses.AddHeaderFooter) (err error) {
 	defer func() {
 		if panicError := recover(); panicError != nil {
-			err = fmt.Errorf("panic occurred in %s: %v", "FORM_CanRedo", panicError)
+			err = fmt.Errorf("panic occurred in %s: %v", "AddHeaderFooter", panicError)
 		}
 	}()
 
-	implResp, err := s.Impl.FORM_CanRedo(request)
+	implResp, err := s.Impl.AddHeaderFooter(request)
 	if err != nil {
 		return err
 	}
@@ -4906,14 +5192,14 @@ func (s *PdfiumRPCServer) FORM_CanRedo(request *requests.FORM_CanRedo, resp *res
 	return nil
 }
 
-func (s *PdfiumRPCServer) FORM_CanUndo(request *requests.FORM_CanUndo, resp *responses.FORM_CanUndo) (err error) {
+func (s *PdfiumRPCServer) CheckAccessibility(request *requests.CheckAccessibility, resp *responses.CheckAccessibility) (err error) {
 	defer func() {
 		if panicError := recover(); panicError != nil {
-			err = fmt.Errorf("panic occurred in %s: %v", "FORM_CanUndo", panicError)
+			err = fmt.Errorf("panic occurred in %s: %v", "CheckAccessibility", panicError)
 		}
 	}()
 
-	implResp, err := s.Impl.FORM_CanUndo(request)
+	implResp, err := s.Impl.CheckAccessibility(request)
 	if err != nil {
 		return err
 	}
@@ -4924,14 +5210,14 @@ func (s *PdfiumRPCServer) FORM_CanUndo(request *requests.FORM_CanUndo, resp *res
 	return nil
 }
 
-func (s *PdfiumRPCServer) FORM_DoDocumentAAction(request *requests.FORM_DoDocumentAAction, resp *responses.FORM_DoDocumentAAction) (err error) {
+func (s *PdfiumRPCServer) CompareDocuments(request *requests.CompareDocuments, resp *responses.CompareDocuments) (err error) {
 	defer func() {
 		if panicError := recover(); panicError != nil {
-			err = fmt.Errorf("panic occurred in %s: %v", "FORM_DoDocumentAAction", panicError)
+			err = fmt.Errorf("panic occurred in %s: %v", "CompareDocuments", panicError)
 		}
 	}()
 
-	implResp, err := s.Impl.FORM_DoDocumentAAction(request)
+	implResp, err := s.Impl.CompareDocuments(request)
 	if err != nil {
 		return err
 	}
@@ -4942,14 +5228,14 @@ func (s *PdfiumRPCServer) FORM_DoDocumentAAction(request *requests.FORM_DoDocume
 	return nil
 }
 
-func (s *PdfiumRPCServer) FORM_DoDocumentJSAction(request *requests.FORM_DoDocumentJSAction, resp *responses.FORM_DoDocumentJSAction) (err error) {
+func (s *PdfiumRPCServer) CompareDocumentsText(request *requests.CompareDocumentsText, resp *responses.CompareDocumentsText) (err error) {
 	defer func() {
 		if panicError := recover(); panicError != nil {
-			err = fmt.Errorf("panic occurred in %s: %v", "FORM_DoDocumentJSAction", panicError)
+			err = fmt.Errorf("panic occurred in %s: %v", "CompareDocumentsText", panicError)
 		}
 	}()
 
-	implResp, err := s.Impl.FORM_DoDocumentJSAction(request)
+	implResp, err := s.Impl.CompareDocumentsText(request)
 	if err != nil {
 		return err
 	}
@@ -4960,14 +5246,14 @@ func (s *PdfiumRPCServer) FORM_DoDocumentJSAction(request *requests.FORM_DoDocum
 	return nil
 }
 
-func (s *PdfiumRPCServer) FORM_DoDocumentOpenAction(request *requests.FORM_DoDocumentOpenAction, resp *responses.FORM_DoDocumentOpenAction) (err error) {
+func (s *PdfiumRPCServer) CreateTextMarkupAnnotations(request *requests.CreateTextMarkupAnnotations, resp *responses.CreateTextMarkupAnnotations) (err error) {
 	defer func() {
 		if panicError := recover(); panicError != nil {
-			err = fmt.Errorf("panic occurred in %s: %v", "FORM_DoDocumentOpenAction", panicError)
+			err = fmt.Errorf("panic occurred in %s: %v", "CreateTextMarkupAnnotations", panicError)
 		}
 	}()
 
-	implResp, err := s.Impl.FORM_DoDocumentOpenAction(request)
+	implResp, err := s.Impl.CreateTextMarkupAnnotations(request)
 	if err != nil {
 		return err
 	}
@@ -4978,14 +5264,14 @@ func (s *PdfiumRPCServer) FORM_DoDocumentOpenAction(request *requests.FORM_DoDoc
 	return nil
 }
 
-func (s *PdfiumRPCServer) FORM_DoPageAAction(request *requests.FORM_DoPageAAction, resp *responses.FORM_DoPageAAction) (err error) {
+func (s *PdfiumRPCServer) CropPages(request *requests.CropPages, resp *responses.CropPages) (err error) {
 	defer func() {
 		if panicError := recover(); panicError != nil {
-			err = fmt.Errorf("panic occurred in %s: %v", "FORM_DoPageAAction", panicError)
+			err = fmt.Errorf("panic occurred in %s: %v", "CropPages", panicError)
 		}
 	}()
 
-	implResp, err := s.Impl.FORM_DoPageAAction(request)
+	implResp, err := s.Impl.CropPages(request)
 	if err != nil {
 		return err
 	}
@@ -4996,14 +5282,14 @@ func (s *PdfiumRPCServer) FORM_DoPageAAction(request *requests.FORM_DoPageAActio
 	return nil
 }
 
-func (s *PdfiumRPCServer) FORM_ForceToKillFocus(request *requests.FORM_ForceToKillFocus, resp *responses.FORM_ForceToKillFocus) (err error) {
+func (s *PdfiumRPCServer) ExportAnnotations(request *requests.ExportAnnotations, resp *responses.ExportAnnotations) (err error) {
 	defer func() {
 		if panicError := recover(); panicError != nil {
-			err = fmt.Errorf("panic occurred in %s: %v", "FORM_ForceToKillFocus", panicError)
+			err = fmt.Errorf("panic occurred in %s: %v", "ExportAnnotations", panicError)
 		}
 	}()
 
-	implResp, err := s.Impl.FORM_ForceToKillFocus(request)
+	implResp, err := s.Impl.ExportAnnotations(request)
 	if err != nil {
 		return err
 	}
@@ -5014,14 +5300,14 @@ func (s *PdfiumRPCServer) FORM_ForceToKillFocus(request *requests.FORM_ForceToKi
 	return nil
 }
 
-func (s *PdfiumRPCServer) FORM_GetFocusedAnnot(request *requests.FORM_GetFocusedAnnot, resp *responses.FORM_GetFocusedAnnot) (err error) {
+func (s *PdfiumRPCServer) ExportFormData(request *requests.ExportFormData, resp *responses.ExportFormData) (err error) {
 	defer func() {
 		if panicError := recover(); panicError != nil {
-			err = fmt.Errorf("panic occurred in %s: %v", "FORM_GetFocusedAnnot", panicError)
+			err = fmt.Errorf("panic occurred in %s: %v", "ExportFormData", panicError)
 		}
 	}()
 
-	implResp, err := s.Impl.FORM_GetFocusedAnnot(request)
+	implResp, err := s.Impl.ExportFormData(request)
 	if err != nil {
 		return err
 	}
@@ -5032,14 +5318,14 @@ func (s *PdfiumRPCServer) FORM_GetFocusedAnnot(request *requests.FORM_GetFocused
 	return nil
 }
 
-func (s *PdfiumRPCServer) FORM_GetFocusedText(request *requests.FORM_GetFocusedText, resp *responses.FORM_GetFocusedText) (err error) {
+func (s *PdfiumRPCServer) FORM_CanRedo(request *requests.FORM_CanRedo, resp *responses.FORM_CanRedo) (err error) {
 	defer func() {
 		if panicError := recover(); panicError != nil {
-			err = fmt.Errorf("panic occurred in %s: %v", "FORM_GetFocusedText", panicError)
+			err = fmt.Errorf("panic occurred in %s: %v", "FORM_CanRedo", panicError)
 		}
 	}()
 
-	implResp, err := s.Impl.FORM_GetFocusedText(request)
+	implResp, err := s.Impl.FORM_CanRedo(request)
 	if err != nil {
 		return err
 	}
@@ -5050,14 +5336,14 @@ func (s *PdfiumRPCServer) FORM_GetFocusedText(request *requests.FORM_GetFocusedT
 	return nil
 }
 
-func (s *PdfiumRPCServer) FORM_GetSelectedText(request *requests.FORM_GetSelectedText, resp *responses.FORM_GetSelectedText) (err error) {
+func (s *PdfiumRPCServer) FORM_CanUndo(request *requests.FORM_CanUndo, resp *responses.FORM_CanUndo) (err error) {
 	defer func() {
 		if panicError := recover(); panicError != nil {
-			err = fmt.Errorf("panic occurred in %s: %v", "FORM_GetSelectedText", panicError)
+			err = fmt.Errorf("panic occurred in %s: %v", "FORM_CanUndo", panicError)
 		}
 	}()
 
-	implResp, err := s.Impl.FORM_GetSelectedText(request)
+	implResp, err := s.Impl.FORM_CanUndo(request)
 	if err != nil {
 		return err
 	}
@@ -5068,14 +5354,14 @@ func (s *PdfiumRPCServer) FORM_GetSelectedText(request *requests.FORM_GetSelecte
 	return nil
 }
 
-func (s *PdfiumRPCServer) FORM_IsIndexSelected(request *requests.FORM_IsIndexSelected, resp *responses.FORM_IsIndexSelected) (err error) {
+func (s *PdfiumRPCServer) FORM_DoDocumentAAction(request *requests.FORM_DoDocumentAAction, resp *responses.FORM_DoDocumentAAction) (err error) {
 	defer func() {
 		if panicError := recover(); panicError != nil {
-			err = fmt.Errorf("panic occurred in %s: %v", "FORM_IsIndexSelected", panicError)
+			err = fmt.Errorf("panic occurred in %s: %v", "FORM_DoDocumentAAction", panicError)
 		}
 	}()
 
-	implResp, err := s.Impl.FORM_IsIndexSelected(request)
+	implResp, err := s.Impl.FORM_DoDocumentAAction(request)
 	if err != nil {
 		return err
 	}
@@ -5086,14 +5372,14 @@ func (s *PdfiumRPCServer) FORM_IsIndexSelected(request *requests.FORM_IsIndexSel
 	return nil
 }
 
-func (s *PdfiumRPCServer) FORM_OnAfterLoadPage(request *requests.FORM_OnAfterLoadPage, resp *responses.FORM_OnAfterLoadPage) (err error) {
+func (s *PdfiumRPCServer) FORM_DoDocumentJSAction(request *requests.FORM_DoDocumentJSAction, resp *responses.FORM_DoDocumentJSAction) (err error) {
 	defer func() {
 		if panicError := recover(); panicError != nil {
-			err = fmt.Errorf("panic occurred in %s: %v", "FORM_OnAfterLoadPage", panicError)
+			err = fmt.Errorf("panic occurred in %s: %v", "FORM_DoDocumentJSAction", panicError)
 		}
 	}()
 
-	implResp, err := s.Impl.FORM_OnAfterLoadPage(request)
+	implResp, err := s.Impl.FORM_DoDocumentJSAction(request)
 	if err != nil {
 		return err
 	}
@@ -5104,14 +5390,14 @@ func (s *PdfiumRPCServer) FORM_OnAfterLoadPage(request *requests.FORM_OnAfterLoa
 	return nil
 }
 
-func (s *PdfiumRPCServer) FORM_OnBeforeClosePage(request *requests.FORM_OnBeforeClosePage, resp *responses.FORM_OnBeforeClosePage) (err error) {
+func (s *PdfiumRPCServer) FORM_DoDocumentOpenAction(request *requests.FORM_DoDocumentOpenAction, resp *responses.FORM_DoDocumentOpenAction) (err error) {
 	defer func() {
 		if panicError := recover(); panicError != nil {
-			err = fmt.Errorf("panic occurred in %s: %v", "FORM_OnBeforeClosePage", panicError)
+			err = fmt.Errorf("panic occurred in %s: %v", "FORM_DoDocumentOpenAction", panicError)
 		}
 	}()
 
-	implResp, err := s.Impl.FORM_OnBeforeClosePage(request)
+	implResp, err := s.Impl.FORM_DoDocumentOpenAction(request)
 	if err != nil {
 		return err
 	}
@@ -5122,14 +5408,14 @@ func (s *PdfiumRPCServer) FORM_OnBeforeClosePage(request *requests.FORM_OnBefore
 	return nil
 }
 
-func (s *PdfiumRPCServer) FORM_OnChar(request *requests.FORM_OnChar, resp *responses.FORM_OnChar) (err error) {
+func (s *PdfiumRPCServer) FORM_DoPageAAction(request *requests.FORM_DoPageAAction, resp *responses.FORM_DoPageAAction) (err error) {
 	defer func() {
 		if panicError := recover(); panicError != nil {
-			err = fmt.Errorf("panic occurred in %s: %v", "FORM_OnChar", panicError)
+			err = fmt.Errorf("panic occurred in %s: %v", "FORM_DoPageAAction", panicError)
 		}
 	}()
 
-	implResp, err := s.Impl.FORM_OnChar(request)
+	implResp, err := s.Impl.FORM_DoPageAAction(request)
 	if err != nil {
 		return err
 	}
@@ -5140,14 +5426,14 @@ func (s *PdfiumRPCServer) FORM_OnChar(request *requests.FORM_OnChar, resp *respo
 	return nil
 }
 
-func (s *PdfiumRPCServer) FORM_OnFocus(request *requests.FORM_OnFocus, resp *responses.FORM_OnFocus) (err error) {
+func (s *PdfiumRPCServer) FORM_ForceToKillFocus(request *requests.FORM_ForceToKillFocus, resp *responses.FORM_ForceToKillFocus) (err error) {
 	defer func() {
 		if panicError := recover(); panicError != nil {
-			err = fmt.Errorf("panic occurred in %s: %v", "FORM_OnFocus", panicError)
+			err = fmt.Errorf("panic occurred in %s: %v", "FORM_ForceToKillFocus", panicError)
 		}
 	}()
 
-	implResp, err := s.Impl.FORM_OnFocus(request)
+	implResp, err := s.Impl.FORM_ForceToKillFocus(request)
 	if err != nil {
 		return err
 	}
@@ -5158,14 +5444,14 @@ func (s *PdfiumRPCServer) FORM_OnFocus(request *requests.FORM_OnFocus, resp *res
 	return nil
 }
 
-func (s *PdfiumRPCServer) FORM_OnKeyDown(request *requests.FORM_OnKeyDown, resp *responses.FORM_OnKeyDown) (err error) {
+func (s *PdfiumRPCServer) FORM_GetFocusedAnnot(request *requests.FORM_GetFocusedAnnot, resp *responses.FORM_GetFocusedAnnot) (err error) {
 	defer func() {
 		if panicError := recover(); panicError != nil {
-			err = fmt.Errorf("panic occurred in %s: %v", "FORM_OnKeyDown", panicError)
+			err = fmt.Errorf("panic occurred in %s: %v", "FORM_GetFocusedAnnot", panicError)
 		}
 	}()
 
-	implResp, err := s.Impl.FORM_OnKeyDown(request)
+	implResp, err := s.Impl.FORM_GetFocusedAnnot(request)
 	if err != nil {
 		return err
 	}
@@ -5176,7 +5462,151 @@ func (s *PdfiumRPCServer) FORM_OnKeyDown(request *requests.FORM_OnKeyDown, resp
 	return nil
 }
 
-func (s *PdfiumRPCServer) FORM_OnKeyUp(request *requests.FORM_OnKeyUp, resp *responses.FORM_OnKeyUp) (err error) {
+func (s *PdfiumRPCServer) FORM_GetFocusedText(request *requests.FORM_GetFocusedText, resp *responses.FORM_GetFocusedText) (err error) {
+	defer func() {
+		if panicError := recover(); panicError != nil {
+			err = fmt.Errorf("panic occurred in %s: %v", "FORM_GetFocusedText", panicError)
+		}
+	}()
+
+	implResp, err := s.Impl.FORM_GetFocusedText(request)
+	if err != nil {
+		return err
+	}
+
+	// Overwrite the target address of resp to the target address of implResp.
+	*resp = *implResp
+
+	return nil
+}
+
+func (s *PdfiumRPCServer) FORM_GetSelectedText(request *requests.FORM_GetSelectedText, resp *responses.FORM_GetSelectedText) (err error) {
+	defer func() {
+		if panicError := recover(); panicError != nil {
+			err = fmt.Errorf("panic occurred in %s: %v", "FORM_GetSelectedText", panicError)
+		}
+	}()
+
+	implResp, err := s.Impl.FORM_GetSelectedText(request)
+	if err != nil {
+		return err
+	}
+
+	// Overwrite the target address of resp to the target address of implResp.
+	*resp = *implResp
+
+	return nil
+}
+
+func (s *PdfiumRPCServer) FORM_IsIndexSelected(request *requests.FORM_IsIndexSelected, resp *responses.FORM_IsIndexSelected) (err error) {
+	defer func() {
+		if panicError := recover(); panicError != nil {
+			err = fmt.Errorf("panic occurred in %s: %v", "FORM_IsIndexSelected", panicError)
+		}
+	}()
+
+	implResp, err := s.Impl.FORM_IsIndexSelected(request)
+	if err != nil {
+		return err
+	}
+
+	// Overwrite the target address of resp to the target address of implResp.
+	*resp = *implResp
+
+	return nil
+}
+
+func (s *PdfiumRPCServer) FORM_OnAfterLoadPage(request *requests.FORM_OnAfterLoadPage, resp *responses.FORM_OnAfterLoadPage) (err error) {
+	defer func() {
+		if panicError := recover(); panicError != nil {
+			err = fmt.Errorf("panic occurred in %s: %v", "FORM_OnAfterLoadPage", panicError)
+		}
+	}()
+
+	implResp, err := s.Impl.FORM_OnAfterLoadPage(request)
+	if err != nil {
+		return err
+	}
+
+	// Overwrite the target address of resp to the target address of implResp.
+	*resp = *implResp
+
+	return nil
+}
+
+func (s *PdfiumRPCServer) FORM_OnBeforeClosePage(request *requests.FORM_OnBeforeClosePage, resp *responses.FORM_OnBeforeClosePage) (err error) {
+	defer func() {
+		if panicError := recover(); panicError != nil {
+			err = fmt.Errorf("panic occurred in %s: %v", "FORM_OnBeforeClosePage", panicError)
+		}
+	}()
+
+	implResp, err := s.Impl.FORM_OnBeforeClosePage(request)
+	if err != nil {
+		return err
+	}
+
+	// Overwrite the target address of resp to the target address of implResp.
+	*resp = *implResp
+
+	return nil
+}
+
+func (s *PdfiumRPCServer) FORM_OnChar(request *requests.FORM_OnChar, resp *responses.FORM_OnChar) (err error) {
+	defer func() {
+		if panicError := recover(); panicError != nil {
+			err = fmt.Errorf("panic occurred in %s: %v", "FORM_OnChar", panicError)
+		}
+	}()
+
+	implResp, err := s.Impl.FORM_OnChar(request)
+	if err != nil {
+		return err
+	}
+
+	// Overwrite the target address of resp to the target address of implResp.
+	*resp = *implResp
+
+	return nil
+}
+
+func (s *PdfiumRPCServer) FORM_OnFocus(request *requests.FORM_OnFocus, resp *responses.FORM_OnFocus) (err error) {
+	defer func() {
+		if panicError := recover(); panicError != nil {
+			err = fmt.Errorf("panic occurred in %s: %v", "FORM_OnFocus", panicError)
+		}
+	}()
+
+	implResp, err := s.Impl.FORM_OnFocus(request)
+	if err != nil {
+		return err
+	}
+
+	// Overwrite the target address of resp to the target address of implResp.
+	*resp = *implResp
+
+	return nil
+}
+
+func (s *PdfiumRPCServer) FORM_OnKeyDown(request *requests.FORM_OnKeyDown, resp *responses.FORM_OnKeyDown) (err error) {
+	defer func() {
+		if panicError := recover(); panicError != nil {
+			err = fmt.Errorf("panic occurred in %s: %v", "FORM_OnKeyDown", panicError)
+		}
+	}()
+
+	implResp, err := s.Impl.FORM_OnKeyDown(request)
+	if err != nil {
+		return err
+	}
+
+	// Overwrite the target address of resp to the target address of implResp.
+	*resp = *implResp
+
+	return nil
+}
+
+func (s *PdfiumRPCServer) FORM_OnKeyUp(request *requests.FORM_OnKeyUp, resp *responses.FORM_OnKeyUp) (err error) {
 	defer func() {
 		if panicError := recover(); panicError != nil {
 			err = fmt.Errorf("panic occurred in %s: %v", "FORM_OnKeyUp", panicError)
@@ -12574,6 +13004,60 @@ func (s *PdfiumRPCServer) FSDK_SetUnSpObjProcessHandler(request *requests.FSDK_S
 	return nil
 }
 
+func (s *PdfiumRPCServer) FillFormFields(request *requests.FillFormFields, resp *responses.FillFormFields) (err error) {
+	defer func() {
+		if panicError := recover(); panicError != nil {
+			err = fmt.Errorf("panic occurred in %s: %v", "FillFormFields", panicError)
+		}
+	}()
+
+	implResp, err := s.Impl.FillFormFields(request)
+	if err != nil {
+		return err
+	}
+
+	// Overwrite the target address of resp to the target address of implResp.
+	*resp = *implResp
+
+	return nil
+}
+
+func (s *PdfiumRPCServer) FlattenDocument(request *requests.FlattenDocument, resp *responses.FlattenDocument) (err error) {
+	defer func() {
+		if panicError := recover(); panicError != nil {
+			err = fmt.Errorf("panic occurred in %s: %v", "FlattenDocument", panicError)
+		}
+	}()
+
+	implResp, err := s.Impl.FlattenDocument(request)
+	if err != nil {
+		return err
+	}
+
+	// Overwrite the target address of resp to the target address of implResp.
+	*resp = *implResp
+
+	return nil
+}
+
+func (s *PdfiumRPCServer) GenerateAnnotationAppearance(request *requests.GenerateAnnotationAppearance, resp *responses.GenerateAnnotationAppearance) (err error) {
+	defer func() {
+		if panicError := recover(); panicError != nil {
+			err = fmt.Errorf("panic occurred in %s: %v", "GenerateAnnotationAppearance", panicError)
+		}
+	}()
+
+	implResp, err := s.Impl.GenerateAnnotationAppearance(request)
+	if err != nil {
+		return err
+	}
+
+	// Overwrite the target address of resp to the target address of implResp.
+	*resp = *implResp
+
+	return nil
+}
+
 func (s *PdfiumRPCServer) GetActionInfo(request *requests.GetActionInfo, resp *responses.GetActionInfo) (err error) {
 	defer func() {
 		if panicError := recover(); panicError != nil {
@@ -12592,6 +13076,24 @@ func (s *PdfiumRPCServer) GetActionInfo(request *requests.GetActionInfo, resp *r
 	return nil
 }
 
+func (s *PdfiumRPCServer) GetAnnotations(request *requests.GetAnnotations, resp *responses.GetAnnotations) (err error) {
+	defer func() {
+		if panicError := recover(); panicError != nil {
+			err = fmt.Errorf("panic occurred in %s: %v", "GetAnnotations", panicError)
+		}
+	}()
+
+	implResp, err := s.Impl.GetAnnotations(request)
+	if err != nil {
+		return err
+	}
+
+	// Overwrite the target address of resp to the target address of implResp.
+	*resp = *implResp
+
+	return nil
+}
+
 func (s *PdfiumRPCServer) GetAttachments(request *requests.GetAttachments, resp *responses.GetAttachments) (err error) {
 	defer func() {
 		if panicError := recover(); panicError != nil {
@@ -12646,6 +13148,42 @@ func (s *PdfiumRPCServer) GetDestInfo(request *requests.GetDestInfo, resp *respo
 	return nil
 }
 
+func (s *PdfiumRPCServer) GetFonts(request *requests.GetFonts, resp *responses.GetFonts) (err error) {
+	defer func() {
+		if panicError := recover(); panicError != nil {
+			err = fmt.Errorf("panic occurred in %s: %v", "GetFonts", panicError)
+		}
+	}()
+
+	implResp, err := s.Impl.GetFonts(request)
+	if err != nil {
+		return err
+	}
+
+	// Overwrite the target address of resp to the target address of implResp.
+	*resp = *implResp
+
+	return nil
+}
+
+func (s *PdfiumRPCServer) GetFormFields(request *requests.GetFormFields, resp *responses.GetFormFields) (err error) {
+	defer func() {
+		if panicError := recover(); panicError != nil {
+			err = fmt.Errorf("panic occurred in %s: %v", "GetFormFields", panicError)
+		}
+	}()
+
+	implResp, err := s.Impl.GetFormFields(request)
+	if err != nil {
+		return err
+	}
+
+	// Overwrite the target address of resp to the target address of implResp.
+	*resp = *implResp
+
+	return nil
+}
+
 func (s *PdfiumRPCServer) GetJavaScriptActions(request *requests.GetJavaScriptActions, resp *responses.GetJavaScriptActions) (err error) {
 	defer func() {
 		if panicError := recover(); panicError != nil {
@@ -12754,6 +13292,78 @@ func (s *PdfiumRPCServer) GetPageTextStructured(request *requests.GetPageTextStr
 	return nil
 }
 
+func (s *PdfiumRPCServer) GetPageThumbnail(request *requests.GetPageThumbnail, resp *responses.GetPageThumbnail) (err error) {
+	defer func() {
+		if panicError := recover(); panicError != nil {
+			err = fmt.Errorf("panic occurred in %s: %v", "GetPageThumbnail", panicError)
+		}
+	}()
+
+	implResp, err := s.Impl.GetPageThumbnail(request)
+	if err != nil {
+		return err
+	}
+
+	// Overwrite the target address of resp to the target address of implResp.
+	*resp = *implResp
+
+	return nil
+}
+
+func (s *PdfiumRPCServer) GetStructTree(request *requests.GetStructTree, resp *responses.GetStructTree) (err error) {
+	defer func() {
+		if panicError := recover(); panicError != nil {
+			err = fmt.Errorf("panic occurred in %s: %v", "GetStructTree", panicError)
+		}
+	}()
+
+	implResp, err := s.Impl.GetStructTree(request)
+	if err != nil {
+		return err
+	}
+
+	// Overwrite the target address of resp to the target address of implResp.
+	*resp = *implResp
+
+	return nil
+}
+
+func (s *PdfiumRPCServer) ImportAnnotations(request *requests.ImportAnnotations, resp *responses.ImportAnnotations) (err error) {
+	defer func() {
+		if panicError := recover(); panicError != nil {
+			err = fmt.Errorf("panic occurred in %s: %v", "ImportAnnotations", panicError)
+		}
+	}()
+
+	implResp, err := s.Impl.ImportAnnotations(request)
+	if err != nil {
+		return err
+	}
+
+	// Overwrite the target address of resp to the target address of implResp.
+	*resp = *implResp
+
+	return nil
+}
+
+func (s *PdfiumRPCServer) ImportFormData(request *requests.ImportFormData, resp *responses.ImportFormData) (err error) {
+	defer func() {
+		if panicError := recover(); panicError != nil {
+			err = fmt.Errorf("panic occurred in %s: %v", "ImportFormData", panicError)
+		}
+	}()
+
+	implResp, err := s.Impl.ImportFormData(request)
+	if err != nil {
+		return err
+	}
+
+	// Overwrite the target address of resp to the target address of implResp.
+	*resp = *implResp
+
+	return nil
+}
+
 func (s *PdfiumRPCServer) OpenDocument(request *requests.OpenDocument, resp *responses.OpenDocument) (err error) {
 	defer func() {
 		if panicError := recover(); panicError != nil {
@@ -12772,6 +13382,42 @@ func (s *PdfiumRPCServer) OpenDocument(request *requests.OpenDocument, resp *res
 	return nil
 }
 
+func (s *PdfiumRPCServer) OptimizeDocument(request *requests.OptimizeDocument, resp *responses.OptimizeDocument) (err error) {
+	defer func() {
+		if panicError := recover(); panicError != nil {
+			err = fmt.Errorf("panic occurred in %s: %v", "OptimizeDocument", panicError)
+		}
+	}()
+
+	implResp, err := s.Impl.OptimizeDocument(request)
+	if err != nil {
+		return err
+	}
+
+	// Overwrite the target address of resp to the target address of implResp.
+	*resp = *implResp
+
+	return nil
+}
+
+func (s *PdfiumRPCServer) Preflight(request *requests.Preflight, resp *responses.Preflight) (err error) {
+	defer func() {
+		if panicError := recover(); panicError != nil {
+			err = fmt.Errorf("panic occurred in %s: %v", "Preflight", panicError)
+		}
+	}()
+
+	implResp, err := s.Impl.Preflight(request)
+	if err != nil {
+		return err
+	}
+
+	// Overwrite the target address of resp to the target address of implResp.
+	*resp = *implResp
+
+	return nil
+}
+
 func (s *PdfiumRPCServer) RenderPageInDPI(request *requests.RenderPageInDPI, resp *responses.RenderPageInDPI) (err error) {
 	defer func() {
 		if panicError := recover(); panicError != nil {
@@ -12861,3 +13507,111 @@ func (s *PdfiumRPCServer) RenderToFile(request *requests.RenderToFile, resp *res
 
 	return nil
 }
+
+func (s *PdfiumRPCServer) ResizePages(request *requests.ResizePages, resp *responses.ResizePages) (err error) {
+	defer func() {
+		if panicError := recover(); panicError != nil {
+			err = fmt.Errorf("panic occurred in %s: %v", "ResizePages", panicError)
+		}
+	}()
+
+	implResp, err := s.Impl.ResizePages(request)
+	if err != nil {
+		return err
+	}
+
+	// Overwrite the target address of resp to the target address of implResp.
+	*resp = *implResp
+
+	return nil
+}
+
+func (s *PdfiumRPCServer) SetBookmarks(request *requests.SetBookmarks, resp *responses.SetBookmarks) (err error) {
+	defer func() {
+		if panicError := recover(); panicError != nil {
+			err = fmt.Errorf("panic occurred in %s: %v", "SetBookmarks", panicError)
+		}
+	}()
+
+	implResp, err := s.Impl.SetBookmarks(request)
+	if err != nil {
+		return err
+	}
+
+	// Overwrite the target address of resp to the target address of implResp.
+	*resp = *implResp
+
+	return nil
+}
+
+func (s *PdfiumRPCServer) SetMetaData(request *requests.SetMetaData, resp *responses.SetMetaData) (err error) {
+	defer func() {
+		if panicError := recover(); panicError != nil {
+			err = fmt.Errorf("panic occurred in %s: %v", "SetMetaData", panicError)
+		}
+	}()
+
+	implResp, err := s.Impl.SetMetaData(request)
+	if err != nil {
+		return err
+	}
+
+	// Overwrite the target address of resp to the target address of implResp.
+	*resp = *implResp
+
+	return nil
+}
+
+func (s *PdfiumRPCServer) SetPageLabels(request *requests.SetPageLabels, resp *responses.SetPageLabels) (err error) {
+	defer func() {
+		if panicError := recover(); panicError != nil {
+			err = fmt.Errorf("panic occurred in %s: %v", "SetPageLabels", panicError)
+		}
+	}()
+
+	implResp, err := s.Impl.SetPageLabels(request)
+	if err != nil {
+		return err
+	}
+
+	// Overwrite the target address of resp to the target address of implResp.
+	*resp = *implResp
+
+	return nil
+}
+
+func (s *PdfiumRPCServer) SignDocument(request *requests.SignDocument, resp *responses.SignDocument) (err error) {
+	defer func() {
+		if panicError := recover(); panicError != nil {
+			err = fmt.Errorf("panic occurred in %s: %v", "SignDocument", panicError)
+		}
+	}()
+
+	implResp, err := s.Impl.SignDocument(request)
+	if err != nil {
+		return err
+	}
+
+	// Overwrite the target address of resp to the target address of implResp.
+	*resp = *implResp
+
+	return nil
+}
+
+func (s *PdfiumRPCServer) VerifySignatures(request *requests.VerifySignatures, resp *responses.VerifySignatures) (err error) {
+	defer func() {
+		if panicError := recover(); panicError != nil {
+			err = fmt.Errorf("panic occurred in %s: %v", "VerifySignatures", panicError)
+		}
+	}()
+
+	implResp, err := s.Impl.VerifySignatures(request)
+	if err != nil {
+		return err
+	}
+
+	// Overwrite the target address of resp to the target address of implResp.
+	*resp = *implResp
+
+	return nil
+}
@@ -0,0 +1,170 @@
+// Package diff calculates the differences between two lists of strings.
+package diff
+
+// EditType is the type of an edit.
+type EditType int
+
+const (
+	EditTypeEqual  EditType = iota // The string is in both lists.
+	EditTypeInsert                 // The string is only in the modified list.
+	EditTypeDelete                 // The string is only in the original list.
+)
+
+// Edit is one step to change the original list into the modified list.
+type Edit struct {
+	Type  EditType
+	Index int // The index of the string in the modified list for inserts, in the original list otherwise.
+}
+
+// Strings returns the shortest list of edits to change the original strings
+// into the modified strings, using the linear space variant of the diff
+// algorithm of Eugene W. Myers. The memory that is used grows with the
+// length of the lists, not with the amount of differences.
+func Strings(original, modified []string) []Edit {
+	d := &differ{
+		original: original,
+		modified: modified,
+		edits:    make([]Edit, 0, len(original)+len(modified)),
+	}
+	d.diff(0, len(original), 0, len(modified))
+	return d.edits
+}
+
+type differ struct {
+	original []string
+	modified []string
+	edits    []Edit
+}
+
+// diff appends the edits to change original[originalStart:originalEnd]
+// into modified[modifiedStart:modifiedEnd].
+func (d *differ) diff(originalStart, originalEnd, modifiedStart, modifiedEnd int) {
+	// Common strings at the start and the end are not part of the search.
+	for originalStart < originalEnd && modifiedStart < modifiedEnd && d.original[originalStart] == d.modified[modifiedStart] {
+		d.edits = append(d.edits, Edit{Type: EditTypeEqual, Index: originalStart})
+		originalStart++
+		modifiedStart++
+	}
+
+	commonEnd := 0
+	for originalStart < originalEnd-commonEnd && modifiedStart < modifiedEnd-commonEnd && d.original[originalEnd-commonEnd-1] == d.modified[modifiedEnd-commonEnd-1] {
+		commonEnd++
+	}
+	originalEnd -= commonEnd
+	modifiedEnd -= commonEnd
+
+	switch {
+	case originalStart == originalEnd:
+		for i := modifiedStart; i < modifiedEnd; i++ {
+			d.edits = append(d.edits, Edit{Type: EditTypeInsert, Index: i})
+		}
+	case modifiedStart == modifiedEnd:
+		for i := originalStart; i < originalEnd; i++ {
+			d.edits = append(d.edits, Edit{Type: EditTypeDelete, Index: i})
+		}
+	default:
+		x, y := d.split(originalStart, originalEnd, modifiedStart, modifiedEnd)
+		d.diff(originalStart, x, modifiedStart, y)
+		d.diff(x, originalEnd, y, modifiedEnd)
+	}
+
+	for i := 0; i < commonEnd; i++ {
+		d.edits = append(d.edits, Edit{Type: EditTypeEqual, Index: originalEnd + i})
+	}
+}
+
+// split returns a point on a shortest path through the given ranges, by
+// searching from the start and from the end at the same time until the
+// paths overlap in the middle. Both ranges must not be empty.
+func (d *differ) split(originalStart, originalEnd, modifiedStart, modifiedEnd int) (int, int) {
+	original := d.original[originalStart:originalEnd]
+	modified := d.modified[modifiedStart:modifiedEnd]
+	n := len(original)
+	m := len(modified)
+
+	// forward[offset+k] is the furthest index in the original strings that
+	// is reached from the start on diagonal k, where k is the index in the
+	// original strings minus the index in the modified strings. backward
+	// does the same from the end, -1 when the diagonal is not reached.
+	maxD := (n + m + 1) / 2
+	offset := maxD + 1
+	forward := make([]int, 2*offset+1)
+	backward := make([]int, 2*offset+1)
+	for i := range forward {
+		forward[i] = -1
+		backward[i] = -1
+	}
+	forward[offset+1] = 0
+	backward[offset+1] = 0
+
+	// When the difference in length is odd, the paths overlap while
+	// searching forward, otherwise while searching backward.
+	delta := n - m
+	checkForward := delta%2 != 0
+
+	// The diagonals that left the ranges are skipped.
+	forwardStart, forwardEnd, backwardStart, backwardEnd := 0, 0, 0, 0
+
+	for step := 0; step < maxD; step++ {
+		for k := -step + forwardStart; k <= step-forwardEnd; k += 2 {
+			var x int
+			if k == -step || (k != step && forward[offset+k-1] < forward[offset+k+1]) {
+				x = forward[offset+k+1]
+			} else {
+				x = forward[offset+k-1] + 1
+			}
+
+			y := x - k
+			for x < n && y < m && original[x] == modified[y] {
+				x++
+				y++
+			}
+			forward[offset+k] = x
+
+			if x > n {
+				forwardEnd += 2
+			} else if y > m {
+				forwardStart += 2
+			} else if checkForward {
+				backwardK := offset + delta - k
+				if backwardK >= 0 && backwardK < len(backward) && backward[backwardK] != -1 && x >= n-backward[backwardK] {
+					return originalStart + x, modifiedStart + y
+				}
+			}
+		}
+
+		for k := -step + backwardStart; k <= step-backwardEnd; k += 2 {
+			var x int
+			if k == -step || (k != step && backward[offset+k-1] < backward[offset+k+1]) {
+				x = backward[offset+k+1]
+			} else {
+				x = backward[offset+k-1] + 1
+			}
+
+			y := x - k
+			for x < n && y < m && original[n-x-1] == modified[m-y-1] {
+				x++
+				y++
+			}
+			backward[offset+k] = x
+
+			if x > n {
+				backwardEnd += 2
+			} else if y > m {
+				backwardStart += 2
+			} else if !checkForward {
+				forwardK := offset + delta - k
+				if forwardK >= 0 && forwardK < len(forward) && forward[forwardK] != -1 {
+					forwardX := forward[forwardK]
+					if forwardX >= n-x {
+						return originalStart + forwardX, modifiedStart + forwardX - (forwardK - offset)
+					}
+				}
+			}
+		}
+	}
+
+	// The paths always overlap before this, deleting and inserting all the
+	// strings is a valid path that makes the ranges smaller.
+	return originalStart + n, modifiedStart
+}
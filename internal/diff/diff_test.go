@@ -0,0 +1,101 @@
+package diff
+
+import (
+	"strings"
+	"testing"
+)
+
+// apply changes the original strings with the edits, to check that the
+// edits result in the modified strings.
+func apply(t *testing.T, original, modified []string, edits []Edit) []string {
+	result := []string{}
+	originalIndex := 0
+	for _, edit := range edits {
+		switch edit.Type {
+		case EditTypeEqual:
+			if edit.Index != originalIndex {
+				t.Fatalf("Strings resulted in an equal edit at %d, want %d", edit.Index, originalIndex)
+			}
+			result = append(result, original[edit.Index])
+			originalIndex++
+		case EditTypeDelete:
+			if edit.Index != originalIndex {
+				t.Fatalf("Strings resulted in a delete edit at %d, want %d", edit.Index, originalIndex)
+			}
+			originalIndex++
+		case EditTypeInsert:
+			result = append(result, modified[edit.Index])
+		}
+	}
+
+	if originalIndex != len(original) {
+		t.Fatalf("Strings didn't go through all original strings, got %d, want %d", originalIndex, len(original))
+	}
+
+	return result
+}
+
+func countChanges(edits []Edit) int {
+	changes := 0
+	for _, edit := range edits {
+		if edit.Type != EditTypeEqual {
+			changes++
+		}
+	}
+	return changes
+}
+
+func TestStrings(t *testing.T) {
+	tests := []struct {
+		original string
+		modified string
+		changes  int
+	}{
+		{"", "", 0},
+		{"a b c", "a b c", 0},
+		{"", "a b", 2},
+		{"a b", "", 2},
+		{"a b c a b b a", "c b a b a c", 5},
+		{"the quick brown fox", "the slow brown dog", 4},
+		{"a b c d e f", "x a b c d e f y", 2},
+		{"a", "b", 2},
+	}
+
+	for _, test := range tests {
+		original := strings.Fields(test.original)
+		modified := strings.Fields(test.modified)
+		edits := Strings(original, modified)
+
+		result := apply(t, original, modified, edits)
+		if strings.Join(result, " ") != strings.Join(modified, " ") {
+			t.Fatalf("Strings(%q, %q) resulted in wrong edits, got %q", test.original, test.modified, strings.Join(result, " "))
+		}
+
+		if countChanges(edits) != test.changes {
+			t.Fatalf("Strings(%q, %q) resulted in wrong amount of changes, got %d, want %d", test.original, test.modified, countChanges(edits), test.changes)
+		}
+	}
+}
+
+func TestStringsDifferentLists(t *testing.T) {
+	original := make([]string, 3000)
+	modified := make([]string, 3000)
+	for i := range original {
+		original[i] = "original"
+		modified[i] = "modified"
+		if i%100 == 0 {
+			original[i] = "same"
+			modified[i] = "same"
+		}
+	}
+
+	edits := Strings(original, modified)
+	result := apply(t, original, modified, edits)
+	if strings.Join(result, " ") != strings.Join(modified, " ") {
+		t.Fatalf("Strings resulted in wrong edits")
+	}
+
+	if countChanges(edits) != 5940 {
+		t.Fatalf("Strings resulted in wrong amount of changes, got %d, want %d", countChanges(edits), 5940)
+	}
+}
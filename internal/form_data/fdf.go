@@ -0,0 +1,194 @@
+package form_data
+
+import (
+	"errors"
+
+	"github.com/klippa-app/go-pdfium/internal/pdf_update"
+)
+
+func writeFDF(formData FormData) ([]byte, error) {
+	fdf := pdf_update.Dictionary{
+		"Fields": fdfFields(buildFieldTree(formData.Fields)),
+	}
+
+	if formData.FileName != "" {
+		fdf["F"] = pdf_update.TextString(formData.FileName)
+	}
+
+	return pdf_update.WriteFDF(fdf)
+}
+
+func fdfFields(nodes []*fieldNode) pdf_update.Array {
+	fields := pdf_update.Array{}
+	for _, node := range nodes {
+		field := pdf_update.Dictionary{
+			"T": pdf_update.TextString(node.partialName),
+		}
+
+		if node.field != nil {
+			values := node.field.Values
+			if len(values) == 1 && node.field.IsState {
+				field["V"] = pdf_update.Name(values[0])
+			} else if len(values) == 1 {
+				field["V"] = pdf_update.TextString(values[0])
+			} else {
+				// An empty array is a listbox without selected options.
+				valueArray := pdf_update.Array{}
+				for i := range values {
+					valueArray = append(valueArray, pdf_update.TextString(values[i]))
+				}
+				field["V"] = valueArray
+			}
+		}
+
+		if len(node.kids) > 0 {
+			field["Kids"] = fdfFields(node.kids)
+		}
+
+		fields = append(fields, field)
+	}
+
+	return fields
+}
+
+func readFDF(data []byte) (*FormData, error) {
+	file, err := pdf_update.ParseFDF(data)
+	if err != nil {
+		return nil, err
+	}
+
+	catalog, err := resolveDictionary(file, file.Trailer()["Root"])
+	if err != nil {
+		return nil, err
+	}
+	if catalog == nil {
+		return nil, errors.New("could not find the FDF catalog")
+	}
+
+	fdf, err := resolveDictionary(file, catalog["FDF"])
+	if err != nil {
+		return nil, err
+	}
+	if fdf == nil {
+		return nil, errors.New("could not find the FDF dictionary")
+	}
+
+	formData := &FormData{
+		Fields: []Field{},
+	}
+
+	fileSpecification, err := file.Resolve(fdf["F"])
+	if err != nil {
+		return nil, err
+	}
+
+	if fileName, ok := textString(fileSpecification); ok {
+		formData.FileName = fileName
+	} else if fileSpecificationDictionary, ok := fileSpecification.(pdf_update.Dictionary); ok {
+		if fileName, ok := textString(fileSpecificationDictionary["UF"]); ok {
+			formData.FileName = fileName
+		} else if fileName, ok := textString(fileSpecificationDictionary["F"]); ok {
+			formData.FileName = fileName
+		}
+	}
+
+	err = readFDFFields(file, fdf["Fields"], "", formData, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	return formData, nil
+}
+
+// readFDFFields reads an array of fields and their kids into the form data.
+func readFDFFields(file *pdf_update.File, fieldsObject pdf_update.Object, parentName string, formData *FormData, depth int) error {
+	// Protect against loops in the hierarchy of fields.
+	if depth > 32 {
+		return errors.New("the hierarchy of fields is too deep")
+	}
+
+	fieldsObject, err := file.Resolve(fieldsObject)
+	if err != nil {
+		return err
+	}
+
+	fields, ok := fieldsObject.(pdf_update.Array)
+	if !ok {
+		return nil
+	}
+
+	for i := range fields {
+		field, err := resolveDictionary(file, fields[i])
+		if err != nil {
+			return err
+		}
+		if field == nil {
+			continue
+		}
+
+		partialName, _ := textString(field["T"])
+		name := joinFieldName(parentName, partialName)
+
+		value, err := file.Resolve(field["V"])
+		if err != nil {
+			return err
+		}
+
+		switch typedValue := value.(type) {
+		case pdf_update.Name:
+			formData.Fields = append(formData.Fields, Field{
+				Name:    name,
+				Values:  []string{string(typedValue)},
+				IsState: true,
+			})
+		case pdf_update.String, pdf_update.HexString:
+			decodedValue, _ := textString(typedValue)
+			formData.Fields = append(formData.Fields, Field{
+				Name:   name,
+				Values: []string{decodedValue},
+			})
+		case pdf_update.Array:
+			values := []string{}
+			for j := range typedValue {
+				if decodedValue, ok := textString(typedValue[j]); ok {
+					values = append(values, decodedValue)
+				}
+			}
+			formData.Fields = append(formData.Fields, Field{
+				Name:   name,
+				Values: values,
+			})
+		}
+
+		err = readFDFFields(file, field["Kids"], name, formData, depth+1)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// resolveDictionary resolves an object to a dictionary, nil is returned
+// when the object is not a dictionary.
+func resolveDictionary(file *pdf_update.File, object pdf_update.Object) (pdf_update.Dictionary, error) {
+	resolvedObject, err := file.Resolve(object)
+	if err != nil {
+		return nil, err
+	}
+
+	dictionary, _ := resolvedObject.(pdf_update.Dictionary)
+	return dictionary, nil
+}
+
+// textString decodes a literal or hexadecimal string.
+func textString(object pdf_update.Object) (string, bool) {
+	switch value := object.(type) {
+	case pdf_update.String:
+		return pdf_update.DecodeTextString(value), true
+	case pdf_update.HexString:
+		return pdf_update.DecodeTextString(value), true
+	}
+
+	return "", false
+}
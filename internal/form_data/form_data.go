@@ -0,0 +1,155 @@
+// Package form_data reads and writes the values of form fields in the FDF
+// and XFDF formats.
+package form_data
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/klippa-app/go-pdfium/enums"
+	"github.com/klippa-app/go-pdfium/requests"
+	"github.com/klippa-app/go-pdfium/structs"
+)
+
+// Field is the value of a form field.
+type Field struct {
+	Name    string   // The fully qualified name of the field.
+	Values  []string // The values of the field, a listbox can have multiple values or none.
+	IsState bool     // Whether the value is the state of a checkbox or radio button, FDF writes states as names.
+}
+
+// Annotation is a markup annotation, only XFDF supports annotations.
+type Annotation struct {
+	Subtype       enums.FPDF_ANNOTATION_SUBTYPE
+	Page          int
+	Rect          structs.FPDF_FS_RECTF
+	Name          string // The unique name (NM) of the annotation.
+	Author        string // The author (T) of the annotation.
+	Date          string // The modification date (M) of the annotation, as PDF date string.
+	Contents      string
+	Color         *structs.FPDF_COLOR // The alpha channel is the opacity of the annotation.
+	InteriorColor *structs.FPDF_COLOR
+	Flags         enums.FPDF_ANNOT_FLAG
+	QuadPoints    []structs.FPDF_FS_QUADPOINTSF // The quad points of text markup annotations.
+	InkList       [][]structs.FPDF_FS_POINTF    // The paths of ink annotations.
+	Vertices      []structs.FPDF_FS_POINTF      // The vertices of polygon and polyline annotations.
+	Line          *[2]structs.FPDF_FS_POINTF    // The start and end of line annotations.
+	InReplyTo     string                        // The name of the annotation that this annotation replies to.
+	Popup         *AnnotationPopup
+	FileName      string // The file name of file attachment annotations.
+	FileContent   []byte // The file content of file attachment annotations.
+}
+
+// AnnotationPopup is the popup of a markup annotation.
+type AnnotationPopup struct {
+	Rect structs.FPDF_FS_RECTF
+	Open bool
+}
+
+// FormData is the form data of a document.
+type FormData struct {
+	FileName    string // The file name of the document that the form data belongs to.
+	Fields      []Field
+	Annotations []Annotation
+}
+
+// DetectFormat detects the format of the given form data.
+func DetectFormat(data []byte) (requests.FormDataFormat, error) {
+	data = bytes.TrimPrefix(data, []byte{0xEF, 0xBB, 0xBF})
+	data = bytes.TrimLeft(data, "\r\n\t ")
+	if bytes.HasPrefix(data, []byte("%FDF-")) {
+		return requests.FormDataFormatFDF, nil
+	}
+
+	if bytes.HasPrefix(data, []byte("<")) {
+		return requests.FormDataFormatXFDF, nil
+	}
+
+	return "", errors.New("could not detect the format of the form data")
+}
+
+// Read reads form data in the given format, the format is detected when no
+// format is given.
+func Read(format requests.FormDataFormat, data []byte) (*FormData, error) {
+	if format == "" {
+		detectedFormat, err := DetectFormat(data)
+		if err != nil {
+			return nil, err
+		}
+		format = detectedFormat
+	}
+
+	switch format {
+	case requests.FormDataFormatFDF:
+		return readFDF(data)
+	case requests.FormDataFormatXFDF:
+		return readXFDF(data)
+	}
+
+	return nil, fmt.Errorf("invalid form data format %s given", format)
+}
+
+// Write writes form data in the given format.
+func Write(format requests.FormDataFormat, formData FormData) ([]byte, error) {
+	switch format {
+	case requests.FormDataFormatFDF:
+		return writeFDF(formData)
+	case requests.FormDataFormatXFDF:
+		return writeXFDF(formData)
+	}
+
+	return nil, fmt.Errorf("invalid form data format %s given", format)
+}
+
+// fieldNode is a node in the hierarchy of fields, both formats write the
+// fields as a tree of partial names.
+type fieldNode struct {
+	partialName string
+	field       *Field
+	kids        []*fieldNode
+}
+
+// buildFieldTree builds the hierarchy of fields from their fully qualified
+// names, the order of the fields is kept.
+func buildFieldTree(fields []Field) []*fieldNode {
+	root := &fieldNode{}
+	for i := range fields {
+		node := root
+		for _, partialName := range strings.Split(fields[i].Name, ".") {
+			var kid *fieldNode
+			for _, existingKid := range node.kids {
+				if existingKid.partialName == partialName {
+					kid = existingKid
+					break
+				}
+			}
+
+			if kid == nil {
+				kid = &fieldNode{partialName: partialName}
+				node.kids = append(node.kids, kid)
+			}
+
+			node = kid
+		}
+
+		node.field = &fields[i]
+	}
+
+	return root.kids
+}
+
+// joinFieldName returns the fully qualified name of a field from the name
+// of its parent and its partial name.
+func joinFieldName(parentName, partialName string) string {
+	if parentName == "" {
+		return partialName
+	}
+
+	if partialName == "" {
+		return parentName
+	}
+
+	return parentName + "." + partialName
+}
@@ -0,0 +1,187 @@
+package form_data
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/klippa-app/go-pdfium/enums"
+	"github.com/klippa-app/go-pdfium/requests"
+	"github.com/klippa-app/go-pdfium/structs"
+)
+
+var testFormData = FormData{
+	FileName: "form.pdf",
+	Fields: []Field{
+		{Name: "name", Values: []string{"Jérôme (test)"}},
+		{Name: "address.street", Values: []string{"Main street"}},
+		{Name: "address.city", Values: []string{"Amsterdam"}},
+		{Name: "agree", Values: []string{"Yes"}, IsState: true},
+		{Name: "countries", Values: []string{"Belgium", "Denmark"}},
+		{Name: "languages", Values: []string{}},
+	},
+}
+
+func TestFDF(t *testing.T) {
+	data, err := Write(requests.FormDataFormatFDF, testFormData)
+	if err != nil {
+		t.Fatalf("Write resulted in error: %s", err.Error())
+	}
+
+	if !strings.Contains(string(data), "<< /Kids [<< /T (street) /V (Main street) >> << /T (city) /V (Amsterdam) >>] /T (address) >>") || !strings.Contains(string(data), "/V /Yes") {
+		t.Fatalf("Write didn't write the hierarchy of fields, got %s", data)
+	}
+
+	if !strings.Contains(string(data), "<< /T (languages) /V [] >>") {
+		t.Fatalf("Write didn't write the field without values, got %s", data)
+	}
+
+	formData, err := Read("", data)
+	if err != nil {
+		t.Fatalf("Read resulted in error: %s", err.Error())
+	}
+
+	if !reflect.DeepEqual(*formData, testFormData) {
+		t.Fatalf("Read resulted in wrong form data, got %+v, want %+v", *formData, testFormData)
+	}
+}
+
+func TestXFDF(t *testing.T) {
+	data, err := Write(requests.FormDataFormatXFDF, testFormData)
+	if err != nil {
+		t.Fatalf("Write resulted in error: %s", err.Error())
+	}
+
+	if !strings.Contains(string(data), `<xfdf xmlns="http://ns.adobe.com/xfdf/" xml:space="preserve">`) || !strings.Contains(string(data), `<field name="address">`) {
+		t.Fatalf("Write didn't write the hierarchy of fields, got %s", data)
+	}
+
+	formData, err := Read("", data)
+	if err != nil {
+		t.Fatalf("Read resulted in error: %s", err.Error())
+	}
+
+	// XFDF doesn't distinguish states from other values.
+	want := testFormData
+	want.Fields = append([]Field{}, testFormData.Fields...)
+	want.Fields[3].IsState = false
+	if !reflect.DeepEqual(*formData, want) {
+		t.Fatalf("Read resulted in wrong form data, got %+v, want %+v", *formData, want)
+	}
+}
+
+func TestReadFDFIndirectObjects(t *testing.T) {
+	data := []byte("%FDF-1.2\n" +
+		"1 0 obj\n<< /FDF << /Fields 2 0 R /F << /Type /Filespec /F (other.pdf) >> >> >>\nendobj\n" +
+		"2 0 obj\n[3 0 R << /T (list) /V [(A) <FEFF00C9>] >>]\nendobj\n" +
+		"3 0 obj\n<< /T <FEFF00E9> /V <FEFF00E9> >>\nendobj\n" +
+		"trailer\n<< /Root 1 0 R >>\n%%EOF\n")
+
+	formData, err := Read(requests.FormDataFormatFDF, data)
+	if err != nil {
+		t.Fatalf("Read resulted in error: %s", err.Error())
+	}
+
+	want := FormData{
+		FileName: "other.pdf",
+		Fields: []Field{
+			{Name: "é", Values: []string{"é"}},
+			{Name: "list", Values: []string{"A", "É"}},
+		},
+	}
+	if !reflect.DeepEqual(*formData, want) {
+		t.Fatalf("Read resulted in wrong form data, got %+v, want %+v", *formData, want)
+	}
+}
+
+func TestReadFDFSelfReferentialLength(t *testing.T) {
+	data := []byte("%FDF-1.2\n" +
+		"1 0 obj\n<< /FDF << /Fields [<< /T (stream) /V 2 0 R >> << /T (name) /V (value) >>] >> >>\nendobj\n" +
+		"2 0 obj\n<< /Length 2 0 R >>\nstream\nvalue\nendstream\nendobj\n" +
+		"trailer\n<< /Root 1 0 R >>\n%%EOF\n")
+
+	formData, err := Read(requests.FormDataFormatFDF, data)
+	if err != nil {
+		t.Fatalf("Read resulted in error: %s", err.Error())
+	}
+
+	// The stream can't be a value, it's only parsed to check that the
+	// length doesn't resolve to itself forever.
+	want := []Field{{Name: "name", Values: []string{"value"}}}
+	if !reflect.DeepEqual(formData.Fields, want) {
+		t.Fatalf("Read resulted in wrong form data, got %+v, want %+v", formData.Fields, want)
+	}
+}
+
+func TestDetectFormat(t *testing.T) {
+	if _, err := Read("", []byte("%PDF-1.7")); err == nil || err.Error() != "could not detect the format of the form data" {
+		t.Fatalf("Read didn't return an error for an unknown format, got %v", err)
+	}
+
+	if _, err := Write("json", testFormData); err == nil || err.Error() != "invalid form data format json given" {
+		t.Fatalf("Write didn't return an error for an invalid format, got %v", err)
+	}
+}
+
+func TestXFDFAnnotations(t *testing.T) {
+	annotations := FormData{
+		Annotations: []Annotation{
+			{
+				Subtype:    enums.FPDF_ANNOT_SUBTYPE_HIGHLIGHT,
+				Page:       1,
+				Rect:       structs.FPDF_FS_RECTF{Left: 10, Bottom: 20, Right: 110.5, Top: 40},
+				Name:       "highlight-1",
+				Author:     "Reviewer",
+				Date:       "D:20240101120000Z",
+				Contents:   "Check this",
+				Color:      &structs.FPDF_COLOR{R: 255, G: 255, A: 128},
+				Flags:      enums.FPDF_ANNOT_FLAG_PRINT | enums.FPDF_ANNOT_FLAG_NOZOOM,
+				QuadPoints: []structs.FPDF_FS_QUADPOINTSF{{X1: 10, Y1: 40, X2: 110.5, Y2: 40, X3: 10, Y3: 20, X4: 110.5, Y4: 20}},
+				Popup:      &AnnotationPopup{Rect: structs.FPDF_FS_RECTF{Left: 120, Bottom: 20, Right: 220, Top: 80}, Open: true},
+			},
+			{
+				Subtype:   enums.FPDF_ANNOT_SUBTYPE_INK,
+				Rect:      structs.FPDF_FS_RECTF{Left: 0, Bottom: 0, Right: 50, Top: 50},
+				Name:      "ink-1",
+				InReplyTo: "highlight-1",
+				InkList:   [][]structs.FPDF_FS_POINTF{{{X: 1, Y: 2}, {X: 3, Y: 4}}, {{X: 5, Y: 6}}},
+			},
+			{
+				Subtype:     enums.FPDF_ANNOT_SUBTYPE_FILEATTACHMENT,
+				Rect:        structs.FPDF_FS_RECTF{Left: 0, Bottom: 0, Right: 20, Top: 20},
+				Name:        "file-1",
+				FileName:    "notes.txt",
+				FileContent: []byte("Notes"),
+			},
+		},
+	}
+
+	data, err := Write(requests.FormDataFormatXFDF, annotations)
+	if err != nil {
+		t.Fatalf("Write resulted in error: %s", err.Error())
+	}
+
+	for _, expected := range []string{
+		`<highlight page="1" rect="10,20,110.5,40" name="highlight-1" title="Reviewer" date="D:20240101120000Z" color="#FFFF00" opacity="0.5019608" flags="print,nozoom" coords="10,40,110.5,40,10,20,110.5,20">`,
+		`<popup rect="120,20,220,80" open="yes"></popup>`,
+		`<gesture>1,2;3,4</gesture>`,
+		`<data MODE="RAW" encoding="HEX" length="5">4E6F746573</data>`,
+	} {
+		if !strings.Contains(string(data), expected) {
+			t.Fatalf("Write didn't write %s, got %s", expected, data)
+		}
+	}
+
+	if strings.Contains(string(data), "<fields>") {
+		t.Fatalf("Write wrote fields without fields, got %s", data)
+	}
+
+	formData, err := Read(requests.FormDataFormatXFDF, data)
+	if err != nil {
+		t.Fatalf("Read resulted in error: %s", err.Error())
+	}
+
+	if !reflect.DeepEqual(formData.Annotations, annotations.Annotations) {
+		t.Fatalf("Read resulted in wrong annotations, got %+v, want %+v", formData.Annotations, annotations.Annotations)
+	}
+}
@@ -0,0 +1,144 @@
+package form_data
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+)
+
+const xfdfNamespace = "http://ns.adobe.com/xfdf/"
+
+type xfdfDocument struct {
+	XMLName xml.Name    `xml:"xfdf"`
+	Xmlns   string      `xml:"xmlns,attr,omitempty"`
+	Space   string      `xml:"http://www.w3.org/XML/1998/namespace space,attr,omitempty"`
+	File    *xfdfFile   `xml:"f,omitempty"`
+	Fields  *xfdfFields `xml:"fields,omitempty"`
+	Annots  *xfdfAnnots `xml:"annots,omitempty"`
+}
+
+type xfdfFile struct {
+	Href string `xml:"href,attr"`
+}
+
+type xfdfFields struct {
+	Fields []xfdfField `xml:"field"`
+}
+
+type xfdfField struct {
+	Name   string      `xml:"name,attr"`
+	Values []string    `xml:"value"`
+	Fields []xfdfField `xml:"field"`
+}
+
+func writeXFDF(formData FormData) ([]byte, error) {
+	document := xfdfDocument{
+		Xmlns: xfdfNamespace,
+		Space: "preserve",
+	}
+
+	// Form data without annotations always has fields, annotations are
+	// written without fields when there are none.
+	if len(formData.Fields) > 0 || len(formData.Annotations) == 0 {
+		document.Fields = &xfdfFields{
+			Fields: xfdfFieldsFromTree(buildFieldTree(formData.Fields)),
+		}
+	}
+
+	if len(formData.Annotations) > 0 {
+		document.Annots = xfdfAnnotsFromAnnotations(formData.Annotations)
+	}
+
+	if formData.FileName != "" {
+		document.File = &xfdfFile{
+			Href: formData.FileName,
+		}
+	}
+
+	return marshalXFDF(document)
+}
+
+func marshalXFDF(document xfdfDocument) ([]byte, error) {
+	buf := &bytes.Buffer{}
+	buf.WriteString(xml.Header)
+
+	encoder := xml.NewEncoder(buf)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(document); err != nil {
+		return nil, err
+	}
+	buf.WriteString("\n")
+
+	return buf.Bytes(), nil
+}
+
+func xfdfFieldsFromTree(nodes []*fieldNode) []xfdfField {
+	fields := []xfdfField{}
+	for _, node := range nodes {
+		field := xfdfField{
+			Name:   node.partialName,
+			Fields: xfdfFieldsFromTree(node.kids),
+		}
+
+		if node.field != nil {
+			field.Values = node.field.Values
+		}
+
+		fields = append(fields, field)
+	}
+
+	return fields
+}
+
+func unmarshalXFDF(data []byte) (*xfdfDocument, error) {
+	document := &xfdfDocument{}
+	if err := xml.Unmarshal(data, document); err != nil {
+		return nil, errors.New("could not parse XFDF: " + err.Error())
+	}
+
+	return document, nil
+}
+
+func readXFDF(data []byte) (*FormData, error) {
+	document, err := unmarshalXFDF(data)
+	if err != nil {
+		return nil, err
+	}
+
+	formData := &FormData{
+		Fields: []Field{},
+	}
+
+	if document.File != nil {
+		formData.FileName = document.File.Href
+	}
+
+	if document.Fields != nil {
+		readXFDFFields(document.Fields.Fields, "", formData)
+	}
+
+	if document.Annots != nil {
+		formData.Annotations, err = annotationsFromXFDFAnnots(document.Annots)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return formData, nil
+}
+
+func readXFDFFields(fields []xfdfField, parentName string, formData *FormData) {
+	for i := range fields {
+		// A field without values and kids is a listbox without selected
+		// options.
+		name := joinFieldName(parentName, fields[i].Name)
+		if len(fields[i].Values) > 0 || len(fields[i].Fields) == 0 {
+			formData.Fields = append(formData.Fields, Field{
+				Name:   name,
+				Values: append([]string{}, fields[i].Values...),
+			})
+		}
+
+		readXFDFFields(fields[i].Fields, name, formData)
+	}
+}
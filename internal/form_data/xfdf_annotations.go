@@ -0,0 +1,444 @@
+package form_data
+
+import (
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/klippa-app/go-pdfium/enums"
+	"github.com/klippa-app/go-pdfium/structs"
+)
+
+// xfdfAnnotationElements are the XFDF element names of the markup
+// annotation subtypes.
+var xfdfAnnotationElements = map[enums.FPDF_ANNOTATION_SUBTYPE]string{
+	enums.FPDF_ANNOT_SUBTYPE_TEXT:           "text",
+	enums.FPDF_ANNOT_SUBTYPE_FREETEXT:       "freetext",
+	enums.FPDF_ANNOT_SUBTYPE_LINE:           "line",
+	enums.FPDF_ANNOT_SUBTYPE_SQUARE:         "square",
+	enums.FPDF_ANNOT_SUBTYPE_CIRCLE:         "circle",
+	enums.FPDF_ANNOT_SUBTYPE_POLYGON:        "polygon",
+	enums.FPDF_ANNOT_SUBTYPE_POLYLINE:       "polyline",
+	enums.FPDF_ANNOT_SUBTYPE_HIGHLIGHT:      "highlight",
+	enums.FPDF_ANNOT_SUBTYPE_UNDERLINE:      "underline",
+	enums.FPDF_ANNOT_SUBTYPE_SQUIGGLY:       "squiggly",
+	enums.FPDF_ANNOT_SUBTYPE_STRIKEOUT:      "strikeout",
+	enums.FPDF_ANNOT_SUBTYPE_STAMP:          "stamp",
+	enums.FPDF_ANNOT_SUBTYPE_CARET:          "caret",
+	enums.FPDF_ANNOT_SUBTYPE_INK:            "ink",
+	enums.FPDF_ANNOT_SUBTYPE_FILEATTACHMENT: "fileattachment",
+	enums.FPDF_ANNOT_SUBTYPE_SOUND:          "sound",
+	enums.FPDF_ANNOT_SUBTYPE_REDACT:         "redact",
+}
+
+// xfdfAnnotationFlags are the XFDF names of the annotation flags.
+var xfdfAnnotationFlags = []struct {
+	flag enums.FPDF_ANNOT_FLAG
+	name string
+}{
+	{enums.FPDF_ANNOT_FLAG_INVISIBLE, "invisible"},
+	{enums.FPDF_ANNOT_FLAG_HIDDEN, "hidden"},
+	{enums.FPDF_ANNOT_FLAG_PRINT, "print"},
+	{enums.FPDF_ANNOT_FLAG_NOZOOM, "nozoom"},
+	{enums.FPDF_ANNOT_FLAG_NOROTATE, "norotate"},
+	{enums.FPDF_ANNOT_FLAG_NOVIEW, "noview"},
+	{enums.FPDF_ANNOT_FLAG_READONLY, "readonly"},
+	{enums.FPDF_ANNOT_FLAG_LOCKED, "locked"},
+	{enums.FPDF_ANNOT_FLAG_TOGGLENOVIEW, "togglenoview"},
+}
+
+// IsMarkupAnnotation returns whether annotations of the given subtype are
+// markup annotations that can be written to XFDF.
+func IsMarkupAnnotation(subtype enums.FPDF_ANNOTATION_SUBTYPE) bool {
+	_, ok := xfdfAnnotationElements[subtype]
+	return ok
+}
+
+type xfdfAnnots struct {
+	Annotations []xfdfAnnotation `xml:",any"`
+}
+
+type xfdfAnnotation struct {
+	XMLName       xml.Name
+	Page          int           `xml:"page,attr"`
+	Rect          string        `xml:"rect,attr"`
+	Name          string        `xml:"name,attr,omitempty"`
+	Title         string        `xml:"title,attr,omitempty"`
+	Date          string        `xml:"date,attr,omitempty"`
+	Color         string        `xml:"color,attr,omitempty"`
+	InteriorColor string        `xml:"interior-color,attr,omitempty"`
+	Opacity       string        `xml:"opacity,attr,omitempty"`
+	Flags         string        `xml:"flags,attr,omitempty"`
+	InReplyTo     string        `xml:"inreplyto,attr,omitempty"`
+	Coords        string        `xml:"coords,attr,omitempty"`
+	Start         string        `xml:"start,attr,omitempty"`
+	End           string        `xml:"end,attr,omitempty"`
+	File          string        `xml:"file,attr,omitempty"`
+	Contents      *string       `xml:"contents"`
+	Popup         *xfdfPopup    `xml:"popup"`
+	InkList       *xfdfInkList  `xml:"inklist"`
+	Vertices      string        `xml:"vertices,omitempty"`
+	Data          *xfdfFileData `xml:"data"`
+}
+
+type xfdfPopup struct {
+	Rect string `xml:"rect,attr"`
+	Open string `xml:"open,attr,omitempty"`
+}
+
+type xfdfInkList struct {
+	Gestures []string `xml:"gesture"`
+}
+
+type xfdfFileData struct {
+	Mode     string `xml:"MODE,attr"`
+	Encoding string `xml:"encoding,attr"`
+	Length   int    `xml:"length,attr"`
+	Data     string `xml:",chardata"`
+}
+
+func xfdfAnnotsFromAnnotations(annotations []Annotation) *xfdfAnnots {
+	annots := &xfdfAnnots{}
+	for _, annotation := range annotations {
+		element, ok := xfdfAnnotationElements[annotation.Subtype]
+		if !ok {
+			continue
+		}
+
+		annot := xfdfAnnotation{
+			XMLName:   xml.Name{Local: element},
+			Page:      annotation.Page,
+			Rect:      formatRect(annotation.Rect),
+			Name:      annotation.Name,
+			Title:     annotation.Author,
+			Date:      annotation.Date,
+			Flags:     formatAnnotationFlags(annotation.Flags),
+			InReplyTo: annotation.InReplyTo,
+		}
+
+		if annotation.Contents != "" {
+			contents := annotation.Contents
+			annot.Contents = &contents
+		}
+
+		if annotation.Color != nil {
+			annot.Color = formatColor(*annotation.Color)
+			if annotation.Color.A < 255 {
+				annot.Opacity = formatNumber(float32(annotation.Color.A) / 255)
+			}
+		}
+
+		if annotation.InteriorColor != nil {
+			annot.InteriorColor = formatColor(*annotation.InteriorColor)
+		}
+
+		if len(annotation.QuadPoints) > 0 {
+			coords := []float32{}
+			for _, quadPoints := range annotation.QuadPoints {
+				coords = append(coords, quadPoints.X1, quadPoints.Y1, quadPoints.X2, quadPoints.Y2, quadPoints.X3, quadPoints.Y3, quadPoints.X4, quadPoints.Y4)
+			}
+			annot.Coords = formatNumbers(coords, ",")
+		}
+
+		if len(annotation.InkList) > 0 {
+			annot.InkList = &xfdfInkList{}
+			for _, path := range annotation.InkList {
+				annot.InkList.Gestures = append(annot.InkList.Gestures, formatPoints(path))
+			}
+		}
+
+		if len(annotation.Vertices) > 0 {
+			annot.Vertices = formatPoints(annotation.Vertices)
+		}
+
+		if annotation.Line != nil {
+			annot.Start = formatPoints(annotation.Line[:1])
+			annot.End = formatPoints(annotation.Line[1:])
+		}
+
+		if annotation.Popup != nil {
+			annot.Popup = &xfdfPopup{
+				Rect: formatRect(annotation.Popup.Rect),
+			}
+			if annotation.Popup.Open {
+				annot.Popup.Open = "yes"
+			}
+		}
+
+		if annotation.FileName != "" {
+			annot.File = annotation.FileName
+			annot.Data = &xfdfFileData{
+				Mode:     "RAW",
+				Encoding: "HEX",
+				Length:   len(annotation.FileContent),
+				Data:     strings.ToUpper(hex.EncodeToString(annotation.FileContent)),
+			}
+		}
+
+		annots.Annotations = append(annots.Annotations, annot)
+	}
+
+	return annots
+}
+
+func annotationsFromXFDFAnnots(annots *xfdfAnnots) ([]Annotation, error) {
+	elementSubtypes := map[string]enums.FPDF_ANNOTATION_SUBTYPE{}
+	for subtype, element := range xfdfAnnotationElements {
+		elementSubtypes[element] = subtype
+	}
+
+	annotations := []Annotation{}
+	for _, annot := range annots.Annotations {
+		subtype, ok := elementSubtypes[strings.ToLower(annot.XMLName.Local)]
+		if !ok {
+			continue
+		}
+
+		rect, err := parseRect(annot.Rect)
+		if err != nil {
+			return nil, err
+		}
+
+		annotation := Annotation{
+			Subtype:   subtype,
+			Page:      annot.Page,
+			Rect:      rect,
+			Name:      annot.Name,
+			Author:    annot.Title,
+			Date:      annot.Date,
+			Flags:     parseAnnotationFlags(annot.Flags),
+			InReplyTo: annot.InReplyTo,
+		}
+
+		if annot.Contents != nil {
+			annotation.Contents = *annot.Contents
+		}
+
+		if annot.Color != "" {
+			color, err := parseColor(annot.Color)
+			if err != nil {
+				return nil, err
+			}
+
+			if annot.Opacity != "" {
+				opacity, err := strconv.ParseFloat(annot.Opacity, 32)
+				if err != nil {
+					return nil, fmt.Errorf("invalid opacity %q", annot.Opacity)
+				}
+				color.A = uint(math.Round(math.Max(0, math.Min(1, opacity)) * 255))
+			}
+
+			annotation.Color = &color
+		}
+
+		if annot.InteriorColor != "" {
+			color, err := parseColor(annot.InteriorColor)
+			if err != nil {
+				return nil, err
+			}
+			annotation.InteriorColor = &color
+		}
+
+		if annot.Coords != "" {
+			coords, err := parseNumbers(annot.Coords)
+			if err != nil {
+				return nil, err
+			}
+
+			for i := 0; i+8 <= len(coords); i += 8 {
+				annotation.QuadPoints = append(annotation.QuadPoints, structs.FPDF_FS_QUADPOINTSF{
+					X1: coords[i], Y1: coords[i+1],
+					X2: coords[i+2], Y2: coords[i+3],
+					X3: coords[i+4], Y3: coords[i+5],
+					X4: coords[i+6], Y4: coords[i+7],
+				})
+			}
+		}
+
+		if annot.InkList != nil {
+			for _, gesture := range annot.InkList.Gestures {
+				path, err := parsePoints(gesture)
+				if err != nil {
+					return nil, err
+				}
+				annotation.InkList = append(annotation.InkList, path)
+			}
+		}
+
+		if annot.Vertices != "" {
+			vertices, err := parsePoints(annot.Vertices)
+			if err != nil {
+				return nil, err
+			}
+			annotation.Vertices = vertices
+		}
+
+		if annot.Start != "" && annot.End != "" {
+			start, err := parsePoints(annot.Start)
+			if err != nil {
+				return nil, err
+			}
+
+			end, err := parsePoints(annot.End)
+			if err != nil {
+				return nil, err
+			}
+
+			if len(start) == 1 && len(end) == 1 {
+				annotation.Line = &[2]structs.FPDF_FS_POINTF{start[0], end[0]}
+			}
+		}
+
+		if annot.Popup != nil {
+			popupRect, err := parseRect(annot.Popup.Rect)
+			if err != nil {
+				return nil, err
+			}
+
+			annotation.Popup = &AnnotationPopup{
+				Rect: popupRect,
+				Open: annot.Popup.Open == "yes" || annot.Popup.Open == "true",
+			}
+		}
+
+		if annot.File != "" {
+			annotation.FileName = annot.File
+			if annot.Data != nil {
+				if !strings.EqualFold(annot.Data.Encoding, "HEX") {
+					return nil, fmt.Errorf("unsupported file data encoding %q", annot.Data.Encoding)
+				}
+
+				content, err := hex.DecodeString(strings.Join(strings.Fields(annot.Data.Data), ""))
+				if err != nil {
+					return nil, fmt.Errorf("invalid file data of annotation %q", annot.Name)
+				}
+				annotation.FileContent = content
+			}
+		}
+
+		annotations = append(annotations, annotation)
+	}
+
+	return annotations, nil
+}
+
+func formatNumber(value float32) string {
+	return strconv.FormatFloat(float64(value), 'f', -1, 32)
+}
+
+func formatNumbers(values []float32, separator string) string {
+	formatted := make([]string, len(values))
+	for i := range values {
+		formatted[i] = formatNumber(values[i])
+	}
+	return strings.Join(formatted, separator)
+}
+
+// formatRect formats a rect as "left,bottom,right,top".
+func formatRect(rect structs.FPDF_FS_RECTF) string {
+	return formatNumbers([]float32{rect.Left, rect.Bottom, rect.Right, rect.Top}, ",")
+}
+
+// formatPoints formats points as "x,y;x,y".
+func formatPoints(points []structs.FPDF_FS_POINTF) string {
+	formatted := make([]string, len(points))
+	for i := range points {
+		formatted[i] = formatNumbers([]float32{points[i].X, points[i].Y}, ",")
+	}
+	return strings.Join(formatted, ";")
+}
+
+func formatColor(color structs.FPDF_COLOR) string {
+	return fmt.Sprintf("#%02X%02X%02X", color.R, color.G, color.B)
+}
+
+func formatAnnotationFlags(flags enums.FPDF_ANNOT_FLAG) string {
+	names := []string{}
+	for _, annotationFlag := range xfdfAnnotationFlags {
+		if flags&annotationFlag.flag != 0 {
+			names = append(names, annotationFlag.name)
+		}
+	}
+	return strings.Join(names, ",")
+}
+
+func parseNumbers(value string) ([]float32, error) {
+	numbers := []float32{}
+	for _, field := range strings.FieldsFunc(value, func(r rune) bool {
+		return r == ',' || r == ';' || r == ' ' || r == '\n' || r == '\r' || r == '\t'
+	}) {
+		number, err := strconv.ParseFloat(field, 32)
+		if err != nil {
+			return nil, fmt.Errorf("invalid number %q", field)
+		}
+		numbers = append(numbers, float32(number))
+	}
+	return numbers, nil
+}
+
+func parseRect(value string) (structs.FPDF_FS_RECTF, error) {
+	numbers, err := parseNumbers(value)
+	if err != nil {
+		return structs.FPDF_FS_RECTF{}, err
+	}
+
+	if len(numbers) != 4 {
+		return structs.FPDF_FS_RECTF{}, fmt.Errorf("invalid rect %q", value)
+	}
+
+	return structs.FPDF_FS_RECTF{
+		Left:   numbers[0],
+		Bottom: numbers[1],
+		Right:  numbers[2],
+		Top:    numbers[3],
+	}, nil
+}
+
+func parsePoints(value string) ([]structs.FPDF_FS_POINTF, error) {
+	numbers, err := parseNumbers(value)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(numbers)%2 != 0 {
+		return nil, fmt.Errorf("invalid points %q", value)
+	}
+
+	points := make([]structs.FPDF_FS_POINTF, len(numbers)/2)
+	for i := range points {
+		points[i] = structs.FPDF_FS_POINTF{
+			X: numbers[i*2],
+			Y: numbers[i*2+1],
+		}
+	}
+	return points, nil
+}
+
+func parseColor(value string) (structs.FPDF_COLOR, error) {
+	colorValue, err := strconv.ParseUint(strings.TrimPrefix(value, "#"), 16, 32)
+	if err != nil || len(strings.TrimPrefix(value, "#")) != 6 {
+		return structs.FPDF_COLOR{}, fmt.Errorf("invalid color %q", value)
+	}
+
+	return structs.FPDF_COLOR{
+		R: uint(colorValue >> 16 & 0xFF),
+		G: uint(colorValue >> 8 & 0xFF),
+		B: uint(colorValue & 0xFF),
+		A: 255,
+	}, nil
+}
+
+func parseAnnotationFlags(value string) enums.FPDF_ANNOT_FLAG {
+	flags := enums.FPDF_ANNOT_FLAG_NONE
+	for _, name := range strings.Split(value, ",") {
+		for _, annotationFlag := range xfdfAnnotationFlags {
+			if strings.EqualFold(strings.TrimSpace(name), annotationFlag.name) {
+				flags |= annotationFlag.flag
+			}
+		}
+	}
+	return flags
+}
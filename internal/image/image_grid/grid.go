@@ -0,0 +1,91 @@
+package image_grid
+
+import (
+	"fmt"
+	"image"
+	"math"
+)
+
+// Layout calculates the position of every cell in a grid with the given
+// amount of columns. Every column is as wide as the widest cell in it, and
+// every row is as high as the highest cell in it plus the label height. The
+// cells are separated by the padding. It returns the top left position of
+// every cell and the total size of the grid.
+func Layout(sizes []image.Point, columns, padding, labelHeight int) ([]image.Point, image.Point) {
+	if columns < 1 {
+		columns = 1
+	}
+
+	columnWidths := make([]int, columns)
+	rowHeights := make([]int, (len(sizes)+columns-1)/columns)
+	for i := range sizes {
+		column := i % columns
+		row := i / columns
+
+		if sizes[i].X > columnWidths[column] {
+			columnWidths[column] = sizes[i].X
+		}
+
+		if sizes[i].Y+labelHeight > rowHeights[row] {
+			rowHeights[row] = sizes[i].Y + labelHeight
+		}
+	}
+
+	columnOffsets := make([]int, len(columnWidths))
+	totalWidth := 0
+	for i := range columnWidths {
+		if i > 0 {
+			totalWidth += padding
+		}
+		columnOffsets[i] = totalWidth
+		totalWidth += columnWidths[i]
+	}
+
+	rowOffsets := make([]int, len(rowHeights))
+	totalHeight := 0
+	for i := range rowHeights {
+		if i > 0 {
+			totalHeight += padding
+		}
+		rowOffsets[i] = totalHeight
+		totalHeight += rowHeights[i]
+	}
+
+	positions := make([]image.Point, len(sizes))
+	for i := range sizes {
+		positions[i] = image.Point{
+			X: columnOffsets[i%columns],
+			Y: rowOffsets[i/columns],
+		}
+	}
+
+	return positions, image.Point{X: totalWidth, Y: totalHeight}
+}
+
+// FitScale returns the scale (at most 1) for the cells so that the grid fits
+// in the maximum width and height, a maximum of 0 means no maximum. The
+// padding and the labels are not scaled, so it returns an error when they
+// don't leave any space for the cells.
+func FitScale(sizes []image.Point, columns, padding, labelHeight, maxWidth, maxHeight int) (float64, error) {
+	_, size := Layout(sizes, columns, padding, labelHeight)
+
+	// The size of the grid without the cells.
+	_, fixedSize := Layout(make([]image.Point, len(sizes)), columns, padding, labelHeight)
+
+	scale := float64(1)
+	if maxWidth > 0 && size.X > maxWidth {
+		if maxWidth <= fixedSize.X {
+			return 0, fmt.Errorf("maximum width %d is too small for the padding, it needs to be larger than %d", maxWidth, fixedSize.X)
+		}
+		scale = math.Min(scale, float64(maxWidth-fixedSize.X)/float64(size.X-fixedSize.X))
+	}
+
+	if maxHeight > 0 && size.Y > maxHeight {
+		if maxHeight <= fixedSize.Y {
+			return 0, fmt.Errorf("maximum height %d is too small for the padding and labels, it needs to be larger than %d", maxHeight, fixedSize.Y)
+		}
+		scale = math.Min(scale, float64(maxHeight-fixedSize.Y)/float64(size.Y-fixedSize.Y))
+	}
+
+	return scale, nil
+}
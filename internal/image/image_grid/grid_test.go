@@ -0,0 +1,92 @@
+package image_grid
+
+import (
+	"image"
+	"image/color"
+	"reflect"
+	"testing"
+)
+
+func TestLayout(t *testing.T) {
+	sizes := []image.Point{{100, 200}, {150, 100}, {50, 50}}
+	positions, size := Layout(sizes, 2, 10, 20)
+
+	wantPositions := []image.Point{{0, 0}, {110, 0}, {0, 230}}
+	if !reflect.DeepEqual(positions, wantPositions) {
+		t.Fatalf("Layout resulted in wrong positions, got %v, want %v", positions, wantPositions)
+	}
+	if size != (image.Point{260, 300}) {
+		t.Fatalf("Layout resulted in wrong size, got %v, want %v", size, image.Point{260, 300})
+	}
+}
+
+func TestLayoutSingleColumn(t *testing.T) {
+	sizes := []image.Point{{100, 200}, {150, 100}}
+	positions, size := Layout(sizes, 0, 10, 0)
+
+	wantPositions := []image.Point{{0, 0}, {0, 210}}
+	if !reflect.DeepEqual(positions, wantPositions) {
+		t.Fatalf("Layout resulted in wrong positions, got %v, want %v", positions, wantPositions)
+	}
+	if size != (image.Point{150, 310}) {
+		t.Fatalf("Layout resulted in wrong size, got %v, want %v", size, image.Point{150, 310})
+	}
+}
+
+func TestFitScale(t *testing.T) {
+	sizes := []image.Point{{100, 200}, {100, 200}}
+
+	scale, err := FitScale(sizes, 2, 10, 20, 110, 0)
+	if err != nil {
+		t.Fatalf("FitScale resulted in an error: %v", err)
+	}
+	if scale != 0.5 {
+		t.Fatalf("FitScale resulted in wrong scale, got %f, want %f", scale, 0.5)
+	}
+
+	scale, err = FitScale(sizes, 2, 10, 20, 0, 70)
+	if err != nil {
+		t.Fatalf("FitScale resulted in an error: %v", err)
+	}
+	if scale != 0.25 {
+		t.Fatalf("FitScale resulted in wrong scale, got %f, want %f", scale, 0.25)
+	}
+
+	scale, err = FitScale(sizes, 2, 10, 20, 1000, 1000)
+	if err != nil {
+		t.Fatalf("FitScale resulted in an error: %v", err)
+	}
+	if scale != 1 {
+		t.Fatalf("FitScale resulted in wrong scale, got %f, want %f", scale, float64(1))
+	}
+}
+
+func TestFitScaleTooSmall(t *testing.T) {
+	sizes := []image.Point{{100, 200}, {100, 200}}
+
+	// The padding between the columns takes all the width.
+	_, err := FitScale(sizes, 2, 10, 20, 10, 0)
+	if err == nil {
+		t.Fatalf("FitScale didn't result in an error for a width that only fits the padding")
+	}
+
+	// The label takes all the height.
+	_, err = FitScale(sizes, 2, 10, 20, 0, 15)
+	if err == nil {
+		t.Fatalf("FitScale didn't result in an error for a height smaller than the label")
+	}
+}
+
+func TestDrawLabel(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 20, 10))
+	black := color.RGBA{A: 0xFF}
+	DrawLabel(img, img.Rect, "1", black)
+
+	// The glyph of the 1 is centered, its stem is in the third column.
+	if img.RGBAAt(9, 1) != black || img.RGBAAt(9, 7) != black {
+		t.Fatalf("DrawLabel didn't draw the label in the center")
+	}
+	if img.RGBAAt(0, 0) != (color.RGBA{}) || img.RGBAAt(19, 9) != (color.RGBA{}) {
+		t.Fatalf("DrawLabel drew outside of the label")
+	}
+}
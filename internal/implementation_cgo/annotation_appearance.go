@@ -0,0 +1,533 @@
+package implementation_cgo
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/klippa-app/go-pdfium/enums"
+	"github.com/klippa-app/go-pdfium/internal/appearance"
+	"github.com/klippa-app/go-pdfium/references"
+	"github.com/klippa-app/go-pdfium/requests"
+	"github.com/klippa-app/go-pdfium/responses"
+	"github.com/klippa-app/go-pdfium/structs"
+)
+
+// GenerateAnnotationAppearance generates the normal appearance of an
+// annotation from its properties, and replaces the appearance that it has.
+// When the appearance can't be generated, the annotation keeps its current
+// appearance.
+// Experimental API.
+func (p *PdfiumImplementation) GenerateAnnotationAppearance(request *requests.GenerateAnnotationAppearance) (*responses.GenerateAnnotationAppearance, error) {
+	// Don't lock here, the methods that we call do that for us, we only
+	// lock to check the document, which is needed for the fonts.
+	p.Lock()
+	_, err := p.getDocumentHandle(request.Document)
+	p.Unlock()
+	if err != nil {
+		return nil, err
+	}
+
+	subtype, err := p.FPDFAnnot_GetSubtype(&requests.FPDFAnnot_GetSubtype{
+		Annotation: request.Annotation,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	switch subtype.Subtype {
+	case enums.FPDF_ANNOT_SUBTYPE_SQUARE, enums.FPDF_ANNOT_SUBTYPE_CIRCLE, enums.FPDF_ANNOT_SUBTYPE_LINE, enums.FPDF_ANNOT_SUBTYPE_POLYGON, enums.FPDF_ANNOT_SUBTYPE_POLYLINE, enums.FPDF_ANNOT_SUBTYPE_INK, enums.FPDF_ANNOT_SUBTYPE_FREETEXT, enums.FPDF_ANNOT_SUBTYPE_STAMP:
+	default:
+		if !appearance.IsTextMarkup(subtype.Subtype) {
+			return nil, fmt.Errorf("generating the appearance of subtype %d is not supported", subtype.Subtype)
+		}
+	}
+
+	if subtype.Subtype == enums.FPDF_ANNOT_SUBTYPE_STAMP {
+		// PDFium keeps the objects of a stamp when its appearance is
+		// cleared, so we can't replace it.
+		objectCount, err := p.FPDFAnnot_GetObjectCount(&requests.FPDFAnnot_GetObjectCount{
+			Annotation: request.Annotation,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		if objectCount.Count > 0 {
+			return nil, errors.New("the stamp already has an appearance")
+		}
+	}
+
+	// Keep the current appearance, so that we can put it back when the new
+	// one can't be generated. PDFium returns an empty string when there is
+	// no appearance, which we shouldn't turn into an empty stream.
+	var currentAppearance *string
+	currentAP, err := p.FPDFAnnot_GetAP(&requests.FPDFAnnot_GetAP{
+		Annotation:     request.Annotation,
+		AppearanceMode: enums.FPDF_ANNOT_APPEARANCEMODE_NORMAL,
+	})
+	if err == nil && currentAP.Value != "" {
+		currentAppearance = &currentAP.Value
+	}
+
+	// PDFium doesn't return the colors of annotations with an appearance
+	// stream, so clear it first.
+	_, err = p.FPDFAnnot_SetAP(&requests.FPDFAnnot_SetAP{
+		Annotation:     request.Annotation,
+		AppearanceMode: enums.FPDF_ANNOT_APPEARANCEMODE_NORMAL,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	generated := false
+	defer func() {
+		if !generated {
+			p.FPDFAnnot_SetAP(&requests.FPDFAnnot_SetAP{
+				Annotation:     request.Annotation,
+				AppearanceMode: enums.FPDF_ANNOT_APPEARANCEMODE_NORMAL,
+				Value:          currentAppearance,
+			})
+		}
+	}()
+
+	rect, err := p.FPDFAnnot_GetRect(&requests.FPDFAnnot_GetRect{
+		Annotation: request.Annotation,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	style, err := p.getAnnotationStyle(request.Annotation)
+	if err != nil {
+		return nil, err
+	}
+
+	// Lines and ink without a color would be invisible, draw them in black
+	// like viewers do.
+	if style.Color == nil {
+		switch subtype.Subtype {
+		case enums.FPDF_ANNOT_SUBTYPE_LINE, enums.FPDF_ANNOT_SUBTYPE_POLYGON, enums.FPDF_ANNOT_SUBTYPE_POLYLINE, enums.FPDF_ANNOT_SUBTYPE_INK:
+			style.Color = &structs.FPDF_COLOR{R: 0, G: 0, B: 0, A: 255}
+		}
+	}
+
+	var appearanceStream string
+	switch subtype.Subtype {
+	case enums.FPDF_ANNOT_SUBTYPE_SQUARE:
+		appearanceStream = appearance.Square(rect.Rect, style)
+	case enums.FPDF_ANNOT_SUBTYPE_CIRCLE:
+		appearanceStream = appearance.Circle(rect.Rect, style)
+	case enums.FPDF_ANNOT_SUBTYPE_LINE:
+		line, err := p.FPDFAnnot_GetLine(&requests.FPDFAnnot_GetLine{
+			Annotation: request.Annotation,
+		})
+		if err != nil {
+			return nil, err
+		}
+		appearanceStream = appearance.Line(line.Start, line.End, style)
+	case enums.FPDF_ANNOT_SUBTYPE_POLYGON, enums.FPDF_ANNOT_SUBTYPE_POLYLINE:
+		vertices, err := p.FPDFAnnot_GetVertices(&requests.FPDFAnnot_GetVertices{
+			Annotation: request.Annotation,
+		})
+		if err != nil {
+			return nil, err
+		}
+		appearanceStream, err = appearance.Polygon(vertices.Vertices, subtype.Subtype == enums.FPDF_ANNOT_SUBTYPE_POLYGON, style)
+		if err != nil {
+			return nil, err
+		}
+	case enums.FPDF_ANNOT_SUBTYPE_INK:
+		paths, err := p.getAnnotationInkPaths(request.Annotation)
+		if err != nil {
+			return nil, err
+		}
+		appearanceStream, err = appearance.Ink(paths, style)
+		if err != nil {
+			return nil, err
+		}
+	case enums.FPDF_ANNOT_SUBTYPE_FREETEXT:
+		appearanceStream, err = p.getFreeTextAppearance(request, rect.Rect, style)
+		if err != nil {
+			return nil, err
+		}
+	case enums.FPDF_ANNOT_SUBTYPE_STAMP:
+		err = p.generateStampAppearance(request, rect.Rect, style)
+		if err != nil {
+			return nil, err
+		}
+	default:
+		quadPoints, err := p.getAnnotationQuadPoints(request.Annotation)
+		if err != nil {
+			return nil, err
+		}
+
+		color := defaultTextMarkupColor(subtype.Subtype)
+		if style.Color != nil {
+			color = *style.Color
+		}
+
+		appearanceStream, err = appearance.TextMarkup(subtype.Subtype, quadPoints, color)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Stamps get their appearance from the objects that are appended.
+	if subtype.Subtype != enums.FPDF_ANNOT_SUBTYPE_STAMP {
+		_, err = p.FPDFAnnot_SetAP(&requests.FPDFAnnot_SetAP{
+			Annotation:     request.Annotation,
+			AppearanceMode: enums.FPDF_ANNOT_APPEARANCEMODE_NORMAL,
+			Value:          &appearanceStream,
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	generated = true
+	return &responses.GenerateAnnotationAppearance{
+		Subtype: subtype.Subtype,
+	}, nil
+}
+
+// getAnnotationStyle returns the colors and border width of an annotation.
+// The colors are nil when the annotation doesn't have them, the border width
+// is 1 when the annotation has no border.
+func (p *PdfiumImplementation) getAnnotationStyle(annotation references.FPDF_ANNOTATION) (appearance.Style, error) {
+	style := appearance.Style{
+		BorderWidth: 1,
+		Opacity:     255,
+	}
+
+	// PDFium returns black for colors that are not set, so check whether
+	// they are set first.
+	colors := []struct {
+		key       string
+		colorType enums.FPDFANNOT_COLORTYPE
+		color     **structs.FPDF_COLOR
+	}{
+		{"C", enums.FPDFANNOT_COLORTYPE_Color, &style.Color},
+		{"IC", enums.FPDFANNOT_COLORTYPE_InteriorColor, &style.InteriorColor},
+	}
+	for _, color := range colors {
+		hasKey, err := p.FPDFAnnot_HasKey(&requests.FPDFAnnot_HasKey{
+			Annotation: annotation,
+			Key:        color.key,
+		})
+		if err != nil {
+			return style, err
+		}
+
+		if !hasKey.HasKey {
+			continue
+		}
+
+		*color.color = p.getAnnotationColor(annotation, color.colorType)
+		if *color.color != nil {
+			style.Opacity = (*color.color).A
+		}
+	}
+
+	border, err := p.FPDFAnnot_GetBorder(&requests.FPDFAnnot_GetBorder{
+		Annotation: annotation,
+	})
+	if err == nil {
+		style.BorderWidth = border.BorderWidth
+	}
+
+	return style, nil
+}
+
+// getFreeTextAppearance returns the appearance of a free text annotation.
+// The color of the annotation is the background, the border is drawn in the
+// color of the text.
+func (p *PdfiumImplementation) getFreeTextAppearance(request *requests.GenerateAnnotationAppearance, rect structs.FPDF_FS_RECTF, style appearance.Style) (string, error) {
+	fontName := request.Font
+	if fontName == "" {
+		fontName = "Helvetica"
+	}
+
+	fontSize := request.FontSize
+	if fontSize == 0 {
+		fontSize = 12
+	}
+
+	textColor := structs.FPDF_COLOR{R: 0, G: 0, B: 0, A: 255}
+	if request.TextColor != nil {
+		textColor = *request.TextColor
+	}
+
+	text := p.getAnnotationStringValue(request.Annotation, "Contents")
+
+	alignment := appearance.TextAlignmentLeft
+	alignmentValue, err := p.FPDFAnnot_GetNumberValue(&requests.FPDFAnnot_GetNumberValue{
+		Annotation: request.Annotation,
+		Key:        "Q",
+	})
+	if err == nil {
+		alignment = appearance.TextAlignment(alignmentValue.Value)
+	}
+
+	font, err := p.FPDFText_LoadStandardFont(&requests.FPDFText_LoadStandardFont{
+		Document: request.Document,
+		Font:     fontName,
+	})
+	if err != nil {
+		return "", err
+	}
+	defer p.FPDFFont_Close(&requests.FPDFFont_Close{
+		Font: font.Font,
+	})
+
+	ascent, err := p.FPDFFont_GetAscent(&requests.FPDFFont_GetAscent{
+		Font:     font.Font,
+		FontSize: fontSize,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	descent, err := p.FPDFFont_GetDescent(&requests.FPDFFont_GetDescent{
+		Font:     font.Font,
+		FontSize: fontSize,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	// The background is the color of the annotation, the border has the
+	// color of the text.
+	style.InteriorColor = style.Color
+	style.Color = &textColor
+
+	return appearance.FreeText(rect, style, appearance.FreeTextText{
+		Text:      text,
+		FontSize:  fontSize,
+		Ascent:    ascent.Ascent,
+		Descent:   descent.Descent,
+		Color:     textColor,
+		Alignment: alignment,
+		Glyph: func(r rune) (*appearance.Glyph, error) {
+			return p.getGlyph(font.Font, fontSize, r)
+		},
+	})
+}
+
+// getGlyph returns the width and outline of a character in a font.
+func (p *PdfiumImplementation) getGlyph(font references.FPDF_FONT, fontSize float32, r rune) (*appearance.Glyph, error) {
+	glyphWidth, err := p.FPDFFont_GetGlyphWidth(&requests.FPDFFont_GetGlyphWidth{
+		Font:     font,
+		Glyph:    uint32(r),
+		FontSize: fontSize,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("could not get the glyph of %q: %w", r, err)
+	}
+
+	glyph := &appearance.Glyph{
+		Width: glyphWidth.GlyphWidth,
+	}
+
+	// Characters like spaces don't have a path.
+	glyphPath, err := p.FPDFFont_GetGlyphPath(&requests.FPDFFont_GetGlyphPath{
+		Font:     font,
+		Glyph:    uint32(r),
+		FontSize: fontSize,
+	})
+	if err != nil {
+		return glyph, nil
+	}
+
+	segmentCount, err := p.FPDFGlyphPath_CountGlyphSegments(&requests.FPDFGlyphPath_CountGlyphSegments{
+		GlyphPath: glyphPath.GlyphPath,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for i := 0; i < segmentCount.Count; i++ {
+		segment, err := p.FPDFGlyphPath_GetGlyphPathSegment(&requests.FPDFGlyphPath_GetGlyphPathSegment{
+			GlyphPath: glyphPath.GlyphPath,
+			Index:     i,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		segmentPoint, err := p.FPDFPathSegment_GetPoint(&requests.FPDFPathSegment_GetPoint{
+			PathSegment: segment.GlyphPathSegment,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		segmentType, err := p.FPDFPathSegment_GetType(&requests.FPDFPathSegment_GetType{
+			PathSegment: segment.GlyphPathSegment,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		segmentClose, err := p.FPDFPathSegment_GetClose(&requests.FPDFPathSegment_GetClose{
+			PathSegment: segment.GlyphPathSegment,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		glyph.Segments = append(glyph.Segments, appearance.GlyphSegment{
+			Type:  segmentType.Type,
+			X:     segmentPoint.X,
+			Y:     segmentPoint.Y,
+			Close: segmentClose.IsClose,
+		})
+	}
+
+	return glyph, nil
+}
+
+// generateStampAppearance appends a border and the name of the stamp as
+// text to a stamp annotation, PDFium generates the appearance stream from
+// these objects.
+func (p *PdfiumImplementation) generateStampAppearance(request *requests.GenerateAnnotationAppearance, rect structs.FPDF_FS_RECTF, style appearance.Style) error {
+	fontName := request.Font
+	if fontName == "" {
+		fontName = "Helvetica"
+	}
+
+	color := structs.FPDF_COLOR{R: 255, G: 0, B: 0, A: 255}
+	if style.Color != nil {
+		color = *style.Color
+	}
+
+	// The name (icon) of the stamp is the text, the default name of stamps
+	// is Draft.
+	text := p.getAnnotationStringValue(request.Annotation, "Name")
+	if text == "" {
+		text = p.getAnnotationStringValue(request.Annotation, "Contents")
+	}
+	if text == "" {
+		text = "Draft"
+	}
+	text = strings.ToUpper(text)
+
+	if rect.Top < rect.Bottom {
+		rect.Top, rect.Bottom = rect.Bottom, rect.Top
+	}
+	width := rect.Right - rect.Left
+	height := rect.Top - rect.Bottom
+	borderWidth := style.BorderWidth
+	if borderWidth <= 0 {
+		borderWidth = 1
+	}
+
+	border, err := p.FPDFPageObj_CreateNewRect(&requests.FPDFPageObj_CreateNewRect{
+		X: rect.Left + borderWidth/2,
+		Y: rect.Bottom + borderWidth/2,
+		W: width - borderWidth,
+		H: height - borderWidth,
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = p.FPDFPageObj_SetStrokeColor(&requests.FPDFPageObj_SetStrokeColor{
+		PageObject:  border.PageObject,
+		StrokeColor: color,
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = p.FPDFPageObj_SetStrokeWidth(&requests.FPDFPageObj_SetStrokeWidth{
+		PageObject:  border.PageObject,
+		StrokeWidth: borderWidth,
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = p.FPDFPath_SetDrawMode(&requests.FPDFPath_SetDrawMode{
+		PageObject: border.PageObject,
+		FillMode:   enums.FPDF_FILLMODE_NONE,
+		Stroke:     true,
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = p.FPDFAnnot_AppendObject(&requests.FPDFAnnot_AppendObject{
+		Annotation: request.Annotation,
+		PageObject: border.PageObject,
+	})
+	if err != nil {
+		return err
+	}
+
+	textObject, err := p.FPDFPageObj_NewTextObj(&requests.FPDFPageObj_NewTextObj{
+		Document: request.Document,
+		Font:     fontName,
+		FontSize: 1,
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = p.FPDFText_SetText(&requests.FPDFText_SetText{
+		PageObject: textObject.PageObject,
+		Text:       text,
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = p.FPDFPageObj_SetFillColor(&requests.FPDFPageObj_SetFillColor{
+		PageObject: textObject.PageObject,
+		FillColor:  color,
+	})
+	if err != nil {
+		return err
+	}
+
+	bounds, err := p.FPDFPageObj_GetBounds(&requests.FPDFPageObj_GetBounds{
+		PageObject: textObject.PageObject,
+	})
+	if err != nil {
+		return err
+	}
+
+	// Scale the text to fit the stamp, with a margin around it.
+	scale := float32(1)
+	textWidth := bounds.Right - bounds.Left
+	textHeight := bounds.Top - bounds.Bottom
+	if textWidth > 0 && textHeight > 0 {
+		scale = (width - 2*borderWidth) * 0.8 / textWidth
+		if heightScale := (height - 2*borderWidth) * 0.6 / textHeight; heightScale < scale {
+			scale = heightScale
+		}
+	}
+
+	_, err = p.FPDFPageObj_Transform(&requests.FPDFPageObj_Transform{
+		PageObject: textObject.PageObject,
+		Transform: structs.FPDF_FS_MATRIX{
+			A: scale,
+			D: scale,
+			E: rect.Left + width/2 - scale*(bounds.Left+bounds.Right)/2,
+			F: rect.Bottom + height/2 - scale*(bounds.Bottom+bounds.Top)/2,
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = p.FPDFAnnot_AppendObject(&requests.FPDFAnnot_AppendObject{
+		Annotation: request.Annotation,
+		PageObject: textObject.PageObject,
+	})
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,576 @@
+package implementation_cgo
+
+import (
+	"fmt"
+
+	"github.com/klippa-app/go-pdfium/enums"
+	"github.com/klippa-app/go-pdfium/internal/form_data"
+	"github.com/klippa-app/go-pdfium/references"
+	"github.com/klippa-app/go-pdfium/requests"
+	"github.com/klippa-app/go-pdfium/responses"
+	"github.com/klippa-app/go-pdfium/structs"
+)
+
+// GetAnnotations returns all the annotations of a document per page.
+// Experimental API.
+func (p *PdfiumImplementation) GetAnnotations(request *requests.GetAnnotations) (*responses.GetAnnotations, error) {
+	pageCount, err := p.FPDF_GetPageCount(&requests.FPDF_GetPageCount{
+		Document: request.Document,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	pages := []responses.AnnotationsPage{}
+	for pageIndex := 0; pageIndex < pageCount.PageCount; pageIndex++ {
+		page := requests.Page{
+			ByIndex: &requests.PageByIndex{
+				Document: request.Document,
+				Index:    pageIndex,
+			},
+		}
+
+		annotationCount, err := p.FPDFPage_GetAnnotCount(&requests.FPDFPage_GetAnnotCount{
+			Page: page,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		annotationsPage := responses.AnnotationsPage{
+			Page:        pageIndex,
+			Annotations: []responses.Annotation{},
+		}
+
+		for i := 0; i < annotationCount.Count; i++ {
+			annotation, err := p.getAnnotation(request.Document, page, i)
+			if err != nil {
+				return nil, err
+			}
+
+			annotationsPage.Annotations = append(annotationsPage.Annotations, *annotation)
+		}
+
+		pages = append(pages, annotationsPage)
+	}
+
+	return &responses.GetAnnotations{
+		Pages: pages,
+	}, nil
+}
+
+func (p *PdfiumImplementation) getAnnotation(document references.FPDF_DOCUMENT, page requests.Page, index int) (*responses.Annotation, error) {
+	annotation, err := p.FPDFPage_GetAnnot(&requests.FPDFPage_GetAnnot{
+		Page:  page,
+		Index: index,
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer p.FPDFPage_CloseAnnot(&requests.FPDFPage_CloseAnnot{
+		Annotation: annotation.Annotation,
+	})
+
+	subtype, err := p.FPDFAnnot_GetSubtype(&requests.FPDFAnnot_GetSubtype{
+		Annotation: annotation.Annotation,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	rect, err := p.FPDFAnnot_GetRect(&requests.FPDFAnnot_GetRect{
+		Annotation: annotation.Annotation,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	flags, err := p.FPDFAnnot_GetFlags(&requests.FPDFAnnot_GetFlags{
+		Annotation: annotation.Annotation,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &responses.Annotation{
+		Index:   index,
+		Subtype: subtype.Subtype,
+		Rect:    rect.Rect,
+		Flags:   flags.Flags,
+	}
+
+	// The string values are optional, PDFium returns an error when they
+	// are empty.
+	resp.Name = p.getAnnotationStringValue(annotation.Annotation, "NM")
+	resp.Contents = p.getAnnotationStringValue(annotation.Annotation, "Contents")
+	resp.Author = p.getAnnotationStringValue(annotation.Annotation, "T")
+	resp.ModificationDate = p.getAnnotationStringValue(annotation.Annotation, "M")
+	resp.Color = p.getAnnotationColor(annotation.Annotation, enums.FPDFANNOT_COLORTYPE_Color)
+	resp.InteriorColor = p.getAnnotationColor(annotation.Annotation, enums.FPDFANNOT_COLORTYPE_InteriorColor)
+
+	resp.QuadPoints, err = p.getAnnotationQuadPoints(annotation.Annotation)
+	if err != nil {
+		return nil, err
+	}
+
+	switch subtype.Subtype {
+	case enums.FPDF_ANNOT_SUBTYPE_INK:
+		resp.InkPaths, err = p.getAnnotationInkPaths(annotation.Annotation)
+		if err != nil {
+			return nil, err
+		}
+	case enums.FPDF_ANNOT_SUBTYPE_POLYGON, enums.FPDF_ANNOT_SUBTYPE_POLYLINE:
+		vertices, err := p.FPDFAnnot_GetVertices(&requests.FPDFAnnot_GetVertices{
+			Annotation: annotation.Annotation,
+		})
+		if err == nil {
+			resp.Vertices = vertices.Vertices
+		}
+	case enums.FPDF_ANNOT_SUBTYPE_LINE:
+		line, err := p.FPDFAnnot_GetLine(&requests.FPDFAnnot_GetLine{
+			Annotation: annotation.Annotation,
+		})
+		if err == nil {
+			resp.Line = &responses.AnnotationLine{
+				Start: line.Start,
+				End:   line.End,
+			}
+		}
+	case enums.FPDF_ANNOT_SUBTYPE_FILEATTACHMENT:
+		file, err := p.getAnnotationFile(document, annotation.Annotation)
+		if err != nil {
+			return nil, err
+		}
+		resp.File = file
+	}
+
+	resp.Popup, err = p.getLinkedAnnotationIndex(page, annotation.Annotation, "Popup")
+	if err != nil {
+		return nil, err
+	}
+
+	resp.Parent, err = p.getLinkedAnnotationIndex(page, annotation.Annotation, "Parent")
+	if err != nil {
+		return nil, err
+	}
+
+	resp.InReplyTo, err = p.getLinkedAnnotationIndex(page, annotation.Annotation, "IRT")
+	if err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+func (p *PdfiumImplementation) getAnnotationInkPaths(annotation references.FPDF_ANNOTATION) ([][]structs.FPDF_FS_POINTF, error) {
+	inkListCount, err := p.FPDFAnnot_GetInkListCount(&requests.FPDFAnnot_GetInkListCount{
+		Annotation: annotation,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var paths [][]structs.FPDF_FS_POINTF
+	for i := uint64(0); i < inkListCount.Count; i++ {
+		inkListPath, err := p.FPDFAnnot_GetInkListPath(&requests.FPDFAnnot_GetInkListPath{
+			Annotation: annotation,
+			Index:      i,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		paths = append(paths, inkListPath.Path)
+	}
+
+	return paths, nil
+}
+
+func (p *PdfiumImplementation) getAnnotationQuadPoints(annotation references.FPDF_ANNOTATION) ([]structs.FPDF_FS_QUADPOINTSF, error) {
+	attachmentPointsCount, err := p.FPDFAnnot_CountAttachmentPoints(&requests.FPDFAnnot_CountAttachmentPoints{
+		Annotation: annotation,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var quadPoints []structs.FPDF_FS_QUADPOINTSF
+	for i := uint64(0); i < attachmentPointsCount.Count; i++ {
+		attachmentPoints, err := p.FPDFAnnot_GetAttachmentPoints(&requests.FPDFAnnot_GetAttachmentPoints{
+			Annotation: annotation,
+			Index:      i,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		quadPoints = append(quadPoints, attachmentPoints.QuadPoints)
+	}
+
+	return quadPoints, nil
+}
+
+// getAnnotationStringValue returns a string value of an annotation, empty
+// when it has none.
+func (p *PdfiumImplementation) getAnnotationStringValue(annotation references.FPDF_ANNOTATION, key string) string {
+	value, err := p.FPDFAnnot_GetStringValue(&requests.FPDFAnnot_GetStringValue{
+		Annotation: annotation,
+		Key:        key,
+	})
+	if err != nil {
+		return ""
+	}
+
+	return value.Value
+}
+
+// getAnnotationColor returns a color of an annotation, nil when it has none.
+func (p *PdfiumImplementation) getAnnotationColor(annotation references.FPDF_ANNOTATION, colorType enums.FPDFANNOT_COLORTYPE) *structs.FPDF_COLOR {
+	color, err := p.FPDFAnnot_GetColor(&requests.FPDFAnnot_GetColor{
+		Annotation: annotation,
+		ColorType:  colorType,
+	})
+	if err != nil {
+		return nil
+	}
+
+	return &structs.FPDF_COLOR{
+		R: color.R,
+		G: color.G,
+		B: color.B,
+		A: color.A,
+	}
+}
+
+// getLinkedAnnotationIndex returns the index of the annotation that the
+// given key links to, nil when the key doesn't link to an annotation of the
+// page.
+func (p *PdfiumImplementation) getLinkedAnnotationIndex(page requests.Page, annotation references.FPDF_ANNOTATION, key string) (*int, error) {
+	linkedAnnotation, err := p.FPDFAnnot_GetLinkedAnnot(&requests.FPDFAnnot_GetLinkedAnnot{
+		Annotation: annotation,
+		Key:        key,
+	})
+	if err != nil {
+		return nil, nil
+	}
+	defer p.FPDFPage_CloseAnnot(&requests.FPDFPage_CloseAnnot{
+		Annotation: linkedAnnotation.LinkedAnnotation,
+	})
+
+	annotationIndex, err := p.FPDFPage_GetAnnotIndex(&requests.FPDFPage_GetAnnotIndex{
+		Page:       page,
+		Annotation: linkedAnnotation.LinkedAnnotation,
+	})
+	if err != nil {
+		return nil, nil
+	}
+
+	return &annotationIndex.Index, nil
+}
+
+// getAnnotationFile returns the attached file of a file attachment
+// annotation, nil when it has none.
+func (p *PdfiumImplementation) getAnnotationFile(document references.FPDF_DOCUMENT, annotation references.FPDF_ANNOTATION) (*responses.AnnotationFile, error) {
+	attachment, err := p.FPDFAnnot_GetFileAttachment(&requests.FPDFAnnot_GetFileAttachment{
+		Document:   document,
+		Annotation: annotation,
+	})
+	if err != nil {
+		return nil, nil
+	}
+
+	file := &responses.AnnotationFile{}
+
+	name, err := p.FPDFAttachment_GetName(&requests.FPDFAttachment_GetName{
+		Attachment: attachment.Attachment,
+	})
+	if err == nil {
+		file.Name = name.Name
+	}
+
+	content, err := p.FPDFAttachment_GetFile(&requests.FPDFAttachment_GetFile{
+		Attachment: attachment.Attachment,
+	})
+	if err == nil {
+		file.Content = content.Contents
+	}
+
+	return file, nil
+}
+
+// ExportAnnotations exports the markup annotations of a document as XFDF.
+// Popups are exported with the annotation that they belong to.
+// Experimental API.
+func (p *PdfiumImplementation) ExportAnnotations(request *requests.ExportAnnotations) (*responses.ExportAnnotations, error) {
+	annotations, err := p.GetAnnotations(&requests.GetAnnotations{
+		Document: request.Document,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	formData := form_data.FormData{
+		FileName:    request.FileName,
+		Annotations: []form_data.Annotation{},
+	}
+
+	for _, page := range annotations.Pages {
+		// XFDF refers to annotations by name, so give the annotations
+		// without a name one.
+		names := make([]string, len(page.Annotations))
+		for i, annotation := range page.Annotations {
+			names[i] = annotation.Name
+			if names[i] == "" {
+				names[i] = fmt.Sprintf("page-%d-annotation-%d", page.Page, annotation.Index)
+			}
+		}
+
+		for i, annotation := range page.Annotations {
+			if !form_data.IsMarkupAnnotation(annotation.Subtype) {
+				continue
+			}
+
+			formDataAnnotation := form_data.Annotation{
+				Subtype:    annotation.Subtype,
+				Page:       page.Page,
+				Rect:       annotation.Rect,
+				Name:       names[i],
+				Author:     annotation.Author,
+				Date:       annotation.ModificationDate,
+				Contents:   annotation.Contents,
+				Color:      annotation.Color,
+				Flags:      annotation.Flags,
+				QuadPoints: annotation.QuadPoints,
+				InkList:    annotation.InkPaths,
+				Vertices:   annotation.Vertices,
+			}
+
+			// PDFium returns black as interior color when an annotation has
+			// none, so only export it for subtypes that have one.
+			switch annotation.Subtype {
+			case enums.FPDF_ANNOT_SUBTYPE_SQUARE, enums.FPDF_ANNOT_SUBTYPE_CIRCLE, enums.FPDF_ANNOT_SUBTYPE_LINE, enums.FPDF_ANNOT_SUBTYPE_POLYGON, enums.FPDF_ANNOT_SUBTYPE_POLYLINE:
+				formDataAnnotation.InteriorColor = annotation.InteriorColor
+			}
+
+			if annotation.Line != nil {
+				formDataAnnotation.Line = &[2]structs.FPDF_FS_POINTF{annotation.Line.Start, annotation.Line.End}
+			}
+
+			if annotation.InReplyTo != nil && *annotation.InReplyTo < len(names) {
+				formDataAnnotation.InReplyTo = names[*annotation.InReplyTo]
+			}
+
+			if annotation.Popup != nil && *annotation.Popup < len(page.Annotations) {
+				formDataAnnotation.Popup = &form_data.AnnotationPopup{
+					Rect: page.Annotations[*annotation.Popup].Rect,
+				}
+			}
+
+			if annotation.File != nil {
+				formDataAnnotation.FileName = annotation.File.Name
+				formDataAnnotation.FileContent = annotation.File.Content
+			}
+
+			formData.Annotations = append(formData.Annotations, formDataAnnotation)
+		}
+	}
+
+	data, err := form_data.Write(requests.FormDataFormatXFDF, formData)
+	if err != nil {
+		return nil, err
+	}
+
+	return &responses.ExportAnnotations{
+		Data: data,
+	}, nil
+}
+
+// ImportAnnotations imports the markup annotations of an XFDF document into
+// a document. Annotations of subtypes that PDFium can't create are skipped.
+// Popups and replies are not linked, PDFium has no API to link annotations.
+// Experimental API.
+func (p *PdfiumImplementation) ImportAnnotations(request *requests.ImportAnnotations) (*responses.ImportAnnotations, error) {
+	pageCount, err := p.FPDF_GetPageCount(&requests.FPDF_GetPageCount{
+		Document: request.Document,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	formData, err := form_data.Read(requests.FormDataFormatXFDF, request.Data)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &responses.ImportAnnotations{
+		FileName: formData.FileName,
+		Skipped:  []responses.ImportAnnotationsSkipped{},
+	}
+
+	for _, annotation := range formData.Annotations {
+		reason := ""
+		if annotation.Page < 0 || annotation.Page >= pageCount.PageCount {
+			reason = "the page doesn't exist"
+		} else {
+			isSupportedSubtype, err := p.FPDFAnnot_IsSupportedSubtype(&requests.FPDFAnnot_IsSupportedSubtype{
+				Subtype: annotation.Subtype,
+			})
+			if err != nil {
+				return nil, err
+			}
+
+			if !isSupportedSubtype.IsSupported {
+				reason = "PDFium can't create annotations of this subtype"
+			}
+		}
+
+		if reason != "" {
+			resp.Skipped = append(resp.Skipped, responses.ImportAnnotationsSkipped{
+				Page:    annotation.Page,
+				Name:    annotation.Name,
+				Subtype: annotation.Subtype,
+				Reason:  reason,
+			})
+			continue
+		}
+
+		err = p.importAnnotation(request.Document, annotation)
+		if err != nil {
+			return nil, err
+		}
+
+		resp.Imported++
+	}
+
+	return resp, nil
+}
+
+func (p *PdfiumImplementation) importAnnotation(document references.FPDF_DOCUMENT, annotation form_data.Annotation) error {
+	createdAnnotation, err := p.FPDFPage_CreateAnnot(&requests.FPDFPage_CreateAnnot{
+		Page: requests.Page{
+			ByIndex: &requests.PageByIndex{
+				Document: document,
+				Index:    annotation.Page,
+			},
+		},
+		Subtype: annotation.Subtype,
+	})
+	if err != nil {
+		return err
+	}
+	defer p.FPDFPage_CloseAnnot(&requests.FPDFPage_CloseAnnot{
+		Annotation: createdAnnotation.Annotation,
+	})
+
+	newAnnotation := createdAnnotation.Annotation
+
+	_, err = p.FPDFAnnot_SetRect(&requests.FPDFAnnot_SetRect{
+		Annotation: newAnnotation,
+		Rect:       annotation.Rect,
+	})
+	if err != nil {
+		return err
+	}
+
+	if annotation.Flags != enums.FPDF_ANNOT_FLAG_NONE {
+		_, err = p.FPDFAnnot_SetFlags(&requests.FPDFAnnot_SetFlags{
+			Annotation: newAnnotation,
+			Flags:      annotation.Flags,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	stringValues := []struct {
+		key   string
+		value string
+	}{
+		{"NM", annotation.Name},
+		{"T", annotation.Author},
+		{"M", annotation.Date},
+		{"Contents", annotation.Contents},
+	}
+	for _, stringValue := range stringValues {
+		if stringValue.value == "" {
+			continue
+		}
+
+		_, err = p.FPDFAnnot_SetStringValue(&requests.FPDFAnnot_SetStringValue{
+			Annotation: newAnnotation,
+			Key:        stringValue.key,
+			Value:      stringValue.value,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	colors := []struct {
+		colorType enums.FPDFANNOT_COLORTYPE
+		color     *structs.FPDF_COLOR
+	}{
+		{enums.FPDFANNOT_COLORTYPE_Color, annotation.Color},
+		{enums.FPDFANNOT_COLORTYPE_InteriorColor, annotation.InteriorColor},
+	}
+	for _, color := range colors {
+		if color.color == nil {
+			continue
+		}
+
+		_, err = p.FPDFAnnot_SetColor(&requests.FPDFAnnot_SetColor{
+			Annotation: newAnnotation,
+			ColorType:  color.colorType,
+			R:          color.color.R,
+			G:          color.color.G,
+			B:          color.color.B,
+			A:          color.color.A,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, quadPoints := range annotation.QuadPoints {
+		_, err = p.FPDFAnnot_AppendAttachmentPoints(&requests.FPDFAnnot_AppendAttachmentPoints{
+			Annotation:       newAnnotation,
+			AttachmentPoints: quadPoints,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	if annotation.Subtype == enums.FPDF_ANNOT_SUBTYPE_INK {
+		for _, path := range annotation.InkList {
+			_, err = p.FPDFAnnot_AddInkStroke(&requests.FPDFAnnot_AddInkStroke{
+				Annotation: newAnnotation,
+				Points:     path,
+			})
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	if annotation.Subtype == enums.FPDF_ANNOT_SUBTYPE_FILEATTACHMENT && annotation.FileName != "" {
+		attachment, err := p.FPDFAnnot_AddFileAttachment(&requests.FPDFAnnot_AddFileAttachment{
+			Document:   document,
+			Annotation: newAnnotation,
+			Name:       annotation.FileName,
+		})
+		if err != nil {
+			return err
+		}
+
+		_, err = p.FPDFAttachment_SetFile(&requests.FPDFAttachment_SetFile{
+			Attachment: attachment.Attachment,
+			Contents:   annotation.FileContent,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
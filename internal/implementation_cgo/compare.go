@@ -0,0 +1,485 @@
+package implementation_cgo
+
+import (
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"image"
+	"math"
+	"strings"
+	"unicode"
+
+	"github.com/klippa-app/go-pdfium/enums"
+	"github.com/klippa-app/go-pdfium/internal/diff"
+	"github.com/klippa-app/go-pdfium/references"
+	"github.com/klippa-app/go-pdfium/requests"
+	"github.com/klippa-app/go-pdfium/responses"
+)
+
+// CompareDocuments renders the pages of two documents and compares them
+// pixel by pixel. Pages that are the same in both documents are matched,
+// so added and removed pages are detected and don't cause the pages after
+// them to be reported as changed.
+func (p *PdfiumImplementation) CompareDocuments(request *requests.CompareDocuments) (*responses.CompareDocuments, error) {
+	if request.DPI == 0 {
+		return nil, errors.New("no DPI given")
+	}
+
+	if request.Tolerance < 0 || request.Tolerance > 255 {
+		return nil, fmt.Errorf("invalid tolerance %d given", request.Tolerance)
+	}
+
+	originalHashes, err := p.getPageRenderHashes(request.Original, request.DPI, request.RenderFlags)
+	if err != nil {
+		return nil, err
+	}
+
+	modifiedHashes, err := p.getPageRenderHashes(request.Modified, request.DPI, request.RenderFlags)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &responses.CompareDocuments{
+		Identical: true,
+		Pages:     []responses.CompareDocumentsPage{},
+	}
+
+	for _, pair := range alignPages(originalHashes, modifiedHashes) {
+		originalPage := pair[0]
+		modifiedPage := pair[1]
+
+		page := responses.CompareDocumentsPage{
+			Status:     responses.CompareDocumentsPageStatusIdentical,
+			Difference: 1,
+		}
+
+		if originalPage == -1 {
+			page.Status = responses.CompareDocumentsPageStatusAdded
+			page.ModifiedPage = &modifiedPage
+		} else if modifiedPage == -1 {
+			page.Status = responses.CompareDocumentsPageStatusRemoved
+			page.OriginalPage = &originalPage
+		} else {
+			page.OriginalPage = &originalPage
+			page.ModifiedPage = &modifiedPage
+			page.Difference = 0
+
+			if originalHashes[originalPage] != modifiedHashes[modifiedPage] {
+				err = p.comparePages(request, originalPage, modifiedPage, &page)
+				if err != nil {
+					return nil, err
+				}
+			}
+		}
+
+		if page.Status != responses.CompareDocumentsPageStatusIdentical {
+			resp.Identical = false
+		}
+
+		resp.Pages = append(resp.Pages, page)
+	}
+
+	return resp, nil
+}
+
+// getPageRenderHashes renders all pages of the document and returns a hash
+// of every rendered page, pages with the same hash look the same.
+func (p *PdfiumImplementation) getPageRenderHashes(document references.FPDF_DOCUMENT, dpi int, renderFlags enums.FPDF_RENDER_FLAG) ([][sha256.Size]byte, error) {
+	pageCount, err := p.FPDF_GetPageCount(&requests.FPDF_GetPageCount{
+		Document: document,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	hashes := make([][sha256.Size]byte, pageCount.PageCount)
+	for i := 0; i < pageCount.PageCount; i++ {
+		renderedPage, err := p.renderComparePage(document, i, dpi, renderFlags)
+		if err != nil {
+			return nil, err
+		}
+
+		hash := sha256.New()
+		fmt.Fprintf(hash, "%dx%d:", renderedPage.Result.Width, renderedPage.Result.Height)
+		hash.Write(renderedPage.Result.Image.Pix)
+		hash.Sum(hashes[i][:0])
+
+		renderedPage.Cleanup()
+	}
+
+	return hashes, nil
+}
+
+func (p *PdfiumImplementation) renderComparePage(document references.FPDF_DOCUMENT, index int, dpi int, renderFlags enums.FPDF_RENDER_FLAG) (*responses.RenderPageInDPI, error) {
+	return p.RenderPageInDPI(&requests.RenderPageInDPI{
+		Page: requests.Page{
+			ByIndex: &requests.PageByIndex{
+				Document: document,
+				Index:    index,
+			},
+		},
+		DPI:         dpi,
+		RenderFlags: renderFlags,
+	})
+}
+
+// alignPages matches the pages of two documents by the longest common
+// subsequence of the page hashes. The pages between the matched pages are
+// paired as changed pages, the pages that remain are added or removed. The
+// result is a list of original and modified page indexes, -1 when the page
+// is not in the document.
+func alignPages(originalHashes, modifiedHashes [][sha256.Size]byte) [][2]int {
+	// lengths[i][j] is the length of the longest common subsequence of the
+	// pages from i in the original and the pages from j in the modified
+	// document.
+	lengths := make([][]int, len(originalHashes)+1)
+	for i := range lengths {
+		lengths[i] = make([]int, len(modifiedHashes)+1)
+	}
+
+	for i := len(originalHashes) - 1; i >= 0; i-- {
+		for j := len(modifiedHashes) - 1; j >= 0; j-- {
+			if originalHashes[i] == modifiedHashes[j] {
+				lengths[i][j] = lengths[i+1][j+1] + 1
+			} else if lengths[i+1][j] >= lengths[i][j+1] {
+				lengths[i][j] = lengths[i+1][j]
+			} else {
+				lengths[i][j] = lengths[i][j+1]
+			}
+		}
+	}
+
+	pairs := [][2]int{}
+	removed := []int{}
+	added := []int{}
+
+	// The pages that are not matched between two matched pages are paired
+	// as long as there are pages on both sides.
+	flush := func() {
+		for len(removed) > 0 && len(added) > 0 {
+			pairs = append(pairs, [2]int{removed[0], added[0]})
+			removed = removed[1:]
+			added = added[1:]
+		}
+		for _, page := range removed {
+			pairs = append(pairs, [2]int{page, -1})
+		}
+		for _, page := range added {
+			pairs = append(pairs, [2]int{-1, page})
+		}
+		removed = []int{}
+		added = []int{}
+	}
+
+	i, j := 0, 0
+	for i < len(originalHashes) || j < len(modifiedHashes) {
+		if i < len(originalHashes) && j < len(modifiedHashes) && originalHashes[i] == modifiedHashes[j] {
+			flush()
+			pairs = append(pairs, [2]int{i, j})
+			i++
+			j++
+		} else if j == len(modifiedHashes) || (i < len(originalHashes) && lengths[i+1][j] >= lengths[i][j+1]) {
+			removed = append(removed, i)
+			i++
+		} else {
+			added = append(added, j)
+			j++
+		}
+	}
+	flush()
+
+	return pairs
+}
+
+// comparePages compares the pixels of two pages that are not the same.
+func (p *PdfiumImplementation) comparePages(request *requests.CompareDocuments, originalPage, modifiedPage int, page *responses.CompareDocumentsPage) error {
+	renderedOriginalPage, err := p.renderComparePage(request.Original, originalPage, request.DPI, request.RenderFlags)
+	if err != nil {
+		return err
+	}
+
+	// Copy the image, the memory of the render can be reused by the next
+	// render.
+	originalImage := &image.RGBA{
+		Pix:    append([]byte(nil), renderedOriginalPage.Result.Image.Pix...),
+		Stride: renderedOriginalPage.Result.Image.Stride,
+		Rect:   renderedOriginalPage.Result.Image.Rect,
+	}
+	renderedOriginalPage.Cleanup()
+
+	renderedModifiedPage, err := p.renderComparePage(request.Modified, modifiedPage, request.DPI, request.RenderFlags)
+	if err != nil {
+		return err
+	}
+	defer renderedModifiedPage.Cleanup()
+
+	modifiedImage := renderedModifiedPage.Result.Image
+
+	// Compare the area of both pages, for pages with a different size the
+	// pixels that are only on one of the pages are changed.
+	width := originalImage.Rect.Dx()
+	if modifiedImage.Rect.Dx() > width {
+		width = modifiedImage.Rect.Dx()
+	}
+
+	height := originalImage.Rect.Dy()
+	if modifiedImage.Rect.Dy() > height {
+		height = modifiedImage.Rect.Dy()
+	}
+
+	var diffImage *image.RGBA
+	if request.IncludeDiffImages {
+		diffImage = image.NewRGBA(image.Rect(0, 0, width, height))
+	}
+
+	changedPixels := 0
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			originalPixel := rgbaPixel(originalImage, x, y)
+			modifiedPixel := rgbaPixel(modifiedImage, x, y)
+
+			changed := originalPixel == nil || modifiedPixel == nil
+			if !changed {
+				for c := 0; c < 4; c++ {
+					difference := int(originalPixel[c]) - int(modifiedPixel[c])
+					if difference > request.Tolerance || -difference > request.Tolerance {
+						changed = true
+						break
+					}
+				}
+			}
+
+			if changed {
+				changedPixels++
+			}
+
+			if diffImage == nil {
+				continue
+			}
+
+			offset := diffImage.PixOffset(x, y)
+			if changed {
+				copy(diffImage.Pix[offset:offset+4], []byte{0xFF, 0x00, 0x00, 0xFF})
+				continue
+			}
+
+			// Draw the unchanged pixels in light gray, so that the changes
+			// stand out.
+			gray := (299*int(modifiedPixel[0]) + 587*int(modifiedPixel[1]) + 114*int(modifiedPixel[2])) / 1000
+			lightGray := byte(255 - (255-gray)/4)
+			copy(diffImage.Pix[offset:offset+4], []byte{lightGray, lightGray, lightGray, 0xFF})
+		}
+	}
+
+	page.ChangedPixels = changedPixels
+	page.Difference = float64(changedPixels) / float64(width*height)
+	if changedPixels > 0 {
+		page.Status = responses.CompareDocumentsPageStatusChanged
+		page.DiffImage = diffImage
+	}
+
+	return nil
+}
+
+// rgbaPixel returns the pixel data of the image at the given location, nil
+// when the location is outside the image.
+func rgbaPixel(img *image.RGBA, x, y int) []byte {
+	if !(image.Point{X: x, Y: y}.In(img.Rect)) {
+		return nil
+	}
+
+	offset := img.PixOffset(x, y)
+	return img.Pix[offset : offset+4]
+}
+
+// CompareDocumentsText compares the words of two documents. The words of
+// all pages are aligned, so text that moved to another page is not
+// reported as changed. The inserted and deleted words are returned with
+// their bounding boxes, so they can be highlighted.
+func (p *PdfiumImplementation) CompareDocumentsText(request *requests.CompareDocumentsText) (*responses.CompareDocumentsText, error) {
+	if request.DPI < 0 {
+		return nil, fmt.Errorf("invalid DPI %d given", request.DPI)
+	}
+
+	originalWords, err := p.getDocumentWords(request.Original, request.DPI)
+	if err != nil {
+		return nil, err
+	}
+
+	modifiedWords, err := p.getDocumentWords(request.Modified, request.DPI)
+	if err != nil {
+		return nil, err
+	}
+
+	originalTexts := make([]string, len(originalWords))
+	for i := range originalWords {
+		originalTexts[i] = originalWords[i].Text
+	}
+
+	modifiedTexts := make([]string, len(modifiedWords))
+	for i := range modifiedWords {
+		modifiedTexts[i] = modifiedWords[i].Text
+	}
+
+	resp := &responses.CompareDocumentsText{
+		Changes: []responses.CompareDocumentsTextChange{},
+	}
+
+	// Consecutive inserted or deleted words are returned as one change.
+	var change *responses.CompareDocumentsTextChange
+	for _, edit := range diff.Strings(originalTexts, modifiedTexts) {
+		if edit.Type == diff.EditTypeEqual {
+			change = nil
+			continue
+		}
+
+		var changeType responses.CompareDocumentsTextChangeType
+		var word responses.CompareDocumentsTextWord
+		if edit.Type == diff.EditTypeInsert {
+			changeType = responses.CompareDocumentsTextChangeTypeInsertion
+			word = modifiedWords[edit.Index]
+		} else {
+			changeType = responses.CompareDocumentsTextChangeTypeDeletion
+			word = originalWords[edit.Index]
+		}
+
+		if change == nil || change.Type != changeType {
+			resp.Changes = append(resp.Changes, responses.CompareDocumentsTextChange{
+				Type:  changeType,
+				Words: []responses.CompareDocumentsTextWord{},
+			})
+			change = &resp.Changes[len(resp.Changes)-1]
+		}
+
+		if change.Text != "" {
+			change.Text += " "
+		}
+		change.Text += word.Text
+		change.Words = append(change.Words, word)
+	}
+
+	resp.Identical = len(resp.Changes) == 0
+
+	return resp, nil
+}
+
+// getDocumentWords returns the words of all pages of a document, a word is
+// a sequence of chars without whitespace.
+func (p *PdfiumImplementation) getDocumentWords(document references.FPDF_DOCUMENT, dpi int) ([]responses.CompareDocumentsTextWord, error) {
+	pageCount, err := p.FPDF_GetPageCount(&requests.FPDF_GetPageCount{
+		Document: document,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	words := []responses.CompareDocumentsTextWord{}
+	for i := 0; i < pageCount.PageCount; i++ {
+		page := requests.Page{
+			ByIndex: &requests.PageByIndex{
+				Document: document,
+				Index:    i,
+			},
+		}
+
+		pageText, err := p.GetPageTextStructured(&requests.GetPageTextStructured{
+			Page: page,
+			Mode: requests.GetPageTextStructuredModeChars,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		var word *responses.CompareDocumentsTextWord
+		for _, char := range pageText.Chars {
+			if strings.TrimFunc(char.Text, unicode.IsSpace) == "" {
+				word = nil
+				continue
+			}
+
+			if word == nil {
+				words = append(words, responses.CompareDocumentsTextWord{
+					Page:          i,
+					PointPosition: char.PointPosition,
+				})
+				word = &words[len(words)-1]
+			}
+
+			word.Text += char.Text
+			word.PointPosition.Left = math.Min(word.PointPosition.Left, char.PointPosition.Left)
+			word.PointPosition.Top = math.Max(word.PointPosition.Top, char.PointPosition.Top)
+			word.PointPosition.Right = math.Max(word.PointPosition.Right, char.PointPosition.Right)
+			word.PointPosition.Bottom = math.Min(word.PointPosition.Bottom, char.PointPosition.Bottom)
+		}
+
+		if dpi == 0 {
+			continue
+		}
+
+		pageSize, err := p.GetPageSizeInPixels(&requests.GetPageSizeInPixels{
+			Page: page,
+			DPI:  dpi,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		for j := range words {
+			if words[j].Page != i {
+				continue
+			}
+
+			words[j].PixelPosition, err = p.getWordPixelPosition(page, pageSize, words[j].PointPosition)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return words, nil
+}
+
+// getWordPixelPosition converts the position of a word from page
+// coordinates to the pixel coordinates of the rendered page, this takes
+// care of the rotation of the page.
+func (p *PdfiumImplementation) getWordPixelPosition(page requests.Page, pageSize *responses.GetPageSizeInPixels, pointPosition responses.CharPosition) (*responses.CharPosition, error) {
+	topLeft, err := p.FPDF_PageToDevice(&requests.FPDF_PageToDevice{
+		Page:  page,
+		SizeX: pageSize.Width,
+		SizeY: pageSize.Height,
+		PageX: pointPosition.Left,
+		PageY: pointPosition.Top,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	bottomRight, err := p.FPDF_PageToDevice(&requests.FPDF_PageToDevice{
+		Page:  page,
+		SizeX: pageSize.Width,
+		SizeY: pageSize.Height,
+		PageX: pointPosition.Right,
+		PageY: pointPosition.Bottom,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	pixelPosition := &responses.CharPosition{
+		Left:   float64(topLeft.DeviceX),
+		Top:    float64(topLeft.DeviceY),
+		Right:  float64(bottomRight.DeviceX),
+		Bottom: float64(bottomRight.DeviceY),
+	}
+
+	// On rotated pages the corners of the page are other corners on the
+	// rendered page.
+	if pixelPosition.Left > pixelPosition.Right {
+		pixelPosition.Left, pixelPosition.Right = pixelPosition.Right, pixelPosition.Left
+	}
+	if pixelPosition.Top > pixelPosition.Bottom {
+		pixelPosition.Top, pixelPosition.Bottom = pixelPosition.Bottom, pixelPosition.Top
+	}
+
+	return pixelPosition, nil
+}
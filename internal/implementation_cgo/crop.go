@@ -0,0 +1,317 @@
+package implementation_cgo
+
+import (
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/klippa-app/go-pdfium/enums"
+	"github.com/klippa-app/go-pdfium/requests"
+	"github.com/klippa-app/go-pdfium/responses"
+)
+
+// CropPages sets the page boxes (CropBox by default) of the given pages.
+// The box can either be given, or be detected from the bounds of the
+// page objects or from a low DPI render of the page.
+func (p *PdfiumImplementation) CropPages(request *requests.CropPages) (*responses.CropPages, error) {
+	pageCount, err := p.FPDF_GetPageCount(&requests.FPDF_GetPageCount{
+		Document: request.Document,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if request.Mode != requests.CropPagesModeValues && request.Mode != requests.CropPagesModeObjects && request.Mode != requests.CropPagesModeRender {
+		return nil, errors.New("invalid crop mode given")
+	}
+
+	if request.Mode == requests.CropPagesModeValues && (request.Right <= request.Left || request.Top <= request.Bottom) {
+		return nil, errors.New("invalid crop box given")
+	}
+
+	boxes := request.Boxes
+	if len(boxes) == 0 {
+		boxes = []requests.CropPagesBox{requests.CropPagesBoxCropBox}
+	}
+
+	pages, err := parsePageRange(request.PageRange, pageCount.PageCount)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &responses.CropPages{
+		Pages: []responses.CropPagesPage{},
+	}
+
+	for _, pageIndex := range pages {
+		page := requests.Page{
+			ByIndex: &requests.PageByIndex{
+				Document: request.Document,
+				Index:    pageIndex,
+			},
+		}
+
+		pageResult := responses.CropPagesPage{
+			Page: pageIndex,
+		}
+
+		var left, bottom, right, top float32
+		if request.Mode == requests.CropPagesModeValues {
+			left, bottom, right, top = request.Left, request.Bottom, request.Right, request.Top
+		} else {
+			found := false
+			if request.Mode == requests.CropPagesModeObjects {
+				left, bottom, right, top, found, err = p.getPageObjectsContentBounds(page)
+			} else {
+				left, bottom, right, top, found, err = p.getRenderedContentBounds(page, request.DPI, request.RenderFlags, request.BackgroundTolerance)
+			}
+			if err != nil {
+				return nil, err
+			}
+
+			if !found {
+				resp.Pages = append(resp.Pages, pageResult)
+				continue
+			}
+
+			left -= request.Margin
+			bottom -= request.Margin
+			right += request.Margin
+			top += request.Margin
+
+			// Make sure we never crop outside the visible area of the page.
+			visibleLeft, visibleBottom, visibleRight, visibleTop, err := p.getPageVisibleBox(page)
+			if err != nil {
+				return nil, err
+			}
+
+			left = float32(math.Max(float64(left), float64(visibleLeft)))
+			bottom = float32(math.Max(float64(bottom), float64(visibleBottom)))
+			right = float32(math.Min(float64(right), float64(visibleRight)))
+			top = float32(math.Min(float64(top), float64(visibleTop)))
+
+			if right <= left || top <= bottom {
+				resp.Pages = append(resp.Pages, pageResult)
+				continue
+			}
+		}
+
+		for _, box := range boxes {
+			err = p.setPageBox(page, box, left, bottom, right, top)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		pageResult.Cropped = true
+		pageResult.Left = left
+		pageResult.Bottom = bottom
+		pageResult.Right = right
+		pageResult.Top = top
+		resp.Pages = append(resp.Pages, pageResult)
+	}
+
+	return resp, nil
+}
+
+// setPageBox sets the given page box to the given values.
+func (p *PdfiumImplementation) setPageBox(page requests.Page, box requests.CropPagesBox, left, bottom, right, top float32) error {
+	var err error
+	switch box {
+	case requests.CropPagesBoxMediaBox:
+		_, err = p.FPDFPage_SetMediaBox(&requests.FPDFPage_SetMediaBox{Page: page, Left: left, Bottom: bottom, Right: right, Top: top})
+	case requests.CropPagesBoxCropBox:
+		_, err = p.FPDFPage_SetCropBox(&requests.FPDFPage_SetCropBox{Page: page, Left: left, Bottom: bottom, Right: right, Top: top})
+	case requests.CropPagesBoxBleedBox:
+		_, err = p.FPDFPage_SetBleedBox(&requests.FPDFPage_SetBleedBox{Page: page, Left: left, Bottom: bottom, Right: right, Top: top})
+	case requests.CropPagesBoxTrimBox:
+		_, err = p.FPDFPage_SetTrimBox(&requests.FPDFPage_SetTrimBox{Page: page, Left: left, Bottom: bottom, Right: right, Top: top})
+	case requests.CropPagesBoxArtBox:
+		_, err = p.FPDFPage_SetArtBox(&requests.FPDFPage_SetArtBox{Page: page, Left: left, Bottom: bottom, Right: right, Top: top})
+	default:
+		err = fmt.Errorf("invalid page box %s given", box)
+	}
+
+	return err
+}
+
+// getPageVisibleBox returns the visible area of the page in page coordinates.
+// This is the crop box of the page, or the media box when the page has no
+// crop box, and takes inherited boxes into account.
+func (p *PdfiumImplementation) getPageVisibleBox(page requests.Page) (float32, float32, float32, float32, error) {
+	width, err := p.FPDF_GetPageWidth(&requests.FPDF_GetPageWidth{
+		Page: page,
+	})
+	if err != nil {
+		return 0, 0, 0, 0, err
+	}
+
+	height, err := p.FPDF_GetPageHeight(&requests.FPDF_GetPageHeight{
+		Page: page,
+	})
+	if err != nil {
+		return 0, 0, 0, 0, err
+	}
+
+	// Device coordinates are integers, so we use a virtual device of 100
+	// pixels per point to keep enough precision.
+	sizeX := int(math.Round(width.Width * 100))
+	sizeY := int(math.Round(height.Height * 100))
+
+	return p.deviceRectToPage(page, sizeX, sizeY, 0, 0, sizeX, sizeY)
+}
+
+// deviceRectToPage converts a rectangle on a rendered page of the given size
+// to page coordinates, this takes care of the crop box offset and the page
+// rotation.
+func (p *PdfiumImplementation) deviceRectToPage(page requests.Page, sizeX, sizeY, x1, y1, x2, y2 int) (float32, float32, float32, float32, error) {
+	corners := [][2]int{{x1, y1}, {x2, y2}}
+	pageX := make([]float64, len(corners))
+	pageY := make([]float64, len(corners))
+	for i := range corners {
+		point, err := p.FPDF_DeviceToPage(&requests.FPDF_DeviceToPage{
+			Page:    page,
+			StartX:  0,
+			StartY:  0,
+			SizeX:   sizeX,
+			SizeY:   sizeY,
+			Rotate:  enums.FPDF_PAGE_ROTATION_NONE,
+			DeviceX: corners[i][0],
+			DeviceY: corners[i][1],
+		})
+		if err != nil {
+			return 0, 0, 0, 0, err
+		}
+
+		pageX[i] = point.PageX
+		pageY[i] = point.PageY
+	}
+
+	return float32(math.Min(pageX[0], pageX[1])), float32(math.Min(pageY[0], pageY[1])), float32(math.Max(pageX[0], pageX[1])), float32(math.Max(pageY[0], pageY[1])), nil
+}
+
+// getPageObjectsContentBounds returns the combined bounds of all the visible
+// page objects of a page, in points.
+func (p *PdfiumImplementation) getPageObjectsContentBounds(page requests.Page) (float32, float32, float32, float32, bool, error) {
+	objectCount, err := p.FPDFPage_CountObjects(&requests.FPDFPage_CountObjects{
+		Page: page,
+	})
+	if err != nil {
+		return 0, 0, 0, 0, false, err
+	}
+
+	found := false
+	var left, bottom, right, top float32
+	for i := 0; i < objectCount.Count; i++ {
+		pageObject, err := p.FPDFPage_GetObject(&requests.FPDFPage_GetObject{
+			Page:  page,
+			Index: i,
+		})
+		if err != nil {
+			return 0, 0, 0, 0, false, err
+		}
+
+		objectType, err := p.FPDFPageObj_GetType(&requests.FPDFPageObj_GetType{
+			PageObject: pageObject.PageObject,
+		})
+		if err != nil {
+			return 0, 0, 0, 0, false, err
+		}
+
+		// Text that is not rendered (like OCR layers) is not visible content.
+		if objectType.Type == enums.FPDF_PAGEOBJ_TEXT {
+			renderMode, err := p.FPDFTextObj_GetTextRenderMode(&requests.FPDFTextObj_GetTextRenderMode{
+				PageObject: pageObject.PageObject,
+			})
+			if err != nil {
+				return 0, 0, 0, 0, false, err
+			}
+
+			if renderMode.TextRenderMode == enums.FPDF_TEXTRENDERMODE_INVISIBLE {
+				continue
+			}
+		}
+
+		bounds, err := p.FPDFPageObj_GetBounds(&requests.FPDFPageObj_GetBounds{
+			PageObject: pageObject.PageObject,
+		})
+		if err != nil {
+			// Objects without bounds can't contribute to the content bounds.
+			continue
+		}
+
+		if bounds.Right <= bounds.Left && bounds.Top <= bounds.Bottom {
+			continue
+		}
+
+		if !found {
+			left, bottom, right, top = bounds.Left, bounds.Bottom, bounds.Right, bounds.Top
+			found = true
+			continue
+		}
+
+		left = float32(math.Min(float64(left), float64(bounds.Left)))
+		bottom = float32(math.Min(float64(bottom), float64(bounds.Bottom)))
+		right = float32(math.Max(float64(right), float64(bounds.Right)))
+		top = float32(math.Max(float64(top), float64(bounds.Top)))
+	}
+
+	return left, bottom, right, top, found, nil
+}
+
+// getRenderedContentBounds renders the page and returns the bounds of the
+// non-white area of the page, in points.
+func (p *PdfiumImplementation) getRenderedContentBounds(page requests.Page, dpi int, renderFlags enums.FPDF_RENDER_FLAG, tolerance int) (float32, float32, float32, float32, bool, error) {
+	if dpi == 0 {
+		dpi = 72
+	}
+
+	render, err := p.RenderPageInDPI(&requests.RenderPageInDPI{
+		Page:        page,
+		DPI:         dpi,
+		RenderFlags: renderFlags,
+	})
+	if err != nil {
+		return 0, 0, 0, 0, false, err
+	}
+	defer render.Cleanup()
+
+	img := render.Result.Image
+	bounds := img.Bounds()
+	minX, minY, maxX, maxY := bounds.Max.X, bounds.Max.Y, -1, -1
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			offset := img.PixOffset(x, y)
+			r, g, b, a := img.Pix[offset], img.Pix[offset+1], img.Pix[offset+2], img.Pix[offset+3]
+
+			// Transparent pixels and (almost) white pixels are background.
+			if a == 0 || (255-int(r) <= tolerance && 255-int(g) <= tolerance && 255-int(b) <= tolerance) {
+				continue
+			}
+
+			if x < minX {
+				minX = x
+			}
+			if x > maxX {
+				maxX = x
+			}
+			if y < minY {
+				minY = y
+			}
+			if y > maxY {
+				maxY = y
+			}
+		}
+	}
+
+	if maxX == -1 {
+		return 0, 0, 0, 0, false, nil
+	}
+
+	left, bottom, right, top, err := p.deviceRectToPage(page, render.Result.Width, render.Result.Height, minX, minY, maxX+1, maxY+1)
+	if err != nil {
+		return 0, 0, 0, 0, false, err
+	}
+
+	return left, bottom, right, top, true, nil
+}
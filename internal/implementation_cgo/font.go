@@ -4,6 +4,8 @@ package implementation_cgo
 // #include "fpdf_edit.h"
 import "C"
 import (
+	"unsafe"
+
 	"github.com/google/uuid"
 	"github.com/klippa-app/go-pdfium/references"
 )
@@ -19,3 +21,17 @@ func (p *PdfiumImplementation) registerFont(pageObject C.FPDF_FONT) *FontHandle
 
 	return handle
 }
+
+// getFontPointer returns the pointer of the font in PDFium. PDFium shares
+// the font objects within a document, so the pointer identifies a font.
+func (p *PdfiumImplementation) getFontPointer(font references.FPDF_FONT) (uint64, error) {
+	p.Lock()
+	defer p.Unlock()
+
+	fontHandle, err := p.getFontHandle(font)
+	if err != nil {
+		return 0, err
+	}
+
+	return uint64(uintptr(unsafe.Pointer(fontHandle.handle))), nil
+}
@@ -0,0 +1,352 @@
+package implementation_cgo
+
+import (
+	"crypto/sha256"
+	"regexp"
+
+	"github.com/klippa-app/go-pdfium/enums"
+	"github.com/klippa-app/go-pdfium/references"
+	"github.com/klippa-app/go-pdfium/requests"
+	"github.com/klippa-app/go-pdfium/responses"
+)
+
+var fontSubsetTagPattern = regexp.MustCompile(`^[A-Z]{6}\+`)
+
+// fontDescriptorFlagsMask contains the flags of ISO 32000-1:2008, table 123.
+const fontDescriptorFlagsMask = 0x7FFFF
+
+// GetFonts returns the fonts that are used by the text of a document,
+// with the pages they are used on. A font is only returned once, also
+// when it's used on multiple pages.
+// Experimental API.
+func (p *PdfiumImplementation) GetFonts(request *requests.GetFonts) (*responses.GetFonts, error) {
+	pageCount, err := p.FPDF_GetPageCount(&requests.FPDF_GetPageCount{
+		Document: request.Document,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	fonts := []responses.Font{}
+	fontIndexes := map[fontKey]int{}
+	for pageIndex := 0; pageIndex < pageCount.PageCount; pageIndex++ {
+		// A font pointer is only unique while the page is loaded, the font
+		// can be freed with the page and its memory reused by a font of
+		// another page. So fonts of different pages are matched by their
+		// properties and data instead.
+		pageFontIndexes := map[uint64]int{}
+
+		page := requests.Page{
+			ByIndex: &requests.PageByIndex{
+				Document: request.Document,
+				Index:    pageIndex,
+			},
+		}
+
+		textObjects, err := p.getPageTextObjects(page)
+		if err != nil {
+			return nil, err
+		}
+
+		pageFonts := &pageFontNames{
+			page: page,
+		}
+
+		for _, textObject := range textObjects {
+			font, err := p.FPDFTextObj_GetFont(&requests.FPDFTextObj_GetFont{
+				PageObject: textObject,
+			})
+			if err != nil {
+				pageFonts.Close(p)
+				return nil, err
+			}
+
+			fontPointer, err := p.getFontPointer(font.Font)
+			if err != nil {
+				pageFonts.Close(p)
+				return nil, err
+			}
+
+			fontIndex, ok := pageFontIndexes[fontPointer]
+			if !ok {
+				fontInfo, key, err := p.getFontInfo(font.Font, request.IncludeFontData)
+				if err != nil {
+					pageFonts.Close(p)
+					return nil, err
+				}
+
+				fontIndex, ok = fontIndexes[key]
+				if !ok {
+					fonts = append(fonts, *fontInfo)
+					fontIndex = len(fonts) - 1
+					fontIndexes[key] = fontIndex
+				}
+				pageFontIndexes[fontPointer] = fontIndex
+			}
+
+			// PDFium doesn't give the base name of a font object, only of
+			// the characters on the text page, so we look it up by location.
+			if fonts[fontIndex].BaseName == "" {
+				baseName, err := pageFonts.BaseName(p, textObject, fonts[fontIndex].Flags.Flags)
+				if err != nil {
+					pageFonts.Close(p)
+					return nil, err
+				}
+
+				fonts[fontIndex].BaseName = baseName
+				fonts[fontIndex].IsSubset = fontSubsetTagPattern.MatchString(baseName)
+			}
+
+			fontPages := fonts[fontIndex].Pages
+			if len(fontPages) == 0 || fontPages[len(fontPages)-1] != pageIndex {
+				fonts[fontIndex].Pages = append(fontPages, pageIndex)
+			}
+		}
+
+		pageFonts.Close(p)
+	}
+
+	return &responses.GetFonts{
+		Fonts: fonts,
+	}, nil
+}
+
+// getPageTextObjects returns the text objects of a page, including the text
+// objects inside form objects.
+func (p *PdfiumImplementation) getPageTextObjects(page requests.Page) ([]references.FPDF_PAGEOBJECT, error) {
+	objectCount, err := p.FPDFPage_CountObjects(&requests.FPDFPage_CountObjects{
+		Page: page,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	textObjects := []references.FPDF_PAGEOBJECT{}
+	for i := 0; i < objectCount.Count; i++ {
+		object, err := p.FPDFPage_GetObject(&requests.FPDFPage_GetObject{
+			Page:  page,
+			Index: i,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		textObjects, err = p.appendTextObjects(textObjects, object.PageObject)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return textObjects, nil
+}
+
+func (p *PdfiumImplementation) appendTextObjects(textObjects []references.FPDF_PAGEOBJECT, pageObject references.FPDF_PAGEOBJECT) ([]references.FPDF_PAGEOBJECT, error) {
+	objectType, err := p.FPDFPageObj_GetType(&requests.FPDFPageObj_GetType{
+		PageObject: pageObject,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	switch objectType.Type {
+	case enums.FPDF_PAGEOBJ_TEXT:
+		textObjects = append(textObjects, pageObject)
+	case enums.FPDF_PAGEOBJ_FORM:
+		objectCount, err := p.FPDFFormObj_CountObjects(&requests.FPDFFormObj_CountObjects{
+			PageObject: pageObject,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		for i := 0; i < objectCount.Count; i++ {
+			object, err := p.FPDFFormObj_GetObject(&requests.FPDFFormObj_GetObject{
+				PageObject: pageObject,
+				Index:      uint64(i),
+			})
+			if err != nil {
+				return nil, err
+			}
+
+			textObjects, err = p.appendTextObjects(textObjects, object.PageObject)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return textObjects, nil
+}
+
+// fontKey identifies a font in the document, also when every page has its
+// own copy of the font.
+type fontKey struct {
+	name       string
+	isEmbedded bool
+	flags      uint32
+	weight     int
+	dataHash   [sha256.Size]byte // The hash of the font program of embedded fonts.
+}
+
+// getFontInfo returns the information of a font and the key to find the
+// same font on other pages.
+func (p *PdfiumImplementation) getFontInfo(font references.FPDF_FONT, includeFontData bool) (*responses.Font, fontKey, error) {
+	fontName, err := p.FPDFFont_GetFontName(&requests.FPDFFont_GetFontName{
+		Font: font,
+	})
+	if err != nil {
+		return nil, fontKey{}, err
+	}
+
+	isEmbedded, err := p.FPDFFont_GetIsEmbedded(&requests.FPDFFont_GetIsEmbedded{
+		Font: font,
+	})
+	if err != nil {
+		return nil, fontKey{}, err
+	}
+
+	flags, err := p.FPDFFont_GetFlags(&requests.FPDFFont_GetFlags{
+		Font: font,
+	})
+	if err != nil {
+		return nil, fontKey{}, err
+	}
+
+	weight, err := p.FPDFFont_GetWeight(&requests.FPDFFont_GetWeight{
+		Font: font,
+	})
+	if err != nil {
+		return nil, fontKey{}, err
+	}
+
+	fontInfo := &responses.Font{
+		Name:       fontName.FontName,
+		IsEmbedded: isEmbedded.IsEmbedded,
+		Flags:      *flags,
+		Weight:     weight.Weight,
+		Pages:      []int{},
+	}
+
+	key := fontKey{
+		name:       fontName.FontName,
+		isEmbedded: isEmbedded.IsEmbedded,
+		flags:      flags.Flags,
+		weight:     weight.Weight,
+	}
+
+	// For fonts that are not embedded PDFium returns the substitution font.
+	// Embedded fonts without font program, like Type 3 fonts, are only
+	// matched by their properties, unless the font data is requested.
+	if isEmbedded.IsEmbedded {
+		fontData, err := p.FPDFFont_GetFontData(&requests.FPDFFont_GetFontData{
+			Font: font,
+		})
+		if err == nil {
+			key.dataHash = sha256.Sum256(fontData.FontData)
+			if includeFontData {
+				fontInfo.FontData = fontData.FontData
+			}
+		} else if includeFontData {
+			return nil, fontKey{}, err
+		}
+	}
+
+	return fontInfo, key, nil
+}
+
+// pageFontNames finds the base font names of the text objects on a page by
+// the characters of the text page, the text page is loaded when needed.
+type pageFontNames struct {
+	page     requests.Page
+	textPage references.FPDF_TEXTPAGE
+	chars    []responses.FPDFText_GetCharBox
+}
+
+func (f *pageFontNames) load(p *PdfiumImplementation) error {
+	textPage, err := p.FPDFText_LoadPage(&requests.FPDFText_LoadPage{
+		Page: f.page,
+	})
+	if err != nil {
+		return err
+	}
+
+	f.textPage = textPage.TextPage
+	f.chars = []responses.FPDFText_GetCharBox{}
+
+	charCount, err := p.FPDFText_CountChars(&requests.FPDFText_CountChars{
+		TextPage: f.textPage,
+	})
+	if err != nil {
+		return err
+	}
+
+	for i := 0; i < charCount.Count; i++ {
+		charBox, err := p.FPDFText_GetCharBox(&requests.FPDFText_GetCharBox{
+			TextPage: f.textPage,
+			Index:    i,
+		})
+
+		// Generated characters, like spaces and new lines, have no box.
+		if err != nil {
+			continue
+		}
+
+		f.chars = append(f.chars, *charBox)
+	}
+
+	return nil
+}
+
+// BaseName returns the base font name of the first character that is
+// located within the bounds of the text object and has the same font flags.
+func (f *pageFontNames) BaseName(p *PdfiumImplementation, textObject references.FPDF_PAGEOBJECT, flags uint32) (string, error) {
+	if f.textPage == "" {
+		if err := f.load(p); err != nil {
+			return "", err
+		}
+	}
+
+	bounds, err := p.FPDFPageObj_GetBounds(&requests.FPDFPageObj_GetBounds{
+		PageObject: textObject,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	for _, char := range f.chars {
+		x := (char.Left + char.Right) / 2
+		y := (char.Bottom + char.Top) / 2
+		if x < float64(bounds.Left) || x > float64(bounds.Right) || y < float64(bounds.Bottom) || y > float64(bounds.Top) {
+			continue
+		}
+
+		fontInfo, err := p.FPDFText_GetFontInfo(&requests.FPDFText_GetFontInfo{
+			TextPage: f.textPage,
+			Index:    char.Index,
+		})
+		if err != nil || fontInfo.FontName == "" {
+			continue
+		}
+
+		// The text page also returns the flags that PDFium uses internally.
+		if uint32(fontInfo.Flags)&fontDescriptorFlagsMask != flags {
+			continue
+		}
+
+		return fontInfo.FontName, nil
+	}
+
+	return "", nil
+}
+
+// Close closes the text page when it has been loaded.
+func (f *pageFontNames) Close(p *PdfiumImplementation) {
+	if f.textPage == "" {
+		return
+	}
+
+	p.FPDFText_ClosePage(&requests.FPDFText_ClosePage{
+		TextPage: f.textPage,
+	})
+	f.textPage = ""
+}
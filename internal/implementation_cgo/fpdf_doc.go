@@ -359,24 +359,34 @@ func (p *PdfiumImplementation) FPDF_GetPageLabel(request *requests.FPDF_GetPageL
 		return nil, err
 	}
 
+	label, err := p.getPageLabel(documentHandle, request.Page)
+	if err != nil {
+		return nil, err
+	}
+
+	return &responses.FPDF_GetPageLabel{
+		Page:  request.Page,
+		Label: label,
+	}, nil
+}
+
+// getPageLabel returns the label of the page with the given index.
+func (p *PdfiumImplementation) getPageLabel(documentHandle *DocumentHandle, page int) (string, error) {
 	// First get the label length.
-	labelSize := C.FPDF_GetPageLabel(documentHandle.handle, C.int(request.Page), C.NULL, 0)
+	labelSize := C.FPDF_GetPageLabel(documentHandle.handle, C.int(page), C.NULL, 0)
 	if labelSize == 0 {
-		return nil, errors.New("Could not get label")
+		return "", errors.New("Could not get label")
 	}
 
 	charData := make([]byte, labelSize)
-	C.FPDF_GetPageLabel(documentHandle.handle, C.int(request.Page), unsafe.Pointer(&charData[0]), C.ulong(len(charData)))
+	C.FPDF_GetPageLabel(documentHandle.handle, C.int(page), unsafe.Pointer(&charData[0]), C.ulong(len(charData)))
 
 	transformedText, err := p.transformUTF16LEToUTF8(charData)
 	if err != nil {
-		return nil, err
+		return "", err
 	}
 
-	return &responses.FPDF_GetPageLabel{
-		Page:  request.Page,
-		Label: transformedText,
-	}, nil
+	return transformedText, nil
 }
 
 // FPDFDest_GetLocationInPage returns the (x, y, zoom) location of dest in the destination page, if the
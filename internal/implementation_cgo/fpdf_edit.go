@@ -71,6 +71,18 @@ func (p *PdfiumImplementation) FPDFPage_Delete(request *requests.FPDFPage_Delete
 		return nil, err
 	}
 
+	// The page and the pages after it get another index, so the current
+	// page can't be reused by index anymore.
+	if documentHandle.currentPage != nil && documentHandle.currentPage.index >= request.PageIndex {
+		documentHandle.currentPage.Close()
+
+		// Cleanup refs.
+		delete(documentHandle.pageRefs, documentHandle.currentPage.nativeRef)
+		delete(p.pageRefs, documentHandle.currentPage.nativeRef)
+
+		documentHandle.currentPage = nil
+	}
+
 	C.FPDFPage_Delete(documentHandle.handle, C.int(request.PageIndex))
 
 	return &responses.FPDFPage_Delete{}, nil
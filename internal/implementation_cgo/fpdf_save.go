@@ -4,6 +4,7 @@ import "C"
 import (
 	"bytes"
 	"errors"
+	"github.com/klippa-app/go-pdfium/internal/pdf_update"
 	"github.com/klippa-app/go-pdfium/requests"
 	"github.com/klippa-app/go-pdfium/responses"
 	"io"
@@ -56,6 +57,7 @@ func (p *PdfiumImplementation) FPDF_SaveAsCopy(request *requests.FPDF_SaveAsCopy
 		FilePath:    request.FilePath,
 		FileWriter:  request.FileWriter,
 		FileVersion: 0,
+		Encryption:  request.Encryption,
 	})
 
 	if err != nil {
@@ -78,6 +80,22 @@ func (p *PdfiumImplementation) FPDF_SaveWithVersion(request *requests.FPDF_SaveW
 		return nil, err
 	}
 
+	// Encryption can't be added with an incremental update, PDFium saves the
+	// document without security and the file is encrypted afterwards.
+	flags := request.Flags
+	if request.Encryption != nil {
+		if flags == requests.SaveFlagIncremental {
+			return nil, errors.New("encryption can't be combined with incremental saving")
+		}
+
+		err = pdf_update.ValidateEncryption(*request.Encryption)
+		if err != nil {
+			return nil, err
+		}
+
+		flags = requests.SaveFlagRemoveSecurity
+	}
+
 	writer := C.FPDF_FILEWRITE{}
 	writer.version = 1
 
@@ -86,18 +104,35 @@ func (p *PdfiumImplementation) FPDF_SaveWithVersion(request *requests.FPDF_SaveW
 
 	var fileBuf *bytes.Buffer
 	var curFile *os.File
+	var outputWriter io.Writer
 	if request.FileWriter != nil {
-		currentWriter = request.FileWriter
+		outputWriter = request.FileWriter
 	} else if request.FilePath != nil {
 		newFile, err := os.Create(*request.FilePath)
 		if err != nil {
 			return nil, err
 		}
-		currentWriter = newFile
+		outputWriter = newFile
 		curFile = newFile
 	} else {
 		fileBuf = &bytes.Buffer{}
-		currentWriter = fileBuf
+		outputWriter = fileBuf
+	}
+
+	// Unused objects can't be removed when saving incrementally.
+	changes := documentHandle.changes
+	if flags == requests.SaveFlagIncremental {
+		changes = changes.Incremental()
+	}
+
+	// When there are changes that PDFium has no API for, or when the file is
+	// encrypted, PDFium writes into a buffer first, the changes and the
+	// encryption are then applied on the way to the output.
+	var pdfiumBuf *bytes.Buffer
+	currentWriter = outputWriter
+	if !changes.IsEmpty() || request.Encryption != nil {
+		pdfiumBuf = &bytes.Buffer{}
+		currentWriter = pdfiumBuf
 	}
 
 	defer func() {
@@ -111,15 +146,36 @@ func (p *PdfiumImplementation) FPDF_SaveWithVersion(request *requests.FPDF_SaveW
 
 	var success C.int
 	if request.FileVersion == 0 {
-		success = C.FPDF_SaveAsCopy(documentHandle.handle, &writer, C.ulong(request.Flags))
+		success = C.FPDF_SaveAsCopy(documentHandle.handle, &writer, C.ulong(flags))
 	} else {
-		success = C.FPDF_SaveWithVersion(documentHandle.handle, &writer, C.ulong(request.Flags), C.int(request.FileVersion))
+		success = C.FPDF_SaveWithVersion(documentHandle.handle, &writer, C.ulong(flags), C.int(request.FileVersion))
 	}
 
 	if int(success) == 0 {
 		return nil, errors.New("save of document failed")
 	}
 
+	if pdfiumBuf != nil {
+		if request.Encryption == nil {
+			err = changes.Apply(pdfiumBuf.Bytes(), outputWriter)
+		} else {
+			output := pdfiumBuf.Bytes()
+			if !changes.IsEmpty() {
+				changedBuf := &bytes.Buffer{}
+				err = changes.Apply(output, changedBuf)
+				if err != nil {
+					return nil, err
+				}
+				output = changedBuf.Bytes()
+			}
+
+			err = pdf_update.Encrypt(output, outputWriter, *request.Encryption)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	resp := &responses.FPDF_SaveWithVersion{}
 	if request.FilePath != nil {
 		resp.FilePath = request.FilePath
@@ -10,6 +10,7 @@ import (
 	"errors"
 	"unsafe"
 
+	"github.com/klippa-app/go-pdfium/internal/pdf_update"
 	"github.com/klippa-app/go-pdfium/references"
 )
 
@@ -19,6 +20,7 @@ type DocumentHandle struct {
 	data          *[]byte                  // Keep a reference to the data otherwise weird stuff happens
 	nativeRef     references.FPDF_DOCUMENT // A string that is our reference inside the process. We need this to close the documents in DestroyLibrary.
 	fileHandleRef *string
+	changes       *pdf_update.Changes // Changes that PDFium has no API for, these are applied when saving the document.
 
 	// lookup tables keeps track of the opened handles for this instance.
 	// we need this for handle lookups and in case of closing the document
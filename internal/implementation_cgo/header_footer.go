@@ -0,0 +1,389 @@
+package implementation_cgo
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/klippa-app/go-pdfium/enums"
+	"github.com/klippa-app/go-pdfium/requests"
+	"github.com/klippa-app/go-pdfium/responses"
+	"github.com/klippa-app/go-pdfium/structs"
+)
+
+// AddHeaderFooter adds text objects with the given templates to the header
+// and footer positions of the pages of the given documents. The running
+// number continues over all the documents, so that it can be used for Bates
+// numbering.
+func (p *PdfiumImplementation) AddHeaderFooter(request *requests.AddHeaderFooter) (*responses.AddHeaderFooter, error) {
+	if len(request.Documents) == 0 {
+		return nil, errors.New("no documents given")
+	}
+
+	if len(request.Texts) == 0 {
+		return nil, errors.New("no texts given")
+	}
+
+	templates := make([]headerFooterTemplate, len(request.Texts))
+	for i := range request.Texts {
+		if !isValidHeaderFooterPosition(request.Texts[i].Position) {
+			return nil, fmt.Errorf("invalid position %s given", request.Texts[i].Position)
+		}
+
+		template, err := parseHeaderFooterTemplate(request.Texts[i].Template)
+		if err != nil {
+			return nil, err
+		}
+		templates[i] = template
+	}
+
+	// Resolve all the page ranges first, so that we don't change any
+	// document when one of the ranges is invalid.
+	documentPages := make([][]int, len(request.Documents))
+	documentPageCounts := make([]int, len(request.Documents))
+	for i := range request.Documents {
+		pageCount, err := p.FPDF_GetPageCount(&requests.FPDF_GetPageCount{
+			Document: request.Documents[i].Document,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		pages, err := parsePageRange(request.Documents[i].PageRange, pageCount.PageCount)
+		if err != nil {
+			return nil, err
+		}
+
+		documentPages[i] = pages
+		documentPageCounts[i] = pageCount.PageCount
+	}
+
+	number := request.Start
+	if number == 0 {
+		number = 1
+	}
+
+	date := request.Date
+	if date.IsZero() {
+		date = time.Now()
+	}
+
+	resp := &responses.AddHeaderFooter{
+		Documents: []responses.AddHeaderFooterDocument{},
+	}
+
+	for i := range request.Documents {
+		documentResult := responses.AddHeaderFooterDocument{
+			FirstNumber: number,
+			LastNumber:  number - 1,
+		}
+
+		for _, pageIndex := range documentPages[i] {
+			page := requests.Page{
+				ByIndex: &requests.PageByIndex{
+					Document: request.Documents[i].Document,
+					Index:    pageIndex,
+				},
+			}
+
+			values := headerFooterValues{
+				Prefix: request.Prefix,
+				Number: number,
+				Page:   pageIndex + 1,
+				Total:  documentPageCounts[i],
+				Date:   date,
+			}
+
+			err := p.addHeaderFooterToPage(page, request.Texts, templates, values)
+			if err != nil {
+				return nil, err
+			}
+
+			documentResult.Pages++
+			documentResult.LastNumber = number
+			number++
+		}
+
+		resp.Documents = append(resp.Documents, documentResult)
+	}
+
+	resp.NextNumber = number
+
+	return resp, nil
+}
+
+func (p *PdfiumImplementation) addHeaderFooterToPage(page requests.Page, texts []requests.HeaderFooterText, templates []headerFooterTemplate, values headerFooterValues) error {
+	left, bottom, right, top, err := p.getPageVisibleBox(page)
+	if err != nil {
+		return err
+	}
+
+	rotation, err := p.FPDFPage_GetRotation(&requests.FPDFPage_GetRotation{
+		Page: page,
+	})
+	if err != nil {
+		return err
+	}
+
+	width := right - left
+	height := top - bottom
+
+	// The size of the page as it is displayed.
+	displayWidth, displayHeight := width, height
+	if rotation.PageRotation == enums.FPDF_PAGE_ROTATION_90_CW || rotation.PageRotation == enums.FPDF_PAGE_ROTATION_270_CW {
+		displayWidth, displayHeight = height, width
+	}
+
+	// The matrix that converts display coordinates into page coordinates,
+	// so that the texts are upright when the page is displayed.
+	var displayMatrix structs.FPDF_FS_MATRIX
+	switch rotation.PageRotation {
+	case enums.FPDF_PAGE_ROTATION_90_CW:
+		displayMatrix = structs.FPDF_FS_MATRIX{A: 0, B: 1, C: -1, D: 0, E: left + width, F: bottom}
+	case enums.FPDF_PAGE_ROTATION_180_CW:
+		displayMatrix = structs.FPDF_FS_MATRIX{A: -1, B: 0, C: 0, D: -1, E: left + width, F: bottom + height}
+	case enums.FPDF_PAGE_ROTATION_270_CW:
+		displayMatrix = structs.FPDF_FS_MATRIX{A: 0, B: -1, C: 1, D: 0, E: left, F: bottom + height}
+	default:
+		displayMatrix = structs.FPDF_FS_MATRIX{A: 1, B: 0, C: 0, D: 1, E: left, F: bottom}
+	}
+
+	for i := range texts {
+		text := templates[i].render(values)
+		if text == "" {
+			continue
+		}
+
+		font := texts[i].Font
+		if font == "" {
+			font = "Helvetica"
+		}
+
+		fontSize := texts[i].FontSize
+		if fontSize == 0 {
+			fontSize = 10
+		}
+
+		margin := texts[i].Margin
+		if margin == 0 {
+			margin = 36
+		}
+
+		color := structs.FPDF_COLOR{R: 0, G: 0, B: 0, A: 255}
+		if texts[i].Color != nil {
+			color = *texts[i].Color
+		}
+
+		textObject, err := p.FPDFPageObj_NewTextObj(&requests.FPDFPageObj_NewTextObj{
+			Document: page.ByIndex.Document,
+			Font:     font,
+			FontSize: fontSize,
+		})
+		if err != nil {
+			return err
+		}
+
+		_, err = p.FPDFText_SetText(&requests.FPDFText_SetText{
+			PageObject: textObject.PageObject,
+			Text:       text,
+		})
+		if err != nil {
+			return err
+		}
+
+		_, err = p.FPDFPageObj_SetFillColor(&requests.FPDFPageObj_SetFillColor{
+			PageObject: textObject.PageObject,
+			FillColor:  color,
+		})
+		if err != nil {
+			return err
+		}
+
+		bounds, err := p.FPDFPageObj_GetBounds(&requests.FPDFPageObj_GetBounds{
+			PageObject: textObject.PageObject,
+		})
+		if err != nil {
+			return err
+		}
+
+		// Calculate the position of the baseline in display coordinates.
+		var x, y float32
+		switch texts[i].Position {
+		case requests.HeaderFooterPositionTopLeft, requests.HeaderFooterPositionBottomLeft:
+			x = margin - bounds.Left
+		case requests.HeaderFooterPositionTopCenter, requests.HeaderFooterPositionBottomCenter:
+			x = (displayWidth-(bounds.Right-bounds.Left))/2 - bounds.Left
+		case requests.HeaderFooterPositionTopRight, requests.HeaderFooterPositionBottomRight:
+			x = displayWidth - margin - bounds.Right
+		}
+
+		switch texts[i].Position {
+		case requests.HeaderFooterPositionTopLeft, requests.HeaderFooterPositionTopCenter, requests.HeaderFooterPositionTopRight:
+			y = displayHeight - margin - fontSize
+		default:
+			y = margin
+		}
+
+		_, err = p.FPDFPageObj_Transform(&requests.FPDFPageObj_Transform{
+			PageObject: textObject.PageObject,
+			Transform: structs.FPDF_FS_MATRIX{
+				A: displayMatrix.A,
+				B: displayMatrix.B,
+				C: displayMatrix.C,
+				D: displayMatrix.D,
+				E: displayMatrix.A*x + displayMatrix.C*y + displayMatrix.E,
+				F: displayMatrix.B*x + displayMatrix.D*y + displayMatrix.F,
+			},
+		})
+		if err != nil {
+			return err
+		}
+
+		_, err = p.FPDFPage_InsertObject(&requests.FPDFPage_InsertObject{
+			Page:       page,
+			PageObject: textObject.PageObject,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err = p.FPDFPage_GenerateContent(&requests.FPDFPage_GenerateContent{
+		Page: page,
+	})
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func isValidHeaderFooterPosition(position requests.HeaderFooterPosition) bool {
+	switch position {
+	case requests.HeaderFooterPositionTopLeft,
+		requests.HeaderFooterPositionTopCenter,
+		requests.HeaderFooterPositionTopRight,
+		requests.HeaderFooterPositionBottomLeft,
+		requests.HeaderFooterPositionBottomCenter,
+		requests.HeaderFooterPositionBottomRight:
+		return true
+	}
+	return false
+}
+
+// headerFooterValues contains the values of the placeholders for one page.
+type headerFooterValues struct {
+	Prefix string
+	Number int
+	Page   int
+	Total  int
+	Date   time.Time
+}
+
+type headerFooterTemplatePart struct {
+	Literal     string
+	Placeholder string
+	Format      string
+}
+
+type headerFooterTemplate []headerFooterTemplatePart
+
+var headerFooterNumberFormat = regexp.MustCompile(`^0?[0-9]+$`)
+
+// parseHeaderFooterTemplate parses a template into literal and placeholder
+// parts, so that we can validate it before changing any page.
+func parseHeaderFooterTemplate(template string) (headerFooterTemplate, error) {
+	parts := headerFooterTemplate{}
+	literal := strings.Builder{}
+	for i := 0; i < len(template); i++ {
+		switch template[i] {
+		case '{':
+			if i+1 < len(template) && template[i+1] == '{' {
+				literal.WriteByte('{')
+				i++
+				continue
+			}
+
+			end := strings.IndexByte(template[i:], '}')
+			if end == -1 {
+				return nil, fmt.Errorf("unclosed placeholder in template %q", template)
+			}
+
+			if literal.Len() > 0 {
+				parts = append(parts, headerFooterTemplatePart{Literal: literal.String()})
+				literal.Reset()
+			}
+
+			placeholder := template[i+1 : i+end]
+			format := ""
+			if colon := strings.IndexByte(placeholder, ':'); colon != -1 {
+				placeholder, format = placeholder[:colon], placeholder[colon+1:]
+			}
+
+			switch placeholder {
+			case "prefix":
+				if format != "" {
+					return nil, fmt.Errorf("placeholder {%s} in template %q does not support a format", placeholder, template)
+				}
+			case "n", "page", "total":
+				if format != "" && !headerFooterNumberFormat.MatchString(format) {
+					return nil, fmt.Errorf("invalid number format %q in template %q", format, template)
+				}
+			case "date":
+				if format == "" {
+					format = "2006-01-02"
+				}
+			default:
+				return nil, fmt.Errorf("unknown placeholder {%s} in template %q", placeholder, template)
+			}
+
+			parts = append(parts, headerFooterTemplatePart{Placeholder: placeholder, Format: format})
+			i += end
+		case '}':
+			if i+1 < len(template) && template[i+1] == '}' {
+				i++
+			}
+			literal.WriteByte('}')
+		default:
+			literal.WriteByte(template[i])
+		}
+	}
+
+	if literal.Len() > 0 {
+		parts = append(parts, headerFooterTemplatePart{Literal: literal.String()})
+	}
+
+	return parts, nil
+}
+
+// render fills in the placeholders of the template with the given values.
+func (t headerFooterTemplate) render(values headerFooterValues) string {
+	result := strings.Builder{}
+	for _, part := range t {
+		switch part.Placeholder {
+		case "":
+			result.WriteString(part.Literal)
+		case "prefix":
+			result.WriteString(values.Prefix)
+		case "n":
+			result.WriteString(formatHeaderFooterNumber(values.Number, part.Format))
+		case "page":
+			result.WriteString(formatHeaderFooterNumber(values.Page, part.Format))
+		case "total":
+			result.WriteString(formatHeaderFooterNumber(values.Total, part.Format))
+		case "date":
+			result.WriteString(values.Date.Format(part.Format))
+		}
+	}
+	return result.String()
+}
+
+func formatHeaderFooterNumber(number int, format string) string {
+	if format == "" {
+		return strconv.Itoa(number)
+	}
+	return fmt.Sprintf("%"+format+"d", number)
+}
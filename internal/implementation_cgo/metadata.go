@@ -9,6 +9,7 @@ import (
 	"errors"
 	"unsafe"
 
+	"github.com/klippa-app/go-pdfium/internal/pdf_update"
 	"github.com/klippa-app/go-pdfium/requests"
 	"github.com/klippa-app/go-pdfium/responses"
 )
@@ -57,6 +58,11 @@ func (p *PdfiumImplementation) GetMetaData(request *requests.GetMetaData) (*resp
 			return nil, err
 		}
 
+		// Values that have been set but are not saved yet.
+		if pendingValue, ok := documentHandle.changes.GetInfo(tags[i]); ok {
+			result = pendingValue
+		}
+
 		results = append(results, responses.GetMetaDataTag{
 			Tag:   tags[i],
 			Value: result,
@@ -67,3 +73,32 @@ func (p *PdfiumImplementation) GetMetaData(request *requests.GetMetaData) (*resp
 		Tags: results,
 	}, nil
 }
+
+// SetMetaData sets metadata values of the document. PDFium has no API for
+// this, the values are written into the Info dictionary when the document
+// is saved with FPDF_SaveAsCopy or FPDF_SaveWithVersion.
+func (p *PdfiumImplementation) SetMetaData(request *requests.SetMetaData) (*responses.SetMetaData, error) {
+	p.Lock()
+	defer p.Unlock()
+
+	documentHandle, err := p.getDocumentHandle(request.Document)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range request.Tags {
+		if request.Tags[i].Tag == "" {
+			return nil, errors.New("tag not given")
+		}
+	}
+
+	if documentHandle.changes == nil {
+		documentHandle.changes = &pdf_update.Changes{}
+	}
+
+	for i := range request.Tags {
+		documentHandle.changes.SetInfo(request.Tags[i].Tag, request.Tags[i].Value)
+	}
+
+	return &responses.SetMetaData{}, nil
+}
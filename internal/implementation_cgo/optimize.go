@@ -0,0 +1,537 @@
+package implementation_cgo
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/jpeg"
+	"math"
+
+	"github.com/klippa-app/go-pdfium/enums"
+	"github.com/klippa-app/go-pdfium/internal/image/image_jpeg"
+	"github.com/klippa-app/go-pdfium/internal/pdf_update"
+	"github.com/klippa-app/go-pdfium/references"
+	"github.com/klippa-app/go-pdfium/requests"
+	"github.com/klippa-app/go-pdfium/responses"
+)
+
+// OptimizeDocument reduces the file size of a document by downsampling
+// images with a high resolution, and optionally by removing thumbnails and
+// embedded files. The replaced images and the other objects that are not
+// used anymore are only removed from the file when it is saved by
+// OptimizeDocument.
+// Experimental API.
+func (p *PdfiumImplementation) OptimizeDocument(request *requests.OptimizeDocument) (*responses.OptimizeDocument, error) {
+	// Don't lock here, the methods that we call do that for us.
+	pageCount, err := p.FPDF_GetPageCount(&requests.FPDF_GetPageCount{
+		Document: request.Document,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	maxDPI := request.MaxDPI
+	if maxDPI == 0 {
+		maxDPI = 150
+	}
+
+	if maxDPI < 0 {
+		return nil, errors.New("no valid maximum DPI given")
+	}
+
+	quality := request.JpegQuality
+	if quality == 0 {
+		quality = 75
+	}
+
+	if quality < 1 || quality > 100 {
+		return nil, fmt.Errorf("invalid JPEG quality %d given", quality)
+	}
+
+	resp := &responses.OptimizeDocument{
+		Images: []responses.OptimizeDocumentImage{},
+	}
+
+	// PDFium shares an image between the pages that use it, so after
+	// replacing it, the other pages see the downsampled image. The data of
+	// the downsampled images is kept to recognize them.
+	downsampledImages := map[string]bool{}
+	for pageIndex := 0; pageIndex < pageCount.PageCount; pageIndex++ {
+		page := requests.Page{
+			ByIndex: &requests.PageByIndex{
+				Document: request.Document,
+				Index:    pageIndex,
+			},
+		}
+
+		images, changed, err := p.optimizePageImages(request.Document, page, maxDPI, quality, downsampledImages)
+		if err != nil {
+			return nil, err
+		}
+
+		// The replaced images are only referenced from the page after
+		// generating its content stream.
+		if changed {
+			_, err = p.FPDFPage_GenerateContent(&requests.FPDFPage_GenerateContent{
+				Page: page,
+			})
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		for i := range images {
+			images[i].Page = pageIndex
+			resp.ImageBytes += images[i].OriginalSize - images[i].Size
+		}
+		resp.Images = append(resp.Images, images...)
+
+		if request.RemoveThumbnails {
+			thumbnail, err := p.FPDFPage_GetRawThumbnailData(&requests.FPDFPage_GetRawThumbnailData{
+				Page: page,
+			})
+			if err != nil {
+				return nil, err
+			}
+
+			resp.ThumbnailBytes += len(thumbnail.RawThumbnail)
+		}
+	}
+
+	if request.RemoveAttachments {
+		attachmentBytes, err := p.removeAttachments(request.Document)
+		if err != nil {
+			return nil, err
+		}
+
+		resp.AttachmentBytes = attachmentBytes
+	}
+
+	resp.BytesSaved = resp.ImageBytes + resp.ThumbnailBytes + resp.AttachmentBytes
+
+	if request.RemoveThumbnails {
+		p.Lock()
+		documentHandle, err := p.getDocumentHandle(request.Document)
+		if err != nil {
+			p.Unlock()
+			return nil, err
+		}
+
+		if documentHandle.changes == nil {
+			documentHandle.changes = &pdf_update.Changes{}
+		}
+
+		documentHandle.changes.RemoveThumbnails()
+		p.Unlock()
+	}
+
+	if request.Save {
+		savedDocument, err := p.saveOptimizedDocument(request.Document, request.FilePath)
+		if err != nil {
+			return nil, err
+		}
+
+		resp.FileBytes = savedDocument.FileBytes
+		resp.FilePath = savedDocument.FilePath
+	}
+
+	return resp, nil
+}
+
+// saveOptimizedDocument saves the document without the objects that are
+// not used anymore, like the replaced images. Only this save removes them,
+// because that rewrites the whole file.
+func (p *PdfiumImplementation) saveOptimizedDocument(document references.FPDF_DOCUMENT, filePath *string) (*responses.FPDF_SaveAsCopy, error) {
+	p.Lock()
+	documentHandle, err := p.getDocumentHandle(document)
+	if err != nil {
+		p.Unlock()
+		return nil, err
+	}
+
+	changes := documentHandle.changes
+	documentHandle.changes = changes.WithoutUnusedObjects()
+	p.Unlock()
+
+	defer func() {
+		p.Lock()
+		documentHandle.changes = changes
+		p.Unlock()
+	}()
+
+	return p.FPDF_SaveAsCopy(&requests.FPDF_SaveAsCopy{
+		Document: document,
+		FilePath: filePath,
+	})
+}
+
+// removeAttachments removes the embedded files of the document and returns
+// their size.
+func (p *PdfiumImplementation) removeAttachments(document references.FPDF_DOCUMENT) (int, error) {
+	attachmentCount, err := p.FPDFDoc_GetAttachmentCount(&requests.FPDFDoc_GetAttachmentCount{
+		Document: document,
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	size := 0
+	// Remove from the back so the indexes stay valid.
+	for i := attachmentCount.AttachmentCount - 1; i >= 0; i-- {
+		attachment, err := p.FPDFDoc_GetAttachment(&requests.FPDFDoc_GetAttachment{
+			Document: document,
+			Index:    i,
+		})
+		if err != nil {
+			return 0, err
+		}
+
+		file, err := p.FPDFAttachment_GetFile(&requests.FPDFAttachment_GetFile{
+			Attachment: attachment.Attachment,
+		})
+		if err != nil {
+			return 0, err
+		}
+		size += len(file.Contents)
+
+		_, err = p.FPDFDoc_DeleteAttachment(&requests.FPDFDoc_DeleteAttachment{
+			Document: document,
+			Index:    i,
+		})
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	return size, nil
+}
+
+// optimizePageImages downsamples the images of a page, it returns the
+// downsampled images and whether the page has to be regenerated.
+func (p *PdfiumImplementation) optimizePageImages(document references.FPDF_DOCUMENT, page requests.Page, maxDPI float64, quality int, downsampledImages map[string]bool) ([]responses.OptimizeDocumentImage, bool, error) {
+	objectCount, err := p.FPDFPage_CountObjects(&requests.FPDFPage_CountObjects{
+		Page: page,
+	})
+	if err != nil {
+		return nil, false, err
+	}
+
+	images := []responses.OptimizeDocumentImage{}
+	changed := false
+	for i := 0; i < objectCount.Count; i++ {
+		object, err := p.FPDFPage_GetObject(&requests.FPDFPage_GetObject{
+			Page:  page,
+			Index: i,
+		})
+		if err != nil {
+			return nil, false, err
+		}
+
+		objectType, err := p.FPDFPageObj_GetType(&requests.FPDFPageObj_GetType{
+			PageObject: object.PageObject,
+		})
+		if err != nil {
+			return nil, false, err
+		}
+
+		if objectType.Type != enums.FPDF_PAGEOBJ_IMAGE {
+			continue
+		}
+
+		rawData, err := p.FPDFImageObj_GetImageDataRaw(&requests.FPDFImageObj_GetImageDataRaw{
+			ImageObject: object.PageObject,
+		})
+		if err != nil {
+			return nil, false, err
+		}
+
+		// The image was downsampled on an earlier page, the object only has
+		// to be marked as changed, by setting its matrix, to reference the
+		// downsampled image when the content is generated.
+		if downsampledImages[string(rawData.Data)] {
+			matrix, err := p.FPDFPageObj_GetMatrix(&requests.FPDFPageObj_GetMatrix{
+				PageObject: object.PageObject,
+			})
+			if err != nil {
+				return nil, false, err
+			}
+
+			_, err = p.FPDFPageObj_SetMatrix(&requests.FPDFPageObj_SetMatrix{
+				PageObject: object.PageObject,
+				Transform:  matrix.Matrix,
+			})
+			if err != nil {
+				return nil, false, err
+			}
+
+			changed = true
+			continue
+		}
+
+		result, jpegData, err := p.optimizeImage(document, page, object.PageObject, rawData.Data, maxDPI, quality)
+		if err != nil {
+			return nil, false, err
+		}
+
+		if result != nil {
+			result.Index = i
+			images = append(images, *result)
+			downsampledImages[string(jpegData)] = true
+			changed = true
+		}
+	}
+
+	return images, changed, nil
+}
+
+// optimizeImage downsamples the given image object when its resolution is
+// too high. It returns the downsampled image and its data, or nil when the
+// image has not been changed.
+func (p *PdfiumImplementation) optimizeImage(document references.FPDF_DOCUMENT, page requests.Page, imageObject references.FPDF_PAGEOBJECT, rawData []byte, maxDPI float64, quality int) (*responses.OptimizeDocumentImage, []byte, error) {
+	pixelSize, err := p.FPDFImageObj_GetImagePixelSize(&requests.FPDFImageObj_GetImagePixelSize{
+		ImageObject: imageObject,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	matrix, err := p.FPDFPageObj_GetMatrix(&requests.FPDFPageObj_GetMatrix{
+		PageObject: imageObject,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// The matrix of an image object maps the unit square to the page, so
+	// the length of its vectors is the placed size in points.
+	placedWidth := math.Hypot(float64(matrix.Matrix.A), float64(matrix.Matrix.B)) / 72
+	placedHeight := math.Hypot(float64(matrix.Matrix.C), float64(matrix.Matrix.D)) / 72
+	if pixelSize.Width == 0 || pixelSize.Height == 0 || placedWidth == 0 || placedHeight == 0 {
+		return nil, nil, nil
+	}
+
+	dpi := math.Max(float64(pixelSize.Width)/placedWidth, float64(pixelSize.Height)/placedHeight)
+	if dpi <= maxDPI {
+		return nil, nil, nil
+	}
+
+	newWidth := int(math.Min(math.Ceil(placedWidth*maxDPI), float64(pixelSize.Width)))
+	newHeight := int(math.Min(math.Ceil(placedHeight*maxDPI), float64(pixelSize.Height)))
+
+	metadata, err := p.FPDFImageObj_GetImageMetadata(&requests.FPDFImageObj_GetImageMetadata{
+		ImageObject: imageObject,
+		Page:        page,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Bilevel images, like most scanned text, are stored a lot more
+	// efficiently than JPEG can.
+	if metadata.ImageMetadata.BitsPerPixel <= 1 {
+		return nil, nil, nil
+	}
+
+	// JPEG images can't contain the mask of an image.
+	transparent, err := p.imageHasTransparency(document, page, imageObject)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if transparent {
+		return nil, nil, nil
+	}
+
+	bitmap, err := p.FPDFImageObj_GetBitmap(&requests.FPDFImageObj_GetBitmap{
+		ImageObject: imageObject,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	downsampledImage, err := p.downsampleBitmap(bitmap.Bitmap, newWidth, newHeight)
+	p.FPDFBitmap_Destroy(&requests.FPDFBitmap_Destroy{
+		Bitmap: bitmap.Bitmap,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	jpegData := bytes.Buffer{}
+	err = image_jpeg.Encode(&jpegData, downsampledImage, image_jpeg.Options{
+		Options: &jpeg.Options{
+			Quality: quality,
+		},
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Keep the original image when recompressing doesn't make it smaller.
+	if jpegData.Len() >= len(rawData) {
+		return nil, nil, nil
+	}
+
+	_, err = p.FPDFImageObj_LoadJpegFileInline(&requests.FPDFImageObj_LoadJpegFileInline{
+		Page:        &page,
+		Count:       1,
+		ImageObject: imageObject,
+		FileData:    jpegData.Bytes(),
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return &responses.OptimizeDocumentImage{
+		OriginalWidth:  int(pixelSize.Width),
+		OriginalHeight: int(pixelSize.Height),
+		OriginalDPI:    dpi,
+		OriginalSize:   len(rawData),
+		Width:          newWidth,
+		Height:         newHeight,
+		Size:           jpegData.Len(),
+	}, jpegData.Bytes(), nil
+}
+
+// imageHasTransparency returns whether the image has pixels that are not
+// fully opaque after applying its mask.
+func (p *PdfiumImplementation) imageHasTransparency(document references.FPDF_DOCUMENT, page requests.Page, imageObject references.FPDF_PAGEOBJECT) (bool, error) {
+	renderedBitmap, err := p.FPDFImageObj_GetRenderedBitmap(&requests.FPDFImageObj_GetRenderedBitmap{
+		Document:    document,
+		Page:        page,
+		ImageObject: imageObject,
+	})
+	if err != nil {
+		return false, err
+	}
+
+	defer p.FPDFBitmap_Destroy(&requests.FPDFBitmap_Destroy{
+		Bitmap: renderedBitmap.Bitmap,
+	})
+
+	format, err := p.FPDFBitmap_GetFormat(&requests.FPDFBitmap_GetFormat{
+		Bitmap: renderedBitmap.Bitmap,
+	})
+	if err != nil {
+		return false, err
+	}
+
+	if format.Format != enums.FPDF_BITMAP_FORMAT_BGRA {
+		return false, nil
+	}
+
+	width, height, stride, buffer, err := p.getBitmapData(renderedBitmap.Bitmap)
+	if err != nil {
+		return false, err
+	}
+
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			if buffer[y*stride+x*4+3] != 0xFF {
+				return true, nil
+			}
+		}
+	}
+
+	return false, nil
+}
+
+// getBitmapData returns the size and the buffer of a bitmap.
+func (p *PdfiumImplementation) getBitmapData(bitmap references.FPDF_BITMAP) (int, int, int, []byte, error) {
+	width, err := p.FPDFBitmap_GetWidth(&requests.FPDFBitmap_GetWidth{
+		Bitmap: bitmap,
+	})
+	if err != nil {
+		return 0, 0, 0, nil, err
+	}
+
+	height, err := p.FPDFBitmap_GetHeight(&requests.FPDFBitmap_GetHeight{
+		Bitmap: bitmap,
+	})
+	if err != nil {
+		return 0, 0, 0, nil, err
+	}
+
+	stride, err := p.FPDFBitmap_GetStride(&requests.FPDFBitmap_GetStride{
+		Bitmap: bitmap,
+	})
+	if err != nil {
+		return 0, 0, 0, nil, err
+	}
+
+	// Get the buffer last, it's a view of the memory of the bitmap.
+	buffer, err := p.FPDFBitmap_GetBuffer(&requests.FPDFBitmap_GetBuffer{
+		Bitmap: bitmap,
+	})
+	if err != nil {
+		return 0, 0, 0, nil, err
+	}
+
+	return width.Width, height.Height, stride.Stride, buffer.Buffer, nil
+}
+
+// downsampleBitmap scales the bitmap down to the given size by averaging
+// the pixels that end up in the same pixel.
+func (p *PdfiumImplementation) downsampleBitmap(bitmap references.FPDF_BITMAP, newWidth, newHeight int) (*image.RGBA, error) {
+	format, err := p.FPDFBitmap_GetFormat(&requests.FPDFBitmap_GetFormat{
+		Bitmap: bitmap,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	bytesPerPixel := 0
+	switch format.Format {
+	case enums.FPDF_BITMAP_FORMAT_GRAY:
+		bytesPerPixel = 1
+	case enums.FPDF_BITMAP_FORMAT_BGR:
+		bytesPerPixel = 3
+	case enums.FPDF_BITMAP_FORMAT_BGRX, enums.FPDF_BITMAP_FORMAT_BGRA:
+		bytesPerPixel = 4
+	default:
+		return nil, errors.New("unsupported bitmap format")
+	}
+
+	width, height, stride, buffer, err := p.getBitmapData(bitmap)
+	if err != nil {
+		return nil, err
+	}
+
+	img := image.NewRGBA(image.Rect(0, 0, newWidth, newHeight))
+	for newY := 0; newY < newHeight; newY++ {
+		startY := newY * height / newHeight
+		endY := int(math.Max(float64((newY+1)*height/newHeight), float64(startY+1)))
+
+		for newX := 0; newX < newWidth; newX++ {
+			startX := newX * width / newWidth
+			endX := int(math.Max(float64((newX+1)*width/newWidth), float64(startX+1)))
+
+			var red, green, blue, count int
+			for y := startY; y < endY; y++ {
+				row := buffer[y*stride:]
+				for x := startX; x < endX; x++ {
+					pixel := row[x*bytesPerPixel:]
+					if bytesPerPixel == 1 {
+						red += int(pixel[0])
+						green += int(pixel[0])
+						blue += int(pixel[0])
+					} else {
+						red += int(pixel[2])
+						green += int(pixel[1])
+						blue += int(pixel[0])
+					}
+					count++
+				}
+			}
+
+			offset := img.PixOffset(newX, newY)
+			img.Pix[offset] = uint8(red / count)
+			img.Pix[offset+1] = uint8(green / count)
+			img.Pix[offset+2] = uint8(blue / count)
+			img.Pix[offset+3] = 0xFF
+		}
+	}
+
+	return img, nil
+}
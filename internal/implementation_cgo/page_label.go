@@ -0,0 +1,72 @@
+package implementation_cgo
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/klippa-app/go-pdfium/internal/pdf_update"
+	"github.com/klippa-app/go-pdfium/requests"
+	"github.com/klippa-app/go-pdfium/responses"
+)
+
+// SetPageLabels replaces the page labels of a document. PDFium can only
+// read page labels, these are written into the catalog when the document
+// is saved with FPDF_SaveAsCopy or FPDF_SaveWithVersion.
+func (p *PdfiumImplementation) SetPageLabels(request *requests.SetPageLabels) (*responses.SetPageLabels, error) {
+	// Don't lock here yet, FPDF_GetPageCount does that for us.
+	pageCount, err := p.FPDF_GetPageCount(&requests.FPDF_GetPageCount{
+		Document: request.Document,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = validatePageLabels(request.Ranges, pageCount.PageCount)
+	if err != nil {
+		return nil, err
+	}
+
+	p.Lock()
+	defer p.Unlock()
+
+	documentHandle, err := p.getDocumentHandle(request.Document)
+	if err != nil {
+		return nil, err
+	}
+
+	if documentHandle.changes == nil {
+		documentHandle.changes = &pdf_update.Changes{}
+	}
+
+	documentHandle.changes.SetPageLabels(request.Ranges)
+
+	return &responses.SetPageLabels{}, nil
+}
+
+func validatePageLabels(ranges []requests.SetPageLabelsRange, pageCount int) error {
+	for i := range ranges {
+		if i == 0 && ranges[i].PageIndex != 0 {
+			return errors.New("the first page label range must start at page index 0")
+		}
+
+		if ranges[i].PageIndex < 0 || ranges[i].PageIndex >= pageCount {
+			return fmt.Errorf("page index %d of page label range is out of range, document has %d pages", ranges[i].PageIndex, pageCount)
+		}
+
+		if i > 0 && ranges[i].PageIndex <= ranges[i-1].PageIndex {
+			return fmt.Errorf("page label ranges must be ordered by page index, page index %d comes after page index %d", ranges[i].PageIndex, ranges[i-1].PageIndex)
+		}
+
+		switch ranges[i].Style {
+		case requests.PageLabelStyleNone, requests.PageLabelStyleDecimal, requests.PageLabelStyleUpperRoman, requests.PageLabelStyleLowerRoman, requests.PageLabelStyleUpperLetters, requests.PageLabelStyleLowerLetters:
+		default:
+			return fmt.Errorf("invalid page label style %s given", ranges[i].Style)
+		}
+
+		if ranges[i].Start < 0 {
+			return fmt.Errorf("invalid start number %d given", ranges[i].Start)
+		}
+	}
+
+	return nil
+}
@@ -4,6 +4,11 @@ package implementation_cgo
 // #include "fpdfview.h"
 import "C"
 import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
 	"github.com/google/uuid"
 	"github.com/klippa-app/go-pdfium/references"
 )
@@ -21,3 +26,68 @@ func (p *PdfiumImplementation) registerPageRange(pageRange C.FPDF_PAGERANGE, doc
 
 	return handle
 }
+
+// parsePageRange parses a page range string like "1,3,5-7" into a list of
+// page indexes (0-index based). When the page range is nil, all pages of the
+// document are returned. Pages are returned in the order they are given in,
+// duplicate pages are only returned once.
+func parsePageRange(pageRange *string, pageCount int) ([]int, error) {
+	if pageRange == nil {
+		pages := make([]int, pageCount)
+		for i := range pages {
+			pages[i] = i
+		}
+		return pages, nil
+	}
+
+	pages := []int{}
+	seen := map[int]bool{}
+	addPage := func(page int) error {
+		if page < 1 || page > pageCount {
+			return fmt.Errorf("page %d is out of range, document has %d pages", page, pageCount)
+		}
+		if !seen[page-1] {
+			seen[page-1] = true
+			pages = append(pages, page-1)
+		}
+		return nil
+	}
+
+	parts := strings.Split(*pageRange, ",")
+	for _, part := range parts {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		rangeParts := strings.SplitN(part, "-", 2)
+		start, err := strconv.Atoi(strings.TrimSpace(rangeParts[0]))
+		if err != nil {
+			return nil, fmt.Errorf("invalid page range %q", part)
+		}
+
+		end := start
+		if len(rangeParts) == 2 {
+			end, err = strconv.Atoi(strings.TrimSpace(rangeParts[1]))
+			if err != nil {
+				return nil, fmt.Errorf("invalid page range %q", part)
+			}
+		}
+
+		if end < start {
+			return nil, fmt.Errorf("invalid page range %q", part)
+		}
+
+		for page := start; page <= end; page++ {
+			if err := addPage(page); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if len(pages) == 0 {
+		return nil, errors.New("page range contains no pages")
+	}
+
+	return pages, nil
+}
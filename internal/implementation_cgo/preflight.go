@@ -0,0 +1,330 @@
+package implementation_cgo
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/klippa-app/go-pdfium/enums"
+	"github.com/klippa-app/go-pdfium/references"
+	"github.com/klippa-app/go-pdfium/requests"
+	"github.com/klippa-app/go-pdfium/responses"
+)
+
+var preflightProfiles = []requests.PreflightProfile{
+	requests.PreflightProfilePDFA1A,
+	requests.PreflightProfilePDFA1B,
+	requests.PreflightProfilePDFA2A,
+	requests.PreflightProfilePDFA2B,
+	requests.PreflightProfilePDFA3A,
+	requests.PreflightProfilePDFA3B,
+	requests.PreflightProfilePrint,
+}
+
+// Preflight gathers what PDFium can tell about the compliance of a
+// document, like the encryption, fonts and transparency, and checks it
+// against the rules of PDF/A and print profiles. This can't replace a full
+// PDF/A validator, it tells whether a document is likely compliant.
+// Experimental API.
+func (p *PdfiumImplementation) Preflight(request *requests.Preflight) (*responses.Preflight, error) {
+	profiles := request.Profiles
+	if len(profiles) == 0 {
+		profiles = preflightProfiles
+	}
+
+	for _, profile := range profiles {
+		if !isValidPreflightProfile(profile) {
+			return nil, fmt.Errorf("invalid preflight profile %s given", profile)
+		}
+	}
+
+	information, err := p.getPreflightInformation(request.Document)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &responses.Preflight{
+		Information: *information,
+		Profiles:    []responses.PreflightProfileResult{},
+	}
+
+	for _, profile := range profiles {
+		rules := preflightRules(profile, information)
+
+		passed := true
+		for _, rule := range rules {
+			if rule.Status == responses.PreflightStatusFail {
+				passed = false
+			}
+		}
+
+		resp.Profiles = append(resp.Profiles, responses.PreflightProfileResult{
+			Profile: profile,
+			Passed:  passed,
+			Rules:   rules,
+		})
+	}
+
+	return resp, nil
+}
+
+func isValidPreflightProfile(profile requests.PreflightProfile) bool {
+	for i := range preflightProfiles {
+		if preflightProfiles[i] == profile {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *PdfiumImplementation) getPreflightInformation(document references.FPDF_DOCUMENT) (*responses.PreflightInformation, error) {
+	pageCount, err := p.FPDF_GetPageCount(&requests.FPDF_GetPageCount{
+		Document: document,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	information := &responses.PreflightInformation{
+		Attachments:      []string{},
+		NonEmbeddedFonts: []string{},
+		TransparentPages: []int{},
+	}
+
+	// New documents don't have a file version yet.
+	fileVersion, err := p.FPDF_GetFileVersion(&requests.FPDF_GetFileVersion{
+		Document: document,
+	})
+	if err == nil {
+		information.FileVersion = fileVersion.FileVersion
+	}
+
+	securityHandlerRevision, err := p.FPDF_GetSecurityHandlerRevision(&requests.FPDF_GetSecurityHandlerRevision{
+		Document: document,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	information.IsEncrypted = securityHandlerRevision.SecurityHandlerRevision != -1
+
+	// The user permissions, the document might be opened with the owner
+	// password which allows everything.
+	permissions, err := p.FPDF_GetDocUserPermissions(&requests.FPDF_GetDocUserPermissions{
+		Document: document,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	information.CanPrint = permissions.PrintDocument
+
+	javaScriptActions, err := p.GetJavaScriptActions(&requests.GetJavaScriptActions{
+		Document: document,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	information.JavaScriptActions = len(javaScriptActions.JavaScriptActions)
+
+	attachmentCount, err := p.FPDFDoc_GetAttachmentCount(&requests.FPDFDoc_GetAttachmentCount{
+		Document: document,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for i := 0; i < attachmentCount.AttachmentCount; i++ {
+		attachment, err := p.FPDFDoc_GetAttachment(&requests.FPDFDoc_GetAttachment{
+			Document: document,
+			Index:    i,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		attachmentName, err := p.FPDFAttachment_GetName(&requests.FPDFAttachment_GetName{
+			Attachment: attachment.Attachment,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		information.Attachments = append(information.Attachments, attachmentName.Name)
+	}
+
+	fonts, err := p.GetFonts(&requests.GetFonts{
+		Document: document,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, font := range fonts.Fonts {
+		if font.IsEmbedded {
+			continue
+		}
+
+		fontName := font.BaseName
+		if fontName == "" {
+			fontName = font.Name
+		}
+		information.NonEmbeddedFonts = append(information.NonEmbeddedFonts, fontName)
+	}
+
+	for i := 0; i < pageCount.PageCount; i++ {
+		hasTransparency, err := p.FPDFPage_HasTransparency(&requests.FPDFPage_HasTransparency{
+			Page: requests.Page{
+				ByIndex: &requests.PageByIndex{
+					Document: document,
+					Index:    i,
+				},
+			},
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		if hasTransparency.HasTransparency {
+			information.TransparentPages = append(information.TransparentPages, i)
+		}
+	}
+
+	isTagged, err := p.FPDFCatalog_IsTagged(&requests.FPDFCatalog_IsTagged{
+		Document: document,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	information.IsTagged = isTagged.IsTagged
+
+	formType, err := p.FPDF_GetFormType(&requests.FPDF_GetFormType{
+		Document: document,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	information.FormType = formType.FormType
+
+	return information, nil
+}
+
+// preflightRules checks the information of a document against the rules of
+// the given profile.
+func preflightRules(profile requests.PreflightProfile, information *responses.PreflightInformation) []responses.PreflightRule {
+	pass := func(check responses.PreflightCheck) responses.PreflightRule {
+		return responses.PreflightRule{Check: check, Status: responses.PreflightStatusPass}
+	}
+	warning := func(check responses.PreflightCheck, message string, args ...interface{}) responses.PreflightRule {
+		return responses.PreflightRule{Check: check, Status: responses.PreflightStatusWarning, Message: fmt.Sprintf(message, args...)}
+	}
+	fail := func(check responses.PreflightCheck, message string, args ...interface{}) responses.PreflightRule {
+		return responses.PreflightRule{Check: check, Status: responses.PreflightStatusFail, Message: fmt.Sprintf(message, args...)}
+	}
+
+	isPrint := profile == requests.PreflightProfilePrint
+	isPDFA1 := profile == requests.PreflightProfilePDFA1A || profile == requests.PreflightProfilePDFA1B
+	isPDFA2 := profile == requests.PreflightProfilePDFA2A || profile == requests.PreflightProfilePDFA2B
+	isLevelA := profile == requests.PreflightProfilePDFA1A || profile == requests.PreflightProfilePDFA2A || profile == requests.PreflightProfilePDFA3A
+
+	rules := []responses.PreflightRule{}
+
+	// PDF/A-1 is based on PDF 1.4 and PDF/A-2 and PDF/A-3 on PDF 1.7, newer
+	// files can use features that are not allowed, like object streams in
+	// PDF/A-1.
+	if !isPrint {
+		maxVersion := 17
+		if isPDFA1 {
+			maxVersion = 14
+		}
+
+		if information.FileVersion == 0 {
+			rules = append(rules, warning(responses.PreflightCheckVersion, "the file version is unknown"))
+		} else if information.FileVersion > maxVersion {
+			rules = append(rules, warning(responses.PreflightCheckVersion, "the file version %s is newer than %s, the file might use features that are not allowed", formatFileVersion(information.FileVersion), formatFileVersion(maxVersion)))
+		} else {
+			rules = append(rules, pass(responses.PreflightCheckVersion))
+		}
+	}
+
+	if !information.IsEncrypted {
+		rules = append(rules, pass(responses.PreflightCheckEncryption))
+	} else if !isPrint {
+		rules = append(rules, fail(responses.PreflightCheckEncryption, "the document is encrypted"))
+	} else if !information.CanPrint {
+		rules = append(rules, fail(responses.PreflightCheckEncryption, "the permissions of the document don't allow printing"))
+	} else {
+		rules = append(rules, warning(responses.PreflightCheckEncryption, "the document is encrypted"))
+	}
+
+	if information.JavaScriptActions == 0 {
+		rules = append(rules, pass(responses.PreflightCheckJavaScript))
+	} else if isPrint {
+		rules = append(rules, warning(responses.PreflightCheckJavaScript, "the document contains JavaScript, which is not run when printing"))
+	} else {
+		rules = append(rules, fail(responses.PreflightCheckJavaScript, "the document contains JavaScript"))
+	}
+
+	// PDF/A-1 doesn't allow embedded files, PDF/A-2 only allows PDF/A files,
+	// PDF/A-3 allows any file.
+	if !isPrint {
+		if len(information.Attachments) == 0 {
+			rules = append(rules, pass(responses.PreflightCheckAttachments))
+		} else if isPDFA1 {
+			rules = append(rules, fail(responses.PreflightCheckAttachments, "the document contains embedded files: %s", strings.Join(information.Attachments, ", ")))
+		} else if isPDFA2 {
+			rules = append(rules, warning(responses.PreflightCheckAttachments, "the document contains embedded files, these must be PDF/A files: %s", strings.Join(information.Attachments, ", ")))
+		} else {
+			rules = append(rules, pass(responses.PreflightCheckAttachments))
+		}
+	}
+
+	if len(information.NonEmbeddedFonts) == 0 {
+		rules = append(rules, pass(responses.PreflightCheckFonts))
+	} else {
+		rules = append(rules, fail(responses.PreflightCheckFonts, "the document uses fonts that are not embedded: %s", strings.Join(information.NonEmbeddedFonts, ", ")))
+	}
+
+	if len(information.TransparentPages) == 0 || (!isPrint && !isPDFA1) {
+		rules = append(rules, pass(responses.PreflightCheckTransparency))
+	} else if isPDFA1 {
+		rules = append(rules, fail(responses.PreflightCheckTransparency, "the document has transparency on pages %s", formatPageIndexes(information.TransparentPages)))
+	} else {
+		rules = append(rules, warning(responses.PreflightCheckTransparency, "the document has transparency on pages %s, which might not print as expected", formatPageIndexes(information.TransparentPages)))
+	}
+
+	if isLevelA {
+		if information.IsTagged {
+			rules = append(rules, pass(responses.PreflightCheckTagged))
+		} else {
+			rules = append(rules, fail(responses.PreflightCheckTagged, "the document is not tagged"))
+		}
+	}
+
+	isXFA := information.FormType == enums.FPDF_FORMTYPE_XFA_FULL || information.FormType == enums.FPDF_FORMTYPE_XFA_FOREGROUND
+	if !isXFA {
+		rules = append(rules, pass(responses.PreflightCheckForms))
+	} else if isPrint {
+		rules = append(rules, warning(responses.PreflightCheckForms, "the document contains an XFA form, which might not print as expected"))
+	} else {
+		rules = append(rules, fail(responses.PreflightCheckForms, "the document contains an XFA form"))
+	}
+
+	return rules
+}
+
+// formatFileVersion formats a numeric file version, like 14, as 1.4.
+func formatFileVersion(version int) string {
+	return fmt.Sprintf("%d.%d", version/10, version%10)
+}
+
+// formatPageIndexes formats page indexes as 1-index based page numbers.
+func formatPageIndexes(pageIndexes []int) string {
+	pageNumbers := make([]string, len(pageIndexes))
+	for i := range pageIndexes {
+		pageNumbers[i] = fmt.Sprintf("%d", pageIndexes[i]+1)
+	}
+	return strings.Join(pageNumbers, ", ")
+}
@@ -17,9 +17,11 @@ import (
 	"io/ioutil"
 	"math"
 	"os"
+	"strconv"
 	"unsafe"
 
 	"github.com/klippa-app/go-pdfium/enums"
+	"github.com/klippa-app/go-pdfium/internal/image/image_grid"
 	"github.com/klippa-app/go-pdfium/internal/image/image_jpeg"
 	"github.com/klippa-app/go-pdfium/requests"
 	"github.com/klippa-app/go-pdfium/responses"
@@ -114,7 +116,7 @@ func (p *PdfiumImplementation) RenderPageInDPI(request *requests.RenderPageInDPI
 			PointToPixelRatio: pointToPixelRatio,
 			Flags:             request.RenderFlags,
 		},
-	}, 0)
+	}, 0, requests.RenderPagesLayout{})
 	if err != nil {
 		return nil, err
 	}
@@ -159,7 +161,7 @@ func (p *PdfiumImplementation) RenderPagesInDPI(request *requests.RenderPagesInD
 		}
 	}
 
-	result, err := p.renderPages(pages, request.Padding)
+	result, err := p.renderPages(pages, request.Padding, request.Layout)
 	if err != nil {
 		return nil, err
 	}
@@ -230,7 +232,7 @@ func (p *PdfiumImplementation) RenderPageInPixels(request *requests.RenderPageIn
 			PointToPixelRatio: ratio,
 			Flags:             request.RenderFlags,
 		},
-	}, 0)
+	}, 0, requests.RenderPagesLayout{})
 	if err != nil {
 		return nil, err
 	}
@@ -277,7 +279,7 @@ func (p *PdfiumImplementation) RenderPagesInPixels(request *requests.RenderPages
 		}
 	}
 
-	result, err := p.renderPages(pages, request.Padding)
+	result, err := p.renderPages(pages, request.Padding, request.Layout)
 	if err != nil {
 		return nil, err
 	}
@@ -296,54 +298,88 @@ type renderPage struct {
 }
 
 // renderPages renders a list of pages, the result is an image.
-func (p *PdfiumImplementation) renderPages(pages []renderPage, padding int) (*responses.RenderPages, error) {
-	totalWidth := 0
-	totalHeight := 0
+func (p *PdfiumImplementation) renderPages(pages []renderPage, padding int, layout requests.RenderPagesLayout) (*responses.RenderPages, error) {
+	columns, labelHeight, err := getRenderPagesGrid(len(pages), layout)
+	if err != nil {
+		return nil, err
+	}
 
-	// First calculate the total image size
+	sizes := make([]image.Point, len(pages))
 	for i := range pages {
-		if pages[i].Width > totalWidth {
-			totalWidth = pages[i].Width
-		}
+		sizes[i] = image.Point{X: pages[i].Width, Y: pages[i].Height}
+	}
 
-		totalHeight += pages[i].Height
+	// Scale the pages down when the image would be larger than the maximum.
+	if layout.MaxWidth > 0 || layout.MaxHeight > 0 {
+		scale, err := image_grid.FitScale(sizes, columns, padding, labelHeight, layout.MaxWidth, layout.MaxHeight)
+		if err != nil {
+			return nil, err
+		}
 
-		// Add padding between the renders
-		if i > 0 {
-			totalHeight += padding
+		if scale < 1 {
+			for i := range pages {
+				pages[i].Width = int(math.Max(math.Floor(float64(pages[i].Width)*scale), 1))
+				pages[i].Height = int(math.Max(math.Floor(float64(pages[i].Height)*scale), 1))
+				pages[i].PointToPixelRatio *= scale
+				sizes[i] = image.Point{X: pages[i].Width, Y: pages[i].Height}
+			}
 		}
 	}
 
+	// First calculate the total image size
+	positions, totalSize := image_grid.Layout(sizes, columns, padding, labelHeight)
+	totalWidth := totalSize.X
+	totalHeight := totalSize.Y
+
 	img := image.NewRGBA(image.Rect(0, 0, totalWidth, totalHeight))
 
 	// Create a device independent bitmap to the external buffer by passing a
 	// pointer to the first pixel, PDFium will do the rest.
 	bitmap := C.FPDFBitmap_CreateEx(C.int(totalWidth), C.int(totalHeight), C.FPDFBitmap_BGRA, unsafe.Pointer(&img.Pix[0]), C.int(img.Stride))
 
+	if layout.BackgroundColor != nil {
+		C.FPDFBitmap_FillRect(bitmap, 0, 0, C.int(totalWidth), C.int(totalHeight), C.ulong(getRenderPagesBackgroundColor(layout.BackgroundColor)))
+	}
+
 	pagesInfo := make([]responses.RenderPagesPage, len(pages))
-	currentOffset := 0
 	for i := range pages {
 		// Keep track of page information in the total image.
 		pagesInfo[i] = responses.RenderPagesPage{
 			PointToPixelRatio: pages[i].PointToPixelRatio,
 			Width:             pages[i].Width,
 			Height:            pages[i].Height,
-			X:                 0,
-			Y:                 currentOffset,
+			X:                 positions[i].X,
+			Y:                 positions[i].Y,
 		}
-		index, hasTransparency, err := p.renderPage(bitmap, pages[i].Page, pages[i].Width, pages[i].Height, currentOffset, pages[i].Flags)
+		index, hasTransparency, err := p.renderPage(bitmap, pages[i].Page, pages[i].Width, pages[i].Height, positions[i].X, positions[i].Y, pages[i].Flags)
 		if err != nil {
+			C.FPDFBitmap_Destroy(bitmap)
 			return nil, err
 		}
 		pagesInfo[i].Page = index
 		pagesInfo[i].HasTransparency = hasTransparency
-		currentOffset += pages[i].Height + padding
+
+		if layout.Label != requests.RenderPagesLayoutLabelNone {
+			pagesInfo[i].Label, err = p.getRenderPageLabel(pages[i].Page, index, layout.Label)
+			if err != nil {
+				C.FPDFBitmap_Destroy(bitmap)
+				return nil, err
+			}
+		}
 	}
 
 	// Release bitmap resources and buffers.
 	// This does not clear the Go image pixel buffer.
 	C.FPDFBitmap_Destroy(bitmap)
 
+	if layout.Label != requests.RenderPagesLayoutLabelNone {
+		labelColor := getRenderPagesLabelColor(layout.BackgroundColor)
+		for i := range pagesInfo {
+			labelRect := image.Rect(pagesInfo[i].X, pagesInfo[i].Y+pagesInfo[i].Height, pagesInfo[i].X+pagesInfo[i].Width, pagesInfo[i].Y+pagesInfo[i].Height+labelHeight)
+			image_grid.DrawLabel(img, labelRect, pagesInfo[i].Label, labelColor)
+		}
+	}
+
 	return &responses.RenderPages{
 		Image:  img,
 		Pages:  pagesInfo,
@@ -352,8 +388,36 @@ func (p *PdfiumImplementation) renderPages(pages []renderPage, padding int) (*re
 	}, nil
 }
 
+// getRenderPageLabel returns the label to draw under a rendered page.
+func (p *PdfiumImplementation) getRenderPageLabel(page requests.Page, index int, label requests.RenderPagesLayoutLabel) (string, error) {
+	// We don't know the page number of pages that were loaded by reference.
+	if index == -1 {
+		return "", nil
+	}
+
+	if label == requests.RenderPagesLayoutLabelPageLabel {
+		pageHandle, err := p.loadPage(page)
+		if err != nil {
+			return "", err
+		}
+
+		documentHandle, err := p.getDocumentHandle(pageHandle.documentRef)
+		if err != nil {
+			return "", err
+		}
+
+		// Fall back to the page number when the page has no label.
+		pageLabel, err := p.getPageLabel(documentHandle, index)
+		if err == nil && pageLabel != "" {
+			return pageLabel, nil
+		}
+	}
+
+	return strconv.Itoa(index + 1), nil
+}
+
 // renderPage renders a specific page in a specific size on a bitmap.
-func (p *PdfiumImplementation) renderPage(bitmap C.FPDF_BITMAP, page requests.Page, width, height, offset int, flags enums.FPDF_RENDER_FLAG) (int, bool, error) {
+func (p *PdfiumImplementation) renderPage(bitmap C.FPDF_BITMAP, page requests.Page, width, height, x, y int, flags enums.FPDF_RENDER_FLAG) (int, bool, error) {
 	pageHandle, err := p.loadPage(page)
 	if err != nil {
 		return 0, false, err
@@ -373,11 +437,11 @@ func (p *PdfiumImplementation) renderPage(bitmap C.FPDF_BITMAP, page requests.Pa
 	}
 
 	// Fill the page rect with the specified color.
-	C.FPDFBitmap_FillRect(bitmap, 0, C.int(offset), C.int(width), C.int(height), C.ulong(fillColor))
+	C.FPDFBitmap_FillRect(bitmap, C.int(x), C.int(y), C.int(width), C.int(height), C.ulong(fillColor))
 
 	// Render the bitmap into the given external bitmap, write the bytes
 	// in reverse order so that BGRA becomes RGBA.
-	C.FPDF_RenderPageBitmap(bitmap, pageHandle.handle, 0, C.int(offset), C.int(width), C.int(height), 0, C.int(flags)|C.FPDF_REVERSE_BYTE_ORDER)
+	C.FPDF_RenderPageBitmap(bitmap, pageHandle.handle, C.int(x), C.int(y), C.int(width), C.int(height), 0, C.int(flags)|C.FPDF_REVERSE_BYTE_ORDER)
 
 	return pageHandle.index, hasTransparency, nil
 }
@@ -478,6 +542,20 @@ func (p *PdfiumImplementation) RenderToFile(request *requests.RenderToFile) (*re
 		return nil, errors.New("no render operation given")
 	}
 
+	imageBytes, imagePath, err := p.writeRenderedImage(renderedImage, hasTransparency, request)
+	if err != nil {
+		return nil, err
+	}
+
+	myResp.ImageBytes = imageBytes
+	myResp.ImagePath = imagePath
+
+	return myResp, nil
+}
+
+// writeRenderedImage encodes the rendered image in the output format of the
+// request and returns the bytes or writes it to the output file.
+func (p *PdfiumImplementation) writeRenderedImage(renderedImage *image.RGBA, hasTransparency bool, request *requests.RenderToFile) (*[]byte, string, error) {
 	var imgBuf bytes.Buffer
 
 	if request.OutputFormat == requests.RenderToFileOutputFormatJPG {
@@ -507,7 +585,7 @@ func (p *PdfiumImplementation) RenderToFile(request *requests.RenderToFile) (*re
 		for {
 			err := image_jpeg.Encode(&imgBuf, renderedImage, opt)
 			if err != nil {
-				return nil, err
+				return nil, "", err
 			}
 
 			if request.MaxFileSize == 0 || int64(imgBuf.Len()) < request.MaxFileSize {
@@ -517,7 +595,7 @@ func (p *PdfiumImplementation) RenderToFile(request *requests.RenderToFile) (*re
 			opt.Quality -= 10
 
 			if opt.Quality <= 45 {
-				return nil, errors.New("PDF image would exceed maximum filesize")
+				return nil, "", errors.New("PDF image would exceed maximum filesize")
 			}
 
 			imgBuf.Reset()
@@ -525,49 +603,47 @@ func (p *PdfiumImplementation) RenderToFile(request *requests.RenderToFile) (*re
 	} else if request.OutputFormat == requests.RenderToFileOutputFormatPNG {
 		err := png.Encode(&imgBuf, renderedImage)
 		if err != nil {
-			return nil, err
+			return nil, "", err
 		}
 
 		if request.MaxFileSize != 0 && int64(imgBuf.Len()) > request.MaxFileSize {
-			return nil, errors.New("PDF image would exceed maximum filesize")
+			return nil, "", errors.New("PDF image would exceed maximum filesize")
 		}
 	} else {
-		return nil, errors.New("invalid output format given")
+		return nil, "", errors.New("invalid output format given")
 	}
 
 	if request.OutputTarget == requests.RenderToFileOutputTargetBytes {
 		imageBytes := imgBuf.Bytes()
-		myResp.ImageBytes = &imageBytes
+		return &imageBytes, "", nil
 	} else if request.OutputTarget == requests.RenderToFileOutputTargetFile {
 		var targetFile *os.File
 		if request.TargetFilePath != "" {
 			existingFile, err := os.Create(request.TargetFilePath)
 			if err != nil {
-				return nil, err
+				return nil, "", err
 			}
 			targetFile = existingFile
 		} else {
 			tempFile, err := ioutil.TempFile("", "")
 			if err != nil {
-				return nil, err
+				return nil, "", err
 			}
 			targetFile = tempFile
 		}
 
 		_, err := targetFile.Write(imgBuf.Bytes())
 		if err != nil {
-			return nil, err
+			return nil, "", err
 		}
 
 		err = targetFile.Close()
 		if err != nil {
-			return nil, err
+			return nil, "", err
 		}
 
-		myResp.ImagePath = targetFile.Name()
-	} else {
-		return nil, errors.New("invalid output target given")
+		return nil, targetFile.Name(), nil
 	}
 
-	return myResp, nil
+	return nil, "", errors.New("invalid output target given")
 }
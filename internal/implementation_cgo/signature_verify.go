@@ -0,0 +1,196 @@
+package implementation_cgo
+
+import (
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/klippa-app/go-pdfium/internal/pdf_signature"
+	"github.com/klippa-app/go-pdfium/references"
+	"github.com/klippa-app/go-pdfium/requests"
+	"github.com/klippa-app/go-pdfium/responses"
+)
+
+// VerifySignatures verifies the signatures of a document over the original
+// file, and validates the certificates of the signers against the given
+// trusted certificates.
+func (p *PdfiumImplementation) VerifySignatures(request *requests.VerifySignatures) (*responses.VerifySignatures, error) {
+	p.Lock()
+	documentHandle, err := p.getDocumentHandle(request.Document)
+	if err != nil {
+		p.Unlock()
+		return nil, err
+	}
+
+	file := request.File
+	if file == nil {
+		file = documentHandle.data
+	}
+	p.Unlock()
+
+	if file == nil {
+		return nil, errors.New("the original file of the document is not available, give it in File")
+	}
+
+	roots, err := pdf_signature.CertificatePool(request.TrustedCertificates)
+	if err != nil {
+		return nil, err
+	}
+
+	// Don't lock here, the methods that we call do that for us.
+	signatureCount, err := p.FPDF_GetSignatureCount(&requests.FPDF_GetSignatureCount{
+		Document: request.Document,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &responses.VerifySignatures{
+		Signatures: []responses.VerifySignaturesSignature{},
+	}
+
+	for i := 0; i < signatureCount.Count; i++ {
+		signature, err := p.verifySignature(request.Document, i, *file, roots)
+		if err != nil {
+			return nil, err
+		}
+
+		resp.Signatures = append(resp.Signatures, *signature)
+	}
+
+	return resp, nil
+}
+
+// verifySignature reads the signature with the given index and verifies it.
+func (p *PdfiumImplementation) verifySignature(document references.FPDF_DOCUMENT, index int, file []byte, roots *x509.CertPool) (*responses.VerifySignaturesSignature, error) {
+	signatureObject, err := p.FPDF_GetSignatureObject(&requests.FPDF_GetSignatureObject{
+		Document: document,
+		Index:    index,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	contents, err := p.FPDFSignatureObj_GetContents(&requests.FPDFSignatureObj_GetContents{
+		Signature: signatureObject.Signature,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	byteRange, err := p.FPDFSignatureObj_GetByteRange(&requests.FPDFSignatureObj_GetByteRange{
+		Signature: signatureObject.Signature,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	subFilter, err := p.FPDFSignatureObj_GetSubFilter(&requests.FPDFSignatureObj_GetSubFilter{
+		Signature: signatureObject.Signature,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	reason, err := p.FPDFSignatureObj_GetReason(&requests.FPDFSignatureObj_GetReason{
+		Signature: signatureObject.Signature,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	signingTime, err := p.FPDFSignatureObj_GetTime(&requests.FPDFSignatureObj_GetTime{
+		Signature: signatureObject.Signature,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// PDFium gives an error when the signature has no DocMDP permission.
+	docMDPPermission := 0
+	docMDPPermissionResult, err := p.FPDFSignatureObj_GetDocMDPPermission(&requests.FPDFSignatureObj_GetDocMDPPermission{
+		Signature: signatureObject.Signature,
+	})
+	if err == nil {
+		docMDPPermission = docMDPPermissionResult.DocMDPPermission
+	}
+
+	signature := &responses.VerifySignaturesSignature{
+		Index:            index,
+		SubFilter:        subFilter.SubFilter,
+		Reason:           reason.Reason,
+		Time:             signingTime.Time,
+		DocMDPPermission: docMDPPermission,
+		ByteRange:        byteRange.ByteRange,
+		Status:           responses.VerifySignaturesStatusInvalid,
+		Certificates:     []responses.VerifySignaturesCertificate{},
+	}
+
+	if byteRange.ByteRange != nil {
+		signature.ModifiedAfterSigning = pdf_signature.ModifiedAfterSigning(file, byteRange.ByteRange)
+	}
+
+	if contents.Contents == nil {
+		signature.Error = "the signature has no contents"
+		return signature, nil
+	}
+
+	signedData, err := pdf_signature.SignedData(file, byteRange.ByteRange, contents.Contents)
+	if err != nil {
+		signature.Error = err.Error()
+		return signature, nil
+	}
+
+	signatureSubFilter := ""
+	if subFilter.SubFilter != nil {
+		signatureSubFilter = *subFilter.SubFilter
+	}
+
+	result, err := pdf_signature.Verify(contents.Contents, signedData, signatureSubFilter, roots, time.Now())
+	if err != nil {
+		if errors.Is(err, pdf_signature.ErrUnsupported) {
+			signature.Status = responses.VerifySignaturesStatusUnsupported
+		}
+		signature.Error = err.Error()
+		return signature, nil
+	}
+
+	signature.SigningTime = result.SigningTime
+	signature.DigestAlgorithm = result.DigestAlgorithm.String()
+	signature.DigestValid = result.DigestValid
+	signature.SignatureValid = result.SignatureValid
+	signature.CertificateTrusted = result.Trusted
+
+	signer := verifySignaturesCertificate(result.Signer)
+	signature.Signer = &signer
+	for _, certificate := range result.Certificates {
+		signature.Certificates = append(signature.Certificates, verifySignaturesCertificate(certificate))
+	}
+
+	switch {
+	case !result.DigestValid:
+		signature.Error = "the signed data was changed after signing"
+	case !result.SignatureValid:
+		signature.Error = "the signature doesn't match the certificate of the signer"
+	case !result.Trusted:
+		signature.Status = responses.VerifySignaturesStatusUntrusted
+		signature.Error = fmt.Sprintf("the certificate of the signer is not trusted: %s", result.TrustError.Error())
+	default:
+		signature.Status = responses.VerifySignaturesStatusValid
+	}
+
+	return signature, nil
+}
+
+// verifySignaturesCertificate converts a certificate for the response.
+func verifySignaturesCertificate(certificate *x509.Certificate) responses.VerifySignaturesCertificate {
+	return responses.VerifySignaturesCertificate{
+		Subject:      certificate.Subject.String(),
+		Issuer:       certificate.Issuer.String(),
+		SerialNumber: certificate.SerialNumber.Text(16),
+		NotBefore:    certificate.NotBefore,
+		NotAfter:     certificate.NotAfter,
+		Raw:          certificate.Raw,
+	}
+}
@@ -0,0 +1,381 @@
+package implementation_cgo
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/klippa-app/go-pdfium/enums"
+	"github.com/klippa-app/go-pdfium/internal/appearance"
+	"github.com/klippa-app/go-pdfium/references"
+	"github.com/klippa-app/go-pdfium/requests"
+	"github.com/klippa-app/go-pdfium/responses"
+	"github.com/klippa-app/go-pdfium/structs"
+)
+
+// CreateTextMarkupAnnotations creates highlight, underline, squiggly or
+// strikeout annotations for text ranges and search hits, with an appearance
+// stream so that they are rendered.
+// Experimental API.
+func (p *PdfiumImplementation) CreateTextMarkupAnnotations(request *requests.CreateTextMarkupAnnotations) (*responses.CreateTextMarkupAnnotations, error) {
+	pageCount, err := p.FPDF_GetPageCount(&requests.FPDF_GetPageCount{
+		Document: request.Document,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if !appearance.IsTextMarkup(request.Subtype) {
+		return nil, fmt.Errorf("subtype %d is not a text markup subtype", request.Subtype)
+	}
+
+	if len(request.Ranges) == 0 && request.Search == nil {
+		return nil, errors.New("no text ranges or search given")
+	}
+
+	if request.Search != nil && request.Search.Text == "" {
+		return nil, errors.New("no search text given")
+	}
+
+	ranges := append([]requests.TextMarkupRange{}, request.Ranges...)
+	if request.Search != nil {
+		pages, err := parsePageRange(request.Search.PageRange, pageCount.PageCount)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, pageIndex := range pages {
+			hits, err := p.searchTextRanges(request.Document, pageIndex, request.Search)
+			if err != nil {
+				return nil, err
+			}
+
+			ranges = append(ranges, hits...)
+		}
+	}
+
+	// Resolve the quad points of all the ranges first, so that we don't
+	// change the document when one of the ranges is invalid.
+	quadPoints := make([][]structs.FPDF_FS_QUADPOINTSF, len(ranges))
+	for i := range ranges {
+		if ranges[i].Page < 0 || ranges[i].Page >= pageCount.PageCount {
+			return nil, fmt.Errorf("page %d of text range %d doesn't exist", ranges[i].Page, i)
+		}
+
+		quadPoints[i], err = p.getTextRangeQuadPoints(request.Document, ranges[i])
+		if err != nil {
+			return nil, fmt.Errorf("could not get quad points of text range %d: %w", i, err)
+		}
+
+		if len(quadPoints[i]) == 0 {
+			return nil, fmt.Errorf("text range %d has no visible characters", i)
+		}
+	}
+
+	color := defaultTextMarkupColor(request.Subtype)
+	if request.Color != nil {
+		color = *request.Color
+	}
+
+	resp := &responses.CreateTextMarkupAnnotations{
+		Annotations: []responses.TextMarkupAnnotation{},
+	}
+
+	for i := range ranges {
+		annotation, err := p.createTextMarkupAnnotation(request, ranges[i], quadPoints[i], color)
+		if err != nil {
+			return nil, err
+		}
+
+		resp.Annotations = append(resp.Annotations, *annotation)
+	}
+
+	return resp, nil
+}
+
+// defaultTextMarkupColor returns the default color of text markup
+// annotations, highlights are transparent to keep the text readable.
+func defaultTextMarkupColor(subtype enums.FPDF_ANNOTATION_SUBTYPE) structs.FPDF_COLOR {
+	if subtype == enums.FPDF_ANNOT_SUBTYPE_HIGHLIGHT {
+		return structs.FPDF_COLOR{R: 255, G: 255, B: 0, A: 128}
+	}
+	return structs.FPDF_COLOR{R: 255, G: 0, B: 0, A: 255}
+}
+
+// searchTextRanges returns the text ranges of all the hits of a search on a
+// page.
+func (p *PdfiumImplementation) searchTextRanges(document references.FPDF_DOCUMENT, pageIndex int, search *requests.TextMarkupSearch) ([]requests.TextMarkupRange, error) {
+	textPage, err := p.FPDFText_LoadPage(&requests.FPDFText_LoadPage{
+		Page: requests.Page{
+			ByIndex: &requests.PageByIndex{
+				Document: document,
+				Index:    pageIndex,
+			},
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer p.FPDFText_ClosePage(&requests.FPDFText_ClosePage{
+		TextPage: textPage.TextPage,
+	})
+
+	searchHandle, err := p.FPDFText_FindStart(&requests.FPDFText_FindStart{
+		TextPage:   textPage.TextPage,
+		Find:       search.Text,
+		Flags:      search.Flags,
+		StartIndex: 0,
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer p.FPDFText_FindClose(&requests.FPDFText_FindClose{
+		Search: searchHandle.Search,
+	})
+
+	ranges := []requests.TextMarkupRange{}
+	for {
+		findNext, err := p.FPDFText_FindNext(&requests.FPDFText_FindNext{
+			Search: searchHandle.Search,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		if !findNext.GotMatch {
+			break
+		}
+
+		resultIndex, err := p.FPDFText_GetSchResultIndex(&requests.FPDFText_GetSchResultIndex{
+			Search: searchHandle.Search,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		resultCount, err := p.FPDFText_GetSchCount(&requests.FPDFText_GetSchCount{
+			Search: searchHandle.Search,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		ranges = append(ranges, requests.TextMarkupRange{
+			Page:       pageIndex,
+			StartIndex: resultIndex.Index,
+			Count:      resultCount.Count,
+		})
+	}
+
+	return ranges, nil
+}
+
+// getTextRangeQuadPoints returns the quad points of the characters in a text
+// range, one per line of text.
+func (p *PdfiumImplementation) getTextRangeQuadPoints(document references.FPDF_DOCUMENT, textRange requests.TextMarkupRange) ([]structs.FPDF_FS_QUADPOINTSF, error) {
+	textPage, err := p.FPDFText_LoadPage(&requests.FPDFText_LoadPage{
+		Page: requests.Page{
+			ByIndex: &requests.PageByIndex{
+				Document: document,
+				Index:    textRange.Page,
+			},
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer p.FPDFText_ClosePage(&requests.FPDFText_ClosePage{
+		TextPage: textPage.TextPage,
+	})
+
+	charCount, err := p.FPDFText_CountChars(&requests.FPDFText_CountChars{
+		TextPage: textPage.TextPage,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if textRange.StartIndex < 0 || textRange.Count <= 0 || textRange.StartIndex+textRange.Count > charCount.Count {
+		return nil, errors.New("the characters are not on the page")
+	}
+
+	boxes := []structs.FPDF_FS_RECTF{}
+	for i := textRange.StartIndex; i < textRange.StartIndex+textRange.Count; i++ {
+		charBox, err := p.FPDFText_GetCharBox(&requests.FPDFText_GetCharBox{
+			TextPage: textPage.TextPage,
+			Index:    i,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		// Generated characters, like line breaks, and white space don't
+		// have a box.
+		if charBox.Right <= charBox.Left || charBox.Top <= charBox.Bottom {
+			continue
+		}
+
+		boxes = append(boxes, structs.FPDF_FS_RECTF{
+			Left:   float32(charBox.Left),
+			Top:    float32(charBox.Top),
+			Right:  float32(charBox.Right),
+			Bottom: float32(charBox.Bottom),
+		})
+	}
+
+	return textLineQuadPoints(boxes), nil
+}
+
+// textLineQuadPoints merges the boxes of characters into quad points, one
+// per line of text. A character starts a new line when it doesn't overlap
+// the current line vertically, or when it is left of the current line.
+func textLineQuadPoints(boxes []structs.FPDF_FS_RECTF) []structs.FPDF_FS_QUADPOINTSF {
+	lines := []structs.FPDF_FS_RECTF{}
+	for _, box := range boxes {
+		if len(lines) > 0 {
+			line := &lines[len(lines)-1]
+			center := (box.Top + box.Bottom) / 2
+			if center >= line.Bottom && center <= line.Top && box.Left >= line.Left {
+				if box.Right > line.Right {
+					line.Right = box.Right
+				}
+				if box.Top > line.Top {
+					line.Top = box.Top
+				}
+				if box.Bottom < line.Bottom {
+					line.Bottom = box.Bottom
+				}
+				continue
+			}
+		}
+
+		lines = append(lines, box)
+	}
+
+	quadPoints := make([]structs.FPDF_FS_QUADPOINTSF, len(lines))
+	for i, line := range lines {
+		quadPoints[i] = structs.FPDF_FS_QUADPOINTSF{
+			X1: line.Left, Y1: line.Top,
+			X2: line.Right, Y2: line.Top,
+			X3: line.Left, Y3: line.Bottom,
+			X4: line.Right, Y4: line.Bottom,
+		}
+	}
+
+	return quadPoints
+}
+
+func (p *PdfiumImplementation) createTextMarkupAnnotation(request *requests.CreateTextMarkupAnnotations, textRange requests.TextMarkupRange, quadPoints []structs.FPDF_FS_QUADPOINTSF, color structs.FPDF_COLOR) (*responses.TextMarkupAnnotation, error) {
+	page := requests.Page{
+		ByIndex: &requests.PageByIndex{
+			Document: request.Document,
+			Index:    textRange.Page,
+		},
+	}
+
+	createdAnnotation, err := p.FPDFPage_CreateAnnot(&requests.FPDFPage_CreateAnnot{
+		Page:    page,
+		Subtype: request.Subtype,
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer p.FPDFPage_CloseAnnot(&requests.FPDFPage_CloseAnnot{
+		Annotation: createdAnnotation.Annotation,
+	})
+
+	annotation := createdAnnotation.Annotation
+	rect := appearance.QuadPointsRect(quadPoints)
+
+	_, err = p.FPDFAnnot_SetRect(&requests.FPDFAnnot_SetRect{
+		Annotation: annotation,
+		Rect:       rect,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = p.FPDFAnnot_SetFlags(&requests.FPDFAnnot_SetFlags{
+		Annotation: annotation,
+		Flags:      enums.FPDF_ANNOT_FLAG_PRINT,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// The color has to be set before the appearance stream, PDFium doesn't
+	// allow setting the color of annotations with an appearance stream.
+	_, err = p.FPDFAnnot_SetColor(&requests.FPDFAnnot_SetColor{
+		Annotation: annotation,
+		ColorType:  enums.FPDFANNOT_COLORTYPE_Color,
+		R:          color.R,
+		G:          color.G,
+		B:          color.B,
+		A:          color.A,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range quadPoints {
+		_, err = p.FPDFAnnot_AppendAttachmentPoints(&requests.FPDFAnnot_AppendAttachmentPoints{
+			Annotation:       annotation,
+			AttachmentPoints: quadPoints[i],
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	stringValues := []struct {
+		key   string
+		value string
+	}{
+		{"Contents", request.Contents},
+		{"T", request.Author},
+	}
+	for _, stringValue := range stringValues {
+		if stringValue.value == "" {
+			continue
+		}
+
+		_, err = p.FPDFAnnot_SetStringValue(&requests.FPDFAnnot_SetStringValue{
+			Annotation: annotation,
+			Key:        stringValue.key,
+			Value:      stringValue.value,
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	appearanceStream, err := appearance.TextMarkup(request.Subtype, quadPoints, color)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = p.FPDFAnnot_SetAP(&requests.FPDFAnnot_SetAP{
+		Annotation:     annotation,
+		AppearanceMode: enums.FPDF_ANNOT_APPEARANCEMODE_NORMAL,
+		Value:          &appearanceStream,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	annotationIndex, err := p.FPDFPage_GetAnnotIndex(&requests.FPDFPage_GetAnnotIndex{
+		Page:       page,
+		Annotation: annotation,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &responses.TextMarkupAnnotation{
+		Page:       textRange.Page,
+		Index:      annotationIndex.Index,
+		StartIndex: textRange.StartIndex,
+		Count:      textRange.Count,
+		Rect:       rect,
+		QuadPoints: quadPoints,
+	}, nil
+}
@@ -0,0 +1,487 @@
+package implementation_webassembly
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/klippa-app/go-pdfium/enums"
+	"github.com/klippa-app/go-pdfium/internal/pdf_update"
+	"github.com/klippa-app/go-pdfium/references"
+	"github.com/klippa-app/go-pdfium/requests"
+	"github.com/klippa-app/go-pdfium/responses"
+)
+
+var accessibilityChecks = []responses.AccessibilityCheck{
+	responses.AccessibilityCheckTagged,
+	responses.AccessibilityCheckTitle,
+	responses.AccessibilityCheckLanguage,
+	responses.AccessibilityCheckUntaggedContent,
+	responses.AccessibilityCheckFigureAltText,
+	responses.AccessibilityCheckHeadingOrder,
+	responses.AccessibilityCheckTableHeaders,
+}
+
+// CheckAccessibility checks a document for common accessibility problems:
+// whether it is tagged and has a title and a language, content that is not
+// tagged or marked as an artifact, figures without alternate text, headings
+// that skip a level and tables without header cells. PDFium only gives the
+// structure tree of a page, so a table that continues on the next page needs
+// header cells on every page, and the heading order is checked from the
+// first page that is checked. This can't replace a full PDF/UA validator.
+// Experimental API.
+func (p *PdfiumImplementation) CheckAccessibility(request *requests.CheckAccessibility) (*responses.CheckAccessibility, error) {
+	pageCount, err := p.FPDF_GetPageCount(&requests.FPDF_GetPageCount{
+		Document: request.Document,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	pages, err := parsePageRange(request.PageRange, pageCount.PageCount)
+	if err != nil {
+		return nil, err
+	}
+
+	information, err := p.getAccessibilityInformation(request.Document)
+	if err != nil {
+		return nil, err
+	}
+
+	checker := &accessibilityChecker{
+		issues: []responses.AccessibilityIssue{},
+	}
+
+	for _, pageIndex := range pages {
+		page := requests.Page{
+			ByIndex: &requests.PageByIndex{
+				Document: request.Document,
+				Index:    pageIndex,
+			},
+		}
+
+		// PDFium gives an error when the document doesn't have a structure
+		// tree, all content of an untagged document is untagged.
+		elements := []responses.StructElement{}
+		if information.IsTagged {
+			elements, err = p.getPageStructTree(page)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		markedContentIDs := map[int]bool{}
+		for i := range elements {
+			checker.checkElement(pageIndex, elements[i], []int{i}, markedContentIDs)
+		}
+
+		untaggedObjects, err := p.countUntaggedPageObjects(page, markedContentIDs)
+		if err != nil {
+			return nil, err
+		}
+
+		if untaggedObjects > 0 {
+			checker.addPageIssue(responses.AccessibilityCheckUntaggedContent, pageIndex, fmt.Sprintf("content objects that are not tagged or marked as an artifact: %d", untaggedObjects))
+		}
+	}
+
+	issues := []responses.AccessibilityIssue{}
+	if !information.IsTagged {
+		issues = append(issues, accessibilityIssue(responses.AccessibilityCheckTagged, responses.AccessibilityStatusFail, "the document is not tagged"))
+	}
+
+	if information.Title == nil {
+		issues = append(issues, accessibilityIssue(responses.AccessibilityCheckTitle, responses.AccessibilityStatusFail, "the document doesn't have a title"))
+	}
+
+	// The language can also be given per structure element, but a document
+	// language is required as the default.
+	if information.Language == nil {
+		if checker.hasElementLanguage {
+			issues = append(issues, accessibilityIssue(responses.AccessibilityCheckLanguage, responses.AccessibilityStatusWarning, "the document doesn't have a language, only structure elements have a language"))
+		} else {
+			issues = append(issues, accessibilityIssue(responses.AccessibilityCheckLanguage, responses.AccessibilityStatusFail, "the document doesn't have a language"))
+		}
+	}
+
+	issues = append(issues, checker.issues...)
+
+	resp := &responses.CheckAccessibility{
+		Information: *information,
+		Passed:      true,
+		Rules:       []responses.AccessibilityRule{},
+		Issues:      issues,
+	}
+
+	for _, check := range accessibilityChecks {
+		// The checks of the structure tree don't apply to untagged documents.
+		if !information.IsTagged && (check == responses.AccessibilityCheckFigureAltText || check == responses.AccessibilityCheckHeadingOrder || check == responses.AccessibilityCheckTableHeaders) {
+			continue
+		}
+
+		rule := accessibilityRule(check, issues)
+		if rule.Status == responses.AccessibilityStatusFail {
+			resp.Passed = false
+		}
+
+		resp.Rules = append(resp.Rules, rule)
+	}
+
+	return resp, nil
+}
+
+func (p *PdfiumImplementation) getAccessibilityInformation(document references.FPDF_DOCUMENT) (*responses.AccessibilityInformation, error) {
+	information := &responses.AccessibilityInformation{}
+
+	isTagged, err := p.FPDFCatalog_IsTagged(&requests.FPDFCatalog_IsTagged{
+		Document: document,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	information.IsTagged = isTagged.IsTagged
+
+	// PDFium gives an error or an empty value when the title is not set.
+	title, err := p.FPDF_GetMetaText(&requests.FPDF_GetMetaText{
+		Document: document,
+		Tag:      "Title",
+	})
+	if err == nil && strings.TrimSpace(title.Value) != "" {
+		information.Title = &title.Value
+	}
+
+	language, err := p.getDocumentLanguage(document)
+	if err != nil {
+		return nil, err
+	}
+
+	information.Language = language
+
+	return information, nil
+}
+
+// getDocumentLanguage returns the language (Lang) of the document catalog.
+// PDFium has no API for it, so it is read from the original file of the
+// document. Documents without original file, or with an encrypted or broken
+// one, are read from a copy of the document without security.
+func (p *PdfiumImplementation) getDocumentLanguage(document references.FPDF_DOCUMENT) (*string, error) {
+	p.Lock()
+	documentHandle, err := p.getDocumentHandle(document)
+	if err != nil {
+		p.Unlock()
+		return nil, err
+	}
+
+	data := documentHandle.data
+	p.Unlock()
+
+	var file *pdf_update.File
+	if data != nil {
+		originalFile, err := pdf_update.Parse(*data)
+		if err == nil {
+			if _, ok := originalFile.Trailer()["Encrypt"]; !ok {
+				file = originalFile
+			}
+		}
+	}
+
+	if file == nil {
+		savedDocument, err := p.FPDF_SaveAsCopy(&requests.FPDF_SaveAsCopy{
+			Document: document,
+			Flags:    requests.SaveFlagRemoveSecurity,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		file, err = pdf_update.Parse(*savedDocument.FileBytes)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	update := file.NewUpdate()
+	_, catalog, err := update.Catalog()
+	if err != nil {
+		return nil, err
+	}
+
+	languageObject, err := update.Resolve(catalog["Lang"])
+	if err != nil {
+		return nil, err
+	}
+
+	language := ""
+	switch value := languageObject.(type) {
+	case pdf_update.String:
+		language = pdf_update.DecodeTextString(value)
+	case pdf_update.HexString:
+		language = pdf_update.DecodeTextString(value)
+	}
+
+	if strings.TrimSpace(language) == "" {
+		return nil, nil
+	}
+
+	return &language, nil
+}
+
+// accessibilityIssue returns an issue about the document.
+func accessibilityIssue(check responses.AccessibilityCheck, status responses.AccessibilityStatus, message string) responses.AccessibilityIssue {
+	return responses.AccessibilityIssue{
+		Check:   check,
+		Status:  status,
+		Message: message,
+	}
+}
+
+// accessibilityChecker checks the structure elements of the pages in page
+// order, the heading levels are followed across pages.
+type accessibilityChecker struct {
+	issues             []responses.AccessibilityIssue
+	headingLevel       int
+	hasElementLanguage bool
+}
+
+func (c *accessibilityChecker) addPageIssue(check responses.AccessibilityCheck, pageIndex int, message string) {
+	c.issues = append(c.issues, responses.AccessibilityIssue{
+		Check:   check,
+		Status:  responses.AccessibilityStatusFail,
+		Page:    &pageIndex,
+		Message: message,
+	})
+}
+
+func (c *accessibilityChecker) addElementIssue(check responses.AccessibilityCheck, pageIndex int, element responses.StructElement, path []int, message string) {
+	c.issues = append(c.issues, responses.AccessibilityIssue{
+		Check:       check,
+		Status:      responses.AccessibilityStatusFail,
+		Page:        &pageIndex,
+		ElementType: element.Type,
+		ElementPath: append([]int{}, path...),
+		Message:     message,
+	})
+}
+
+// checkElement checks an element and its children, and collects the marked
+// content IDs of the content that is in the structure tree.
+func (c *accessibilityChecker) checkElement(pageIndex int, element responses.StructElement, path []int, markedContentIDs map[int]bool) {
+	for _, markedContentID := range element.MarkedContentIDs {
+		markedContentIDs[markedContentID] = true
+	}
+
+	if element.Lang != nil && strings.TrimSpace(*element.Lang) != "" {
+		c.hasElementLanguage = true
+	}
+
+	switch {
+	case element.Type == "Figure":
+		// The replacement text is also an alternative for a figure, like
+		// for a figure of text.
+		if !hasStructElementText(element.AltText) && !hasStructElementText(element.ActualText) {
+			c.addElementIssue(responses.AccessibilityCheckFigureAltText, pageIndex, element, path, "the figure doesn't have alternate text")
+		}
+	case element.Type == "Table":
+		if !hasStructElementType(element.Children, "TH") {
+			c.addElementIssue(responses.AccessibilityCheckTableHeaders, pageIndex, element, path, "the table doesn't have header cells")
+		}
+	case isHeadingType(element.Type):
+		level, _ := strconv.Atoi(element.Type[1:])
+		if c.headingLevel == 0 && level != 1 {
+			c.addElementIssue(responses.AccessibilityCheckHeadingOrder, pageIndex, element, path, fmt.Sprintf("the first heading is %s instead of H1", element.Type))
+		} else if c.headingLevel != 0 && level > c.headingLevel+1 {
+			c.addElementIssue(responses.AccessibilityCheckHeadingOrder, pageIndex, element, path, fmt.Sprintf("heading %s follows heading H%d, which skips a level", element.Type, c.headingLevel))
+		}
+		c.headingLevel = level
+	}
+
+	for i := range element.Children {
+		c.checkElement(pageIndex, element.Children[i], append(path, i), markedContentIDs)
+	}
+}
+
+func hasStructElementText(value *string) bool {
+	return value != nil && strings.TrimSpace(*value) != ""
+}
+
+// hasStructElementType returns whether one of the elements or their children
+// has the given type.
+func hasStructElementType(elements []responses.StructElement, elementType string) bool {
+	for i := range elements {
+		if elements[i].Type == elementType || hasStructElementType(elements[i].Children, elementType) {
+			return true
+		}
+	}
+	return false
+}
+
+// isHeadingType returns whether the type is a numbered heading, H1 to H6.
+func isHeadingType(elementType string) bool {
+	return len(elementType) == 2 && elementType[0] == 'H' && elementType[1] >= '1' && elementType[1] <= '6'
+}
+
+// countUntaggedPageObjects returns the amount of page objects that are not
+// marked as content of the structure tree or as an artifact. The objects
+// inside form objects are counted, unless the form object itself is marked.
+func (p *PdfiumImplementation) countUntaggedPageObjects(page requests.Page, markedContentIDs map[int]bool) (int, error) {
+	objectCount, err := p.FPDFPage_CountObjects(&requests.FPDFPage_CountObjects{
+		Page: page,
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	untaggedObjects := 0
+	for i := 0; i < objectCount.Count; i++ {
+		object, err := p.FPDFPage_GetObject(&requests.FPDFPage_GetObject{
+			Page:  page,
+			Index: i,
+		})
+		if err != nil {
+			return 0, err
+		}
+
+		count, err := p.countUntaggedObjects(object.PageObject, markedContentIDs)
+		if err != nil {
+			return 0, err
+		}
+
+		untaggedObjects += count
+	}
+
+	return untaggedObjects, nil
+}
+
+func (p *PdfiumImplementation) countUntaggedObjects(pageObject references.FPDF_PAGEOBJECT, markedContentIDs map[int]bool) (int, error) {
+	isTagged, err := p.isTaggedPageObject(pageObject, markedContentIDs)
+	if err != nil {
+		return 0, err
+	}
+
+	if isTagged {
+		return 0, nil
+	}
+
+	objectType, err := p.FPDFPageObj_GetType(&requests.FPDFPageObj_GetType{
+		PageObject: pageObject,
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	if objectType.Type != enums.FPDF_PAGEOBJ_FORM {
+		return 1, nil
+	}
+
+	objectCount, err := p.FPDFFormObj_CountObjects(&requests.FPDFFormObj_CountObjects{
+		PageObject: pageObject,
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	untaggedObjects := 0
+	for i := 0; i < objectCount.Count; i++ {
+		object, err := p.FPDFFormObj_GetObject(&requests.FPDFFormObj_GetObject{
+			PageObject: pageObject,
+			Index:      uint64(i),
+		})
+		if err != nil {
+			return 0, err
+		}
+
+		count, err := p.countUntaggedObjects(object.PageObject, markedContentIDs)
+		if err != nil {
+			return 0, err
+		}
+
+		untaggedObjects += count
+	}
+
+	return untaggedObjects, nil
+}
+
+// isTaggedPageObject returns whether a page object is marked as an artifact
+// or has a marked content ID that is in the structure tree.
+func (p *PdfiumImplementation) isTaggedPageObject(pageObject references.FPDF_PAGEOBJECT, markedContentIDs map[int]bool) (bool, error) {
+	markCount, err := p.FPDFPageObj_CountMarks(&requests.FPDFPageObj_CountMarks{
+		PageObject: pageObject,
+	})
+	if err != nil {
+		return false, err
+	}
+
+	for i := 0; i < markCount.Count; i++ {
+		mark, err := p.FPDFPageObj_GetMark(&requests.FPDFPageObj_GetMark{
+			PageObject: pageObject,
+			Index:      uint64(i),
+		})
+		if err != nil {
+			return false, err
+		}
+
+		markName, err := p.FPDFPageObjMark_GetName(&requests.FPDFPageObjMark_GetName{
+			PageObjectMark: mark.Mark,
+		})
+		if err == nil && markName.Name == "Artifact" {
+			return true, nil
+		}
+
+		// PDFium gives an error when the mark doesn't have a marked content
+		// ID.
+		markedContentID, err := p.FPDFPageObjMark_GetParamIntValue(&requests.FPDFPageObjMark_GetParamIntValue{
+			PageObjectMark: mark.Mark,
+			Key:            "MCID",
+		})
+		if err == nil && markedContentIDs[markedContentID.Value] {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// accessibilityRule returns the result of a check from the issues that were
+// found for it.
+func accessibilityRule(check responses.AccessibilityCheck, issues []responses.AccessibilityIssue) responses.AccessibilityRule {
+	rule := responses.AccessibilityRule{
+		Check:  check,
+		Status: responses.AccessibilityStatusPass,
+	}
+
+	pageIndexes := []int{}
+	for _, issue := range issues {
+		if issue.Check != check {
+			continue
+		}
+
+		if rule.Status != responses.AccessibilityStatusFail {
+			rule.Status = issue.Status
+		}
+
+		if issue.Page == nil {
+			// Issues about the document only happen once.
+			rule.Message = issue.Message
+		} else if len(pageIndexes) == 0 || pageIndexes[len(pageIndexes)-1] != *issue.Page {
+			pageIndexes = append(pageIndexes, *issue.Page)
+		}
+	}
+
+	if len(pageIndexes) == 0 {
+		return rule
+	}
+
+	switch check {
+	case responses.AccessibilityCheckUntaggedContent:
+		rule.Message = fmt.Sprintf("the content on pages %s is not all tagged or marked as an artifact", formatPageIndexes(pageIndexes))
+	case responses.AccessibilityCheckFigureAltText:
+		rule.Message = fmt.Sprintf("figures on pages %s don't have alternate text", formatPageIndexes(pageIndexes))
+	case responses.AccessibilityCheckHeadingOrder:
+		rule.Message = fmt.Sprintf("headings on pages %s skip a level", formatPageIndexes(pageIndexes))
+	case responses.AccessibilityCheckTableHeaders:
+		rule.Message = fmt.Sprintf("tables on pages %s don't have header cells", formatPageIndexes(pageIndexes))
+	}
+
+	return rule
+}
@@ -2,8 +2,10 @@ package implementation_webassembly
 
 import (
 	"errors"
+	"fmt"
 	"unsafe"
 
+	"github.com/klippa-app/go-pdfium/internal/pdf_update"
 	"github.com/klippa-app/go-pdfium/references"
 	"github.com/klippa-app/go-pdfium/requests"
 	"github.com/klippa-app/go-pdfium/responses"
@@ -128,7 +130,7 @@ func (p *PdfiumImplementation) getBookMarkChildren(documentHandle *DocumentHandl
 			return nil, err
 		}
 		action := res[0]
-		if action == 0 {
+		if action != 0 {
 			actionHandle := p.registerAction(&action)
 			actionInfo, err := p.getActionInfo(actionHandle, documentHandle)
 			if err != nil {
@@ -162,3 +164,56 @@ func (p *PdfiumImplementation) getBookMarkChildren(documentHandle *DocumentHandl
 
 	return resp, nil
 }
+
+// SetBookmarks replaces the bookmarks (outline) of a document. PDFium has
+// no API for this, the bookmarks are written when the document is saved
+// with FPDF_SaveAsCopy or FPDF_SaveWithVersion.
+func (p *PdfiumImplementation) SetBookmarks(request *requests.SetBookmarks) (*responses.SetBookmarks, error) {
+	// Don't lock here yet, FPDF_GetPageCount does that for us.
+	pageCount, err := p.FPDF_GetPageCount(&requests.FPDF_GetPageCount{
+		Document: request.Document,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = validateBookmarks(request.Bookmarks, pageCount.PageCount)
+	if err != nil {
+		return nil, err
+	}
+
+	p.Lock()
+	defer p.Unlock()
+
+	documentHandle, err := p.getDocumentHandle(request.Document)
+	if err != nil {
+		return nil, err
+	}
+
+	if documentHandle.changes == nil {
+		documentHandle.changes = &pdf_update.Changes{}
+	}
+
+	documentHandle.changes.SetOutline(request.Bookmarks)
+
+	return &responses.SetBookmarks{}, nil
+}
+
+func validateBookmarks(bookmarks []requests.SetBookmarksBookmark, pageCount int) error {
+	for i := range bookmarks {
+		if bookmarks[i].Dest != nil && bookmarks[i].URI != nil {
+			return fmt.Errorf("bookmark %q can't have both a destination and a URI", bookmarks[i].Title)
+		}
+
+		if bookmarks[i].Dest != nil && (bookmarks[i].Dest.PageIndex < 0 || bookmarks[i].Dest.PageIndex >= pageCount) {
+			return fmt.Errorf("page index %d of bookmark %q is out of range, document has %d pages", bookmarks[i].Dest.PageIndex, bookmarks[i].Title, pageCount)
+		}
+
+		err := validateBookmarks(bookmarks[i].Children, pageCount)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
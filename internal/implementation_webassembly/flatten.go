@@ -0,0 +1,363 @@
+package implementation_webassembly
+
+import (
+	"errors"
+
+	"github.com/klippa-app/go-pdfium/enums"
+	"github.com/klippa-app/go-pdfium/internal/pdf_update"
+	"github.com/klippa-app/go-pdfium/references"
+	"github.com/klippa-app/go-pdfium/requests"
+	"github.com/klippa-app/go-pdfium/responses"
+)
+
+// FlattenDocument flattens the form fields, the annotations, or both, of the
+// given pages. PDFium always flattens all the annotations of a page, so to
+// only flatten some of them, a copy of the page with only those annotations
+// is flattened, and its flattened content is added to the page as a form
+// object.
+func (p *PdfiumImplementation) FlattenDocument(request *requests.FlattenDocument) (*responses.FlattenDocument, error) {
+	pageCount, err := p.FPDF_GetPageCount(&requests.FPDF_GetPageCount{
+		Document: request.Document,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	mode := request.Mode
+	if mode == "" {
+		mode = requests.FlattenDocumentModeAll
+	}
+
+	if mode != requests.FlattenDocumentModeAll && mode != requests.FlattenDocumentModeFormFields && mode != requests.FlattenDocumentModeAnnotations {
+		return nil, errors.New("invalid flatten mode given")
+	}
+
+	if request.Usage != requests.FPDFPage_FlattenUsageNormalDisplay && request.Usage != requests.FPDFPage_FlattenUsagePrint {
+		return nil, errors.New("invalid flatten usage given")
+	}
+
+	pages, err := parsePageRange(request.PageRange, pageCount.PageCount)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &responses.FlattenDocument{
+		Pages: []responses.FlattenDocumentPage{},
+	}
+
+	for _, pageIndex := range pages {
+		pageResult, err := p.flattenDocumentPage(request.Document, pageIndex, mode, request.Usage)
+		if err != nil {
+			return nil, err
+		}
+
+		resp.Pages = append(resp.Pages, *pageResult)
+	}
+
+	if request.Save {
+		savedDocument, err := p.FPDF_SaveAsCopy(&requests.FPDF_SaveAsCopy{
+			Document: request.Document,
+			FilePath: request.FilePath,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		resp.FileBytes = savedDocument.FileBytes
+		resp.FilePath = savedDocument.FilePath
+	}
+
+	return resp, nil
+}
+
+// flattenDocumentPage flattens the annotations of a page that match the mode.
+func (p *PdfiumImplementation) flattenDocumentPage(document references.FPDF_DOCUMENT, pageIndex int, mode requests.FlattenDocumentMode, usage requests.FPDFPage_FlattenUsage) (*responses.FlattenDocumentPage, error) {
+	page := requests.Page{
+		ByIndex: &requests.PageByIndex{
+			Document: document,
+			Index:    pageIndex,
+		},
+	}
+
+	pageResult := &responses.FlattenDocumentPage{
+		Page: pageIndex,
+	}
+
+	flatten := []int{}
+	keep := []int{}
+	if mode != requests.FlattenDocumentModeAll {
+		subtypes, err := p.getPageAnnotationSubtypes(page)
+		if err != nil {
+			return nil, err
+		}
+
+		for i, subtype := range subtypes {
+			if (subtype == enums.FPDF_ANNOT_SUBTYPE_WIDGET) == (mode == requests.FlattenDocumentModeFormFields) {
+				flatten = append(flatten, i)
+			} else {
+				keep = append(keep, i)
+			}
+		}
+
+		if len(flatten) == 0 {
+			pageResult.Result = responses.FPDFPage_FlattenResultNothingToDo
+			pageResult.Kept = len(keep)
+			return pageResult, nil
+		}
+	}
+
+	// When all the annotations of the page are flattened, PDFium can do it
+	// directly on the page.
+	if len(keep) == 0 {
+		flattenResult, err := p.FPDFPage_Flatten(&requests.FPDFPage_Flatten{
+			Page:  page,
+			Usage: usage,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		pageResult.Result = flattenResult.Result
+		return pageResult, nil
+	}
+
+	flattenResult, err := p.flattenPageAnnotations(document, pageIndex, keep, usage)
+	if err != nil {
+		return nil, err
+	}
+
+	pageResult.Result = flattenResult
+	pageResult.Kept = len(keep)
+
+	if flattenResult != responses.FPDFPage_FlattenResultSuccess {
+		return pageResult, nil
+	}
+
+	// The flattened annotations are part of the content now, remove them
+	// from the page. Remove from the back so the indexes stay valid.
+	for i := len(flatten) - 1; i >= 0; i-- {
+		_, err = p.FPDFPage_RemoveAnnot(&requests.FPDFPage_RemoveAnnot{
+			Page:  page,
+			Index: flatten[i],
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return pageResult, nil
+}
+
+// getPageAnnotationSubtypes returns the subtypes of the annotations of a page
+// by annotation index.
+func (p *PdfiumImplementation) getPageAnnotationSubtypes(page requests.Page) ([]enums.FPDF_ANNOTATION_SUBTYPE, error) {
+	annotationCount, err := p.FPDFPage_GetAnnotCount(&requests.FPDFPage_GetAnnotCount{
+		Page: page,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	subtypes := make([]enums.FPDF_ANNOTATION_SUBTYPE, annotationCount.Count)
+	for i := 0; i < annotationCount.Count; i++ {
+		annotation, err := p.FPDFPage_GetAnnot(&requests.FPDFPage_GetAnnot{
+			Page:  page,
+			Index: i,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		subtype, err := p.FPDFAnnot_GetSubtype(&requests.FPDFAnnot_GetSubtype{
+			Annotation: annotation.Annotation,
+		})
+		p.FPDFPage_CloseAnnot(&requests.FPDFPage_CloseAnnot{
+			Annotation: annotation.Annotation,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		subtypes[i] = subtype.Subtype
+	}
+
+	return subtypes, nil
+}
+
+// flattenPageAnnotations flattens the annotations of a page, except the ones
+// at the given indexes, into a form object that is added to the page. The
+// page is copied to the end of the document to be flattened there, the copy
+// is made in the same document because the form object keeps using the
+// objects of the document that it was made from.
+func (p *PdfiumImplementation) flattenPageAnnotations(document references.FPDF_DOCUMENT, pageIndex int, keep []int, usage requests.FPDFPage_FlattenUsage) (responses.FPDFPage_FlattenResult, error) {
+	pageCount, err := p.FPDF_GetPageCount(&requests.FPDF_GetPageCount{
+		Document: document,
+	})
+	if err != nil {
+		return responses.FPDFPage_FlattenResultFail, err
+	}
+
+	copyIndex := pageCount.PageCount
+	_, err = p.FPDF_ImportPagesByIndex(&requests.FPDF_ImportPagesByIndex{
+		Source:      document,
+		Destination: document,
+		PageIndices: []int{pageIndex},
+		Index:       copyIndex,
+	})
+	if err != nil {
+		return responses.FPDFPage_FlattenResultFail, err
+	}
+
+	result, err := p.flattenPageCopy(document, pageIndex, copyIndex, keep, usage)
+
+	_, deleteErr := p.FPDFPage_Delete(&requests.FPDFPage_Delete{
+		Document:  document,
+		PageIndex: copyIndex,
+	})
+	if err != nil {
+		return responses.FPDFPage_FlattenResultFail, err
+	}
+	if deleteErr != nil {
+		return responses.FPDFPage_FlattenResultFail, deleteErr
+	}
+
+	// The objects of the copy are still in the document, they are only gone
+	// from the file when it is written without the unused objects. That is
+	// not done for incremental saves, which keep the original file.
+	p.Lock()
+	defer p.Unlock()
+
+	documentHandle, err := p.getDocumentHandle(document)
+	if err != nil {
+		return responses.FPDFPage_FlattenResultFail, err
+	}
+
+	if documentHandle.changes == nil {
+		documentHandle.changes = &pdf_update.Changes{}
+	}
+
+	documentHandle.changes.RemoveUnusedObjects()
+
+	return result, nil
+}
+
+// flattenPageCopy removes the page content and the annotations to keep from
+// the copy of a page, so that flattening the copy results in only the
+// content of the flattened annotations, and adds that content to the page as
+// a form object.
+func (p *PdfiumImplementation) flattenPageCopy(document references.FPDF_DOCUMENT, pageIndex int, copyIndex int, keep []int, usage requests.FPDFPage_FlattenUsage) (responses.FPDFPage_FlattenResult, error) {
+	copyPage := requests.Page{
+		ByIndex: &requests.PageByIndex{
+			Document: document,
+			Index:    copyIndex,
+		},
+	}
+
+	// Remove from the back so the indexes stay valid.
+	for i := len(keep) - 1; i >= 0; i-- {
+		_, err := p.FPDFPage_RemoveAnnot(&requests.FPDFPage_RemoveAnnot{
+			Page:  copyPage,
+			Index: keep[i],
+		})
+		if err != nil {
+			return responses.FPDFPage_FlattenResultFail, err
+		}
+	}
+
+	objectCount, err := p.FPDFPage_CountObjects(&requests.FPDFPage_CountObjects{
+		Page: copyPage,
+	})
+	if err != nil {
+		return responses.FPDFPage_FlattenResultFail, err
+	}
+
+	for i := objectCount.Count - 1; i >= 0; i-- {
+		pageObject, err := p.FPDFPage_GetObject(&requests.FPDFPage_GetObject{
+			Page:  copyPage,
+			Index: i,
+		})
+		if err != nil {
+			return responses.FPDFPage_FlattenResultFail, err
+		}
+
+		_, err = p.FPDFPage_RemoveObject(&requests.FPDFPage_RemoveObject{
+			Page:       copyPage,
+			PageObject: pageObject.PageObject,
+		})
+		if err != nil {
+			return responses.FPDFPage_FlattenResultFail, err
+		}
+
+		_, err = p.FPDFPageObj_Destroy(&requests.FPDFPageObj_Destroy{
+			PageObject: pageObject.PageObject,
+		})
+		if err != nil {
+			return responses.FPDFPage_FlattenResultFail, err
+		}
+	}
+
+	_, err = p.FPDFPage_GenerateContent(&requests.FPDFPage_GenerateContent{
+		Page: copyPage,
+	})
+	if err != nil {
+		return responses.FPDFPage_FlattenResultFail, err
+	}
+
+	flattenResult, err := p.FPDFPage_Flatten(&requests.FPDFPage_Flatten{
+		Page:  copyPage,
+		Usage: usage,
+	})
+	if err != nil {
+		return responses.FPDFPage_FlattenResultFail, err
+	}
+
+	if flattenResult.Result != responses.FPDFPage_FlattenResultSuccess {
+		return flattenResult.Result, nil
+	}
+
+	xObject, err := p.FPDF_NewXObjectFromPage(&requests.FPDF_NewXObjectFromPage{
+		Source:          document,
+		Destination:     document,
+		SourcePageIndex: copyIndex,
+	})
+	if err != nil {
+		return responses.FPDFPage_FlattenResultFail, err
+	}
+	defer p.FPDF_CloseXObject(&requests.FPDF_CloseXObject{
+		XObject: xObject.XObject,
+	})
+
+	formObject, err := p.FPDF_NewFormObjectFromXObject(&requests.FPDF_NewFormObjectFromXObject{
+		XObject: xObject.XObject,
+	})
+	if err != nil {
+		return responses.FPDFPage_FlattenResultFail, err
+	}
+
+	page := requests.Page{
+		ByIndex: &requests.PageByIndex{
+			Document: document,
+			Index:    pageIndex,
+		},
+	}
+
+	_, err = p.FPDFPage_InsertObject(&requests.FPDFPage_InsertObject{
+		Page:       page,
+		PageObject: formObject.PageObject,
+	})
+	if err != nil {
+		p.FPDFPageObj_Destroy(&requests.FPDFPageObj_Destroy{
+			PageObject: formObject.PageObject,
+		})
+		return responses.FPDFPage_FlattenResultFail, err
+	}
+
+	_, err = p.FPDFPage_GenerateContent(&requests.FPDFPage_GenerateContent{
+		Page: page,
+	})
+	if err != nil {
+		return responses.FPDFPage_FlattenResultFail, err
+	}
+
+	return responses.FPDFPage_FlattenResultSuccess, nil
+}
@@ -16,3 +16,17 @@ func (p *PdfiumImplementation) registerFont(pageObject *uint64) *FontHandle {
 
 	return handle
 }
+
+// getFontPointer returns the pointer of the font in PDFium. PDFium shares
+// the font objects within a document, so the pointer identifies a font.
+func (p *PdfiumImplementation) getFontPointer(font references.FPDF_FONT) (uint64, error) {
+	p.Lock()
+	defer p.Unlock()
+
+	fontHandle, err := p.getFontHandle(font)
+	if err != nil {
+		return 0, err
+	}
+
+	return *fontHandle.handle, nil
+}
@@ -0,0 +1,906 @@
+package implementation_webassembly
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/klippa-app/go-pdfium/enums"
+	"github.com/klippa-app/go-pdfium/references"
+	"github.com/klippa-app/go-pdfium/requests"
+	"github.com/klippa-app/go-pdfium/responses"
+	"github.com/klippa-app/go-pdfium/structs"
+)
+
+// GetFormFields returns the interactive form (AcroForm) fields of a
+// document, with their value, options and widgets. The widgets of a field
+// are grouped by the fully qualified name of the field.
+// Experimental API.
+func (p *PdfiumImplementation) GetFormFields(request *requests.GetFormFields) (*responses.GetFormFields, error) {
+	pageCount, err := p.FPDF_GetPageCount(&requests.FPDF_GetPageCount{
+		Document: request.Document,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	formHandle, err := p.initFormFillEnvironment(request.Document)
+	if err != nil {
+		return nil, err
+	}
+	defer p.FPDFDOC_ExitFormFillEnvironment(&requests.FPDFDOC_ExitFormFillEnvironment{
+		FormHandle: formHandle,
+	})
+
+	fields := []responses.FormField{}
+	fieldIndexes := map[string]int{}
+	for pageIndex := 0; pageIndex < pageCount.PageCount; pageIndex++ {
+		page := requests.Page{
+			ByIndex: &requests.PageByIndex{
+				Document: request.Document,
+				Index:    pageIndex,
+			},
+		}
+
+		fields, err = p.appendPageFormFields(fields, fieldIndexes, formHandle, page)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &responses.GetFormFields{
+		Fields: fields,
+	}, nil
+}
+
+// appendPageFormFields adds the widget annotations of a page to their
+// fields.
+func (p *PdfiumImplementation) appendPageFormFields(fields []responses.FormField, fieldIndexes map[string]int, formHandle references.FPDF_FORMHANDLE, page requests.Page) ([]responses.FormField, error) {
+	// The form controls, which hold the checked state and the export value,
+	// are only available after the page has been loaded in the form.
+	_, err := p.FORM_OnAfterLoadPage(&requests.FORM_OnAfterLoadPage{
+		Page:       page,
+		FormHandle: formHandle,
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer p.FORM_OnBeforeClosePage(&requests.FORM_OnBeforeClosePage{
+		Page:       page,
+		FormHandle: formHandle,
+	})
+
+	annotationCount, err := p.FPDFPage_GetAnnotCount(&requests.FPDFPage_GetAnnotCount{
+		Page: page,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for i := 0; i < annotationCount.Count; i++ {
+		fields, err = p.appendFormField(fields, fieldIndexes, formHandle, page, i)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return fields, nil
+}
+
+// appendFormField adds the widget annotation at the given index to its
+// field, the field is added when it's the first widget of the field.
+func (p *PdfiumImplementation) appendFormField(fields []responses.FormField, fieldIndexes map[string]int, formHandle references.FPDF_FORMHANDLE, page requests.Page, index int) ([]responses.FormField, error) {
+	annotation, err := p.FPDFPage_GetAnnot(&requests.FPDFPage_GetAnnot{
+		Page:  page,
+		Index: index,
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer p.FPDFPage_CloseAnnot(&requests.FPDFPage_CloseAnnot{
+		Annotation: annotation.Annotation,
+	})
+
+	subtype, err := p.FPDFAnnot_GetSubtype(&requests.FPDFAnnot_GetSubtype{
+		Annotation: annotation.Annotation,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if subtype.Subtype != enums.FPDF_ANNOT_SUBTYPE_WIDGET {
+		return fields, nil
+	}
+
+	// A widget that isn't part of the form has no field name.
+	fieldName, err := p.FPDFAnnot_GetFormFieldName(&requests.FPDFAnnot_GetFormFieldName{
+		FormHandle: formHandle,
+		Annotation: annotation.Annotation,
+	})
+	if err != nil {
+		return fields, nil
+	}
+
+	fieldIndex, ok := fieldIndexes[fieldName.FormFieldName]
+	if !ok {
+		field, err := p.getFormField(formHandle, annotation.Annotation, fieldName.FormFieldName)
+		if err != nil {
+			return nil, err
+		}
+
+		fields = append(fields, *field)
+		fieldIndex = len(fields) - 1
+		fieldIndexes[fieldName.FormFieldName] = fieldIndex
+	}
+
+	rect, err := p.FPDFAnnot_GetRect(&requests.FPDFAnnot_GetRect{
+		Annotation: annotation.Annotation,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	widget := responses.FormFieldWidget{
+		Page: page.ByIndex.Index,
+		Rect: rect.Rect,
+	}
+
+	fieldType := fields[fieldIndex].Type
+	if fieldType == enums.FPDF_FORMFIELD_TYPE_CHECKBOX || fieldType == enums.FPDF_FORMFIELD_TYPE_RADIOBUTTON {
+		// A widget without appearance for its on state has no export value.
+		exportValue, err := p.FPDFAnnot_GetFormFieldExportValue(&requests.FPDFAnnot_GetFormFieldExportValue{
+			FormHandle: formHandle,
+			Annotation: annotation.Annotation,
+		})
+		if err == nil {
+			widget.ExportValue = exportValue.Value
+			if !containsString(fields[fieldIndex].ExportValues, exportValue.Value) {
+				fields[fieldIndex].ExportValues = append(fields[fieldIndex].ExportValues, exportValue.Value)
+			}
+		}
+
+		isChecked, err := p.FPDFAnnot_IsChecked(&requests.FPDFAnnot_IsChecked{
+			FormHandle: formHandle,
+			Annotation: annotation.Annotation,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		widget.IsChecked = isChecked.IsChecked
+	}
+
+	fields[fieldIndex].Widgets = append(fields[fieldIndex].Widgets, widget)
+
+	return fields, nil
+}
+
+// getFormField returns the information of the field of a widget annotation,
+// without the widgets.
+func (p *PdfiumImplementation) getFormField(formHandle references.FPDF_FORMHANDLE, annotation references.FPDF_ANNOTATION, name string) (*responses.FormField, error) {
+	field := &responses.FormField{
+		Name:         name,
+		ExportValues: []string{},
+		Options:      []responses.FormFieldOption{},
+		Widgets:      []responses.FormFieldWidget{},
+	}
+
+	fieldType, err := p.FPDFAnnot_GetFormFieldType(&requests.FPDFAnnot_GetFormFieldType{
+		FormHandle: formHandle,
+		Annotation: annotation,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	field.Type = fieldType.FormFieldType
+
+	fieldFlags, err := p.FPDFAnnot_GetFormFieldFlags(&requests.FPDFAnnot_GetFormFieldFlags{
+		FormHandle: formHandle,
+		Annotation: annotation,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	field.Flags = fieldFlags.Flags
+
+	// The alternate name and the value are optional.
+	alternateName, err := p.FPDFAnnot_GetFormFieldAlternateName(&requests.FPDFAnnot_GetFormFieldAlternateName{
+		FormHandle: formHandle,
+		Annotation: annotation,
+	})
+	if err == nil {
+		field.AlternateName = alternateName.FormFieldAlternateName
+	}
+
+	value, err := p.FPDFAnnot_GetFormFieldValue(&requests.FPDFAnnot_GetFormFieldValue{
+		FormHandle: formHandle,
+		Annotation: annotation,
+	})
+	if err == nil {
+		field.Value = value.FormFieldValue
+	}
+
+	if field.Type == enums.FPDF_FORMFIELD_TYPE_COMBOBOX || field.Type == enums.FPDF_FORMFIELD_TYPE_LISTBOX {
+		optionCount, err := p.FPDFAnnot_GetOptionCount(&requests.FPDFAnnot_GetOptionCount{
+			FormHandle: formHandle,
+			Annotation: annotation,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		for i := 0; i < optionCount.OptionCount; i++ {
+			option := responses.FormFieldOption{}
+
+			// Options can have an empty label.
+			optionLabel, err := p.FPDFAnnot_GetOptionLabel(&requests.FPDFAnnot_GetOptionLabel{
+				FormHandle: formHandle,
+				Annotation: annotation,
+				Index:      i,
+			})
+			if err == nil {
+				option.Label = optionLabel.OptionLabel
+			}
+
+			isOptionSelected, err := p.FPDFAnnot_IsOptionSelected(&requests.FPDFAnnot_IsOptionSelected{
+				FormHandle: formHandle,
+				Annotation: annotation,
+				Index:      i,
+			})
+			if err != nil {
+				return nil, err
+			}
+
+			option.IsSelected = isOptionSelected.IsOptionSelected
+			field.Options = append(field.Options, option)
+		}
+	}
+
+	return field, nil
+}
+
+// FillFormFields fills the interactive form (AcroForm) fields of a document
+// by their fully qualified name. The fields are filled through the form fill
+// environment, like a user would, so the appearances of the fields are
+// regenerated. Values that don't fit the field, or that are changed by the
+// form, are rejected.
+// Experimental API.
+func (p *PdfiumImplementation) FillFormFields(request *requests.FillFormFields) (*responses.FillFormFields, error) {
+	pageCount, err := p.FPDF_GetPageCount(&requests.FPDF_GetPageCount{
+		Document: request.Document,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	formHandle, err := p.initFormFillEnvironment(request.Document)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &responses.FillFormFields{
+		Filled:   []string{},
+		NotFound: []string{},
+		Rejected: []responses.FillFormFieldsRejectedField{},
+	}
+
+	foundFields := map[string]bool{}
+	for pageIndex := 0; pageIndex < pageCount.PageCount; pageIndex++ {
+		page := requests.Page{
+			ByIndex: &requests.PageByIndex{
+				Document: request.Document,
+				Index:    pageIndex,
+			},
+		}
+
+		err = p.fillPageFormFields(resp, foundFields, formHandle, page, request.Values)
+		if err != nil {
+			p.FPDFDOC_ExitFormFillEnvironment(&requests.FPDFDOC_ExitFormFillEnvironment{
+				FormHandle: formHandle,
+			})
+			return nil, err
+		}
+	}
+
+	// The form fill environment has to be closed before flattening, because
+	// flattening removes the widgets from the pages.
+	_, err = p.FPDFDOC_ExitFormFillEnvironment(&requests.FPDFDOC_ExitFormFillEnvironment{
+		FormHandle: formHandle,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for name := range request.Values {
+		if !foundFields[name] {
+			resp.NotFound = append(resp.NotFound, name)
+		}
+	}
+	sort.Strings(resp.NotFound)
+
+	if request.Flatten {
+		for pageIndex := 0; pageIndex < pageCount.PageCount; pageIndex++ {
+			_, err = p.FPDFPage_Flatten(&requests.FPDFPage_Flatten{
+				Page: requests.Page{
+					ByIndex: &requests.PageByIndex{
+						Document: request.Document,
+						Index:    pageIndex,
+					},
+				},
+				Usage: requests.FPDFPage_FlattenUsageNormalDisplay,
+			})
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if request.Save {
+		savedDocument, err := p.FPDF_SaveAsCopy(&requests.FPDF_SaveAsCopy{
+			Document: request.Document,
+			FilePath: request.FilePath,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		resp.FileBytes = savedDocument.FileBytes
+		resp.FilePath = savedDocument.FilePath
+	}
+
+	return resp, nil
+}
+
+// fillPageFormFields fills the fields of which the first widget is on the
+// given page. Widgets of the same field on later pages are updated by the
+// form.
+func (p *PdfiumImplementation) fillPageFormFields(resp *responses.FillFormFields, foundFields map[string]bool, formHandle references.FPDF_FORMHANDLE, page requests.Page, values map[string]requests.FillFormFieldsValue) error {
+	_, err := p.FORM_OnAfterLoadPage(&requests.FORM_OnAfterLoadPage{
+		Page:       page,
+		FormHandle: formHandle,
+	})
+	if err != nil {
+		return err
+	}
+	defer p.FORM_OnBeforeClosePage(&requests.FORM_OnBeforeClosePage{
+		Page:       page,
+		FormHandle: formHandle,
+	})
+
+	annotationCount, err := p.FPDFPage_GetAnnotCount(&requests.FPDFPage_GetAnnotCount{
+		Page: page,
+	})
+	if err != nil {
+		return err
+	}
+
+	annotations := []references.FPDF_ANNOTATION{}
+	defer func() {
+		for i := range annotations {
+			p.FPDFPage_CloseAnnot(&requests.FPDFPage_CloseAnnot{
+				Annotation: annotations[i],
+			})
+		}
+	}()
+
+	fieldNames := []string{}
+	fieldWidgets := map[string][]references.FPDF_ANNOTATION{}
+	for i := 0; i < annotationCount.Count; i++ {
+		annotation, err := p.FPDFPage_GetAnnot(&requests.FPDFPage_GetAnnot{
+			Page:  page,
+			Index: i,
+		})
+		if err != nil {
+			return err
+		}
+
+		annotations = append(annotations, annotation.Annotation)
+
+		subtype, err := p.FPDFAnnot_GetSubtype(&requests.FPDFAnnot_GetSubtype{
+			Annotation: annotation.Annotation,
+		})
+		if err != nil {
+			return err
+		}
+
+		if subtype.Subtype != enums.FPDF_ANNOT_SUBTYPE_WIDGET {
+			continue
+		}
+
+		fieldName, err := p.FPDFAnnot_GetFormFieldName(&requests.FPDFAnnot_GetFormFieldName{
+			FormHandle: formHandle,
+			Annotation: annotation.Annotation,
+		})
+		if err != nil {
+			continue
+		}
+
+		name := fieldName.FormFieldName
+		if _, ok := values[name]; !ok || foundFields[name] {
+			continue
+		}
+
+		if _, ok := fieldWidgets[name]; !ok {
+			fieldNames = append(fieldNames, name)
+		}
+		fieldWidgets[name] = append(fieldWidgets[name], annotation.Annotation)
+	}
+
+	for _, name := range fieldNames {
+		foundFields[name] = true
+
+		reason, err := p.fillFormField(formHandle, page, fieldWidgets[name], values[name])
+		if err != nil {
+			return err
+		}
+
+		if reason != "" {
+			resp.Rejected = append(resp.Rejected, responses.FillFormFieldsRejectedField{
+				Name:   name,
+				Reason: reason,
+			})
+		} else {
+			resp.Filled = append(resp.Filled, name)
+		}
+	}
+
+	return nil
+}
+
+// fillFormField fills a field with the given value, it returns the reason
+// when the value is rejected.
+func (p *PdfiumImplementation) fillFormField(formHandle references.FPDF_FORMHANDLE, page requests.Page, widgets []references.FPDF_ANNOTATION, value requests.FillFormFieldsValue) (string, error) {
+	fieldType, err := p.FPDFAnnot_GetFormFieldType(&requests.FPDFAnnot_GetFormFieldType{
+		FormHandle: formHandle,
+		Annotation: widgets[0],
+	})
+	if err != nil {
+		return "", err
+	}
+
+	fieldFlags, err := p.FPDFAnnot_GetFormFieldFlags(&requests.FPDFAnnot_GetFormFieldFlags{
+		FormHandle: formHandle,
+		Annotation: widgets[0],
+	})
+	if err != nil {
+		return "", err
+	}
+
+	if fieldFlags.Flags&enums.FPDF_FORMFLAG_READONLY != 0 {
+		return "the field is read only", nil
+	}
+
+	// An empty list of selected options deselects all options.
+	hasText := value.Text != nil
+	hasChecked := value.Checked != nil || value.ExportValue != nil
+	hasSelected := value.Selected != nil
+
+	switch fieldType.FormFieldType {
+	case enums.FPDF_FORMFIELD_TYPE_TEXTFIELD:
+		if !hasText || hasChecked || hasSelected {
+			return "a text field can only be filled with text", nil
+		}
+
+		return p.fillFormFieldText(formHandle, page, widgets[0], *value.Text)
+	case enums.FPDF_FORMFIELD_TYPE_CHECKBOX, enums.FPDF_FORMFIELD_TYPE_RADIOBUTTON:
+		if !hasChecked || hasText || hasSelected {
+			return "a checkbox or radio button can only be filled with a checked state or an export value", nil
+		}
+
+		if fieldType.FormFieldType == enums.FPDF_FORMFIELD_TYPE_RADIOBUTTON && value.ExportValue == nil && (value.Checked == nil || *value.Checked) {
+			return "a radio button can only be checked with an export value", nil
+		}
+
+		return p.fillFormFieldCheckable(formHandle, page, fieldType.FormFieldType, widgets, value)
+	case enums.FPDF_FORMFIELD_TYPE_COMBOBOX, enums.FPDF_FORMFIELD_TYPE_LISTBOX:
+		if (!hasSelected && !hasText) || hasChecked {
+			return "a combobox or listbox can only be filled with selected options or text", nil
+		}
+
+		return p.fillFormFieldChoice(formHandle, page, widgets[0], fieldType.FormFieldType, fieldFlags.Flags, value)
+	}
+
+	return "the type of the field can't be filled", nil
+}
+
+func (p *PdfiumImplementation) fillFormFieldText(formHandle references.FPDF_FORMHANDLE, page requests.Page, annotation references.FPDF_ANNOTATION, text string) (string, error) {
+	previousValue := p.getFormFieldValue(formHandle, annotation)
+
+	err := p.setFormFieldText(formHandle, page, annotation, text)
+	if err != nil {
+		return "", err
+	}
+
+	// The form limits the value, for example to the maximum length. The
+	// previous value is put back, so that the changed value isn't saved.
+	value := p.getFormFieldValue(formHandle, annotation)
+	if value != text {
+		err = p.setFormFieldText(formHandle, page, annotation, previousValue)
+		if err != nil {
+			return "", err
+		}
+
+		return fmt.Sprintf("the value was changed to %q by the form", value), nil
+	}
+
+	return "", nil
+}
+
+// setFormFieldText replaces the text of a field and commits it.
+func (p *PdfiumImplementation) setFormFieldText(formHandle references.FPDF_FORMHANDLE, page requests.Page, annotation references.FPDF_ANNOTATION, text string) error {
+	_, err := p.FORM_SetFocusedAnnot(&requests.FORM_SetFocusedAnnot{
+		FormHandle: formHandle,
+		Annotation: annotation,
+	})
+	if err != nil {
+		return err
+	}
+
+	err = p.replaceFormFieldText(formHandle, page, text)
+	if err != nil {
+		return err
+	}
+
+	// Killing the focus commits the value to the field.
+	_, err = p.FORM_ForceToKillFocus(&requests.FORM_ForceToKillFocus{
+		FormHandle: formHandle,
+	})
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// replaceFormFieldText replaces the text of the focused field.
+func (p *PdfiumImplementation) replaceFormFieldText(formHandle references.FPDF_FORMHANDLE, page requests.Page, text string) error {
+	_, err := p.FORM_SelectAllText(&requests.FORM_SelectAllText{
+		FormHandle: formHandle,
+		Page:       page,
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = p.FORM_ReplaceSelection(&requests.FORM_ReplaceSelection{
+		FormHandle: formHandle,
+		Page:       page,
+		Text:       text,
+	})
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (p *PdfiumImplementation) fillFormFieldCheckable(formHandle references.FPDF_FORMHANDLE, page requests.Page, fieldType enums.FPDF_FORMFIELD_TYPE, widgets []references.FPDF_ANNOTATION, value requests.FillFormFieldsValue) (string, error) {
+	checked := value.Checked == nil || *value.Checked
+
+	// A radio button group without export value is unchecked by unchecking
+	// the widget that is checked.
+	targetWidgets := widgets[:1]
+	if value.ExportValue != nil {
+		targetWidgets = nil
+		for i := range widgets {
+			exportValue, err := p.FPDFAnnot_GetFormFieldExportValue(&requests.FPDFAnnot_GetFormFieldExportValue{
+				FormHandle: formHandle,
+				Annotation: widgets[i],
+			})
+			if err == nil && exportValue.Value == *value.ExportValue {
+				targetWidgets = widgets[i : i+1]
+				break
+			}
+		}
+
+		if targetWidgets == nil {
+			return fmt.Sprintf("the export value %q doesn't exist", *value.ExportValue), nil
+		}
+	} else if fieldType == enums.FPDF_FORMFIELD_TYPE_RADIOBUTTON {
+		targetWidgets = widgets
+	}
+
+	for _, widget := range targetWidgets {
+		reason, err := p.setFormFieldWidgetChecked(formHandle, page, widget, checked)
+		if err != nil || reason != "" {
+			return reason, err
+		}
+	}
+
+	return "", nil
+}
+
+// setFormFieldWidgetChecked checks or unchecks a checkbox or radio button
+// widget, it returns the reason when the form doesn't accept it.
+func (p *PdfiumImplementation) setFormFieldWidgetChecked(formHandle references.FPDF_FORMHANDLE, page requests.Page, widget references.FPDF_ANNOTATION, checked bool) (string, error) {
+	isChecked, err := p.FPDFAnnot_IsChecked(&requests.FPDFAnnot_IsChecked{
+		FormHandle: formHandle,
+		Annotation: widget,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	if isChecked.IsChecked == checked {
+		return "", nil
+	}
+
+	// Checkboxes and radio buttons are toggled by clicking them.
+	rect, err := p.FPDFAnnot_GetRect(&requests.FPDFAnnot_GetRect{
+		Annotation: widget,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	x := float64(rect.Rect.Left+rect.Rect.Right) / 2
+	y := float64(rect.Rect.Top+rect.Rect.Bottom) / 2
+
+	// The form decides whether it handles the click, the checked state is
+	// verified afterwards.
+	p.FORM_OnLButtonDown(&requests.FORM_OnLButtonDown{
+		FormHandle: formHandle,
+		Page:       page,
+		PageX:      x,
+		PageY:      y,
+	})
+	p.FORM_OnLButtonUp(&requests.FORM_OnLButtonUp{
+		FormHandle: formHandle,
+		Page:       page,
+		PageX:      x,
+		PageY:      y,
+	})
+
+	_, err = p.FORM_ForceToKillFocus(&requests.FORM_ForceToKillFocus{
+		FormHandle: formHandle,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	isChecked, err = p.FPDFAnnot_IsChecked(&requests.FPDFAnnot_IsChecked{
+		FormHandle: formHandle,
+		Annotation: widget,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	if isChecked.IsChecked != checked {
+		return "the checked state was not changed by the form", nil
+	}
+
+	return "", nil
+}
+
+func (p *PdfiumImplementation) fillFormFieldChoice(formHandle references.FPDF_FORMHANDLE, page requests.Page, annotation references.FPDF_ANNOTATION, fieldType enums.FPDF_FORMFIELD_TYPE, fieldFlags enums.FPDF_FORMFLAG, value requests.FillFormFieldsValue) (string, error) {
+	isComboBox := fieldType == enums.FPDF_FORMFIELD_TYPE_COMBOBOX
+	if isComboBox && len(value.Selected) > 1 {
+		return "a combobox can only have one option selected", nil
+	}
+
+	if isComboBox && value.Selected != nil && len(value.Selected) == 0 {
+		return "the options of a combobox can't be deselected", nil
+	}
+
+	if !isComboBox && len(value.Selected) > 1 && fieldFlags&enums.FPDF_FORMFLAG_CHOICE_MULTI_SELECT == 0 {
+		return "the listbox doesn't allow multiple selected options", nil
+	}
+
+	if value.Text != nil && (!isComboBox || fieldFlags&enums.FPDF_FORMFLAG_CHOICE_EDIT == 0) {
+		return "only an editable combobox can be filled with text", nil
+	}
+
+	optionCount, err := p.FPDFAnnot_GetOptionCount(&requests.FPDFAnnot_GetOptionCount{
+		FormHandle: formHandle,
+		Annotation: annotation,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	optionLabels := make([]string, optionCount.OptionCount)
+	for i := range optionLabels {
+		optionLabel, err := p.FPDFAnnot_GetOptionLabel(&requests.FPDFAnnot_GetOptionLabel{
+			FormHandle: formHandle,
+			Annotation: annotation,
+			Index:      i,
+		})
+		if err == nil {
+			optionLabels[i] = optionLabel.OptionLabel
+		}
+	}
+
+	for _, selected := range value.Selected {
+		if !containsString(optionLabels, selected) {
+			return fmt.Sprintf("the option %q doesn't exist", selected), nil
+		}
+	}
+
+	// The previous value is put back when the form changes the value, so
+	// that the changed value isn't saved.
+	previousValue := p.getFormFieldValue(formHandle, annotation)
+	previousSelected := make([]bool, len(optionLabels))
+	hasPreviousSelected := false
+	for i := range optionLabels {
+		isOptionSelected, err := p.FPDFAnnot_IsOptionSelected(&requests.FPDFAnnot_IsOptionSelected{
+			FormHandle: formHandle,
+			Annotation: annotation,
+			Index:      i,
+		})
+		if err != nil {
+			return "", err
+		}
+
+		previousSelected[i] = isOptionSelected.IsOptionSelected
+		hasPreviousSelected = hasPreviousSelected || previousSelected[i]
+	}
+
+	selected := make([]bool, len(optionLabels))
+	for i := range optionLabels {
+		selected[i] = containsString(value.Selected, optionLabels[i])
+	}
+
+	err = p.setFormFieldOptions(formHandle, page, annotation, isComboBox, selected, value.Text)
+	if err != nil {
+		return "", err
+	}
+
+	reason := ""
+	if value.Text != nil {
+		fieldValue := p.getFormFieldValue(formHandle, annotation)
+		if fieldValue != *value.Text {
+			reason = fmt.Sprintf("the value was changed to %q by the form", fieldValue)
+		}
+	} else {
+		for i := range optionLabels {
+			isOptionSelected, err := p.FPDFAnnot_IsOptionSelected(&requests.FPDFAnnot_IsOptionSelected{
+				FormHandle: formHandle,
+				Annotation: annotation,
+				Index:      i,
+			})
+			if err != nil {
+				return "", err
+			}
+
+			if isOptionSelected.IsOptionSelected != selected[i] {
+				reason = "the selected options were not accepted by the form"
+				break
+			}
+		}
+	}
+
+	if reason != "" {
+		// The text of an editable combobox doesn't have to be an option.
+		var previousText *string
+		if isComboBox && !hasPreviousSelected && fieldFlags&enums.FPDF_FORMFLAG_CHOICE_EDIT != 0 {
+			previousText = &previousValue
+		}
+
+		err = p.setFormFieldOptions(formHandle, page, annotation, isComboBox, previousSelected, previousText)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	return reason, nil
+}
+
+// setFormFieldOptions selects the options of a combobox or listbox, and
+// replaces the text of an editable combobox when text is given.
+func (p *PdfiumImplementation) setFormFieldOptions(formHandle references.FPDF_FORMHANDLE, page requests.Page, annotation references.FPDF_ANNOTATION, isComboBox bool, selected []bool, text *string) error {
+	_, err := p.FORM_SetFocusedAnnot(&requests.FORM_SetFocusedAnnot{
+		FormHandle: formHandle,
+		Annotation: annotation,
+	})
+	if err != nil {
+		return err
+	}
+
+	// Deselect the options first, selecting an option of a listbox that
+	// allows multiple selected options doesn't deselect the others.
+	if !isComboBox {
+		for i := range selected {
+			if selected[i] {
+				continue
+			}
+
+			_, err = p.FORM_SetIndexSelected(&requests.FORM_SetIndexSelected{
+				FormHandle: formHandle,
+				Page:       page,
+				Index:      i,
+				Selected:   false,
+			})
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	for i := range selected {
+		if !selected[i] {
+			continue
+		}
+
+		_, err = p.FORM_SetIndexSelected(&requests.FORM_SetIndexSelected{
+			FormHandle: formHandle,
+			Page:       page,
+			Index:      i,
+			Selected:   true,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	if text != nil {
+		err = p.replaceFormFieldText(formHandle, page, *text)
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err = p.FORM_ForceToKillFocus(&requests.FORM_ForceToKillFocus{
+		FormHandle: formHandle,
+	})
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// getFormFieldValue returns the value of a field, empty when it has none.
+func (p *PdfiumImplementation) getFormFieldValue(formHandle references.FPDF_FORMHANDLE, annotation references.FPDF_ANNOTATION) string {
+	value, err := p.FPDFAnnot_GetFormFieldValue(&requests.FPDFAnnot_GetFormFieldValue{
+		FormHandle: formHandle,
+		Annotation: annotation,
+	})
+	if err != nil {
+		return ""
+	}
+
+	return value.FormFieldValue
+}
+
+// initFormFillEnvironment initializes a form fill environment for helpers
+// that need a form handle, without user interaction. The form handle must be
+// closed with FPDFDOC_ExitFormFillEnvironment.
+func (p *PdfiumImplementation) initFormFillEnvironment(document references.FPDF_DOCUMENT) (references.FPDF_FORMHANDLE, error) {
+	formFillEnvironment, err := p.FPDFDOC_InitFormFillEnvironment(&requests.FPDFDOC_InitFormFillEnvironment{
+		Document: document,
+		FormFillInfo: structs.FPDF_FORMFILLINFO{
+			FFI_Invalidate: func(page references.FPDF_PAGE, left, top, right, bottom float64) {},
+			FFI_SetCursor:  func(cursorType enums.FXCT) {},
+			FFI_SetTimer: func(elapse int, timerFunc func(idEvent int)) int {
+				return 0
+			},
+			FFI_KillTimer: func(timerID int) {},
+			FFI_GetLocalTime: func() structs.FPDF_SYSTEMTIME {
+				return structs.FPDF_SYSTEMTIME{}
+			},
+			FFI_GetPage: func(document references.FPDF_DOCUMENT, index int) *references.FPDF_PAGE {
+				return nil
+			},
+			FFI_GetRotation: func(page references.FPDF_PAGE) enums.FPDF_PAGE_ROTATION {
+				return enums.FPDF_PAGE_ROTATION_NONE
+			},
+			FFI_ExecuteNamedAction: func(namedAction string) {},
+		},
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return formFillEnvironment.FormHandle, nil
+}
+
+func containsString(values []string, value string) bool {
+	for i := range values {
+		if values[i] == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,166 @@
+package implementation_webassembly
+
+import (
+	"sort"
+
+	"github.com/klippa-app/go-pdfium/enums"
+	"github.com/klippa-app/go-pdfium/internal/form_data"
+	"github.com/klippa-app/go-pdfium/requests"
+	"github.com/klippa-app/go-pdfium/responses"
+)
+
+// ExportFormData exports the values of the interactive form (AcroForm)
+// fields of a document as FDF or XFDF. Buttons, signatures and fields that
+// are marked to not be exported are not included.
+// Experimental API.
+func (p *PdfiumImplementation) ExportFormData(request *requests.ExportFormData) (*responses.ExportFormData, error) {
+	formFields, err := p.GetFormFields(&requests.GetFormFields{
+		Document: request.Document,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	formData := form_data.FormData{
+		FileName: request.FileName,
+		Fields:   []form_data.Field{},
+	}
+
+	for _, formField := range formFields.Fields {
+		if formField.Flags&enums.FPDF_FORMFLAG_NOEXPORT != 0 {
+			continue
+		}
+
+		field := form_data.Field{
+			Name:   formField.Name,
+			Values: []string{formField.Value},
+		}
+
+		switch formField.Type {
+		case enums.FPDF_FORMFIELD_TYPE_TEXTFIELD, enums.FPDF_FORMFIELD_TYPE_COMBOBOX:
+		case enums.FPDF_FORMFIELD_TYPE_CHECKBOX, enums.FPDF_FORMFIELD_TYPE_RADIOBUTTON:
+			field.IsState = true
+			if field.Values[0] == "" {
+				field.Values[0] = "Off"
+			}
+		case enums.FPDF_FORMFIELD_TYPE_LISTBOX:
+			// The value only contains one option of a listbox that
+			// allows multiple selected options.
+			field.Values = []string{}
+			for _, option := range formField.Options {
+				if option.IsSelected {
+					field.Values = append(field.Values, option.Label)
+				}
+			}
+		default:
+			continue
+		}
+
+		formData.Fields = append(formData.Fields, field)
+	}
+
+	data, err := form_data.Write(request.Format, formData)
+	if err != nil {
+		return nil, err
+	}
+
+	return &responses.ExportFormData{
+		Data: data,
+	}, nil
+}
+
+// ImportFormData imports FDF or XFDF form data into the interactive form
+// (AcroForm) fields of a document. The fields are filled with FillFormFields,
+// so the values are validated in the same way.
+// Experimental API.
+func (p *PdfiumImplementation) ImportFormData(request *requests.ImportFormData) (*responses.ImportFormData, error) {
+	formFields, err := p.GetFormFields(&requests.GetFormFields{
+		Document: request.Document,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	formData, err := form_data.Read(request.Format, request.Data)
+	if err != nil {
+		return nil, err
+	}
+
+	formFieldsByName := map[string]responses.FormField{}
+	for _, formField := range formFields.Fields {
+		formFieldsByName[formField.Name] = formField
+	}
+
+	resp := &responses.ImportFormData{
+		FileName: formData.FileName,
+		NotFound: []string{},
+	}
+
+	values := map[string]requests.FillFormFieldsValue{}
+	for _, field := range formData.Fields {
+		formField, ok := formFieldsByName[field.Name]
+		if !ok {
+			if !containsString(resp.NotFound, field.Name) {
+				resp.NotFound = append(resp.NotFound, field.Name)
+			}
+			continue
+		}
+
+		values[field.Name] = getFormDataFieldValue(formField, field.Values)
+	}
+
+	filledFormFields, err := p.FillFormFields(&requests.FillFormFields{
+		Document: request.Document,
+		Values:   values,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	resp.Filled = filledFormFields.Filled
+	resp.Rejected = filledFormFields.Rejected
+	resp.NotFound = append(resp.NotFound, filledFormFields.NotFound...)
+	sort.Strings(resp.NotFound)
+
+	return resp, nil
+}
+
+// getFormDataFieldValue converts the values of a field in the form data to
+// the value to fill the field with, depending on the type of the field.
+func getFormDataFieldValue(formField responses.FormField, values []string) requests.FillFormFieldsValue {
+	value := ""
+	if len(values) > 0 {
+		value = values[0]
+	}
+
+	switch formField.Type {
+	case enums.FPDF_FORMFIELD_TYPE_CHECKBOX, enums.FPDF_FORMFIELD_TYPE_RADIOBUTTON:
+		if value == "Off" || value == "" {
+			checked := false
+			return requests.FillFormFieldsValue{
+				Checked: &checked,
+			}
+		}
+
+		return requests.FillFormFieldsValue{
+			ExportValue: &value,
+		}
+	case enums.FPDF_FORMFIELD_TYPE_COMBOBOX:
+		for _, option := range formField.Options {
+			if option.Label == value {
+				return requests.FillFormFieldsValue{
+					Selected: []string{value},
+				}
+			}
+		}
+	case enums.FPDF_FORMFIELD_TYPE_LISTBOX:
+		// A listbox without values has no option selected.
+		return requests.FillFormFieldsValue{
+			Selected: append([]string{}, values...),
+		}
+	}
+
+	return requests.FillFormFieldsValue{
+		Text: &value,
+	}
+}
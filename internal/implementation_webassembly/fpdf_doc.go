@@ -473,42 +473,52 @@ func (p *PdfiumImplementation) FPDF_GetPageLabel(request *requests.FPDF_GetPageL
 		return nil, err
 	}
 
-	// First get the label length.
-	res, err := p.Module.ExportedFunction("FPDF_GetPageLabel").Call(p.Context, *documentHandle.handle, *(*uint64)(unsafe.Pointer(&request.Page)), 0, 0)
+	label, err := p.getPageLabel(documentHandle, request.Page)
 	if err != nil {
 		return nil, err
 	}
 
+	return &responses.FPDF_GetPageLabel{
+		Page:  request.Page,
+		Label: label,
+	}, nil
+}
+
+// getPageLabel returns the label of the page with the given index.
+func (p *PdfiumImplementation) getPageLabel(documentHandle *DocumentHandle, page int) (string, error) {
+	// First get the label length.
+	res, err := p.Module.ExportedFunction("FPDF_GetPageLabel").Call(p.Context, *documentHandle.handle, *(*uint64)(unsafe.Pointer(&page)), 0, 0)
+	if err != nil {
+		return "", err
+	}
+
 	labelSize := uint64(*(*int32)(unsafe.Pointer(&res[0])))
 	if labelSize == 0 {
-		return nil, errors.New("Could not get label")
+		return "", errors.New("Could not get label")
 	}
 
 	charDataPointer, err := p.ByteArrayPointer(labelSize, nil)
 	if err != nil {
-		return nil, err
+		return "", err
 	}
 	defer charDataPointer.Free()
 
-	res, err = p.Module.ExportedFunction("FPDF_GetPageLabel").Call(p.Context, *documentHandle.handle, *(*uint64)(unsafe.Pointer(&request.Page)), charDataPointer.Pointer, labelSize)
+	res, err = p.Module.ExportedFunction("FPDF_GetPageLabel").Call(p.Context, *documentHandle.handle, *(*uint64)(unsafe.Pointer(&page)), charDataPointer.Pointer, labelSize)
 	if err != nil {
-		return nil, err
+		return "", err
 	}
 
 	charData, err := charDataPointer.Value(false)
 	if err != nil {
-		return nil, err
+		return "", err
 	}
 
 	transformedText, err := p.transformUTF16LEToUTF8(charData)
 	if err != nil {
-		return nil, err
+		return "", err
 	}
 
-	return &responses.FPDF_GetPageLabel{
-		Page:  request.Page,
-		Label: transformedText,
-	}, nil
+	return transformedText, nil
 }
 
 // FPDFDest_GetLocationInPage returns the (x, y, zoom) location of dest in the destination page, if the
@@ -1053,7 +1063,7 @@ func (p *PdfiumImplementation) FPDFBookmark_GetCount(request *requests.FPDFBookm
 		return nil, err
 	}
 
-	res, err := p.Module.ExportedFunction("FPDFLink_CountQuadPoints").Call(p.Context, *bookmarkHandle.handle)
+	res, err := p.Module.ExportedFunction("FPDFBookmark_GetCount").Call(p.Context, *bookmarkHandle.handle)
 	if err != nil {
 		return nil, err
 	}
@@ -67,6 +67,18 @@ func (p *PdfiumImplementation) FPDFPage_Delete(request *requests.FPDFPage_Delete
 		return nil, err
 	}
 
+	// The page and the pages after it get another index, so the current
+	// page can't be reused by index anymore.
+	if documentHandle.currentPage != nil && documentHandle.currentPage.index >= request.PageIndex {
+		documentHandle.currentPage.Close(p)
+
+		// Cleanup refs.
+		delete(documentHandle.pageRefs, documentHandle.currentPage.nativeRef)
+		delete(p.pageRefs, documentHandle.currentPage.nativeRef)
+
+		documentHandle.currentPage = nil
+	}
+
 	_, err = p.Module.ExportedFunction("FPDFPage_Delete").Call(p.Context, *documentHandle.handle, *(*uint64)(unsafe.Pointer(&request.PageIndex)))
 	if err != nil {
 		return nil, err
@@ -423,14 +435,23 @@ func (p *PdfiumImplementation) FPDFImageObj_LoadJpegFile(request *requests.FPDFI
 	p.Lock()
 	defer p.Unlock()
 
-	var pageHandle uint64
+	// PDFium expects an array of pages, we only support passing one page.
+	var pagesPointer uint64
 	if request.Page != nil {
 		loadedPage, err := p.loadPage(*request.Page)
 		if err != nil {
 			return nil, err
 		}
 
-		pageHandle = *loadedPage.handle
+		pagesPointer, err = p.Malloc(p.CSizePointer())
+		if err != nil {
+			return nil, err
+		}
+		defer p.Free(pagesPointer)
+
+		if !p.Module.Memory().WriteUint32Le(uint32(pagesPointer), uint32(*loadedPage.handle)) {
+			return nil, errors.New("could not write page handle to memory")
+		}
 	}
 
 	pageObjectHandle, err := p.getPageObjectHandle(request.ImageObject)
@@ -464,7 +485,7 @@ func (p *PdfiumImplementation) FPDFImageObj_LoadJpegFile(request *requests.FPDFI
 		return nil, err
 	}
 
-	res, err := p.Module.ExportedFunction("FPDFImageObj_LoadJpegFile").Call(p.Context, pageHandle, *(*uint64)(unsafe.Pointer(&request.Count)), *pageObjectHandle.handle, *fileAccessPointer)
+	res, err := p.Module.ExportedFunction("FPDFImageObj_LoadJpegFile").Call(p.Context, pagesPointer, *(*uint64)(unsafe.Pointer(&request.Count)), *pageObjectHandle.handle, *fileAccessPointer)
 	if err != nil {
 		return nil, err
 	}
@@ -489,14 +510,23 @@ func (p *PdfiumImplementation) FPDFImageObj_LoadJpegFileInline(request *requests
 	p.Lock()
 	defer p.Unlock()
 
-	var pageHandle uint64
+	// PDFium expects an array of pages, we only support passing one page.
+	var pagesPointer uint64
 	if request.Page != nil {
 		loadedPage, err := p.loadPage(*request.Page)
 		if err != nil {
 			return nil, err
 		}
 
-		pageHandle = *loadedPage.handle
+		pagesPointer, err = p.Malloc(p.CSizePointer())
+		if err != nil {
+			return nil, err
+		}
+		defer p.Free(pagesPointer)
+
+		if !p.Module.Memory().WriteUint32Le(uint32(pagesPointer), uint32(*loadedPage.handle)) {
+			return nil, errors.New("could not write page handle to memory")
+		}
 	}
 
 	pageObjectHandle, err := p.getPageObjectHandle(request.ImageObject)
@@ -530,7 +560,7 @@ func (p *PdfiumImplementation) FPDFImageObj_LoadJpegFileInline(request *requests
 		return nil, err
 	}
 
-	res, err := p.Module.ExportedFunction("FPDFImageObj_LoadJpegFileInline").Call(p.Context, pageHandle, *(*uint64)(unsafe.Pointer(&request.Count)), *pageObjectHandle.handle, *fileAccessPointer)
+	res, err := p.Module.ExportedFunction("FPDFImageObj_LoadJpegFileInline").Call(p.Context, pagesPointer, *(*uint64)(unsafe.Pointer(&request.Count)), *pageObjectHandle.handle, *fileAccessPointer)
 	if err != nil {
 		return nil, err
 	}
@@ -578,14 +608,23 @@ func (p *PdfiumImplementation) FPDFImageObj_SetBitmap(request *requests.FPDFImag
 	p.Lock()
 	defer p.Unlock()
 
-	var pageHandle uint64
+	// PDFium expects an array of pages, we only support passing one page.
+	var pagesPointer uint64
 	if request.Page != nil {
 		loadedPage, err := p.loadPage(*request.Page)
 		if err != nil {
 			return nil, err
 		}
 
-		pageHandle = *loadedPage.handle
+		pagesPointer, err = p.Malloc(p.CSizePointer())
+		if err != nil {
+			return nil, err
+		}
+		defer p.Free(pagesPointer)
+
+		if !p.Module.Memory().WriteUint32Le(uint32(pagesPointer), uint32(*loadedPage.handle)) {
+			return nil, errors.New("could not write page handle to memory")
+		}
 	}
 
 	imageObjectHandle, err := p.getPageObjectHandle(request.ImageObject)
@@ -598,7 +637,7 @@ func (p *PdfiumImplementation) FPDFImageObj_SetBitmap(request *requests.FPDFImag
 		return nil, err
 	}
 
-	res, err := p.Module.ExportedFunction("FPDFImageObj_SetBitmap").Call(p.Context, pageHandle, *(*uint64)(unsafe.Pointer(&request.Count)), *imageObjectHandle.handle, *bitmapHandle.handle)
+	res, err := p.Module.ExportedFunction("FPDFImageObj_SetBitmap").Call(p.Context, pagesPointer, *(*uint64)(unsafe.Pointer(&request.Count)), *imageObjectHandle.handle, *bitmapHandle.handle)
 	if err != nil {
 		return nil, err
 	}
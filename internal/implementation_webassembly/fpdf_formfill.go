@@ -40,6 +40,10 @@ func (f *FormFillInfo) FFI_Invalidate_CB(page uint32, left, top, right, bottom u
 }
 
 func (f *FormFillInfo) FFI_OutputSelectedRect(page uint32, left, top, right, bottom uint64) {
+	if f.FormFillInfo.FFI_OutputSelectedRect == nil {
+		return
+	}
+
 	var pageRef references.FPDF_PAGE
 	if pointerPageRef, ok := f.FormHandleHandle.pagePointers[uint64(page)]; ok {
 		pageRef = pointerPageRef
@@ -72,6 +76,10 @@ func (f *FormFillInfo) FFI_GetLocalTime() structs.FPDF_SYSTEMTIME {
 }
 
 func (f *FormFillInfo) FFI_OnChange() {
+	if f.FormFillInfo.FFI_OnChange == nil {
+		return
+	}
+
 	f.FormFillInfo.FFI_OnChange()
 }
 
@@ -149,6 +157,10 @@ func (f *FormFillInfo) FFI_ExecuteNamedAction(namedActionPointer uint64) {
 }
 
 func (f *FormFillInfo) FFI_SetTextFieldFocus(value uint32, valueLen uint32, isFocus uint32) {
+	if f.FormFillInfo.FFI_SetTextFieldFocus == nil {
+		return
+	}
+
 	size := valueLen * 2
 	data, success := f.Instance.Module.Memory().Read(value, size)
 	if !success {
@@ -164,6 +176,10 @@ func (f *FormFillInfo) FFI_SetTextFieldFocus(value uint32, valueLen uint32, isFo
 }
 
 func (f *FormFillInfo) FFI_DoURIAction(bsURI uint32) {
+	if f.FormFillInfo.FFI_DoURIAction == nil {
+		return
+	}
+
 	bsURIData := []byte{}
 	for {
 		data, success := f.Instance.Module.Memory().Read(bsURI, 1)
@@ -183,6 +199,10 @@ func (f *FormFillInfo) FFI_DoURIAction(bsURI uint32) {
 }
 
 func (f *FormFillInfo) FFI_DoGoToAction(nPageIndex, zoomMode, fPosArray, sizeofArray uint32) {
+	if f.FormFillInfo.FFI_DoGoToAction == nil {
+		return
+	}
+
 	pos := make([]float32, int(sizeofArray))
 	for i := range pos {
 		targetValue, success := f.Instance.Module.Memory().ReadFloat32Le(fPosArray + (uint32(i) * uint32(f.Instance.CSizeFloat())))
@@ -1437,18 +1457,12 @@ func (p *PdfiumImplementation) FORM_SetIndexSelected(request *requests.FORM_SetI
 		return nil, err
 	}
 
-	selected := int64(0)
+	selected := uint64(0)
 	if request.Selected {
-		selected = int64(1)
-	}
-
-	selectedPointer, err := p.IntPointer()
-	if err != nil {
-		return nil, err
+		selected = uint64(1)
 	}
 
-	p.Module.Memory().WriteUint64Le(uint32(selectedPointer.Pointer), api.EncodeI64(selected))
-	res, err := p.Module.ExportedFunction("FORM_SetIndexSelected").Call(p.Context, *formHandleHandle.handle, *pageHandle.handle, *(*uint64)(unsafe.Pointer(&request.Index)), selectedPointer.Pointer)
+	res, err := p.Module.ExportedFunction("FORM_SetIndexSelected").Call(p.Context, *formHandleHandle.handle, *pageHandle.handle, *(*uint64)(unsafe.Pointer(&request.Index)), selected)
 	if err != nil {
 		return nil, err
 	}
@@ -7,6 +7,7 @@ import (
 	"os"
 	"unsafe"
 
+	"github.com/klippa-app/go-pdfium/internal/pdf_update"
 	"github.com/klippa-app/go-pdfium/requests"
 	"github.com/klippa-app/go-pdfium/responses"
 )
@@ -22,6 +23,7 @@ func (p *PdfiumImplementation) FPDF_SaveAsCopy(request *requests.FPDF_SaveAsCopy
 		FilePath:    request.FilePath,
 		FileWriter:  request.FileWriter,
 		FileVersion: 0,
+		Encryption:  request.Encryption,
 	})
 
 	if err != nil {
@@ -44,6 +46,22 @@ func (p *PdfiumImplementation) FPDF_SaveWithVersion(request *requests.FPDF_SaveW
 		return nil, err
 	}
 
+	// Encryption can't be added with an incremental update, PDFium saves the
+	// document without security and the file is encrypted afterwards.
+	flags := request.Flags
+	if request.Encryption != nil {
+		if flags == requests.SaveFlagIncremental {
+			return nil, errors.New("encryption can't be combined with incremental saving")
+		}
+
+		err = pdf_update.ValidateEncryption(*request.Encryption)
+		if err != nil {
+			return nil, err
+		}
+
+		flags = requests.SaveFlagRemoveSecurity
+	}
+
 	res, err := p.Module.ExportedFunction("FPDF_FILEWRITE_Create").Call(p.Context)
 	if err != nil {
 		return nil, err
@@ -71,8 +89,24 @@ func (p *PdfiumImplementation) FPDF_SaveWithVersion(request *requests.FPDF_SaveW
 		currentWriter = fileBuf
 	}
 
+	// Unused objects can't be removed when saving incrementally.
+	changes := documentHandle.changes
+	if flags == requests.SaveFlagIncremental {
+		changes = changes.Incremental()
+	}
+
+	// When there are changes that PDFium has no API for, or when the file is
+	// encrypted, PDFium writes into a buffer first, the changes and the
+	// encryption are then applied on the way to the output.
+	var pdfiumBuf *bytes.Buffer
+	pdfiumWriter := currentWriter
+	if !changes.IsEmpty() || request.Encryption != nil {
+		pdfiumBuf = &bytes.Buffer{}
+		pdfiumWriter = pdfiumBuf
+	}
+
 	fileWriterRef := &FileWriterRef{
-		Writer:    currentWriter,
+		Writer:    pdfiumWriter,
 		FileWrite: &fileWriterPointer,
 	}
 
@@ -95,13 +129,13 @@ func (p *PdfiumImplementation) FPDF_SaveWithVersion(request *requests.FPDF_SaveW
 
 	var success int32
 	if request.FileVersion == 0 {
-		res, err = p.Module.ExportedFunction("FPDF_SaveAsCopy").Call(p.Context, *documentHandle.handle, fileWriterPointer, *(*uint64)(unsafe.Pointer(&request.Flags)))
+		res, err = p.Module.ExportedFunction("FPDF_SaveAsCopy").Call(p.Context, *documentHandle.handle, fileWriterPointer, *(*uint64)(unsafe.Pointer(&flags)))
 		if err != nil {
 			return nil, err
 		}
 		success = *(*int32)(unsafe.Pointer(&res[0]))
 	} else {
-		res, err = p.Module.ExportedFunction("FPDF_SaveWithVersion").Call(p.Context, *documentHandle.handle, fileWriterPointer, *(*uint64)(unsafe.Pointer(&request.Flags)), *(*uint64)(unsafe.Pointer(&request.FileVersion)))
+		res, err = p.Module.ExportedFunction("FPDF_SaveWithVersion").Call(p.Context, *documentHandle.handle, fileWriterPointer, *(*uint64)(unsafe.Pointer(&flags)), *(*uint64)(unsafe.Pointer(&request.FileVersion)))
 		if err != nil {
 			return nil, err
 		}
@@ -112,6 +146,27 @@ func (p *PdfiumImplementation) FPDF_SaveWithVersion(request *requests.FPDF_SaveW
 		return nil, errors.New("save of document failed")
 	}
 
+	if pdfiumBuf != nil {
+		if request.Encryption == nil {
+			err = changes.Apply(pdfiumBuf.Bytes(), currentWriter)
+		} else {
+			output := pdfiumBuf.Bytes()
+			if !changes.IsEmpty() {
+				changedBuf := &bytes.Buffer{}
+				err = changes.Apply(output, changedBuf)
+				if err != nil {
+					return nil, err
+				}
+				output = changedBuf.Bytes()
+			}
+
+			err = pdf_update.Encrypt(output, currentWriter, *request.Encryption)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	resp := &responses.FPDF_SaveWithVersion{}
 	if request.FilePath != nil {
 		resp.FilePath = request.FilePath
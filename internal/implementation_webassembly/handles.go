@@ -2,6 +2,7 @@ package implementation_webassembly
 
 import (
 	"errors"
+	"github.com/klippa-app/go-pdfium/internal/pdf_update"
 	"github.com/klippa-app/go-pdfium/references"
 )
 
@@ -12,6 +13,7 @@ type DocumentHandle struct {
 	nativeRef     references.FPDF_DOCUMENT // A string that is our reference inside the process. We need this to close the documents in DestroyLibrary.
 	dataPointer   *uint64
 	fileHandleRef *uint32
+	changes       *pdf_update.Changes // Changes that PDFium has no API for, these are applied when saving the document.
 
 	// lookup tables keeps track of the opened handles for this instance.
 	// we need this for handle lookups and in case of closing the document
@@ -12,9 +12,11 @@ import (
 	"io/ioutil"
 	"math"
 	"os"
+	"strconv"
 	"unsafe"
 
 	"github.com/klippa-app/go-pdfium/enums"
+	"github.com/klippa-app/go-pdfium/internal/image/image_grid"
 	"github.com/klippa-app/go-pdfium/internal/image/image_jpeg"
 	"github.com/klippa-app/go-pdfium/requests"
 	"github.com/klippa-app/go-pdfium/responses"
@@ -120,7 +122,7 @@ func (p *PdfiumImplementation) RenderPageInDPI(request *requests.RenderPageInDPI
 			PointToPixelRatio: pointToPixelRatio,
 			Flags:             request.RenderFlags,
 		},
-	}, 0)
+	}, 0, requests.RenderPagesLayout{})
 	if err != nil {
 		return nil, err
 	}
@@ -166,7 +168,7 @@ func (p *PdfiumImplementation) RenderPagesInDPI(request *requests.RenderPagesInD
 		}
 	}
 
-	result, cleanupFunc, err := p.renderPages(pages, request.Padding)
+	result, cleanupFunc, err := p.renderPages(pages, request.Padding, request.Layout)
 	if err != nil {
 		return nil, err
 	}
@@ -238,7 +240,7 @@ func (p *PdfiumImplementation) RenderPageInPixels(request *requests.RenderPageIn
 			PointToPixelRatio: ratio,
 			Flags:             request.RenderFlags,
 		},
-	}, 0)
+	}, 0, requests.RenderPagesLayout{})
 	if err != nil {
 		return nil, err
 	}
@@ -286,7 +288,7 @@ func (p *PdfiumImplementation) RenderPagesInPixels(request *requests.RenderPages
 		}
 	}
 
-	result, cleanupFunc, err := p.renderPages(pages, request.Padding)
+	result, cleanupFunc, err := p.renderPages(pages, request.Padding, request.Layout)
 	if err != nil {
 		return nil, err
 	}
@@ -306,24 +308,39 @@ type renderPage struct {
 }
 
 // renderPages renders a list of pages, the result is an image.
-func (p *PdfiumImplementation) renderPages(pages []renderPage, padding int) (*responses.RenderPages, func(), error) {
-	totalWidth := 0
-	totalHeight := 0
+func (p *PdfiumImplementation) renderPages(pages []renderPage, padding int, layout requests.RenderPagesLayout) (*responses.RenderPages, func(), error) {
+	columns, labelHeight, err := getRenderPagesGrid(len(pages), layout)
+	if err != nil {
+		return nil, nil, err
+	}
 
-	// First calculate the total image size
+	sizes := make([]image.Point, len(pages))
 	for i := range pages {
-		if pages[i].Width > totalWidth {
-			totalWidth = pages[i].Width
-		}
+		sizes[i] = image.Point{X: pages[i].Width, Y: pages[i].Height}
+	}
 
-		totalHeight += pages[i].Height
+	// Scale the pages down when the image would be larger than the maximum.
+	if layout.MaxWidth > 0 || layout.MaxHeight > 0 {
+		scale, err := image_grid.FitScale(sizes, columns, padding, labelHeight, layout.MaxWidth, layout.MaxHeight)
+		if err != nil {
+			return nil, nil, err
+		}
 
-		// Add padding between the renders
-		if i > 0 {
-			totalHeight += padding
+		if scale < 1 {
+			for i := range pages {
+				pages[i].Width = int(math.Max(math.Floor(float64(pages[i].Width)*scale), 1))
+				pages[i].Height = int(math.Max(math.Floor(float64(pages[i].Height)*scale), 1))
+				pages[i].PointToPixelRatio *= scale
+				sizes[i] = image.Point{X: pages[i].Width, Y: pages[i].Height}
+			}
 		}
 	}
 
+	// First calculate the total image size
+	positions, totalSize := image_grid.Layout(sizes, columns, padding, labelHeight)
+	totalWidth := totalSize.X
+	totalHeight := totalSize.Y
+
 	// We use a "fake" image here, we will replace the Pix later.
 	rect := image.Rect(0, 0, totalWidth, totalHeight)
 	img := &image.RGBA{
@@ -346,25 +363,39 @@ func (p *PdfiumImplementation) renderPages(pages []renderPage, padding int) (*re
 		p.Module.ExportedFunction("FPDFBitmap_Destroy").Call(p.Context, bitmap)
 	}
 
+	if layout.BackgroundColor != nil {
+		_, err = p.Module.ExportedFunction("FPDFBitmap_FillRect").Call(p.Context, bitmap, uint64(0), uint64(0), uint64(totalWidth), uint64(totalHeight), uint64(getRenderPagesBackgroundColor(layout.BackgroundColor)))
+		if err != nil {
+			releaseFunc()
+			return nil, nil, err
+		}
+	}
+
 	pagesInfo := make([]responses.RenderPagesPage, len(pages))
-	currentOffset := 0
 	for i := range pages {
 		// Keep track of page information in the total image.
 		pagesInfo[i] = responses.RenderPagesPage{
 			PointToPixelRatio: pages[i].PointToPixelRatio,
 			Width:             pages[i].Width,
 			Height:            pages[i].Height,
-			X:                 0,
-			Y:                 currentOffset,
+			X:                 positions[i].X,
+			Y:                 positions[i].Y,
 		}
-		index, hasTransparency, err := p.renderPage(bitmap, pages[i].Page, pages[i].Width, pages[i].Height, currentOffset, pages[i].Flags)
+		index, hasTransparency, err := p.renderPage(bitmap, pages[i].Page, pages[i].Width, pages[i].Height, positions[i].X, positions[i].Y, pages[i].Flags)
 		if err != nil {
 			releaseFunc()
 			return nil, nil, err
 		}
 		pagesInfo[i].Page = index
 		pagesInfo[i].HasTransparency = hasTransparency
-		currentOffset += pages[i].Height + padding
+
+		if layout.Label != requests.RenderPagesLayoutLabelNone {
+			pagesInfo[i].Label, err = p.getRenderPageLabel(pages[i].Page, index, layout.Label)
+			if err != nil {
+				releaseFunc()
+				return nil, nil, err
+			}
+		}
 	}
 
 	// The pointer to the first byte of the bitmap buffer.
@@ -383,6 +414,14 @@ func (p *PdfiumImplementation) renderPages(pages []renderPage, padding int) (*re
 
 	img.Pix = data
 
+	if layout.Label != requests.RenderPagesLayoutLabelNone {
+		labelColor := getRenderPagesLabelColor(layout.BackgroundColor)
+		for i := range pagesInfo {
+			labelRect := image.Rect(pagesInfo[i].X, pagesInfo[i].Y+pagesInfo[i].Height, pagesInfo[i].X+pagesInfo[i].Width, pagesInfo[i].Y+pagesInfo[i].Height+labelHeight)
+			image_grid.DrawLabel(img, labelRect, pagesInfo[i].Label, labelColor)
+		}
+	}
+
 	return &responses.RenderPages{
 		Image:  img,
 		Pages:  pagesInfo,
@@ -391,8 +430,36 @@ func (p *PdfiumImplementation) renderPages(pages []renderPage, padding int) (*re
 	}, releaseFunc, nil
 }
 
+// getRenderPageLabel returns the label to draw under a rendered page.
+func (p *PdfiumImplementation) getRenderPageLabel(page requests.Page, index int, label requests.RenderPagesLayoutLabel) (string, error) {
+	// We don't know the page number of pages that were loaded by reference.
+	if index == -1 {
+		return "", nil
+	}
+
+	if label == requests.RenderPagesLayoutLabelPageLabel {
+		pageHandle, err := p.loadPage(page)
+		if err != nil {
+			return "", err
+		}
+
+		documentHandle, err := p.getDocumentHandle(pageHandle.documentRef)
+		if err != nil {
+			return "", err
+		}
+
+		// Fall back to the page number when the page has no label.
+		pageLabel, err := p.getPageLabel(documentHandle, index)
+		if err == nil && pageLabel != "" {
+			return pageLabel, nil
+		}
+	}
+
+	return strconv.Itoa(index + 1), nil
+}
+
 // renderPage renders a specific page in a specific size on a bitmap.
-func (p *PdfiumImplementation) renderPage(bitmap uint64, page requests.Page, width, height, offset int, flags enums.FPDF_RENDER_FLAG) (int, bool, error) {
+func (p *PdfiumImplementation) renderPage(bitmap uint64, page requests.Page, width, height, x, y int, flags enums.FPDF_RENDER_FLAG) (int, bool, error) {
 	pageHandle, err := p.loadPage(page)
 	if err != nil {
 		return 0, false, err
@@ -417,7 +484,7 @@ func (p *PdfiumImplementation) renderPage(bitmap uint64, page requests.Page, wid
 	}
 
 	// Fill the page rect with the specified color.
-	_, err = p.Module.ExportedFunction("FPDFBitmap_FillRect").Call(p.Context, bitmap, uint64(0), uint64(offset), uint64(width), uint64(height), fillColor)
+	_, err = p.Module.ExportedFunction("FPDFBitmap_FillRect").Call(p.Context, bitmap, uint64(x), uint64(y), uint64(width), uint64(height), fillColor)
 	if err != nil {
 		return 0, false, err
 	}
@@ -425,7 +492,7 @@ func (p *PdfiumImplementation) renderPage(bitmap uint64, page requests.Page, wid
 	// Render the bitmap into the given external bitmap, write the bytes
 	// in reverse order so that BGRA becomes RGBA.
 	flags = flags | enums.FPDF_RENDER_FLAG_REVERSE_BYTE_ORDER
-	_, err = p.Module.ExportedFunction("FPDF_RenderPageBitmap").Call(p.Context, bitmap, *pageHandle.handle, uint64(0), uint64(offset), uint64(width), uint64(height), uint64(0), *(*uint64)(unsafe.Pointer(&flags)))
+	_, err = p.Module.ExportedFunction("FPDF_RenderPageBitmap").Call(p.Context, bitmap, *pageHandle.handle, uint64(x), uint64(y), uint64(width), uint64(height), uint64(0), *(*uint64)(unsafe.Pointer(&flags)))
 	if err != nil {
 		return 0, false, err
 	}
@@ -533,6 +600,20 @@ func (p *PdfiumImplementation) RenderToFile(request *requests.RenderToFile) (*re
 		return nil, errors.New("no render operation given")
 	}
 
+	imageBytes, imagePath, err := p.writeRenderedImage(renderedImage, hasTransparency, request)
+	if err != nil {
+		return nil, err
+	}
+
+	myResp.ImageBytes = imageBytes
+	myResp.ImagePath = imagePath
+
+	return myResp, nil
+}
+
+// writeRenderedImage encodes the rendered image in the output format of the
+// request and returns the bytes or writes it to the output file.
+func (p *PdfiumImplementation) writeRenderedImage(renderedImage *image.RGBA, hasTransparency bool, request *requests.RenderToFile) (*[]byte, string, error) {
 	var imgBuf bytes.Buffer
 
 	if request.OutputFormat == requests.RenderToFileOutputFormatJPG {
@@ -561,7 +642,7 @@ func (p *PdfiumImplementation) RenderToFile(request *requests.RenderToFile) (*re
 		for {
 			err := image_jpeg.Encode(&imgBuf, renderedImage, opt)
 			if err != nil {
-				return nil, err
+				return nil, "", err
 			}
 
 			if request.MaxFileSize == 0 || int64(imgBuf.Len()) < request.MaxFileSize {
@@ -571,7 +652,7 @@ func (p *PdfiumImplementation) RenderToFile(request *requests.RenderToFile) (*re
 			opt.Quality -= 10
 
 			if opt.Quality <= 45 {
-				return nil, errors.New("PDF image would exceed maximum filesize")
+				return nil, "", errors.New("PDF image would exceed maximum filesize")
 			}
 
 			imgBuf.Reset()
@@ -579,49 +660,47 @@ func (p *PdfiumImplementation) RenderToFile(request *requests.RenderToFile) (*re
 	} else if request.OutputFormat == requests.RenderToFileOutputFormatPNG {
 		err := png.Encode(&imgBuf, renderedImage)
 		if err != nil {
-			return nil, err
+			return nil, "", err
 		}
 
 		if request.MaxFileSize != 0 && int64(imgBuf.Len()) > request.MaxFileSize {
-			return nil, errors.New("PDF image would exceed maximum filesize")
+			return nil, "", errors.New("PDF image would exceed maximum filesize")
 		}
 	} else {
-		return nil, errors.New("invalid output format given")
+		return nil, "", errors.New("invalid output format given")
 	}
 
 	if request.OutputTarget == requests.RenderToFileOutputTargetBytes {
 		imageBytes := imgBuf.Bytes()
-		myResp.ImageBytes = &imageBytes
+		return &imageBytes, "", nil
 	} else if request.OutputTarget == requests.RenderToFileOutputTargetFile {
 		var targetFile *os.File
 		if request.TargetFilePath != "" {
 			existingFile, err := os.Create(request.TargetFilePath)
 			if err != nil {
-				return nil, err
+				return nil, "", err
 			}
 			targetFile = existingFile
 		} else {
 			tempFile, err := ioutil.TempFile("", "")
 			if err != nil {
-				return nil, err
+				return nil, "", err
 			}
 			targetFile = tempFile
 		}
 
 		_, err := targetFile.Write(imgBuf.Bytes())
 		if err != nil {
-			return nil, err
+			return nil, "", err
 		}
 
 		err = targetFile.Close()
 		if err != nil {
-			return nil, err
+			return nil, "", err
 		}
 
-		myResp.ImagePath = targetFile.Name()
-	} else {
-		return nil, errors.New("invalid output target given")
+		return nil, targetFile.Name(), nil
 	}
 
-	return myResp, nil
+	return nil, "", errors.New("invalid output target given")
 }
@@ -0,0 +1,82 @@
+package implementation_webassembly
+
+import (
+	"errors"
+	"fmt"
+	"image/color"
+
+	"github.com/klippa-app/go-pdfium/requests"
+	"github.com/klippa-app/go-pdfium/structs"
+)
+
+// defaultLabelHeight is the height of the labels under the pages when no
+// label height is given.
+const defaultLabelHeight = 20
+
+// getRenderPagesGrid returns the amount of columns and the label height of
+// the grid to render the pages in.
+func getRenderPagesGrid(pageCount int, layout requests.RenderPagesLayout) (int, int, error) {
+	columns := 1
+	switch layout.Mode {
+	case "", requests.RenderPagesLayoutModeVertical:
+	case requests.RenderPagesLayoutModeHorizontal:
+		columns = pageCount
+	case requests.RenderPagesLayoutModeGrid:
+		if layout.Columns < 1 {
+			return 0, 0, errors.New("no columns given for the grid layout")
+		}
+		columns = layout.Columns
+	default:
+		return 0, 0, fmt.Errorf("invalid layout mode %s given", layout.Mode)
+	}
+
+	if layout.LabelHeight < 0 {
+		return 0, 0, errors.New("invalid label height given")
+	}
+
+	labelHeight := 0
+	switch layout.Label {
+	case requests.RenderPagesLayoutLabelNone:
+	case requests.RenderPagesLayoutLabelPageNumber, requests.RenderPagesLayoutLabelPageLabel:
+		labelHeight = layout.LabelHeight
+		if labelHeight == 0 {
+			labelHeight = defaultLabelHeight
+		}
+	default:
+		return 0, 0, fmt.Errorf("invalid layout label %s given", layout.Label)
+	}
+
+	if layout.MaxWidth < 0 || layout.MaxHeight < 0 {
+		return 0, 0, errors.New("invalid maximum width or height given")
+	}
+
+	return columns, labelHeight, nil
+}
+
+// getRenderPagesBackgroundColor returns the background color for
+// FPDFBitmap_FillRect. The bitmap is rendered in reverse byte order, which
+// FPDFBitmap_FillRect doesn't do, so the red and blue are swapped. The
+// color is premultiplied with the alpha like the pixels of a Go image.
+func getRenderPagesBackgroundColor(backgroundColor *structs.FPDF_COLOR) uint32 {
+	premultiplied := color.RGBAModel.Convert(color.NRGBA{
+		R: uint8(backgroundColor.R),
+		G: uint8(backgroundColor.G),
+		B: uint8(backgroundColor.B),
+		A: uint8(backgroundColor.A),
+	}).(color.RGBA)
+
+	return uint32(premultiplied.A)<<24 | uint32(premultiplied.B)<<16 | uint32(premultiplied.G)<<8 | uint32(premultiplied.R)
+}
+
+// getRenderPagesLabelColor returns the color of the labels, white on a dark
+// background and black otherwise.
+func getRenderPagesLabelColor(backgroundColor *structs.FPDF_COLOR) color.RGBA {
+	if backgroundColor != nil && backgroundColor.A > 127 {
+		luminance := (299*backgroundColor.R + 587*backgroundColor.G + 114*backgroundColor.B) / 1000
+		if luminance < 128 {
+			return color.RGBA{R: 0xFF, G: 0xFF, B: 0xFF, A: 0xFF}
+		}
+	}
+
+	return color.RGBA{A: 0xFF}
+}
@@ -0,0 +1,233 @@
+package implementation_webassembly
+
+import (
+	"errors"
+	"math"
+
+	"github.com/klippa-app/go-pdfium/enums"
+	"github.com/klippa-app/go-pdfium/requests"
+	"github.com/klippa-app/go-pdfium/responses"
+	"github.com/klippa-app/go-pdfium/structs"
+)
+
+// ResizePages rescales the content of the given pages to a target page
+// size, this can be used to normalize documents with mixed page sizes.
+// The annotations of the pages are transformed as well.
+func (p *PdfiumImplementation) ResizePages(request *requests.ResizePages) (*responses.ResizePages, error) {
+	pageCount, err := p.FPDF_GetPageCount(&requests.FPDF_GetPageCount{
+		Document: request.Document,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if request.Width <= 0 || request.Height <= 0 {
+		return nil, errors.New("no valid target size given")
+	}
+
+	mode := request.Mode
+	if mode == "" {
+		mode = requests.ResizePagesModeFit
+	}
+
+	if mode != requests.ResizePagesModeFit && mode != requests.ResizePagesModeFill {
+		return nil, errors.New("invalid resize mode given")
+	}
+
+	pages, err := parsePageRange(request.PageRange, pageCount.PageCount)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &responses.ResizePages{
+		Pages: []responses.ResizePagesPage{},
+	}
+
+	for _, pageIndex := range pages {
+		page := requests.Page{
+			ByIndex: &requests.PageByIndex{
+				Document: request.Document,
+				Index:    pageIndex,
+			},
+		}
+
+		pageResult, err := p.resizePage(page, request, mode)
+		if err != nil {
+			return nil, err
+		}
+
+		pageResult.Page = pageIndex
+		resp.Pages = append(resp.Pages, *pageResult)
+	}
+
+	return resp, nil
+}
+
+func (p *PdfiumImplementation) resizePage(page requests.Page, request *requests.ResizePages, mode requests.ResizePagesMode) (*responses.ResizePagesPage, error) {
+	left, bottom, right, top, err := p.getPageVisibleBox(page)
+	if err != nil {
+		return nil, err
+	}
+
+	rotation, err := p.FPDFPage_GetRotation(&requests.FPDFPage_GetRotation{
+		Page: page,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	width := float64(right - left)
+	height := float64(top - bottom)
+	if width <= 0 || height <= 0 {
+		return nil, errors.New("page has an invalid size")
+	}
+
+	isRotated := rotation.PageRotation == enums.FPDF_PAGE_ROTATION_90_CW || rotation.PageRotation == enums.FPDF_PAGE_ROTATION_270_CW
+
+	// The size of the page as it is displayed.
+	displayWidth, displayHeight := width, height
+	if isRotated {
+		displayWidth, displayHeight = height, width
+	}
+
+	targetWidth := float64(request.Width)
+	targetHeight := float64(request.Height)
+	if request.MatchOrientation && (displayWidth > displayHeight) != (targetWidth > targetHeight) {
+		targetWidth, targetHeight = targetHeight, targetWidth
+	}
+
+	// The rotation matrix that bakes the page rotation into the content,
+	// the identity matrix when we keep the page rotation.
+	rotationMatrix := [6]float64{1, 0, 0, 1, 0, 0}
+	contentWidth, contentHeight := width, height
+	pageWidth, pageHeight := targetWidth, targetHeight
+	newRotation := rotation.PageRotation
+	if request.NormalizeRotation {
+		switch rotation.PageRotation {
+		case enums.FPDF_PAGE_ROTATION_90_CW:
+			rotationMatrix = [6]float64{0, -1, 1, 0, 0, width}
+		case enums.FPDF_PAGE_ROTATION_180_CW:
+			rotationMatrix = [6]float64{-1, 0, 0, -1, width, height}
+		case enums.FPDF_PAGE_ROTATION_270_CW:
+			rotationMatrix = [6]float64{0, 1, -1, 0, height, 0}
+		}
+		contentWidth, contentHeight = displayWidth, displayHeight
+		newRotation = enums.FPDF_PAGE_ROTATION_NONE
+	} else if isRotated {
+		// The page will still be rotated when displayed, so the page box
+		// needs to be rotated as well to get the target size.
+		pageWidth, pageHeight = targetHeight, targetWidth
+	}
+
+	scale := math.Min(pageWidth/contentWidth, pageHeight/contentHeight)
+	if mode == requests.ResizePagesModeFill {
+		scale = math.Max(pageWidth/contentWidth, pageHeight/contentHeight)
+	}
+
+	if request.NoUpscale && scale > 1 {
+		scale = 1
+	}
+
+	offsetX := (pageWidth - contentWidth*scale) / 2
+	offsetY := (pageHeight - contentHeight*scale) / 2
+
+	// Move the visible box to the origin, rotate, scale and then center it
+	// on the new page.
+	matrix := structs.FPDF_FS_MATRIX{
+		A: float32(scale * rotationMatrix[0]),
+		B: float32(scale * rotationMatrix[1]),
+		C: float32(scale * rotationMatrix[2]),
+		D: float32(scale * rotationMatrix[3]),
+		E: float32(scale*(rotationMatrix[4]-rotationMatrix[0]*float64(left)-rotationMatrix[2]*float64(bottom)) + offsetX),
+		F: float32(scale*(rotationMatrix[5]-rotationMatrix[1]*float64(left)-rotationMatrix[3]*float64(bottom)) + offsetY),
+	}
+
+	// Clip to the area where the old visible box ends up, so that content
+	// that was outside the visible box stays invisible.
+	clipRect := structs.FPDF_FS_RECTF{
+		Left:   float32(math.Max(offsetX, 0)),
+		Bottom: float32(math.Max(offsetY, 0)),
+		Right:  float32(math.Min(offsetX+contentWidth*scale, pageWidth)),
+		Top:    float32(math.Min(offsetY+contentHeight*scale, pageHeight)),
+	}
+
+	// Get the other page boxes before we change anything, so that we can
+	// transform them as well.
+	otherBoxes := map[requests.CropPagesBox]structs.FPDF_FS_RECTF{}
+	if box, err := p.FPDFPage_GetBleedBox(&requests.FPDFPage_GetBleedBox{Page: page}); err == nil {
+		otherBoxes[requests.CropPagesBoxBleedBox] = structs.FPDF_FS_RECTF{Left: box.Left, Bottom: box.Bottom, Right: box.Right, Top: box.Top}
+	}
+	if box, err := p.FPDFPage_GetTrimBox(&requests.FPDFPage_GetTrimBox{Page: page}); err == nil {
+		otherBoxes[requests.CropPagesBoxTrimBox] = structs.FPDF_FS_RECTF{Left: box.Left, Bottom: box.Bottom, Right: box.Right, Top: box.Top}
+	}
+	if box, err := p.FPDFPage_GetArtBox(&requests.FPDFPage_GetArtBox{Page: page}); err == nil {
+		otherBoxes[requests.CropPagesBoxArtBox] = structs.FPDF_FS_RECTF{Left: box.Left, Bottom: box.Bottom, Right: box.Right, Top: box.Top}
+	}
+
+	_, err = p.FPDFPage_TransFormWithClip(&requests.FPDFPage_TransFormWithClip{
+		Page:     page,
+		Matrix:   &matrix,
+		ClipRect: &clipRect,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = p.FPDFPage_TransformAnnots(&requests.FPDFPage_TransformAnnots{
+		Page:      page,
+		Transform: matrix,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = p.setPageBox(page, requests.CropPagesBoxMediaBox, 0, 0, float32(pageWidth), float32(pageHeight))
+	if err != nil {
+		return nil, err
+	}
+
+	err = p.setPageBox(page, requests.CropPagesBoxCropBox, 0, 0, float32(pageWidth), float32(pageHeight))
+	if err != nil {
+		return nil, err
+	}
+
+	for boxType, box := range otherBoxes {
+		x1, y1 := transformPoint(matrix, box.Left, box.Bottom)
+		x2, y2 := transformPoint(matrix, box.Right, box.Top)
+		boxLeft := math.Max(math.Min(x1, x2), 0)
+		boxBottom := math.Max(math.Min(y1, y2), 0)
+		boxRight := math.Min(math.Max(x1, x2), pageWidth)
+		boxTop := math.Min(math.Max(y1, y2), pageHeight)
+		err = p.setPageBox(page, boxType, float32(boxLeft), float32(boxBottom), float32(boxRight), float32(boxTop))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if newRotation != rotation.PageRotation {
+		_, err = p.FPDFPage_SetRotation(&requests.FPDFPage_SetRotation{
+			Page:   page,
+			Rotate: newRotation,
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	resultWidth, resultHeight := pageWidth, pageHeight
+	if newRotation == enums.FPDF_PAGE_ROTATION_90_CW || newRotation == enums.FPDF_PAGE_ROTATION_270_CW {
+		resultWidth, resultHeight = pageHeight, pageWidth
+	}
+
+	return &responses.ResizePagesPage{
+		Scale:    scale,
+		Width:    float32(resultWidth),
+		Height:   float32(resultHeight),
+		Rotation: newRotation,
+	}, nil
+}
+
+// transformPoint applies the given matrix to a point.
+func transformPoint(matrix structs.FPDF_FS_MATRIX, x, y float32) (float64, float64) {
+	return float64(matrix.A*x + matrix.C*y + matrix.E), float64(matrix.B*x + matrix.D*y + matrix.F)
+}
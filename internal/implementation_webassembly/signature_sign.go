@@ -0,0 +1,292 @@
+package implementation_webassembly
+
+import (
+	"crypto"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/klippa-app/go-pdfium/internal/appearance"
+	"github.com/klippa-app/go-pdfium/internal/pdf_signature"
+	"github.com/klippa-app/go-pdfium/requests"
+	"github.com/klippa-app/go-pdfium/responses"
+	"github.com/klippa-app/go-pdfium/structs"
+)
+
+// SignDocument signs the document with an incremental update. The document
+// is saved incrementally, so that existing signatures stay valid, a
+// signature field with a placeholder is appended, and the CMS signature over
+// the byte range is written into the placeholder. Pending changes of the
+// document are part of the incremental save, except for the removal of
+// unused objects, which would rewrite the file. The opened document itself
+// is not changed.
+func (p *PdfiumImplementation) SignDocument(request *requests.SignDocument) (*responses.SignDocument, error) {
+	p.Lock()
+	_, err := p.getDocumentHandle(request.Document)
+	p.Unlock()
+	if err != nil {
+		return nil, err
+	}
+
+	signer, certificate, chain, err := getSignDocumentSigner(request)
+	if err != nil {
+		return nil, err
+	}
+
+	signingTime := request.SigningTime
+	if signingTime.IsZero() {
+		signingTime = time.Now()
+	}
+
+	contentsSize := request.ContentsSize
+	if contentsSize == 0 {
+		contentsSize = 8192
+	}
+
+	if contentsSize < 0 {
+		return nil, errors.New("contents size can't be negative")
+	}
+
+	name := request.Name
+	if name == "" {
+		name = certificate.Subject.CommonName
+	}
+
+	field := pdf_signature.Field{
+		Name:         request.FieldName,
+		SignerName:   name,
+		Reason:       request.Reason,
+		Location:     request.Location,
+		ContactInfo:  request.ContactInfo,
+		SigningTime:  signingTime,
+		ContentsSize: contentsSize,
+	}
+
+	// Don't lock here, the methods that we call do that for us.
+	if request.Appearance != nil {
+		field.Page = request.Appearance.Page
+		field.Appearance, err = p.getSignDocumentAppearance(request, name, signingTime)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	savedDocument, err := p.FPDF_SaveAsCopy(&requests.FPDF_SaveAsCopy{
+		Document: request.Document,
+		Flags:    requests.SaveFlagIncremental,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	placeholder, err := pdf_signature.Prepare(*savedDocument.FileBytes, field)
+	if err != nil {
+		return nil, err
+	}
+
+	contents, err := pdf_signature.Sign(placeholder.SignedData(), signer, certificate, chain, signingTime)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := placeholder.Embed(contents); err != nil {
+		return nil, err
+	}
+
+	resp := &responses.SignDocument{
+		FieldName: placeholder.FieldName,
+		ByteRange: placeholder.ByteRange,
+	}
+
+	if request.FilePath != nil {
+		if err := os.WriteFile(*request.FilePath, placeholder.File, 0644); err != nil {
+			return nil, err
+		}
+		resp.FilePath = request.FilePath
+	} else {
+		resp.FileBytes = &placeholder.File
+	}
+
+	return resp, nil
+}
+
+// getSignDocumentSigner returns the signer, its certificate and the chain of
+// the certificate.
+func getSignDocumentSigner(request *requests.SignDocument) (crypto.Signer, *x509.Certificate, []*x509.Certificate, error) {
+	signer := request.Signer
+	if signer == nil {
+		if request.PrivateKey == nil {
+			return nil, nil, nil, errors.New("no signer or private key given")
+		}
+
+		var err error
+		signer, err = pdf_signature.ParsePrivateKey(request.PrivateKey)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+	}
+
+	if request.Certificate == nil {
+		return nil, nil, nil, errors.New("no certificate given")
+	}
+
+	certificates, err := pdf_signature.ParseCertificates(request.Certificate)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("could not parse certificate: %w", err)
+	}
+	certificate := certificates[0]
+
+	publicKey, ok := signer.Public().(interface{ Equal(crypto.PublicKey) bool })
+	if !ok || !publicKey.Equal(certificate.PublicKey) {
+		return nil, nil, nil, errors.New("the certificate doesn't belong to the private key")
+	}
+
+	chain := []*x509.Certificate{}
+	for i := range request.CertificateChain {
+		chainCertificates, err := pdf_signature.ParseCertificates(request.CertificateChain[i])
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("could not parse chain certificate %d: %w", i+1, err)
+		}
+		chain = append(chain, chainCertificates...)
+	}
+
+	return signer, certificate, chain, nil
+}
+
+// getSignDocumentAppearance returns the appearance of a visible signature.
+// The image is drawn left of the text, the text is drawn as the outlines of
+// the glyphs of Helvetica, so that the appearance doesn't need font
+// resources.
+func (p *PdfiumImplementation) getSignDocumentAppearance(request *requests.SignDocument, name string, signingTime time.Time) (*pdf_signature.Appearance, error) {
+	rect := request.Appearance.Rect
+	if rect.Top < rect.Bottom {
+		rect.Top, rect.Bottom = rect.Bottom, rect.Top
+	}
+	if rect.Right < rect.Left {
+		rect.Left, rect.Right = rect.Right, rect.Left
+	}
+
+	if rect.Right-rect.Left <= 0 || rect.Top-rect.Bottom <= 0 {
+		return nil, errors.New("the rect of the signature has no size")
+	}
+
+	text := ""
+	if request.Appearance.Text != nil {
+		text = *request.Appearance.Text
+	} else {
+		lines := []string{
+			fmt.Sprintf("Digitally signed by %s", name),
+			fmt.Sprintf("Date: %s", signingTime.Format("2006-01-02 15:04:05 -07:00")),
+		}
+		if request.Reason != "" {
+			lines = append(lines, fmt.Sprintf("Reason: %s", request.Reason))
+		}
+		if request.Location != "" {
+			lines = append(lines, fmt.Sprintf("Location: %s", request.Location))
+		}
+		text = strings.Join(lines, "\n")
+	}
+
+	signatureAppearance := &pdf_signature.Appearance{
+		Rect: rect,
+	}
+
+	textRect := rect
+	if request.Appearance.Image != nil {
+		imageWidth, imageHeight, err := pdf_signature.ImageSize(request.Appearance.Image)
+		if err != nil {
+			return nil, err
+		}
+
+		// The image gets the whole rect when there is no text, otherwise
+		// the left part, at most half of the rect.
+		imageArea := rect
+		aspectRatio := float32(imageWidth) / float32(imageHeight)
+		if text != "" {
+			imageAreaWidth := (rect.Top - rect.Bottom) * aspectRatio
+			if imageAreaWidth > (rect.Right-rect.Left)/2 {
+				imageAreaWidth = (rect.Right - rect.Left) / 2
+			}
+			imageArea.Right = rect.Left + imageAreaWidth
+			textRect.Left = imageArea.Right
+		}
+
+		// Fit the image in the area, keeping its aspect ratio.
+		width := imageArea.Right - imageArea.Left
+		height := width / aspectRatio
+		if height > imageArea.Top-imageArea.Bottom {
+			height = imageArea.Top - imageArea.Bottom
+			width = height * aspectRatio
+		}
+
+		left := imageArea.Left + (imageArea.Right-imageArea.Left-width)/2
+		bottom := imageArea.Bottom + (imageArea.Top-imageArea.Bottom-height)/2
+		signatureAppearance.Image = request.Appearance.Image
+		signatureAppearance.ImageRect = structs.FPDF_FS_RECTF{Left: left, Top: bottom + height, Right: left + width, Bottom: bottom}
+	}
+
+	if text != "" {
+		content, err := p.getSignDocumentText(request, textRect, text)
+		if err != nil {
+			return nil, err
+		}
+		signatureAppearance.Content = content
+	}
+
+	return signatureAppearance, nil
+}
+
+// getSignDocumentText returns the content stream that draws the text of a
+// visible signature.
+func (p *PdfiumImplementation) getSignDocumentText(request *requests.SignDocument, rect structs.FPDF_FS_RECTF, text string) (string, error) {
+	fontSize := request.Appearance.FontSize
+	if fontSize == 0 {
+		fontSize = 10
+	}
+
+	textColor := structs.FPDF_COLOR{R: 0, G: 0, B: 0, A: 255}
+	if request.Appearance.TextColor != nil {
+		textColor = *request.Appearance.TextColor
+	}
+
+	font, err := p.FPDFText_LoadStandardFont(&requests.FPDFText_LoadStandardFont{
+		Document: request.Document,
+		Font:     "Helvetica",
+	})
+	if err != nil {
+		return "", err
+	}
+	defer p.FPDFFont_Close(&requests.FPDFFont_Close{
+		Font: font.Font,
+	})
+
+	ascent, err := p.FPDFFont_GetAscent(&requests.FPDFFont_GetAscent{
+		Font:     font.Font,
+		FontSize: fontSize,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	descent, err := p.FPDFFont_GetDescent(&requests.FPDFFont_GetDescent{
+		Font:     font.Font,
+		FontSize: fontSize,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return appearance.FreeText(rect, appearance.Style{Opacity: 255}, appearance.FreeTextText{
+		Text:     text,
+		FontSize: fontSize,
+		Ascent:   ascent.Ascent,
+		Descent:  descent.Descent,
+		Color:    textColor,
+		Glyph: func(r rune) (*appearance.Glyph, error) {
+			return p.getGlyph(font.Font, fontSize, r)
+		},
+	})
+}
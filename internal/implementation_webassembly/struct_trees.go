@@ -0,0 +1,306 @@
+package implementation_webassembly
+
+import (
+	"github.com/klippa-app/go-pdfium/enums"
+	"github.com/klippa-app/go-pdfium/references"
+	"github.com/klippa-app/go-pdfium/requests"
+	"github.com/klippa-app/go-pdfium/responses"
+)
+
+// GetStructTree returns the structure tree of the given pages. PDFium only
+// gives the structure tree of a page, elements that have content on more
+// than one page are returned for every page they have content on.
+// Experimental API.
+func (p *PdfiumImplementation) GetStructTree(request *requests.GetStructTree) (*responses.GetStructTree, error) {
+	pageCount, err := p.FPDF_GetPageCount(&requests.FPDF_GetPageCount{
+		Document: request.Document,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	pages, err := parsePageRange(request.PageRange, pageCount.PageCount)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &responses.GetStructTree{
+		Pages: []responses.StructTreePage{},
+	}
+
+	for _, pageIndex := range pages {
+		elements, err := p.getPageStructTree(requests.Page{
+			ByIndex: &requests.PageByIndex{
+				Document: request.Document,
+				Index:    pageIndex,
+			},
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		resp.Pages = append(resp.Pages, responses.StructTreePage{
+			Page:     pageIndex,
+			Elements: elements,
+		})
+	}
+
+	return resp, nil
+}
+
+// getPageStructTree returns the elements at the top of the structure tree of
+// a page.
+func (p *PdfiumImplementation) getPageStructTree(page requests.Page) ([]responses.StructElement, error) {
+	structTree, err := p.FPDF_StructTree_GetForPage(&requests.FPDF_StructTree_GetForPage{
+		Page: page,
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer p.FPDF_StructTree_Close(&requests.FPDF_StructTree_Close{
+		StructTree: structTree.StructTree,
+	})
+
+	childCount, err := p.FPDF_StructTree_CountChildren(&requests.FPDF_StructTree_CountChildren{
+		StructTree: structTree.StructTree,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	elements := []responses.StructElement{}
+	for i := 0; i < childCount.Count; i++ {
+		child, err := p.FPDF_StructTree_GetChildAtIndex(&requests.FPDF_StructTree_GetChildAtIndex{
+			StructTree: structTree.StructTree,
+			Index:      i,
+		})
+		if err != nil {
+			// The child is not an element.
+			continue
+		}
+
+		element, err := p.getStructElement(child.StructElement)
+		if err != nil {
+			return nil, err
+		}
+
+		elements = append(elements, *element)
+	}
+
+	return elements, nil
+}
+
+// getStructElement returns a structure element with its attributes and its
+// children.
+func (p *PdfiumImplementation) getStructElement(structElement references.FPDF_STRUCTELEMENT) (*responses.StructElement, error) {
+	element := &responses.StructElement{
+		Attributes:       []responses.StructElementAttribute{},
+		MarkedContentIDs: []int{},
+		Children:         []responses.StructElement{},
+	}
+
+	elementType, err := p.FPDF_StructElement_GetType(&requests.FPDF_StructElement_GetType{
+		StructElement: structElement,
+	})
+	if err == nil {
+		element.Type = elementType.Type
+	}
+
+	objType, err := p.FPDF_StructElement_GetObjType(&requests.FPDF_StructElement_GetObjType{
+		StructElement: structElement,
+	})
+	if err == nil {
+		element.ObjType = objType.ObjType
+	}
+
+	// PDFium gives an error when an element doesn't have a value.
+	title, err := p.FPDF_StructElement_GetTitle(&requests.FPDF_StructElement_GetTitle{
+		StructElement: structElement,
+	})
+	if err == nil {
+		element.Title = &title.Title
+	}
+
+	altText, err := p.FPDF_StructElement_GetAltText(&requests.FPDF_StructElement_GetAltText{
+		StructElement: structElement,
+	})
+	if err == nil {
+		element.AltText = &altText.AltText
+	}
+
+	actualText, err := p.FPDF_StructElement_GetActualText(&requests.FPDF_StructElement_GetActualText{
+		StructElement: structElement,
+	})
+	if err == nil {
+		element.ActualText = &actualText.Actualtext
+	}
+
+	lang, err := p.FPDF_StructElement_GetLang(&requests.FPDF_StructElement_GetLang{
+		StructElement: structElement,
+	})
+	if err == nil {
+		element.Lang = &lang.Lang
+	}
+
+	id, err := p.FPDF_StructElement_GetID(&requests.FPDF_StructElement_GetID{
+		StructElement: structElement,
+	})
+	if err == nil {
+		element.ID = &id.ID
+	}
+
+	attributes, err := p.getStructElementAttributes(structElement)
+	if err != nil {
+		return nil, err
+	}
+	element.Attributes = attributes
+
+	childCount, err := p.FPDF_StructElement_CountChildren(&requests.FPDF_StructElement_CountChildren{
+		StructElement: structElement,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for i := 0; i < childCount.Count; i++ {
+		child, err := p.FPDF_StructElement_GetChildAtIndex(&requests.FPDF_StructElement_GetChildAtIndex{
+			StructElement: structElement,
+			Index:         i,
+		})
+		if err == nil {
+			childElement, err := p.getStructElement(child.StructElement)
+			if err != nil {
+				return nil, err
+			}
+
+			element.Children = append(element.Children, *childElement)
+			continue
+		}
+
+		// The child is marked content on the page, or a reference to an
+		// object like an annotation, which doesn't have a marked content ID.
+		markedContentID, err := p.FPDF_StructElement_GetChildMarkedContentID(&requests.FPDF_StructElement_GetChildMarkedContentID{
+			StructElement: structElement,
+			Index:         i,
+		})
+		if err == nil {
+			element.MarkedContentIDs = append(element.MarkedContentIDs, markedContentID.ChildMarkedContentID)
+		}
+	}
+
+	return element, nil
+}
+
+// getStructElementAttributes returns the attributes of all the attribute
+// objects of a structure element.
+func (p *PdfiumImplementation) getStructElementAttributes(structElement references.FPDF_STRUCTELEMENT) ([]responses.StructElementAttribute, error) {
+	attributes := []responses.StructElementAttribute{}
+
+	attributeCount, err := p.FPDF_StructElement_GetAttributeCount(&requests.FPDF_StructElement_GetAttributeCount{
+		StructElement: structElement,
+	})
+	if err != nil {
+		// The element has no attributes.
+		return attributes, nil
+	}
+
+	for i := 0; i < attributeCount.Count; i++ {
+		attribute, err := p.FPDF_StructElement_GetAttributeAtIndex(&requests.FPDF_StructElement_GetAttributeAtIndex{
+			StructElement: structElement,
+			Index:         i,
+		})
+		if err != nil {
+			// The attribute object is not a dictionary.
+			continue
+		}
+
+		owner := ""
+		ownerValue, err := p.FPDF_StructElement_Attr_GetStringValue(&requests.FPDF_StructElement_Attr_GetStringValue{
+			StructElementAttribute: attribute.StructElementAttribute,
+			Name:                   "O",
+		})
+		if err == nil {
+			owner = ownerValue.Value
+		}
+
+		keyCount, err := p.FPDF_StructElement_Attr_GetCount(&requests.FPDF_StructElement_Attr_GetCount{
+			StructElementAttribute: attribute.StructElementAttribute,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		for j := 0; j < keyCount.Count; j++ {
+			name, err := p.FPDF_StructElement_Attr_GetName(&requests.FPDF_StructElement_Attr_GetName{
+				StructElementAttribute: attribute.StructElementAttribute,
+				Index:                  j,
+			})
+			if err != nil {
+				return nil, err
+			}
+
+			if name.Name == "O" {
+				continue
+			}
+
+			structElementAttribute, err := p.getStructElementAttribute(attribute.StructElementAttribute, name.Name)
+			if err != nil {
+				return nil, err
+			}
+			structElementAttribute.Owner = owner
+
+			attributes = append(attributes, *structElementAttribute)
+		}
+	}
+
+	return attributes, nil
+}
+
+// getStructElementAttribute returns an attribute with its value. PDFium can
+// only give the value of booleans, numbers, strings and names.
+func (p *PdfiumImplementation) getStructElementAttribute(attribute references.FPDF_STRUCTELEMENT_ATTR, name string) (*responses.StructElementAttribute, error) {
+	attributeType, err := p.FPDF_StructElement_Attr_GetType(&requests.FPDF_StructElement_Attr_GetType{
+		StructElementAttribute: attribute,
+		Name:                   name,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	structElementAttribute := &responses.StructElementAttribute{
+		Name: name,
+		Type: attributeType.ObjectType,
+	}
+
+	switch attributeType.ObjectType {
+	case enums.FPDF_OBJECT_TYPE_BOOLEAN:
+		value, err := p.FPDF_StructElement_Attr_GetBooleanValue(&requests.FPDF_StructElement_Attr_GetBooleanValue{
+			StructElementAttribute: attribute,
+			Name:                   name,
+		})
+		if err != nil {
+			return nil, err
+		}
+		structElementAttribute.BooleanValue = &value.Value
+	case enums.FPDF_OBJECT_TYPE_NUMBER:
+		value, err := p.FPDF_StructElement_Attr_GetNumberValue(&requests.FPDF_StructElement_Attr_GetNumberValue{
+			StructElementAttribute: attribute,
+			Name:                   name,
+		})
+		if err != nil {
+			return nil, err
+		}
+		structElementAttribute.NumberValue = &value.Value
+	case enums.FPDF_OBJECT_TYPE_STRING, enums.FPDF_OBJECT_TYPE_NAME:
+		value, err := p.FPDF_StructElement_Attr_GetStringValue(&requests.FPDF_StructElement_Attr_GetStringValue{
+			StructElementAttribute: attribute,
+			Name:                   name,
+		})
+		if err != nil {
+			return nil, err
+		}
+		structElementAttribute.StringValue = &value.Value
+	}
+
+	return structElementAttribute, nil
+}
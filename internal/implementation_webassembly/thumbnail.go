@@ -0,0 +1,121 @@
+package implementation_webassembly
+
+import (
+	"errors"
+	"image"
+	"math"
+
+	pdfium_errors "github.com/klippa-app/go-pdfium/errors"
+	"github.com/klippa-app/go-pdfium/requests"
+	"github.com/klippa-app/go-pdfium/responses"
+)
+
+// GetPageThumbnail returns the embedded thumbnail of a page when it has
+// one that is large enough for the maximum size, otherwise the page is
+// rendered in the maximum size. The output options are the same as the
+// options of RenderToFile.
+func (p *PdfiumImplementation) GetPageThumbnail(request *requests.GetPageThumbnail) (*responses.GetPageThumbnail, error) {
+	if request.MaxWidth == 0 && request.MaxHeight == 0 {
+		return nil, errors.New("no maximum width or height given")
+	}
+
+	outputRequest := &requests.RenderToFile{
+		OutputFormat:   request.OutputFormat,
+		OutputTarget:   request.OutputTarget,
+		OutputQuality:  request.OutputQuality,
+		Progressive:    request.Progressive,
+		MaxFileSize:    request.MaxFileSize,
+		TargetFilePath: request.TargetFilePath,
+	}
+
+	embeddedThumbnail, err := p.getEmbeddedThumbnail(request.Page, request.MaxWidth, request.MaxHeight)
+	if err != nil {
+		return nil, err
+	}
+
+	if embeddedThumbnail != nil {
+		imageBytes, imagePath, err := p.writeRenderedImage(embeddedThumbnail, false, outputRequest)
+		if err != nil {
+			return nil, err
+		}
+
+		return &responses.GetPageThumbnail{
+			IsEmbedded: true,
+			ImageBytes: imageBytes,
+			ImagePath:  imagePath,
+			Width:      embeddedThumbnail.Bounds().Dx(),
+			Height:     embeddedThumbnail.Bounds().Dy(),
+		}, nil
+	}
+
+	renderedPage, err := p.RenderPageInPixels(&requests.RenderPageInPixels{
+		Page:        request.Page,
+		Width:       request.MaxWidth,
+		Height:      request.MaxHeight,
+		RenderFlags: request.RenderFlags,
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer renderedPage.Cleanup()
+
+	imageBytes, imagePath, err := p.writeRenderedImage(renderedPage.Result.Image, renderedPage.Result.HasTransparency, outputRequest)
+	if err != nil {
+		return nil, err
+	}
+
+	return &responses.GetPageThumbnail{
+		ImageBytes: imageBytes,
+		ImagePath:  imagePath,
+		Width:      renderedPage.Result.Width,
+		Height:     renderedPage.Result.Height,
+	}, nil
+}
+
+// getEmbeddedThumbnail returns the embedded thumbnail of the page scaled down
+// to the maximum size, nil when the page has no thumbnail or when the
+// thumbnail is smaller than the maximum size.
+func (p *PdfiumImplementation) getEmbeddedThumbnail(page requests.Page, maxWidth, maxHeight int) (*image.RGBA, error) {
+	thumbnail, err := p.FPDFPage_GetThumbnailAsBitmap(&requests.FPDFPage_GetThumbnailAsBitmap{
+		Page: page,
+	})
+	if err != nil {
+		// Without experimental support we can only render the page.
+		if errors.Is(err, pdfium_errors.ErrExperimentalUnsupported) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	if thumbnail.Bitmap == nil {
+		return nil, nil
+	}
+
+	defer p.FPDFBitmap_Destroy(&requests.FPDFBitmap_Destroy{
+		Bitmap: *thumbnail.Bitmap,
+	})
+
+	width, height, _, _, err := p.getBitmapData(*thumbnail.Bitmap)
+	if err != nil {
+		return nil, err
+	}
+
+	// The thumbnail must fill the maximum size in at least one direction,
+	// we don't scale thumbnails up.
+	scale := math.Inf(1)
+	if maxWidth > 0 {
+		scale = float64(maxWidth) / float64(width)
+	}
+	if maxHeight > 0 {
+		scale = math.Min(scale, float64(maxHeight)/float64(height))
+	}
+
+	if scale > 1 {
+		return nil, nil
+	}
+
+	newWidth := int(math.Max(math.Round(float64(width)*scale), 1))
+	newHeight := int(math.Max(math.Round(float64(height)*scale), 1))
+
+	return p.downsampleBitmap(*thumbnail.Bitmap, newWidth, newHeight)
+}
@@ -0,0 +1,178 @@
+package pdf_signature
+
+import (
+	"errors"
+)
+
+// maxBERDepth is how deep BER elements may be nested, to prevent running out
+// of stack on malicious input.
+const maxBERDepth = 64
+
+// berToDER converts the first BER element of the data to DER, as far as
+// needed to parse it with encoding/asn1. Indefinite lengths are replaced by
+// definite lengths, and constructed octet strings are joined into primitive
+// octet strings. Data after the element, like the zero padding of the
+// signature contents, is ignored.
+func berToDER(data []byte) ([]byte, error) {
+	der, _, err := convertBERElement(data, 0)
+	return der, err
+}
+
+// convertBERElement converts the BER element at the start of the data and
+// returns it as DER, together with the length of the BER element.
+func convertBERElement(data []byte, depth int) ([]byte, int, error) {
+	if depth > maxBERDepth {
+		return nil, 0, errors.New("ber: elements are nested too deep")
+	}
+
+	if len(data) < 2 {
+		return nil, 0, errors.New("ber: element is truncated")
+	}
+
+	// Read the tag, high tag numbers are encoded in the following bytes.
+	offset := 1
+	if data[0]&0x1f == 0x1f {
+		for {
+			if offset >= len(data) {
+				return nil, 0, errors.New("ber: tag is truncated")
+			}
+			offset++
+			if data[offset-1]&0x80 == 0 {
+				break
+			}
+		}
+	}
+	tag := data[:offset]
+	constructed := data[0]&0x20 != 0
+
+	if offset >= len(data) {
+		return nil, 0, errors.New("ber: length is truncated")
+	}
+
+	lengthByte := data[offset]
+	offset++
+
+	indefinite := false
+	length := 0
+	switch {
+	case lengthByte == 0x80:
+		if !constructed {
+			return nil, 0, errors.New("ber: primitive element with indefinite length")
+		}
+		indefinite = true
+	case lengthByte&0x80 == 0:
+		length = int(lengthByte)
+	default:
+		lengthBytes := int(lengthByte & 0x7f)
+		if lengthBytes > 4 || offset+lengthBytes > len(data) {
+			return nil, 0, errors.New("ber: invalid length")
+		}
+		for i := 0; i < lengthBytes; i++ {
+			length = length<<8 | int(data[offset+i])
+		}
+		offset += lengthBytes
+	}
+
+	if !indefinite && (length < 0 || offset+length > len(data)) {
+		return nil, 0, errors.New("ber: element is truncated")
+	}
+
+	if !constructed {
+		return encodeDERElement(tag, data[offset:offset+length]), offset + length, nil
+	}
+
+	contents := []byte{}
+	children := [][]byte{}
+	position := offset
+	for {
+		if indefinite {
+			if position+2 > len(data) {
+				return nil, 0, errors.New("ber: missing end of contents")
+			}
+			if data[position] == 0 && data[position+1] == 0 {
+				position += 2
+				break
+			}
+		} else if position >= offset+length {
+			break
+		}
+
+		end := len(data)
+		if !indefinite {
+			end = offset + length
+		}
+
+		child, childLength, err := convertBERElement(data[position:end], depth+1)
+		if err != nil {
+			return nil, 0, err
+		}
+		children = append(children, child)
+		contents = append(contents, child...)
+		position += childLength
+	}
+
+	// DER only allows primitive octet strings.
+	if data[0] == 0x24 {
+		joined := []byte{}
+		for _, child := range children {
+			if len(child) == 0 || child[0] != 0x04 {
+				return nil, 0, errors.New("ber: constructed octet string contains another type")
+			}
+			_, childContents, err := splitDERElement(child)
+			if err != nil {
+				return nil, 0, err
+			}
+			joined = append(joined, childContents...)
+		}
+		return encodeDERElement([]byte{0x04}, joined), position, nil
+	}
+
+	return encodeDERElement(tag, contents), position, nil
+}
+
+// encodeDERElement encodes an element with the given tag and contents with a
+// definite length.
+func encodeDERElement(tag []byte, contents []byte) []byte {
+	element := append([]byte{}, tag...)
+	length := len(contents)
+	if length < 0x80 {
+		element = append(element, byte(length))
+	} else {
+		lengthBytes := []byte{}
+		for length > 0 {
+			lengthBytes = append([]byte{byte(length)}, lengthBytes...)
+			length >>= 8
+		}
+		element = append(element, 0x80|byte(len(lengthBytes)))
+		element = append(element, lengthBytes...)
+	}
+	return append(element, contents...)
+}
+
+// splitDERElement returns the tag and the contents of a DER element that
+// has a low tag number.
+func splitDERElement(element []byte) (byte, []byte, error) {
+	if len(element) < 2 {
+		return 0, nil, errors.New("ber: element is truncated")
+	}
+
+	offset := 2
+	length := int(element[1])
+	if element[1]&0x80 != 0 {
+		lengthBytes := int(element[1] & 0x7f)
+		if 2+lengthBytes > len(element) {
+			return 0, nil, errors.New("ber: invalid length")
+		}
+		length = 0
+		for i := 0; i < lengthBytes; i++ {
+			length = length<<8 | int(element[2+i])
+		}
+		offset += lengthBytes
+	}
+
+	if offset+length > len(element) {
+		return 0, nil, errors.New("ber: element is truncated")
+	}
+
+	return element[0], element[offset : offset+length], nil
+}
@@ -0,0 +1,268 @@
+// Package pdf_signature verifies the CMS (PKCS#7) signatures of PDF
+// documents.
+package pdf_signature
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+)
+
+var (
+	oidSignedData    = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 7, 2}
+	oidTSTInfo       = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 9, 16, 1, 4}
+	oidContentType   = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 9, 3}
+	oidMessageDigest = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 9, 4}
+	oidSigningTime   = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 9, 5}
+
+	oidSHA1   = asn1.ObjectIdentifier{1, 3, 14, 3, 2, 26}
+	oidSHA256 = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 2, 1}
+	oidSHA384 = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 2, 2}
+	oidSHA512 = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 2, 3}
+	oidSHA224 = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 2, 4}
+
+	oidRSAEncryption   = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 1, 1}
+	oidSHA1WithRSA     = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 1, 5}
+	oidRSAPSS          = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 1, 10}
+	oidSHA256WithRSA   = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 1, 11}
+	oidSHA384WithRSA   = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 1, 12}
+	oidSHA512WithRSA   = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 1, 13}
+	oidSHA224WithRSA   = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 1, 14}
+	oidECPublicKey     = asn1.ObjectIdentifier{1, 2, 840, 10045, 2, 1}
+	oidECDSAWithSHA1   = asn1.ObjectIdentifier{1, 2, 840, 10045, 4, 1}
+	oidECDSAWithSHA224 = asn1.ObjectIdentifier{1, 2, 840, 10045, 4, 3, 1}
+	oidECDSAWithSHA256 = asn1.ObjectIdentifier{1, 2, 840, 10045, 4, 3, 2}
+	oidECDSAWithSHA384 = asn1.ObjectIdentifier{1, 2, 840, 10045, 4, 3, 3}
+	oidECDSAWithSHA512 = asn1.ObjectIdentifier{1, 2, 840, 10045, 4, 3, 4}
+	oidEd25519         = asn1.ObjectIdentifier{1, 3, 101, 112}
+)
+
+// contentInfo is the CMS ContentInfo (RFC 5652 section 3).
+type contentInfo struct {
+	ContentType asn1.ObjectIdentifier
+	Content     asn1.RawValue `asn1:"explicit,optional,tag:0"`
+}
+
+// signedData is the CMS SignedData (RFC 5652 section 5.1).
+type signedData struct {
+	Version          int
+	DigestAlgorithms []pkix.AlgorithmIdentifier `asn1:"set"`
+	EncapContentInfo encapsulatedContentInfo
+	Certificates     asn1.RawValue `asn1:"optional,tag:0"`
+	CRLs             asn1.RawValue `asn1:"optional,tag:1"`
+	SignerInfos      []signerInfo  `asn1:"set"`
+}
+
+// encapsulatedContentInfo is the signed content, for detached signatures
+// the content is not included.
+type encapsulatedContentInfo struct {
+	ContentType asn1.ObjectIdentifier
+	Content     []byte `asn1:"explicit,optional,tag:0"`
+}
+
+// signerInfo is the CMS SignerInfo (RFC 5652 section 5.3).
+type signerInfo struct {
+	Version            int
+	SignerIdentifier   asn1.RawValue
+	DigestAlgorithm    pkix.AlgorithmIdentifier
+	SignedAttributes   asn1.RawValue `asn1:"optional,tag:0"`
+	SignatureAlgorithm pkix.AlgorithmIdentifier
+	Signature          []byte
+	UnsignedAttributes asn1.RawValue `asn1:"optional,tag:1"`
+}
+
+// issuerAndSerialNumber identifies the certificate of the signer.
+type issuerAndSerialNumber struct {
+	Issuer       asn1.RawValue
+	SerialNumber *big.Int
+}
+
+// attribute is a signed or unsigned attribute of a signer.
+type attribute struct {
+	Type   asn1.ObjectIdentifier
+	Values asn1.RawValue `asn1:"set"`
+}
+
+// tstInfo is the content of a time stamp token (RFC 3161 section 2.4.2),
+// only the fields up to the time are parsed.
+type tstInfo struct {
+	Version        int
+	Policy         asn1.ObjectIdentifier
+	MessageImprint messageImprint
+	SerialNumber   *big.Int
+	GenTime        time.Time `asn1:"generalized"`
+	Rest           asn1.RawContent
+}
+
+// messageImprint is the hash of the time stamped data.
+type messageImprint struct {
+	HashAlgorithm pkix.AlgorithmIdentifier
+	HashedMessage []byte
+}
+
+// parsedSignature is a parsed CMS signature with a single signer.
+type parsedSignature struct {
+	contentType      asn1.ObjectIdentifier
+	content          []byte // The encapsulated content, nil for detached signatures.
+	certificates     []*x509.Certificate
+	signer           signerInfo
+	signerCertifcate *x509.Certificate
+	digestAlgorithm  crypto.Hash
+	signedAttributes []attribute
+}
+
+// parseSignature parses the contents of a PDF signature as CMS SignedData.
+func parseSignature(contents []byte) (*parsedSignature, error) {
+	der, err := berToDER(contents)
+	if err != nil {
+		return nil, err
+	}
+
+	info := contentInfo{}
+	_, err = asn1.Unmarshal(der, &info)
+	if err != nil {
+		return nil, fmt.Errorf("could not parse content info: %w", err)
+	}
+
+	if !info.ContentType.Equal(oidSignedData) {
+		return nil, fmt.Errorf("content type %s is not signed data", info.ContentType.String())
+	}
+
+	data := signedData{}
+	_, err = asn1.Unmarshal(info.Content.Bytes, &data)
+	if err != nil {
+		return nil, fmt.Errorf("could not parse signed data: %w", err)
+	}
+
+	if len(data.SignerInfos) != 1 {
+		return nil, fmt.Errorf("signed data has %d signers, expected 1", len(data.SignerInfos))
+	}
+
+	signature := &parsedSignature{
+		contentType: data.EncapContentInfo.ContentType,
+		content:     data.EncapContentInfo.Content,
+		signer:      data.SignerInfos[0],
+	}
+
+	signature.certificates, err = parseCertificates(data.Certificates.Bytes)
+	if err != nil {
+		return nil, err
+	}
+
+	signature.signerCertifcate, err = findSignerCertificate(signature.signer.SignerIdentifier, signature.certificates)
+	if err != nil {
+		return nil, err
+	}
+
+	signature.digestAlgorithm, err = getDigestAlgorithm(signature.signer.DigestAlgorithm.Algorithm)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(signature.signer.SignedAttributes.FullBytes) > 0 {
+		_, err = asn1.UnmarshalWithParams(signedAttributesSet(signature.signer.SignedAttributes), &signature.signedAttributes, "set")
+		if err != nil {
+			return nil, fmt.Errorf("could not parse signed attributes: %w", err)
+		}
+	}
+
+	return signature, nil
+}
+
+// parseCertificates parses the certificates of the signed data, other
+// kinds of certificates, like attribute certificates, are skipped.
+func parseCertificates(data []byte) ([]*x509.Certificate, error) {
+	certificates := []*x509.Certificate{}
+	for len(data) > 0 {
+		element := asn1.RawValue{}
+		rest, err := asn1.Unmarshal(data, &element)
+		if err != nil {
+			return nil, fmt.Errorf("could not parse certificates: %w", err)
+		}
+		data = rest
+
+		if element.Class != asn1.ClassUniversal || element.Tag != asn1.TagSequence {
+			continue
+		}
+
+		certificate, err := x509.ParseCertificate(element.FullBytes)
+		if err != nil {
+			return nil, fmt.Errorf("could not parse certificate: %w", err)
+		}
+		certificates = append(certificates, certificate)
+	}
+
+	return certificates, nil
+}
+
+// findSignerCertificate returns the certificate of the signer, by issuer and
+// serial number or by subject key identifier.
+func findSignerCertificate(identifier asn1.RawValue, certificates []*x509.Certificate) (*x509.Certificate, error) {
+	if identifier.Class == asn1.ClassContextSpecific && identifier.Tag == 0 {
+		for _, certificate := range certificates {
+			if len(certificate.SubjectKeyId) > 0 && bytes.Equal(certificate.SubjectKeyId, identifier.Bytes) {
+				return certificate, nil
+			}
+		}
+		return nil, errors.New("the certificate of the signer is not included")
+	}
+
+	issuerAndSerial := issuerAndSerialNumber{}
+	_, err := asn1.Unmarshal(identifier.FullBytes, &issuerAndSerial)
+	if err != nil {
+		return nil, fmt.Errorf("could not parse signer identifier: %w", err)
+	}
+
+	for _, certificate := range certificates {
+		if certificate.SerialNumber.Cmp(issuerAndSerial.SerialNumber) == 0 && bytes.Equal(certificate.RawIssuer, issuerAndSerial.Issuer.FullBytes) {
+			return certificate, nil
+		}
+	}
+
+	return nil, errors.New("the certificate of the signer is not included")
+}
+
+// signedAttributesSet returns the DER of the signed attributes as a SET, the
+// signature is calculated over this encoding instead of the implicitly
+// tagged encoding in the signer info.
+func signedAttributesSet(signedAttributes asn1.RawValue) []byte {
+	set := append([]byte{}, signedAttributes.FullBytes...)
+	set[0] = 0x31
+	return set
+}
+
+// getAttribute returns the value of the signed attribute with the given
+// type, nil when the attribute is not there.
+func (s *parsedSignature) getAttribute(attributeType asn1.ObjectIdentifier) []byte {
+	for _, signedAttribute := range s.signedAttributes {
+		if signedAttribute.Type.Equal(attributeType) {
+			return signedAttribute.Values.Bytes
+		}
+	}
+	return nil
+}
+
+// getDigestAlgorithm returns the hash of a digest algorithm. Some signers
+// give the signature algorithm as digest algorithm, these are accepted too.
+func getDigestAlgorithm(algorithm asn1.ObjectIdentifier) (crypto.Hash, error) {
+	switch {
+	case algorithm.Equal(oidSHA1), algorithm.Equal(oidSHA1WithRSA), algorithm.Equal(oidECDSAWithSHA1):
+		return crypto.SHA1, nil
+	case algorithm.Equal(oidSHA224), algorithm.Equal(oidSHA224WithRSA), algorithm.Equal(oidECDSAWithSHA224):
+		return crypto.SHA224, nil
+	case algorithm.Equal(oidSHA256), algorithm.Equal(oidSHA256WithRSA), algorithm.Equal(oidECDSAWithSHA256):
+		return crypto.SHA256, nil
+	case algorithm.Equal(oidSHA384), algorithm.Equal(oidSHA384WithRSA), algorithm.Equal(oidECDSAWithSHA384):
+		return crypto.SHA384, nil
+	case algorithm.Equal(oidSHA512), algorithm.Equal(oidSHA512WithRSA), algorithm.Equal(oidECDSAWithSHA512):
+		return crypto.SHA512, nil
+	}
+
+	return 0, fmt.Errorf("digest algorithm %s is not supported", algorithm.String())
+}
@@ -0,0 +1,367 @@
+package pdf_signature
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/klippa-app/go-pdfium/internal/pdf_update"
+	"github.com/klippa-app/go-pdfium/structs"
+)
+
+// byteRangePlaceholder is written as the values of the byte range before the
+// offsets are known, it is wide enough for files up to 10 GB.
+const byteRangePlaceholder = 9999999999
+
+// Field is a signature field to add to a document.
+type Field struct {
+	Name         string // The name of the field. When empty, a name like Signature1 that is not used yet.
+	Page         int    // The page (0-index based) to put the widget of the field on.
+	SignerName   string // The name of the signer (Name).
+	Reason       string
+	Location     string
+	ContactInfo  string
+	SigningTime  time.Time
+	ContentsSize int         // The amount of bytes to reserve for the signature.
+	Appearance   *Appearance // The appearance of the signature, nil for an invisible signature.
+}
+
+// Appearance is the appearance of a visible signature, in page coordinates.
+type Appearance struct {
+	Rect      structs.FPDF_FS_RECTF // The rect of the widget on the page.
+	Content   string                // The content stream that draws the text.
+	Image     []byte                // A JPEG or PNG image to draw, nil for no image.
+	ImageRect structs.FPDF_FS_RECTF // The rect to draw the image in.
+}
+
+// Placeholder is a file with a signature field that has a placeholder for
+// the signature.
+type Placeholder struct {
+	File      []byte
+	ByteRange []int
+	FieldName string // The name of the signature field.
+}
+
+// Prepare appends an incremental update to the file that adds a signature
+// field with a widget on the given page and a signature dictionary with a
+// placeholder for the contents.
+func Prepare(data []byte, field Field) (*Placeholder, error) {
+	file, err := pdf_update.Parse(data)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, ok := file.Trailer()["Encrypt"]; ok {
+		return nil, errors.New("can not sign an encrypted document, save the document without security")
+	}
+
+	update := file.NewUpdate()
+
+	pages, err := update.PageReferences()
+	if err != nil {
+		return nil, err
+	}
+
+	if field.Page < 0 || field.Page >= len(pages) {
+		return nil, fmt.Errorf("page %d is out of range, document has %d pages", field.Page, len(pages))
+	}
+	pageReference := pages[field.Page]
+
+	catalogReference, catalog, err := update.Catalog()
+	if err != nil {
+		return nil, err
+	}
+
+	acroForm := pdf_update.Dictionary{}
+	acroFormReference, acroFormIsReference := catalog["AcroForm"].(pdf_update.Reference)
+	acroFormObject, err := update.Resolve(catalog["AcroForm"])
+	if err != nil {
+		return nil, err
+	}
+	if existingAcroForm, ok := acroFormObject.(pdf_update.Dictionary); ok {
+		acroForm = existingAcroForm.Copy()
+	}
+
+	fields := pdf_update.Array{}
+	fieldsReference, fieldsIsReference := acroForm["Fields"].(pdf_update.Reference)
+	fieldsObject, err := update.Resolve(acroForm["Fields"])
+	if err != nil {
+		return nil, err
+	}
+	if existingFields, ok := fieldsObject.(pdf_update.Array); ok {
+		fields = append(fields, existingFields...)
+	}
+
+	name, err := getFieldName(update, fields, field.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	signatureReference := update.Add(signatureDictionary(field))
+
+	rect := pdf_update.Array{pdf_update.Integer(0), pdf_update.Integer(0), pdf_update.Integer(0), pdf_update.Integer(0)}
+	if field.Appearance != nil {
+		rect = rectArray(field.Appearance.Rect)
+	}
+
+	appearanceReference, err := addAppearance(update, field.Appearance)
+	if err != nil {
+		return nil, err
+	}
+
+	widgetReference := update.Add(pdf_update.Dictionary{
+		"Type":    pdf_update.Name("Annot"),
+		"Subtype": pdf_update.Name("Widget"),
+		"FT":      pdf_update.Name("Sig"),
+		"T":       pdf_update.TextString(name),
+		"V":       signatureReference,
+		"P":       pageReference,
+		"Rect":    rect,
+		"F":       pdf_update.Integer(132), // Print and locked.
+		"AP":      pdf_update.Dictionary{"N": appearanceReference},
+	})
+
+	// Add the widget to the annotations of the page.
+	pageObject, err := update.Object(pageReference.Number)
+	if err != nil {
+		return nil, err
+	}
+	page, ok := pageObject.(pdf_update.Dictionary)
+	if !ok {
+		return nil, errors.New("page is not a dictionary")
+	}
+	page = page.Copy()
+
+	annotationsReference, annotationsIsReference := page["Annots"].(pdf_update.Reference)
+	annotations := pdf_update.Array{}
+	annotationsObject, err := update.Resolve(page["Annots"])
+	if err != nil {
+		return nil, err
+	}
+	if existingAnnotations, ok := annotationsObject.(pdf_update.Array); ok {
+		annotations = append(annotations, existingAnnotations...)
+	}
+	annotations = append(annotations, widgetReference)
+
+	if annotationsIsReference {
+		update.Set(annotationsReference.Number, annotations)
+	} else {
+		page["Annots"] = annotations
+		update.Set(pageReference.Number, page)
+	}
+
+	// Add the field to the form.
+	fields = append(fields, widgetReference)
+	if fieldsIsReference {
+		update.Set(fieldsReference.Number, fields)
+	} else {
+		acroForm["Fields"] = fields
+	}
+
+	sigFlags, _ := acroForm["SigFlags"].(pdf_update.Integer)
+	acroForm["SigFlags"] = sigFlags | 3 // Signatures exist and append only.
+
+	if acroFormIsReference {
+		update.Set(acroFormReference.Number, acroForm)
+	} else {
+		catalog["AcroForm"] = acroForm
+		update.Set(catalogReference.Number, catalog)
+	}
+
+	buf := &bytes.Buffer{}
+	if _, err := update.WriteTo(buf); err != nil {
+		return nil, err
+	}
+
+	placeholder, err := newPlaceholder(buf.Bytes(), len(data), field.ContentsSize)
+	if err != nil {
+		return nil, err
+	}
+	placeholder.FieldName = name
+
+	return placeholder, nil
+}
+
+// newPlaceholder finds the placeholders of the signature in the update and
+// fills in the byte range.
+func newPlaceholder(file []byte, updateOffset int, contentsSize int) (*Placeholder, error) {
+	placeholderValue := strconv.Itoa(byteRangePlaceholder)
+	byteRangeMarker := []byte("/ByteRange [0 " + placeholderValue + " " + placeholderValue + " " + placeholderValue + "]")
+	byteRangeOffset := bytes.Index(file[updateOffset:], byteRangeMarker)
+	if byteRangeOffset == -1 {
+		return nil, errors.New("could not find the byte range placeholder")
+	}
+	byteRangeOffset += updateOffset
+
+	contentsMarker := []byte("/Contents <" + string(bytes.Repeat([]byte("0"), contentsSize*2)) + ">")
+	contentsOffset := bytes.Index(file[byteRangeOffset:], contentsMarker)
+	if contentsOffset == -1 {
+		return nil, errors.New("could not find the contents placeholder")
+	}
+	contentsOffset += byteRangeOffset + len("/Contents ")
+
+	contentsEnd := contentsOffset + contentsSize*2 + 2
+	byteRange := []int{0, contentsOffset, contentsEnd, len(file) - contentsEnd}
+
+	// The byte range is padded with spaces so that the offsets stay the same.
+	byteRangeValue := []byte(fmt.Sprintf("/ByteRange [%d %d %d %d]", byteRange[0], byteRange[1], byteRange[2], byteRange[3]))
+	byteRangeValue = append(byteRangeValue, bytes.Repeat([]byte(" "), len(byteRangeMarker)-len(byteRangeValue))...)
+	copy(file[byteRangeOffset:], byteRangeValue)
+
+	return &Placeholder{
+		File:      file,
+		ByteRange: byteRange,
+	}, nil
+}
+
+// SignedData returns the data of the file that the signature covers.
+func (p *Placeholder) SignedData() []byte {
+	signedData := make([]byte, 0, p.ByteRange[1]+p.ByteRange[3])
+	signedData = append(signedData, p.File[:p.ByteRange[1]]...)
+	signedData = append(signedData, p.File[p.ByteRange[2]:]...)
+	return signedData
+}
+
+// Embed writes the signature into the placeholder.
+func (p *Placeholder) Embed(contents []byte) error {
+	size := (p.ByteRange[2] - p.ByteRange[1] - 2) / 2
+	if len(contents) > size {
+		return fmt.Errorf("the signature of %d bytes doesn't fit the %d reserved bytes", len(contents), size)
+	}
+
+	encoded := []byte(hex.EncodeToString(contents))
+	copy(p.File[p.ByteRange[1]+1:], bytes.ToUpper(encoded))
+	return nil
+}
+
+// signatureDictionary returns the signature dictionary with the
+// placeholders.
+func signatureDictionary(field Field) pdf_update.Dictionary {
+	signature := pdf_update.Dictionary{
+		"Type":      pdf_update.Name("Sig"),
+		"Filter":    pdf_update.Name("Adobe.PPKLite"),
+		"SubFilter": pdf_update.Name(SubFilterPKCS7Detached),
+		"M":         pdf_update.String(formatDate(field.SigningTime)),
+		"ByteRange": pdf_update.Array{pdf_update.Integer(0), pdf_update.Integer(byteRangePlaceholder), pdf_update.Integer(byteRangePlaceholder), pdf_update.Integer(byteRangePlaceholder)},
+		"Contents":  pdf_update.HexString(make([]byte, field.ContentsSize)),
+	}
+
+	for key, value := range map[pdf_update.Name]string{
+		"Name":        field.SignerName,
+		"Reason":      field.Reason,
+		"Location":    field.Location,
+		"ContactInfo": field.ContactInfo,
+	} {
+		if value != "" {
+			signature[key] = pdf_update.TextString(value)
+		}
+	}
+
+	return signature
+}
+
+// getFieldName returns the given name when no field has it yet, or a new
+// name when no name is given.
+func getFieldName(update *pdf_update.Update, fields pdf_update.Array, name string) (string, error) {
+	names := map[string]bool{}
+	for _, field := range fields {
+		fieldObject, err := update.Resolve(field)
+		if err != nil {
+			return "", err
+		}
+
+		fieldDictionary, ok := fieldObject.(pdf_update.Dictionary)
+		if !ok {
+			continue
+		}
+
+		if fieldName, ok := fieldDictionary["T"].(pdf_update.String); ok {
+			names[pdf_update.DecodeTextString(fieldName)] = true
+		}
+	}
+
+	if name != "" {
+		if names[name] {
+			return "", fmt.Errorf("a field with name %s already exists", name)
+		}
+		return name, nil
+	}
+
+	for i := 1; ; i++ {
+		name = fmt.Sprintf("Signature%d", i)
+		if !names[name] {
+			return name, nil
+		}
+	}
+}
+
+// addAppearance adds the appearance stream of the widget, for invisible
+// signatures the appearance is empty.
+func addAppearance(update *pdf_update.Update, appearance *Appearance) (pdf_update.Reference, error) {
+	if appearance == nil {
+		return update.Add(&pdf_update.Stream{
+			Dictionary: pdf_update.Dictionary{
+				"Type":    pdf_update.Name("XObject"),
+				"Subtype": pdf_update.Name("Form"),
+				"BBox":    pdf_update.Array{pdf_update.Integer(0), pdf_update.Integer(0), pdf_update.Integer(0), pdf_update.Integer(0)},
+			},
+		}), nil
+	}
+
+	resources := pdf_update.Dictionary{}
+	content := appearance.Content
+	if appearance.Image != nil {
+		imageReference, err := addImage(update, appearance.Image)
+		if err != nil {
+			return pdf_update.Reference{}, err
+		}
+
+		resources["XObject"] = pdf_update.Dictionary{"Im1": imageReference}
+
+		imageRect := appearance.ImageRect
+		content = fmt.Sprintf("q\n%s 0 0 %s %s %s cm\n/Im1 Do\nQ\n", formatNumber(imageRect.Right-imageRect.Left), formatNumber(imageRect.Top-imageRect.Bottom), formatNumber(imageRect.Left), formatNumber(imageRect.Bottom)) + content
+	}
+
+	// The bounding box is the rect of the widget, so that the content can
+	// be drawn in page coordinates.
+	return update.Add(&pdf_update.Stream{
+		Dictionary: pdf_update.Dictionary{
+			"Type":      pdf_update.Name("XObject"),
+			"Subtype":   pdf_update.Name("Form"),
+			"BBox":      rectArray(appearance.Rect),
+			"Resources": resources,
+		},
+		Data: []byte(content),
+	}), nil
+}
+
+// rectArray returns the rect as PDF array.
+func rectArray(rect structs.FPDF_FS_RECTF) pdf_update.Array {
+	return pdf_update.Array{realNumber(rect.Left), realNumber(rect.Bottom), realNumber(rect.Right), realNumber(rect.Top)}
+}
+
+// realNumber returns the value as PDF real, rounded to 3 decimals so that
+// the float32 precision doesn't show.
+func realNumber(value float32) pdf_update.Real {
+	return pdf_update.Real(math.Round(float64(value)*1000) / 1000)
+}
+
+// formatNumber formats a number for a content stream.
+func formatNumber(value float32) string {
+	return strconv.FormatFloat(float64(realNumber(value)), 'f', -1, 64)
+}
+
+// formatDate formats a time as PDF date string.
+func formatDate(date time.Time) string {
+	_, offset := date.Zone()
+	sign := "+"
+	if offset < 0 {
+		sign = "-"
+		offset = -offset
+	}
+	return fmt.Sprintf("D:%s%s%02d'%02d'", date.Format("20060102150405"), sign, offset/3600, offset%3600/60)
+}
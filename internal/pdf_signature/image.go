@@ -0,0 +1,114 @@
+package pdf_signature
+
+import (
+	"bytes"
+	"compress/zlib"
+	"errors"
+	"image"
+	"image/color"
+	_ "image/jpeg"
+	_ "image/png"
+
+	"github.com/klippa-app/go-pdfium/internal/pdf_update"
+)
+
+// ImageSize returns the size in pixels of a JPEG or PNG image.
+func ImageSize(data []byte) (int, int, error) {
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return 0, 0, errors.New("could not read image, only JPEG and PNG are supported")
+	}
+
+	return config.Width, config.Height, nil
+}
+
+// addImage adds the image XObject of a JPEG or PNG image to the update.
+// Gray and RGB JPEG images are embedded as is, other images are embedded as
+// RGB, with a soft mask when they have transparency.
+func addImage(update *pdf_update.Update, data []byte) (pdf_update.Reference, error) {
+	config, format, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return pdf_update.Reference{}, errors.New("could not read image, only JPEG and PNG are supported")
+	}
+
+	if format == "jpeg" && (config.ColorModel == color.GrayModel || config.ColorModel == color.YCbCrModel) {
+		colorSpace := pdf_update.Name("DeviceRGB")
+		if config.ColorModel == color.GrayModel {
+			colorSpace = pdf_update.Name("DeviceGray")
+		}
+
+		return update.Add(&pdf_update.Stream{
+			Dictionary: pdf_update.Dictionary{
+				"Type":             pdf_update.Name("XObject"),
+				"Subtype":          pdf_update.Name("Image"),
+				"Width":            pdf_update.Integer(config.Width),
+				"Height":           pdf_update.Integer(config.Height),
+				"ColorSpace":       colorSpace,
+				"BitsPerComponent": pdf_update.Integer(8),
+				"Filter":           pdf_update.Name("DCTDecode"),
+			},
+			Data: data,
+		}), nil
+	}
+
+	decoded, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return pdf_update.Reference{}, errors.New("could not read image, only JPEG and PNG are supported")
+	}
+
+	bounds := decoded.Bounds()
+	rgb := make([]byte, 0, bounds.Dx()*bounds.Dy()*3)
+	alpha := make([]byte, 0, bounds.Dx()*bounds.Dy())
+	transparent := false
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			pixel := color.NRGBAModel.Convert(decoded.At(x, y)).(color.NRGBA)
+			rgb = append(rgb, pixel.R, pixel.G, pixel.B)
+			alpha = append(alpha, pixel.A)
+			if pixel.A != 0xff {
+				transparent = true
+			}
+		}
+	}
+
+	imageStream, err := flateImage(rgb, bounds.Dx(), bounds.Dy(), "DeviceRGB")
+	if err != nil {
+		return pdf_update.Reference{}, err
+	}
+
+	if transparent {
+		maskStream, err := flateImage(alpha, bounds.Dx(), bounds.Dy(), "DeviceGray")
+		if err != nil {
+			return pdf_update.Reference{}, err
+		}
+		imageStream.Dictionary["SMask"] = update.Add(maskStream)
+	}
+
+	return update.Add(imageStream), nil
+}
+
+// flateImage returns the image XObject of raw 8-bit samples, compressed with
+// Flate.
+func flateImage(samples []byte, width, height int, colorSpace pdf_update.Name) (*pdf_update.Stream, error) {
+	compressed := &bytes.Buffer{}
+	writer := zlib.NewWriter(compressed)
+	if _, err := writer.Write(samples); err != nil {
+		return nil, err
+	}
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+
+	return &pdf_update.Stream{
+		Dictionary: pdf_update.Dictionary{
+			"Type":             pdf_update.Name("XObject"),
+			"Subtype":          pdf_update.Name("Image"),
+			"Width":            pdf_update.Integer(width),
+			"Height":           pdf_update.Integer(height),
+			"ColorSpace":       colorSpace,
+			"BitsPerComponent": pdf_update.Integer(8),
+			"Filter":           pdf_update.Name("FlateDecode"),
+		},
+		Data: compressed.Bytes(),
+	}, nil
+}
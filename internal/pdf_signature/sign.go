@@ -0,0 +1,221 @@
+package pdf_signature
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+)
+
+var oidData = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 7, 1}
+
+// Sign creates a detached CMS signature of the data with SHA-256, or with
+// SHA-512 for Ed25519 keys. The certificate of the signer and the chain are
+// included in the signature.
+func Sign(data []byte, signer crypto.Signer, certificate *x509.Certificate, chain []*x509.Certificate, signingTime time.Time) ([]byte, error) {
+	signatureAlgorithm, hash, err := getSignatureAlgorithm(signer.Public())
+	if err != nil {
+		return nil, err
+	}
+
+	digestAlgorithm := pkix.AlgorithmIdentifier{Algorithm: oidSHA256}
+	if hash == crypto.SHA512 {
+		digestAlgorithm = pkix.AlgorithmIdentifier{Algorithm: oidSHA512}
+	}
+
+	messageDigest, err := asn1.Marshal(digest(hash, data))
+	if err != nil {
+		return nil, err
+	}
+
+	signingTimeValue, err := asn1.Marshal(signingTime.UTC())
+	if err != nil {
+		return nil, err
+	}
+
+	contentType, err := asn1.Marshal(oidData)
+	if err != nil {
+		return nil, err
+	}
+
+	signedAttributes, err := marshalAttributes([]attribute{
+		newAttribute(oidContentType, contentType),
+		newAttribute(oidSigningTime, signingTimeValue),
+		newAttribute(oidMessageDigest, messageDigest),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	message := signedAttributesSet(signedAttributes)
+	var signature []byte
+	if signatureAlgorithm.Algorithm.Equal(oidEd25519) {
+		// Ed25519 signs the message itself.
+		signature, err = signer.Sign(rand.Reader, message, crypto.Hash(0))
+	} else {
+		signature, err = signer.Sign(rand.Reader, digest(hash, message), hash)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("could not sign: %w", err)
+	}
+
+	signerIdentifier, err := asn1.Marshal(issuerAndSerialNumber{
+		Issuer:       asn1.RawValue{FullBytes: certificate.RawIssuer},
+		SerialNumber: certificate.SerialNumber,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	certificates := append([]byte{}, certificate.Raw...)
+	for _, chainCertificate := range chain {
+		certificates = append(certificates, chainCertificate.Raw...)
+	}
+
+	signed, err := asn1.Marshal(signedData{
+		Version:          1,
+		DigestAlgorithms: []pkix.AlgorithmIdentifier{digestAlgorithm},
+		EncapContentInfo: encapsulatedContentInfo{ContentType: oidData},
+		Certificates:     asn1.RawValue{Class: asn1.ClassContextSpecific, Tag: 0, IsCompound: true, Bytes: certificates},
+		SignerInfos: []signerInfo{{
+			Version:            1,
+			SignerIdentifier:   asn1.RawValue{FullBytes: signerIdentifier},
+			DigestAlgorithm:    digestAlgorithm,
+			SignedAttributes:   signedAttributes,
+			SignatureAlgorithm: signatureAlgorithm,
+			Signature:          signature,
+		}},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("could not create signed data: %w", err)
+	}
+
+	return asn1.Marshal(contentInfo{
+		ContentType: oidSignedData,
+		Content:     asn1.RawValue{Class: asn1.ClassContextSpecific, Tag: 0, IsCompound: true, Bytes: signed},
+	})
+}
+
+// getSignatureAlgorithm returns the signature algorithm for the public key
+// of the signer, and the digest algorithm to use with it.
+func getSignatureAlgorithm(publicKey crypto.PublicKey) (pkix.AlgorithmIdentifier, crypto.Hash, error) {
+	switch publicKey.(type) {
+	case *rsa.PublicKey:
+		return pkix.AlgorithmIdentifier{Algorithm: oidRSAEncryption, Parameters: asn1.NullRawValue}, crypto.SHA256, nil
+	case *ecdsa.PublicKey:
+		return pkix.AlgorithmIdentifier{Algorithm: oidECDSAWithSHA256}, crypto.SHA256, nil
+	case ed25519.PublicKey:
+		return pkix.AlgorithmIdentifier{Algorithm: oidEd25519}, crypto.SHA512, nil
+	}
+
+	return pkix.AlgorithmIdentifier{}, 0, fmt.Errorf("public key of type %T is %w", publicKey, ErrUnsupported)
+}
+
+// newAttribute returns an attribute with a single DER encoded value.
+func newAttribute(attributeType asn1.ObjectIdentifier, value []byte) attribute {
+	return attribute{
+		Type:   attributeType,
+		Values: asn1.RawValue{Class: asn1.ClassUniversal, Tag: asn1.TagSet, IsCompound: true, Bytes: value},
+	}
+}
+
+// marshalAttributes encodes the attributes as the implicitly tagged signed
+// attributes of a signer. DER requires the attributes of the SET to be
+// sorted by their encoding.
+func marshalAttributes(attributes []attribute) (asn1.RawValue, error) {
+	encoded := [][]byte{}
+	for i := range attributes {
+		attributeDER, err := asn1.Marshal(attributes[i])
+		if err != nil {
+			return asn1.RawValue{}, err
+		}
+		encoded = append(encoded, attributeDER)
+	}
+
+	sort.Slice(encoded, func(i, j int) bool {
+		return bytes.Compare(encoded[i], encoded[j]) < 0
+	})
+
+	signedAttributes := asn1.RawValue{Class: asn1.ClassContextSpecific, Tag: 0, IsCompound: true, Bytes: bytes.Join(encoded, nil)}
+	signedAttributesDER, err := asn1.Marshal(signedAttributes)
+	if err != nil {
+		return asn1.RawValue{}, err
+	}
+	signedAttributes.FullBytes = signedAttributesDER
+
+	return signedAttributes, nil
+}
+
+// ParseCertificates parses certificates that are DER, or PEM with one or
+// more certificates.
+func ParseCertificates(data []byte) ([]*x509.Certificate, error) {
+	if !bytes.Contains(data, []byte("-----BEGIN")) {
+		certificate, err := x509.ParseCertificate(data)
+		if err != nil {
+			return nil, err
+		}
+		return []*x509.Certificate{certificate}, nil
+	}
+
+	certificates := []*x509.Certificate{}
+	rest := data
+	for {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			break
+		}
+
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+
+		certificate, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		certificates = append(certificates, certificate)
+	}
+
+	if len(certificates) == 0 {
+		return nil, errors.New("no certificate found in PEM")
+	}
+
+	return certificates, nil
+}
+
+// ParsePrivateKey parses a PKCS#1, PKCS#8 or SEC 1 private key that is DER
+// or PEM.
+func ParsePrivateKey(data []byte) (crypto.Signer, error) {
+	if block, _ := pem.Decode(data); block != nil {
+		data = block.Bytes
+	}
+
+	if key, err := x509.ParsePKCS8PrivateKey(data); err == nil {
+		signer, ok := key.(crypto.Signer)
+		if !ok {
+			return nil, fmt.Errorf("private key of type %T is %w", key, ErrUnsupported)
+		}
+		return signer, nil
+	}
+
+	if key, err := x509.ParsePKCS1PrivateKey(data); err == nil {
+		return key, nil
+	}
+
+	if key, err := x509.ParseECPrivateKey(data); err == nil {
+		return key, nil
+	}
+
+	return nil, errors.New("could not parse private key")
+}
@@ -0,0 +1,289 @@
+package pdf_signature
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"testing"
+	"time"
+
+	"github.com/klippa-app/go-pdfium/internal/pdf_update"
+	"github.com/klippa-app/go-pdfium/structs"
+)
+
+// buildTestFile creates a minimal PDF file with two pages.
+func buildTestFile() []byte {
+	objects := []string{
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [3 0 R 4 0 R] /Count 2 >>",
+		"<< /Type /Page /Parent 2 0 R /MediaBox [0 0 612 792] >>",
+		"<< /Type /Page /Parent 2 0 R /MediaBox [0 0 612 792] /Annots [] >>",
+	}
+
+	buf := &bytes.Buffer{}
+	buf.WriteString("%PDF-1.4\n")
+	offsets := []int{}
+	for i, object := range objects {
+		offsets = append(offsets, buf.Len())
+		buf.WriteString(fmt.Sprintf("%d 0 obj\n%s\nendobj\n", i+1, object))
+	}
+
+	xrefOffset := buf.Len()
+	buf.WriteString(fmt.Sprintf("xref\n0 %d\n0000000000 65535 f\r\n", len(objects)+1))
+	for _, offset := range offsets {
+		buf.WriteString(fmt.Sprintf("%010d 00000 n\r\n", offset))
+	}
+	buf.WriteString(fmt.Sprintf("trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(objects)+1, xrefOffset))
+
+	return buf.Bytes()
+}
+
+func TestSign(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("could not generate key: %s", err.Error())
+	}
+
+	ecdsaKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("could not generate key: %s", err.Error())
+	}
+
+	_, ed25519Key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("could not generate key: %s", err.Error())
+	}
+
+	data := []byte("the signed data of the document")
+	for _, key := range []crypto.Signer{rsaKey, ecdsaKey, ed25519Key} {
+		signer := createTestSigner(t, key)
+		contents, err := Sign(data, key, signer.certificate, []*x509.Certificate{signer.root}, testSigningTime)
+		if err != nil {
+			t.Fatalf("Sign resulted in error: %s", err.Error())
+		}
+
+		roots := x509.NewCertPool()
+		roots.AddCert(signer.root)
+
+		result, err := Verify(contents, data, SubFilterPKCS7Detached, roots, testSigningTime)
+		if err != nil {
+			t.Fatalf("Verify resulted in error: %s", err.Error())
+		}
+
+		if !result.DigestValid || !result.SignatureValid || !result.Trusted {
+			t.Fatalf("Verify resulted in wrong result for %T, got %+v", key, result)
+		}
+
+		if len(result.Certificates) != 2 || result.SigningTime == nil || !result.SigningTime.Equal(testSigningTime) {
+			t.Fatalf("Verify resulted in wrong signer for %T, got %+v", key, result)
+		}
+	}
+}
+
+func TestParsePrivateKey(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("could not generate key: %s", err.Error())
+	}
+
+	pkcs8, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatalf("could not marshal key: %s", err.Error())
+	}
+
+	sec1, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatalf("could not marshal key: %s", err.Error())
+	}
+
+	for _, data := range [][]byte{
+		pkcs8,
+		pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: pkcs8}),
+		pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: sec1}),
+	} {
+		signer, err := ParsePrivateKey(data)
+		if err != nil {
+			t.Fatalf("ParsePrivateKey resulted in error: %s", err.Error())
+		}
+
+		if !key.PublicKey.Equal(signer.Public()) {
+			t.Fatalf("ParsePrivateKey resulted in wrong key")
+		}
+	}
+
+	_, err = ParsePrivateKey([]byte("not a key"))
+	if err == nil || err.Error() != "could not parse private key" {
+		t.Fatalf("ParsePrivateKey resulted in wrong error, got %v", err)
+	}
+}
+
+func TestPrepare(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("could not generate key: %s", err.Error())
+	}
+	signer := createTestSigner(t, key)
+
+	data := buildTestFile()
+	placeholder, err := Prepare(data, Field{
+		Page:         1,
+		Reason:       "Approved",
+		SigningTime:  testSigningTime,
+		ContentsSize: 4096,
+		Appearance: &Appearance{
+			Rect:    structs.FPDF_FS_RECTF{Left: 50, Top: 150, Right: 250, Bottom: 100},
+			Content: "BT /Helv 10 Tf 60 120 Td (Approved) Tj ET\n",
+		},
+	})
+	if err != nil {
+		t.Fatalf("Prepare resulted in error: %s", err.Error())
+	}
+
+	if !bytes.HasPrefix(placeholder.File, data) {
+		t.Fatalf("Prepare didn't append an incremental update")
+	}
+
+	if placeholder.ByteRange[0] != 0 || placeholder.ByteRange[2]-placeholder.ByteRange[1] != 4096*2+2 || placeholder.ByteRange[2]+placeholder.ByteRange[3] != len(placeholder.File) {
+		t.Fatalf("Prepare resulted in wrong byte range, got %v", placeholder.ByteRange)
+	}
+
+	expectedByteRange := fmt.Sprintf("/ByteRange [0 %d %d %d]", placeholder.ByteRange[1], placeholder.ByteRange[2], placeholder.ByteRange[3])
+	if !bytes.Contains(placeholder.File, []byte(expectedByteRange)) {
+		t.Fatalf("Prepare didn't write the byte range %s", expectedByteRange)
+	}
+
+	contents, err := Sign(placeholder.SignedData(), key, signer.certificate, nil, testSigningTime)
+	if err != nil {
+		t.Fatalf("Sign resulted in error: %s", err.Error())
+	}
+
+	if err := placeholder.Embed(contents); err != nil {
+		t.Fatalf("Embed resulted in error: %s", err.Error())
+	}
+
+	paddedContents := append(contents, make([]byte, 4096-len(contents))...)
+	signedData, err := SignedData(placeholder.File, placeholder.ByteRange, paddedContents)
+	if err != nil {
+		t.Fatalf("SignedData resulted in error: %s", err.Error())
+	}
+
+	result, err := Verify(paddedContents, signedData, SubFilterPKCS7Detached, nil, testSigningTime)
+	if err != nil {
+		t.Fatalf("Verify resulted in error: %s", err.Error())
+	}
+
+	if !result.DigestValid || !result.SignatureValid {
+		t.Fatalf("Verify resulted in wrong result, got %+v", result)
+	}
+
+	// The updated file has the field in the form and on the page.
+	file, err := pdf_update.Parse(placeholder.File)
+	if err != nil {
+		t.Fatalf("Parse resulted in error: %s", err.Error())
+	}
+
+	update := file.NewUpdate()
+	_, catalog, err := update.Catalog()
+	if err != nil {
+		t.Fatalf("Catalog resulted in error: %s", err.Error())
+	}
+
+	acroForm, _ := catalog["AcroForm"].(pdf_update.Dictionary)
+	fields, _ := acroForm["Fields"].(pdf_update.Array)
+	if len(fields) != 1 || acroForm["SigFlags"] != pdf_update.Integer(3) {
+		t.Fatalf("Prepare resulted in wrong form, got %v", acroForm)
+	}
+
+	page, err := update.Object(4)
+	if err != nil {
+		t.Fatalf("Object resulted in error: %s", err.Error())
+	}
+
+	annotations, _ := page.(pdf_update.Dictionary)["Annots"].(pdf_update.Array)
+	if len(annotations) != 1 || annotations[0] != fields[0] {
+		t.Fatalf("Prepare resulted in wrong annotations, got %v", annotations)
+	}
+
+	widget, err := update.Resolve(fields[0])
+	if err != nil {
+		t.Fatalf("Resolve resulted in error: %s", err.Error())
+	}
+
+	if name := pdf_update.DecodeTextString(widget.(pdf_update.Dictionary)["T"].(pdf_update.String)); name != "Signature1" || placeholder.FieldName != "Signature1" {
+		t.Fatalf("Prepare resulted in wrong name, got %s", name)
+	}
+
+	// A second signature gets the next name, and can't reuse a name.
+	_, err = Prepare(placeholder.File, Field{Page: 0, SigningTime: time.Now(), ContentsSize: 4096, Name: "Signature1"})
+	if err == nil || err.Error() != "a field with name Signature1 already exists" {
+		t.Fatalf("Prepare resulted in wrong error, got %v", err)
+	}
+
+	_, err = Prepare(placeholder.File, Field{Page: 2, SigningTime: time.Now(), ContentsSize: 4096})
+	if err == nil || err.Error() != "page 2 is out of range, document has 2 pages" {
+		t.Fatalf("Prepare resulted in wrong error, got %v", err)
+	}
+
+	err = placeholder.Embed(make([]byte, 4097))
+	if err == nil || err.Error() != "the signature of 4097 bytes doesn't fit the 4096 reserved bytes" {
+		t.Fatalf("Embed resulted in wrong error, got %v", err)
+	}
+}
+
+func TestAddImage(t *testing.T) {
+	transparent := image.NewNRGBA(image.Rect(0, 0, 4, 2))
+	transparent.Set(0, 0, color.NRGBA{R: 255, A: 128})
+	pngData := &bytes.Buffer{}
+	if err := png.Encode(pngData, transparent); err != nil {
+		t.Fatalf("could not encode image: %s", err.Error())
+	}
+
+	width, height, err := ImageSize(pngData.Bytes())
+	if err != nil {
+		t.Fatalf("ImageSize resulted in error: %s", err.Error())
+	}
+
+	if width != 4 || height != 2 {
+		t.Fatalf("ImageSize resulted in wrong size, got %dx%d", width, height)
+	}
+
+	file, err := pdf_update.Parse(buildTestFile())
+	if err != nil {
+		t.Fatalf("Parse resulted in error: %s", err.Error())
+	}
+
+	update := file.NewUpdate()
+	imageReference, err := addImage(update, pngData.Bytes())
+	if err != nil {
+		t.Fatalf("addImage resulted in error: %s", err.Error())
+	}
+
+	imageObject, err := update.Object(imageReference.Number)
+	if err != nil {
+		t.Fatalf("Object resulted in error: %s", err.Error())
+	}
+
+	imageStream := imageObject.(*pdf_update.Stream)
+	if imageStream.Dictionary["Filter"] != pdf_update.Name("FlateDecode") || imageStream.Dictionary["Width"] != pdf_update.Integer(4) {
+		t.Fatalf("addImage resulted in wrong image, got %v", imageStream.Dictionary)
+	}
+
+	if _, ok := imageStream.Dictionary["SMask"].(pdf_update.Reference); !ok {
+		t.Fatalf("addImage didn't add a soft mask for a transparent image")
+	}
+
+	_, err = addImage(update, []byte("not an image"))
+	if err == nil || err.Error() != "could not read image, only JPEG and PNG are supported" {
+		t.Fatalf("addImage resulted in wrong error, got %v", err)
+	}
+}
@@ -0,0 +1,280 @@
+package pdf_signature
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	_ "crypto/sha1"
+	_ "crypto/sha256"
+	_ "crypto/sha512"
+	"crypto/x509"
+	"encoding/asn1"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrUnsupported is returned when a signature uses a format or an algorithm
+// that can't be verified.
+var ErrUnsupported = errors.New("not supported")
+
+const (
+	SubFilterPKCS7Detached = "adbe.pkcs7.detached"
+	SubFilterPKCS7SHA1     = "adbe.pkcs7.sha1"
+	SubFilterX509RSASHA1   = "adbe.x509.rsa_sha1"
+	SubFilterCAdESDetached = "ETSI.CAdES.detached"
+	SubFilterRFC3161       = "ETSI.RFC3161"
+)
+
+// Result is the result of verifying a signature.
+type Result struct {
+	DigestAlgorithm crypto.Hash
+	DigestValid     bool                // Whether the signed data matches the signed digest.
+	SignatureValid  bool                // Whether the signature of the signer is valid.
+	Trusted         bool                // Whether the certificate of the signer chains up to a trusted root.
+	TrustError      error               // Why the certificate of the signer is not trusted.
+	Signer          *x509.Certificate   // The certificate of the signer.
+	Certificates    []*x509.Certificate // All the certificates included in the signature.
+	SigningTime     *time.Time          // The signing time of the signed attributes, or the time of the time stamp token.
+}
+
+// Verify verifies the CMS signature contents over the signed data, and
+// validates the certificate of the signer against the given roots. The
+// certificates of the system are never used, when no roots are given the
+// signer is never trusted. The certificate is validated at the given time,
+// the signing time that the signer claims can't be trusted. Only the time of
+// a valid time stamp token is used instead, as it is signed by the time
+// stamp authority.
+func Verify(contents []byte, signedData []byte, subFilter string, roots *x509.CertPool, now time.Time) (*Result, error) {
+	if subFilter == SubFilterX509RSASHA1 {
+		return nil, fmt.Errorf("sub filter %s is %w", subFilter, ErrUnsupported)
+	}
+
+	signature, err := parseSignature(contents)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &Result{
+		DigestAlgorithm: signature.digestAlgorithm,
+		Signer:          signature.signerCertifcate,
+		Certificates:    signature.certificates,
+	}
+
+	// The content that the signer signed, for detached signatures this is
+	// the signed data of the document itself.
+	signedContent := signedData
+	isTimeStampToken := subFilter == SubFilterRFC3161 || signature.contentType.Equal(oidTSTInfo)
+	switch {
+	case isTimeStampToken:
+		if signature.content == nil {
+			return nil, errors.New("time stamp token has no content")
+		}
+
+		info := tstInfo{}
+		_, err = asn1.Unmarshal(signature.content, &info)
+		if err != nil {
+			return nil, fmt.Errorf("could not parse time stamp token info: %w", err)
+		}
+
+		imprintAlgorithm, err := getDigestAlgorithm(info.MessageImprint.HashAlgorithm.Algorithm)
+		if err != nil {
+			return nil, err
+		}
+
+		result.DigestValid = bytes.Equal(digest(imprintAlgorithm, signedData), info.MessageImprint.HashedMessage)
+		genTime := info.GenTime
+		result.SigningTime = &genTime
+		signedContent = signature.content
+	case signature.content != nil:
+		// The adbe.pkcs7.sha1 sub filter signs the SHA-1 digest of the
+		// signed data.
+		result.DigestValid = bytes.Equal(digest(crypto.SHA1, signedData), signature.content)
+		signedContent = signature.content
+	default:
+		result.DigestValid = true
+	}
+
+	message := signedContent
+	if len(signature.signedAttributes) > 0 {
+		messageDigest := []byte{}
+		messageDigestValue := signature.getAttribute(oidMessageDigest)
+		if messageDigestValue == nil {
+			return nil, errors.New("signed attributes have no message digest")
+		}
+
+		_, err = asn1.Unmarshal(messageDigestValue, &messageDigest)
+		if err != nil {
+			return nil, fmt.Errorf("could not parse message digest: %w", err)
+		}
+
+		if !bytes.Equal(digest(signature.digestAlgorithm, signedContent), messageDigest) {
+			result.DigestValid = false
+		}
+
+		if result.SigningTime == nil {
+			if signingTimeValue := signature.getAttribute(oidSigningTime); signingTimeValue != nil {
+				signingTime := time.Time{}
+				_, err = asn1.Unmarshal(signingTimeValue, &signingTime)
+				if err == nil {
+					result.SigningTime = &signingTime
+				}
+			}
+		}
+
+		message = signedAttributesSet(signature.signer.SignedAttributes)
+	}
+
+	err = verifySignature(signature.signerCertifcate, signature.signer.SignatureAlgorithm.Algorithm, signature.digestAlgorithm, message, signature.signer.Signature)
+	if err != nil {
+		if errors.Is(err, ErrUnsupported) {
+			return nil, err
+		}
+	} else {
+		result.SignatureValid = true
+	}
+
+	if roots == nil {
+		roots = x509.NewCertPool()
+	}
+
+	intermediates := x509.NewCertPool()
+	for _, certificate := range signature.certificates {
+		if certificate != signature.signerCertifcate {
+			intermediates.AddCert(certificate)
+		}
+	}
+
+	currentTime := now
+	if isTimeStampToken && result.SignatureValid && result.DigestValid {
+		currentTime = *result.SigningTime
+	}
+
+	_, err = signature.signerCertifcate.Verify(x509.VerifyOptions{
+		Roots:         roots,
+		Intermediates: intermediates,
+		CurrentTime:   currentTime,
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageAny},
+	})
+	if err != nil {
+		result.TrustError = err
+	} else {
+		result.Trusted = true
+	}
+
+	return result, nil
+}
+
+// verifySignature verifies the signature of the message with the public key
+// of the certificate.
+func verifySignature(certificate *x509.Certificate, signatureAlgorithm asn1.ObjectIdentifier, hash crypto.Hash, message []byte, signature []byte) error {
+	switch publicKey := certificate.PublicKey.(type) {
+	case *rsa.PublicKey:
+		if signatureAlgorithm.Equal(oidRSAPSS) {
+			return rsa.VerifyPSS(publicKey, hash, digest(hash, message), signature, &rsa.PSSOptions{
+				SaltLength: rsa.PSSSaltLengthAuto,
+			})
+		}
+		return rsa.VerifyPKCS1v15(publicKey, hash, digest(hash, message), signature)
+	case *ecdsa.PublicKey:
+		if !ecdsa.VerifyASN1(publicKey, digest(hash, message), signature) {
+			return errors.New("ecdsa: verification error")
+		}
+		return nil
+	case ed25519.PublicKey:
+		if !ed25519.Verify(publicKey, message, signature) {
+			return errors.New("ed25519: verification error")
+		}
+		return nil
+	}
+
+	return fmt.Errorf("public key algorithm %s is %w", certificate.PublicKeyAlgorithm.String(), ErrUnsupported)
+}
+
+// digest returns the digest of the data with the given hash.
+func digest(hash crypto.Hash, data []byte) []byte {
+	hasher := hash.New()
+	hasher.Write(data)
+	return hasher.Sum(nil)
+}
+
+// SignedData returns the data of the file that is covered by the byte range
+// of a signature. The byte range must start at the beginning of the file,
+// and the gap between the two ranges must be exactly the hex string of the
+// signature contents.
+func SignedData(file []byte, byteRange []int, contents []byte) ([]byte, error) {
+	if len(byteRange) != 4 {
+		return nil, fmt.Errorf("byte range has %d values, expected 4", len(byteRange))
+	}
+
+	for _, value := range byteRange {
+		if value < 0 {
+			return nil, errors.New("byte range has negative values")
+		}
+	}
+
+	if byteRange[0] != 0 {
+		return nil, errors.New("byte range doesn't start at the beginning of the file")
+	}
+
+	gapStart := byteRange[0] + byteRange[1]
+	gapEnd := byteRange[2]
+	end := byteRange[2] + byteRange[3]
+	if gapEnd <= gapStart || end > len(file) {
+		return nil, errors.New("byte range is outside of the file")
+	}
+
+	gap := file[gapStart:gapEnd]
+	if len(gap) < 2 || gap[0] != '<' || gap[len(gap)-1] != '>' {
+		return nil, errors.New("byte range gap is not the signature contents")
+	}
+
+	// The contents may be padded with zeros, PDFium may strip these.
+	gapContents, err := hex.DecodeString(string(gap[1 : len(gap)-1]))
+	if err != nil || len(gapContents) < len(contents) || !bytes.Equal(gapContents[:len(contents)], contents) {
+		return nil, errors.New("byte range gap is not the signature contents")
+	}
+
+	signedData := make([]byte, 0, byteRange[1]+byteRange[3])
+	signedData = append(signedData, file[byteRange[0]:gapStart]...)
+	signedData = append(signedData, file[gapEnd:end]...)
+	return signedData, nil
+}
+
+// ModifiedAfterSigning returns whether the file has data after the byte
+// range of a signature, like incremental updates that were made after
+// signing.
+func ModifiedAfterSigning(file []byte, byteRange []int) bool {
+	if len(byteRange) < 2 {
+		return true
+	}
+
+	end := byteRange[len(byteRange)-2] + byteRange[len(byteRange)-1]
+	if end >= len(file) {
+		return false
+	}
+
+	// Only trailing whitespace is allowed after the signed data.
+	return len(bytes.TrimRight(file[end:], "\r\n\t \x00")) > 0
+}
+
+// CertificatePool parses the given certificates into a pool. Every
+// certificate may be DER, or PEM with one or more certificates.
+func CertificatePool(certificates [][]byte) (*x509.CertPool, error) {
+	pool := x509.NewCertPool()
+	for i := range certificates {
+		parsedCertificates, err := ParseCertificates(certificates[i])
+		if err != nil {
+			return nil, fmt.Errorf("could not parse certificate %d: %w", i, err)
+		}
+
+		for _, certificate := range parsedCertificates {
+			pool.AddCert(certificate)
+		}
+	}
+
+	return pool, nil
+}
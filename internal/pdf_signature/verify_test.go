@@ -0,0 +1,295 @@
+package pdf_signature
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/hex"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+)
+
+var testSigningTime = time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+
+type testSigner struct {
+	root        *x509.Certificate
+	certificate *x509.Certificate
+	key         crypto.Signer
+}
+
+func createTestSigner(t *testing.T, key crypto.Signer) *testSigner {
+	rootKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("could not generate root key: %s", err.Error())
+	}
+
+	rootTemplate := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "Test Root"},
+		NotBefore:             testSigningTime.Add(-time.Hour),
+		NotAfter:              testSigningTime.Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+
+	rootDER, err := x509.CreateCertificate(rand.Reader, rootTemplate, rootTemplate, rootKey.Public(), rootKey)
+	if err != nil {
+		t.Fatalf("could not create root certificate: %s", err.Error())
+	}
+
+	root, err := x509.ParseCertificate(rootDER)
+	if err != nil {
+		t.Fatalf("could not parse root certificate: %s", err.Error())
+	}
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		Subject:      pkix.Name{CommonName: "Test Signer"},
+		NotBefore:    testSigningTime.Add(-time.Hour),
+		NotAfter:     testSigningTime.Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+	}
+
+	certificateDER, err := x509.CreateCertificate(rand.Reader, template, root, key.Public(), rootKey)
+	if err != nil {
+		t.Fatalf("could not create certificate: %s", err.Error())
+	}
+
+	certificate, err := x509.ParseCertificate(certificateDER)
+	if err != nil {
+		t.Fatalf("could not parse certificate: %s", err.Error())
+	}
+
+	return &testSigner{
+		root:        root,
+		certificate: certificate,
+		key:         key,
+	}
+}
+
+// sign creates a detached CMS signature with signed attributes.
+func (s *testSigner) sign(t *testing.T, data []byte) []byte {
+	messageDigest, _ := asn1.Marshal(digest(crypto.SHA256, data))
+	signingTime, _ := asn1.Marshal(testSigningTime)
+	contentType, _ := asn1.Marshal(asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 7, 1})
+
+	attributes := []byte{}
+	for _, attr := range []struct {
+		oid   asn1.ObjectIdentifier
+		value []byte
+	}{{oidContentType, contentType}, {oidSigningTime, signingTime}, {oidMessageDigest, messageDigest}} {
+		encoded, err := asn1.Marshal(attribute{
+			Type:   attr.oid,
+			Values: asn1.RawValue{Class: asn1.ClassUniversal, Tag: asn1.TagSet, IsCompound: true, Bytes: attr.value},
+		})
+		if err != nil {
+			t.Fatalf("could not marshal attribute: %s", err.Error())
+		}
+		attributes = append(attributes, encoded...)
+	}
+
+	signedAttributes := asn1.RawValue{Class: asn1.ClassContextSpecific, Tag: 0, IsCompound: true, Bytes: attributes}
+	signedAttributesDER, _ := asn1.Marshal(signedAttributes)
+	signedAttributes.FullBytes = signedAttributesDER
+
+	signatureAlgorithm := oidSHA256WithRSA
+	if _, ok := s.key.(*ecdsa.PrivateKey); ok {
+		signatureAlgorithm = oidECDSAWithSHA256
+	}
+
+	signature, err := s.key.Sign(rand.Reader, digest(crypto.SHA256, signedAttributesSet(signedAttributes)), crypto.SHA256)
+	if err != nil {
+		t.Fatalf("could not sign: %s", err.Error())
+	}
+
+	signerIdentifier, _ := asn1.Marshal(issuerAndSerialNumber{
+		Issuer:       asn1.RawValue{FullBytes: s.certificate.RawIssuer},
+		SerialNumber: s.certificate.SerialNumber,
+	})
+
+	signed, err := asn1.Marshal(signedData{
+		Version:          1,
+		DigestAlgorithms: []pkix.AlgorithmIdentifier{{Algorithm: oidSHA256}},
+		EncapContentInfo: encapsulatedContentInfo{ContentType: asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 7, 1}},
+		Certificates:     asn1.RawValue{Class: asn1.ClassContextSpecific, Tag: 0, IsCompound: true, Bytes: append(append([]byte{}, s.certificate.Raw...), s.root.Raw...)},
+		SignerInfos: []signerInfo{{
+			Version:            1,
+			SignerIdentifier:   asn1.RawValue{FullBytes: signerIdentifier},
+			DigestAlgorithm:    pkix.AlgorithmIdentifier{Algorithm: oidSHA256},
+			SignedAttributes:   signedAttributes,
+			SignatureAlgorithm: pkix.AlgorithmIdentifier{Algorithm: signatureAlgorithm},
+			Signature:          signature,
+		}},
+	})
+	if err != nil {
+		t.Fatalf("could not marshal signed data: %s", err.Error())
+	}
+
+	info, err := asn1.Marshal(contentInfo{
+		ContentType: oidSignedData,
+		Content:     asn1.RawValue{Class: asn1.ClassContextSpecific, Tag: 0, IsCompound: true, Bytes: signed},
+	})
+	if err != nil {
+		t.Fatalf("could not marshal content info: %s", err.Error())
+	}
+
+	return info
+}
+
+func TestVerify(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("could not generate key: %s", err.Error())
+	}
+
+	ecdsaKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("could not generate key: %s", err.Error())
+	}
+
+	data := []byte("the signed data of the document")
+	for _, key := range []crypto.Signer{rsaKey, ecdsaKey} {
+		signer := createTestSigner(t, key)
+		contents := signer.sign(t, data)
+
+		roots := x509.NewCertPool()
+		roots.AddCert(signer.root)
+
+		// The contents of a signature are padded with zeros.
+		result, err := Verify(append(contents, make([]byte, 32)...), data, SubFilterPKCS7Detached, roots, testSigningTime)
+		if err != nil {
+			t.Fatalf("Verify resulted in error: %s", err.Error())
+		}
+
+		if !result.DigestValid || !result.SignatureValid || !result.Trusted || result.TrustError != nil {
+			t.Fatalf("Verify resulted in wrong result, got %+v", result)
+		}
+
+		if result.DigestAlgorithm != crypto.SHA256 || result.Signer.Subject.CommonName != "Test Signer" || len(result.Certificates) != 2 {
+			t.Fatalf("Verify resulted in wrong signer, got %+v", result)
+		}
+
+		if result.SigningTime == nil || !result.SigningTime.Equal(testSigningTime) {
+			t.Fatalf("Verify resulted in wrong signing time, got %v", result.SigningTime)
+		}
+
+		// The signing time of the signer is not trusted, so the certificate
+		// is validated at the given time, after it has expired.
+		result, err = Verify(contents, data, SubFilterPKCS7Detached, roots, testSigningTime.Add(24*time.Hour))
+		if err != nil {
+			t.Fatalf("Verify resulted in error: %s", err.Error())
+		}
+
+		if !result.DigestValid || !result.SignatureValid || result.Trusted || result.TrustError == nil {
+			t.Fatalf("Verify resulted in wrong result after the certificate expired, got %+v", result)
+		}
+
+		// Without roots the signer is never trusted.
+		result, err = Verify(contents, data, SubFilterPKCS7Detached, nil, testSigningTime)
+		if err != nil {
+			t.Fatalf("Verify resulted in error: %s", err.Error())
+		}
+
+		if !result.DigestValid || !result.SignatureValid || result.Trusted || result.TrustError == nil {
+			t.Fatalf("Verify resulted in wrong result without roots, got %+v", result)
+		}
+
+		result, err = Verify(contents, []byte("the modified data of the document"), SubFilterPKCS7Detached, roots, testSigningTime)
+		if err != nil {
+			t.Fatalf("Verify resulted in error: %s", err.Error())
+		}
+
+		if result.DigestValid || !result.SignatureValid {
+			t.Fatalf("Verify resulted in wrong result for modified data, got %+v", result)
+		}
+
+		// Change the last byte of the signature value.
+		tampered := append([]byte{}, contents...)
+		tampered[len(tampered)-1] ^= 0xff
+		result, err = Verify(tampered, data, SubFilterPKCS7Detached, roots, testSigningTime)
+		if err != nil {
+			t.Fatalf("Verify resulted in error: %s", err.Error())
+		}
+
+		if !result.DigestValid || result.SignatureValid {
+			t.Fatalf("Verify resulted in wrong result for tampered signature, got %+v", result)
+		}
+	}
+}
+
+func TestVerifyErrors(t *testing.T) {
+	_, err := Verify([]byte{0x30, 0x00}, nil, SubFilterX509RSASHA1, nil, time.Now())
+	if !errors.Is(err, ErrUnsupported) {
+		t.Fatalf("Verify resulted in wrong error, got %v", err)
+	}
+
+	// The contents of the signature test files of PDFium.
+	contents, _ := hex.DecodeString("308006092A864886F70D010702A0803080020101")
+	_, err = Verify(contents, nil, SubFilterPKCS7Detached, nil, time.Now())
+	if err == nil || err.Error() != "ber: missing end of contents" {
+		t.Fatalf("Verify resulted in wrong error, got %v", err)
+	}
+}
+
+func TestBERToDER(t *testing.T) {
+	// A sequence with an indefinite length that contains a constructed octet
+	// string with an indefinite length, followed by padding.
+	ber := []byte{0x30, 0x80, 0x24, 0x80, 0x04, 0x02, 0x01, 0x02, 0x04, 0x01, 0x03, 0x00, 0x00, 0x02, 0x01, 0x05, 0x00, 0x00, 0x00, 0x00}
+	der, err := berToDER(ber)
+	if err != nil {
+		t.Fatalf("berToDER resulted in error: %s", err.Error())
+	}
+
+	want := []byte{0x30, 0x08, 0x04, 0x03, 0x01, 0x02, 0x03, 0x02, 0x01, 0x05}
+	if !bytes.Equal(der, want) {
+		t.Fatalf("berToDER resulted in wrong DER, got %x, want %x", der, want)
+	}
+
+	_, err = berToDER([]byte{0x30, 0x80, 0x02, 0x01, 0x05})
+	if err == nil || err.Error() != "ber: missing end of contents" {
+		t.Fatalf("berToDER resulted in wrong error, got %v", err)
+	}
+}
+
+func TestSignedData(t *testing.T) {
+	contents := []byte{0xab, 0xcd}
+	file := []byte("%PDF-1.7 /Contents <abcd0000> endobj %%EOF\n")
+
+	signedData, err := SignedData(file, []int{0, 19, 29, 14}, contents)
+	if err != nil {
+		t.Fatalf("SignedData resulted in error: %s", err.Error())
+	}
+
+	if string(signedData) != "%PDF-1.7 /Contents  endobj %%EOF\n" {
+		t.Fatalf("SignedData resulted in wrong data, got %q", signedData)
+	}
+
+	if ModifiedAfterSigning(file, []int{0, 19, 29, 14}) {
+		t.Fatalf("ModifiedAfterSigning resulted in modified for unmodified file")
+	}
+
+	if !ModifiedAfterSigning(append(file, []byte("1 0 obj\n")...), []int{0, 19, 29, 14}) {
+		t.Fatalf("ModifiedAfterSigning resulted in unmodified for modified file")
+	}
+
+	for _, byteRange := range [][]int{{0, 10, 30, 10}, {0, 19, 29, 15}, {1, 18, 29, 14}, {0, 19, 29}, {0, 18, 29, 14}} {
+		_, err = SignedData(file, byteRange, contents)
+		if err == nil {
+			t.Fatalf("SignedData resulted in no error for byte range %v", byteRange)
+		}
+	}
+
+	_, err = SignedData(file, []int{0, 19, 29, 14}, []byte{0xab, 0xce})
+	if err == nil || err.Error() != "byte range gap is not the signature contents" {
+		t.Fatalf("SignedData resulted in wrong error, got %v", err)
+	}
+}
@@ -0,0 +1,182 @@
+// Package pdf_update applies changes that PDFium has no API for to the file
+// that PDFium writes when saving a document, by appending an incremental
+// update to it.
+package pdf_update
+
+import (
+	"bytes"
+	"errors"
+	"io"
+
+	"github.com/klippa-app/go-pdfium/requests"
+)
+
+// Changes are the pending changes of a document, they are applied every
+// time the document is saved.
+type Changes struct {
+	info       map[string]string
+	outline    *[]requests.SetBookmarksBookmark
+	pageLabels *[]requests.SetPageLabelsRange
+
+	removeThumbnails    bool
+	removeUnusedObjects bool
+}
+
+// SetInfo sets a key of the document information dictionary, an empty
+// value removes the key.
+func (c *Changes) SetInfo(key, value string) {
+	if c.info == nil {
+		c.info = map[string]string{}
+	}
+
+	c.info[key] = value
+}
+
+// GetInfo returns the pending value of a key of the document information
+// dictionary, and whether the key has a pending value.
+func (c *Changes) GetInfo(key string) (string, bool) {
+	if c == nil {
+		return "", false
+	}
+
+	value, ok := c.info[key]
+	return value, ok
+}
+
+// SetOutline replaces the outline of the document, an empty outline removes
+// the outline.
+func (c *Changes) SetOutline(bookmarks []requests.SetBookmarksBookmark) {
+	c.outline = &bookmarks
+}
+
+// SetPageLabels replaces the page labels of the document, no ranges removes
+// the page labels.
+func (c *Changes) SetPageLabels(ranges []requests.SetPageLabelsRange) {
+	c.pageLabels = &ranges
+}
+
+// RemoveThumbnails removes the thumbnails of all pages.
+func (c *Changes) RemoveThumbnails() {
+	c.removeThumbnails = true
+}
+
+// RemoveUnusedObjects makes Apply write a complete new file that only
+// contains the objects that are still used, instead of an incremental
+// update. This is not done for incremental saves, see Incremental.
+func (c *Changes) RemoveUnusedObjects() {
+	c.removeUnusedObjects = true
+}
+
+// Incremental returns the changes to apply to an incremental save, unused
+// objects are kept then, because removing them rewrites the original file
+// that the incremental save has to keep, and with it any signatures.
+func (c *Changes) Incremental() *Changes {
+	if c == nil || !c.removeUnusedObjects {
+		return c
+	}
+
+	incremental := *c
+	incremental.removeUnusedObjects = false
+	return &incremental
+}
+
+// WithoutUnusedObjects returns a copy of the changes that also removes the
+// unused objects, for a single save.
+func (c *Changes) WithoutUnusedObjects() *Changes {
+	withoutUnusedObjects := &Changes{}
+	if c != nil {
+		*withoutUnusedObjects = *c
+	}
+
+	withoutUnusedObjects.removeUnusedObjects = true
+	return withoutUnusedObjects
+}
+
+// IsEmpty returns whether there are no pending changes.
+func (c *Changes) IsEmpty() bool {
+	return c == nil || (len(c.info) == 0 && c.outline == nil && c.pageLabels == nil && !c.removeThumbnails && !c.removeUnusedObjects)
+}
+
+// Apply writes the given PDF file with the pending changes to the writer.
+func (c *Changes) Apply(data []byte, w io.Writer) error {
+	file, err := Parse(data)
+	if err != nil {
+		return err
+	}
+
+	if _, ok := file.Trailer()["Encrypt"]; ok {
+		return errors.New("can not apply changes to an encrypted document, save the document without security")
+	}
+
+	update := file.NewUpdate()
+
+	if len(c.info) > 0 {
+		if err := c.applyInfo(file, update); err != nil {
+			return err
+		}
+	}
+
+	if c.outline != nil {
+		if err := applyOutline(update, *c.outline); err != nil {
+			return err
+		}
+	}
+
+	if c.pageLabels != nil {
+		if err := applyPageLabels(update, *c.pageLabels); err != nil {
+			return err
+		}
+	}
+
+	if c.removeThumbnails {
+		if err := removeThumbnails(update); err != nil {
+			return err
+		}
+	}
+
+	if !c.removeUnusedObjects {
+		_, err = update.WriteTo(w)
+		return err
+	}
+
+	updatedData := &bytes.Buffer{}
+	if _, err := update.WriteTo(updatedData); err != nil {
+		return err
+	}
+
+	updatedFile, err := Parse(updatedData.Bytes())
+	if err != nil {
+		return err
+	}
+
+	id, _ := updatedFile.Trailer()["ID"].(Array)
+	return rewriteFile(updatedFile, w, headerVersion(data), id, nil, nil)
+}
+
+func (c *Changes) applyInfo(file *File, update *Update) error {
+	info := Dictionary{}
+	infoObject, err := file.Resolve(file.Trailer()["Info"])
+	if err != nil {
+		return err
+	}
+
+	if existingInfo, ok := infoObject.(Dictionary); ok {
+		info = existingInfo.Copy()
+	}
+
+	for key, value := range c.info {
+		if value == "" {
+			delete(info, Name(key))
+			continue
+		}
+		info[Name(key)] = TextString(value)
+	}
+
+	if reference, ok := file.Trailer()["Info"].(Reference); ok {
+		update.Set(reference.Number, info)
+	} else {
+		update.Trailer["Info"] = update.Add(info)
+	}
+
+	return nil
+}
@@ -0,0 +1,386 @@
+package pdf_update
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/md5"
+	"crypto/rand"
+	"crypto/rc4"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/klippa-app/go-pdfium/requests"
+)
+
+// passwordPadding is used to pad passwords to 32 bytes for revision 4 of
+// the standard security handler.
+var passwordPadding = []byte{
+	0x28, 0xBF, 0x4E, 0x5E, 0x4E, 0x75, 0x8A, 0x41, 0x64, 0x00, 0x4E, 0x56, 0xFF, 0xFA, 0x01, 0x08,
+	0x2E, 0x2E, 0x00, 0xB6, 0xD0, 0x68, 0x3E, 0x80, 0x2F, 0x0C, 0xA9, 0xFE, 0x64, 0x53, 0x69, 0x7A,
+}
+
+// securityHandler encrypts the strings and streams of a file.
+type securityHandler struct {
+	key    []byte
+	aes256 bool
+}
+
+// ValidateEncryption returns an error when the encryption options can't be
+// used.
+func ValidateEncryption(options requests.SaveEncryption) error {
+	switch options.Method {
+	case requests.SaveEncryptionMethodAES128, requests.SaveEncryptionMethodAES256:
+	default:
+		return fmt.Errorf("invalid encryption method %s given", options.Method)
+	}
+
+	return nil
+}
+
+// Encrypt writes the given unencrypted PDF file as a new file that is
+// encrypted with the standard security handler. Encryption can't be added
+// with an incremental update, so all the used objects are written again.
+func Encrypt(data []byte, w io.Writer, options requests.SaveEncryption) error {
+	if err := ValidateEncryption(options); err != nil {
+		return err
+	}
+
+	file, err := Parse(data)
+	if err != nil {
+		return err
+	}
+
+	if _, ok := file.Trailer()["Encrypt"]; ok {
+		return errors.New("can not encrypt an encrypted document, save the document without security")
+	}
+
+	id, ok := file.Trailer()["ID"].(Array)
+	if !ok || len(id) != 2 {
+		newID := make([]byte, 16)
+		if _, err := rand.Read(newID); err != nil {
+			return err
+		}
+		id = Array{HexString(newID), HexString(newID)}
+	}
+
+	firstID := []byte{}
+	switch value := id[0].(type) {
+	case String:
+		firstID = value
+	case HexString:
+		firstID = value
+	}
+
+	ownerPassword := options.OwnerPassword
+	if ownerPassword == "" {
+		randomPassword := make([]byte, 16)
+		if _, err := rand.Read(randomPassword); err != nil {
+			return err
+		}
+		ownerPassword = hex.EncodeToString(randomPassword)
+	}
+
+	// Bits 7, 8 and 13 to 32 are reserved and must be set, bits 1 and 2 must
+	// be unset.
+	permissions := int32(uint32(options.Permissions&requests.SavePermissionAll) | 0xFFFFF0C0)
+
+	var handler *securityHandler
+	var encrypt Dictionary
+	var minimumVersion string
+	switch options.Method {
+	case requests.SaveEncryptionMethodAES128:
+		handler, encrypt = newAES128Handler(options.UserPassword, ownerPassword, permissions, firstID)
+		minimumVersion = "1.6"
+	case requests.SaveEncryptionMethodAES256:
+		handler, encrypt, err = newAES256Handler(options.UserPassword, ownerPassword, permissions)
+		if err != nil {
+			return err
+		}
+		minimumVersion = "2.0"
+	default:
+		return fmt.Errorf("invalid encryption method %s given", options.Method)
+	}
+
+	version := minimumVersion
+	if fileVersion := headerVersion(data); fileVersion > version {
+		version = fileVersion
+	}
+
+	return rewriteFile(file, w, version, id, handler, encrypt)
+}
+
+// newAES128Handler creates a revision 4 security handler with AESV2 crypt
+// filters.
+func newAES128Handler(userPassword, ownerPassword string, permissions int32, id []byte) (*securityHandler, Dictionary) {
+	paddedUserPassword := padPassword(userPassword)
+	paddedOwnerPassword := padPassword(ownerPassword)
+
+	// The O value is the padded user password, encrypted with a key that
+	// is derived from the owner password.
+	ownerHash := md5.Sum(paddedOwnerPassword)
+	for i := 0; i < 50; i++ {
+		ownerHash = md5.Sum(ownerHash[:])
+	}
+	o := rc4Rounds(ownerHash[:], paddedUserPassword)
+
+	hash := md5.New()
+	hash.Write(paddedUserPassword)
+	hash.Write(o)
+	binary.Write(hash, binary.LittleEndian, permissions)
+	hash.Write(id)
+	key := hash.Sum(nil)
+	for i := 0; i < 50; i++ {
+		keyHash := md5.Sum(key)
+		key = keyHash[:]
+	}
+
+	// The U value is the hash of the padding and the ID, encrypted with the
+	// file key, followed by 16 arbitrary bytes.
+	hash = md5.New()
+	hash.Write(passwordPadding)
+	hash.Write(id)
+	u := append(rc4Rounds(key, hash.Sum(nil)), make([]byte, 16)...)
+
+	return &securityHandler{key: key}, Dictionary{
+		"Filter": Name("Standard"),
+		"V":      Integer(4),
+		"R":      Integer(4),
+		"Length": Integer(128),
+		"CF": Dictionary{
+			"StdCF": Dictionary{
+				"CFM":       Name("AESV2"),
+				"AuthEvent": Name("DocOpen"),
+				"Length":    Integer(16),
+			},
+		},
+		"StmF": Name("StdCF"),
+		"StrF": Name("StdCF"),
+		"O":    HexString(o),
+		"U":    HexString(u),
+		"P":    Integer(permissions),
+	}
+}
+
+// newAES256Handler creates a revision 6 security handler with AESV3 crypt
+// filters.
+func newAES256Handler(userPassword, ownerPassword string, permissions int32) (*securityHandler, Dictionary, error) {
+	// Revision 6 uses UTF-8 passwords of at most 127 bytes.
+	user := []byte(userPassword)
+	if len(user) > 127 {
+		user = user[:127]
+	}
+
+	owner := []byte(ownerPassword)
+	if len(owner) > 127 {
+		owner = owner[:127]
+	}
+
+	random := make([]byte, 32+32+4)
+	if _, err := rand.Read(random); err != nil {
+		return nil, nil, err
+	}
+
+	key := random[0:32]
+	userValidationSalt := random[32:40]
+	userKeySalt := random[40:48]
+	ownerValidationSalt := random[48:56]
+	ownerKeySalt := random[56:64]
+
+	u := append(append(hashR6(user, userValidationSalt, nil), userValidationSalt...), userKeySalt...)
+	ue := encryptAESNoIV(hashR6(user, userKeySalt, nil), key)
+
+	o := append(append(hashR6(owner, ownerValidationSalt, u), ownerValidationSalt...), ownerKeySalt...)
+	oe := encryptAESNoIV(hashR6(owner, ownerKeySalt, u), key)
+
+	perms := make([]byte, 16)
+	binary.LittleEndian.PutUint32(perms[0:4], uint32(permissions))
+	copy(perms[4:12], []byte{0xFF, 0xFF, 0xFF, 0xFF, 'T', 'a', 'd', 'b'})
+	copy(perms[12:16], random[64:68])
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, nil, err
+	}
+	block.Encrypt(perms, perms)
+
+	return &securityHandler{key: key, aes256: true}, Dictionary{
+		"Filter": Name("Standard"),
+		"V":      Integer(5),
+		"R":      Integer(6),
+		"Length": Integer(256),
+		"CF": Dictionary{
+			"StdCF": Dictionary{
+				"CFM":       Name("AESV3"),
+				"AuthEvent": Name("DocOpen"),
+				"Length":    Integer(32),
+			},
+		},
+		"StmF":  Name("StdCF"),
+		"StrF":  Name("StdCF"),
+		"O":     HexString(o),
+		"U":     HexString(u),
+		"OE":    HexString(oe),
+		"UE":    HexString(ue),
+		"Perms": HexString(perms),
+		"P":     Integer(permissions),
+	}, nil
+}
+
+// padPassword pads or truncates a password to 32 bytes. Like PDFium, the
+// password is encoded as Latin-1 when possible.
+func padPassword(password string) []byte {
+	encoded := []byte{}
+	for _, char := range password {
+		if char > 0xFF {
+			encoded = []byte(password)
+			break
+		}
+		encoded = append(encoded, byte(char))
+	}
+
+	padded := append(encoded, passwordPadding...)
+	return padded[:32]
+}
+
+// rc4Rounds encrypts the data 20 times with RC4, each round with the key
+// XOR-ed with the round number.
+func rc4Rounds(key, data []byte) []byte {
+	result := append([]byte{}, data...)
+	roundKey := make([]byte, len(key))
+	for round := 0; round < 20; round++ {
+		for i := range key {
+			roundKey[i] = key[i] ^ byte(round)
+		}
+		rc4Cipher, _ := rc4.NewCipher(roundKey)
+		rc4Cipher.XORKeyStream(result, result)
+	}
+	return result
+}
+
+// hashR6 is the hash function of revision 6 of the standard security
+// handler.
+func hashR6(password, salt, userKey []byte) []byte {
+	input := append(append(append([]byte{}, password...), salt...), userKey...)
+	firstHash := sha256.Sum256(input)
+	k := firstHash[:]
+
+	var e []byte
+	for round := 0; round < 64 || int(e[len(e)-1]) > round-32; round++ {
+		k1 := bytes.Repeat(append(append(append([]byte{}, password...), k...), userKey...), 64)
+
+		block, _ := aes.NewCipher(k[:16])
+		e = make([]byte, len(k1))
+		cipher.NewCBCEncrypter(block, k[16:32]).CryptBlocks(e, k1)
+
+		sum := 0
+		for _, value := range e[:16] {
+			sum += int(value)
+		}
+
+		switch sum % 3 {
+		case 0:
+			hash := sha256.Sum256(e)
+			k = hash[:]
+		case 1:
+			hash := sha512.Sum384(e)
+			k = hash[:]
+		case 2:
+			hash := sha512.Sum512(e)
+			k = hash[:]
+		}
+	}
+
+	return k[:32]
+}
+
+// encryptAESNoIV encrypts the data with AES-256 in CBC mode with a zero
+// initialization vector and without padding.
+func encryptAESNoIV(key, data []byte) []byte {
+	block, _ := aes.NewCipher(key)
+	result := make([]byte, len(data))
+	cipher.NewCBCEncrypter(block, make([]byte, aes.BlockSize)).CryptBlocks(result, data)
+	return result
+}
+
+// encryptObject returns a copy of the object with the strings and the
+// stream data encrypted.
+func (h *securityHandler) encryptObject(object Object, number, generation int) (Object, error) {
+	key := h.key
+	if !h.aes256 {
+		hash := md5.New()
+		hash.Write(h.key)
+		hash.Write([]byte{byte(number), byte(number >> 8), byte(number >> 16), byte(generation), byte(generation >> 8)})
+		hash.Write([]byte("sAlT"))
+		key = hash.Sum(nil)
+	}
+
+	return encryptObjectWithKey(object, key)
+}
+
+func encryptObjectWithKey(object Object, key []byte) (Object, error) {
+	switch value := object.(type) {
+	case String:
+		encrypted, err := encryptAES(key, value)
+		return String(encrypted), err
+	case HexString:
+		encrypted, err := encryptAES(key, value)
+		return HexString(encrypted), err
+	case Array:
+		encryptedArray := make(Array, len(value))
+		for i := range value {
+			encryptedValue, err := encryptObjectWithKey(value[i], key)
+			if err != nil {
+				return nil, err
+			}
+			encryptedArray[i] = encryptedValue
+		}
+		return encryptedArray, nil
+	case Dictionary:
+		encryptedDictionary := Dictionary{}
+		for name := range value {
+			encryptedValue, err := encryptObjectWithKey(value[name], key)
+			if err != nil {
+				return nil, err
+			}
+			encryptedDictionary[name] = encryptedValue
+		}
+		return encryptedDictionary, nil
+	case *Stream:
+		encryptedDictionary, err := encryptObjectWithKey(value.Dictionary, key)
+		if err != nil {
+			return nil, err
+		}
+		encryptedData, err := encryptAES(key, value.Data)
+		if err != nil {
+			return nil, err
+		}
+		return &Stream{Dictionary: encryptedDictionary.(Dictionary), Data: encryptedData}, nil
+	}
+
+	return object, nil
+}
+
+// encryptAES encrypts the data with AES in CBC mode, the random
+// initialization vector is prepended to the result.
+func encryptAES(key, data []byte) ([]byte, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	padding := aes.BlockSize - len(data)%aes.BlockSize
+	padded := append(append([]byte{}, data...), bytes.Repeat([]byte{byte(padding)}, padding)...)
+
+	result := make([]byte, aes.BlockSize+len(padded))
+	if _, err := rand.Read(result[:aes.BlockSize]); err != nil {
+		return nil, err
+	}
+
+	cipher.NewCBCEncrypter(block, result[:aes.BlockSize]).CryptBlocks(result[aes.BlockSize:], padded)
+	return result, nil
+}
@@ -0,0 +1,83 @@
+package pdf_update
+
+import (
+	"bytes"
+	"errors"
+	"regexp"
+	"strconv"
+)
+
+var fdfObjectPattern = regexp.MustCompile(`(?:^|[^0-9])(\d+)\s+(\d+)\s+obj\b`)
+
+// ParseFDF parses an FDF file. FDF files usually don't have a
+// cross-reference table, so the objects are found by scanning the file.
+func ParseFDF(data []byte) (*File, error) {
+	if !bytes.HasPrefix(bytes.TrimLeft(data, "\r\n\t "), []byte("%FDF-")) {
+		return nil, errors.New("not an FDF file")
+	}
+
+	file := &File{
+		data:        data,
+		xref:        map[int]xrefEntry{},
+		objectCache: map[int]Object{},
+		parsing:     map[int]bool{},
+	}
+
+	for _, match := range fdfObjectPattern.FindAllSubmatchIndex(data, -1) {
+		number, err := strconv.Atoi(string(data[match[2]:match[3]]))
+		if err != nil {
+			continue
+		}
+
+		generation, err := strconv.Atoi(string(data[match[4]:match[5]]))
+		if err != nil {
+			continue
+		}
+
+		// Later objects replace earlier objects with the same number.
+		file.xref[number] = xrefEntry{
+			offset:     int64(match[2]),
+			generation: generation,
+		}
+
+		if number >= file.size {
+			file.size = number + 1
+		}
+	}
+
+	trailerPos := bytes.LastIndex(data, []byte("trailer"))
+	if trailerPos == -1 {
+		return nil, errors.New("could not find trailer")
+	}
+
+	p := &parser{data: data, pos: trailerPos + len("trailer")}
+	trailer, err := p.object()
+	if err != nil {
+		return nil, err
+	}
+
+	trailerDictionary, ok := trailer.(Dictionary)
+	if !ok {
+		return nil, errors.New("trailer is not a dictionary")
+	}
+	file.trailer = trailerDictionary
+
+	return file, nil
+}
+
+// WriteFDF writes an FDF file with the given FDF dictionary, the dictionary
+// is written as the /FDF entry of the catalog.
+func WriteFDF(fdf Dictionary) ([]byte, error) {
+	buf := &bytes.Buffer{}
+	buf.WriteString("%FDF-1.2\n%\xE2\xE3\xCF\xD3\n1 0 obj\n")
+	if err := writeObject(buf, Dictionary{"FDF": fdf}); err != nil {
+		return nil, err
+	}
+	buf.WriteString("\nendobj\ntrailer\n")
+	if err := writeObject(buf, Dictionary{"Root": Reference{Number: 1}}); err != nil {
+		return nil, err
+	}
+	buf.WriteString("\n%%EOF\n")
+
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,606 @@
+package pdf_update
+
+import (
+	"bytes"
+	"compress/zlib"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+)
+
+type xrefEntry struct {
+	compressed bool
+	offset     int64 // The offset of the object in the file, when not compressed.
+	generation int
+	stream     int // The object number of the object stream, when compressed.
+	index      int // The index in the object stream, when compressed.
+}
+
+// File is a parsed PDF file.
+type File struct {
+	data        []byte
+	xref        map[int]xrefEntry
+	trailer     Dictionary
+	startXref   int64
+	xrefStream  bool // Whether the last cross-reference section is a stream.
+	size        int
+	objectCache map[int]Object
+	parsing     map[int]bool // The objects that are being parsed, to detect objects that refer to themselves.
+	sectionFree map[int]bool // The free entries of the last table, a hybrid file's stream can override them.
+}
+
+// Parse parses the cross-reference sections of a PDF file, objects are
+// parsed when they are requested.
+func Parse(data []byte) (*File, error) {
+	file := &File{
+		data:        data,
+		xref:        map[int]xrefEntry{},
+		objectCache: map[int]Object{},
+		parsing:     map[int]bool{},
+	}
+
+	startXrefPos := bytes.LastIndex(data, []byte("startxref"))
+	if startXrefPos == -1 {
+		return nil, errors.New("could not find startxref")
+	}
+
+	p := &parser{data: data, pos: startXrefPos + len("startxref")}
+	startXref, err := p.integer()
+	if err != nil {
+		return nil, err
+	}
+	file.startXref = startXref
+
+	visited := map[int64]bool{}
+	offset := startXref
+	first := true
+	for {
+		if visited[offset] {
+			break
+		}
+		visited[offset] = true
+
+		trailer, isStream, err := file.loadXrefSection(offset)
+		if err != nil {
+			return nil, err
+		}
+
+		if first {
+			file.trailer = trailer
+			file.xrefStream = isStream
+			first = false
+		}
+
+		// Hybrid files have an additional cross-reference stream, its entries
+		// are part of the same section and take the place of the free entries
+		// in the table, which readers without stream support would use.
+		if xrefStm, ok := trailer["XRefStm"].(Integer); ok && !visited[int64(xrefStm)] {
+			visited[int64(xrefStm)] = true
+			if _, _, err := file.loadXrefSection(int64(xrefStm)); err != nil {
+				return nil, err
+			}
+		}
+		file.sectionFree = nil
+
+		prev, ok := trailer["Prev"].(Integer)
+		if !ok {
+			break
+		}
+		offset = int64(prev)
+	}
+
+	if size, ok := file.trailer["Size"].(Integer); ok {
+		file.size = int(size)
+	}
+
+	for number := range file.xref {
+		if number >= file.size {
+			file.size = number + 1
+		}
+	}
+
+	return file, nil
+}
+
+// Trailer returns the trailer dictionary of the last cross-reference section.
+func (f *File) Trailer() Dictionary {
+	return f.trailer
+}
+
+// loadXrefSection loads a cross-reference table or stream, entries that
+// were already loaded from a newer section are kept.
+func (f *File) loadXrefSection(offset int64) (Dictionary, bool, error) {
+	if offset < 0 || offset >= int64(len(f.data)) {
+		return nil, false, fmt.Errorf("invalid cross-reference offset %d", offset)
+	}
+
+	p := &parser{data: f.data, pos: int(offset)}
+	afterKeyword := p.pos
+	if p.keyword() != "xref" {
+		p.pos = afterKeyword
+		return f.loadXrefStream(offset)
+	}
+
+	f.sectionFree = map[int]bool{}
+	for {
+		keyword := p.keyword()
+		if keyword == "trailer" {
+			break
+		}
+
+		start, err := strconv.Atoi(keyword)
+		if err != nil {
+			return nil, false, fmt.Errorf("invalid cross-reference table at offset %d", offset)
+		}
+
+		count, err := p.integer()
+		if err != nil {
+			return nil, false, err
+		}
+
+		for i := 0; i < int(count); i++ {
+			entryOffset, err := p.integer()
+			if err != nil {
+				return nil, false, err
+			}
+
+			generation, err := p.integer()
+			if err != nil {
+				return nil, false, err
+			}
+
+			entryType := p.keyword()
+			number := start + i
+			if _, ok := f.xref[number]; ok {
+				continue
+			}
+
+			if entryType == "n" {
+				f.xref[number] = xrefEntry{offset: entryOffset, generation: int(generation)}
+			} else {
+				// Remember free entries so that older sections don't revive them.
+				f.xref[number] = xrefEntry{offset: -1, generation: int(generation)}
+				f.sectionFree[number] = true
+			}
+		}
+	}
+
+	trailer, err := p.object()
+	if err != nil {
+		return nil, false, err
+	}
+
+	trailerDictionary, ok := trailer.(Dictionary)
+	if !ok {
+		return nil, false, errors.New("trailer is not a dictionary")
+	}
+
+	return trailerDictionary, false, nil
+}
+
+func (f *File) loadXrefStream(offset int64) (Dictionary, bool, error) {
+	_, object, err := f.parseIndirectObject(offset)
+	if err != nil {
+		return nil, false, err
+	}
+
+	// PDFium doesn't write the type of the cross-reference stream in
+	// incremental updates, so check for the widths as well.
+	stream, ok := object.(*Stream)
+	if !ok || (stream.Dictionary["Type"] != Name("XRef") && stream.Dictionary["W"] == nil) {
+		return nil, false, fmt.Errorf("invalid cross-reference stream at offset %d", offset)
+	}
+
+	data, err := f.DecodeStream(stream)
+	if err != nil {
+		return nil, false, err
+	}
+
+	widthsArray, ok := stream.Dictionary["W"].(Array)
+	if !ok || len(widthsArray) != 3 {
+		return nil, false, errors.New("invalid cross-reference stream widths")
+	}
+
+	widths := [3]int{}
+	for i := range widthsArray {
+		width, ok := widthsArray[i].(Integer)
+		if !ok || width < 0 || width > 8 {
+			return nil, false, errors.New("invalid cross-reference stream widths")
+		}
+		widths[i] = int(width)
+	}
+
+	index := Array{Integer(0), stream.Dictionary["Size"]}
+	if indexArray, ok := stream.Dictionary["Index"].(Array); ok {
+		index = indexArray
+	}
+
+	readField := func(field []byte) int64 {
+		value := int64(0)
+		for _, char := range field {
+			value = value<<8 | int64(char)
+		}
+		return value
+	}
+
+	entrySize := widths[0] + widths[1] + widths[2]
+	pos := 0
+	for i := 0; i+1 < len(index); i += 2 {
+		start, startOk := index[i].(Integer)
+		count, countOk := index[i+1].(Integer)
+		if !startOk || !countOk {
+			return nil, false, errors.New("invalid cross-reference stream index")
+		}
+
+		for j := 0; j < int(count); j++ {
+			if pos+entrySize > len(data) {
+				return nil, false, errors.New("cross-reference stream is too short")
+			}
+
+			entryType := int64(1)
+			if widths[0] > 0 {
+				entryType = readField(data[pos : pos+widths[0]])
+			}
+			field2 := readField(data[pos+widths[0] : pos+widths[0]+widths[1]])
+			field3 := readField(data[pos+widths[0]+widths[1] : pos+entrySize])
+			pos += entrySize
+
+			number := int(start) + j
+			if _, ok := f.xref[number]; ok && !f.sectionFree[number] {
+				continue
+			}
+
+			switch entryType {
+			case 0:
+				f.xref[number] = xrefEntry{offset: -1, generation: int(field3)}
+			case 1:
+				f.xref[number] = xrefEntry{offset: field2, generation: int(field3)}
+			case 2:
+				f.xref[number] = xrefEntry{compressed: true, stream: int(field2), index: int(field3)}
+			}
+		}
+	}
+
+	return stream.Dictionary, true, nil
+}
+
+// Object returns the object with the given object number.
+func (f *File) Object(number int) (Object, error) {
+	if object, ok := f.objectCache[number]; ok {
+		return object, nil
+	}
+
+	entry, ok := f.xref[number]
+	if !ok || (!entry.compressed && entry.offset < 0) {
+		return Null{}, nil
+	}
+
+	// An object that is requested while it's being parsed refers to itself,
+	// like a stream with its own object as length, or an object stream that
+	// is stored in itself.
+	if f.parsing[number] {
+		return nil, fmt.Errorf("object %d refers to itself", number)
+	}
+	f.parsing[number] = true
+	defer delete(f.parsing, number)
+
+	var object Object
+	var err error
+	if entry.compressed {
+		object, err = f.compressedObject(entry.stream, entry.index)
+	} else {
+		var parsedNumber int
+		parsedNumber, object, err = f.parseIndirectObject(entry.offset)
+		if err == nil && parsedNumber != number {
+			err = fmt.Errorf("expected object %d at offset %d, got object %d", number, entry.offset, parsedNumber)
+		}
+
+		// Some files have wrong offsets, so try to find the object instead.
+		if err != nil {
+			if offset := f.findObject(number); offset != -1 {
+				_, object, err = f.parseIndirectObject(offset)
+			}
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	f.objectCache[number] = object
+	return object, nil
+}
+
+// Resolve returns the object that the reference points to, other objects
+// are returned as is.
+func (f *File) Resolve(object Object) (Object, error) {
+	reference, ok := object.(Reference)
+	if !ok {
+		return object, nil
+	}
+	return f.Object(reference.Number)
+}
+
+// Generation returns the generation of the given object number.
+func (f *File) Generation(number int) int {
+	if entry, ok := f.xref[number]; ok && !entry.compressed && entry.offset >= 0 {
+		return entry.generation
+	}
+	return 0
+}
+
+// Size returns the amount of objects in the file, including the free
+// object 0.
+func (f *File) Size() int {
+	return f.size
+}
+
+func (f *File) findObject(number int) int64 {
+	pattern := regexp.MustCompile(fmt.Sprintf(`(?:^|[^0-9])%d\s+\d+\s+obj`, number))
+	matches := pattern.FindAllIndex(f.data, -1)
+	if len(matches) == 0 {
+		return -1
+	}
+
+	// Take the last one, this is the newest version of the object.
+	match := matches[len(matches)-1]
+	offset := match[0]
+	for offset < len(f.data) && (f.data[offset] < '0' || f.data[offset] > '9') {
+		offset++
+	}
+	return int64(offset)
+}
+
+// parseIndirectObject parses an "N G obj ... endobj" object at the given
+// offset.
+func (f *File) parseIndirectObject(offset int64) (int, Object, error) {
+	if offset < 0 || offset >= int64(len(f.data)) {
+		return 0, nil, fmt.Errorf("invalid object offset %d", offset)
+	}
+
+	p := &parser{data: f.data, pos: int(offset)}
+	number, err := p.integer()
+	if err != nil {
+		return 0, nil, err
+	}
+
+	if _, err := p.integer(); err != nil {
+		return 0, nil, err
+	}
+
+	if err := p.expectKeyword("obj"); err != nil {
+		return 0, nil, err
+	}
+
+	object, err := p.object()
+	if err != nil {
+		return 0, nil, err
+	}
+
+	dictionary, ok := object.(Dictionary)
+	if !ok {
+		return int(number), object, nil
+	}
+
+	afterObject := p.pos
+	if p.keyword() != "stream" {
+		p.pos = afterObject
+		return int(number), object, nil
+	}
+
+	// The stream keyword is followed by CRLF or LF.
+	if p.pos < len(f.data) && f.data[p.pos] == '\r' {
+		p.pos++
+	}
+	if p.pos < len(f.data) && f.data[p.pos] == '\n' {
+		p.pos++
+	}
+
+	dataStart := p.pos
+	// The length can't be resolved when it refers to an object that is being
+	// parsed, then the end of the stream is searched.
+	length := -1
+	if lengthObject, err := f.Resolve(dictionary["Length"]); err == nil {
+		if lengthValue, ok := lengthObject.(Integer); ok {
+			length = int(lengthValue)
+		}
+	}
+
+	// Verify the length, fall back to searching the end of the stream.
+	if length < 0 || dataStart+length > len(f.data) || !bytes.HasPrefix(bytes.TrimLeft(f.data[dataStart+length:], "\r\n "), []byte("endstream")) {
+		end := bytes.Index(f.data[dataStart:], []byte("endstream"))
+		if end == -1 {
+			return 0, nil, fmt.Errorf("unterminated stream at offset %d", offset)
+		}
+		length = end
+		for length > 0 && (f.data[dataStart+length-1] == '\n' || f.data[dataStart+length-1] == '\r') {
+			length--
+		}
+	}
+
+	return int(number), &Stream{
+		Dictionary: dictionary,
+		Data:       f.data[dataStart : dataStart+length],
+	}, nil
+}
+
+// compressedObject returns an object from an object stream.
+func (f *File) compressedObject(streamNumber, index int) (Object, error) {
+	object, err := f.Object(streamNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	stream, ok := object.(*Stream)
+	if !ok {
+		return nil, fmt.Errorf("object stream %d is not a stream", streamNumber)
+	}
+
+	data, err := f.DecodeStream(stream)
+	if err != nil {
+		return nil, err
+	}
+
+	count, _ := stream.Dictionary["N"].(Integer)
+	first, _ := stream.Dictionary["First"].(Integer)
+	if index >= int(count) {
+		return nil, fmt.Errorf("object stream %d has no object with index %d", streamNumber, index)
+	}
+
+	p := &parser{data: data}
+	var offset int64
+	for i := 0; i <= index; i++ {
+		if _, err := p.integer(); err != nil {
+			return nil, err
+		}
+		offset, err = p.integer()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	p.pos = int(first) + int(offset)
+	return p.object()
+}
+
+// DecodeStream returns the decoded data of a stream, only the filters that
+// are used for cross-reference and object streams are supported.
+func (f *File) DecodeStream(stream *Stream) ([]byte, error) {
+	filters := Array{}
+	switch filter := stream.Dictionary["Filter"].(type) {
+	case Name:
+		filters = Array{filter}
+	case Array:
+		filters = filter
+	}
+
+	parameters := Array{}
+	switch parameter := stream.Dictionary["DecodeParms"].(type) {
+	case Dictionary:
+		parameters = Array{parameter}
+	case Array:
+		parameters = parameter
+	}
+
+	data := stream.Data
+	for i := range filters {
+		filter, ok := filters[i].(Name)
+		if !ok {
+			return nil, errors.New("invalid stream filter")
+		}
+
+		if filter != "FlateDecode" && filter != "Fl" {
+			return nil, fmt.Errorf("unsupported stream filter %s", filter)
+		}
+
+		reader, err := zlib.NewReader(bytes.NewReader(data))
+		if err != nil {
+			return nil, err
+		}
+
+		decoded, err := io.ReadAll(reader)
+		if err != nil && len(decoded) == 0 {
+			return nil, err
+		}
+
+		if i < len(parameters) {
+			if parameter, ok := parameters[i].(Dictionary); ok {
+				decoded, err = applyPredictor(decoded, parameter)
+				if err != nil {
+					return nil, err
+				}
+			}
+		}
+
+		data = decoded
+	}
+
+	return data, nil
+}
+
+// applyPredictor reverses the PNG predictors that are used by cross-reference
+// streams.
+func applyPredictor(data []byte, parameters Dictionary) ([]byte, error) {
+	predictor, _ := parameters["Predictor"].(Integer)
+	if predictor < 10 {
+		if predictor > 1 {
+			return nil, fmt.Errorf("unsupported predictor %d", predictor)
+		}
+		return data, nil
+	}
+
+	columns := 1
+	if value, ok := parameters["Columns"].(Integer); ok {
+		columns = int(value)
+	}
+	colors := 1
+	if value, ok := parameters["Colors"].(Integer); ok {
+		colors = int(value)
+	}
+	bitsPerComponent := 8
+	if value, ok := parameters["BitsPerComponent"].(Integer); ok {
+		bitsPerComponent = int(value)
+	}
+
+	bytesPerPixel := (colors*bitsPerComponent + 7) / 8
+	rowLength := (columns*colors*bitsPerComponent + 7) / 8
+
+	result := []byte{}
+	previous := make([]byte, rowLength)
+	for pos := 0; pos < len(data); pos += rowLength + 1 {
+		filterType := data[pos]
+		end := pos + 1 + rowLength
+		if end > len(data) {
+			end = len(data)
+		}
+		row := make([]byte, rowLength)
+		copy(row, data[pos+1:end])
+
+		for i := 0; i < rowLength; i++ {
+			var left, upperLeft byte
+			if i >= bytesPerPixel {
+				left = row[i-bytesPerPixel]
+				upperLeft = previous[i-bytesPerPixel]
+			}
+			up := previous[i]
+
+			switch filterType {
+			case 1:
+				row[i] += left
+			case 2:
+				row[i] += up
+			case 3:
+				row[i] += byte((int(left) + int(up)) / 2)
+			case 4:
+				row[i] += paeth(left, up, upperLeft)
+			}
+		}
+
+		result = append(result, row...)
+		previous = row
+	}
+
+	return result, nil
+}
+
+func paeth(left, up, upperLeft byte) byte {
+	estimate := int(left) + int(up) - int(upperLeft)
+	distanceLeft := abs(estimate - int(left))
+	distanceUp := abs(estimate - int(up))
+	distanceUpperLeft := abs(estimate - int(upperLeft))
+	if distanceLeft <= distanceUp && distanceLeft <= distanceUpperLeft {
+		return left
+	}
+	if distanceUp <= distanceUpperLeft {
+		return up
+	}
+	return upperLeft
+}
+
+func abs(value int) int {
+	if value < 0 {
+		return -value
+	}
+	return value
+}
@@ -0,0 +1,214 @@
+package pdf_update
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strconv"
+	"unicode/utf16"
+)
+
+// Object is one of the PDF object types below.
+type Object interface{}
+
+type Null struct{}
+
+type Boolean bool
+
+type Integer int64
+
+type Real float64
+
+type Name string
+
+// String is a string that is written as a literal string.
+type String []byte
+
+// HexString is a string that is written as a hexadecimal string.
+type HexString []byte
+
+type Array []Object
+
+type Dictionary map[Name]Object
+
+type Reference struct {
+	Number     int
+	Generation int
+}
+
+type Stream struct {
+	Dictionary Dictionary
+	Data       []byte // The encoded data of the stream.
+}
+
+// TextString encodes a Go string as a PDF text string. Strings that only
+// contain ASCII are written as is, other strings are encoded as UTF-16BE
+// with a byte order mark.
+func TextString(value string) String {
+	isASCII := true
+	for _, r := range value {
+		if r >= 0x80 {
+			isASCII = false
+			break
+		}
+	}
+
+	if isASCII {
+		return String(value)
+	}
+
+	encoded := []byte{0xFE, 0xFF}
+	for _, char := range utf16.Encode([]rune(value)) {
+		encoded = append(encoded, byte(char>>8), byte(char))
+	}
+
+	return String(encoded)
+}
+
+// pdfDocEncoding maps the characters 0x80 to 0x9F of PDFDocEncoding to
+// Unicode, the other characters are the same as in Latin-1.
+var pdfDocEncoding = [32]rune{
+	0x2022, 0x2020, 0x2021, 0x2026, 0x2014, 0x2013, 0x0192, 0x2044,
+	0x2039, 0x203A, 0x2212, 0x2030, 0x201E, 0x201C, 0x201D, 0x2018,
+	0x2019, 0x201A, 0x2122, 0xFB01, 0xFB02, 0x0141, 0x0152, 0x0160,
+	0x0178, 0x017D, 0x0131, 0x0142, 0x0153, 0x0161, 0x017E, 0xFFFD,
+}
+
+// DecodeTextString decodes a PDF text string to a Go string. Text strings
+// are UTF-16BE or UTF-8 with a byte order mark, or PDFDocEncoding.
+func DecodeTextString(value []byte) string {
+	if len(value) >= 2 && value[0] == 0xFE && value[1] == 0xFF {
+		chars := make([]uint16, 0, (len(value)-2)/2)
+		for i := 2; i+1 < len(value); i += 2 {
+			chars = append(chars, uint16(value[i])<<8|uint16(value[i+1]))
+		}
+		return string(utf16.Decode(chars))
+	}
+
+	if len(value) >= 3 && value[0] == 0xEF && value[1] == 0xBB && value[2] == 0xBF {
+		return string(value[3:])
+	}
+
+	decoded := make([]rune, len(value))
+	for i, char := range value {
+		if char >= 0x80 && char <= 0x9F {
+			decoded[i] = pdfDocEncoding[char-0x80]
+			continue
+		}
+		decoded[i] = rune(char)
+	}
+	return string(decoded)
+}
+
+// Copy returns a shallow copy of the dictionary.
+func (d Dictionary) Copy() Dictionary {
+	newDictionary := Dictionary{}
+	for key, value := range d {
+		newDictionary[key] = value
+	}
+	return newDictionary
+}
+
+// writeObject writes the PDF syntax of the given object.
+func writeObject(buf *bytes.Buffer, object Object) error {
+	switch value := object.(type) {
+	case nil, Null:
+		buf.WriteString("null")
+	case Boolean:
+		buf.WriteString(strconv.FormatBool(bool(value)))
+	case Integer:
+		buf.WriteString(strconv.FormatInt(int64(value), 10))
+	case Real:
+		buf.WriteString(strconv.FormatFloat(float64(value), 'f', -1, 64))
+	case Name:
+		writeName(buf, value)
+	case String:
+		writeLiteralString(buf, value)
+	case HexString:
+		buf.WriteString(fmt.Sprintf("<%X>", []byte(value)))
+	case Array:
+		buf.WriteString("[")
+		for i := range value {
+			if i > 0 {
+				buf.WriteString(" ")
+			}
+			if err := writeObject(buf, value[i]); err != nil {
+				return err
+			}
+		}
+		buf.WriteString("]")
+	case Dictionary:
+		return writeDictionary(buf, value)
+	case Reference:
+		buf.WriteString(fmt.Sprintf("%d %d R", value.Number, value.Generation))
+	case *Stream:
+		dictionary := value.Dictionary.Copy()
+		dictionary["Length"] = Integer(len(value.Data))
+		if err := writeDictionary(buf, dictionary); err != nil {
+			return err
+		}
+		buf.WriteString("\nstream\n")
+		buf.Write(value.Data)
+		buf.WriteString("\nendstream")
+	default:
+		return fmt.Errorf("can not write object of type %T", object)
+	}
+
+	return nil
+}
+
+func writeDictionary(buf *bytes.Buffer, dictionary Dictionary) error {
+	// Sort the keys so that the output is stable.
+	keys := make([]string, 0, len(dictionary))
+	for key := range dictionary {
+		keys = append(keys, string(key))
+	}
+	sort.Strings(keys)
+
+	buf.WriteString("<<")
+	for _, key := range keys {
+		buf.WriteString(" ")
+		writeName(buf, Name(key))
+		buf.WriteString(" ")
+		if err := writeObject(buf, dictionary[Name(key)]); err != nil {
+			return err
+		}
+	}
+	buf.WriteString(" >>")
+
+	return nil
+}
+
+func writeName(buf *bytes.Buffer, name Name) {
+	buf.WriteString("/")
+	for i := 0; i < len(name); i++ {
+		char := name[i]
+		if char < 0x21 || char > 0x7E || char == '#' || isDelimiter(char) {
+			buf.WriteString(fmt.Sprintf("#%02X", char))
+			continue
+		}
+		buf.WriteByte(char)
+	}
+}
+
+func writeLiteralString(buf *bytes.Buffer, value []byte) {
+	buf.WriteString("(")
+	for _, char := range value {
+		switch char {
+		case '(', ')', '\\':
+			buf.WriteByte('\\')
+			buf.WriteByte(char)
+		case '\n':
+			buf.WriteString("\\n")
+		case '\r':
+			buf.WriteString("\\r")
+		default:
+			if char < 0x20 || char > 0x7E {
+				buf.WriteString(fmt.Sprintf("\\%03o", char))
+				continue
+			}
+			buf.WriteByte(char)
+		}
+	}
+	buf.WriteString(")")
+}
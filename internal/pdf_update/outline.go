@@ -0,0 +1,127 @@
+package pdf_update
+
+import (
+	"fmt"
+
+	"github.com/klippa-app/go-pdfium/requests"
+)
+
+// applyOutline replaces the outline of the document.
+func applyOutline(update *Update, bookmarks []requests.SetBookmarksBookmark) error {
+	catalogReference, catalog, err := update.Catalog()
+	if err != nil {
+		return err
+	}
+
+	if len(bookmarks) == 0 {
+		delete(catalog, "Outlines")
+		update.Set(catalogReference.Number, catalog)
+		return nil
+	}
+
+	pages, err := update.PageReferences()
+	if err != nil {
+		return err
+	}
+
+	outlinesReference := update.Reserve()
+	first, last, err := addOutlineItems(update, pages, outlinesReference, bookmarks)
+	if err != nil {
+		return err
+	}
+
+	update.Set(outlinesReference.Number, Dictionary{
+		"Type":  Name("Outlines"),
+		"First": first,
+		"Last":  last,
+		"Count": Integer(countOutlineItems(bookmarks)),
+	})
+
+	catalog["Outlines"] = outlinesReference
+	update.Set(catalogReference.Number, catalog)
+
+	return nil
+}
+
+// addOutlineItems adds the outline items of one level and returns the first
+// and the last item.
+func addOutlineItems(update *Update, pages []Reference, parent Reference, bookmarks []requests.SetBookmarksBookmark) (Reference, Reference, error) {
+	references := make([]Reference, len(bookmarks))
+	for i := range bookmarks {
+		references[i] = update.Reserve()
+	}
+
+	for i, bookmark := range bookmarks {
+		item := Dictionary{
+			"Title":  TextString(bookmark.Title),
+			"Parent": parent,
+		}
+
+		if i > 0 {
+			item["Prev"] = references[i-1]
+		}
+
+		if i < len(bookmarks)-1 {
+			item["Next"] = references[i+1]
+		}
+
+		if bookmark.Dest != nil {
+			if bookmark.Dest.PageIndex < 0 || bookmark.Dest.PageIndex >= len(pages) {
+				return Reference{}, Reference{}, fmt.Errorf("page index %d of bookmark %q is out of range, document has %d pages", bookmark.Dest.PageIndex, bookmark.Title, len(pages))
+			}
+
+			optionalNumber := func(value *float32) Object {
+				if value == nil {
+					return Null{}
+				}
+				return Real(*value)
+			}
+
+			item["Dest"] = Array{
+				pages[bookmark.Dest.PageIndex],
+				Name("XYZ"),
+				optionalNumber(bookmark.Dest.X),
+				optionalNumber(bookmark.Dest.Y),
+				optionalNumber(bookmark.Dest.Zoom),
+			}
+		} else if bookmark.URI != nil {
+			item["A"] = Dictionary{
+				"S":   Name("URI"),
+				"URI": String(*bookmark.URI),
+			}
+		}
+
+		if len(bookmark.Children) > 0 {
+			first, last, err := addOutlineItems(update, pages, references[i], bookmark.Children)
+			if err != nil {
+				return Reference{}, Reference{}, err
+			}
+
+			item["First"] = first
+			item["Last"] = last
+
+			// The count is negative when the item is closed.
+			count := countOutlineItems(bookmark.Children)
+			if !bookmark.Open {
+				count = -count
+			}
+			item["Count"] = Integer(count)
+		}
+
+		update.Set(references[i].Number, item)
+	}
+
+	return references[0], references[len(references)-1], nil
+}
+
+// countOutlineItems returns the amount of items that are visible when the
+// parent of the given items is open.
+func countOutlineItems(bookmarks []requests.SetBookmarksBookmark) int {
+	count := len(bookmarks)
+	for _, bookmark := range bookmarks {
+		if bookmark.Open {
+			count += countOutlineItems(bookmark.Children)
+		}
+	}
+	return count
+}
@@ -0,0 +1,59 @@
+package pdf_update
+
+import (
+	"fmt"
+
+	"github.com/klippa-app/go-pdfium/requests"
+)
+
+var pageLabelStyles = map[requests.PageLabelStyle]Name{
+	requests.PageLabelStyleDecimal:      "D",
+	requests.PageLabelStyleUpperRoman:   "R",
+	requests.PageLabelStyleLowerRoman:   "r",
+	requests.PageLabelStyleUpperLetters: "A",
+	requests.PageLabelStyleLowerLetters: "a",
+}
+
+// applyPageLabels replaces the page labels number tree of the document.
+func applyPageLabels(update *Update, ranges []requests.SetPageLabelsRange) error {
+	catalogReference, catalog, err := update.Catalog()
+	if err != nil {
+		return err
+	}
+
+	if len(ranges) == 0 {
+		delete(catalog, "PageLabels")
+		update.Set(catalogReference.Number, catalog)
+		return nil
+	}
+
+	nums := Array{}
+	for _, labelRange := range ranges {
+		label := Dictionary{}
+
+		if labelRange.Style != requests.PageLabelStyleNone {
+			style, ok := pageLabelStyles[labelRange.Style]
+			if !ok {
+				return fmt.Errorf("invalid page label style %s given", labelRange.Style)
+			}
+			label["S"] = style
+		}
+
+		if labelRange.Prefix != "" {
+			label["P"] = TextString(labelRange.Prefix)
+		}
+
+		if labelRange.Start > 1 {
+			label["St"] = Integer(labelRange.Start)
+		}
+
+		nums = append(nums, Integer(labelRange.PageIndex), label)
+	}
+
+	catalog["PageLabels"] = update.Add(Dictionary{
+		"Nums": nums,
+	})
+	update.Set(catalogReference.Number, catalog)
+
+	return nil
+}
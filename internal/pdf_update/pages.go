@@ -0,0 +1,116 @@
+package pdf_update
+
+import (
+	"errors"
+)
+
+// Catalog returns the reference to the document catalog and a copy of it,
+// so that it can be changed with Set.
+func (u *Update) Catalog() (Reference, Dictionary, error) {
+	reference, ok := u.file.trailer["Root"].(Reference)
+	if !ok {
+		return Reference{}, nil, errors.New("document has no catalog")
+	}
+
+	object, err := u.Object(reference.Number)
+	if err != nil {
+		return Reference{}, nil, err
+	}
+
+	catalog, ok := object.(Dictionary)
+	if !ok {
+		return Reference{}, nil, errors.New("document catalog is not a dictionary")
+	}
+
+	return reference, catalog.Copy(), nil
+}
+
+// PageReferences returns the references to the pages of the document, in
+// page order.
+func (u *Update) PageReferences() ([]Reference, error) {
+	_, catalog, err := u.Catalog()
+	if err != nil {
+		return nil, err
+	}
+
+	pagesReference, ok := catalog["Pages"].(Reference)
+	if !ok {
+		return nil, errors.New("document has no page tree")
+	}
+
+	pages := []Reference{}
+	visited := map[int]bool{}
+
+	var walk func(reference Reference) error
+	walk = func(reference Reference) error {
+		if visited[reference.Number] {
+			return errors.New("page tree contains a loop")
+		}
+		visited[reference.Number] = true
+
+		object, err := u.Object(reference.Number)
+		if err != nil {
+			return err
+		}
+
+		node, ok := object.(Dictionary)
+		if !ok {
+			return errors.New("page tree node is not a dictionary")
+		}
+
+		kids, hasKids := node["Kids"].(Array)
+		if node["Type"] == Name("Page") || (!hasKids && node["Type"] != Name("Pages")) {
+			pages = append(pages, reference)
+			return nil
+		}
+
+		for i := range kids {
+			kid, ok := kids[i].(Reference)
+			if !ok {
+				continue
+			}
+
+			if err := walk(kid); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+
+	if err := walk(pagesReference); err != nil {
+		return nil, err
+	}
+
+	return pages, nil
+}
+
+// removeThumbnails removes the thumbnail of every page that has one.
+func removeThumbnails(update *Update) error {
+	pages, err := update.PageReferences()
+	if err != nil {
+		return err
+	}
+
+	for _, pageReference := range pages {
+		page, err := update.Object(pageReference.Number)
+		if err != nil {
+			return err
+		}
+
+		pageDictionary, ok := page.(Dictionary)
+		if !ok {
+			continue
+		}
+
+		if _, ok := pageDictionary["Thumb"]; !ok {
+			continue
+		}
+
+		pageDictionary = pageDictionary.Copy()
+		delete(pageDictionary, "Thumb")
+		update.Set(pageReference.Number, pageDictionary)
+	}
+
+	return nil
+}
@@ -0,0 +1,313 @@
+package pdf_update
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strconv"
+)
+
+// maxNestingDepth is the maximum depth of nested arrays and dictionaries,
+// the same as PDFium uses.
+const maxNestingDepth = 64
+
+// parser reads PDF objects from a byte slice.
+type parser struct {
+	data  []byte
+	pos   int
+	depth int // The depth of the arrays and dictionaries that are being parsed.
+}
+
+func isWhitespace(char byte) bool {
+	return char == 0 || char == '\t' || char == '\n' || char == '\f' || char == '\r' || char == ' '
+}
+
+func isDelimiter(char byte) bool {
+	switch char {
+	case '(', ')', '<', '>', '[', ']', '{', '}', '/', '%':
+		return true
+	}
+	return false
+}
+
+func isRegular(char byte) bool {
+	return !isWhitespace(char) && !isDelimiter(char)
+}
+
+// skipWhitespace skips whitespace and comments.
+func (p *parser) skipWhitespace() {
+	for p.pos < len(p.data) {
+		char := p.data[p.pos]
+		if isWhitespace(char) {
+			p.pos++
+			continue
+		}
+
+		if char == '%' {
+			for p.pos < len(p.data) && p.data[p.pos] != '\n' && p.data[p.pos] != '\r' {
+				p.pos++
+			}
+			continue
+		}
+
+		break
+	}
+}
+
+// keyword reads a token of regular characters, like a number or a keyword.
+func (p *parser) keyword() string {
+	p.skipWhitespace()
+	start := p.pos
+	for p.pos < len(p.data) && isRegular(p.data[p.pos]) {
+		p.pos++
+	}
+	return string(p.data[start:p.pos])
+}
+
+// expectKeyword reads the next token and checks whether it is the given
+// keyword.
+func (p *parser) expectKeyword(expected string) error {
+	start := p.pos
+	if keyword := p.keyword(); keyword != expected {
+		return fmt.Errorf("expected %s at offset %d, got %q", expected, start, keyword)
+	}
+	return nil
+}
+
+// integer reads an integer token.
+func (p *parser) integer() (int64, error) {
+	start := p.pos
+	keyword := p.keyword()
+	value, err := strconv.ParseInt(keyword, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("expected integer at offset %d, got %q", start, keyword)
+	}
+	return value, nil
+}
+
+// object reads the next direct object, references are not resolved.
+func (p *parser) object() (Object, error) {
+	p.skipWhitespace()
+	if p.pos >= len(p.data) {
+		return nil, errors.New("unexpected end of data")
+	}
+
+	switch p.data[p.pos] {
+	case '/':
+		return p.name(), nil
+	case '(':
+		return p.literalString()
+	case '<':
+		if p.pos+1 < len(p.data) && p.data[p.pos+1] == '<' {
+			return p.dictionary()
+		}
+		return p.hexString()
+	case '[':
+		return p.array()
+	}
+
+	start := p.pos
+	keyword := p.keyword()
+	switch keyword {
+	case "":
+		return nil, fmt.Errorf("unexpected character %q at offset %d", p.data[start], start)
+	case "true":
+		return Boolean(true), nil
+	case "false":
+		return Boolean(false), nil
+	case "null":
+		return Null{}, nil
+	}
+
+	if number, err := strconv.ParseInt(keyword, 10, 64); err == nil {
+		// Check whether this is the start of a reference.
+		afterNumber := p.pos
+		if generation, err := strconv.ParseInt(p.keyword(), 10, 64); err == nil && p.keyword() == "R" {
+			return Reference{Number: int(number), Generation: int(generation)}, nil
+		}
+		p.pos = afterNumber
+		return Integer(number), nil
+	}
+
+	if number, err := strconv.ParseFloat(keyword, 64); err == nil {
+		return Real(number), nil
+	}
+
+	return nil, fmt.Errorf("unexpected token %q at offset %d", keyword, start)
+}
+
+func (p *parser) name() Name {
+	p.pos++ // Skip the slash.
+	name := []byte{}
+	for p.pos < len(p.data) && isRegular(p.data[p.pos]) {
+		char := p.data[p.pos]
+		if char == '#' && p.pos+2 < len(p.data) {
+			if value, err := strconv.ParseUint(string(p.data[p.pos+1:p.pos+3]), 16, 8); err == nil {
+				name = append(name, byte(value))
+				p.pos += 3
+				continue
+			}
+		}
+		name = append(name, char)
+		p.pos++
+	}
+	return Name(name)
+}
+
+func (p *parser) literalString() (Object, error) {
+	start := p.pos
+	p.pos++ // Skip the opening parenthesis.
+	value := []byte{}
+	depth := 1
+	for p.pos < len(p.data) {
+		char := p.data[p.pos]
+		p.pos++
+		switch char {
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth == 0 {
+				return String(value), nil
+			}
+		case '\\':
+			if p.pos >= len(p.data) {
+				continue
+			}
+			escaped := p.data[p.pos]
+			p.pos++
+			switch escaped {
+			case 'n':
+				value = append(value, '\n')
+			case 'r':
+				value = append(value, '\r')
+			case 't':
+				value = append(value, '\t')
+			case 'b':
+				value = append(value, '\b')
+			case 'f':
+				value = append(value, '\f')
+			case '\r':
+				// Line continuation.
+				if p.pos < len(p.data) && p.data[p.pos] == '\n' {
+					p.pos++
+				}
+			case '\n':
+				// Line continuation.
+			default:
+				if escaped >= '0' && escaped <= '7' {
+					octal := int(escaped - '0')
+					for i := 0; i < 2 && p.pos < len(p.data) && p.data[p.pos] >= '0' && p.data[p.pos] <= '7'; i++ {
+						octal = octal*8 + int(p.data[p.pos]-'0')
+						p.pos++
+					}
+					value = append(value, byte(octal))
+					continue
+				}
+				value = append(value, escaped)
+			}
+			continue
+		}
+		value = append(value, char)
+	}
+
+	return nil, fmt.Errorf("unterminated string at offset %d", start)
+}
+
+func (p *parser) hexString() (Object, error) {
+	start := p.pos
+	end := bytes.IndexByte(p.data[p.pos:], '>')
+	if end == -1 {
+		return nil, fmt.Errorf("unterminated hex string at offset %d", start)
+	}
+
+	digits := []byte{}
+	for _, char := range p.data[p.pos+1 : p.pos+end] {
+		if !isWhitespace(char) {
+			digits = append(digits, char)
+		}
+	}
+	if len(digits)%2 == 1 {
+		digits = append(digits, '0')
+	}
+
+	value := make([]byte, len(digits)/2)
+	for i := range value {
+		decoded, err := strconv.ParseUint(string(digits[i*2:i*2+2]), 16, 8)
+		if err != nil {
+			return nil, fmt.Errorf("invalid hex string at offset %d", start)
+		}
+		value[i] = byte(decoded)
+	}
+
+	p.pos += end + 1
+	return HexString(value), nil
+}
+
+func (p *parser) array() (Object, error) {
+	if p.depth >= maxNestingDepth {
+		return nil, fmt.Errorf("objects are nested too deep at offset %d", p.pos)
+	}
+	p.depth++
+	defer func() { p.depth-- }()
+
+	p.pos++ // Skip the opening bracket.
+	array := Array{}
+	for {
+		p.skipWhitespace()
+		if p.pos >= len(p.data) {
+			return nil, errors.New("unterminated array")
+		}
+
+		if p.data[p.pos] == ']' {
+			p.pos++
+			return array, nil
+		}
+
+		object, err := p.object()
+		if err != nil {
+			return nil, err
+		}
+		array = append(array, object)
+	}
+}
+
+func (p *parser) dictionary() (Object, error) {
+	if p.depth >= maxNestingDepth {
+		return nil, fmt.Errorf("objects are nested too deep at offset %d", p.pos)
+	}
+	p.depth++
+	defer func() { p.depth-- }()
+
+	p.pos += 2 // Skip the opening brackets.
+	dictionary := Dictionary{}
+	for {
+		p.skipWhitespace()
+		if p.pos >= len(p.data) {
+			return nil, errors.New("unterminated dictionary")
+		}
+
+		if p.data[p.pos] == '>' {
+			if p.pos+1 < len(p.data) && p.data[p.pos+1] == '>' {
+				p.pos += 2
+				return dictionary, nil
+			}
+			return nil, fmt.Errorf("invalid dictionary end at offset %d", p.pos)
+		}
+
+		if p.data[p.pos] != '/' {
+			return nil, fmt.Errorf("expected dictionary key at offset %d", p.pos)
+		}
+
+		key := p.name()
+		value, err := p.object()
+		if err != nil {
+			return nil, err
+		}
+
+		// A null value is the same as a missing key.
+		if _, isNull := value.(Null); !isNull {
+			dictionary[key] = value
+		}
+	}
+}
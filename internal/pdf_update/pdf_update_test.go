@@ -0,0 +1,496 @@
+package pdf_update
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/klippa-app/go-pdfium/requests"
+)
+
+// buildTestFile creates a minimal PDF file with a cross-reference table.
+func buildTestFile() []byte {
+	return buildTestFileWithObjects([]string{
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [3 0 R] /Count 1 >>",
+		"<< /Type /Page /Parent 2 0 R /MediaBox [0 0 612 792] >>",
+		"<< /Title (Old title) /Producer (Test \\(producer\\)) >>",
+	})
+}
+
+// buildTestFileWithObjects creates a PDF file with a cross-reference table
+// from the given objects, the first object is the catalog and the fourth
+// object the information dictionary.
+func buildTestFileWithObjects(objects []string) []byte {
+	buf := &bytes.Buffer{}
+	buf.WriteString("%PDF-1.4\n")
+	offsets := []int{}
+	for i, object := range objects {
+		offsets = append(offsets, buf.Len())
+		buf.WriteString(fmt.Sprintf("%d 0 obj\n%s\nendobj\n", i+1, object))
+	}
+
+	xrefOffset := buf.Len()
+	buf.WriteString(fmt.Sprintf("xref\n0 %d\n0000000000 65535 f\r\n", len(objects)+1))
+	for _, offset := range offsets {
+		buf.WriteString(fmt.Sprintf("%010d 00000 n\r\n", offset))
+	}
+	buf.WriteString(fmt.Sprintf("trailer\n<< /Size %d /Root 1 0 R /Info 4 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(objects)+1, xrefOffset))
+
+	return buf.Bytes()
+}
+
+func getInfo(t *testing.T, data []byte) Dictionary {
+	file, err := Parse(data)
+	if err != nil {
+		t.Fatalf("Parse resulted in error: %s", err.Error())
+	}
+
+	info, err := file.Resolve(file.Trailer()["Info"])
+	if err != nil {
+		t.Fatalf("Resolve resulted in error: %s", err.Error())
+	}
+
+	infoDictionary, ok := info.(Dictionary)
+	if !ok {
+		t.Fatalf("Info is not a dictionary, got %T", info)
+	}
+
+	return infoDictionary
+}
+
+func TestParse(t *testing.T) {
+	file, err := Parse(buildTestFile())
+	if err != nil {
+		t.Fatalf("Parse resulted in error: %s", err.Error())
+	}
+
+	if file.Size() != 5 {
+		t.Fatalf("Parse resulted in wrong size, got %d, want %d", file.Size(), 5)
+	}
+
+	page, err := file.Object(3)
+	if err != nil {
+		t.Fatalf("Object resulted in error: %s", err.Error())
+	}
+
+	mediaBox := page.(Dictionary)["MediaBox"].(Array)
+	if mediaBox[2] != Integer(612) {
+		t.Fatalf("Object resulted in wrong media box, got %v", mediaBox)
+	}
+
+	info := getInfo(t, buildTestFile())
+	if string(info["Producer"].(String)) != "Test (producer)" {
+		t.Fatalf("Object resulted in wrong producer, got %q", info["Producer"])
+	}
+}
+
+func TestParseSelfReferentialLength(t *testing.T) {
+	file, err := Parse(buildTestFileWithObjects([]string{
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [3 0 R] /Count 1 >>",
+		"<< /Type /Page /Parent 2 0 R /MediaBox [0 0 612 792] /Contents [5 0 R 6 0 R] >>",
+		"<< /Title (Title) >>",
+		"<< /Length 5 0 R >>\nstream\nfirst\nendstream",
+		"<< /Length 7 0 R >>\nstream\nsecond\nendstream",
+		"<< /Length 6 0 R >>\nstream\nthird\nendstream",
+	}))
+	if err != nil {
+		t.Fatalf("Parse resulted in error: %s", err.Error())
+	}
+
+	// A length that refers to the stream itself, directly or through another
+	// stream, can't be resolved, the end of the stream is searched instead.
+	for number, want := range map[int]string{5: "first", 6: "second", 7: "third"} {
+		object, err := file.Object(number)
+		if err != nil {
+			t.Fatalf("Object resulted in error: %s", err.Error())
+		}
+
+		stream, ok := object.(*Stream)
+		if !ok || string(stream.Data) != want {
+			t.Fatalf("Object resulted in wrong stream, got %v, want %s", object, want)
+		}
+	}
+}
+
+func TestParseNestingDepth(t *testing.T) {
+	p := &parser{data: bytes.Repeat([]byte("[<< /A "), 1000000)}
+	_, err := p.object()
+	if err == nil || err.Error() != "objects are nested too deep at offset 224" {
+		t.Fatalf("object resulted in wrong error, got %v", err)
+	}
+}
+
+func TestParseHybrid(t *testing.T) {
+	buf := &bytes.Buffer{}
+	buf.WriteString("%PDF-1.5\n")
+	objects := []string{
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [3 0 R] /Count 1 >>",
+		"<< /Type /Page /Parent 2 0 R /MediaBox [0 0 612 792] >>",
+		"",
+		"<< /Type /ObjStm /N 1 /First 4 /Length 25 >>\nstream\n4 0 << /Title (Hybrid) >>\nendstream",
+	}
+	offsets := []int{}
+	for i, object := range objects {
+		offsets = append(offsets, buf.Len())
+		if object != "" {
+			buf.WriteString(fmt.Sprintf("%d 0 obj\n%s\nendobj\n", i+1, object))
+		}
+	}
+
+	// The cross-reference stream only contains object 4, which is stored in
+	// the object stream.
+	xrefStmOffset := buf.Len()
+	buf.WriteString("6 0 obj\n<< /Type /XRef /Size 7 /W [1 1 1] /Index [4 1] /Length 3 >>\nstream\n")
+	buf.Write([]byte{2, 5, 0})
+	buf.WriteString("\nendstream\nendobj\n")
+
+	// Readers that don't support cross-reference streams see object 4 as free.
+	xrefOffset := buf.Len()
+	buf.WriteString("xref\n0 7\n0000000000 65535 f\r\n")
+	for i, offset := range offsets {
+		if i == 3 {
+			buf.WriteString("0000000000 00001 f\r\n")
+			continue
+		}
+		buf.WriteString(fmt.Sprintf("%010d 00000 n\r\n", offset))
+	}
+	buf.WriteString(fmt.Sprintf("%010d 00000 n\r\n", xrefStmOffset))
+	buf.WriteString(fmt.Sprintf("trailer\n<< /Size 7 /Root 1 0 R /Info 4 0 R /XRefStm %d >>\nstartxref\n%d\n%%%%EOF\n", xrefStmOffset, xrefOffset))
+
+	info := getInfo(t, buf.Bytes())
+	if string(info["Title"].(String)) != "Hybrid" {
+		t.Fatalf("Object resulted in wrong title, got %q", info["Title"])
+	}
+}
+
+func TestApplyInfo(t *testing.T) {
+	changes := &Changes{}
+	changes.SetInfo("Title", "New title")
+	changes.SetInfo("Author", "Jérôme")
+	changes.SetInfo("Producer", "")
+
+	output := &bytes.Buffer{}
+	err := changes.Apply(buildTestFile(), output)
+	if err != nil {
+		t.Fatalf("Apply resulted in error: %s", err.Error())
+	}
+
+	if !bytes.HasPrefix(output.Bytes(), buildTestFile()) {
+		t.Fatalf("Apply did not keep the original file")
+	}
+
+	info := getInfo(t, output.Bytes())
+	if string(info["Title"].(String)) != "New title" {
+		t.Fatalf("Apply resulted in wrong title, got %q", info["Title"])
+	}
+
+	if !bytes.Equal(info["Author"].(String), []byte{0xFE, 0xFF, 0x00, 'J', 0x00, 0xE9, 0x00, 'r', 0x00, 0xF4, 0x00, 'm', 0x00, 'e'}) {
+		t.Fatalf("Apply resulted in wrong author, got %q", info["Author"])
+	}
+
+	if _, ok := info["Producer"]; ok {
+		t.Fatalf("Apply did not remove the producer")
+	}
+}
+
+func TestApplyInfoXrefStream(t *testing.T) {
+	data, err := os.ReadFile("../../shared_tests/testdata/page_labels.pdf")
+	if err != nil {
+		t.Fatalf("ReadFile resulted in error: %s", err.Error())
+	}
+
+	changes := &Changes{}
+	changes.SetInfo("Title", "Page labels")
+
+	output := &bytes.Buffer{}
+	err = changes.Apply(data, output)
+	if err != nil {
+		t.Fatalf("Apply resulted in error: %s", err.Error())
+	}
+
+	file, err := Parse(output.Bytes())
+	if err != nil {
+		t.Fatalf("Parse resulted in error: %s", err.Error())
+	}
+
+	if !file.xrefStream {
+		t.Fatalf("Apply did not write a cross-reference stream")
+	}
+
+	info := getInfo(t, output.Bytes())
+	if string(info["Title"].(String)) != "Page labels" {
+		t.Fatalf("Apply resulted in wrong title, got %q", info["Title"])
+	}
+
+	root, err := file.Resolve(file.Trailer()["Root"])
+	if err != nil {
+		t.Fatalf("Resolve resulted in error: %s", err.Error())
+	}
+
+	if root.(Dictionary)["Type"] != Name("Catalog") {
+		t.Fatalf("Resolve resulted in wrong root, got %v", root)
+	}
+
+	// Make sure we can still read the objects in the object streams.
+	compressedObjects := 0
+	for number, entry := range file.xref {
+		if !entry.compressed {
+			continue
+		}
+
+		object, err := file.Object(number)
+		if err != nil {
+			t.Fatalf("Object resulted in error: %s", err.Error())
+		}
+
+		if _, ok := object.(Dictionary); !ok {
+			t.Fatalf("Object %d is not a dictionary, got %T", number, object)
+		}
+		compressedObjects++
+	}
+
+	if compressedObjects == 0 {
+		t.Fatalf("File has no objects in object streams")
+	}
+}
+
+func TestApplyOutline(t *testing.T) {
+	uri := "https://example.com"
+	changes := &Changes{}
+	changes.SetOutline([]requests.SetBookmarksBookmark{
+		{
+			Title: "Chapter 1",
+			Dest:  &requests.SetBookmarksDestination{PageIndex: 0},
+			Children: []requests.SetBookmarksBookmark{
+				{Title: "Section 1.1"},
+				{Title: "Section 1.2"},
+			},
+		},
+		{Title: "Website", URI: &uri},
+	})
+
+	output := &bytes.Buffer{}
+	err := changes.Apply(buildTestFile(), output)
+	if err != nil {
+		t.Fatalf("Apply resulted in error: %s", err.Error())
+	}
+
+	file, err := Parse(output.Bytes())
+	if err != nil {
+		t.Fatalf("Parse resulted in error: %s", err.Error())
+	}
+
+	root, err := file.Resolve(file.Trailer()["Root"])
+	if err != nil {
+		t.Fatalf("Resolve resulted in error: %s", err.Error())
+	}
+
+	outlines, err := file.Resolve(root.(Dictionary)["Outlines"])
+	if err != nil {
+		t.Fatalf("Resolve resulted in error: %s", err.Error())
+	}
+
+	// The children of the first item are closed, so they don't count.
+	if outlines.(Dictionary)["Count"] != Integer(2) {
+		t.Fatalf("Apply resulted in wrong count, got %v", outlines.(Dictionary)["Count"])
+	}
+
+	first, err := file.Resolve(outlines.(Dictionary)["First"])
+	if err != nil {
+		t.Fatalf("Resolve resulted in error: %s", err.Error())
+	}
+
+	if string(first.(Dictionary)["Title"].(String)) != "Chapter 1" {
+		t.Fatalf("Apply resulted in wrong title, got %q", first.(Dictionary)["Title"])
+	}
+
+	if first.(Dictionary)["Count"] != Integer(-2) {
+		t.Fatalf("Apply resulted in wrong count, got %v", first.(Dictionary)["Count"])
+	}
+
+	dest := first.(Dictionary)["Dest"].(Array)
+	if dest[0] != (Reference{Number: 3}) || dest[1] != Name("XYZ") {
+		t.Fatalf("Apply resulted in wrong destination, got %v", dest)
+	}
+
+	last, err := file.Resolve(outlines.(Dictionary)["Last"])
+	if err != nil {
+		t.Fatalf("Resolve resulted in error: %s", err.Error())
+	}
+
+	action := last.(Dictionary)["A"].(Dictionary)
+	if action["S"] != Name("URI") || string(action["URI"].(String)) != uri {
+		t.Fatalf("Apply resulted in wrong action, got %v", action)
+	}
+}
+
+func TestApplyPageLabels(t *testing.T) {
+	changes := &Changes{}
+	changes.SetPageLabels([]requests.SetPageLabelsRange{
+		{PageIndex: 0, Style: requests.PageLabelStyleUpperRoman, Prefix: "P-", Start: 4},
+	})
+
+	output := &bytes.Buffer{}
+	err := changes.Apply(buildTestFile(), output)
+	if err != nil {
+		t.Fatalf("Apply resulted in error: %s", err.Error())
+	}
+
+	file, err := Parse(output.Bytes())
+	if err != nil {
+		t.Fatalf("Parse resulted in error: %s", err.Error())
+	}
+
+	root, err := file.Resolve(file.Trailer()["Root"])
+	if err != nil {
+		t.Fatalf("Resolve resulted in error: %s", err.Error())
+	}
+
+	pageLabels, err := file.Resolve(root.(Dictionary)["PageLabels"])
+	if err != nil {
+		t.Fatalf("Resolve resulted in error: %s", err.Error())
+	}
+
+	nums := pageLabels.(Dictionary)["Nums"].(Array)
+	if len(nums) != 2 || nums[0] != Integer(0) {
+		t.Fatalf("Apply resulted in wrong number tree, got %v", nums)
+	}
+
+	label := nums[1].(Dictionary)
+	if label["S"] != Name("R") || string(label["P"].(String)) != "P-" || label["St"] != Integer(4) {
+		t.Fatalf("Apply resulted in wrong page label, got %v", label)
+	}
+}
+
+func TestRemoveThumbnails(t *testing.T) {
+	changes := &Changes{}
+	changes.RemoveThumbnails()
+	changes.RemoveUnusedObjects()
+
+	output := &bytes.Buffer{}
+	err := changes.Apply(buildTestFileWithObjects([]string{
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [3 0 R] /Count 1 >>",
+		"<< /Type /Page /Parent 2 0 R /MediaBox [0 0 612 792] /Thumb 5 0 R >>",
+		"<< /Title (Old title) >>",
+		"<< /Width 1 /Height 1 /BitsPerComponent 8 /ColorSpace /DeviceGray /Length 1 >>\nstream\n\x00\nendstream",
+		"<< /Unused true >>",
+	}), output)
+	if err != nil {
+		t.Fatalf("Apply resulted in error: %s", err.Error())
+	}
+
+	file, err := Parse(output.Bytes())
+	if err != nil {
+		t.Fatalf("Parse resulted in error: %s", err.Error())
+	}
+
+	page, err := file.Object(3)
+	if err != nil {
+		t.Fatalf("Object resulted in error: %s", err.Error())
+	}
+
+	if _, ok := page.(Dictionary)["Thumb"]; ok {
+		t.Fatalf("Apply did not remove the thumbnail from the page, got %v", page)
+	}
+
+	for _, number := range []int{5, 6} {
+		object, err := file.Object(number)
+		if err != nil {
+			t.Fatalf("Object resulted in error: %s", err.Error())
+		}
+
+		if _, ok := object.(Null); !ok {
+			t.Fatalf("Apply did not remove unused object %d, got %v", number, object)
+		}
+	}
+
+	if getInfo(t, output.Bytes())["Title"] == nil {
+		t.Fatalf("Apply removed the information dictionary")
+	}
+}
+
+func TestApplyIncremental(t *testing.T) {
+	changes := &Changes{}
+	changes.SetInfo("Title", "New title")
+	changes.RemoveUnusedObjects()
+
+	// An incremental save keeps the original file, the unused objects are
+	// only removed by other saves.
+	output := &bytes.Buffer{}
+	err := changes.Incremental().Apply(buildTestFile(), output)
+	if err != nil {
+		t.Fatalf("Apply resulted in error: %s", err.Error())
+	}
+
+	if !bytes.HasPrefix(output.Bytes(), buildTestFile()) {
+		t.Fatalf("Apply did not keep the original file")
+	}
+
+	info := getInfo(t, output.Bytes())
+	if string(info["Title"].(String)) != "New title" {
+		t.Fatalf("Apply resulted in wrong title, got %q", info["Title"])
+	}
+
+	if !changes.removeUnusedObjects {
+		t.Fatalf("Incremental changed the original changes")
+	}
+}
+
+func TestWithoutUnusedObjects(t *testing.T) {
+	var changes *Changes
+	if !changes.WithoutUnusedObjects().removeUnusedObjects {
+		t.Fatalf("WithoutUnusedObjects did not remove the unused objects without changes")
+	}
+
+	changes = &Changes{}
+	changes.RemoveThumbnails()
+	withoutUnusedObjects := changes.WithoutUnusedObjects()
+	if !withoutUnusedObjects.removeUnusedObjects || !withoutUnusedObjects.removeThumbnails {
+		t.Fatalf("WithoutUnusedObjects resulted in wrong changes, got %+v", withoutUnusedObjects)
+	}
+
+	if changes.removeUnusedObjects {
+		t.Fatalf("WithoutUnusedObjects changed the original changes")
+	}
+}
+
+func TestEncrypt(t *testing.T) {
+	output := &bytes.Buffer{}
+	err := Encrypt(buildTestFile(), output, requests.SaveEncryption{
+		Method:       requests.SaveEncryptionMethodAES128,
+		UserPassword: "test",
+	})
+	if err != nil {
+		t.Fatalf("Encrypt resulted in error: %s", err.Error())
+	}
+
+	if bytes.Contains(output.Bytes(), []byte("Old title")) {
+		t.Fatalf("Encrypt did not encrypt the strings")
+	}
+
+	file, err := Parse(output.Bytes())
+	if err != nil {
+		t.Fatalf("Parse resulted in error: %s", err.Error())
+	}
+
+	encrypt, err := file.Resolve(file.Trailer()["Encrypt"])
+	if err != nil {
+		t.Fatalf("Resolve resulted in error: %s", err.Error())
+	}
+
+	if encrypt.(Dictionary)["R"] != Integer(4) || encrypt.(Dictionary)["P"] != Integer(-3904) {
+		t.Fatalf("Encrypt resulted in wrong encryption dictionary, got %v", encrypt)
+	}
+
+	err = Encrypt(output.Bytes(), &bytes.Buffer{}, requests.SaveEncryption{
+		Method: requests.SaveEncryptionMethodAES256,
+	})
+	if err == nil || err.Error() != "can not encrypt an encrypted document, save the document without security" {
+		t.Fatalf("Encrypt of an encrypted document resulted in wrong error, got %v", err)
+	}
+}
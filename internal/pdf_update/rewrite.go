@@ -0,0 +1,156 @@
+package pdf_update
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+)
+
+var headerVersionPattern = regexp.MustCompile(`^%PDF-([0-9]\.[0-9])`)
+
+// headerVersion returns the version in the header of the file, 1.4 when the
+// header can't be read.
+func headerVersion(data []byte) string {
+	if match := headerVersionPattern.FindSubmatch(data); match != nil {
+		return string(match[1])
+	}
+	return "1.4"
+}
+
+// usedObjects returns the numbers of the objects that can be reached from
+// the root and the information dictionary of the file.
+func usedObjects(file *File) ([]int, error) {
+	used := map[int]bool{}
+	queue := []Object{file.Trailer()["Root"], file.Trailer()["Info"]}
+	for len(queue) > 0 {
+		object := queue[len(queue)-1]
+		queue = queue[:len(queue)-1]
+
+		switch value := object.(type) {
+		case Reference:
+			if used[value.Number] || value.Number <= 0 || value.Number >= file.Size() {
+				continue
+			}
+
+			resolved, err := file.Object(value.Number)
+			if err != nil {
+				return nil, err
+			}
+
+			if _, ok := resolved.(Null); ok {
+				continue
+			}
+
+			used[value.Number] = true
+			queue = append(queue, resolved)
+		case Array:
+			for i := range value {
+				queue = append(queue, value[i])
+			}
+		case Dictionary:
+			for key := range value {
+				queue = append(queue, value[key])
+			}
+		case *Stream:
+			queue = append(queue, value.Dictionary)
+		}
+	}
+
+	numbers := make([]int, 0, len(used))
+	for number := range used {
+		numbers = append(numbers, number)
+	}
+	sort.Ints(numbers)
+
+	return numbers, nil
+}
+
+// rewriteFile writes all the used objects of the file into a new file with
+// a cross-reference table, objects that are not used anymore are dropped.
+// When a security handler is given, the objects are encrypted and the
+// encryption dictionary is added.
+func rewriteFile(file *File, w io.Writer, version string, id Array, handler *securityHandler, encrypt Dictionary) error {
+	numbers, err := usedObjects(file)
+	if err != nil {
+		return err
+	}
+
+	buf := &bytes.Buffer{}
+	buf.WriteString(fmt.Sprintf("%%PDF-%s\n%%\xE2\xE3\xCF\xD3\n", version))
+
+	offsets := map[int]int64{}
+	generations := map[int]int{}
+	for _, number := range numbers {
+		object, err := file.Object(number)
+		if err != nil {
+			return err
+		}
+
+		generation := file.Generation(number)
+		if handler != nil {
+			object, err = handler.encryptObject(object, number, generation)
+			if err != nil {
+				return err
+			}
+		}
+
+		offsets[number] = int64(buf.Len())
+		generations[number] = generation
+		buf.WriteString(fmt.Sprintf("%d %d obj\n", number, generation))
+		if err := writeObject(buf, object); err != nil {
+			return err
+		}
+		buf.WriteString("\nendobj\n")
+	}
+
+	trailer := Dictionary{}
+	for _, key := range []Name{"Root", "Info"} {
+		if value, ok := file.Trailer()[key]; ok {
+			trailer[key] = value
+		}
+	}
+
+	if id != nil {
+		trailer["ID"] = id
+	}
+
+	size := file.Size()
+	if encrypt != nil {
+		// The encryption dictionary itself is not encrypted.
+		encryptNumber := size
+		size++
+
+		offsets[encryptNumber] = int64(buf.Len())
+		buf.WriteString(fmt.Sprintf("%d 0 obj\n", encryptNumber))
+		if err := writeObject(buf, encrypt); err != nil {
+			return err
+		}
+		buf.WriteString("\nendobj\n")
+
+		trailer["Encrypt"] = Reference{Number: encryptNumber}
+	}
+	trailer["Size"] = Integer(size)
+
+	// The objects of object streams are written as normal objects, so the
+	// new file can use a cross-reference table.
+	xrefOffset := buf.Len()
+	buf.WriteString(fmt.Sprintf("xref\n0 %d\n0000000000 65535 f\r\n", size))
+	for number := 1; number < size; number++ {
+		if offset, ok := offsets[number]; ok {
+			buf.WriteString(fmt.Sprintf("%010d %05d n\r\n", offset, generations[number]))
+		} else {
+			buf.WriteString("0000000000 00000 f\r\n")
+		}
+	}
+
+	buf.WriteString("trailer\n")
+	if err := writeObject(buf, trailer); err != nil {
+		return err
+	}
+	buf.WriteString(fmt.Sprintf("\nstartxref\n%d\n%%%%EOF\n", xrefOffset))
+
+	_, err = buf.WriteTo(w)
+	return err
+}
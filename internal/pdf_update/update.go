@@ -0,0 +1,184 @@
+package pdf_update
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"sort"
+)
+
+// Update is an incremental update of a PDF file, it only contains the new
+// and changed objects.
+type Update struct {
+	file    *File
+	objects map[int]Object
+	size    int
+	Trailer Dictionary // Entries that are set in the trailer of the update, on top of Root, Info, ID and Encrypt of the file.
+}
+
+// NewUpdate starts a new incremental update of the file.
+func (f *File) NewUpdate() *Update {
+	return &Update{
+		file:    f,
+		objects: map[int]Object{},
+		size:    f.size,
+		Trailer: Dictionary{},
+	}
+}
+
+// Add adds a new object to the update and returns the reference to it.
+func (u *Update) Add(object Object) Reference {
+	number := u.size
+	u.size++
+	u.objects[number] = object
+	return Reference{Number: number}
+}
+
+// Reserve returns a reference for a new object that is set later, this
+// allows objects to refer to each other.
+func (u *Update) Reserve() Reference {
+	number := u.size
+	u.size++
+	u.objects[number] = Null{}
+	return Reference{Number: number}
+}
+
+// Set replaces the object with the given object number.
+func (u *Update) Set(number int, object Object) {
+	u.objects[number] = object
+	if number >= u.size {
+		u.size = number + 1
+	}
+}
+
+// Object returns the object with the given number, taking the changes of
+// the update into account.
+func (u *Update) Object(number int) (Object, error) {
+	if object, ok := u.objects[number]; ok {
+		return object, nil
+	}
+	return u.file.Object(number)
+}
+
+// Resolve returns the object that the reference points to, taking the
+// changes of the update into account.
+func (u *Update) Resolve(object Object) (Object, error) {
+	reference, ok := object.(Reference)
+	if !ok {
+		return object, nil
+	}
+	return u.Object(reference.Number)
+}
+
+// WriteTo writes the original file followed by the incremental update.
+func (u *Update) WriteTo(w io.Writer) (int64, error) {
+	buf := &bytes.Buffer{}
+	buf.Write(u.file.data)
+	if len(u.file.data) > 0 && u.file.data[len(u.file.data)-1] != '\n' && u.file.data[len(u.file.data)-1] != '\r' {
+		buf.WriteString("\n")
+	}
+
+	numbers := make([]int, 0, len(u.objects))
+	for number := range u.objects {
+		numbers = append(numbers, number)
+	}
+	sort.Ints(numbers)
+
+	offsets := map[int]int64{}
+	generations := map[int]int{}
+	for _, number := range numbers {
+		generation := u.file.Generation(number)
+		offsets[number] = int64(buf.Len())
+		generations[number] = generation
+		buf.WriteString(fmt.Sprintf("%d %d obj\n", number, generation))
+		if err := writeObject(buf, u.objects[number]); err != nil {
+			return 0, err
+		}
+		buf.WriteString("\nendobj\n")
+	}
+
+	trailer := Dictionary{}
+	for _, key := range []Name{"Root", "Info", "ID", "Encrypt"} {
+		if value, ok := u.file.trailer[key]; ok {
+			trailer[key] = value
+		}
+	}
+	for key, value := range u.Trailer {
+		trailer[key] = value
+	}
+	trailer["Prev"] = Integer(u.file.startXref)
+
+	xrefOffset := int64(buf.Len())
+	if u.file.xrefStream {
+		// Keep using cross-reference streams when the file uses them, older
+		// sections can't be referred to from a cross-reference table.
+		xrefNumber := u.size
+		numbers = append(numbers, xrefNumber)
+		offsets[xrefNumber] = xrefOffset
+
+		offsetWidth := 4
+		if xrefOffset > 0xFFFFFFFF {
+			offsetWidth = 8
+		}
+
+		index := Array{}
+		data := []byte{}
+		for _, section := range xrefSections(numbers) {
+			index = append(index, Integer(section[0]), Integer(len(section)))
+			for _, number := range section {
+				offset := make([]byte, 8)
+				binary.BigEndian.PutUint64(offset, uint64(offsets[number]))
+				data = append(data, 1)
+				data = append(data, offset[8-offsetWidth:]...)
+				data = append(data, byte(generations[number]>>8), byte(generations[number]))
+			}
+		}
+
+		trailer["Type"] = Name("XRef")
+		trailer["Size"] = Integer(xrefNumber + 1)
+		trailer["W"] = Array{Integer(1), Integer(offsetWidth), Integer(2)}
+		trailer["Index"] = index
+
+		buf.WriteString(fmt.Sprintf("%d 0 obj\n", xrefNumber))
+		if err := writeObject(buf, &Stream{Dictionary: trailer, Data: data}); err != nil {
+			return 0, err
+		}
+		buf.WriteString("\nendobj\n")
+	} else {
+		buf.WriteString("xref\n")
+		for _, section := range xrefSections(numbers) {
+			buf.WriteString(fmt.Sprintf("%d %d\n", section[0], len(section)))
+			for _, number := range section {
+				buf.WriteString(fmt.Sprintf("%010d %05d n\r\n", offsets[number], generations[number]))
+			}
+		}
+
+		trailer["Size"] = Integer(u.size)
+		buf.WriteString("trailer\n")
+		if err := writeObject(buf, trailer); err != nil {
+			return 0, err
+		}
+		buf.WriteString("\n")
+	}
+
+	buf.WriteString(fmt.Sprintf("startxref\n%d\n%%%%EOF\n", xrefOffset))
+
+	return buf.WriteTo(w)
+}
+
+// xrefSections groups sorted object numbers into consecutive runs.
+func xrefSections(numbers []int) [][]int {
+	sections := [][]int{}
+	for _, number := range numbers {
+		if len(sections) > 0 {
+			last := sections[len(sections)-1]
+			if last[len(last)-1] == number-1 {
+				sections[len(sections)-1] = append(last, number)
+				continue
+			}
+		}
+		sections = append(sections, []int{number})
+	}
+	return sections
+}
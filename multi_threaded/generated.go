@@ -11,6 +11,70 @@ import (
 	"github.com/klippa-app/go-pdfium/responses"
 )
 
+func (i *pdfiumInstance) AddHeaderFooter(request *requests.AddHeaderFooter) (*responses.AddHeaderFooter, error) {
+	if i.closed {
+		return nil, errors.New("instance is closed")
+	}
+
+	return i.worker.plugin.AddHeaderFooter(request)
+}
+
+func (i *pdfiumInstance) CheckAccessibility(request *requests.CheckAccessibility) (*responses.CheckAccessibility, error) {
+	if i.closed {
+		return nil, errors.New("instance is closed")
+	}
+
+	return i.worker.plugin.CheckAccessibility(request)
+}
+
+func (i *pdfiumInstance) CompareDocuments(request *requests.CompareDocuments) (*responses.CompareDocuments, error) {
+	if i.closed {
+		return nil, errors.New("instance is closed")
+	}
+
+	return i.worker.plugin.CompareDocuments(request)
+}
+
+func (i *pdfiumInstance) CompareDocumentsText(request *requests.CompareDocumentsText) (*responses.CompareDocumentsText, error) {
+	if i.closed {
+		return nil, errors.New("instance is closed")
+	}
+
+	return i.worker.plugin.CompareDocumentsText(request)
+}
+
+func (i *pdfiumInstance) CreateTextMarkupAnnotations(request *requests.CreateTextMarkupAnnotations) (*responses.CreateTextMarkupAnnotations, error) {
+	if i.closed {
+		return nil, errors.New("instance is closed")
+	}
+
+	return i.worker.plugin.CreateTextMarkupAnnotations(request)
+}
+
+func (i *pdfiumInstance) CropPages(request *requests.CropPages) (*responses.CropPages, error) {
+	if i.closed {
+		return nil, errors.New("instance is closed")
+	}
+
+	return i.worker.plugin.CropPages(request)
+}
+
+func (i *pdfiumInstance) ExportAnnotations(request *requests.ExportAnnotations) (*responses.ExportAnnotations, error) {
+	if i.closed {
+		return nil, errors.New("instance is closed")
+	}
+
+	return i.worker.plugin.ExportAnnotations(request)
+}
+
+func (i *pdfiumInstance) ExportFormData(request *requests.ExportFormData) (*responses.ExportFormData, error) {
+	if i.closed {
+		return nil, errors.New("instance is closed")
+	}
+
+	return i.worker.plugin.ExportFormData(request)
+}
+
 func (i *pdfiumInstance) FORM_CanRedo(request *requests.FORM_CanRedo) (*responses.FORM_CanRedo, error) {
 	if i.closed {
 		return nil, errors.New("instance is closed")
@@ -3379,6 +3443,30 @@ func (i *pdfiumInstance) FSDK_SetUnSpObjProcessHandler(request *requests.FSDK_Se
 	return nil, errors.New("unsupported method on multi-threaded usage")
 }
 
+func (i *pdfiumInstance) FillFormFields(request *requests.FillFormFields) (*responses.FillFormFields, error) {
+	if i.closed {
+		return nil, errors.New("instance is closed")
+	}
+
+	return i.worker.plugin.FillFormFields(request)
+}
+
+func (i *pdfiumInstance) FlattenDocument(request *requests.FlattenDocument) (*responses.FlattenDocument, error) {
+	if i.closed {
+		return nil, errors.New("instance is closed")
+	}
+
+	return i.worker.plugin.FlattenDocument(request)
+}
+
+func (i *pdfiumInstance) GenerateAnnotationAppearance(request *requests.GenerateAnnotationAppearance) (*responses.GenerateAnnotationAppearance, error) {
+	if i.closed {
+		return nil, errors.New("instance is closed")
+	}
+
+	return i.worker.plugin.GenerateAnnotationAppearance(request)
+}
+
 func (i *pdfiumInstance) GetActionInfo(request *requests.GetActionInfo) (*responses.GetActionInfo, error) {
 	if i.closed {
 		return nil, errors.New("instance is closed")
@@ -3387,6 +3475,14 @@ func (i *pdfiumInstance) GetActionInfo(request *requests.GetActionInfo) (*respon
 	return i.worker.plugin.GetActionInfo(request)
 }
 
+func (i *pdfiumInstance) GetAnnotations(request *requests.GetAnnotations) (*responses.GetAnnotations, error) {
+	if i.closed {
+		return nil, errors.New("instance is closed")
+	}
+
+	return i.worker.plugin.GetAnnotations(request)
+}
+
 func (i *pdfiumInstance) GetAttachments(request *requests.GetAttachments) (*responses.GetAttachments, error) {
 	if i.closed {
 		return nil, errors.New("instance is closed")
@@ -3411,6 +3507,22 @@ func (i *pdfiumInstance) GetDestInfo(request *requests.GetDestInfo) (*responses.
 	return i.worker.plugin.GetDestInfo(request)
 }
 
+func (i *pdfiumInstance) GetFonts(request *requests.GetFonts) (*responses.GetFonts, error) {
+	if i.closed {
+		return nil, errors.New("instance is closed")
+	}
+
+	return i.worker.plugin.GetFonts(request)
+}
+
+func (i *pdfiumInstance) GetFormFields(request *requests.GetFormFields) (*responses.GetFormFields, error) {
+	if i.closed {
+		return nil, errors.New("instance is closed")
+	}
+
+	return i.worker.plugin.GetFormFields(request)
+}
+
 func (i *pdfiumInstance) GetJavaScriptActions(request *requests.GetJavaScriptActions) (*responses.GetJavaScriptActions, error) {
 	if i.closed {
 		return nil, errors.New("instance is closed")
@@ -3459,6 +3571,38 @@ func (i *pdfiumInstance) GetPageTextStructured(request *requests.GetPageTextStru
 	return i.worker.plugin.GetPageTextStructured(request)
 }
 
+func (i *pdfiumInstance) GetPageThumbnail(request *requests.GetPageThumbnail) (*responses.GetPageThumbnail, error) {
+	if i.closed {
+		return nil, errors.New("instance is closed")
+	}
+
+	return i.worker.plugin.GetPageThumbnail(request)
+}
+
+func (i *pdfiumInstance) GetStructTree(request *requests.GetStructTree) (*responses.GetStructTree, error) {
+	if i.closed {
+		return nil, errors.New("instance is closed")
+	}
+
+	return i.worker.plugin.GetStructTree(request)
+}
+
+func (i *pdfiumInstance) ImportAnnotations(request *requests.ImportAnnotations) (*responses.ImportAnnotations, error) {
+	if i.closed {
+		return nil, errors.New("instance is closed")
+	}
+
+	return i.worker.plugin.ImportAnnotations(request)
+}
+
+func (i *pdfiumInstance) ImportFormData(request *requests.ImportFormData) (*responses.ImportFormData, error) {
+	if i.closed {
+		return nil, errors.New("instance is closed")
+	}
+
+	return i.worker.plugin.ImportFormData(request)
+}
+
 func (i *pdfiumInstance) OpenDocument(request *requests.OpenDocument) (*responses.OpenDocument, error) {
 	if i.closed {
 		return nil, errors.New("instance is closed")
@@ -3479,6 +3623,22 @@ func (i *pdfiumInstance) OpenDocument(request *requests.OpenDocument) (*response
 	return i.worker.plugin.OpenDocument(request)
 }
 
+func (i *pdfiumInstance) OptimizeDocument(request *requests.OptimizeDocument) (*responses.OptimizeDocument, error) {
+	if i.closed {
+		return nil, errors.New("instance is closed")
+	}
+
+	return i.worker.plugin.OptimizeDocument(request)
+}
+
+func (i *pdfiumInstance) Preflight(request *requests.Preflight) (*responses.Preflight, error) {
+	if i.closed {
+		return nil, errors.New("instance is closed")
+	}
+
+	return i.worker.plugin.Preflight(request)
+}
+
 func (i *pdfiumInstance) RenderPageInDPI(request *requests.RenderPageInDPI) (*responses.RenderPageInDPI, error) {
 	if i.closed {
 		return nil, errors.New("instance is closed")
@@ -3518,3 +3678,55 @@ func (i *pdfiumInstance) RenderToFile(request *requests.RenderToFile) (*response
 
 	return i.worker.plugin.RenderToFile(request)
 }
+
+func (i *pdfiumInstance) ResizePages(request *requests.ResizePages) (*responses.ResizePages, error) {
+	if i.closed {
+		return nil, errors.New("instance is closed")
+	}
+
+	return i.worker.plugin.ResizePages(request)
+}
+
+func (i *pdfiumInstance) SetBookmarks(request *requests.SetBookmarks) (*responses.SetBookmarks, error) {
+	if i.closed {
+		return nil, errors.New("instance is closed")
+	}
+
+	return i.worker.plugin.SetBookmarks(request)
+}
+
+func (i *pdfiumInstance) SetMetaData(request *requests.SetMetaData) (*responses.SetMetaData, error) {
+	if i.closed {
+		return nil, errors.New("instance is closed")
+	}
+
+	return i.worker.plugin.SetMetaData(request)
+}
+
+func (i *pdfiumInstance) SetPageLabels(request *requests.SetPageLabels) (*responses.SetPageLabels, error) {
+	if i.closed {
+		return nil, errors.New("instance is closed")
+	}
+
+	return i.worker.plugin.SetPageLabels(request)
+}
+
+func (i *pdfiumInstance) SignDocument(request *requests.SignDocument) (*responses.SignDocument, error) {
+	if i.closed {
+		return nil, errors.New("instance is closed")
+	}
+
+	if request.Signer != nil {
+		return nil, errors.New("using a crypto.Signer is not supported on multi-threaded usage, use PrivateKey")
+	}
+
+	return i.worker.plugin.SignDocument(request)
+}
+
+func (i *pdfiumInstance) VerifySignatures(request *requests.VerifySignatures) (*responses.VerifySignatures, error) {
+	if i.closed {
+		return nil, errors.New("instance is closed")
+	}
+
+	return i.worker.plugin.VerifySignatures(request)
+}
@@ -75,6 +75,11 @@ type Pdfium interface {
 	// GetMetaData returns the metadata values of the document.
 	GetMetaData(request *requests.GetMetaData) (*responses.GetMetaData, error)
 
+	// SetMetaData sets metadata values of the document. PDFium has no API for
+	// this, the values are written into the Info dictionary when the document
+	// is saved with FPDF_SaveAsCopy or FPDF_SaveWithVersion.
+	SetMetaData(request *requests.SetMetaData) (*responses.SetMetaData, error)
+
 	// End text: metadata helpers
 
 	// Start render: render helpers
@@ -101,6 +106,12 @@ type Pdfium interface {
 	// and output the resulting image into a file.
 	RenderToFile(request *requests.RenderToFile) (*responses.RenderToFile, error)
 
+	// GetPageThumbnail returns the embedded thumbnail of a page when it has
+	// one that is large enough for the maximum size, otherwise the page is
+	// rendered in the maximum size. The output options are the same as the
+	// options of RenderToFile.
+	GetPageThumbnail(request *requests.GetPageThumbnail) (*responses.GetPageThumbnail, error)
+
 	// End render
 
 	// Start bookmark: bookmark helpers
@@ -108,6 +119,11 @@ type Pdfium interface {
 	// GetBookmarks returns all the bookmarks of a document.
 	GetBookmarks(request *requests.GetBookmarks) (*responses.GetBookmarks, error)
 
+	// SetBookmarks replaces the bookmarks (outline) of a document. PDFium has
+	// no API for this, the bookmarks are written when the document is saved
+	// with FPDF_SaveAsCopy or FPDF_SaveWithVersion.
+	SetBookmarks(request *requests.SetBookmarks) (*responses.SetBookmarks, error)
+
 	// End bookmark
 
 	// Start action: action helpers
@@ -124,6 +140,220 @@ type Pdfium interface {
 
 	// End dest
 
+	// Start crop: crop helpers
+
+	// CropPages sets the page boxes (CropBox by default) of the given pages.
+	// The box can either be given, or be detected from the bounds of the
+	// page objects or from a low DPI render of the page.
+	CropPages(request *requests.CropPages) (*responses.CropPages, error)
+
+	// End crop
+
+	// Start resize: resize helpers
+
+	// ResizePages rescales the content of the given pages to a target page
+	// size, this can be used to normalize documents with mixed page sizes.
+	// The annotations of the pages are transformed as well.
+	ResizePages(request *requests.ResizePages) (*responses.ResizePages, error)
+
+	// End resize
+
+	// Start header_footer: header and footer helpers
+
+	// AddHeaderFooter adds texts like page numbers and Bates numbers to the
+	// header and footer of the pages of one or multiple documents, the running
+	// number continues over the documents.
+	AddHeaderFooter(request *requests.AddHeaderFooter) (*responses.AddHeaderFooter, error)
+
+	// End header_footer
+
+	// Start page_label: page label helpers
+
+	// SetPageLabels replaces the page labels of a document. PDFium can only
+	// read page labels, these are written into the catalog when the document
+	// is saved with FPDF_SaveAsCopy or FPDF_SaveWithVersion.
+	SetPageLabels(request *requests.SetPageLabels) (*responses.SetPageLabels, error)
+
+	// End page_label
+
+	// Start optimize: optimize helpers
+
+	// OptimizeDocument reduces the file size of a document by downsampling
+	// images with a high resolution, and optionally by removing thumbnails
+	// and embedded files. The unused objects are removed when the document
+	// is saved by OptimizeDocument.
+	// Experimental API.
+	OptimizeDocument(request *requests.OptimizeDocument) (*responses.OptimizeDocument, error)
+
+	// End optimize
+
+	// Start fonts: font helpers
+
+	// GetFonts returns the fonts that are used by the text of a document,
+	// with the pages they are used on. A font is only returned once, also
+	// when it's used on multiple pages.
+	// Experimental API.
+	GetFonts(request *requests.GetFonts) (*responses.GetFonts, error)
+
+	// End fonts
+
+	// Start preflight: preflight helpers
+
+	// Preflight gathers what PDFium can tell about the compliance of a
+	// document, like the encryption, fonts and transparency, and checks it
+	// against the rules of PDF/A and print profiles. This can't replace a full
+	// PDF/A validator, it tells whether a document is likely compliant.
+	// Experimental API.
+	Preflight(request *requests.Preflight) (*responses.Preflight, error)
+
+	// End preflight
+
+	// Start compare: compare helpers
+
+	// CompareDocuments renders the pages of two documents and compares them
+	// pixel by pixel. Pages that are the same in both documents are matched,
+	// so added and removed pages are detected and don't cause the pages after
+	// them to be reported as changed.
+	CompareDocuments(request *requests.CompareDocuments) (*responses.CompareDocuments, error)
+
+	// CompareDocumentsText compares the words of two documents. The words of
+	// all pages are aligned, so text that moved to another page is not
+	// reported as changed. The inserted and deleted words are returned with
+	// their bounding boxes, so they can be highlighted.
+	CompareDocumentsText(request *requests.CompareDocumentsText) (*responses.CompareDocumentsText, error)
+
+	// End compare
+
+	// Start form: form helpers
+
+	// GetFormFields returns the interactive form (AcroForm) fields of a
+	// document, with their value, options and widgets. The widgets of a field
+	// are grouped by the fully qualified name of the field.
+	// Experimental API.
+	GetFormFields(request *requests.GetFormFields) (*responses.GetFormFields, error)
+
+	// FillFormFields fills the interactive form (AcroForm) fields of a document
+	// by their fully qualified name. The fields are filled through the form fill
+	// environment, like a user would, so the appearances of the fields are
+	// regenerated. Values that don't fit the field, or that are changed by the
+	// form, are rejected, rejected fields keep their previous value.
+	// Experimental API.
+	FillFormFields(request *requests.FillFormFields) (*responses.FillFormFields, error)
+
+	// ExportFormData exports the values of the interactive form (AcroForm)
+	// fields of a document as FDF or XFDF. Buttons, signatures and fields that
+	// are marked to not be exported are not included.
+	// Experimental API.
+	ExportFormData(request *requests.ExportFormData) (*responses.ExportFormData, error)
+
+	// ImportFormData imports FDF or XFDF form data into the interactive form
+	// (AcroForm) fields of a document. The fields are filled with FillFormFields,
+	// so the values are validated in the same way.
+	// Experimental API.
+	ImportFormData(request *requests.ImportFormData) (*responses.ImportFormData, error)
+
+	// End form
+
+	// Start annotation: annotation helpers
+
+	// GetAnnotations returns the annotations of a document per page, with their
+	// subtype, rect, contents, author, colors, flags, points, attached files
+	// and the annotations they link to. The colors are not given for
+	// annotations with an appearance stream, PDFium doesn't return them.
+	// Experimental API.
+	GetAnnotations(request *requests.GetAnnotations) (*responses.GetAnnotations, error)
+
+	// ExportAnnotations exports the markup annotations (comments) of a
+	// document as XFDF. Popups are exported with the annotation that they
+	// belong to.
+	// Experimental API.
+	ExportAnnotations(request *requests.ExportAnnotations) (*responses.ExportAnnotations, error)
+
+	// ImportAnnotations imports the markup annotations (comments) of an XFDF
+	// document into a document. Annotations of subtypes that PDFium can't
+	// create are skipped. Popups and replies are not linked, PDFium has no API
+	// to link annotations.
+	// Experimental API.
+	ImportAnnotations(request *requests.ImportAnnotations) (*responses.ImportAnnotations, error)
+
+	// CreateTextMarkupAnnotations creates highlight, underline, squiggly or
+	// strikeout annotations for text ranges or the hits of a search. The quad
+	// points are derived from the character boxes, one per line of text, and
+	// an appearance stream is generated so that the annotations are rendered.
+	// Experimental API.
+	CreateTextMarkupAnnotations(request *requests.CreateTextMarkupAnnotations) (*responses.CreateTextMarkupAnnotations, error)
+
+	// GenerateAnnotationAppearance generates the normal appearance stream of a
+	// square, circle, line, polygon, polyline, ink, free text, stamp or text
+	// markup annotation from its properties, like its colors, border width,
+	// points and text, so that annotations created with FPDFPage_CreateAnnot
+	// are rendered. An existing appearance is replaced, except for stamps that
+	// already have objects, and is kept when generating fails. Free text is
+	// drawn as the outlines of the glyphs, stamps get a border and their name
+	// as text through FPDFAnnot_AppendObject.
+	// Experimental API.
+	GenerateAnnotationAppearance(request *requests.GenerateAnnotationAppearance) (*responses.GenerateAnnotationAppearance, error)
+
+	// End annotation
+
+	// Start flatten: flatten helpers
+
+	// FlattenDocument flattens the form fields, the annotations, or both, of
+	// a range of pages into the page content, for display or for print. Only
+	// annotations that are visible for the given usage are drawn. When only
+	// the form fields or only the annotations are flattened, the other
+	// annotations are kept on the page.
+	// Experimental API.
+	FlattenDocument(request *requests.FlattenDocument) (*responses.FlattenDocument, error)
+
+	// End flatten
+
+	// Start signature: signature helpers
+
+	// VerifySignatures verifies the digital signatures of a document. The
+	// digest is recalculated over the byte range of the original file, the
+	// signature is verified with the certificate of the signer, and that
+	// certificate is validated against the given trusted certificates,
+	// without using the network or the certificates of the system. The
+	// certificate is validated at the current time, only a valid time stamp
+	// token is trusted to give the time of signing. It also reports whether
+	// the file was changed after signing.
+	// Experimental API.
+	VerifySignatures(request *requests.VerifySignatures) (*responses.VerifySignatures, error)
+
+	// SignDocument signs a document with an incremental update, so that
+	// existing signatures stay valid. A signature field is added, visible
+	// with a text and an image or invisible, and a detached CMS signature
+	// over the byte range of the file is embedded in it. The signed file is
+	// returned, the opened document is not changed.
+	// Experimental API.
+	SignDocument(request *requests.SignDocument) (*responses.SignDocument, error)
+
+	// End signature
+
+	// Start struct_tree: structure tree helpers
+
+	// GetStructTree returns the tagged structure of a document or of a range
+	// of pages: the elements with their type, title, alt text, actual text,
+	// language, ID, attributes, marked content IDs and children. The
+	// structure tree is given per page.
+	// Experimental API.
+	GetStructTree(request *requests.GetStructTree) (*responses.GetStructTree, error)
+
+	// End struct_tree
+	// Start accessibility: accessibility helpers
+
+	// CheckAccessibility checks a document for common accessibility problems
+	// and returns a report with the result per check and every issue that
+	// was found: whether the document is tagged and has a title and a
+	// language, content that is not tagged or marked as an artifact, figures
+	// without alternate text, headings that skip a level and tables without
+	// header cells. This can't replace a full PDF/UA validator.
+	// Experimental API.
+	CheckAccessibility(request *requests.CheckAccessibility) (*responses.CheckAccessibility, error)
+
+	// End accessibility
+
 	// Start fpdfview.h
 
 	// FPDF_LoadDocument opens and load a PDF document from a file path.
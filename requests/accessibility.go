@@ -0,0 +1,8 @@
+package requests
+
+import "github.com/klippa-app/go-pdfium/references"
+
+type CheckAccessibility struct {
+	Document  references.FPDF_DOCUMENT
+	PageRange *string // The page ranges to check the content and the structure of, such as "1,3,5-7". If it is nil, all pages are checked. The title and the language of the document are always checked.
+}
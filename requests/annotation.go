@@ -0,0 +1,51 @@
+package requests
+
+import (
+	"github.com/klippa-app/go-pdfium/enums"
+	"github.com/klippa-app/go-pdfium/references"
+	"github.com/klippa-app/go-pdfium/structs"
+)
+
+type GetAnnotations struct {
+	Document references.FPDF_DOCUMENT
+}
+
+type ExportAnnotations struct {
+	Document references.FPDF_DOCUMENT
+	FileName string // The file name of the document to refer to from the XFDF, optional.
+}
+
+type ImportAnnotations struct {
+	Document references.FPDF_DOCUMENT
+	Data     []byte // The XFDF data.
+}
+
+type TextMarkupRange struct {
+	Page       int // The index of the page (0-index based).
+	StartIndex int // The index of the first character of the range.
+	Count      int // The amount of characters in the range.
+}
+
+type TextMarkupSearch struct {
+	Text      string                 // The text to search for.
+	Flags     FPDFText_FindStartFlag // The search flags.
+	PageRange *string                // The page ranges, such as "1,3,5-7". If it is nil, all pages will be searched.
+}
+
+type CreateTextMarkupAnnotations struct {
+	Document references.FPDF_DOCUMENT
+	Subtype  enums.FPDF_ANNOTATION_SUBTYPE // The subtype of the annotations, one of highlight, underline, squiggly and strikeout.
+	Ranges   []TextMarkupRange             // The text ranges to mark, every range gets its own annotation.
+	Search   *TextMarkupSearch             // Mark every hit of a search, every hit gets its own annotation.
+	Color    *structs.FPDF_COLOR           // The color of the annotations, the alpha channel is the opacity. Highlights are drawn over the text, so use a transparent color for them. The default is yellow with an opacity of 50% for highlights and red for the other subtypes.
+	Contents string                        // The text of the annotations, optional.
+	Author   string                        // The author of the annotations, optional.
+}
+
+type GenerateAnnotationAppearance struct {
+	Document   references.FPDF_DOCUMENT
+	Annotation references.FPDF_ANNOTATION
+	Font       string              // The name of one of the 14 standard fonts, like Helvetica or Times-Roman, to draw the text of free text and stamp annotations with. The default is Helvetica.
+	FontSize   float32             // The font size of the text of free text annotations in points. The default is 12. The text of stamps is scaled to fit the stamp.
+	TextColor  *structs.FPDF_COLOR // The color of the text of free text annotations. The default is black.
+}
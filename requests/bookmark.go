@@ -5,3 +5,23 @@ import "github.com/klippa-app/go-pdfium/references"
 type GetBookmarks struct {
 	Document references.FPDF_DOCUMENT
 }
+
+type SetBookmarksDestination struct {
+	PageIndex int      // The page index (0-index based) to go to.
+	X         *float32 // The left position on the page in points. If nil, the current position is kept.
+	Y         *float32 // The top position on the page in points. If nil, the current position is kept.
+	Zoom      *float32 // The zoom factor, 1 is 100%. If nil, the current zoom is kept.
+}
+
+type SetBookmarksBookmark struct {
+	Title    string
+	Dest     *SetBookmarksDestination // The destination in the document to go to.
+	URI      *string                  // The URI to open, can't be combined with Dest.
+	Open     bool                     // Whether the children of the bookmark are shown (expanded) when the document is opened.
+	Children []SetBookmarksBookmark
+}
+
+type SetBookmarks struct {
+	Document  references.FPDF_DOCUMENT
+	Bookmarks []SetBookmarksBookmark // The new bookmarks of the document. When empty, the bookmarks of the document are removed.
+}
@@ -0,0 +1,21 @@
+package requests
+
+import (
+	"github.com/klippa-app/go-pdfium/enums"
+	"github.com/klippa-app/go-pdfium/references"
+)
+
+type CompareDocuments struct {
+	Original          references.FPDF_DOCUMENT // The original document, like the document of the previous release.
+	Modified          references.FPDF_DOCUMENT // The modified document to compare with the original document.
+	DPI               int                      // The DPI to render the pages in.
+	Tolerance         int                      // The difference (0-255) that is allowed per color channel before a pixel counts as changed, to ignore small rendering differences. The default is 0.
+	IncludeDiffImages bool                     // Whether to create an image of the changed pages that highlights the changed pixels.
+	RenderFlags       enums.FPDF_RENDER_FLAG   // The flags to render the pages with.
+}
+
+type CompareDocumentsText struct {
+	Original references.FPDF_DOCUMENT // The original document, like the document of the previous release.
+	Modified references.FPDF_DOCUMENT // The modified document to compare with the original document.
+	DPI      int                      // When given, the pixel positions of the words are calculated for pages rendered in this DPI.
+}
@@ -0,0 +1,39 @@
+package requests
+
+import (
+	"github.com/klippa-app/go-pdfium/enums"
+	"github.com/klippa-app/go-pdfium/references"
+)
+
+type CropPagesMode string // How to determine the box to crop the pages to.
+
+const (
+	CropPagesModeValues  CropPagesMode = "values"  // Crop the pages to the given Left, Bottom, Right and Top values.
+	CropPagesModeObjects CropPagesMode = "objects" // Crop the pages to the combined bounds of the visible page objects.
+	CropPagesModeRender  CropPagesMode = "render"  // Crop the pages to the non-white area of a low DPI render of the page.
+)
+
+type CropPagesBox string // A page box that can be set.
+
+const (
+	CropPagesBoxMediaBox CropPagesBox = "MediaBox"
+	CropPagesBoxCropBox  CropPagesBox = "CropBox"
+	CropPagesBoxBleedBox CropPagesBox = "BleedBox"
+	CropPagesBoxTrimBox  CropPagesBox = "TrimBox"
+	CropPagesBoxArtBox   CropPagesBox = "ArtBox"
+)
+
+type CropPages struct {
+	Document            references.FPDF_DOCUMENT
+	PageRange           *string                // The page ranges, such as "1,3,5-7". If it is nil, all pages will be cropped.
+	Mode                CropPagesMode          // How to determine the box to crop the pages to.
+	Boxes               []CropPagesBox         // The page boxes to set. When empty, only the CropBox will be set.
+	Left                float32                // The left of the box in points, only used in CropPagesModeValues.
+	Bottom              float32                // The bottom of the box in points, only used in CropPagesModeValues.
+	Right               float32                // The right of the box in points, only used in CropPagesModeValues.
+	Top                 float32                // The top of the box in points, only used in CropPagesModeValues.
+	Margin              float32                // The margin in points to add around the detected content bounds. Not used in CropPagesModeValues.
+	DPI                 int                    // The DPI to render the page in, only used in CropPagesModeRender. The default is 72.
+	RenderFlags         enums.FPDF_RENDER_FLAG // The flags to render the page with, only used in CropPagesModeRender.
+	BackgroundTolerance int                    // How much (0-255) a pixel may differ from white to still be seen as background, only used in CropPagesModeRender.
+}
@@ -0,0 +1,20 @@
+package requests
+
+import "github.com/klippa-app/go-pdfium/references"
+
+type FlattenDocumentMode string // What to flatten.
+
+const (
+	FlattenDocumentModeAll         FlattenDocumentMode = "all"         // Flatten the form fields and the annotations.
+	FlattenDocumentModeFormFields  FlattenDocumentMode = "form_fields" // Only flatten the form fields (widget annotations), other annotations are kept.
+	FlattenDocumentModeAnnotations FlattenDocumentMode = "annotations" // Only flatten the annotations that are not form fields, the form fields are kept.
+)
+
+type FlattenDocument struct {
+	Document  references.FPDF_DOCUMENT
+	PageRange *string               // The page ranges, such as "1,3,5-7". If it is nil, all pages will be flattened.
+	Mode      FlattenDocumentMode   // What to flatten. When empty, everything is flattened.
+	Usage     FPDFPage_FlattenUsage // The usage to flatten for, this decides which appearances are used and which annotations are skipped.
+	Save      bool                  // Whether to save the document after flattening, the document is returned as bytes when no FilePath is given.
+	FilePath  *string               // A path to save the file to.
+}
@@ -0,0 +1,8 @@
+package requests
+
+import "github.com/klippa-app/go-pdfium/references"
+
+type GetFonts struct {
+	Document        references.FPDF_DOCUMENT
+	IncludeFontData bool // Whether to include the font program of the embedded fonts.
+}
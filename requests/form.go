@@ -0,0 +1,41 @@
+package requests
+
+import "github.com/klippa-app/go-pdfium/references"
+
+type GetFormFields struct {
+	Document references.FPDF_DOCUMENT
+}
+
+type FillFormFieldsValue struct {
+	Text        *string  // The text of a text field or of an editable combobox.
+	Checked     *bool    // Whether a checkbox should be checked. Without export value, false unchecks a radio button group.
+	ExportValue *string  // The export value of the radio button or checkbox widget to check.
+	Selected    []string // The labels of the options to select in a combobox or listbox. Only a listbox that allows multiple selections can have multiple options selected, an empty (non-nil) list deselects all options of a listbox.
+}
+
+type FillFormFields struct {
+	Document references.FPDF_DOCUMENT
+	Values   map[string]FillFormFieldsValue // The values to fill by the fully qualified name of the field.
+	Flatten  bool                           // Whether to flatten the pages after filling, this makes the fields part of the page content.
+	Save     bool                           // Whether to save the document after filling, the document is returned as bytes when no FilePath is given.
+	FilePath *string                        // A path to save the file to.
+}
+
+type FormDataFormat string
+
+const (
+	FormDataFormatFDF  FormDataFormat = "fdf"  // Forms Data Format, the PDF syntax based format.
+	FormDataFormatXFDF FormDataFormat = "xfdf" // XML Forms Data Format.
+)
+
+type ExportFormData struct {
+	Document references.FPDF_DOCUMENT
+	Format   FormDataFormat // The format to export the form data in.
+	FileName string         // The file name of the document to refer to from the form data, optional.
+}
+
+type ImportFormData struct {
+	Document references.FPDF_DOCUMENT
+	Data     []byte         // The FDF or XFDF data.
+	Format   FormDataFormat // The format of the data, the format is detected from the data when not given.
+}
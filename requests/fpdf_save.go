@@ -13,17 +13,47 @@ const (
 	SaveFlagRemoveSecurity SaveFlags = 3 // Remove security.
 )
 
+type SaveEncryptionMethod string
+
+const (
+	SaveEncryptionMethodAES128 SaveEncryptionMethod = "aes-128" // AES with a 128-bit key, requires PDF 1.6.
+	SaveEncryptionMethodAES256 SaveEncryptionMethod = "aes-256" // AES with a 256-bit key, requires PDF 2.0.
+)
+
+type SavePermissions uint32 // The permission flags, as reported by FPDF_GetDocPermissions. Please refer to "TABLE 3.20 User access permissions" in PDF Reference 1.7 P123 for detailed description.
+
+const (
+	SavePermissionPrint            SavePermissions = 1 << 2  // Print the document, in low quality when SavePermissionPrintHighQuality is not given.
+	SavePermissionModify           SavePermissions = 1 << 3  // Modify the contents of the document.
+	SavePermissionCopy             SavePermissions = 1 << 4  // Copy or extract text and graphics.
+	SavePermissionAnnotate         SavePermissions = 1 << 5  // Add or modify annotations and fill in form fields.
+	SavePermissionFillForms        SavePermissions = 1 << 8  // Fill in form fields, even when SavePermissionAnnotate is not given.
+	SavePermissionAccessibility    SavePermissions = 1 << 9  // Extract text and graphics for accessibility.
+	SavePermissionAssemble         SavePermissions = 1 << 10 // Insert, rotate and delete pages, and create bookmarks and thumbnails.
+	SavePermissionPrintHighQuality SavePermissions = 1 << 11 // Print the document in high quality.
+	SavePermissionAll              SavePermissions = SavePermissionPrint | SavePermissionModify | SavePermissionCopy | SavePermissionAnnotate | SavePermissionFillForms | SavePermissionAccessibility | SavePermissionAssemble | SavePermissionPrintHighQuality
+)
+
+type SaveEncryption struct {
+	Method        SaveEncryptionMethod
+	UserPassword  string          // The password to open the document with. When empty, the document opens without a password, but with the given permissions.
+	OwnerPassword string          // The password to open the document with all permissions. When empty, a random owner password is used.
+	Permissions   SavePermissions // What users that open the document with the user password are allowed to do.
+}
+
 type FPDF_SaveAsCopy struct {
 	Flags      SaveFlags // The creating flags.
 	Document   references.FPDF_DOCUMENT
-	FilePath   *string   // A path to save the file to.
-	FileWriter io.Writer // A writer to save the file to.
+	FilePath   *string         // A path to save the file to.
+	FileWriter io.Writer       // A writer to save the file to.
+	Encryption *SaveEncryption // Encrypts the saved file with the given passwords and permissions. Can't be combined with SaveFlagIncremental, the security of the document is replaced.
 }
 
 type FPDF_SaveWithVersion struct {
 	Document    references.FPDF_DOCUMENT
-	Flags       SaveFlags // The creating flags.
-	FileVersion int       // The PDF file version. File version: 14 for 1.4, 15 for 1.5, ...
-	FilePath    *string   // A path to save the file to.
-	FileWriter  io.Writer // A writer to save the file to.
+	Flags       SaveFlags       // The creating flags.
+	FileVersion int             // The PDF file version. File version: 14 for 1.4, 15 for 1.5, ... When encrypting, the version is raised to the version that the encryption method requires.
+	FilePath    *string         // A path to save the file to.
+	FileWriter  io.Writer       // A writer to save the file to.
+	Encryption  *SaveEncryption // Encrypts the saved file with the given passwords and permissions. Can't be combined with SaveFlagIncremental, the security of the document is replaced.
 }
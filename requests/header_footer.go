@@ -0,0 +1,50 @@
+package requests
+
+import (
+	"time"
+
+	"github.com/klippa-app/go-pdfium/references"
+	"github.com/klippa-app/go-pdfium/structs"
+)
+
+type HeaderFooterPosition string // Where to place the text on the page, as displayed (after rotation).
+
+const (
+	HeaderFooterPositionTopLeft      HeaderFooterPosition = "top-left"
+	HeaderFooterPositionTopCenter    HeaderFooterPosition = "top-center"
+	HeaderFooterPositionTopRight     HeaderFooterPosition = "top-right"
+	HeaderFooterPositionBottomLeft   HeaderFooterPosition = "bottom-left"
+	HeaderFooterPositionBottomCenter HeaderFooterPosition = "bottom-center"
+	HeaderFooterPositionBottomRight  HeaderFooterPosition = "bottom-right"
+)
+
+type HeaderFooterDocument struct {
+	Document  references.FPDF_DOCUMENT
+	PageRange *string // The page ranges, such as "1,3,5-7". If it is nil, all pages will get the texts.
+}
+
+type HeaderFooterText struct {
+	// The template of the text. The following placeholders are supported:
+	// {prefix}: the prefix of the request.
+	// {n}: the running number, this continues over all documents, useful for Bates numbering.
+	// {page}: the page number (1-index based) within the document.
+	// {total}: the amount of pages in the document.
+	// {date}: the date of the request.
+	// Numbers can be zero padded by giving a width, like {n:06}, the date
+	// can be formatted with a Go time layout, like {date:02-01-2006}.
+	// Use {{ and }} to get literal braces.
+	Template string
+	Position HeaderFooterPosition // Where to place the text on the page.
+	Font     string               // The name of one of the 14 standard fonts, like Helvetica or Times-Roman. The default is Helvetica.
+	FontSize float32              // The font size in points. The default is 10.
+	Color    *structs.FPDF_COLOR  // The color of the text. The default is black.
+	Margin   float32              // The distance from the text to the edges of the page in points. The default is 36 (half an inch).
+}
+
+type AddHeaderFooter struct {
+	Documents []HeaderFooterDocument // The documents to add the texts to, the running number continues over the documents in the given order.
+	Texts     []HeaderFooterText     // The texts to add to every page.
+	Prefix    string                 // The value of the {prefix} placeholder.
+	Start     int                    // The first value of the running number. The default is 1.
+	Date      time.Time              // The value of the {date} placeholder. The default is the current time.
+}
@@ -6,3 +6,13 @@ type GetMetaData struct {
 	Document references.FPDF_DOCUMENT
 	Tags     *[]string // A list of metadata tags. If nil, it will return: Title, Author, Subject, Keywords, Creator, Producer, CreationDate, ModDate. For detailed explanation of these tags and their respective values, please refer to section 10.2.1 "Document Information Dictionary" in PDF Reference 1.7.
 }
+
+type SetMetaDataTag struct {
+	Tag   string // The metadata tag, like Title, Author, Subject, Keywords, Creator or Producer.
+	Value string // The value of the tag. An empty value removes the tag.
+}
+
+type SetMetaData struct {
+	Document references.FPDF_DOCUMENT
+	Tags     []SetMetaDataTag // The tags to set. Dates (CreationDate and ModDate) should be given in the PDF date format, like D:20230102150405+01'00'.
+}
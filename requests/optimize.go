@@ -0,0 +1,13 @@
+package requests
+
+import "github.com/klippa-app/go-pdfium/references"
+
+type OptimizeDocument struct {
+	Document          references.FPDF_DOCUMENT
+	MaxDPI            float64 // Images with an effective resolution above this DPI are downsampled to this DPI. Defaults to 150.
+	JpegQuality       int     // The quality (1-100) of the downsampled images, which are recompressed as JPEG. Defaults to 75.
+	RemoveThumbnails  bool    // Remove the embedded page thumbnails when saving.
+	RemoveAttachments bool    // Remove all the embedded files of the document, also the ones that links or a portfolio refer to. The files of file attachment annotations are kept.
+	Save              bool    // Whether to save the document after optimizing, the document is returned as bytes when no FilePath is given. Only this save removes the replaced images and the other objects that are not used anymore, other saves keep them.
+	FilePath          *string // A path to save the file to.
+}
@@ -0,0 +1,26 @@
+package requests
+
+import "github.com/klippa-app/go-pdfium/references"
+
+type PageLabelStyle string // The numbering style of a page label range.
+
+const (
+	PageLabelStyleNone         PageLabelStyle = ""              // No numbering, only the prefix is used.
+	PageLabelStyleDecimal      PageLabelStyle = "decimal"       // 1, 2, 3, ...
+	PageLabelStyleUpperRoman   PageLabelStyle = "upper-roman"   // I, II, III, ...
+	PageLabelStyleLowerRoman   PageLabelStyle = "lower-roman"   // i, ii, iii, ...
+	PageLabelStyleUpperLetters PageLabelStyle = "upper-letters" // A to Z, then AA to ZZ, ...
+	PageLabelStyleLowerLetters PageLabelStyle = "lower-letters" // a to z, then aa to zz, ...
+)
+
+type SetPageLabelsRange struct {
+	PageIndex int            // The page index (0-index based) where the range starts, the range continues until the next range or the end of the document.
+	Style     PageLabelStyle // The numbering style of the range.
+	Prefix    string         // The prefix of the label, for example "A-".
+	Start     int            // The number of the first page in the range, defaults to 1.
+}
+
+type SetPageLabels struct {
+	Document references.FPDF_DOCUMENT
+	Ranges   []SetPageLabelsRange // The page label ranges, ordered by page index. The first range must start at page index 0. When empty, the page labels of the document are removed.
+}
@@ -0,0 +1,20 @@
+package requests
+
+import "github.com/klippa-app/go-pdfium/references"
+
+type PreflightProfile string
+
+const (
+	PreflightProfilePDFA1A PreflightProfile = "pdf/a-1a" // PDF/A-1 level A, which requires a tagged document.
+	PreflightProfilePDFA1B PreflightProfile = "pdf/a-1b"
+	PreflightProfilePDFA2A PreflightProfile = "pdf/a-2a" // PDF/A-2 level A, which requires a tagged document.
+	PreflightProfilePDFA2B PreflightProfile = "pdf/a-2b"
+	PreflightProfilePDFA3A PreflightProfile = "pdf/a-3a" // PDF/A-3 level A, which requires a tagged document.
+	PreflightProfilePDFA3B PreflightProfile = "pdf/a-3b"
+	PreflightProfilePrint  PreflightProfile = "print" // Whether the document can be printed reliably.
+)
+
+type Preflight struct {
+	Document references.FPDF_DOCUMENT
+	Profiles []PreflightProfile // The profiles to check the document against. When none are given, all profiles are checked.
+}
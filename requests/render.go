@@ -2,6 +2,7 @@ package requests
 
 import (
 	"github.com/klippa-app/go-pdfium/enums"
+	"github.com/klippa-app/go-pdfium/structs"
 )
 
 type RenderPageInDPI struct {
@@ -13,6 +14,7 @@ type RenderPageInDPI struct {
 type RenderPagesInDPI struct {
 	Pages   []RenderPageInDPI // The pages
 	Padding int               // The amount of padding (in pixels) between the images
+	Layout  RenderPagesLayout // How to lay out the pages in the image, by default the pages are placed below each other.
 }
 
 type RenderPageInPixels struct {
@@ -25,6 +27,33 @@ type RenderPageInPixels struct {
 type RenderPagesInPixels struct {
 	Pages   []RenderPageInPixels // The pages
 	Padding int                  // The amount of padding (in pixels) between the images
+	Layout  RenderPagesLayout    // How to lay out the pages in the image, by default the pages are placed below each other.
+}
+
+type RenderPagesLayoutMode string
+
+const (
+	RenderPagesLayoutModeVertical   RenderPagesLayoutMode = "vertical"   // Place the pages below each other, this is the default.
+	RenderPagesLayoutModeHorizontal RenderPagesLayoutMode = "horizontal" // Place the pages next to each other, in a strip.
+	RenderPagesLayoutModeGrid       RenderPagesLayoutMode = "grid"       // Place the pages in a grid with the given amount of columns, like a contact sheet.
+)
+
+type RenderPagesLayoutLabel string
+
+const (
+	RenderPagesLayoutLabelNone       RenderPagesLayoutLabel = ""            // Don't draw labels, this is the default.
+	RenderPagesLayoutLabelPageNumber RenderPagesLayoutLabel = "page_number" // Draw the page number (1-index based) under every page.
+	RenderPagesLayoutLabelPageLabel  RenderPagesLayoutLabel = "page_label"  // Draw the page label under every page, the page number when the page has no label.
+)
+
+type RenderPagesLayout struct {
+	Mode            RenderPagesLayoutMode  // How to place the pages.
+	Columns         int                    // The amount of columns when Mode is RenderPagesLayoutModeGrid.
+	Label           RenderPagesLayoutLabel // The label to draw under every page.
+	LabelHeight     int                    // The height (in pixels) of the space for the label under every page. The default is 20.
+	BackgroundColor *structs.FPDF_COLOR    // The color of the space around the pages, the default is transparent.
+	MaxWidth        int                    // The maximum width of the image, the pages are scaled down to fit. The padding and labels are not scaled, so it has to be larger than them.
+	MaxHeight       int                    // The maximum height of the image, the pages are scaled down to fit. The padding and labels are not scaled, so it has to be larger than them.
 }
 
 type RenderToFileOutputFormat string // The file format to render output as.
@@ -53,3 +82,16 @@ type RenderToFile struct {
 	MaxFileSize         int64                    // The maximum file size, when OutputFormat RenderToFileOutputFormatJPG, it will try to lower the quality it until it fits.
 	TargetFilePath      string                   // When OutputTarget is file, the path to write it to, if not given, a temp file is created
 }
+
+type GetPageThumbnail struct {
+	Page           Page
+	MaxWidth       int                      // The maximum width of the thumbnail.
+	MaxHeight      int                      // The maximum height of the thumbnail.
+	RenderFlags    enums.FPDF_RENDER_FLAG   // The flags to render the page with when the embedded thumbnail is not used.
+	OutputFormat   RenderToFileOutputFormat // The format to output the image as
+	OutputTarget   RenderToFileOutputTarget // Where to output the image
+	OutputQuality  int                      // Only used when OutputFormat RenderToFileOutputFormatJPG. Ranges from 1 to 100 inclusive, higher is better. The default is 95.
+	Progressive    bool                     // Only used when OutputFormat RenderToFileOutputFormatJPG and with build tag pdfium_use_turbojpeg. Will render a progressive jpeg.
+	MaxFileSize    int64                    // The maximum file size, when OutputFormat RenderToFileOutputFormatJPG, it will try to lower the quality it until it fits.
+	TargetFilePath string                   // When OutputTarget is file, the path to write it to, if not given, a temp file is created
+}
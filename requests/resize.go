@@ -0,0 +1,21 @@
+package requests
+
+import "github.com/klippa-app/go-pdfium/references"
+
+type ResizePagesMode string // How to scale the page content to the target size.
+
+const (
+	ResizePagesModeFit  ResizePagesMode = "fit"  // Scale the content to fit inside the target size while keeping the aspect ratio, the content is centered on the page (letterboxing).
+	ResizePagesModeFill ResizePagesMode = "fill" // Scale the content to fill the target size while keeping the aspect ratio, content that falls outside the page is clipped.
+)
+
+type ResizePages struct {
+	Document          references.FPDF_DOCUMENT
+	PageRange         *string         // The page ranges, such as "1,3,5-7". If it is nil, all pages will be resized.
+	Width             float32         // The target page width in points, for example 595.28 for A4 or 612 for Letter.
+	Height            float32         // The target page height in points, for example 841.89 for A4 or 792 for Letter.
+	Mode              ResizePagesMode // How to scale the page content to the target size. The default is ResizePagesModeFit.
+	NoUpscale         bool            // Never scale the content up, pages that are smaller than the target size will be centered.
+	NormalizeRotation bool            // Apply the page rotation to the content, so that the page ends up without rotation.
+	MatchOrientation  bool            // Swap the target width and height for pages that have a different orientation (portrait/landscape) than the target size.
+}
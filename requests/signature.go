@@ -0,0 +1,41 @@
+package requests
+
+import (
+	"crypto"
+	"time"
+
+	"github.com/klippa-app/go-pdfium/references"
+	"github.com/klippa-app/go-pdfium/structs"
+)
+
+type VerifySignatures struct {
+	Document            references.FPDF_DOCUMENT
+	File                *[]byte  // The original bytes of the document file. Only needed when the document was not opened from bytes, the signatures are verified over the original file, not over the document as it is now.
+	TrustedCertificates [][]byte // The certificates (DER or PEM) of the roots to trust. The certificates of the system are not used, so when no certificates are given, no signer is trusted.
+}
+
+type SignDocumentAppearance struct {
+	Page      int                   // The page (0-index based) to put the signature on.
+	Rect      structs.FPDF_FS_RECTF // The rect of the signature on the page, in page coordinates.
+	Text      *string               // The text to show. When nil, a text with the name of the signer, the date and the reason is shown. Use an empty string to show no text.
+	Image     []byte                // A JPEG or PNG image to show left of the text, or in the whole rect when there is no text.
+	FontSize  float32               // The font size of the text, 10 when not given.
+	TextColor *structs.FPDF_COLOR   // The color of the text, black when not given.
+}
+
+type SignDocument struct {
+	Document         references.FPDF_DOCUMENT
+	Signer           crypto.Signer           // The private key to sign with. Not supported on multi-threaded usage, use PrivateKey there.
+	PrivateKey       []byte                  // The private key (PKCS#1, PKCS#8 or SEC 1, DER or PEM) to sign with, when no Signer is given.
+	Certificate      []byte                  // The certificate (DER or PEM) of the signer.
+	CertificateChain [][]byte                // The certificates (DER or PEM) of the issuers of the certificate, to include in the signature.
+	FieldName        string                  // The name of the signature field. When empty, a name like Signature1 that is not used yet.
+	Name             string                  // The name of the signer. When empty, the common name of the certificate.
+	Reason           string                  // The reason of the signature.
+	Location         string                  // The location of signing.
+	ContactInfo      string                  // The contact info of the signer.
+	SigningTime      time.Time               // The time of signing, the current time when not given.
+	Appearance       *SignDocumentAppearance // The appearance of the signature. When nil, the signature is invisible and its field is on the first page.
+	ContentsSize     int                     // The amount of bytes to reserve for the signature, 8192 when not given.
+	FilePath         *string                 // A path to save the signed file to, the signed file is returned as bytes when not given.
+}
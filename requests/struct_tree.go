@@ -0,0 +1,8 @@
+package requests
+
+import "github.com/klippa-app/go-pdfium/references"
+
+type GetStructTree struct {
+	Document  references.FPDF_DOCUMENT
+	PageRange *string // The page ranges, such as "1,3,5-7". If it is nil, the structure tree of all pages is returned.
+}
@@ -0,0 +1,49 @@
+package responses
+
+type AccessibilityCheck string
+
+const (
+	AccessibilityCheckTagged          AccessibilityCheck = "tagged"
+	AccessibilityCheckTitle           AccessibilityCheck = "title"
+	AccessibilityCheckLanguage        AccessibilityCheck = "language"
+	AccessibilityCheckUntaggedContent AccessibilityCheck = "untagged_content"
+	AccessibilityCheckFigureAltText   AccessibilityCheck = "figure_alt_text"
+	AccessibilityCheckHeadingOrder    AccessibilityCheck = "heading_order"
+	AccessibilityCheckTableHeaders    AccessibilityCheck = "table_headers"
+)
+
+type AccessibilityStatus string
+
+const (
+	AccessibilityStatusPass    AccessibilityStatus = "pass"
+	AccessibilityStatusWarning AccessibilityStatus = "warning" // The document might not be accessible, this should be checked manually.
+	AccessibilityStatusFail    AccessibilityStatus = "fail"
+)
+
+type AccessibilityInformation struct {
+	IsTagged bool    // Whether the document is tagged.
+	Title    *string // The title of the document from the document information. nil when not set.
+	Language *string // The language (Lang) of the document catalog. nil when not set.
+}
+
+type AccessibilityRule struct {
+	Check   AccessibilityCheck
+	Status  AccessibilityStatus
+	Message string // Why the check didn't pass, empty when it passed.
+}
+
+type AccessibilityIssue struct {
+	Check       AccessibilityCheck
+	Status      AccessibilityStatus
+	Page        *int   // The page number (0-index based) of the issue. nil when the issue is about the document.
+	ElementType string // The structure type of the element of the issue, like Figure or H2. Empty when the issue is not about an element.
+	ElementPath []int  // The indexes of the element and its parents in the structure tree of the page, as returned by GetStructTree. nil when the issue is not about an element.
+	Message     string
+}
+
+type CheckAccessibility struct {
+	Information AccessibilityInformation
+	Passed      bool                 // Whether none of the rules failed, warnings are allowed.
+	Rules       []AccessibilityRule  // The result per check, in the order of the checks.
+	Issues      []AccessibilityIssue // Every issue that was found, the issues about the document first, then the issues of the pages in page order.
+}
@@ -0,0 +1,80 @@
+package responses
+
+import (
+	"github.com/klippa-app/go-pdfium/enums"
+	"github.com/klippa-app/go-pdfium/structs"
+)
+
+type AnnotationLine struct {
+	Start structs.FPDF_FS_POINTF
+	End   structs.FPDF_FS_POINTF
+}
+
+type AnnotationFile struct {
+	Name    string
+	Content []byte
+}
+
+type Annotation struct {
+	Index            int // The index of the annotation in the page.
+	Subtype          enums.FPDF_ANNOTATION_SUBTYPE
+	Rect             structs.FPDF_FS_RECTF
+	Name             string              // The unique name (NM) of the annotation.
+	Contents         string              // The text of the annotation.
+	Author           string              // The author (T) of the annotation.
+	ModificationDate string              // The modification date (M) of the annotation, as PDF date string.
+	Color            *structs.FPDF_COLOR // The color (C) of the annotation, the alpha channel is the opacity. PDFium doesn't return the color of annotations with an appearance stream.
+	InteriorColor    *structs.FPDF_COLOR // The interior color (IC) of the annotation. PDFium doesn't return the color of annotations with an appearance stream.
+	Flags            enums.FPDF_ANNOT_FLAG
+	QuadPoints       []structs.FPDF_FS_QUADPOINTSF // The quad points of text markup and link annotations.
+	InkPaths         [][]structs.FPDF_FS_POINTF    // The paths of ink annotations.
+	Vertices         []structs.FPDF_FS_POINTF      // The vertices of polygon and polyline annotations.
+	Line             *AnnotationLine               // The line of line annotations.
+	Popup            *int                          // The index of the popup annotation of this annotation in the page.
+	Parent           *int                          // The index of the annotation that this popup annotation belongs to in the page.
+	InReplyTo        *int                          // The index of the annotation that this annotation replies to in the page.
+	File             *AnnotationFile               // The attached file of file attachment annotations.
+}
+
+type AnnotationsPage struct {
+	Page        int
+	Annotations []Annotation
+}
+
+type GetAnnotations struct {
+	Pages []AnnotationsPage
+}
+
+type ExportAnnotations struct {
+	Data []byte // The annotations as XFDF.
+}
+
+type ImportAnnotationsSkipped struct {
+	Page    int
+	Name    string
+	Subtype enums.FPDF_ANNOTATION_SUBTYPE
+	Reason  string
+}
+
+type ImportAnnotations struct {
+	FileName string                     // The file name of the document that the XFDF refers to, if given.
+	Imported int                        // The amount of imported annotations.
+	Skipped  []ImportAnnotationsSkipped // The annotations that could not be imported, with the reason.
+}
+
+type TextMarkupAnnotation struct {
+	Page       int                           // The index of the page (0-index based).
+	Index      int                           // The index of the annotation in the page.
+	StartIndex int                           // The index of the first marked character.
+	Count      int                           // The amount of marked characters.
+	Rect       structs.FPDF_FS_RECTF         // The rect of the annotation.
+	QuadPoints []structs.FPDF_FS_QUADPOINTSF // The quad points of the annotation, one per line of text.
+}
+
+type CreateTextMarkupAnnotations struct {
+	Annotations []TextMarkupAnnotation // The created annotations, ranges first, then search hits.
+}
+
+type GenerateAnnotationAppearance struct {
+	Subtype enums.FPDF_ANNOTATION_SUBTYPE // The subtype of the annotation that the appearance was generated for.
+}
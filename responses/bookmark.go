@@ -15,3 +15,5 @@ type GetBookmarksBookmark struct {
 type GetBookmarks struct {
 	Bookmarks []GetBookmarksBookmark
 }
+
+type SetBookmarks struct{}
@@ -0,0 +1,53 @@
+package responses
+
+import (
+	"image"
+)
+
+type CompareDocumentsPageStatus string
+
+const (
+	CompareDocumentsPageStatusIdentical CompareDocumentsPageStatus = "identical" // The page looks the same in both documents.
+	CompareDocumentsPageStatusChanged   CompareDocumentsPageStatus = "changed"   // The page has changed pixels.
+	CompareDocumentsPageStatusAdded     CompareDocumentsPageStatus = "added"     // The page is only in the modified document.
+	CompareDocumentsPageStatusRemoved   CompareDocumentsPageStatus = "removed"   // The page is only in the original document.
+)
+
+type CompareDocumentsPage struct {
+	Status        CompareDocumentsPageStatus
+	OriginalPage  *int        // The page number (0-index based) in the original document, nil when the page was added.
+	ModifiedPage  *int        // The page number (0-index based) in the modified document, nil when the page was removed.
+	ChangedPixels int         // The amount of pixels that are different, pixels outside a page when the page size has changed count as changed.
+	Difference    float64     // The fraction (0-1) of the pixels that are different. 1 for added and removed pages.
+	DiffImage     *image.RGBA // The modified page in light gray with the changed pixels in red. Only set for changed pages when IncludeDiffImages was set.
+}
+
+type CompareDocuments struct {
+	Identical bool                   // Whether all the pages of the documents look the same.
+	Pages     []CompareDocumentsPage // The pages of both documents, in the order of the documents.
+}
+
+type CompareDocumentsTextChangeType string
+
+const (
+	CompareDocumentsTextChangeTypeInsertion CompareDocumentsTextChangeType = "insertion" // The words are only in the modified document.
+	CompareDocumentsTextChangeTypeDeletion  CompareDocumentsTextChangeType = "deletion"  // The words are only in the original document.
+)
+
+type CompareDocumentsTextWord struct {
+	Text          string        // The text of this word.
+	Page          int           // The page number (0-index based) of this word, in the modified document for insertions and in the original document for deletions.
+	PointPosition CharPosition  // The bounding box of this word in points.
+	PixelPosition *CharPosition // The bounding box of this word in pixels from the top left of the rendered page. When DPI was given.
+}
+
+type CompareDocumentsTextChange struct {
+	Type  CompareDocumentsTextChangeType
+	Text  string                     // The text of the words, separated by spaces.
+	Words []CompareDocumentsTextWord // The words that were inserted or deleted.
+}
+
+type CompareDocumentsText struct {
+	Identical bool                         // Whether the words of the documents are the same.
+	Changes   []CompareDocumentsTextChange // The insertions and deletions, in the order of the documents.
+}
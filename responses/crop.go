@@ -0,0 +1,14 @@
+package responses
+
+type CropPagesPage struct {
+	Page    int     // The page number (0-index based).
+	Cropped bool    // Whether the page boxes have been set. Is false when no content could be detected on the page.
+	Left    float32 // The left of the box that has been set, in points.
+	Bottom  float32 // The bottom of the box that has been set, in points.
+	Right   float32 // The right of the box that has been set, in points.
+	Top     float32 // The top of the box that has been set, in points.
+}
+
+type CropPages struct {
+	Pages []CropPagesPage // The result per page.
+}
@@ -0,0 +1,13 @@
+package responses
+
+type FlattenDocumentPage struct {
+	Page   int                    // The page number (0-index based).
+	Result FPDFPage_FlattenResult // The result of the flatten.
+	Kept   int                    // The number of annotations that were kept on the page because they didn't match the mode.
+}
+
+type FlattenDocument struct {
+	Pages     []FlattenDocumentPage // The result per page.
+	FileBytes *[]byte               // The byte array if the document was saved and no path was given.
+	FilePath  *string               // The path the document was saved to.
+}
@@ -0,0 +1,16 @@
+package responses
+
+type Font struct {
+	Name       string            // The family name of the font.
+	BaseName   string            // The PostScript name of the font (BaseFont) as used in the document, empty when it could not be determined.
+	IsEmbedded bool              // Whether the font program is embedded in the document.
+	IsSubset   bool              // Whether the font is a subset, which means only the used glyphs are in the font. Detected by the subset tag (like ABCDEF+) in the base name.
+	Flags      FPDFFont_GetFlags // The descriptor flags of the font.
+	Weight     int               // The weight of the font, typical values are 400 (normal) and 700 (bold).
+	Pages      []int             // The pages (0-index based) that the font is used on.
+	FontData   []byte            // The font program, only given when IncludeFontData was set and the font is embedded.
+}
+
+type GetFonts struct {
+	Fonts []Font
+}
@@ -0,0 +1,57 @@
+package responses
+
+import (
+	"github.com/klippa-app/go-pdfium/enums"
+	"github.com/klippa-app/go-pdfium/structs"
+)
+
+type FormFieldOption struct {
+	Label      string // The label of the option.
+	IsSelected bool   // Whether the option is selected.
+}
+
+type FormFieldWidget struct {
+	Page        int                   // The page (0-index based) that the widget is on.
+	Rect        structs.FPDF_FS_RECTF // The rectangle of the widget on the page, in points.
+	ExportValue string                // The value of the field when this widget is checked. Only for checkboxes and radio buttons.
+	IsChecked   bool                  // Whether the widget is checked. Only for checkboxes and radio buttons.
+}
+
+type FormField struct {
+	Name          string                    // The fully qualified name of the field, like parent.child.
+	AlternateName string                    // The alternate name (TU) of the field, the name to show to the user.
+	Type          enums.FPDF_FORMFIELD_TYPE // The type of the field.
+	Flags         enums.FPDF_FORMFLAG       // The field flags (Ff), like read only and required.
+	Value         string                    // The value of the field.
+	ExportValues  []string                  // The export values of the widgets of the field, the values a checkbox or radio button can have when checked.
+	Options       []FormFieldOption         // The options of a combobox or listbox.
+	Widgets       []FormFieldWidget         // The widgets of the field, a field can be shown on multiple places and pages.
+}
+
+type GetFormFields struct {
+	Fields []FormField
+}
+
+type FillFormFieldsRejectedField struct {
+	Name   string // The fully qualified name of the field.
+	Reason string // Why the value was rejected.
+}
+
+type FillFormFields struct {
+	Filled    []string                      // The names of the fields that were filled.
+	NotFound  []string                      // The names of the fields that are not in the document.
+	Rejected  []FillFormFieldsRejectedField // The fields of which the value was rejected, these keep their previous value.
+	FileBytes *[]byte                       // The byte array if the document was saved and no path was given.
+	FilePath  *string                       // The path the document was saved to.
+}
+
+type ExportFormData struct {
+	Data []byte // The form data in the requested format.
+}
+
+type ImportFormData struct {
+	FileName string                        // The file name of the document that the form data refers to, if given.
+	Filled   []string                      // The names of the fields that were filled.
+	NotFound []string                      // The names of the fields in the form data that don't exist in the document.
+	Rejected []FillFormFieldsRejectedField // The fields of which the value was rejected, with the reason.
+}
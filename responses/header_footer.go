@@ -0,0 +1,12 @@
+package responses
+
+type AddHeaderFooterDocument struct {
+	Pages       int // The amount of pages that got the texts.
+	FirstNumber int // The running number of the first page that got the texts.
+	LastNumber  int // The running number of the last page that got the texts.
+}
+
+type AddHeaderFooter struct {
+	Documents  []AddHeaderFooterDocument // The result per document, in the order of the request.
+	NextNumber int                       // The next running number, can be used as Start to continue numbering in another request.
+}
@@ -8,3 +8,5 @@ type GetMetaDataTag struct {
 type GetMetaData struct {
 	Tags []GetMetaDataTag
 }
+
+type SetMetaData struct{}
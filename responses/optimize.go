@@ -0,0 +1,23 @@
+package responses
+
+type OptimizeDocumentImage struct {
+	Page           int     // The page number (0-index based).
+	Index          int     // The index of the image object on the page.
+	OriginalWidth  int     // The width of the image in pixels before downsampling.
+	OriginalHeight int     // The height of the image in pixels before downsampling.
+	OriginalDPI    float64 // The effective resolution of the image before downsampling, the highest of the horizontal and the vertical resolution.
+	OriginalSize   int     // The size of the image data in bytes before downsampling.
+	Width          int     // The width of the image in pixels after downsampling.
+	Height         int     // The height of the image in pixels after downsampling.
+	Size           int     // The size of the image data in bytes after downsampling.
+}
+
+type OptimizeDocument struct {
+	Images          []OptimizeDocumentImage // The images that have been downsampled. An image that is used on multiple pages is downsampled once, on the first page that it is used on.
+	ImageBytes      int                     // The amount of bytes that were saved by downsampling images.
+	ThumbnailBytes  int                     // The amount of bytes that are saved by removing the thumbnails.
+	AttachmentBytes int                     // The amount of bytes that are saved by removing the embedded files.
+	BytesSaved      int                     // The total amount of bytes that were saved. The size of the removed unused objects is not included, as that is only known when saving.
+	FileBytes       *[]byte                 // The byte array if the document was saved and no path was given.
+	FilePath        *string                 // The path the document was saved to.
+}
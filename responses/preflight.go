@@ -0,0 +1,56 @@
+package responses
+
+import (
+	"github.com/klippa-app/go-pdfium/enums"
+	"github.com/klippa-app/go-pdfium/requests"
+)
+
+type PreflightCheck string
+
+const (
+	PreflightCheckVersion      PreflightCheck = "version"
+	PreflightCheckEncryption   PreflightCheck = "encryption"
+	PreflightCheckJavaScript   PreflightCheck = "javascript"
+	PreflightCheckAttachments  PreflightCheck = "attachments"
+	PreflightCheckFonts        PreflightCheck = "fonts"
+	PreflightCheckTransparency PreflightCheck = "transparency"
+	PreflightCheckTagged       PreflightCheck = "tagged"
+	PreflightCheckForms        PreflightCheck = "forms"
+)
+
+type PreflightStatus string
+
+const (
+	PreflightStatusPass    PreflightStatus = "pass"
+	PreflightStatusWarning PreflightStatus = "warning" // The document might not comply, this can't be determined with PDFium.
+	PreflightStatusFail    PreflightStatus = "fail"
+)
+
+type PreflightInformation struct {
+	FileVersion       int                 // The numeric version of the file: 14 for 1.4, 15 for 1.5, ... 0 when it's unknown, like for new documents.
+	IsEncrypted       bool                // Whether the document is protected by a security handler.
+	CanPrint          bool                // Whether the user permissions of the document allow printing.
+	JavaScriptActions int                 // The amount of document level JavaScript actions.
+	Attachments       []string            // The names of the embedded files.
+	NonEmbeddedFonts  []string            // The names of the fonts that are not embedded.
+	TransparentPages  []int               // The pages (0-index based) that have transparency.
+	IsTagged          bool                // Whether the document is tagged, which is required for accessibility.
+	FormType          enums.FPDF_FORMTYPE // The type of the form of the document.
+}
+
+type PreflightRule struct {
+	Check   PreflightCheck
+	Status  PreflightStatus
+	Message string // Why the check didn't pass, empty when it passed.
+}
+
+type PreflightProfileResult struct {
+	Profile requests.PreflightProfile
+	Passed  bool            // Whether none of the rules of the profile failed, warnings are allowed.
+	Rules   []PreflightRule // The rules of the profile, in the order of the checks.
+}
+
+type Preflight struct {
+	Information PreflightInformation
+	Profiles    []PreflightProfileResult
+}
@@ -21,6 +21,7 @@ type RenderPagesPage struct {
 	X                 int     // The X start position of this page inside the image.
 	Y                 int     // The Y start position of this page inside the image.
 	HasTransparency   bool    // Whether the page has transparency.
+	Label             string  // The label that is drawn under the page, when labels were requested.
 }
 
 type RenderPages struct {
@@ -90,3 +91,11 @@ type RenderToFile struct {
 	Height            int               // The height of the rendered image.
 	PointToPixelRatio float64           // The point to pixel ratio for the rendered image. How many points is 1 pixel in this image. Only set when rendering one page.
 }
+
+type GetPageThumbnail struct {
+	IsEmbedded bool    // Whether the embedded thumbnail of the page was used, otherwise the page was rendered.
+	ImageBytes *[]byte // The byte array of the thumbnail file when OutputTarget is RenderToFileOutputTargetBytes.
+	ImagePath  string  // The file path when OutputTarget is RenderToFileOutputTargetFile, is a tmp path when TargetFilePath was empty in the request.
+	Width      int     // The width of the thumbnail.
+	Height     int     // The height of the thumbnail.
+}
@@ -0,0 +1,15 @@
+package responses
+
+import "github.com/klippa-app/go-pdfium/enums"
+
+type ResizePagesPage struct {
+	Page     int                      // The page number (0-index based).
+	Scale    float64                  // The scale that has been applied to the page content.
+	Width    float32                  // The new width of the page in points, as displayed (after rotation).
+	Height   float32                  // The new height of the page in points, as displayed (after rotation).
+	Rotation enums.FPDF_PAGE_ROTATION // The rotation of the page after resizing.
+}
+
+type ResizePages struct {
+	Pages []ResizePagesPage // The result per page.
+}
@@ -0,0 +1,51 @@
+package responses
+
+import "time"
+
+type VerifySignaturesStatus string // The verdict of a signature.
+
+const (
+	VerifySignaturesStatusValid       VerifySignaturesStatus = "valid"       // The signature is valid and the signer is trusted.
+	VerifySignaturesStatusUntrusted   VerifySignaturesStatus = "untrusted"   // The signature is valid, but the certificate of the signer doesn't chain up to a trusted certificate.
+	VerifySignaturesStatusInvalid     VerifySignaturesStatus = "invalid"     // The signed data was changed, or the signature doesn't match the signer.
+	VerifySignaturesStatusUnsupported VerifySignaturesStatus = "unsupported" // The signature uses a format or an algorithm that can't be verified.
+)
+
+type VerifySignaturesCertificate struct {
+	Subject      string    // The subject of the certificate.
+	Issuer       string    // The issuer of the certificate.
+	SerialNumber string    // The serial number of the certificate, in hex.
+	NotBefore    time.Time // The start of the validity of the certificate.
+	NotAfter     time.Time // The end of the validity of the certificate.
+	Raw          []byte    // The DER of the certificate.
+}
+
+type VerifySignaturesSignature struct {
+	Index                int                           // The index of the signature in the document.
+	SubFilter            *string                       // The encoding of the signature. nil when no sub filter.
+	Reason               *string                       // The reason of the signature. nil when no reason.
+	Time                 *string                       // The time of signing from the signature dictionary, as PDF date string. nil when no time.
+	SigningTime          *time.Time                    // The time of signing from the signature itself, or from its time stamp token. nil when the signature has no time.
+	DocMDPPermission     int                           // The DocMDP permission of the signature, 0 when it has none.
+	ByteRange            []int                         // The byte range of the file that is signed.
+	Status               VerifySignaturesStatus        // The verdict of the signature.
+	Error                string                        // Why the signature is invalid or unsupported, or why the signer is not trusted.
+	DigestAlgorithm      string                        // The digest algorithm of the signature, like SHA-256.
+	DigestValid          bool                          // Whether the digest of the signed data of the file matches the signed digest.
+	SignatureValid       bool                          // Whether the signature of the signer is valid.
+	CertificateTrusted   bool                          // Whether the certificate of the signer chains up to a trusted certificate, at the current time or at the time of a valid time stamp token.
+	ModifiedAfterSigning bool                          // Whether the file was changed after signing, for example with an incremental update. This doesn't invalidate the signature, but the document might not show what was signed.
+	Signer               *VerifySignaturesCertificate  // The certificate of the signer. nil when the signature could not be parsed.
+	Certificates         []VerifySignaturesCertificate // All the certificates included in the signature.
+}
+
+type VerifySignatures struct {
+	Signatures []VerifySignaturesSignature // The result per signature.
+}
+
+type SignDocument struct {
+	FieldName string  // The name of the signature field.
+	ByteRange []int   // The byte range of the file that is signed.
+	FileBytes *[]byte // The signed file, when no path was given.
+	FilePath  *string // The path the signed file was saved to.
+}
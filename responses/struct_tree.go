@@ -0,0 +1,34 @@
+package responses
+
+import "github.com/klippa-app/go-pdfium/enums"
+
+type StructElementAttribute struct {
+	Owner        string                 // The owner (O) of the attribute object that has the attribute, like Layout, List or Table.
+	Name         string                 // The name of the attribute, like Scope or BBox.
+	Type         enums.FPDF_OBJECT_TYPE // The type of the value of the attribute.
+	BooleanValue *bool                  // The value when the attribute is a boolean.
+	NumberValue  *float32               // The value when the attribute is a number.
+	StringValue  *string                // The value when the attribute is a string or a name.
+}
+
+type StructElement struct {
+	Type             string                   // The structure type (S) of the element, like P, H1, Figure or Table.
+	ObjType          string                   // The object type (Type) of the element, normally StructElem.
+	Title            *string                  // The title (T) of the element. nil when not set.
+	AltText          *string                  // The alternate description (Alt) of the element. nil when not set.
+	ActualText       *string                  // The replacement text (ActualText) of the element. nil when not set.
+	Lang             *string                  // The language (Lang) of the element. nil when not set.
+	ID               *string                  // The ID of the element. nil when not set.
+	Attributes       []StructElementAttribute // The attributes (A) of the element.
+	MarkedContentIDs []int                    // The marked content IDs of the content of the element on the page.
+	Children         []StructElement          // The child elements.
+}
+
+type StructTreePage struct {
+	Page     int             // The page number (0-index based).
+	Elements []StructElement // The elements at the top of the structure tree of the page.
+}
+
+type GetStructTree struct {
+	Pages []StructTreePage // The structure tree per page.
+}
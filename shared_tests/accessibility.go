@@ -0,0 +1,28 @@
+package shared_tests
+
+import (
+	"github.com/klippa-app/go-pdfium/requests"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("accessibility", func() {
+	BeforeEach(func() {
+		Locker.Lock()
+	})
+
+	AfterEach(func() {
+		Locker.Unlock()
+	})
+
+	Context("no document", func() {
+		When("is opened", func() {
+			It("returns an error when calling CheckAccessibility", func() {
+				CheckAccessibility, err := PdfiumInstance.CheckAccessibility(&requests.CheckAccessibility{})
+				Expect(err).To(MatchError("document not given"))
+				Expect(CheckAccessibility).To(BeNil())
+			})
+		})
+	})
+})
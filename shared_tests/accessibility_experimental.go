@@ -0,0 +1,290 @@
+//go:build pdfium_experimental
+// +build pdfium_experimental
+
+package shared_tests
+
+import (
+	"encoding/json"
+	"encoding/xml"
+
+	"github.com/klippa-app/go-pdfium/references"
+	"github.com/klippa-app/go-pdfium/requests"
+	"github.com/klippa-app/go-pdfium/responses"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("accessibility_experimental", func() {
+	BeforeEach(func() {
+		Locker.Lock()
+	})
+
+	AfterEach(func() {
+		Locker.Unlock()
+	})
+
+	stringValue := func(value string) *string {
+		return &value
+	}
+
+	intValue := func(value int) *int {
+		return &value
+	}
+
+	Context("an untagged PDF file", func() {
+		var doc references.FPDF_DOCUMENT
+
+		BeforeEach(func() {
+			doc = openDocument("test.pdf")
+		})
+
+		AfterEach(func() {
+			closeDocument(doc)
+		})
+
+		When("CheckAccessibility is called", func() {
+			It("returns that the document and its content are not tagged", func() {
+				CheckAccessibility, err := PdfiumInstance.CheckAccessibility(&requests.CheckAccessibility{
+					Document: doc,
+				})
+				Expect(err).To(BeNil())
+				Expect(CheckAccessibility).To(Equal(&responses.CheckAccessibility{
+					Information: responses.AccessibilityInformation{
+						IsTagged: false,
+					},
+					Passed: false,
+					Rules: []responses.AccessibilityRule{
+						{Check: responses.AccessibilityCheckTagged, Status: responses.AccessibilityStatusFail, Message: "the document is not tagged"},
+						{Check: responses.AccessibilityCheckTitle, Status: responses.AccessibilityStatusFail, Message: "the document doesn't have a title"},
+						{Check: responses.AccessibilityCheckLanguage, Status: responses.AccessibilityStatusFail, Message: "the document doesn't have a language"},
+						{Check: responses.AccessibilityCheckUntaggedContent, Status: responses.AccessibilityStatusFail, Message: "the content on pages 1 is not all tagged or marked as an artifact"},
+					},
+					Issues: []responses.AccessibilityIssue{
+						{Check: responses.AccessibilityCheckTagged, Status: responses.AccessibilityStatusFail, Message: "the document is not tagged"},
+						{Check: responses.AccessibilityCheckTitle, Status: responses.AccessibilityStatusFail, Message: "the document doesn't have a title"},
+						{Check: responses.AccessibilityCheckLanguage, Status: responses.AccessibilityStatusFail, Message: "the document doesn't have a language"},
+						{Check: responses.AccessibilityCheckUntaggedContent, Status: responses.AccessibilityStatusFail, Page: intValue(0), Message: "content objects that are not tagged or marked as an artifact: 4"},
+					},
+				}))
+			})
+		})
+	})
+
+	Context("a tagged PDF file with alt text", func() {
+		var doc references.FPDF_DOCUMENT
+
+		BeforeEach(func() {
+			doc = openDocument("tagged_alt_text.pdf")
+		})
+
+		AfterEach(func() {
+			closeDocument(doc)
+		})
+
+		When("CheckAccessibility is called", func() {
+			It("returns that only the title is missing", func() {
+				CheckAccessibility, err := PdfiumInstance.CheckAccessibility(&requests.CheckAccessibility{
+					Document: doc,
+				})
+				Expect(err).To(BeNil())
+				Expect(CheckAccessibility).To(Equal(&responses.CheckAccessibility{
+					Information: responses.AccessibilityInformation{
+						IsTagged: true,
+						Language: stringValue("en-US"),
+					},
+					Passed: false,
+					Rules: []responses.AccessibilityRule{
+						{Check: responses.AccessibilityCheckTagged, Status: responses.AccessibilityStatusPass},
+						{Check: responses.AccessibilityCheckTitle, Status: responses.AccessibilityStatusFail, Message: "the document doesn't have a title"},
+						{Check: responses.AccessibilityCheckLanguage, Status: responses.AccessibilityStatusPass},
+						{Check: responses.AccessibilityCheckUntaggedContent, Status: responses.AccessibilityStatusPass},
+						{Check: responses.AccessibilityCheckFigureAltText, Status: responses.AccessibilityStatusPass},
+						{Check: responses.AccessibilityCheckHeadingOrder, Status: responses.AccessibilityStatusPass},
+						{Check: responses.AccessibilityCheckTableHeaders, Status: responses.AccessibilityStatusPass},
+					},
+					Issues: []responses.AccessibilityIssue{
+						{Check: responses.AccessibilityCheckTitle, Status: responses.AccessibilityStatusFail, Message: "the document doesn't have a title"},
+					},
+				}))
+			})
+		})
+	})
+
+	Context("a tagged PDF file with alt text that is loaded from a path", func() {
+		var doc references.FPDF_DOCUMENT
+
+		BeforeEach(func() {
+			filePath := TestDataPath + "/testdata/tagged_alt_text.pdf"
+			newDoc, err := PdfiumInstance.FPDF_LoadDocument(&requests.FPDF_LoadDocument{
+				Path: &filePath,
+			})
+			Expect(err).To(BeNil())
+
+			doc = newDoc.Document
+		})
+
+		AfterEach(func() {
+			closeDocument(doc)
+		})
+
+		When("CheckAccessibility is called", func() {
+			It("returns the language of the document", func() {
+				CheckAccessibility, err := PdfiumInstance.CheckAccessibility(&requests.CheckAccessibility{
+					Document: doc,
+				})
+				Expect(err).To(BeNil())
+				Expect(CheckAccessibility.Information.Language).To(Equal(stringValue("en-US")))
+			})
+		})
+	})
+
+	Context("a tagged PDF file with actual text on a figure", func() {
+		var doc references.FPDF_DOCUMENT
+
+		BeforeEach(func() {
+			doc = openDocument("tagged_actual_text.pdf")
+		})
+
+		AfterEach(func() {
+			closeDocument(doc)
+		})
+
+		When("CheckAccessibility is called", func() {
+			It("accepts the actual text as alternative for the figure", func() {
+				CheckAccessibility, err := PdfiumInstance.CheckAccessibility(&requests.CheckAccessibility{
+					Document: doc,
+				})
+				Expect(err).To(BeNil())
+				Expect(CheckAccessibility.Rules).To(ContainElement(responses.AccessibilityRule{Check: responses.AccessibilityCheckFigureAltText, Status: responses.AccessibilityStatusPass}))
+			})
+		})
+	})
+
+	Context("a tagged PDF file with a table", func() {
+		var doc references.FPDF_DOCUMENT
+
+		BeforeEach(func() {
+			doc = openDocument("tagged_table.pdf")
+		})
+
+		AfterEach(func() {
+			closeDocument(doc)
+		})
+
+		When("CheckAccessibility is called", func() {
+			It("returns that the table has header cells and that the marked content is not in the structure tree", func() {
+				CheckAccessibility, err := PdfiumInstance.CheckAccessibility(&requests.CheckAccessibility{
+					Document: doc,
+				})
+				Expect(err).To(BeNil())
+				Expect(CheckAccessibility.Rules).To(ContainElement(responses.AccessibilityRule{Check: responses.AccessibilityCheckTableHeaders, Status: responses.AccessibilityStatusPass}))
+				Expect(CheckAccessibility.Issues).To(ContainElement(responses.AccessibilityIssue{
+					Check:   responses.AccessibilityCheckUntaggedContent,
+					Status:  responses.AccessibilityStatusFail,
+					Page:    intValue(0),
+					Message: "content objects that are not tagged or marked as an artifact: 1",
+				}))
+			})
+		})
+	})
+
+	Context("a tagged PDF file with accessibility issues", func() {
+		var doc references.FPDF_DOCUMENT
+
+		BeforeEach(func() {
+			doc = openDocument("tagged_accessibility_issues.pdf")
+		})
+
+		AfterEach(func() {
+			closeDocument(doc)
+		})
+
+		expectedReport := &responses.CheckAccessibility{
+			Information: responses.AccessibilityInformation{
+				IsTagged: true,
+				Title:    stringValue("Accessibility issues"),
+			},
+			Passed: false,
+			Rules: []responses.AccessibilityRule{
+				{Check: responses.AccessibilityCheckTagged, Status: responses.AccessibilityStatusPass},
+				{Check: responses.AccessibilityCheckTitle, Status: responses.AccessibilityStatusPass},
+				{Check: responses.AccessibilityCheckLanguage, Status: responses.AccessibilityStatusWarning, Message: "the document doesn't have a language, only structure elements have a language"},
+				{Check: responses.AccessibilityCheckUntaggedContent, Status: responses.AccessibilityStatusFail, Message: "the content on pages 1 is not all tagged or marked as an artifact"},
+				{Check: responses.AccessibilityCheckFigureAltText, Status: responses.AccessibilityStatusFail, Message: "figures on pages 1 don't have alternate text"},
+				{Check: responses.AccessibilityCheckHeadingOrder, Status: responses.AccessibilityStatusFail, Message: "headings on pages 1 skip a level"},
+				{Check: responses.AccessibilityCheckTableHeaders, Status: responses.AccessibilityStatusFail, Message: "tables on pages 2 don't have header cells"},
+			},
+			Issues: []responses.AccessibilityIssue{
+				{Check: responses.AccessibilityCheckLanguage, Status: responses.AccessibilityStatusWarning, Message: "the document doesn't have a language, only structure elements have a language"},
+				{Check: responses.AccessibilityCheckHeadingOrder, Status: responses.AccessibilityStatusFail, Page: intValue(0), ElementType: "H3", ElementPath: []int{0, 1}, Message: "heading H3 follows heading H1, which skips a level"},
+				{Check: responses.AccessibilityCheckFigureAltText, Status: responses.AccessibilityStatusFail, Page: intValue(0), ElementType: "Figure", ElementPath: []int{0, 2}, Message: "the figure doesn't have alternate text"},
+				{Check: responses.AccessibilityCheckUntaggedContent, Status: responses.AccessibilityStatusFail, Page: intValue(0), Message: "content objects that are not tagged or marked as an artifact: 1"},
+				{Check: responses.AccessibilityCheckTableHeaders, Status: responses.AccessibilityStatusFail, Page: intValue(1), ElementType: "Table", ElementPath: []int{0, 0}, Message: "the table doesn't have header cells"},
+			},
+		}
+
+		When("CheckAccessibility is called", func() {
+			It("returns the issues of the document and its pages", func() {
+				CheckAccessibility, err := PdfiumInstance.CheckAccessibility(&requests.CheckAccessibility{
+					Document: doc,
+				})
+				Expect(err).To(BeNil())
+				Expect(CheckAccessibility).To(Equal(expectedReport))
+			})
+
+			It("returns the issues of the given pages", func() {
+				pageRange := "2"
+				CheckAccessibility, err := PdfiumInstance.CheckAccessibility(&requests.CheckAccessibility{
+					Document:  doc,
+					PageRange: &pageRange,
+				})
+				Expect(err).To(BeNil())
+				Expect(CheckAccessibility.Passed).To(BeFalse())
+				Expect(CheckAccessibility.Rules).To(ContainElements(
+					responses.AccessibilityRule{Check: responses.AccessibilityCheckUntaggedContent, Status: responses.AccessibilityStatusPass},
+					responses.AccessibilityRule{Check: responses.AccessibilityCheckFigureAltText, Status: responses.AccessibilityStatusPass},
+					responses.AccessibilityRule{Check: responses.AccessibilityCheckHeadingOrder, Status: responses.AccessibilityStatusPass},
+				))
+				Expect(CheckAccessibility.Issues).To(Equal([]responses.AccessibilityIssue{
+					{Check: responses.AccessibilityCheckLanguage, Status: responses.AccessibilityStatusWarning, Message: "the document doesn't have a language, only structure elements have a language"},
+					{Check: responses.AccessibilityCheckTableHeaders, Status: responses.AccessibilityStatusFail, Page: intValue(1), ElementType: "Table", ElementPath: []int{0, 0}, Message: "the table doesn't have header cells"},
+				}))
+			})
+
+			It("returns an error when the page range is invalid", func() {
+				pageRange := "3"
+				CheckAccessibility, err := PdfiumInstance.CheckAccessibility(&requests.CheckAccessibility{
+					Document:  doc,
+					PageRange: &pageRange,
+				})
+				Expect(err).To(MatchError("page 3 is out of range, document has 2 pages"))
+				Expect(CheckAccessibility).To(BeNil())
+			})
+
+			It("returns a report that can be serialized to JSON and XML", func() {
+				CheckAccessibility, err := PdfiumInstance.CheckAccessibility(&requests.CheckAccessibility{
+					Document: doc,
+				})
+				Expect(err).To(BeNil())
+
+				jsonData, err := json.Marshal(CheckAccessibility)
+				Expect(err).To(BeNil())
+				Expect(string(jsonData)).To(ContainSubstring(`{"Check":"figure_alt_text","Status":"fail","Page":0,"ElementType":"Figure","ElementPath":[0,2],"Message":"the figure doesn't have alternate text"}`))
+
+				fromJSON := &responses.CheckAccessibility{}
+				Expect(json.Unmarshal(jsonData, fromJSON)).To(BeNil())
+				Expect(fromJSON).To(Equal(expectedReport))
+
+				xmlData, err := xml.Marshal(CheckAccessibility)
+				Expect(err).To(BeNil())
+
+				fromXML := &responses.CheckAccessibility{}
+				Expect(xml.Unmarshal(xmlData, fromXML)).To(BeNil())
+				Expect(fromXML.Rules).To(Equal(expectedReport.Rules))
+				Expect(fromXML.Issues).To(HaveLen(5))
+				Expect(fromXML.Issues[2].ElementPath).To(Equal([]int{0, 2}))
+			})
+		})
+	})
+})
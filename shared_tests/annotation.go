@@ -0,0 +1,52 @@
+package shared_tests
+
+import (
+	"github.com/klippa-app/go-pdfium/requests"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("annotation", func() {
+	BeforeEach(func() {
+		Locker.Lock()
+	})
+
+	AfterEach(func() {
+		Locker.Unlock()
+	})
+
+	Context("no document", func() {
+		When("is opened", func() {
+			It("returns an error when calling GetAnnotations", func() {
+				GetAnnotations, err := PdfiumInstance.GetAnnotations(&requests.GetAnnotations{})
+				Expect(err).To(MatchError("document not given"))
+				Expect(GetAnnotations).To(BeNil())
+			})
+
+			It("returns an error when calling ExportAnnotations", func() {
+				ExportAnnotations, err := PdfiumInstance.ExportAnnotations(&requests.ExportAnnotations{})
+				Expect(err).To(MatchError("document not given"))
+				Expect(ExportAnnotations).To(BeNil())
+			})
+
+			It("returns an error when calling ImportAnnotations", func() {
+				ImportAnnotations, err := PdfiumInstance.ImportAnnotations(&requests.ImportAnnotations{})
+				Expect(err).To(MatchError("document not given"))
+				Expect(ImportAnnotations).To(BeNil())
+			})
+
+			It("returns an error when calling CreateTextMarkupAnnotations", func() {
+				CreateTextMarkupAnnotations, err := PdfiumInstance.CreateTextMarkupAnnotations(&requests.CreateTextMarkupAnnotations{})
+				Expect(err).To(MatchError("document not given"))
+				Expect(CreateTextMarkupAnnotations).To(BeNil())
+			})
+
+			It("returns an error when calling GenerateAnnotationAppearance", func() {
+				GenerateAnnotationAppearance, err := PdfiumInstance.GenerateAnnotationAppearance(&requests.GenerateAnnotationAppearance{})
+				Expect(err).To(MatchError("document not given"))
+				Expect(GenerateAnnotationAppearance).To(BeNil())
+			})
+		})
+	})
+})
@@ -0,0 +1,712 @@
+//go:build pdfium_experimental
+// +build pdfium_experimental
+
+package shared_tests
+
+import (
+	"github.com/klippa-app/go-pdfium/enums"
+	"github.com/klippa-app/go-pdfium/references"
+	"github.com/klippa-app/go-pdfium/requests"
+	"github.com/klippa-app/go-pdfium/responses"
+	"github.com/klippa-app/go-pdfium/structs"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("annotation_experimental", func() {
+	BeforeEach(func() {
+		Locker.Lock()
+	})
+
+	AfterEach(func() {
+		Locker.Unlock()
+	})
+
+	intPointer := func(i int) *int {
+		return &i
+	}
+
+	Context("a PDF file without annotations", func() {
+		var doc references.FPDF_DOCUMENT
+
+		BeforeEach(func() {
+			doc = openDocument("test.pdf")
+		})
+
+		AfterEach(func() {
+			closeDocument(doc)
+		})
+
+		When("GetAnnotations is called", func() {
+			It("returns no annotations", func() {
+				GetAnnotations, err := PdfiumInstance.GetAnnotations(&requests.GetAnnotations{
+					Document: doc,
+				})
+				Expect(err).To(BeNil())
+				Expect(GetAnnotations).To(Equal(&responses.GetAnnotations{
+					Pages: []responses.AnnotationsPage{
+						{
+							Page:        0,
+							Annotations: []responses.Annotation{},
+						},
+					},
+				}))
+			})
+		})
+
+		When("ExportAnnotations is called", func() {
+			It("returns an empty XFDF document", func() {
+				ExportAnnotations, err := PdfiumInstance.ExportAnnotations(&requests.ExportAnnotations{
+					Document: doc,
+					FileName: "test.pdf",
+				})
+				Expect(err).To(BeNil())
+				Expect(ExportAnnotations).To(Not(BeNil()))
+				Expect(string(ExportAnnotations.Data)).To(Equal(`<?xml version="1.0" encoding="UTF-8"?>
+<xfdf xmlns="http://ns.adobe.com/xfdf/" xml:space="preserve">
+  <f href="test.pdf"></f>
+  <fields></fields>
+</xfdf>
+`))
+			})
+		})
+
+		When("ImportAnnotations is called", func() {
+			It("returns an error for invalid XFDF", func() {
+				ImportAnnotations, err := PdfiumInstance.ImportAnnotations(&requests.ImportAnnotations{
+					Document: doc,
+					Data:     []byte("not xfdf"),
+				})
+				Expect(err).To(Not(BeNil()))
+				Expect(ImportAnnotations).To(BeNil())
+			})
+
+			It("imports the annotations and skips the ones that can't be imported", func() {
+				ImportAnnotations, err := PdfiumInstance.ImportAnnotations(&requests.ImportAnnotations{
+					Document: doc,
+					Data: []byte(`<?xml version="1.0" encoding="UTF-8"?>
+<xfdf xmlns="http://ns.adobe.com/xfdf/" xml:space="preserve">
+  <f href="annots.pdf"/>
+  <annots>
+    <highlight page="0" rect="149,476,191,487" name="Highlight-1" title="Someone" color="#FFE500" flags="print" coords="149,487,191,487,149,476,191,476">
+      <contents>Text Note</contents>
+    </highlight>
+    <line page="0" rect="293,530,349,542" name="Line-1" start="159,296" end="472,243.42"/>
+    <square page="1" rect="50,100,60,120" name="Square-1"/>
+  </annots>
+</xfdf>`),
+				})
+				Expect(err).To(BeNil())
+				Expect(ImportAnnotations).To(Equal(&responses.ImportAnnotations{
+					FileName: "annots.pdf",
+					Imported: 1,
+					Skipped: []responses.ImportAnnotationsSkipped{
+						{
+							Page:    0,
+							Name:    "Line-1",
+							Subtype: enums.FPDF_ANNOT_SUBTYPE_LINE,
+							Reason:  "PDFium can't create annotations of this subtype",
+						},
+						{
+							Page:    1,
+							Name:    "Square-1",
+							Subtype: enums.FPDF_ANNOT_SUBTYPE_SQUARE,
+							Reason:  "the page doesn't exist",
+						},
+					},
+				}))
+
+				GetAnnotations, err := PdfiumInstance.GetAnnotations(&requests.GetAnnotations{
+					Document: doc,
+				})
+				Expect(err).To(BeNil())
+				Expect(GetAnnotations).To(Not(BeNil()))
+				Expect(GetAnnotations.Pages).To(HaveLen(1))
+				Expect(GetAnnotations.Pages[0].Annotations).To(HaveLen(1))
+
+				annotation := GetAnnotations.Pages[0].Annotations[0]
+				Expect(annotation.Subtype).To(Equal(enums.FPDF_ANNOT_SUBTYPE_HIGHLIGHT))
+				Expect(annotation.Name).To(Equal("Highlight-1"))
+				Expect(annotation.Author).To(Equal("Someone"))
+				Expect(annotation.Contents).To(Equal("Text Note"))
+				Expect(annotation.Flags).To(Equal(enums.FPDF_ANNOT_FLAG_PRINT))
+				Expect(annotation.Color).To(Equal(&structs.FPDF_COLOR{R: 255, G: 229, B: 0, A: 255}))
+				Expect(annotation.Rect).To(Equal(structs.FPDF_FS_RECTF{Left: 149, Top: 487, Right: 191, Bottom: 476}))
+				Expect(annotation.QuadPoints).To(Equal([]structs.FPDF_FS_QUADPOINTSF{
+					{X1: 149, Y1: 487, X2: 191, Y2: 487, X3: 149, Y3: 476, X4: 191, Y4: 476},
+				}))
+			})
+		})
+	})
+
+	Context("a PDF file with annotations", func() {
+		var doc references.FPDF_DOCUMENT
+
+		BeforeEach(func() {
+			doc = openDocument("annots.pdf")
+		})
+
+		AfterEach(func() {
+			closeDocument(doc)
+		})
+
+		When("GetAnnotations is called", func() {
+			It("returns the annotations of every page", func() {
+				GetAnnotations, err := PdfiumInstance.GetAnnotations(&requests.GetAnnotations{
+					Document: doc,
+				})
+				Expect(err).To(BeNil())
+				Expect(GetAnnotations).To(Not(BeNil()))
+				Expect(GetAnnotations.Pages).To(HaveLen(2))
+				Expect(GetAnnotations.Pages[0].Annotations).To(HaveLen(9))
+				Expect(GetAnnotations.Pages[1].Annotations).To(HaveLen(3))
+
+				Expect(GetAnnotations.Pages[0].Annotations[4]).To(Equal(responses.Annotation{
+					Index:   4,
+					Subtype: enums.FPDF_ANNOT_SUBTYPE_HIGHLIGHT,
+					Rect:    structs.FPDF_FS_RECTF{Left: 293, Top: 542, Right: 349, Bottom: 530},
+					Name:    "Highlight-1",
+					Flags:   enums.FPDF_ANNOT_FLAG_PRINT,
+					QuadPoints: []structs.FPDF_FS_QUADPOINTSF{
+						{X1: 293, Y1: 542, X2: 349, Y2: 542, X3: 293, Y3: 530, X4: 349, Y4: 530},
+					},
+				}))
+
+				Expect(GetAnnotations.Pages[1].Annotations[2].Subtype).To(Equal(enums.FPDF_ANNOT_SUBTYPE_SQUARE))
+				Expect(GetAnnotations.Pages[1].Annotations[2].Color).To(Equal(&structs.FPDF_COLOR{R: 255, G: 0, B: 0, A: 255}))
+			})
+
+			It("returns the links between annotations and their popups", func() {
+				GetAnnotations, err := PdfiumInstance.GetAnnotations(&requests.GetAnnotations{
+					Document: doc,
+				})
+				Expect(err).To(BeNil())
+				Expect(GetAnnotations).To(Not(BeNil()))
+
+				highlight := GetAnnotations.Pages[0].Annotations[7]
+				Expect(highlight.Name).To(Equal("Highlight-With-Popup-1"))
+				Expect(highlight.Contents).To(Equal("Text Note"))
+				Expect(highlight.Popup).To(Equal(intPointer(6)))
+
+				popup := GetAnnotations.Pages[0].Annotations[6]
+				Expect(popup.Subtype).To(Equal(enums.FPDF_ANNOT_SUBTYPE_POPUP))
+				Expect(popup.Parent).To(Equal(intPointer(7)))
+			})
+		})
+
+		When("ExportAnnotations is called", func() {
+			It("exports the markup annotations", func() {
+				ExportAnnotations, err := PdfiumInstance.ExportAnnotations(&requests.ExportAnnotations{
+					Document: doc,
+					FileName: "annots.pdf",
+				})
+				Expect(err).To(BeNil())
+				Expect(ExportAnnotations).To(Not(BeNil()))
+				Expect(string(ExportAnnotations.Data)).To(Equal(`<?xml version="1.0" encoding="UTF-8"?>
+<xfdf xmlns="http://ns.adobe.com/xfdf/" xml:space="preserve">
+  <f href="annots.pdf"></f>
+  <annots>
+    <highlight page="0" rect="293,530,349,542" name="Highlight-1" flags="print" coords="293,542,349,542,293,530,349,530"></highlight>
+    <highlight page="0" rect="83,440,178,453" name="Highlight-2" flags="print" coords="83,453,178,453,83,440,178,440"></highlight>
+    <highlight page="0" rect="149,476,191,487" name="Highlight-With-Popup-1" flags="print" coords="149,487,191,487,149,476,191,476">
+      <contents>Text Note</contents>
+      <popup rect="191,377,443,488"></popup>
+    </highlight>
+    <square page="1" rect="50,100,60,120" name="page-1-annotation-2" color="#FF0000" interior-color="#000000" flags="print"></square>
+  </annots>
+</xfdf>
+`))
+			})
+		})
+	})
+
+	Context("a PDF file with ink annotations", func() {
+		var doc references.FPDF_DOCUMENT
+
+		BeforeEach(func() {
+			doc = openDocument("ink_annot.pdf")
+		})
+
+		AfterEach(func() {
+			closeDocument(doc)
+		})
+
+		When("GetAnnotations is called", func() {
+			It("returns the ink paths", func() {
+				GetAnnotations, err := PdfiumInstance.GetAnnotations(&requests.GetAnnotations{
+					Document: doc,
+				})
+				Expect(err).To(BeNil())
+				Expect(GetAnnotations).To(Not(BeNil()))
+				Expect(GetAnnotations.Pages[0].Annotations).To(HaveLen(2))
+				Expect(GetAnnotations.Pages[0].Annotations[0].Subtype).To(Equal(enums.FPDF_ANNOT_SUBTYPE_INK))
+				Expect(GetAnnotations.Pages[0].Annotations[0].Color).To(Equal(&structs.FPDF_COLOR{R: 255, G: 229, B: 0, A: 255}))
+				Expect(GetAnnotations.Pages[0].Annotations[0].InkPaths).To(Equal([][]structs.FPDF_FS_POINTF{
+					{{X: 159, Y: 296}, {X: 350, Y: 411}, {X: 472, Y: 243.42}},
+				}))
+			})
+		})
+
+		When("the annotations are exported and imported into another document", func() {
+			It("keeps the ink paths", func() {
+				ExportAnnotations, err := PdfiumInstance.ExportAnnotations(&requests.ExportAnnotations{
+					Document: doc,
+				})
+				Expect(err).To(BeNil())
+				Expect(ExportAnnotations).To(Not(BeNil()))
+
+				otherDoc := openDocument("test.pdf")
+				defer closeDocument(otherDoc)
+
+				ImportAnnotations, err := PdfiumInstance.ImportAnnotations(&requests.ImportAnnotations{
+					Document: otherDoc,
+					Data:     ExportAnnotations.Data,
+				})
+				Expect(err).To(BeNil())
+				Expect(ImportAnnotations).To(Equal(&responses.ImportAnnotations{
+					Imported: 2,
+					Skipped:  []responses.ImportAnnotationsSkipped{},
+				}))
+
+				GetAnnotations, err := PdfiumInstance.GetAnnotations(&requests.GetAnnotations{
+					Document: otherDoc,
+				})
+				Expect(err).To(BeNil())
+				Expect(GetAnnotations).To(Not(BeNil()))
+				Expect(GetAnnotations.Pages[0].Annotations).To(HaveLen(2))
+				Expect(GetAnnotations.Pages[0].Annotations[1].Name).To(Equal("Ink-2"))
+				Expect(GetAnnotations.Pages[0].Annotations[1].InkPaths).To(Equal([][]structs.FPDF_FS_POINTF{
+					{{X: 259, Y: 396}, {X: 450, Y: 511}, {X: 572, Y: 343}},
+				}))
+			})
+		})
+	})
+
+	Context("a PDF file with a file attachment annotation", func() {
+		var doc references.FPDF_DOCUMENT
+
+		BeforeEach(func() {
+			doc = openDocument("annotation_fileattachment.pdf")
+		})
+
+		AfterEach(func() {
+			closeDocument(doc)
+		})
+
+		When("GetAnnotations is called", func() {
+			It("returns the attached file", func() {
+				GetAnnotations, err := PdfiumInstance.GetAnnotations(&requests.GetAnnotations{
+					Document: doc,
+				})
+				Expect(err).To(BeNil())
+				Expect(GetAnnotations).To(Not(BeNil()))
+				Expect(GetAnnotations.Pages[0].Annotations).To(HaveLen(1))
+
+				annotation := GetAnnotations.Pages[0].Annotations[0]
+				Expect(annotation.Subtype).To(Equal(enums.FPDF_ANNOT_SUBTYPE_FILEATTACHMENT))
+				Expect(annotation.Author).To(Equal("Someone"))
+				Expect(annotation.Contents).To(Equal("Test file"))
+				Expect(annotation.ModificationDate).To(Equal("D:20240213082026+03'00'"))
+				Expect(annotation.File).To(Equal(&responses.AnnotationFile{
+					Name:    "test.txt",
+					Content: []byte("test text"),
+				}))
+			})
+		})
+
+		When("ExportAnnotations is called", func() {
+			It("exports the attached file", func() {
+				ExportAnnotations, err := PdfiumInstance.ExportAnnotations(&requests.ExportAnnotations{
+					Document: doc,
+				})
+				Expect(err).To(BeNil())
+				Expect(ExportAnnotations).To(Not(BeNil()))
+				Expect(string(ExportAnnotations.Data)).To(ContainSubstring(`file="test.txt"`))
+				Expect(string(ExportAnnotations.Data)).To(ContainSubstring(`<data MODE="RAW" encoding="HEX" length="9">746573742074657874</data>`))
+			})
+		})
+	})
+	Context("a PDF file with text", func() {
+		var doc references.FPDF_DOCUMENT
+
+		BeforeEach(func() {
+			doc = openDocument("test.pdf")
+		})
+
+		AfterEach(func() {
+			closeDocument(doc)
+		})
+
+		When("CreateTextMarkupAnnotations is called", func() {
+			It("returns an error when the subtype is not a text markup subtype", func() {
+				CreateTextMarkupAnnotations, err := PdfiumInstance.CreateTextMarkupAnnotations(&requests.CreateTextMarkupAnnotations{
+					Document: doc,
+					Subtype:  enums.FPDF_ANNOT_SUBTYPE_SQUARE,
+					Ranges:   []requests.TextMarkupRange{{Page: 0, StartIndex: 0, Count: 4}},
+				})
+				Expect(err).To(MatchError("subtype 5 is not a text markup subtype"))
+				Expect(CreateTextMarkupAnnotations).To(BeNil())
+			})
+
+			It("returns an error when no ranges or search are given", func() {
+				CreateTextMarkupAnnotations, err := PdfiumInstance.CreateTextMarkupAnnotations(&requests.CreateTextMarkupAnnotations{
+					Document: doc,
+					Subtype:  enums.FPDF_ANNOT_SUBTYPE_HIGHLIGHT,
+				})
+				Expect(err).To(MatchError("no text ranges or search given"))
+				Expect(CreateTextMarkupAnnotations).To(BeNil())
+			})
+
+			It("returns an error when a range is not on the page", func() {
+				CreateTextMarkupAnnotations, err := PdfiumInstance.CreateTextMarkupAnnotations(&requests.CreateTextMarkupAnnotations{
+					Document: doc,
+					Subtype:  enums.FPDF_ANNOT_SUBTYPE_HIGHLIGHT,
+					Ranges:   []requests.TextMarkupRange{{Page: 0, StartIndex: 50, Count: 100}},
+				})
+				Expect(err).To(MatchError("could not get quad points of text range 0: the characters are not on the page"))
+				Expect(CreateTextMarkupAnnotations).To(BeNil())
+			})
+
+			It("returns an error when a page doesn't exist", func() {
+				CreateTextMarkupAnnotations, err := PdfiumInstance.CreateTextMarkupAnnotations(&requests.CreateTextMarkupAnnotations{
+					Document: doc,
+					Subtype:  enums.FPDF_ANNOT_SUBTYPE_HIGHLIGHT,
+					Ranges:   []requests.TextMarkupRange{{Page: 1, StartIndex: 0, Count: 4}},
+				})
+				Expect(err).To(MatchError("page 1 of text range 0 doesn't exist"))
+				Expect(CreateTextMarkupAnnotations).To(BeNil())
+			})
+
+			It("highlights a text range with a quad per line", func() {
+				CreateTextMarkupAnnotations, err := PdfiumInstance.CreateTextMarkupAnnotations(&requests.CreateTextMarkupAnnotations{
+					Document: doc,
+					Subtype:  enums.FPDF_ANNOT_SUBTYPE_HIGHLIGHT,
+					Ranges:   []requests.TextMarkupRange{{Page: 0, StartIndex: 0, Count: 50}},
+					Contents: "Read this",
+					Author:   "Someone",
+				})
+				Expect(err).To(BeNil())
+				Expect(CreateTextMarkupAnnotations).To(Not(BeNil()))
+				Expect(CreateTextMarkupAnnotations.Annotations).To(HaveLen(1))
+				Expect(CreateTextMarkupAnnotations.Annotations[0].Page).To(Equal(0))
+				Expect(CreateTextMarkupAnnotations.Annotations[0].Index).To(Equal(0))
+				Expect(CreateTextMarkupAnnotations.Annotations[0].QuadPoints).To(HaveLen(2))
+
+				GetAnnotations, err := PdfiumInstance.GetAnnotations(&requests.GetAnnotations{
+					Document: doc,
+				})
+				Expect(err).To(BeNil())
+				Expect(GetAnnotations).To(Not(BeNil()))
+				Expect(GetAnnotations.Pages[0].Annotations).To(HaveLen(1))
+
+				annotation := GetAnnotations.Pages[0].Annotations[0]
+				Expect(annotation.Subtype).To(Equal(enums.FPDF_ANNOT_SUBTYPE_HIGHLIGHT))
+				Expect(annotation.Contents).To(Equal("Read this"))
+				Expect(annotation.Author).To(Equal("Someone"))
+				Expect(annotation.Flags).To(Equal(enums.FPDF_ANNOT_FLAG_PRINT))
+				Expect(annotation.QuadPoints).To(Equal(CreateTextMarkupAnnotations.Annotations[0].QuadPoints))
+
+				// The color is not returned because of the appearance stream.
+				Expect(annotation.Color).To(BeNil())
+
+				FPDFPage_GetAnnot, err := PdfiumInstance.FPDFPage_GetAnnot(&requests.FPDFPage_GetAnnot{
+					Page: requests.Page{
+						ByIndex: &requests.PageByIndex{
+							Document: doc,
+							Index:    0,
+						},
+					},
+					Index: 0,
+				})
+				Expect(err).To(BeNil())
+				Expect(FPDFPage_GetAnnot).To(Not(BeNil()))
+				defer PdfiumInstance.FPDFPage_CloseAnnot(&requests.FPDFPage_CloseAnnot{
+					Annotation: FPDFPage_GetAnnot.Annotation,
+				})
+
+				FPDFAnnot_GetAP, err := PdfiumInstance.FPDFAnnot_GetAP(&requests.FPDFAnnot_GetAP{
+					Annotation:     FPDFPage_GetAnnot.Annotation,
+					AppearanceMode: enums.FPDF_ANNOT_APPEARANCEMODE_NORMAL,
+				})
+				Expect(err).To(BeNil())
+				Expect(FPDFAnnot_GetAP).To(Not(BeNil()))
+				Expect(FPDFAnnot_GetAP.Value).To(HavePrefix("q\n/GS gs\n1 1 0 rg\n"))
+			})
+
+			It("marks all the hits of a search", func() {
+				CreateTextMarkupAnnotations, err := PdfiumInstance.CreateTextMarkupAnnotations(&requests.CreateTextMarkupAnnotations{
+					Document: doc,
+					Subtype:  enums.FPDF_ANNOT_SUBTYPE_UNDERLINE,
+					Search: &requests.TextMarkupSearch{
+						Text: "t",
+					},
+					Color: &structs.FPDF_COLOR{R: 0, G: 0, B: 255, A: 255},
+				})
+				Expect(err).To(BeNil())
+				Expect(CreateTextMarkupAnnotations).To(Not(BeNil()))
+				Expect(CreateTextMarkupAnnotations.Annotations).To(HaveLen(6))
+				for i, annotation := range CreateTextMarkupAnnotations.Annotations {
+					Expect(annotation.Index).To(Equal(i))
+					Expect(annotation.Count).To(Equal(1))
+					Expect(annotation.QuadPoints).To(HaveLen(1))
+				}
+
+				Expect(CreateTextMarkupAnnotations.Annotations[0].StartIndex).To(Equal(8))
+			})
+
+			It("marks the hits of a search with match case", func() {
+				CreateTextMarkupAnnotations, err := PdfiumInstance.CreateTextMarkupAnnotations(&requests.CreateTextMarkupAnnotations{
+					Document: doc,
+					Subtype:  enums.FPDF_ANNOT_SUBTYPE_STRIKEOUT,
+					Search: &requests.TextMarkupSearch{
+						Text:  "PDF",
+						Flags: requests.FPDFText_FindStartFlag_MATCHCASE,
+					},
+				})
+				Expect(err).To(BeNil())
+				Expect(CreateTextMarkupAnnotations).To(Not(BeNil()))
+				Expect(CreateTextMarkupAnnotations.Annotations).To(HaveLen(1))
+				Expect(CreateTextMarkupAnnotations.Annotations[0].StartIndex).To(Equal(54))
+				Expect(CreateTextMarkupAnnotations.Annotations[0].Count).To(Equal(3))
+			})
+		})
+
+		When("GenerateAnnotationAppearance is called", func() {
+			createAnnotation := func(subtype enums.FPDF_ANNOTATION_SUBTYPE, rect structs.FPDF_FS_RECTF) references.FPDF_ANNOTATION {
+				FPDFPage_CreateAnnot, err := PdfiumInstance.FPDFPage_CreateAnnot(&requests.FPDFPage_CreateAnnot{
+					Page: requests.Page{
+						ByIndex: &requests.PageByIndex{
+							Document: doc,
+							Index:    0,
+						},
+					},
+					Subtype: subtype,
+				})
+				Expect(err).To(BeNil())
+				Expect(FPDFPage_CreateAnnot).To(Not(BeNil()))
+
+				FPDFAnnot_SetRect, err := PdfiumInstance.FPDFAnnot_SetRect(&requests.FPDFAnnot_SetRect{
+					Annotation: FPDFPage_CreateAnnot.Annotation,
+					Rect:       rect,
+				})
+				Expect(err).To(BeNil())
+				Expect(FPDFAnnot_SetRect).To(Not(BeNil()))
+
+				return FPDFPage_CreateAnnot.Annotation
+			}
+
+			getAppearance := func(annotation references.FPDF_ANNOTATION) string {
+				FPDFAnnot_GetAP, err := PdfiumInstance.FPDFAnnot_GetAP(&requests.FPDFAnnot_GetAP{
+					Annotation:     annotation,
+					AppearanceMode: enums.FPDF_ANNOT_APPEARANCEMODE_NORMAL,
+				})
+				Expect(err).To(BeNil())
+				Expect(FPDFAnnot_GetAP).To(Not(BeNil()))
+				return FPDFAnnot_GetAP.Value
+			}
+
+			It("returns an error when the subtype is not supported", func() {
+				annotation := createAnnotation(enums.FPDF_ANNOT_SUBTYPE_LINK, structs.FPDF_FS_RECTF{Left: 50, Top: 500, Right: 200, Bottom: 480})
+				GenerateAnnotationAppearance, err := PdfiumInstance.GenerateAnnotationAppearance(&requests.GenerateAnnotationAppearance{
+					Document:   doc,
+					Annotation: annotation,
+				})
+				Expect(err).To(MatchError("generating the appearance of subtype 2 is not supported"))
+				Expect(GenerateAnnotationAppearance).To(BeNil())
+			})
+
+			It("generates the appearance of a square annotation", func() {
+				annotation := createAnnotation(enums.FPDF_ANNOT_SUBTYPE_SQUARE, structs.FPDF_FS_RECTF{Left: 50, Top: 700, Right: 150, Bottom: 620})
+				_, err := PdfiumInstance.FPDFAnnot_SetColor(&requests.FPDFAnnot_SetColor{
+					Annotation: annotation,
+					ColorType:  enums.FPDFANNOT_COLORTYPE_Color,
+					R:          255,
+					A:          255,
+				})
+				Expect(err).To(BeNil())
+				_, err = PdfiumInstance.FPDFAnnot_SetColor(&requests.FPDFAnnot_SetColor{
+					Annotation: annotation,
+					ColorType:  enums.FPDFANNOT_COLORTYPE_InteriorColor,
+					G:          255,
+					A:          255,
+				})
+				Expect(err).To(BeNil())
+				_, err = PdfiumInstance.FPDFAnnot_SetBorder(&requests.FPDFAnnot_SetBorder{
+					Annotation:  annotation,
+					BorderWidth: 3,
+				})
+				Expect(err).To(BeNil())
+
+				GenerateAnnotationAppearance, err := PdfiumInstance.GenerateAnnotationAppearance(&requests.GenerateAnnotationAppearance{
+					Document:   doc,
+					Annotation: annotation,
+				})
+				Expect(err).To(BeNil())
+				Expect(GenerateAnnotationAppearance).To(Equal(&responses.GenerateAnnotationAppearance{
+					Subtype: enums.FPDF_ANNOT_SUBTYPE_SQUARE,
+				}))
+				Expect(getAppearance(annotation)).To(Equal("q\n1 0 0 RG\n3 w\n0 1 0 rg\n51.5 621.5 97 77 re\nB\nQ\n"))
+			})
+
+			It("generates the appearance of an ink annotation", func() {
+				annotation := createAnnotation(enums.FPDF_ANNOT_SUBTYPE_INK, structs.FPDF_FS_RECTF{Left: 320, Top: 700, Right: 450, Bottom: 620})
+				_, err := PdfiumInstance.FPDFAnnot_AddInkStroke(&requests.FPDFAnnot_AddInkStroke{
+					Annotation: annotation,
+					Points:     []structs.FPDF_FS_POINTF{{X: 330, Y: 630}, {X: 380, Y: 690}, {X: 440, Y: 630}},
+				})
+				Expect(err).To(BeNil())
+
+				GenerateAnnotationAppearance, err := PdfiumInstance.GenerateAnnotationAppearance(&requests.GenerateAnnotationAppearance{
+					Document:   doc,
+					Annotation: annotation,
+				})
+				Expect(err).To(BeNil())
+				Expect(GenerateAnnotationAppearance).To(Not(BeNil()))
+				Expect(GenerateAnnotationAppearance.Subtype).To(Equal(enums.FPDF_ANNOT_SUBTYPE_INK))
+
+				// Without a color the ink is drawn in black.
+				Expect(getAppearance(annotation)).To(Equal("q\n0 0 0 RG\n1 w\n1 J\n1 j\n330 630 m\n380 690 l\n440 630 l\nS\nQ\n"))
+			})
+
+			It("keeps the existing appearance when generating fails", func() {
+				annotation := createAnnotation(enums.FPDF_ANNOT_SUBTYPE_INK, structs.FPDF_FS_RECTF{Left: 320, Top: 700, Right: 450, Bottom: 620})
+				existingAppearance := "q\n0 0 1 RG\n330 630 m\n440 690 l\nS\nQ\n"
+				_, err := PdfiumInstance.FPDFAnnot_SetAP(&requests.FPDFAnnot_SetAP{
+					Annotation:     annotation,
+					AppearanceMode: enums.FPDF_ANNOT_APPEARANCEMODE_NORMAL,
+					Value:          &existingAppearance,
+				})
+				Expect(err).To(BeNil())
+
+				// An ink annotation without strokes has nothing to draw.
+				GenerateAnnotationAppearance, err := PdfiumInstance.GenerateAnnotationAppearance(&requests.GenerateAnnotationAppearance{
+					Document:   doc,
+					Annotation: annotation,
+				})
+				Expect(err).To(Not(BeNil()))
+				Expect(GenerateAnnotationAppearance).To(BeNil())
+				Expect(getAppearance(annotation)).To(Equal(existingAppearance))
+			})
+
+			It("generates the appearance of a free text annotation", func() {
+				annotation := createAnnotation(enums.FPDF_ANNOT_SUBTYPE_FREETEXT, structs.FPDF_FS_RECTF{Left: 50, Top: 600, Right: 250, Bottom: 520})
+				_, err := PdfiumInstance.FPDFAnnot_SetStringValue(&requests.FPDFAnnot_SetStringValue{
+					Annotation: annotation,
+					Key:        "Contents",
+					Value:      "Hello world, this is a free text annotation that wraps over lines.",
+				})
+				Expect(err).To(BeNil())
+
+				GenerateAnnotationAppearance, err := PdfiumInstance.GenerateAnnotationAppearance(&requests.GenerateAnnotationAppearance{
+					Document:   doc,
+					Annotation: annotation,
+					Font:       "Courier",
+					FontSize:   10,
+					TextColor:  &structs.FPDF_COLOR{B: 255, A: 255},
+				})
+				Expect(err).To(BeNil())
+				Expect(GenerateAnnotationAppearance).To(Not(BeNil()))
+				Expect(GenerateAnnotationAppearance.Subtype).To(Equal(enums.FPDF_ANNOT_SUBTYPE_FREETEXT))
+
+				appearance := getAppearance(annotation)
+				Expect(appearance).To(HavePrefix("q\n0 0 1 RG\n1 w\n50.5 520.5 199 79 re\nS\n53 523 194 74 re\nW\nn\n0 0 1 rg\n"))
+				Expect(appearance).To(HaveSuffix("f\nQ\n"))
+			})
+
+			It("generates the appearance of a stamp annotation only once", func() {
+				annotation := createAnnotation(enums.FPDF_ANNOT_SUBTYPE_STAMP, structs.FPDF_FS_RECTF{Left: 300, Top: 600, Right: 500, Bottom: 540})
+				_, err := PdfiumInstance.FPDFAnnot_SetStringValue(&requests.FPDFAnnot_SetStringValue{
+					Annotation: annotation,
+					Key:        "Name",
+					Value:      "Approved",
+				})
+				Expect(err).To(BeNil())
+
+				GenerateAnnotationAppearance, err := PdfiumInstance.GenerateAnnotationAppearance(&requests.GenerateAnnotationAppearance{
+					Document:   doc,
+					Annotation: annotation,
+				})
+				Expect(err).To(BeNil())
+				Expect(GenerateAnnotationAppearance).To(Not(BeNil()))
+				Expect(GenerateAnnotationAppearance.Subtype).To(Equal(enums.FPDF_ANNOT_SUBTYPE_STAMP))
+				Expect(getAppearance(annotation)).To(ContainSubstring("<415050524F564544> Tj"))
+
+				GenerateAnnotationAppearance, err = PdfiumInstance.GenerateAnnotationAppearance(&requests.GenerateAnnotationAppearance{
+					Document:   doc,
+					Annotation: annotation,
+				})
+				Expect(err).To(MatchError("the stamp already has an appearance"))
+				Expect(GenerateAnnotationAppearance).To(BeNil())
+			})
+
+			It("regenerates the appearance of a text markup annotation", func() {
+				annotation := createAnnotation(enums.FPDF_ANNOT_SUBTYPE_SQUIGGLY, structs.FPDF_FS_RECTF{Left: 50, Top: 500, Right: 200, Bottom: 480})
+				_, err := PdfiumInstance.FPDFAnnot_AppendAttachmentPoints(&requests.FPDFAnnot_AppendAttachmentPoints{
+					Annotation:       annotation,
+					AttachmentPoints: structs.FPDF_FS_QUADPOINTSF{X1: 50, Y1: 500, X2: 200, Y2: 500, X3: 50, Y3: 480, X4: 200, Y4: 480},
+				})
+				Expect(err).To(BeNil())
+
+				for i := 0; i < 2; i++ {
+					GenerateAnnotationAppearance, err := PdfiumInstance.GenerateAnnotationAppearance(&requests.GenerateAnnotationAppearance{
+						Document:   doc,
+						Annotation: annotation,
+					})
+					Expect(err).To(BeNil())
+					Expect(GenerateAnnotationAppearance).To(Not(BeNil()))
+					Expect(GenerateAnnotationAppearance.Subtype).To(Equal(enums.FPDF_ANNOT_SUBTYPE_SQUIGGLY))
+					Expect(getAppearance(annotation)).To(HavePrefix("q\n1 0 0 RG\n1.429 w\n50 480.714 m\n53.333 482.381 l\n"))
+				}
+			})
+		})
+	})
+
+	Context("a PDF file with a line annotation", func() {
+		var doc references.FPDF_DOCUMENT
+
+		BeforeEach(func() {
+			doc = openDocument("line_annot.pdf")
+		})
+
+		AfterEach(func() {
+			closeDocument(doc)
+		})
+
+		When("GenerateAnnotationAppearance is called", func() {
+			It("regenerates the appearance of the line", func() {
+				FPDFPage_GetAnnot, err := PdfiumInstance.FPDFPage_GetAnnot(&requests.FPDFPage_GetAnnot{
+					Page: requests.Page{
+						ByIndex: &requests.PageByIndex{
+							Document: doc,
+							Index:    0,
+						},
+					},
+					Index: 0,
+				})
+				Expect(err).To(BeNil())
+				Expect(FPDFPage_GetAnnot).To(Not(BeNil()))
+				defer PdfiumInstance.FPDFPage_CloseAnnot(&requests.FPDFPage_CloseAnnot{
+					Annotation: FPDFPage_GetAnnot.Annotation,
+				})
+
+				GenerateAnnotationAppearance, err := PdfiumInstance.GenerateAnnotationAppearance(&requests.GenerateAnnotationAppearance{
+					Document:   doc,
+					Annotation: FPDFPage_GetAnnot.Annotation,
+				})
+				Expect(err).To(BeNil())
+				Expect(GenerateAnnotationAppearance).To(Equal(&responses.GenerateAnnotationAppearance{
+					Subtype: enums.FPDF_ANNOT_SUBTYPE_LINE,
+				}))
+
+				FPDFAnnot_GetAP, err := PdfiumInstance.FPDFAnnot_GetAP(&requests.FPDFAnnot_GetAP{
+					Annotation:     FPDFPage_GetAnnot.Annotation,
+					AppearanceMode: enums.FPDF_ANNOT_APPEARANCEMODE_NORMAL,
+				})
+				Expect(err).To(BeNil())
+				Expect(FPDFAnnot_GetAP).To(Not(BeNil()))
+				Expect(FPDFAnnot_GetAP.Value).To(Equal("q\n1 0.898 0 RG\n2 w\n159 296 m\n472 243.42 l\nS\nQ\n"))
+			})
+		})
+	})
+})
@@ -99,35 +99,218 @@ var _ = Describe("bookmarks", func() {
 				Expect(bookmarks.Bookmarks).To(ContainElement(MatchAllFields(Fields{
 					"Reference":  Not(BeNil()),
 					"Title":      Equal("A Good Beginning"),
-					"ActionInfo": Not(BeNil()),
+					"ActionInfo": BeNil(),
 					"DestInfo":   BeNil(),
 					"Children":   HaveLen(0),
 				})))
 
-				Expect(*bookmarks.Bookmarks[0].ActionInfo).To(MatchAllFields(Fields{
-					"Reference": Not(BeNil()),
-					"Type":      Equal(enums.FPDF_ACTION_ACTION_UNSUPPORTED),
-					"DestInfo":  BeNil(),
-					"FilePath":  BeNil(),
-					"URIPath":   BeNil(),
-				}))
-
 				Expect(bookmarks.Bookmarks).To(ContainElement(MatchAllFields(Fields{
 					"Reference":  Not(BeNil()),
 					"Title":      Equal("A Good Ending"),
-					"ActionInfo": Not(BeNil()),
+					"ActionInfo": BeNil(),
 					"DestInfo":   BeNil(),
 					"Children":   HaveLen(0),
 				})))
+			})
+		})
+	})
+
+	Context("a PDF file with multiple pages", func() {
+		var doc references.FPDF_DOCUMENT
 
-				Expect(*bookmarks.Bookmarks[1].ActionInfo).To(MatchAllFields(Fields{
-					"Reference": Not(BeNil()),
-					"Type":      Equal(enums.FPDF_ACTION_ACTION_UNSUPPORTED),
-					"DestInfo":  BeNil(),
-					"FilePath":  BeNil(),
-					"URIPath":   BeNil(),
+		BeforeEach(func() {
+			pdfData, err := ioutil.ReadFile(TestDataPath + "/testdata/test_multipage.pdf")
+			Expect(err).To(BeNil())
+
+			newDoc, err := PdfiumInstance.FPDF_LoadMemDocument(&requests.FPDF_LoadMemDocument{
+				Data: &pdfData,
+			})
+			Expect(err).To(BeNil())
+
+			doc = newDoc.Document
+		})
+
+		AfterEach(func() {
+			FPDF_CloseDocument, err := PdfiumInstance.FPDF_CloseDocument(&requests.FPDF_CloseDocument{
+				Document: doc,
+			})
+			Expect(err).To(BeNil())
+			Expect(FPDF_CloseDocument).To(Not(BeNil()))
+		})
+
+		When("SetBookmarks is called", func() {
+			It("returns an error when no document is given", func() {
+				SetBookmarks, err := PdfiumInstance.SetBookmarks(&requests.SetBookmarks{})
+				Expect(err).To(MatchError("document not given"))
+				Expect(SetBookmarks).To(BeNil())
+			})
+
+			It("returns an error when a page index is out of range", func() {
+				SetBookmarks, err := PdfiumInstance.SetBookmarks(&requests.SetBookmarks{
+					Document: doc,
+					Bookmarks: []requests.SetBookmarksBookmark{
+						{
+							Title: "Chapter 1",
+							Children: []requests.SetBookmarksBookmark{
+								{Title: "Section 1.1", Dest: &requests.SetBookmarksDestination{PageIndex: 2}},
+							},
+						},
+					},
+				})
+				Expect(err).To(MatchError("page index 2 of bookmark \"Section 1.1\" is out of range, document has 2 pages"))
+				Expect(SetBookmarks).To(BeNil())
+			})
+
+			It("returns an error when both a destination and a URI are given", func() {
+				uri := "https://github.com/klippa-app/go-pdfium"
+				SetBookmarks, err := PdfiumInstance.SetBookmarks(&requests.SetBookmarks{
+					Document: doc,
+					Bookmarks: []requests.SetBookmarksBookmark{
+						{Title: "Chapter 1", Dest: &requests.SetBookmarksDestination{PageIndex: 0}, URI: &uri},
+					},
+				})
+				Expect(err).To(MatchError("bookmark \"Chapter 1\" can't have both a destination and a URI"))
+				Expect(SetBookmarks).To(BeNil())
+			})
+
+			It("writes the bookmarks into the saved document", func() {
+				x := float32(10)
+				y := float32(700)
+				uri := "https://github.com/klippa-app/go-pdfium"
+				SetBookmarks, err := PdfiumInstance.SetBookmarks(&requests.SetBookmarks{
+					Document: doc,
+					Bookmarks: []requests.SetBookmarksBookmark{
+						{
+							Title: "Chapter 1",
+							Dest:  &requests.SetBookmarksDestination{PageIndex: 0, X: &x, Y: &y},
+							Open:  true,
+							Children: []requests.SetBookmarksBookmark{
+								{Title: "Section 1.1", Dest: &requests.SetBookmarksDestination{PageIndex: 0}},
+								{Title: "Section 1.2 – Ünïcode", Dest: &requests.SetBookmarksDestination{PageIndex: 1}},
+							},
+						},
+						{Title: "Website", URI: &uri},
+					},
+				})
+				Expect(err).To(BeNil())
+				Expect(SetBookmarks).To(Equal(&responses.SetBookmarks{}))
+
+				FPDF_SaveAsCopy, err := PdfiumInstance.FPDF_SaveAsCopy(&requests.FPDF_SaveAsCopy{
+					Document: doc,
+				})
+				Expect(err).To(BeNil())
+
+				savedDoc, err := PdfiumInstance.FPDF_LoadMemDocument(&requests.FPDF_LoadMemDocument{
+					Data: FPDF_SaveAsCopy.FileBytes,
+				})
+				Expect(err).To(BeNil())
+				defer PdfiumInstance.FPDF_CloseDocument(&requests.FPDF_CloseDocument{
+					Document: savedDoc.Document,
+				})
+
+				bookmarks, err := PdfiumInstance.GetBookmarks(&requests.GetBookmarks{
+					Document: savedDoc.Document,
+				})
+				Expect(err).To(BeNil())
+				Expect(bookmarks.Bookmarks).To(HaveLen(2))
+
+				Expect(bookmarks.Bookmarks[0].Title).To(Equal("Chapter 1"))
+				Expect(bookmarks.Bookmarks[0].ActionInfo).To(BeNil())
+				Expect(bookmarks.Bookmarks[0].DestInfo).To(Not(BeNil()))
+				Expect(bookmarks.Bookmarks[0].DestInfo.PageIndex).To(Equal(0))
+				Expect(bookmarks.Bookmarks[0].Children).To(HaveLen(2))
+				Expect(bookmarks.Bookmarks[0].Children[0].Title).To(Equal("Section 1.1"))
+				Expect(bookmarks.Bookmarks[0].Children[0].DestInfo.PageIndex).To(Equal(0))
+				Expect(bookmarks.Bookmarks[0].Children[1].Title).To(Equal("Section 1.2 – Ünïcode"))
+				Expect(bookmarks.Bookmarks[0].Children[1].DestInfo.PageIndex).To(Equal(1))
+
+				Expect(bookmarks.Bookmarks[1].Title).To(Equal("Website"))
+				Expect(bookmarks.Bookmarks[1].DestInfo).To(BeNil())
+				Expect(bookmarks.Bookmarks[1].Children).To(HaveLen(0))
+				Expect(bookmarks.Bookmarks[1].ActionInfo).To(Not(BeNil()))
+				Expect(bookmarks.Bookmarks[1].ActionInfo.Type).To(Equal(enums.FPDF_ACTION_ACTION_URI))
+				Expect(bookmarks.Bookmarks[1].ActionInfo.URIPath).To(Equal(&uri))
+
+				FPDFDest_GetLocationInPage, err := PdfiumInstance.FPDFDest_GetLocationInPage(&requests.FPDFDest_GetLocationInPage{
+					Dest: bookmarks.Bookmarks[0].DestInfo.Reference,
+				})
+				Expect(err).To(BeNil())
+				Expect(FPDFDest_GetLocationInPage).To(Equal(&responses.FPDFDest_GetLocationInPage{
+					X: &x,
+					Y: &y,
 				}))
 			})
 		})
 	})
+
+	Context("a PDF file with bookmarks that are replaced", func() {
+		var doc references.FPDF_DOCUMENT
+
+		BeforeEach(func() {
+			pdfData, err := ioutil.ReadFile(TestDataPath + "/testdata/bookmarks.pdf")
+			Expect(err).To(BeNil())
+
+			newDoc, err := PdfiumInstance.FPDF_LoadMemDocument(&requests.FPDF_LoadMemDocument{
+				Data: &pdfData,
+			})
+			Expect(err).To(BeNil())
+
+			doc = newDoc.Document
+		})
+
+		AfterEach(func() {
+			FPDF_CloseDocument, err := PdfiumInstance.FPDF_CloseDocument(&requests.FPDF_CloseDocument{
+				Document: doc,
+			})
+			Expect(err).To(BeNil())
+			Expect(FPDF_CloseDocument).To(Not(BeNil()))
+		})
+
+		getSavedBookmarks := func() *responses.GetBookmarks {
+			FPDF_SaveAsCopy, err := PdfiumInstance.FPDF_SaveAsCopy(&requests.FPDF_SaveAsCopy{
+				Document: doc,
+			})
+			Expect(err).To(BeNil())
+
+			savedDoc, err := PdfiumInstance.FPDF_LoadMemDocument(&requests.FPDF_LoadMemDocument{
+				Data: FPDF_SaveAsCopy.FileBytes,
+			})
+			Expect(err).To(BeNil())
+			defer PdfiumInstance.FPDF_CloseDocument(&requests.FPDF_CloseDocument{
+				Document: savedDoc.Document,
+			})
+
+			bookmarks, err := PdfiumInstance.GetBookmarks(&requests.GetBookmarks{
+				Document: savedDoc.Document,
+			})
+			Expect(err).To(BeNil())
+
+			return bookmarks
+		}
+
+		It("replaces the existing bookmarks", func() {
+			_, err := PdfiumInstance.SetBookmarks(&requests.SetBookmarks{
+				Document: doc,
+				Bookmarks: []requests.SetBookmarksBookmark{
+					{Title: "The only bookmark", Dest: &requests.SetBookmarksDestination{PageIndex: 0}},
+				},
+			})
+			Expect(err).To(BeNil())
+
+			bookmarks := getSavedBookmarks()
+			Expect(bookmarks.Bookmarks).To(HaveLen(1))
+			Expect(bookmarks.Bookmarks[0].Title).To(Equal("The only bookmark"))
+			Expect(bookmarks.Bookmarks[0].DestInfo.PageIndex).To(Equal(0))
+		})
+
+		It("removes the bookmarks", func() {
+			_, err := PdfiumInstance.SetBookmarks(&requests.SetBookmarks{
+				Document: doc,
+			})
+			Expect(err).To(BeNil())
+
+			bookmarks := getSavedBookmarks()
+			Expect(bookmarks).To(Equal(&responses.GetBookmarks{}))
+		})
+	})
 })
@@ -0,0 +1,104 @@
+//go:build pdfium_experimental
+// +build pdfium_experimental
+
+package shared_tests
+
+import (
+	"io/ioutil"
+
+	"github.com/klippa-app/go-pdfium/references"
+	"github.com/klippa-app/go-pdfium/requests"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("bookmark_experimental", func() {
+	BeforeEach(func() {
+		Locker.Lock()
+	})
+
+	AfterEach(func() {
+		Locker.Unlock()
+	})
+
+	Context("a PDF file with multiple pages", func() {
+		var doc references.FPDF_DOCUMENT
+
+		BeforeEach(func() {
+			pdfData, err := ioutil.ReadFile(TestDataPath + "/testdata/test_multipage.pdf")
+			Expect(err).To(BeNil())
+
+			newDoc, err := PdfiumInstance.FPDF_LoadMemDocument(&requests.FPDF_LoadMemDocument{
+				Data: &pdfData,
+			})
+			Expect(err).To(BeNil())
+
+			doc = newDoc.Document
+		})
+
+		AfterEach(func() {
+			FPDF_CloseDocument, err := PdfiumInstance.FPDF_CloseDocument(&requests.FPDF_CloseDocument{
+				Document: doc,
+			})
+			Expect(err).To(BeNil())
+			Expect(FPDF_CloseDocument).To(Not(BeNil()))
+		})
+
+		It("writes the open state of the bookmarks", func() {
+			_, err := PdfiumInstance.SetBookmarks(&requests.SetBookmarks{
+				Document: doc,
+				Bookmarks: []requests.SetBookmarksBookmark{
+					{
+						Title: "Open",
+						Open:  true,
+						Children: []requests.SetBookmarksBookmark{
+							{Title: "Open child 1"},
+							{Title: "Open child 2"},
+						},
+					},
+					{
+						Title: "Closed",
+						Children: []requests.SetBookmarksBookmark{
+							{Title: "Closed child 1"},
+							{Title: "Closed child 2"},
+							{Title: "Closed child 3"},
+						},
+					},
+				},
+			})
+			Expect(err).To(BeNil())
+
+			FPDF_SaveAsCopy, err := PdfiumInstance.FPDF_SaveAsCopy(&requests.FPDF_SaveAsCopy{
+				Document: doc,
+			})
+			Expect(err).To(BeNil())
+
+			savedDoc, err := PdfiumInstance.FPDF_LoadMemDocument(&requests.FPDF_LoadMemDocument{
+				Data: FPDF_SaveAsCopy.FileBytes,
+			})
+			Expect(err).To(BeNil())
+			defer PdfiumInstance.FPDF_CloseDocument(&requests.FPDF_CloseDocument{
+				Document: savedDoc.Document,
+			})
+
+			bookmarks, err := PdfiumInstance.GetBookmarks(&requests.GetBookmarks{
+				Document: savedDoc.Document,
+			})
+			Expect(err).To(BeNil())
+			Expect(bookmarks.Bookmarks).To(HaveLen(2))
+
+			FPDFBookmark_GetCount, err := PdfiumInstance.FPDFBookmark_GetCount(&requests.FPDFBookmark_GetCount{
+				Bookmark: bookmarks.Bookmarks[0].Reference,
+			})
+			Expect(err).To(BeNil())
+			Expect(FPDFBookmark_GetCount.Count).To(Equal(2))
+
+			FPDFBookmark_GetCount, err = PdfiumInstance.FPDFBookmark_GetCount(&requests.FPDFBookmark_GetCount{
+				Bookmark: bookmarks.Bookmarks[1].Reference,
+			})
+			Expect(err).To(BeNil())
+			Expect(FPDFBookmark_GetCount.Count).To(Equal(-3))
+		})
+	})
+})
@@ -0,0 +1,289 @@
+package shared_tests
+
+import (
+	"github.com/klippa-app/go-pdfium/references"
+	"github.com/klippa-app/go-pdfium/requests"
+	"github.com/klippa-app/go-pdfium/responses"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("compare", func() {
+	BeforeEach(func() {
+		Locker.Lock()
+	})
+
+	AfterEach(func() {
+		Locker.Unlock()
+	})
+
+	pageIndex := func(index int) *int {
+		return &index
+	}
+
+	Context("no document", func() {
+		When("is opened", func() {
+			It("returns an error when calling CompareDocuments", func() {
+				CompareDocuments, err := PdfiumInstance.CompareDocuments(&requests.CompareDocuments{
+					DPI: 72,
+				})
+				Expect(err).To(MatchError("document not given"))
+				Expect(CompareDocuments).To(BeNil())
+			})
+
+			It("returns an error when calling CompareDocumentsText", func() {
+				CompareDocumentsText, err := PdfiumInstance.CompareDocumentsText(&requests.CompareDocumentsText{})
+				Expect(err).To(MatchError("document not given"))
+				Expect(CompareDocumentsText).To(BeNil())
+			})
+		})
+	})
+
+	Context("two PDF files", func() {
+		var original references.FPDF_DOCUMENT
+		var modified references.FPDF_DOCUMENT
+
+		AfterEach(func() {
+			closeDocument(original)
+			closeDocument(modified)
+		})
+
+		When("the files are the same", func() {
+			BeforeEach(func() {
+				original = openDocument("test_multipage.pdf")
+				modified = openDocument("test_multipage.pdf")
+			})
+
+			It("returns an error when no DPI is given", func() {
+				CompareDocuments, err := PdfiumInstance.CompareDocuments(&requests.CompareDocuments{
+					Original: original,
+					Modified: modified,
+				})
+				Expect(err).To(MatchError("no DPI given"))
+				Expect(CompareDocuments).To(BeNil())
+			})
+
+			It("returns an error when an invalid tolerance is given", func() {
+				CompareDocuments, err := PdfiumInstance.CompareDocuments(&requests.CompareDocuments{
+					Original:  original,
+					Modified:  modified,
+					DPI:       72,
+					Tolerance: 256,
+				})
+				Expect(err).To(MatchError("invalid tolerance 256 given"))
+				Expect(CompareDocuments).To(BeNil())
+			})
+
+			It("returns that the documents are identical", func() {
+				CompareDocuments, err := PdfiumInstance.CompareDocuments(&requests.CompareDocuments{
+					Original:          original,
+					Modified:          modified,
+					DPI:               72,
+					IncludeDiffImages: true,
+				})
+				Expect(err).To(BeNil())
+				Expect(CompareDocuments).To(Equal(&responses.CompareDocuments{
+					Identical: true,
+					Pages: []responses.CompareDocumentsPage{
+						{
+							Status:       responses.CompareDocumentsPageStatusIdentical,
+							OriginalPage: pageIndex(0),
+							ModifiedPage: pageIndex(0),
+						},
+						{
+							Status:       responses.CompareDocumentsPageStatusIdentical,
+							OriginalPage: pageIndex(1),
+							ModifiedPage: pageIndex(1),
+						},
+					},
+				}))
+			})
+
+			It("returns an error when an invalid DPI is given for the text", func() {
+				CompareDocumentsText, err := PdfiumInstance.CompareDocumentsText(&requests.CompareDocumentsText{
+					Original: original,
+					Modified: modified,
+					DPI:      -1,
+				})
+				Expect(err).To(MatchError("invalid DPI -1 given"))
+				Expect(CompareDocumentsText).To(BeNil())
+			})
+
+			It("returns that the text is identical", func() {
+				CompareDocumentsText, err := PdfiumInstance.CompareDocumentsText(&requests.CompareDocumentsText{
+					Original: original,
+					Modified: modified,
+				})
+				Expect(err).To(BeNil())
+				Expect(CompareDocumentsText).To(Equal(&responses.CompareDocumentsText{
+					Identical: true,
+					Changes:   []responses.CompareDocumentsTextChange{},
+				}))
+			})
+
+			It("returns the removed and added pages", func() {
+				FPDFPage_Delete, err := PdfiumInstance.FPDFPage_Delete(&requests.FPDFPage_Delete{
+					Document:  modified,
+					PageIndex: 0,
+				})
+				Expect(err).To(BeNil())
+				Expect(FPDFPage_Delete).To(Not(BeNil()))
+
+				CompareDocuments, err := PdfiumInstance.CompareDocuments(&requests.CompareDocuments{
+					Original: original,
+					Modified: modified,
+					DPI:      72,
+				})
+				Expect(err).To(BeNil())
+				Expect(CompareDocuments).To(Equal(&responses.CompareDocuments{
+					Identical: false,
+					Pages: []responses.CompareDocumentsPage{
+						{
+							Status:       responses.CompareDocumentsPageStatusRemoved,
+							OriginalPage: pageIndex(0),
+							Difference:   1,
+						},
+						{
+							Status:       responses.CompareDocumentsPageStatusIdentical,
+							OriginalPage: pageIndex(1),
+							ModifiedPage: pageIndex(0),
+						},
+					},
+				}))
+
+				CompareDocuments, err = PdfiumInstance.CompareDocuments(&requests.CompareDocuments{
+					Original: modified,
+					Modified: original,
+					DPI:      72,
+				})
+				Expect(err).To(BeNil())
+				Expect(CompareDocuments).To(Equal(&responses.CompareDocuments{
+					Identical: false,
+					Pages: []responses.CompareDocumentsPage{
+						{
+							Status:       responses.CompareDocumentsPageStatusAdded,
+							ModifiedPage: pageIndex(0),
+							Difference:   1,
+						},
+						{
+							Status:       responses.CompareDocumentsPageStatusIdentical,
+							OriginalPage: pageIndex(0),
+							ModifiedPage: pageIndex(1),
+						},
+					},
+				}))
+			})
+		})
+
+		When("the files are different", func() {
+			BeforeEach(func() {
+				original = openDocument("test.pdf")
+				modified = openDocument("hello_world.pdf")
+			})
+
+			It("returns the changed pages", func() {
+				CompareDocuments, err := PdfiumInstance.CompareDocuments(&requests.CompareDocuments{
+					Original:          original,
+					Modified:          modified,
+					DPI:               72,
+					IncludeDiffImages: true,
+				})
+				Expect(err).To(BeNil())
+				Expect(CompareDocuments).To(Not(BeNil()))
+				Expect(CompareDocuments.Identical).To(BeFalse())
+				Expect(CompareDocuments.Pages).To(HaveLen(1))
+
+				page := CompareDocuments.Pages[0]
+				Expect(page.Status).To(Equal(responses.CompareDocumentsPageStatusChanged))
+				Expect(page.OriginalPage).To(Equal(pageIndex(0)))
+				Expect(page.ModifiedPage).To(Equal(pageIndex(0)))
+				Expect(page.ChangedPixels).To(Equal(464542))
+				Expect(page.Difference).To(BeNumerically("~", 0.9257, 0.0001))
+				Expect(page.DiffImage).To(Not(BeNil()))
+				Expect(page.DiffImage.Bounds().Dx()).To(Equal(596))
+				Expect(page.DiffImage.Bounds().Dy()).To(Equal(842))
+
+				// Unchanged pixels are light gray, changed pixels are red.
+				Expect(page.DiffImage.Pix[0:4]).To(Equal([]byte{255, 255, 255, 255}))
+				Expect(page.DiffImage.Pix[len(page.DiffImage.Pix)-4:]).To(Equal([]byte{255, 0, 0, 255}))
+			})
+
+			It("returns the changed pages without diff image", func() {
+				CompareDocuments, err := PdfiumInstance.CompareDocuments(&requests.CompareDocuments{
+					Original: original,
+					Modified: modified,
+					DPI:      72,
+				})
+				Expect(err).To(BeNil())
+				Expect(CompareDocuments).To(Not(BeNil()))
+				Expect(CompareDocuments.Pages).To(HaveLen(1))
+				Expect(CompareDocuments.Pages[0].Status).To(Equal(responses.CompareDocumentsPageStatusChanged))
+				Expect(CompareDocuments.Pages[0].DiffImage).To(BeNil())
+			})
+		})
+
+		When("the files have different text", func() {
+			BeforeEach(func() {
+				original = openDocument("test.pdf")
+				modified = openDocument("test_multipage.pdf")
+			})
+
+			It("returns the inserted and deleted words", func() {
+				CompareDocumentsText, err := PdfiumInstance.CompareDocumentsText(&requests.CompareDocumentsText{
+					Original: original,
+					Modified: modified,
+					DPI:      72,
+				})
+				Expect(err).To(BeNil())
+				Expect(CompareDocumentsText).To(Not(BeNil()))
+				Expect(CompareDocumentsText.Identical).To(BeFalse())
+				Expect(CompareDocumentsText.Changes).To(HaveLen(3))
+
+				// The page number in "Page 1 of 1" is changed.
+				deletion := CompareDocumentsText.Changes[0]
+				Expect(deletion.Type).To(Equal(responses.CompareDocumentsTextChangeTypeDeletion))
+				Expect(deletion.Text).To(Equal("1"))
+				Expect(deletion.Words).To(HaveLen(1))
+				Expect(deletion.Words[0].Page).To(Equal(0))
+				Expect(deletion.Words[0].PointPosition.Left).To(BeNumerically("~", 518.56, 0.01))
+				Expect(deletion.Words[0].PointPosition.Top).To(BeNumerically("~", 797.18, 0.01))
+				Expect(deletion.Words[0].PixelPosition).To(Equal(&responses.CharPosition{
+					Left:   519,
+					Top:    45,
+					Right:  524,
+					Bottom: 53,
+				}))
+
+				insertion := CompareDocumentsText.Changes[1]
+				Expect(insertion.Type).To(Equal(responses.CompareDocumentsTextChangeTypeInsertion))
+				Expect(insertion.Text).To(Equal("2"))
+				Expect(insertion.Words).To(HaveLen(1))
+				Expect(insertion.Words[0].Page).To(Equal(0))
+
+				// The second page is added.
+				insertion = CompareDocumentsText.Changes[2]
+				Expect(insertion.Type).To(Equal(responses.CompareDocumentsTextChangeTypeInsertion))
+				Expect(insertion.Text).To(Equal("File: Untitled Document 2 Page 2 of 2 With multiple pages"))
+				Expect(insertion.Words).To(HaveLen(11))
+				for _, word := range insertion.Words {
+					Expect(word.Page).To(Equal(1))
+					Expect(word.PixelPosition).To(Not(BeNil()))
+				}
+			})
+
+			It("returns the deleted words the other way around", func() {
+				CompareDocumentsText, err := PdfiumInstance.CompareDocumentsText(&requests.CompareDocumentsText{
+					Original: modified,
+					Modified: original,
+				})
+				Expect(err).To(BeNil())
+				Expect(CompareDocumentsText).To(Not(BeNil()))
+				Expect(CompareDocumentsText.Changes).To(HaveLen(3))
+				Expect(CompareDocumentsText.Changes[2].Type).To(Equal(responses.CompareDocumentsTextChangeTypeDeletion))
+				Expect(CompareDocumentsText.Changes[2].Text).To(Equal("File: Untitled Document 2 Page 2 of 2 With multiple pages"))
+				Expect(CompareDocumentsText.Changes[2].Words[0].PixelPosition).To(BeNil())
+			})
+		})
+	})
+})
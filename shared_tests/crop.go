@@ -0,0 +1,275 @@
+package shared_tests
+
+import (
+	"io/ioutil"
+
+	"github.com/klippa-app/go-pdfium/references"
+	"github.com/klippa-app/go-pdfium/requests"
+	"github.com/klippa-app/go-pdfium/responses"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("crop", func() {
+	BeforeEach(func() {
+		Locker.Lock()
+	})
+
+	AfterEach(func() {
+		Locker.Unlock()
+	})
+
+	Context("no document", func() {
+		When("is opened", func() {
+			It("returns an error when calling CropPages", func() {
+				CropPages, err := PdfiumInstance.CropPages(&requests.CropPages{
+					Mode: requests.CropPagesModeObjects,
+				})
+				Expect(err).To(MatchError("document not given"))
+				Expect(CropPages).To(BeNil())
+			})
+		})
+	})
+
+	Context("a normal PDF file", func() {
+		var doc references.FPDF_DOCUMENT
+
+		BeforeEach(func() {
+			pdfData, err := ioutil.ReadFile(TestDataPath + "/testdata/test.pdf")
+			Expect(err).To(BeNil())
+
+			newDoc, err := PdfiumInstance.FPDF_LoadMemDocument(&requests.FPDF_LoadMemDocument{
+				Data: &pdfData,
+			})
+			Expect(err).To(BeNil())
+
+			doc = newDoc.Document
+		})
+
+		AfterEach(func() {
+			FPDF_CloseDocument, err := PdfiumInstance.FPDF_CloseDocument(&requests.FPDF_CloseDocument{
+				Document: doc,
+			})
+			Expect(err).To(BeNil())
+			Expect(FPDF_CloseDocument).To(Not(BeNil()))
+		})
+
+		It("returns an error when an invalid mode is given", func() {
+			CropPages, err := PdfiumInstance.CropPages(&requests.CropPages{
+				Document: doc,
+			})
+			Expect(err).To(MatchError("invalid crop mode given"))
+			Expect(CropPages).To(BeNil())
+		})
+
+		It("returns an error when an invalid box is given", func() {
+			CropPages, err := PdfiumInstance.CropPages(&requests.CropPages{
+				Document: doc,
+				Mode:     requests.CropPagesModeValues,
+				Left:     100,
+				Bottom:   100,
+				Right:    50,
+				Top:      200,
+			})
+			Expect(err).To(MatchError("invalid crop box given"))
+			Expect(CropPages).To(BeNil())
+		})
+
+		It("returns an error when an invalid page range is given", func() {
+			pageRange := "1-3"
+			CropPages, err := PdfiumInstance.CropPages(&requests.CropPages{
+				Document:  doc,
+				PageRange: &pageRange,
+				Mode:      requests.CropPagesModeObjects,
+			})
+			Expect(err).To(MatchError("page 2 is out of range, document has 1 pages"))
+			Expect(CropPages).To(BeNil())
+		})
+
+		It("sets the crop box to the given values", func() {
+			CropPages, err := PdfiumInstance.CropPages(&requests.CropPages{
+				Document: doc,
+				Mode:     requests.CropPagesModeValues,
+				Left:     50,
+				Bottom:   100,
+				Right:    400,
+				Top:      600,
+			})
+			Expect(err).To(BeNil())
+			Expect(CropPages).To(Equal(&responses.CropPages{
+				Pages: []responses.CropPagesPage{
+					{Page: 0, Cropped: true, Left: 50, Bottom: 100, Right: 400, Top: 600},
+				},
+			}))
+
+			FPDFPage_GetCropBox, err := PdfiumInstance.FPDFPage_GetCropBox(&requests.FPDFPage_GetCropBox{
+				Page: requests.Page{
+					ByIndex: &requests.PageByIndex{
+						Document: doc,
+						Index:    0,
+					},
+				},
+			})
+			Expect(err).To(BeNil())
+			Expect(FPDFPage_GetCropBox).To(Equal(&responses.FPDFPage_GetCropBox{
+				Left:   50,
+				Bottom: 100,
+				Right:  400,
+				Top:    600,
+			}))
+		})
+
+		It("sets multiple boxes to the given values", func() {
+			CropPages, err := PdfiumInstance.CropPages(&requests.CropPages{
+				Document: doc,
+				Mode:     requests.CropPagesModeValues,
+				Boxes:    []requests.CropPagesBox{requests.CropPagesBoxTrimBox, requests.CropPagesBoxBleedBox},
+				Left:     10,
+				Bottom:   20,
+				Right:    300,
+				Top:      400,
+			})
+			Expect(err).To(BeNil())
+			Expect(CropPages.Pages).To(HaveLen(1))
+
+			page := requests.Page{
+				ByIndex: &requests.PageByIndex{
+					Document: doc,
+					Index:    0,
+				},
+			}
+
+			FPDFPage_GetTrimBox, err := PdfiumInstance.FPDFPage_GetTrimBox(&requests.FPDFPage_GetTrimBox{
+				Page: page,
+			})
+			Expect(err).To(BeNil())
+			Expect(FPDFPage_GetTrimBox).To(Equal(&responses.FPDFPage_GetTrimBox{
+				Left:   10,
+				Bottom: 20,
+				Right:  300,
+				Top:    400,
+			}))
+
+			FPDFPage_GetBleedBox, err := PdfiumInstance.FPDFPage_GetBleedBox(&requests.FPDFPage_GetBleedBox{
+				Page: page,
+			})
+			Expect(err).To(BeNil())
+			Expect(FPDFPage_GetBleedBox).To(Equal(&responses.FPDFPage_GetBleedBox{
+				Left:   10,
+				Bottom: 20,
+				Right:  300,
+				Top:    400,
+			}))
+
+			FPDFPage_GetCropBox, err := PdfiumInstance.FPDFPage_GetCropBox(&requests.FPDFPage_GetCropBox{
+				Page: page,
+			})
+			Expect(err).To(MatchError("could not get crop box"))
+			Expect(FPDFPage_GetCropBox).To(BeNil())
+		})
+
+		It("crops the page to the bounds of the page objects", func() {
+			CropPages, err := PdfiumInstance.CropPages(&requests.CropPages{
+				Document: doc,
+				Mode:     requests.CropPagesModeObjects,
+			})
+			Expect(err).To(BeNil())
+			Expect(CropPages.Pages).To(HaveLen(1))
+			Expect(CropPages.Pages[0].Cropped).To(BeTrue())
+			Expect(CropPages.Pages[0].Left).To(BeNumerically("~", 70.17, 0.1))
+			Expect(CropPages.Pages[0].Bottom).To(BeNumerically("~", 762.73, 0.1))
+			Expect(CropPages.Pages[0].Right).To(BeNumerically("~", 525.11, 0.1))
+			Expect(CropPages.Pages[0].Top).To(BeNumerically("~", 797.52, 0.1))
+		})
+
+		It("crops the page to the bounds of the page objects with a margin", func() {
+			CropPages, err := PdfiumInstance.CropPages(&requests.CropPages{
+				Document: doc,
+				Mode:     requests.CropPagesModeObjects,
+				Margin:   20,
+			})
+			Expect(err).To(BeNil())
+			Expect(CropPages.Pages).To(HaveLen(1))
+			Expect(CropPages.Pages[0].Cropped).To(BeTrue())
+			Expect(CropPages.Pages[0].Left).To(BeNumerically("~", 50.17, 0.1))
+			Expect(CropPages.Pages[0].Bottom).To(BeNumerically("~", 742.73, 0.1))
+			Expect(CropPages.Pages[0].Right).To(BeNumerically("~", 545.11, 0.1))
+			Expect(CropPages.Pages[0].Top).To(BeNumerically("~", 817.52, 0.1))
+		})
+
+		It("never crops outside of the page", func() {
+			CropPages, err := PdfiumInstance.CropPages(&requests.CropPages{
+				Document: doc,
+				Mode:     requests.CropPagesModeObjects,
+				Margin:   100,
+			})
+			Expect(err).To(BeNil())
+			Expect(CropPages.Pages).To(HaveLen(1))
+			Expect(CropPages.Pages[0].Top).To(BeNumerically("~", 841.89, 0.1))
+		})
+
+		It("crops the page to the non-white area of the rendered page", func() {
+			CropPages, err := PdfiumInstance.CropPages(&requests.CropPages{
+				Document: doc,
+				Mode:     requests.CropPagesModeRender,
+			})
+			Expect(err).To(BeNil())
+			Expect(CropPages.Pages).To(HaveLen(1))
+			Expect(CropPages.Pages[0].Cropped).To(BeTrue())
+			Expect(CropPages.Pages[0].Left).To(BeNumerically("~", 70.17, 2))
+			Expect(CropPages.Pages[0].Bottom).To(BeNumerically("~", 762.73, 2))
+			Expect(CropPages.Pages[0].Right).To(BeNumerically("~", 525.11, 2))
+			Expect(CropPages.Pages[0].Top).To(BeNumerically("~", 797.52, 2))
+
+			FPDFPage_GetCropBox, err := PdfiumInstance.FPDFPage_GetCropBox(&requests.FPDFPage_GetCropBox{
+				Page: requests.Page{
+					ByIndex: &requests.PageByIndex{
+						Document: doc,
+						Index:    0,
+					},
+				},
+			})
+			Expect(err).To(BeNil())
+			Expect(FPDFPage_GetCropBox.Left).To(Equal(CropPages.Pages[0].Left))
+			Expect(FPDFPage_GetCropBox.Top).To(Equal(CropPages.Pages[0].Top))
+		})
+	})
+
+	Context("a PDF file with an inherited media box", func() {
+		var doc references.FPDF_DOCUMENT
+
+		BeforeEach(func() {
+			pdfData, err := ioutil.ReadFile(TestDataPath + "/testdata/rectangles.pdf")
+			Expect(err).To(BeNil())
+
+			newDoc, err := PdfiumInstance.FPDF_LoadMemDocument(&requests.FPDF_LoadMemDocument{
+				Data: &pdfData,
+			})
+			Expect(err).To(BeNil())
+
+			doc = newDoc.Document
+		})
+
+		AfterEach(func() {
+			FPDF_CloseDocument, err := PdfiumInstance.FPDF_CloseDocument(&requests.FPDF_CloseDocument{
+				Document: doc,
+			})
+			Expect(err).To(BeNil())
+			Expect(FPDF_CloseDocument).To(Not(BeNil()))
+		})
+
+		It("limits the bounds of the page objects to the page", func() {
+			CropPages, err := PdfiumInstance.CropPages(&requests.CropPages{
+				Document: doc,
+				Mode:     requests.CropPagesModeObjects,
+			})
+			Expect(err).To(BeNil())
+			Expect(CropPages).To(Equal(&responses.CropPages{
+				Pages: []responses.CropPagesPage{
+					{Page: 0, Cropped: true, Left: 0, Bottom: 0, Right: 200, Top: 300},
+				},
+			}))
+		})
+	})
+})
@@ -0,0 +1,263 @@
+//go:build pdfium_experimental
+// +build pdfium_experimental
+
+package shared_tests
+
+import (
+	"io/ioutil"
+
+	"github.com/klippa-app/go-pdfium/enums"
+	"github.com/klippa-app/go-pdfium/references"
+	"github.com/klippa-app/go-pdfium/requests"
+	"github.com/klippa-app/go-pdfium/responses"
+	"github.com/klippa-app/go-pdfium/structs"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("flatten_experimental", func() {
+	BeforeEach(func() {
+		Locker.Lock()
+	})
+
+	AfterEach(func() {
+		Locker.Unlock()
+	})
+
+	Context("a PDF file with a form field and an annotation", func() {
+		var doc references.FPDF_DOCUMENT
+		var page requests.Page
+
+		BeforeEach(func() {
+			pdfData, err := ioutil.ReadFile(TestDataPath + "/testdata/text_form.pdf")
+			Expect(err).To(BeNil())
+
+			newDoc, err := PdfiumInstance.FPDF_LoadMemDocument(&requests.FPDF_LoadMemDocument{
+				Data: &pdfData,
+			})
+			Expect(err).To(BeNil())
+
+			doc = newDoc.Document
+			page = requests.Page{
+				ByIndex: &requests.PageByIndex{
+					Document: doc,
+					Index:    0,
+				},
+			}
+
+			FPDFPage_CreateAnnot, err := PdfiumInstance.FPDFPage_CreateAnnot(&requests.FPDFPage_CreateAnnot{
+				Page:    page,
+				Subtype: enums.FPDF_ANNOT_SUBTYPE_SQUARE,
+			})
+			Expect(err).To(BeNil())
+			Expect(FPDFPage_CreateAnnot).To(Not(BeNil()))
+
+			_, err = PdfiumInstance.FPDFAnnot_SetRect(&requests.FPDFAnnot_SetRect{
+				Annotation: FPDFPage_CreateAnnot.Annotation,
+				Rect:       structs.FPDF_FS_RECTF{Left: 10, Top: 100, Right: 100, Bottom: 10},
+			})
+			Expect(err).To(BeNil())
+
+			_, err = PdfiumInstance.FPDFAnnot_SetColor(&requests.FPDFAnnot_SetColor{
+				Annotation: FPDFPage_CreateAnnot.Annotation,
+				ColorType:  enums.FPDFANNOT_COLORTYPE_Color,
+				R:          255,
+				A:          255,
+			})
+			Expect(err).To(BeNil())
+
+			_, err = PdfiumInstance.FPDFAnnot_SetFlags(&requests.FPDFAnnot_SetFlags{
+				Annotation: FPDFPage_CreateAnnot.Annotation,
+				Flags:      enums.FPDF_ANNOT_FLAG_PRINT,
+			})
+			Expect(err).To(BeNil())
+
+			_, err = PdfiumInstance.GenerateAnnotationAppearance(&requests.GenerateAnnotationAppearance{
+				Document:   doc,
+				Annotation: FPDFPage_CreateAnnot.Annotation,
+			})
+			Expect(err).To(BeNil())
+
+			_, err = PdfiumInstance.FPDFPage_CloseAnnot(&requests.FPDFPage_CloseAnnot{
+				Annotation: FPDFPage_CreateAnnot.Annotation,
+			})
+			Expect(err).To(BeNil())
+		})
+
+		AfterEach(func() {
+			FPDF_CloseDocument, err := PdfiumInstance.FPDF_CloseDocument(&requests.FPDF_CloseDocument{
+				Document: doc,
+			})
+			Expect(err).To(BeNil())
+			Expect(FPDF_CloseDocument).To(Not(BeNil()))
+		})
+
+		getAnnotationSubtypes := func(page requests.Page) []enums.FPDF_ANNOTATION_SUBTYPE {
+			FPDFPage_GetAnnotCount, err := PdfiumInstance.FPDFPage_GetAnnotCount(&requests.FPDFPage_GetAnnotCount{
+				Page: page,
+			})
+			Expect(err).To(BeNil())
+
+			subtypes := []enums.FPDF_ANNOTATION_SUBTYPE{}
+			for i := 0; i < FPDFPage_GetAnnotCount.Count; i++ {
+				FPDFPage_GetAnnot, err := PdfiumInstance.FPDFPage_GetAnnot(&requests.FPDFPage_GetAnnot{
+					Page:  page,
+					Index: i,
+				})
+				Expect(err).To(BeNil())
+
+				FPDFAnnot_GetSubtype, err := PdfiumInstance.FPDFAnnot_GetSubtype(&requests.FPDFAnnot_GetSubtype{
+					Annotation: FPDFPage_GetAnnot.Annotation,
+				})
+				Expect(err).To(BeNil())
+				subtypes = append(subtypes, FPDFAnnot_GetSubtype.Subtype)
+
+				_, err = PdfiumInstance.FPDFPage_CloseAnnot(&requests.FPDFPage_CloseAnnot{
+					Annotation: FPDFPage_GetAnnot.Annotation,
+				})
+				Expect(err).To(BeNil())
+			}
+
+			return subtypes
+		}
+
+		getObjectCount := func(page requests.Page) int {
+			FPDFPage_CountObjects, err := PdfiumInstance.FPDFPage_CountObjects(&requests.FPDFPage_CountObjects{
+				Page: page,
+			})
+			Expect(err).To(BeNil())
+			return FPDFPage_CountObjects.Count
+		}
+
+		When("FlattenDocument is called", func() {
+			It("returns an error when an invalid mode is given", func() {
+				FlattenDocument, err := PdfiumInstance.FlattenDocument(&requests.FlattenDocument{
+					Document: doc,
+					Mode:     "everything",
+				})
+				Expect(err).To(MatchError("invalid flatten mode given"))
+				Expect(FlattenDocument).To(BeNil())
+			})
+
+			It("returns an error when an invalid usage is given", func() {
+				FlattenDocument, err := PdfiumInstance.FlattenDocument(&requests.FlattenDocument{
+					Document: doc,
+					Usage:    3,
+				})
+				Expect(err).To(MatchError("invalid flatten usage given"))
+				Expect(FlattenDocument).To(BeNil())
+			})
+
+			It("returns an error when the page range is out of range", func() {
+				pageRange := "2"
+				FlattenDocument, err := PdfiumInstance.FlattenDocument(&requests.FlattenDocument{
+					Document:  doc,
+					PageRange: &pageRange,
+				})
+				Expect(err).To(MatchError("page 2 is out of range, document has 1 pages"))
+				Expect(FlattenDocument).To(BeNil())
+			})
+
+			It("flattens everything", func() {
+				FlattenDocument, err := PdfiumInstance.FlattenDocument(&requests.FlattenDocument{
+					Document: doc,
+					Usage:    requests.FPDFPage_FlattenUsagePrint,
+				})
+				Expect(err).To(BeNil())
+				Expect(FlattenDocument).To(Equal(&responses.FlattenDocument{
+					Pages: []responses.FlattenDocumentPage{
+						{Page: 0, Result: responses.FPDFPage_FlattenResultSuccess},
+					},
+				}))
+				Expect(getAnnotationSubtypes(page)).To(BeEmpty())
+			})
+
+			It("flattens only the form fields", func() {
+				objectCount := getObjectCount(page)
+
+				FlattenDocument, err := PdfiumInstance.FlattenDocument(&requests.FlattenDocument{
+					Document: doc,
+					Mode:     requests.FlattenDocumentModeFormFields,
+				})
+				Expect(err).To(BeNil())
+				Expect(FlattenDocument).To(Equal(&responses.FlattenDocument{
+					Pages: []responses.FlattenDocumentPage{
+						{Page: 0, Result: responses.FPDFPage_FlattenResultSuccess, Kept: 1},
+					},
+				}))
+				Expect(getAnnotationSubtypes(page)).To(Equal([]enums.FPDF_ANNOTATION_SUBTYPE{enums.FPDF_ANNOT_SUBTYPE_SQUARE}))
+
+				// The flattened form field is added as a form object.
+				Expect(getObjectCount(page)).To(Equal(objectCount + 1))
+
+				// The copy of the page that was used to flatten is removed.
+				FPDF_GetPageCount, err := PdfiumInstance.FPDF_GetPageCount(&requests.FPDF_GetPageCount{
+					Document: doc,
+				})
+				Expect(err).To(BeNil())
+				Expect(FPDF_GetPageCount.PageCount).To(Equal(1))
+			})
+
+			It("flattens only the annotations", func() {
+				FlattenDocument, err := PdfiumInstance.FlattenDocument(&requests.FlattenDocument{
+					Document: doc,
+					Mode:     requests.FlattenDocumentModeAnnotations,
+				})
+				Expect(err).To(BeNil())
+				Expect(FlattenDocument).To(Equal(&responses.FlattenDocument{
+					Pages: []responses.FlattenDocumentPage{
+						{Page: 0, Result: responses.FPDFPage_FlattenResultSuccess, Kept: 1},
+					},
+				}))
+				Expect(getAnnotationSubtypes(page)).To(Equal([]enums.FPDF_ANNOTATION_SUBTYPE{enums.FPDF_ANNOT_SUBTYPE_WIDGET}))
+
+				// There is nothing left to flatten.
+				FlattenDocument, err = PdfiumInstance.FlattenDocument(&requests.FlattenDocument{
+					Document: doc,
+					Mode:     requests.FlattenDocumentModeAnnotations,
+				})
+				Expect(err).To(BeNil())
+				Expect(FlattenDocument).To(Equal(&responses.FlattenDocument{
+					Pages: []responses.FlattenDocumentPage{
+						{Page: 0, Result: responses.FPDFPage_FlattenResultNothingToDo, Kept: 1},
+					},
+				}))
+			})
+
+			It("saves the flattened document", func() {
+				FlattenDocument, err := PdfiumInstance.FlattenDocument(&requests.FlattenDocument{
+					Document: doc,
+					Mode:     requests.FlattenDocumentModeFormFields,
+					Save:     true,
+				})
+				Expect(err).To(BeNil())
+				Expect(FlattenDocument).To(Not(BeNil()))
+				Expect(FlattenDocument.FileBytes).To(Not(BeNil()))
+				Expect(FlattenDocument.FilePath).To(BeNil())
+
+				savedDoc, err := PdfiumInstance.FPDF_LoadMemDocument(&requests.FPDF_LoadMemDocument{
+					Data: FlattenDocument.FileBytes,
+				})
+				Expect(err).To(BeNil())
+				defer PdfiumInstance.FPDF_CloseDocument(&requests.FPDF_CloseDocument{
+					Document: savedDoc.Document,
+				})
+
+				savedPage := requests.Page{
+					ByIndex: &requests.PageByIndex{
+						Document: savedDoc.Document,
+						Index:    0,
+					},
+				}
+				Expect(getAnnotationSubtypes(savedPage)).To(Equal([]enums.FPDF_ANNOTATION_SUBTYPE{enums.FPDF_ANNOT_SUBTYPE_SQUARE}))
+
+				FPDF_GetPageCount, err := PdfiumInstance.FPDF_GetPageCount(&requests.FPDF_GetPageCount{
+					Document: savedDoc.Document,
+				})
+				Expect(err).To(BeNil())
+				Expect(FPDF_GetPageCount.PageCount).To(Equal(1))
+			})
+		})
+	})
+})
@@ -0,0 +1,155 @@
+//go:build pdfium_experimental
+// +build pdfium_experimental
+
+package shared_tests
+
+import (
+	"github.com/klippa-app/go-pdfium/references"
+	"github.com/klippa-app/go-pdfium/requests"
+	"github.com/klippa-app/go-pdfium/responses"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("fonts_experimental", func() {
+	BeforeEach(func() {
+		Locker.Lock()
+	})
+
+	AfterEach(func() {
+		Locker.Unlock()
+	})
+
+	// fontSummary returns the fields of the fonts that don't depend on the
+	// font that PDFium substitutes.
+	type fontSummary struct {
+		BaseName   string
+		IsEmbedded bool
+		IsSubset   bool
+		Flags      uint32
+		Pages      []int
+		FontData   int
+	}
+
+	summarizeFonts := func(fonts []responses.Font) []fontSummary {
+		summaries := []fontSummary{}
+		for _, font := range fonts {
+			summaries = append(summaries, fontSummary{
+				BaseName:   font.BaseName,
+				IsEmbedded: font.IsEmbedded,
+				IsSubset:   font.IsSubset,
+				Flags:      font.Flags.Flags,
+				Pages:      font.Pages,
+				FontData:   len(font.FontData),
+			})
+		}
+		return summaries
+	}
+
+	Context("a PDF file with embedded fonts", func() {
+		var doc references.FPDF_DOCUMENT
+
+		BeforeEach(func() {
+			doc = openDocument("test.pdf")
+		})
+
+		AfterEach(func() {
+			closeDocument(doc)
+		})
+
+		When("GetFonts is called", func() {
+			It("returns the fonts", func() {
+				GetFonts, err := PdfiumInstance.GetFonts(&requests.GetFonts{
+					Document: doc,
+				})
+				Expect(err).To(BeNil())
+				Expect(GetFonts).To(Not(BeNil()))
+				Expect(GetFonts.Fonts).To(HaveLen(2))
+				Expect(GetFonts.Fonts[0].Weight).To(Equal(400))
+				Expect(summarizeFonts(GetFonts.Fonts)).To(Equal([]fontSummary{
+					{BaseName: "DejaVuSans", IsEmbedded: true, Flags: 32, Pages: []int{0}},
+					{BaseName: "DejaVuSansMono", IsEmbedded: true, Flags: 32, Pages: []int{0}},
+				}))
+			})
+
+			It("returns the font data when requested", func() {
+				GetFonts, err := PdfiumInstance.GetFonts(&requests.GetFonts{
+					Document:        doc,
+					IncludeFontData: true,
+				})
+				Expect(err).To(BeNil())
+				Expect(GetFonts).To(Not(BeNil()))
+				Expect(summarizeFonts(GetFonts.Fonts)).To(Equal([]fontSummary{
+					{BaseName: "DejaVuSans", IsEmbedded: true, Flags: 32, Pages: []int{0}, FontData: 6164},
+					{BaseName: "DejaVuSansMono", IsEmbedded: true, Flags: 32, Pages: []int{0}, FontData: 4980},
+				}))
+			})
+		})
+	})
+
+	Context("a PDF file with multiple pages", func() {
+		var doc references.FPDF_DOCUMENT
+
+		BeforeEach(func() {
+			doc = openDocument("test_multipage.pdf")
+		})
+
+		AfterEach(func() {
+			closeDocument(doc)
+		})
+
+		When("GetFonts is called", func() {
+			It("returns every font once with the pages it is used on", func() {
+				GetFonts, err := PdfiumInstance.GetFonts(&requests.GetFonts{
+					Document: doc,
+				})
+				Expect(err).To(BeNil())
+				Expect(GetFonts).To(Not(BeNil()))
+				Expect(summarizeFonts(GetFonts.Fonts)).To(Equal([]fontSummary{
+					{BaseName: "DejaVuSans", IsEmbedded: true, Flags: 32, Pages: []int{0, 1}},
+					{BaseName: "DejaVuSansMono", IsEmbedded: true, Flags: 32, Pages: []int{0, 1}},
+				}))
+			})
+
+			It("returns the font data of fonts that are used on multiple pages", func() {
+				GetFonts, err := PdfiumInstance.GetFonts(&requests.GetFonts{
+					Document:        doc,
+					IncludeFontData: true,
+				})
+				Expect(err).To(BeNil())
+				Expect(GetFonts).To(Not(BeNil()))
+				Expect(summarizeFonts(GetFonts.Fonts)).To(Equal([]fontSummary{
+					{BaseName: "DejaVuSans", IsEmbedded: true, Flags: 32, Pages: []int{0, 1}, FontData: 6164},
+					{BaseName: "DejaVuSansMono", IsEmbedded: true, Flags: 32, Pages: []int{0, 1}, FontData: 6124},
+				}))
+			})
+		})
+	})
+
+	Context("a PDF file with fonts that are not embedded", func() {
+		var doc references.FPDF_DOCUMENT
+
+		BeforeEach(func() {
+			doc = openDocument("embedded_images.pdf")
+		})
+
+		AfterEach(func() {
+			closeDocument(doc)
+		})
+
+		When("GetFonts is called", func() {
+			It("returns the fonts without font data", func() {
+				GetFonts, err := PdfiumInstance.GetFonts(&requests.GetFonts{
+					Document:        doc,
+					IncludeFontData: true,
+				})
+				Expect(err).To(BeNil())
+				Expect(GetFonts).To(Not(BeNil()))
+				Expect(summarizeFonts(GetFonts.Fonts)).To(Equal([]fontSummary{
+					{BaseName: "BCDEEE+Calibri", IsEmbedded: false, IsSubset: true, Flags: 32, Pages: []int{0}},
+				}))
+			})
+		})
+	})
+})
@@ -0,0 +1,46 @@
+package shared_tests
+
+import (
+	"github.com/klippa-app/go-pdfium/requests"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("form", func() {
+	BeforeEach(func() {
+		Locker.Lock()
+	})
+
+	AfterEach(func() {
+		Locker.Unlock()
+	})
+
+	Context("no document", func() {
+		When("is opened", func() {
+			It("returns an error when calling GetFormFields", func() {
+				GetFormFields, err := PdfiumInstance.GetFormFields(&requests.GetFormFields{})
+				Expect(err).To(MatchError("document not given"))
+				Expect(GetFormFields).To(BeNil())
+			})
+
+			It("returns an error when calling FillFormFields", func() {
+				FillFormFields, err := PdfiumInstance.FillFormFields(&requests.FillFormFields{})
+				Expect(err).To(MatchError("document not given"))
+				Expect(FillFormFields).To(BeNil())
+			})
+
+			It("returns an error when calling ExportFormData", func() {
+				ExportFormData, err := PdfiumInstance.ExportFormData(&requests.ExportFormData{})
+				Expect(err).To(MatchError("document not given"))
+				Expect(ExportFormData).To(BeNil())
+			})
+
+			It("returns an error when calling ImportFormData", func() {
+				ImportFormData, err := PdfiumInstance.ImportFormData(&requests.ImportFormData{})
+				Expect(err).To(MatchError("document not given"))
+				Expect(ImportFormData).To(BeNil())
+			})
+		})
+	})
+})
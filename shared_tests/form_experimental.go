@@ -0,0 +1,617 @@
+//go:build pdfium_experimental
+// +build pdfium_experimental
+
+package shared_tests
+
+import (
+	"github.com/klippa-app/go-pdfium/enums"
+	"github.com/klippa-app/go-pdfium/references"
+	"github.com/klippa-app/go-pdfium/requests"
+	"github.com/klippa-app/go-pdfium/responses"
+	"github.com/klippa-app/go-pdfium/structs"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("form_experimental", func() {
+	BeforeEach(func() {
+		Locker.Lock()
+	})
+
+	AfterEach(func() {
+		Locker.Unlock()
+	})
+
+	Context("a PDF file without form", func() {
+		var doc references.FPDF_DOCUMENT
+
+		BeforeEach(func() {
+			doc = openDocument("test.pdf")
+		})
+
+		AfterEach(func() {
+			closeDocument(doc)
+		})
+
+		When("GetFormFields is called", func() {
+			It("returns no fields", func() {
+				GetFormFields, err := PdfiumInstance.GetFormFields(&requests.GetFormFields{
+					Document: doc,
+				})
+				Expect(err).To(BeNil())
+				Expect(GetFormFields).To(Equal(&responses.GetFormFields{
+					Fields: []responses.FormField{},
+				}))
+			})
+		})
+	})
+
+	Context("a PDF file with checkboxes and radio buttons", func() {
+		var doc references.FPDF_DOCUMENT
+
+		BeforeEach(func() {
+			doc = openDocument("click_form.pdf")
+		})
+
+		AfterEach(func() {
+			closeDocument(doc)
+		})
+
+		When("GetFormFields is called", func() {
+			It("returns the fields with their export values and widgets", func() {
+				GetFormFields, err := PdfiumInstance.GetFormFields(&requests.GetFormFields{
+					Document: doc,
+				})
+				Expect(err).To(BeNil())
+				Expect(GetFormFields).To(Not(BeNil()))
+				Expect(GetFormFields.Fields).To(HaveLen(4))
+
+				Expect(GetFormFields.Fields[0]).To(Equal(responses.FormField{
+					Name:          "readOnlyCheckbox",
+					AlternateName: "readOnlyCheckbox",
+					Type:          enums.FPDF_FORMFIELD_TYPE_CHECKBOX,
+					Flags:         enums.FPDF_FORMFLAG_READONLY,
+					Value:         "Yes",
+					ExportValues:  []string{"Yes"},
+					Options:       []responses.FormFieldOption{},
+					Widgets: []responses.FormFieldWidget{
+						{
+							Page:        0,
+							Rect:        structs.FPDF_FS_RECTF{Left: 135, Top: 270, Right: 155, Bottom: 250},
+							ExportValue: "Yes",
+							IsChecked:   true,
+						},
+					},
+				}))
+
+				Expect(GetFormFields.Fields[1].Name).To(Equal("checkbox"))
+				Expect(GetFormFields.Fields[1].Flags).To(Equal(enums.FPDF_FORMFLAG_REQUIRED))
+				Expect(GetFormFields.Fields[1].Value).To(Equal("Off"))
+				Expect(GetFormFields.Fields[1].Widgets[0].IsChecked).To(BeFalse())
+
+				radioButton := GetFormFields.Fields[3]
+				Expect(radioButton.Name).To(Equal("radioButton"))
+				Expect(radioButton.AlternateName).To(Equal("radioButton1"))
+				Expect(radioButton.Type).To(Equal(enums.FPDF_FORMFIELD_TYPE_RADIOBUTTON))
+				Expect(radioButton.Value).To(Equal("value3"))
+				Expect(radioButton.ExportValues).To(Equal([]string{"value1", "value2", "value3"}))
+				Expect(radioButton.Widgets).To(Equal([]responses.FormFieldWidget{
+					{
+						Page:        0,
+						Rect:        structs.FPDF_FS_RECTF{Left: 85, Top: 70, Right: 105, Bottom: 50},
+						ExportValue: "value1",
+					},
+					{
+						Page:        0,
+						Rect:        structs.FPDF_FS_RECTF{Left: 135, Top: 70, Right: 155, Bottom: 50},
+						ExportValue: "value2",
+					},
+					{
+						Page:        0,
+						Rect:        structs.FPDF_FS_RECTF{Left: 185, Top: 70, Right: 205, Bottom: 50},
+						ExportValue: "value3",
+						IsChecked:   true,
+					},
+				}))
+			})
+		})
+
+		When("FillFormFields is called", func() {
+			It("checks the checkboxes and radio buttons", func() {
+				checked := true
+				exportValue := "value1"
+				FillFormFields, err := PdfiumInstance.FillFormFields(&requests.FillFormFields{
+					Document: doc,
+					Values: map[string]requests.FillFormFieldsValue{
+						"checkbox":         {Checked: &checked},
+						"radioButton":      {ExportValue: &exportValue},
+						"readOnlyCheckbox": {Checked: &checked},
+					},
+				})
+				Expect(err).To(BeNil())
+				Expect(FillFormFields).To(Equal(&responses.FillFormFields{
+					Filled:   []string{"checkbox", "radioButton"},
+					NotFound: []string{},
+					Rejected: []responses.FillFormFieldsRejectedField{
+						{Name: "readOnlyCheckbox", Reason: "the field is read only"},
+					},
+				}))
+
+				GetFormFields, err := PdfiumInstance.GetFormFields(&requests.GetFormFields{
+					Document: doc,
+				})
+				Expect(err).To(BeNil())
+				Expect(GetFormFields.Fields[1].Value).To(Equal("Yes"))
+				Expect(GetFormFields.Fields[3].Value).To(Equal("value1"))
+				Expect(GetFormFields.Fields[3].Widgets[0].IsChecked).To(BeTrue())
+				Expect(GetFormFields.Fields[3].Widgets[2].IsChecked).To(BeFalse())
+			})
+
+			It("rejects an export value that doesn't exist", func() {
+				exportValue := "value4"
+				FillFormFields, err := PdfiumInstance.FillFormFields(&requests.FillFormFields{
+					Document: doc,
+					Values: map[string]requests.FillFormFieldsValue{
+						"radioButton": {ExportValue: &exportValue},
+					},
+				})
+				Expect(err).To(BeNil())
+				Expect(FillFormFields.Filled).To(BeEmpty())
+				Expect(FillFormFields.Rejected).To(Equal([]responses.FillFormFieldsRejectedField{
+					{Name: "radioButton", Reason: "the export value \"value4\" doesn't exist"},
+				}))
+			})
+		})
+
+		When("ExportFormData is called", func() {
+			It("returns the states of the checkboxes and radio buttons as FDF", func() {
+				ExportFormData, err := PdfiumInstance.ExportFormData(&requests.ExportFormData{
+					Document: doc,
+					Format:   requests.FormDataFormatFDF,
+					FileName: "click_form.pdf",
+				})
+				Expect(err).To(BeNil())
+				Expect(ExportFormData).To(Not(BeNil()))
+				Expect(string(ExportFormData.Data)).To(HavePrefix("%FDF-1.2"))
+				Expect(string(ExportFormData.Data)).To(ContainSubstring("<< /FDF << /F (click_form.pdf) /Fields [<< /T (readOnlyCheckbox) /V /Yes >> << /T (checkbox) /V /Off >> << /T (readOnlyRadioButton) /V /value3 >> << /T (radioButton) /V /value3 >>] >> >>"))
+			})
+
+			It("returns the states of the checkboxes and radio buttons as XFDF", func() {
+				ExportFormData, err := PdfiumInstance.ExportFormData(&requests.ExportFormData{
+					Document: doc,
+					Format:   requests.FormDataFormatXFDF,
+				})
+				Expect(err).To(BeNil())
+				Expect(ExportFormData).To(Not(BeNil()))
+				Expect(string(ExportFormData.Data)).To(Equal(`<?xml version="1.0" encoding="UTF-8"?>
+<xfdf xmlns="http://ns.adobe.com/xfdf/" xml:space="preserve">
+  <fields>
+    <field name="readOnlyCheckbox">
+      <value>Yes</value>
+    </field>
+    <field name="checkbox">
+      <value>Off</value>
+    </field>
+    <field name="readOnlyRadioButton">
+      <value>value3</value>
+    </field>
+    <field name="radioButton">
+      <value>value3</value>
+    </field>
+  </fields>
+</xfdf>
+`))
+			})
+
+			It("returns an error when an invalid format is given", func() {
+				ExportFormData, err := PdfiumInstance.ExportFormData(&requests.ExportFormData{
+					Document: doc,
+					Format:   "json",
+				})
+				Expect(err).To(MatchError("invalid form data format json given"))
+				Expect(ExportFormData).To(BeNil())
+			})
+		})
+
+		When("ImportFormData is called", func() {
+			It("checks the checkboxes and radio buttons from FDF", func() {
+				ImportFormData, err := PdfiumInstance.ImportFormData(&requests.ImportFormData{
+					Document: doc,
+					Data:     []byte("%FDF-1.2\n1 0 obj\n<< /FDF << /F (click_form.pdf) /Fields [<< /T (checkbox) /V /Yes >> << /T (radioButton) /V /value2 >> << /T (unknown) /V (test) >>] >> >>\nendobj\ntrailer\n<< /Root 1 0 R >>\n%%EOF\n"),
+				})
+				Expect(err).To(BeNil())
+				Expect(ImportFormData).To(Equal(&responses.ImportFormData{
+					FileName: "click_form.pdf",
+					Filled:   []string{"checkbox", "radioButton"},
+					NotFound: []string{"unknown"},
+					Rejected: []responses.FillFormFieldsRejectedField{},
+				}))
+
+				GetFormFields, err := PdfiumInstance.GetFormFields(&requests.GetFormFields{
+					Document: doc,
+				})
+				Expect(err).To(BeNil())
+				Expect(GetFormFields.Fields[1].Value).To(Equal("Yes"))
+				Expect(GetFormFields.Fields[3].Value).To(Equal("value2"))
+			})
+
+			It("imports the form data that is exported from the same form", func() {
+				ExportFormData, err := PdfiumInstance.ExportFormData(&requests.ExportFormData{
+					Document: doc,
+					Format:   requests.FormDataFormatFDF,
+				})
+				Expect(err).To(BeNil())
+
+				otherDoc := openDocument("click_form.pdf")
+				defer closeDocument(otherDoc)
+
+				ImportFormData, err := PdfiumInstance.ImportFormData(&requests.ImportFormData{
+					Document: otherDoc,
+					Data:     ExportFormData.Data,
+				})
+				Expect(err).To(BeNil())
+				Expect(ImportFormData).To(Equal(&responses.ImportFormData{
+					Filled:   []string{"checkbox", "radioButton"},
+					NotFound: []string{},
+					Rejected: []responses.FillFormFieldsRejectedField{
+						{Name: "readOnlyCheckbox", Reason: "the field is read only"},
+						{Name: "readOnlyRadioButton", Reason: "the field is read only"},
+					},
+				}))
+
+				GetFormFields, err := PdfiumInstance.GetFormFields(&requests.GetFormFields{
+					Document: otherDoc,
+				})
+				Expect(err).To(BeNil())
+				Expect(GetFormFields.Fields[1].Value).To(Equal("Off"))
+				Expect(GetFormFields.Fields[3].Value).To(Equal("value3"))
+			})
+
+			It("rejects unchecking a radio button group when the form doesn't allow it", func() {
+				ImportFormData, err := PdfiumInstance.ImportFormData(&requests.ImportFormData{
+					Document: doc,
+					Data:     []byte("%FDF-1.2\n1 0 obj\n<< /FDF << /Fields [<< /T (radioButton) /V /Off >>] >> >>\nendobj\ntrailer\n<< /Root 1 0 R >>\n%%EOF\n"),
+				})
+				Expect(err).To(BeNil())
+				Expect(ImportFormData).To(Equal(&responses.ImportFormData{
+					Filled:   []string{},
+					NotFound: []string{},
+					Rejected: []responses.FillFormFieldsRejectedField{
+						{Name: "radioButton", Reason: "the checked state was not changed by the form"},
+					},
+				}))
+			})
+
+			It("returns an error when the format can't be detected", func() {
+				ImportFormData, err := PdfiumInstance.ImportFormData(&requests.ImportFormData{
+					Document: doc,
+					Data:     []byte("{}"),
+				})
+				Expect(err).To(MatchError("could not detect the format of the form data"))
+				Expect(ImportFormData).To(BeNil())
+			})
+		})
+	})
+
+	Context("a PDF file with listboxes", func() {
+		var doc references.FPDF_DOCUMENT
+
+		BeforeEach(func() {
+			doc = openDocument("listbox_form.pdf")
+		})
+
+		AfterEach(func() {
+			closeDocument(doc)
+		})
+
+		When("GetFormFields is called", func() {
+			It("returns the fields with their options", func() {
+				GetFormFields, err := PdfiumInstance.GetFormFields(&requests.GetFormFields{
+					Document: doc,
+				})
+				Expect(err).To(BeNil())
+				Expect(GetFormFields).To(Not(BeNil()))
+				Expect(GetFormFields.Fields).To(HaveLen(7))
+
+				Expect(GetFormFields.Fields[3]).To(Equal(responses.FormField{
+					Name:         "Listbox_MultiSelectMultipleIndices",
+					Type:         enums.FPDF_FORMFIELD_TYPE_LISTBOX,
+					Flags:        enums.FPDF_FORMFLAG_CHOICE_MULTI_SELECT,
+					ExportValues: []string{},
+					Options: []responses.FormFieldOption{
+						{Label: "Albania"},
+						{Label: "Belgium", IsSelected: true},
+						{Label: "Croatia"},
+						{Label: "Denmark", IsSelected: true},
+						{Label: "Estonia"},
+					},
+					Widgets: []responses.FormFieldWidget{
+						{
+							Page: 0,
+							Rect: structs.FPDF_FS_RECTF{Left: 100, Top: 280, Right: 200, Bottom: 250},
+						},
+					},
+				}))
+			})
+		})
+
+		When("FillFormFields is called", func() {
+			It("selects the options", func() {
+				FillFormFields, err := PdfiumInstance.FillFormFields(&requests.FillFormFields{
+					Document: doc,
+					Values: map[string]requests.FillFormFieldsValue{
+						"Listbox_SingleSelect":               {Selected: []string{"Bar"}},
+						"Listbox_MultiSelectMultipleIndices": {Selected: []string{"Albania", "Estonia"}},
+					},
+				})
+				Expect(err).To(BeNil())
+				Expect(FillFormFields).To(Equal(&responses.FillFormFields{
+					Filled:   []string{"Listbox_SingleSelect", "Listbox_MultiSelectMultipleIndices"},
+					NotFound: []string{},
+					Rejected: []responses.FillFormFieldsRejectedField{},
+				}))
+
+				GetFormFields, err := PdfiumInstance.GetFormFields(&requests.GetFormFields{
+					Document: doc,
+				})
+				Expect(err).To(BeNil())
+				Expect(GetFormFields.Fields[0].Options[1]).To(Equal(responses.FormFieldOption{Label: "Bar", IsSelected: true}))
+				Expect(GetFormFields.Fields[3].Options).To(Equal([]responses.FormFieldOption{
+					{Label: "Albania", IsSelected: true},
+					{Label: "Belgium"},
+					{Label: "Croatia"},
+					{Label: "Denmark"},
+					{Label: "Estonia", IsSelected: true},
+				}))
+			})
+
+			It("rejects values that don't fit the field", func() {
+				text := "Foo"
+				FillFormFields, err := PdfiumInstance.FillFormFields(&requests.FillFormFields{
+					Document: doc,
+					Values: map[string]requests.FillFormFieldsValue{
+						"Listbox_SingleSelect":             {Selected: []string{"Foo", "Bar"}},
+						"Listbox_MultiSelect":              {Selected: []string{"Pineapple"}},
+						"Listbox_SingleSelectLastSelected": {Text: &text},
+					},
+				})
+				Expect(err).To(BeNil())
+				Expect(FillFormFields).To(Equal(&responses.FillFormFields{
+					Filled:   []string{},
+					NotFound: []string{},
+					Rejected: []responses.FillFormFieldsRejectedField{
+						{Name: "Listbox_SingleSelect", Reason: "the listbox doesn't allow multiple selected options"},
+						{Name: "Listbox_MultiSelect", Reason: "the option \"Pineapple\" doesn't exist"},
+						{Name: "Listbox_SingleSelectLastSelected", Reason: "only an editable combobox can be filled with text"},
+					},
+				}))
+			})
+		})
+
+		When("the form data is exported and imported", func() {
+			It("keeps the listboxes without selected options", func() {
+				FillFormFields, err := PdfiumInstance.FillFormFields(&requests.FillFormFields{
+					Document: doc,
+					Values: map[string]requests.FillFormFieldsValue{
+						"Listbox_MultiSelectMultipleIndices": {Selected: []string{}},
+					},
+				})
+				Expect(err).To(BeNil())
+				Expect(FillFormFields.Rejected).To(BeEmpty())
+
+				ExportFormData, err := PdfiumInstance.ExportFormData(&requests.ExportFormData{
+					Document: doc,
+					Format:   requests.FormDataFormatXFDF,
+				})
+				Expect(err).To(BeNil())
+
+				otherDoc := openDocument("listbox_form.pdf")
+				defer closeDocument(otherDoc)
+
+				ImportFormData, err := PdfiumInstance.ImportFormData(&requests.ImportFormData{
+					Document: otherDoc,
+					Data:     ExportFormData.Data,
+				})
+				Expect(err).To(BeNil())
+				Expect(ImportFormData).To(Equal(&responses.ImportFormData{
+					Filled: []string{
+						"Listbox_SingleSelect",
+						"Listbox_MultiSelect",
+						"Listbox_MultiSelectMultipleIndices",
+						"Listbox_MultiSelectMultipleValues",
+						"Listbox_MultiSelectMultipleMismatch",
+						"Listbox_SingleSelectLastSelected",
+					},
+					NotFound: []string{},
+					Rejected: []responses.FillFormFieldsRejectedField{
+						{Name: "Listbox_ReadOnly", Reason: "the field is read only"},
+					},
+				}))
+
+				GetFormFields, err := PdfiumInstance.GetFormFields(&requests.GetFormFields{
+					Document: otherDoc,
+				})
+				Expect(err).To(BeNil())
+				Expect(GetFormFields.Fields[3].Options).To(Equal([]responses.FormFieldOption{
+					{Label: "Albania"},
+					{Label: "Belgium"},
+					{Label: "Croatia"},
+					{Label: "Denmark"},
+					{Label: "Estonia"},
+				}))
+			})
+		})
+	})
+
+	Context("a PDF file with comboboxes", func() {
+		var doc references.FPDF_DOCUMENT
+
+		BeforeEach(func() {
+			doc = openDocument("combobox_form.pdf")
+		})
+
+		AfterEach(func() {
+			closeDocument(doc)
+		})
+
+		When("FillFormFields is called", func() {
+			It("selects the option or sets the text of an editable combobox", func() {
+				text := "Custom"
+				FillFormFields, err := PdfiumInstance.FillFormFields(&requests.FillFormFields{
+					Document: doc,
+					Values: map[string]requests.FillFormFieldsValue{
+						"Combo_Editable": {Text: &text},
+						"Combo1":         {Selected: []string{"Cherry"}},
+					},
+				})
+				Expect(err).To(BeNil())
+				Expect(FillFormFields).To(Equal(&responses.FillFormFields{
+					Filled:   []string{"Combo_Editable", "Combo1"},
+					NotFound: []string{},
+					Rejected: []responses.FillFormFieldsRejectedField{},
+				}))
+
+				GetFormFields, err := PdfiumInstance.GetFormFields(&requests.GetFormFields{
+					Document: doc,
+				})
+				Expect(err).To(BeNil())
+				Expect(GetFormFields.Fields[0].Value).To(Equal("Custom"))
+				Expect(GetFormFields.Fields[1].Value).To(Equal("Cherry"))
+			})
+		})
+	})
+
+	Context("a PDF file with text fields", func() {
+		var doc references.FPDF_DOCUMENT
+
+		BeforeEach(func() {
+			doc = openDocument("text_form_multiple.pdf")
+		})
+
+		AfterEach(func() {
+			closeDocument(doc)
+		})
+
+		When("GetFormFields is called", func() {
+			It("returns the fields with their values", func() {
+				GetFormFields, err := PdfiumInstance.GetFormFields(&requests.GetFormFields{
+					Document: doc,
+				})
+				Expect(err).To(BeNil())
+				Expect(GetFormFields).To(Not(BeNil()))
+				Expect(GetFormFields.Fields).To(HaveLen(4))
+				Expect(GetFormFields.Fields[1].Name).To(Equal("ReadOnly"))
+				Expect(GetFormFields.Fields[1].Flags).To(Equal(enums.FPDF_FORMFLAG_READONLY))
+				Expect(GetFormFields.Fields[2].Name).To(Equal("CharLimit"))
+				Expect(GetFormFields.Fields[2].Type).To(Equal(enums.FPDF_FORMFIELD_TYPE_TEXTFIELD))
+				Expect(GetFormFields.Fields[2].Value).To(Equal("Elephant"))
+			})
+		})
+
+		When("FillFormFields is called", func() {
+			It("fills the text fields and reports the fields that were not filled", func() {
+				text := "Hello"
+				longText := "Hippopotamus"
+				FillFormFields, err := PdfiumInstance.FillFormFields(&requests.FillFormFields{
+					Document: doc,
+					Values: map[string]requests.FillFormFieldsValue{
+						"Text Box":  {Text: &text},
+						"ReadOnly":  {Text: &text},
+						"CharLimit": {Text: &longText},
+						"Unknown":   {Text: &text},
+						"Password":  {Selected: []string{"Hello"}},
+					},
+				})
+				Expect(err).To(BeNil())
+				Expect(FillFormFields).To(Equal(&responses.FillFormFields{
+					Filled:   []string{"Text Box"},
+					NotFound: []string{"Unknown"},
+					Rejected: []responses.FillFormFieldsRejectedField{
+						{Name: "ReadOnly", Reason: "the field is read only"},
+						{Name: "CharLimit", Reason: "the value was changed to \"Hippopotam\" by the form"},
+						{Name: "Password", Reason: "a text field can only be filled with text"},
+					},
+				}))
+
+				GetFormFields, err := PdfiumInstance.GetFormFields(&requests.GetFormFields{
+					Document: doc,
+				})
+				Expect(err).To(BeNil())
+				Expect(GetFormFields.Fields[0].Value).To(Equal("Hello"))
+
+				// The value that the form changed is not kept.
+				Expect(GetFormFields.Fields[2].Value).To(Equal("Elephant"))
+			})
+
+			It("flattens and saves the document", func() {
+				text := "Hello"
+				FillFormFields, err := PdfiumInstance.FillFormFields(&requests.FillFormFields{
+					Document: doc,
+					Values: map[string]requests.FillFormFieldsValue{
+						"Text Box": {Text: &text},
+					},
+					Flatten: true,
+					Save:    true,
+				})
+				Expect(err).To(BeNil())
+				Expect(FillFormFields.Filled).To(Equal([]string{"Text Box"}))
+				Expect(FillFormFields.FileBytes).To(Not(BeNil()))
+
+				savedDoc, err := PdfiumInstance.FPDF_LoadMemDocument(&requests.FPDF_LoadMemDocument{
+					Data: FillFormFields.FileBytes,
+				})
+				Expect(err).To(BeNil())
+				defer closeDocument(savedDoc.Document)
+
+				GetFormFields, err := PdfiumInstance.GetFormFields(&requests.GetFormFields{
+					Document: savedDoc.Document,
+				})
+				Expect(err).To(BeNil())
+				Expect(GetFormFields.Fields).To(BeEmpty())
+			})
+		})
+
+		When("the form data is exported and imported", func() {
+			It("fills the fields of another document with the same values", func() {
+				text := "Hello"
+				_, err := PdfiumInstance.FillFormFields(&requests.FillFormFields{
+					Document: doc,
+					Values: map[string]requests.FillFormFieldsValue{
+						"Text Box": {Text: &text},
+					},
+				})
+				Expect(err).To(BeNil())
+
+				ExportFormData, err := PdfiumInstance.ExportFormData(&requests.ExportFormData{
+					Document: doc,
+					Format:   requests.FormDataFormatXFDF,
+				})
+				Expect(err).To(BeNil())
+
+				otherDoc := openDocument("text_form_multiple.pdf")
+				defer closeDocument(otherDoc)
+
+				ImportFormData, err := PdfiumInstance.ImportFormData(&requests.ImportFormData{
+					Document: otherDoc,
+					Data:     ExportFormData.Data,
+				})
+				Expect(err).To(BeNil())
+				Expect(ImportFormData).To(Equal(&responses.ImportFormData{
+					Filled:   []string{"Text Box", "CharLimit", "Password"},
+					NotFound: []string{},
+					Rejected: []responses.FillFormFieldsRejectedField{
+						{Name: "ReadOnly", Reason: "the field is read only"},
+					},
+				}))
+
+				GetFormFields, err := PdfiumInstance.GetFormFields(&requests.GetFormFields{
+					Document: otherDoc,
+				})
+				Expect(err).To(BeNil())
+				Expect(GetFormFields.Fields[0].Value).To(Equal("Hello"))
+				Expect(GetFormFields.Fields[2].Value).To(Equal("Elephant"))
+			})
+		})
+	})
+})
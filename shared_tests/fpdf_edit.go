@@ -489,6 +489,34 @@ var _ = Describe("fpdf_edit", func() {
 				Expect(FPDFPage_Delete).To(Equal(&responses.FPDFPage_Delete{}))
 			})
 
+			It("doesn't reuse a loaded page after removing it", func() {
+				page := requests.Page{
+					ByIndex: &requests.PageByIndex{
+						Document: doc,
+						Index:    0,
+					},
+				}
+
+				FPDF_GetPageWidth, err := PdfiumInstance.FPDF_GetPageWidth(&requests.FPDF_GetPageWidth{
+					Page: page,
+				})
+				Expect(err).To(BeNil())
+				Expect(FPDF_GetPageWidth).To(Not(BeNil()))
+
+				FPDFPage_Delete, err := PdfiumInstance.FPDFPage_Delete(&requests.FPDFPage_Delete{
+					Document:  doc,
+					PageIndex: 0,
+				})
+				Expect(err).To(BeNil())
+				Expect(FPDFPage_Delete).To(Equal(&responses.FPDFPage_Delete{}))
+
+				FPDF_GetPageWidth, err = PdfiumInstance.FPDF_GetPageWidth(&requests.FPDF_GetPageWidth{
+					Page: page,
+				})
+				Expect(err).To(MatchError("6: incorrect page"))
+				Expect(FPDF_GetPageWidth).To(BeNil())
+			})
+
 			It("gives an error when inserting an invalid object", func() {
 				FPDFPage_InsertObject, err := PdfiumInstance.FPDFPage_InsertObject(&requests.FPDFPage_InsertObject{
 					Page: requests.Page{
@@ -2261,4 +2261,157 @@ var _ = Describe("fpdf_formfill_experimental", func() {
 			})
 		})
 	})
+
+	Context("a PDF file with a form listbox", func() {
+		var doc references.FPDF_DOCUMENT
+		var formHandle references.FPDF_FORMHANDLE
+
+		BeforeEach(func() {
+			pdfData, err := ioutil.ReadFile(TestDataPath + "/testdata/listbox_form.pdf")
+			Expect(err).To(BeNil())
+
+			newDoc, err := PdfiumInstance.FPDF_LoadMemDocument(&requests.FPDF_LoadMemDocument{
+				Data: &pdfData,
+			})
+			Expect(err).To(BeNil())
+
+			doc = newDoc.Document
+
+			// The optional callbacks, like FFI_OnChange and
+			// FFI_OutputSelectedRect, are not given.
+			FPDFDOC_InitFormFillEnvironment, err := PdfiumInstance.FPDFDOC_InitFormFillEnvironment(&requests.FPDFDOC_InitFormFillEnvironment{
+				Document: doc,
+				FormFillInfo: structs.FPDF_FORMFILLINFO{
+					FFI_Invalidate: func(page references.FPDF_PAGE, left, top, right, bottom float64) {},
+					FFI_SetCursor:  func(cursorType enums.FXCT) {},
+					FFI_SetTimer: func(elapse int, timerFunc func(idEvent int)) int {
+						return 0
+					},
+					FFI_KillTimer: func(timerID int) {},
+					FFI_GetLocalTime: func() structs.FPDF_SYSTEMTIME {
+						return structs.FPDF_SYSTEMTIME{}
+					},
+					FFI_GetPage: func(document references.FPDF_DOCUMENT, index int) *references.FPDF_PAGE {
+						return nil
+					},
+					FFI_GetRotation: func(page references.FPDF_PAGE) enums.FPDF_PAGE_ROTATION {
+						return enums.FPDF_PAGE_ROTATION_NONE
+					},
+					FFI_ExecuteNamedAction: func(namedAction string) {},
+				},
+			})
+			Expect(err).To(BeNil())
+			Expect(FPDFDOC_InitFormFillEnvironment).ToNot(BeNil())
+			formHandle = FPDFDOC_InitFormFillEnvironment.FormHandle
+		})
+
+		AfterEach(func() {
+			FPDFDOC_ExitFormFillEnvironment, err := PdfiumInstance.FPDFDOC_ExitFormFillEnvironment(&requests.FPDFDOC_ExitFormFillEnvironment{
+				FormHandle: formHandle,
+			})
+			Expect(err).To(BeNil())
+			Expect(FPDFDOC_ExitFormFillEnvironment).To(Not(BeNil()))
+
+			FPDF_CloseDocument, err := PdfiumInstance.FPDF_CloseDocument(&requests.FPDF_CloseDocument{
+				Document: doc,
+			})
+			Expect(err).To(BeNil())
+			Expect(FPDF_CloseDocument).To(Not(BeNil()))
+		})
+
+		When("is opened", func() {
+			It("selects and deselects options of a multi select listbox", func() {
+				FPDF_LoadPage, err := PdfiumInstance.FPDF_LoadPage(&requests.FPDF_LoadPage{
+					Document: doc,
+					Index:    0,
+				})
+				Expect(err).To(BeNil())
+
+				page := requests.Page{
+					ByReference: &FPDF_LoadPage.Page,
+				}
+
+				_, err = PdfiumInstance.FORM_OnAfterLoadPage(&requests.FORM_OnAfterLoadPage{
+					Page:       page,
+					FormHandle: formHandle,
+				})
+				Expect(err).To(BeNil())
+
+				FPDFPage_GetAnnotCount, err := PdfiumInstance.FPDFPage_GetAnnotCount(&requests.FPDFPage_GetAnnotCount{
+					Page: page,
+				})
+				Expect(err).To(BeNil())
+
+				var listbox references.FPDF_ANNOTATION
+				for i := 0; i < FPDFPage_GetAnnotCount.Count && listbox == ""; i++ {
+					FPDFPage_GetAnnot, err := PdfiumInstance.FPDFPage_GetAnnot(&requests.FPDFPage_GetAnnot{
+						Page:  page,
+						Index: i,
+					})
+					Expect(err).To(BeNil())
+
+					FPDFAnnot_GetFormFieldName, err := PdfiumInstance.FPDFAnnot_GetFormFieldName(&requests.FPDFAnnot_GetFormFieldName{
+						FormHandle: formHandle,
+						Annotation: FPDFPage_GetAnnot.Annotation,
+					})
+					Expect(err).To(BeNil())
+
+					if FPDFAnnot_GetFormFieldName.FormFieldName == "Listbox_MultiSelect" {
+						listbox = FPDFPage_GetAnnot.Annotation
+						continue
+					}
+
+					_, err = PdfiumInstance.FPDFPage_CloseAnnot(&requests.FPDFPage_CloseAnnot{
+						Annotation: FPDFPage_GetAnnot.Annotation,
+					})
+					Expect(err).To(BeNil())
+				}
+				Expect(listbox).ToNot(BeEmpty())
+
+				FORM_SetFocusedAnnot, err := PdfiumInstance.FORM_SetFocusedAnnot(&requests.FORM_SetFocusedAnnot{
+					FormHandle: formHandle,
+					Annotation: listbox,
+				})
+				Expect(err).To(BeNil())
+				Expect(FORM_SetFocusedAnnot).To(Equal(&responses.FORM_SetFocusedAnnot{}))
+
+				for _, selected := range []bool{true, false} {
+					FORM_SetIndexSelected, err := PdfiumInstance.FORM_SetIndexSelected(&requests.FORM_SetIndexSelected{
+						FormHandle: formHandle,
+						Page:       page,
+						Index:      1,
+						Selected:   selected,
+					})
+					Expect(err).To(BeNil())
+					Expect(FORM_SetIndexSelected).To(Equal(&responses.FORM_SetIndexSelected{}))
+
+					FORM_IsIndexSelected, err := PdfiumInstance.FORM_IsIndexSelected(&requests.FORM_IsIndexSelected{
+						FormHandle: formHandle,
+						Page:       page,
+						Index:      1,
+					})
+					Expect(err).To(BeNil())
+					Expect(FORM_IsIndexSelected).To(Equal(&responses.FORM_IsIndexSelected{
+						IsIndexSelected: selected,
+					}))
+				}
+
+				_, err = PdfiumInstance.FPDFPage_CloseAnnot(&requests.FPDFPage_CloseAnnot{
+					Annotation: listbox,
+				})
+				Expect(err).To(BeNil())
+
+				_, err = PdfiumInstance.FORM_OnBeforeClosePage(&requests.FORM_OnBeforeClosePage{
+					Page:       page,
+					FormHandle: formHandle,
+				})
+				Expect(err).To(BeNil())
+
+				_, err = PdfiumInstance.FPDF_ClosePage(&requests.FPDF_ClosePage{
+					Page: FPDF_LoadPage.Page,
+				})
+				Expect(err).To(BeNil())
+			})
+		})
+	})
 })
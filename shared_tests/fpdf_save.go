@@ -6,6 +6,7 @@ import (
 	"io/ioutil"
 	"os"
 
+	"github.com/klippa-app/go-pdfium/errors"
 	"github.com/klippa-app/go-pdfium/references"
 	"github.com/klippa-app/go-pdfium/requests"
 
@@ -177,4 +178,255 @@ var _ = Describe("fpdf_save", func() {
 			})
 		})
 	})
+
+	Context("a PDF file that is encrypted when saving", func() {
+		var doc references.FPDF_DOCUMENT
+
+		BeforeEach(func() {
+			pdfData, err := ioutil.ReadFile(TestDataPath + "/testdata/test.pdf")
+			Expect(err).To(BeNil())
+
+			newDoc, err := PdfiumInstance.FPDF_LoadMemDocument(&requests.FPDF_LoadMemDocument{
+				Data: &pdfData,
+			})
+			Expect(err).To(BeNil())
+
+			doc = newDoc.Document
+		})
+
+		AfterEach(func() {
+			FPDF_CloseDocument, err := PdfiumInstance.FPDF_CloseDocument(&requests.FPDF_CloseDocument{
+				Document: doc,
+			})
+			Expect(err).To(BeNil())
+			Expect(FPDF_CloseDocument).To(Not(BeNil()))
+		})
+
+		saveEncrypted := func(encryption requests.SaveEncryption) *[]byte {
+			FPDF_SaveAsCopy, err := PdfiumInstance.FPDF_SaveAsCopy(&requests.FPDF_SaveAsCopy{
+				Document:   doc,
+				Encryption: &encryption,
+			})
+			Expect(err).To(BeNil())
+			Expect(FPDF_SaveAsCopy.FileBytes).To(Not(BeNil()))
+
+			return FPDF_SaveAsCopy.FileBytes
+		}
+
+		expectReadable := func(encryptedDoc references.FPDF_DOCUMENT) {
+			pageText, err := PdfiumInstance.GetPageText(&requests.GetPageText{
+				Page: requests.Page{
+					ByIndex: &requests.PageByIndex{
+						Document: encryptedDoc,
+						Index:    0,
+					},
+				},
+			})
+			Expect(err).To(BeNil())
+			Expect(pageText).To(Equal(&responses.GetPageText{
+				Text: "File: Untitled Document 2 Page 1 of 1\r\nThis is a test PDF",
+			}))
+		}
+
+		It("returns an error when an invalid encryption method is given", func() {
+			FPDF_SaveAsCopy, err := PdfiumInstance.FPDF_SaveAsCopy(&requests.FPDF_SaveAsCopy{
+				Document: doc,
+				Encryption: &requests.SaveEncryption{
+					Method: "rc4",
+				},
+			})
+			Expect(err).To(MatchError("invalid encryption method rc4 given"))
+			Expect(FPDF_SaveAsCopy).To(BeNil())
+		})
+
+		It("returns an error when encrypting an incremental save", func() {
+			FPDF_SaveWithVersion, err := PdfiumInstance.FPDF_SaveWithVersion(&requests.FPDF_SaveWithVersion{
+				Document: doc,
+				Flags:    requests.SaveFlagIncremental,
+				Encryption: &requests.SaveEncryption{
+					Method: requests.SaveEncryptionMethodAES128,
+				},
+			})
+			Expect(err).To(MatchError("encryption can't be combined with incremental saving"))
+			Expect(FPDF_SaveWithVersion).To(BeNil())
+		})
+
+		for _, method := range []requests.SaveEncryptionMethod{requests.SaveEncryptionMethodAES128, requests.SaveEncryptionMethodAES256} {
+			method := method
+			expectedRevision := 4
+			if method == requests.SaveEncryptionMethodAES256 {
+				expectedRevision = 6
+			}
+
+			Context("with "+string(method)+" encryption", func() {
+				var encryptedData *[]byte
+
+				BeforeEach(func() {
+					encryptedData = saveEncrypted(requests.SaveEncryption{
+						Method:        method,
+						UserPassword:  "user",
+						OwnerPassword: "owner",
+						Permissions:   requests.SavePermissionPrint | requests.SavePermissionCopy,
+					})
+				})
+
+				It("can't be opened without a password", func() {
+					encryptedDoc, err := PdfiumInstance.FPDF_LoadMemDocument(&requests.FPDF_LoadMemDocument{
+						Data: encryptedData,
+					})
+					Expect(err).To(MatchError(errors.ErrPassword.Error()))
+					Expect(encryptedDoc).To(BeNil())
+				})
+
+				It("can be opened with the user password and has the given permissions", func() {
+					password := "user"
+					encryptedDoc, err := PdfiumInstance.FPDF_LoadMemDocument(&requests.FPDF_LoadMemDocument{
+						Data:     encryptedData,
+						Password: &password,
+					})
+					Expect(err).To(BeNil())
+					defer PdfiumInstance.FPDF_CloseDocument(&requests.FPDF_CloseDocument{
+						Document: encryptedDoc.Document,
+					})
+
+					FPDF_GetSecurityHandlerRevision, err := PdfiumInstance.FPDF_GetSecurityHandlerRevision(&requests.FPDF_GetSecurityHandlerRevision{
+						Document: encryptedDoc.Document,
+					})
+					Expect(err).To(BeNil())
+					Expect(FPDF_GetSecurityHandlerRevision.SecurityHandlerRevision).To(Equal(expectedRevision))
+
+					FPDF_GetDocPermissions, err := PdfiumInstance.FPDF_GetDocPermissions(&requests.FPDF_GetDocPermissions{
+						Document: encryptedDoc.Document,
+					})
+					Expect(err).To(BeNil())
+					Expect(FPDF_GetDocPermissions.DocPermissions).To(Equal(uint32(0xFFFFF0C0 | requests.SavePermissionPrint | requests.SavePermissionCopy)))
+					Expect(FPDF_GetDocPermissions.PrintDocument).To(BeTrue())
+					Expect(FPDF_GetDocPermissions.ModifyContents).To(BeFalse())
+					Expect(FPDF_GetDocPermissions.CopyOrExtractText).To(BeTrue())
+
+					expectReadable(encryptedDoc.Document)
+				})
+
+				It("can be opened with the owner password", func() {
+					password := "owner"
+					encryptedDoc, err := PdfiumInstance.FPDF_LoadMemDocument(&requests.FPDF_LoadMemDocument{
+						Data:     encryptedData,
+						Password: &password,
+					})
+					Expect(err).To(BeNil())
+					defer PdfiumInstance.FPDF_CloseDocument(&requests.FPDF_CloseDocument{
+						Document: encryptedDoc.Document,
+					})
+
+					expectReadable(encryptedDoc.Document)
+				})
+			})
+		}
+
+		It("can be opened without a password when no user password is given", func() {
+			encryptedData := saveEncrypted(requests.SaveEncryption{
+				Method:      requests.SaveEncryptionMethodAES256,
+				Permissions: requests.SavePermissionPrint,
+			})
+
+			encryptedDoc, err := PdfiumInstance.FPDF_LoadMemDocument(&requests.FPDF_LoadMemDocument{
+				Data: encryptedData,
+			})
+			Expect(err).To(BeNil())
+			defer PdfiumInstance.FPDF_CloseDocument(&requests.FPDF_CloseDocument{
+				Document: encryptedDoc.Document,
+			})
+
+			FPDF_GetDocPermissions, err := PdfiumInstance.FPDF_GetDocPermissions(&requests.FPDF_GetDocPermissions{
+				Document: encryptedDoc.Document,
+			})
+			Expect(err).To(BeNil())
+			Expect(FPDF_GetDocPermissions.DocPermissions).To(Equal(uint32(0xFFFFF0C0 | requests.SavePermissionPrint)))
+
+			expectReadable(encryptedDoc.Document)
+		})
+
+		It("encrypts the changes that are applied when saving", func() {
+			_, err := PdfiumInstance.SetMetaData(&requests.SetMetaData{
+				Document: doc,
+				Tags: []requests.SetMetaDataTag{
+					{Tag: "Title", Value: "Encrypted title"},
+				},
+			})
+			Expect(err).To(BeNil())
+
+			encryptedData := saveEncrypted(requests.SaveEncryption{
+				Method:       requests.SaveEncryptionMethodAES128,
+				UserPassword: "user",
+			})
+			Expect(bytes.Contains(*encryptedData, []byte("Encrypted title"))).To(BeFalse())
+
+			password := "user"
+			encryptedDoc, err := PdfiumInstance.FPDF_LoadMemDocument(&requests.FPDF_LoadMemDocument{
+				Data:     encryptedData,
+				Password: &password,
+			})
+			Expect(err).To(BeNil())
+			defer PdfiumInstance.FPDF_CloseDocument(&requests.FPDF_CloseDocument{
+				Document: encryptedDoc.Document,
+			})
+
+			GetMetaData, err := PdfiumInstance.GetMetaData(&requests.GetMetaData{
+				Document: encryptedDoc.Document,
+				Tags:     &[]string{"Title"},
+			})
+			Expect(err).To(BeNil())
+			Expect(GetMetaData.Tags[0].Value).To(Equal("Encrypted title"))
+		})
+	})
+
+	Context("a password protected PDF file that is encrypted when saving", func() {
+		It("replaces the password", func() {
+			pdfData, err := ioutil.ReadFile(TestDataPath + "/testdata/password_test123.pdf")
+			Expect(err).To(BeNil())
+
+			password := "test123"
+			doc, err := PdfiumInstance.FPDF_LoadMemDocument(&requests.FPDF_LoadMemDocument{
+				Data:     &pdfData,
+				Password: &password,
+			})
+			Expect(err).To(BeNil())
+			defer PdfiumInstance.FPDF_CloseDocument(&requests.FPDF_CloseDocument{
+				Document: doc.Document,
+			})
+
+			FPDF_SaveAsCopy, err := PdfiumInstance.FPDF_SaveAsCopy(&requests.FPDF_SaveAsCopy{
+				Document: doc.Document,
+				Encryption: &requests.SaveEncryption{
+					Method:       requests.SaveEncryptionMethodAES256,
+					UserPassword: "new password",
+					Permissions:  requests.SavePermissionAll,
+				},
+			})
+			Expect(err).To(BeNil())
+
+			encryptedDoc, err := PdfiumInstance.FPDF_LoadMemDocument(&requests.FPDF_LoadMemDocument{
+				Data:     FPDF_SaveAsCopy.FileBytes,
+				Password: &password,
+			})
+			Expect(err).To(MatchError(errors.ErrPassword.Error()))
+			Expect(encryptedDoc).To(BeNil())
+
+			newPassword := "new password"
+			encryptedDoc, err = PdfiumInstance.FPDF_LoadMemDocument(&requests.FPDF_LoadMemDocument{
+				Data:     FPDF_SaveAsCopy.FileBytes,
+				Password: &newPassword,
+			})
+			Expect(err).To(BeNil())
+			defer PdfiumInstance.FPDF_CloseDocument(&requests.FPDF_CloseDocument{
+				Document: encryptedDoc.Document,
+			})
+
+			FPDF_GetDocPermissions, err := PdfiumInstance.FPDF_GetDocPermissions(&requests.FPDF_GetDocPermissions{
+				Document: encryptedDoc.Document,
+			})
+			Expect(err).To(BeNil())
+			Expect(FPDF_GetDocPermissions.DocPermissions).To(Equal(uint32(0xFFFFFFFC)))
+		})
+	})
 })
@@ -0,0 +1,315 @@
+package shared_tests
+
+import (
+	"io/ioutil"
+	"time"
+
+	"github.com/klippa-app/go-pdfium/references"
+	"github.com/klippa-app/go-pdfium/requests"
+	"github.com/klippa-app/go-pdfium/responses"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("header_footer", func() {
+	BeforeEach(func() {
+		Locker.Lock()
+	})
+
+	AfterEach(func() {
+		Locker.Unlock()
+	})
+
+	getSavedPageText := func(doc references.FPDF_DOCUMENT, index int) string {
+		FPDF_SaveAsCopy, err := PdfiumInstance.FPDF_SaveAsCopy(&requests.FPDF_SaveAsCopy{
+			Document: doc,
+		})
+		Expect(err).To(BeNil())
+
+		newDoc, err := PdfiumInstance.FPDF_LoadMemDocument(&requests.FPDF_LoadMemDocument{
+			Data: FPDF_SaveAsCopy.FileBytes,
+		})
+		Expect(err).To(BeNil())
+
+		GetPageText, err := PdfiumInstance.GetPageText(&requests.GetPageText{
+			Page: requests.Page{
+				ByIndex: &requests.PageByIndex{
+					Document: newDoc.Document,
+					Index:    index,
+				},
+			},
+		})
+		Expect(err).To(BeNil())
+
+		FPDF_CloseDocument, err := PdfiumInstance.FPDF_CloseDocument(&requests.FPDF_CloseDocument{
+			Document: newDoc.Document,
+		})
+		Expect(err).To(BeNil())
+		Expect(FPDF_CloseDocument).To(Not(BeNil()))
+
+		return GetPageText.Text
+	}
+
+	getLastObjectBounds := func(doc references.FPDF_DOCUMENT, index int) *responses.FPDFPageObj_GetBounds {
+		page := requests.Page{
+			ByIndex: &requests.PageByIndex{
+				Document: doc,
+				Index:    index,
+			},
+		}
+
+		FPDFPage_CountObjects, err := PdfiumInstance.FPDFPage_CountObjects(&requests.FPDFPage_CountObjects{
+			Page: page,
+		})
+		Expect(err).To(BeNil())
+
+		FPDFPage_GetObject, err := PdfiumInstance.FPDFPage_GetObject(&requests.FPDFPage_GetObject{
+			Page:  page,
+			Index: FPDFPage_CountObjects.Count - 1,
+		})
+		Expect(err).To(BeNil())
+
+		FPDFPageObj_GetBounds, err := PdfiumInstance.FPDFPageObj_GetBounds(&requests.FPDFPageObj_GetBounds{
+			PageObject: FPDFPage_GetObject.PageObject,
+		})
+		Expect(err).To(BeNil())
+
+		return FPDFPageObj_GetBounds
+	}
+
+	Context("no document", func() {
+		When("is opened", func() {
+			It("returns an error when calling AddHeaderFooter", func() {
+				AddHeaderFooter, err := PdfiumInstance.AddHeaderFooter(&requests.AddHeaderFooter{
+					Documents: []requests.HeaderFooterDocument{{}},
+					Texts: []requests.HeaderFooterText{
+						{Template: "{page}", Position: requests.HeaderFooterPositionBottomCenter},
+					},
+				})
+				Expect(err).To(MatchError("document not given"))
+				Expect(AddHeaderFooter).To(BeNil())
+			})
+
+			It("returns an error when no documents are given", func() {
+				AddHeaderFooter, err := PdfiumInstance.AddHeaderFooter(&requests.AddHeaderFooter{
+					Texts: []requests.HeaderFooterText{
+						{Template: "{page}", Position: requests.HeaderFooterPositionBottomCenter},
+					},
+				})
+				Expect(err).To(MatchError("no documents given"))
+				Expect(AddHeaderFooter).To(BeNil())
+			})
+		})
+	})
+
+	Context("a normal PDF file", func() {
+		var doc references.FPDF_DOCUMENT
+
+		BeforeEach(func() {
+			pdfData, err := ioutil.ReadFile(TestDataPath + "/testdata/test.pdf")
+			Expect(err).To(BeNil())
+
+			newDoc, err := PdfiumInstance.FPDF_LoadMemDocument(&requests.FPDF_LoadMemDocument{
+				Data: &pdfData,
+			})
+			Expect(err).To(BeNil())
+
+			doc = newDoc.Document
+		})
+
+		AfterEach(func() {
+			FPDF_CloseDocument, err := PdfiumInstance.FPDF_CloseDocument(&requests.FPDF_CloseDocument{
+				Document: doc,
+			})
+			Expect(err).To(BeNil())
+			Expect(FPDF_CloseDocument).To(Not(BeNil()))
+		})
+
+		It("returns an error when no texts are given", func() {
+			AddHeaderFooter, err := PdfiumInstance.AddHeaderFooter(&requests.AddHeaderFooter{
+				Documents: []requests.HeaderFooterDocument{{Document: doc}},
+			})
+			Expect(err).To(MatchError("no texts given"))
+			Expect(AddHeaderFooter).To(BeNil())
+		})
+
+		It("returns an error when an invalid position is given", func() {
+			AddHeaderFooter, err := PdfiumInstance.AddHeaderFooter(&requests.AddHeaderFooter{
+				Documents: []requests.HeaderFooterDocument{{Document: doc}},
+				Texts: []requests.HeaderFooterText{
+					{Template: "{page}", Position: "middle"},
+				},
+			})
+			Expect(err).To(MatchError("invalid position middle given"))
+			Expect(AddHeaderFooter).To(BeNil())
+		})
+
+		It("returns an error when an unknown placeholder is given", func() {
+			AddHeaderFooter, err := PdfiumInstance.AddHeaderFooter(&requests.AddHeaderFooter{
+				Documents: []requests.HeaderFooterDocument{{Document: doc}},
+				Texts: []requests.HeaderFooterText{
+					{Template: "{pages}", Position: requests.HeaderFooterPositionBottomCenter},
+				},
+			})
+			Expect(err).To(MatchError("unknown placeholder {pages} in template \"{pages}\""))
+			Expect(AddHeaderFooter).To(BeNil())
+		})
+
+		It("returns an error when an invalid number format is given", func() {
+			AddHeaderFooter, err := PdfiumInstance.AddHeaderFooter(&requests.AddHeaderFooter{
+				Documents: []requests.HeaderFooterDocument{{Document: doc}},
+				Texts: []requests.HeaderFooterText{
+					{Template: "{n:x}", Position: requests.HeaderFooterPositionBottomCenter},
+				},
+			})
+			Expect(err).To(MatchError("invalid number format \"x\" in template \"{n:x}\""))
+			Expect(AddHeaderFooter).To(BeNil())
+		})
+
+		It("returns an error when a placeholder is not closed", func() {
+			AddHeaderFooter, err := PdfiumInstance.AddHeaderFooter(&requests.AddHeaderFooter{
+				Documents: []requests.HeaderFooterDocument{{Document: doc}},
+				Texts: []requests.HeaderFooterText{
+					{Template: "Page {page", Position: requests.HeaderFooterPositionBottomCenter},
+				},
+			})
+			Expect(err).To(MatchError("unclosed placeholder in template \"Page {page\""))
+			Expect(AddHeaderFooter).To(BeNil())
+		})
+
+		It("adds the header and footer texts to the page", func() {
+			AddHeaderFooter, err := PdfiumInstance.AddHeaderFooter(&requests.AddHeaderFooter{
+				Documents: []requests.HeaderFooterDocument{{Document: doc}},
+				Texts: []requests.HeaderFooterText{
+					{Template: "Printed on {date:02-01-2006} {{draft}}", Position: requests.HeaderFooterPositionTopLeft},
+					{Template: "Page {page} of {total}", Position: requests.HeaderFooterPositionBottomCenter},
+					{Template: "{prefix}{n:06}", Position: requests.HeaderFooterPositionBottomRight, FontSize: 12},
+				},
+				Prefix: "ABC",
+				Start:  42,
+				Date:   time.Date(2022, 3, 4, 0, 0, 0, 0, time.UTC),
+			})
+			Expect(err).To(BeNil())
+			Expect(AddHeaderFooter).To(Equal(&responses.AddHeaderFooter{
+				Documents: []responses.AddHeaderFooterDocument{
+					{Pages: 1, FirstNumber: 42, LastNumber: 42},
+				},
+				NextNumber: 43,
+			}))
+
+			bounds := getLastObjectBounds(doc, 0)
+			Expect(bounds.Right).To(BeNumerically("~", 595.28-36, 1))
+			Expect(bounds.Bottom).To(BeNumerically(">=", 35))
+			Expect(bounds.Top).To(BeNumerically("<", 36+12))
+
+			pageText := getSavedPageText(doc, 0)
+			Expect(pageText).To(ContainSubstring("Printed on 04-03-2022 {draft}"))
+			Expect(pageText).To(ContainSubstring("Page 1 of 1"))
+			Expect(pageText).To(ContainSubstring("ABC000042"))
+		})
+	})
+
+	Context("a PDF file with a rotated page", func() {
+		var doc references.FPDF_DOCUMENT
+
+		BeforeEach(func() {
+			pdfData, err := ioutil.ReadFile(TestDataPath + "/testdata/rectangles_multi_pages.pdf")
+			Expect(err).To(BeNil())
+
+			newDoc, err := PdfiumInstance.FPDF_LoadMemDocument(&requests.FPDF_LoadMemDocument{
+				Data: &pdfData,
+			})
+			Expect(err).To(BeNil())
+
+			doc = newDoc.Document
+		})
+
+		AfterEach(func() {
+			FPDF_CloseDocument, err := PdfiumInstance.FPDF_CloseDocument(&requests.FPDF_CloseDocument{
+				Document: doc,
+			})
+			Expect(err).To(BeNil())
+			Expect(FPDF_CloseDocument).To(Not(BeNil()))
+		})
+
+		It("places the text at the bottom of the page as it is displayed", func() {
+			pageRange := "2"
+			_, err := PdfiumInstance.AddHeaderFooter(&requests.AddHeaderFooter{
+				Documents: []requests.HeaderFooterDocument{{Document: doc, PageRange: &pageRange}},
+				Texts: []requests.HeaderFooterText{
+					{Template: "{page}", Position: requests.HeaderFooterPositionBottomLeft, Margin: 20},
+				},
+			})
+			Expect(err).To(BeNil())
+
+			// The page is rotated 90 degrees clockwise, so the bottom of the
+			// displayed page is the right side of the page, and the left of
+			// the displayed page is the bottom of the page.
+			bounds := getLastObjectBounds(doc, 1)
+			Expect(bounds.Right).To(BeNumerically("~", 200-20, 1))
+			Expect(bounds.Left).To(BeNumerically(">", 200-20-10))
+			Expect(bounds.Bottom).To(BeNumerically("~", 20, 1))
+		})
+	})
+
+	Context("multiple PDF files", func() {
+		var doc1 references.FPDF_DOCUMENT
+		var doc2 references.FPDF_DOCUMENT
+
+		BeforeEach(func() {
+			pdfData, err := ioutil.ReadFile(TestDataPath + "/testdata/test_multipage.pdf")
+			Expect(err).To(BeNil())
+
+			newDoc, err := PdfiumInstance.FPDF_LoadMemDocument(&requests.FPDF_LoadMemDocument{
+				Data: &pdfData,
+			})
+			Expect(err).To(BeNil())
+			doc1 = newDoc.Document
+
+			pdfData, err = ioutil.ReadFile(TestDataPath + "/testdata/test.pdf")
+			Expect(err).To(BeNil())
+
+			newDoc, err = PdfiumInstance.FPDF_LoadMemDocument(&requests.FPDF_LoadMemDocument{
+				Data: &pdfData,
+			})
+			Expect(err).To(BeNil())
+			doc2 = newDoc.Document
+		})
+
+		AfterEach(func() {
+			for _, doc := range []references.FPDF_DOCUMENT{doc1, doc2} {
+				FPDF_CloseDocument, err := PdfiumInstance.FPDF_CloseDocument(&requests.FPDF_CloseDocument{
+					Document: doc,
+				})
+				Expect(err).To(BeNil())
+				Expect(FPDF_CloseDocument).To(Not(BeNil()))
+			}
+		})
+
+		It("continues the numbering over the documents", func() {
+			pageRange := "2"
+			AddHeaderFooter, err := PdfiumInstance.AddHeaderFooter(&requests.AddHeaderFooter{
+				Documents: []requests.HeaderFooterDocument{
+					{Document: doc1, PageRange: &pageRange},
+					{Document: doc2},
+				},
+				Texts: []requests.HeaderFooterText{
+					{Template: "DOC-{n:04} ({page}/{total})", Position: requests.HeaderFooterPositionBottomRight},
+				},
+			})
+			Expect(err).To(BeNil())
+			Expect(AddHeaderFooter).To(Equal(&responses.AddHeaderFooter{
+				Documents: []responses.AddHeaderFooterDocument{
+					{Pages: 1, FirstNumber: 1, LastNumber: 1},
+					{Pages: 1, FirstNumber: 2, LastNumber: 2},
+				},
+				NextNumber: 3,
+			}))
+
+			Expect(getSavedPageText(doc1, 0)).To(Not(ContainSubstring("DOC-")))
+			Expect(getSavedPageText(doc1, 1)).To(ContainSubstring("DOC-0001 (2/2)"))
+			Expect(getSavedPageText(doc2, 0)).To(ContainSubstring("DOC-0002 (1/1)"))
+		})
+	})
+})
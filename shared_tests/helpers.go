@@ -0,0 +1,39 @@
+package shared_tests
+
+import (
+	"io/ioutil"
+
+	"github.com/klippa-app/go-pdfium/references"
+	"github.com/klippa-app/go-pdfium/requests"
+
+	. "github.com/onsi/gomega"
+)
+
+// openDocument loads a file of the test data from memory.
+func openDocument(fileName string) references.FPDF_DOCUMENT {
+	return openDocumentWithPassword(fileName, nil)
+}
+
+// openDocumentWithPassword loads a file of the test data from memory, with
+// the given password.
+func openDocumentWithPassword(fileName string, password *string) references.FPDF_DOCUMENT {
+	pdfData, err := ioutil.ReadFile(TestDataPath + "/testdata/" + fileName)
+	Expect(err).To(BeNil())
+
+	newDoc, err := PdfiumInstance.FPDF_LoadMemDocument(&requests.FPDF_LoadMemDocument{
+		Data:     &pdfData,
+		Password: password,
+	})
+	Expect(err).To(BeNil())
+
+	return newDoc.Document
+}
+
+// closeDocument closes a document that was loaded by a test.
+func closeDocument(doc references.FPDF_DOCUMENT) {
+	FPDF_CloseDocument, err := PdfiumInstance.FPDF_CloseDocument(&requests.FPDF_CloseDocument{
+		Document: doc,
+	})
+	Expect(err).To(BeNil())
+	Expect(FPDF_CloseDocument).To(Not(BeNil()))
+}
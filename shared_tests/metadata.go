@@ -0,0 +1,311 @@
+package shared_tests
+
+import (
+	"io/ioutil"
+
+	"github.com/klippa-app/go-pdfium/references"
+	"github.com/klippa-app/go-pdfium/requests"
+	"github.com/klippa-app/go-pdfium/responses"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("metadata", func() {
+	BeforeEach(func() {
+		Locker.Lock()
+	})
+
+	AfterEach(func() {
+		Locker.Unlock()
+	})
+
+	saveAndReload := func(doc references.FPDF_DOCUMENT, flags requests.SaveFlags) references.FPDF_DOCUMENT {
+		FPDF_SaveAsCopy, err := PdfiumInstance.FPDF_SaveAsCopy(&requests.FPDF_SaveAsCopy{
+			Document: doc,
+			Flags:    flags,
+		})
+		Expect(err).To(BeNil())
+		Expect(FPDF_SaveAsCopy.FileBytes).To(Not(BeNil()))
+
+		newDoc, err := PdfiumInstance.FPDF_LoadMemDocument(&requests.FPDF_LoadMemDocument{
+			Data: FPDF_SaveAsCopy.FileBytes,
+		})
+		Expect(err).To(BeNil())
+
+		return newDoc.Document
+	}
+
+	Context("no document", func() {
+		When("is opened", func() {
+			It("returns an error when calling SetMetaData", func() {
+				SetMetaData, err := PdfiumInstance.SetMetaData(&requests.SetMetaData{})
+				Expect(err).To(MatchError("document not given"))
+				Expect(SetMetaData).To(BeNil())
+			})
+		})
+	})
+
+	Context("a normal PDF file", func() {
+		var doc references.FPDF_DOCUMENT
+
+		BeforeEach(func() {
+			pdfData, err := ioutil.ReadFile(TestDataPath + "/testdata/test.pdf")
+			Expect(err).To(BeNil())
+
+			newDoc, err := PdfiumInstance.FPDF_LoadMemDocument(&requests.FPDF_LoadMemDocument{
+				Data: &pdfData,
+			})
+			Expect(err).To(BeNil())
+
+			doc = newDoc.Document
+		})
+
+		AfterEach(func() {
+			closeDocument(doc)
+		})
+
+		It("returns an error when no tag is given", func() {
+			SetMetaData, err := PdfiumInstance.SetMetaData(&requests.SetMetaData{
+				Document: doc,
+				Tags: []requests.SetMetaDataTag{
+					{Value: "Test"},
+				},
+			})
+			Expect(err).To(MatchError("tag not given"))
+			Expect(SetMetaData).To(BeNil())
+		})
+
+		When("the metadata is set", func() {
+			BeforeEach(func() {
+				SetMetaData, err := PdfiumInstance.SetMetaData(&requests.SetMetaData{
+					Document: doc,
+					Tags: []requests.SetMetaDataTag{
+						{Tag: "Title", Value: "A (new) title"},
+						{Tag: "Author", Value: "Jérôme Müller"},
+						{Tag: "Keywords", Value: "pdf, metadata"},
+						{Tag: "Producer", Value: ""},
+						{Tag: "Custom", Value: "Custom value"},
+					},
+				})
+				Expect(err).To(BeNil())
+				Expect(SetMetaData).To(Equal(&responses.SetMetaData{}))
+			})
+
+			It("returns the new metadata before saving", func() {
+				GetMetaData, err := PdfiumInstance.GetMetaData(&requests.GetMetaData{
+					Document: doc,
+					Tags:     &[]string{"Title", "Author", "Producer", "CreationDate"},
+				})
+				Expect(err).To(BeNil())
+				Expect(GetMetaData).To(Equal(&responses.GetMetaData{
+					Tags: []responses.GetMetaDataTag{
+						{Tag: "Title", Value: "A (new) title"},
+						{Tag: "Author", Value: "Jérôme Müller"},
+						{Tag: "Producer", Value: ""},
+						{Tag: "CreationDate", Value: "D:20210823145142+02'00"},
+					},
+				}))
+			})
+
+			It("writes the metadata into the saved document", func() {
+				savedDoc := saveAndReload(doc, 0)
+				defer closeDocument(savedDoc)
+
+				GetMetaData, err := PdfiumInstance.GetMetaData(&requests.GetMetaData{
+					Document: savedDoc,
+					Tags:     &[]string{"Title", "Author", "Subject", "Keywords", "Producer", "CreationDate", "Custom"},
+				})
+				Expect(err).To(BeNil())
+				Expect(GetMetaData).To(Equal(&responses.GetMetaData{
+					Tags: []responses.GetMetaDataTag{
+						{Tag: "Title", Value: "A (new) title"},
+						{Tag: "Author", Value: "Jérôme Müller"},
+						{Tag: "Subject", Value: ""},
+						{Tag: "Keywords", Value: "pdf, metadata"},
+						{Tag: "Producer", Value: ""},
+						{Tag: "CreationDate", Value: "D:20210823145142+02'00"},
+						{Tag: "Custom", Value: "Custom value"},
+					},
+				}))
+			})
+
+			It("writes the metadata into an incrementally saved document", func() {
+				savedDoc := saveAndReload(doc, requests.SaveFlagIncremental)
+				defer closeDocument(savedDoc)
+
+				GetMetaData, err := PdfiumInstance.GetMetaData(&requests.GetMetaData{
+					Document: savedDoc,
+					Tags:     &[]string{"Title", "Author"},
+				})
+				Expect(err).To(BeNil())
+				Expect(GetMetaData).To(Equal(&responses.GetMetaData{
+					Tags: []responses.GetMetaDataTag{
+						{Tag: "Title", Value: "A (new) title"},
+						{Tag: "Author", Value: "Jérôme Müller"},
+					},
+				}))
+			})
+
+			It("writes the metadata every time the document is saved", func() {
+				for i := 0; i < 2; i++ {
+					savedDoc := saveAndReload(doc, 0)
+
+					GetMetaData, err := PdfiumInstance.GetMetaData(&requests.GetMetaData{
+						Document: savedDoc,
+						Tags:     &[]string{"Title"},
+					})
+					Expect(err).To(BeNil())
+					Expect(GetMetaData.Tags[0].Value).To(Equal("A (new) title"))
+
+					closeDocument(savedDoc)
+				}
+			})
+		})
+	})
+
+	Context("a PDF file with a cross-reference stream", func() {
+		var doc references.FPDF_DOCUMENT
+
+		BeforeEach(func() {
+			pdfData, err := ioutil.ReadFile(TestDataPath + "/testdata/page_labels.pdf")
+			Expect(err).To(BeNil())
+
+			newDoc, err := PdfiumInstance.FPDF_LoadMemDocument(&requests.FPDF_LoadMemDocument{
+				Data: &pdfData,
+			})
+			Expect(err).To(BeNil())
+
+			doc = newDoc.Document
+		})
+
+		AfterEach(func() {
+			closeDocument(doc)
+		})
+
+		It("writes the metadata into an incrementally saved document", func() {
+			_, err := PdfiumInstance.SetMetaData(&requests.SetMetaData{
+				Document: doc,
+				Tags: []requests.SetMetaDataTag{
+					{Tag: "Title", Value: "Page labels"},
+				},
+			})
+			Expect(err).To(BeNil())
+
+			savedDoc := saveAndReload(doc, requests.SaveFlagIncremental)
+			defer closeDocument(savedDoc)
+
+			GetMetaData, err := PdfiumInstance.GetMetaData(&requests.GetMetaData{
+				Document: savedDoc,
+				Tags:     &[]string{"Title"},
+			})
+			Expect(err).To(BeNil())
+			Expect(GetMetaData.Tags[0].Value).To(Equal("Page labels"))
+
+			FPDF_GetPageCount, err := PdfiumInstance.FPDF_GetPageCount(&requests.FPDF_GetPageCount{
+				Document: savedDoc,
+			})
+			Expect(err).To(BeNil())
+			Expect(FPDF_GetPageCount.PageCount).To(Equal(7))
+		})
+	})
+
+	Context("a new PDF file", func() {
+		var doc references.FPDF_DOCUMENT
+
+		BeforeEach(func() {
+			newDoc, err := PdfiumInstance.FPDF_CreateNewDocument(&requests.FPDF_CreateNewDocument{})
+			Expect(err).To(BeNil())
+
+			doc = newDoc.Document
+
+			_, err = PdfiumInstance.FPDFPage_New(&requests.FPDFPage_New{
+				Document:  doc,
+				PageIndex: 0,
+				Width:     612,
+				Height:    792,
+			})
+			Expect(err).To(BeNil())
+		})
+
+		AfterEach(func() {
+			closeDocument(doc)
+		})
+
+		It("writes the metadata into the saved document", func() {
+			_, err := PdfiumInstance.SetMetaData(&requests.SetMetaData{
+				Document: doc,
+				Tags: []requests.SetMetaDataTag{
+					{Tag: "Title", Value: "Generated"},
+					{Tag: "Creator", Value: "go-pdfium"},
+				},
+			})
+			Expect(err).To(BeNil())
+
+			savedDoc := saveAndReload(doc, 0)
+			defer closeDocument(savedDoc)
+
+			GetMetaData, err := PdfiumInstance.GetMetaData(&requests.GetMetaData{
+				Document: savedDoc,
+				Tags:     &[]string{"Title", "Creator"},
+			})
+			Expect(err).To(BeNil())
+			Expect(GetMetaData).To(Equal(&responses.GetMetaData{
+				Tags: []responses.GetMetaDataTag{
+					{Tag: "Title", Value: "Generated"},
+					{Tag: "Creator", Value: "go-pdfium"},
+				},
+			}))
+		})
+	})
+
+	Context("an encrypted PDF file", func() {
+		var doc references.FPDF_DOCUMENT
+
+		BeforeEach(func() {
+			pdfData, err := ioutil.ReadFile(TestDataPath + "/testdata/password_test123.pdf")
+			Expect(err).To(BeNil())
+
+			password := "test123"
+			newDoc, err := PdfiumInstance.FPDF_LoadMemDocument(&requests.FPDF_LoadMemDocument{
+				Data:     &pdfData,
+				Password: &password,
+			})
+			Expect(err).To(BeNil())
+
+			doc = newDoc.Document
+
+			_, err = PdfiumInstance.SetMetaData(&requests.SetMetaData{
+				Document: doc,
+				Tags: []requests.SetMetaDataTag{
+					{Tag: "Title", Value: "Decrypted"},
+				},
+			})
+			Expect(err).To(BeNil())
+		})
+
+		AfterEach(func() {
+			closeDocument(doc)
+		})
+
+		It("returns an error when saving with security", func() {
+			FPDF_SaveAsCopy, err := PdfiumInstance.FPDF_SaveAsCopy(&requests.FPDF_SaveAsCopy{
+				Document: doc,
+			})
+			Expect(err).To(MatchError("can not apply changes to an encrypted document, save the document without security"))
+			Expect(FPDF_SaveAsCopy).To(BeNil())
+		})
+
+		It("writes the metadata when saving without security", func() {
+			savedDoc := saveAndReload(doc, requests.SaveFlagRemoveSecurity)
+			defer closeDocument(savedDoc)
+
+			GetMetaData, err := PdfiumInstance.GetMetaData(&requests.GetMetaData{
+				Document: savedDoc,
+				Tags:     &[]string{"Title"},
+			})
+			Expect(err).To(BeNil())
+			Expect(GetMetaData.Tags[0].Value).To(Equal("Decrypted"))
+		})
+	})
+})
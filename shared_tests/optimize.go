@@ -0,0 +1,75 @@
+package shared_tests
+
+import (
+	"io/ioutil"
+
+	"github.com/klippa-app/go-pdfium/references"
+	"github.com/klippa-app/go-pdfium/requests"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("optimize", func() {
+	BeforeEach(func() {
+		Locker.Lock()
+	})
+
+	AfterEach(func() {
+		Locker.Unlock()
+	})
+
+	Context("no document", func() {
+		When("is opened", func() {
+			It("returns an error when calling OptimizeDocument", func() {
+				OptimizeDocument, err := PdfiumInstance.OptimizeDocument(&requests.OptimizeDocument{})
+				Expect(err).To(MatchError("document not given"))
+				Expect(OptimizeDocument).To(BeNil())
+			})
+		})
+	})
+
+	Context("a PDF file with images", func() {
+		var doc references.FPDF_DOCUMENT
+
+		BeforeEach(func() {
+			pdfData, err := ioutil.ReadFile(TestDataPath + "/testdata/embedded_images.pdf")
+			Expect(err).To(BeNil())
+
+			newDoc, err := PdfiumInstance.FPDF_LoadMemDocument(&requests.FPDF_LoadMemDocument{
+				Data: &pdfData,
+			})
+			Expect(err).To(BeNil())
+
+			doc = newDoc.Document
+		})
+
+		AfterEach(func() {
+			FPDF_CloseDocument, err := PdfiumInstance.FPDF_CloseDocument(&requests.FPDF_CloseDocument{
+				Document: doc,
+			})
+			Expect(err).To(BeNil())
+			Expect(FPDF_CloseDocument).To(Not(BeNil()))
+		})
+
+		When("OptimizeDocument is called", func() {
+			It("returns an error when an invalid maximum DPI is given", func() {
+				OptimizeDocument, err := PdfiumInstance.OptimizeDocument(&requests.OptimizeDocument{
+					Document: doc,
+					MaxDPI:   -1,
+				})
+				Expect(err).To(MatchError("no valid maximum DPI given"))
+				Expect(OptimizeDocument).To(BeNil())
+			})
+
+			It("returns an error when an invalid JPEG quality is given", func() {
+				OptimizeDocument, err := PdfiumInstance.OptimizeDocument(&requests.OptimizeDocument{
+					Document:    doc,
+					JpegQuality: 101,
+				})
+				Expect(err).To(MatchError("invalid JPEG quality 101 given"))
+				Expect(OptimizeDocument).To(BeNil())
+			})
+		})
+	})
+})
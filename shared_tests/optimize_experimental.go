@@ -0,0 +1,296 @@
+//go:build pdfium_experimental
+// +build pdfium_experimental
+
+package shared_tests
+
+import (
+	"bytes"
+	"io/ioutil"
+
+	"github.com/klippa-app/go-pdfium/references"
+	"github.com/klippa-app/go-pdfium/requests"
+	"github.com/klippa-app/go-pdfium/responses"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("optimize_experimental", func() {
+	BeforeEach(func() {
+		Locker.Lock()
+	})
+
+	AfterEach(func() {
+		Locker.Unlock()
+	})
+
+	saveDocument := func(doc references.FPDF_DOCUMENT) []byte {
+		FPDF_SaveAsCopy, err := PdfiumInstance.FPDF_SaveAsCopy(&requests.FPDF_SaveAsCopy{
+			Document: doc,
+		})
+		Expect(err).To(BeNil())
+		Expect(FPDF_SaveAsCopy.FileBytes).To(Not(BeNil()))
+
+		return *FPDF_SaveAsCopy.FileBytes
+	}
+
+	Context("a PDF file with images", func() {
+		var doc references.FPDF_DOCUMENT
+
+		BeforeEach(func() {
+			doc = openDocument("embedded_images.pdf")
+		})
+
+		AfterEach(func() {
+			closeDocument(doc)
+		})
+
+		When("OptimizeDocument is called", func() {
+			It("does not change images below the maximum DPI", func() {
+				OptimizeDocument, err := PdfiumInstance.OptimizeDocument(&requests.OptimizeDocument{
+					Document: doc,
+					MaxDPI:   300,
+				})
+				Expect(err).To(BeNil())
+				Expect(OptimizeDocument).To(Equal(&responses.OptimizeDocument{
+					Images: []responses.OptimizeDocumentImage{},
+				}))
+			})
+
+			It("downsamples the images above the maximum DPI", func() {
+				originalFile := saveDocument(doc)
+
+				OptimizeDocument, err := PdfiumInstance.OptimizeDocument(&requests.OptimizeDocument{
+					Document: doc,
+					MaxDPI:   100,
+				})
+				Expect(err).To(BeNil())
+				Expect(OptimizeDocument).To(Not(BeNil()))
+				Expect(OptimizeDocument.Images).To(HaveLen(4))
+
+				sizes := [][]int{}
+				for _, image := range OptimizeDocument.Images {
+					Expect(image.Page).To(Equal(0))
+					Expect(image.OriginalDPI).To(BeNumerically(">", 100))
+					Expect(image.Size).To(BeNumerically("<", image.OriginalSize))
+					sizes = append(sizes, []int{image.Index, image.OriginalWidth, image.OriginalHeight, image.Width, image.Height})
+				}
+				Expect(sizes).To(Equal([][]int{
+					{33, 109, 88, 74, 60},
+					{34, 103, 75, 98, 71},
+					{37, 126, 106, 78, 66},
+					{38, 194, 119, 98, 60},
+				}))
+				Expect(OptimizeDocument.ImageBytes).To(BeNumerically(">", 0))
+				Expect(OptimizeDocument.ThumbnailBytes).To(Equal(0))
+				Expect(OptimizeDocument.BytesSaved).To(Equal(OptimizeDocument.ImageBytes))
+
+				optimizedFile := saveDocument(doc)
+				Expect(len(optimizedFile)).To(BeNumerically("<", len(originalFile)))
+
+				optimizedDoc, err := PdfiumInstance.FPDF_LoadMemDocument(&requests.FPDF_LoadMemDocument{
+					Data: &optimizedFile,
+				})
+				Expect(err).To(BeNil())
+				defer closeDocument(optimizedDoc.Document)
+
+				FPDFPage_GetObject, err := PdfiumInstance.FPDFPage_GetObject(&requests.FPDFPage_GetObject{
+					Page: requests.Page{
+						ByIndex: &requests.PageByIndex{
+							Document: optimizedDoc.Document,
+							Index:    0,
+						},
+					},
+					Index: 38,
+				})
+				Expect(err).To(BeNil())
+
+				FPDFImageObj_GetImagePixelSize, err := PdfiumInstance.FPDFImageObj_GetImagePixelSize(&requests.FPDFImageObj_GetImagePixelSize{
+					ImageObject: FPDFPage_GetObject.PageObject,
+				})
+				Expect(err).To(BeNil())
+				Expect(FPDFImageObj_GetImagePixelSize).To(Equal(&responses.FPDFImageObj_GetImagePixelSize{
+					Width:  98,
+					Height: 60,
+				}))
+			})
+		})
+	})
+
+	Context("a PDF file with an image that is used on two pages", func() {
+		var doc references.FPDF_DOCUMENT
+		var pdfData []byte
+
+		BeforeEach(func() {
+			var err error
+			pdfData, err = ioutil.ReadFile(TestDataPath + "/testdata/shared_image.pdf")
+			Expect(err).To(BeNil())
+
+			newDoc, err := PdfiumInstance.FPDF_LoadMemDocument(&requests.FPDF_LoadMemDocument{
+				Data: &pdfData,
+			})
+			Expect(err).To(BeNil())
+
+			doc = newDoc.Document
+		})
+
+		AfterEach(func() {
+			closeDocument(doc)
+		})
+
+		When("OptimizeDocument is called", func() {
+			It("downsamples the image once and saves the document", func() {
+				OptimizeDocument, err := PdfiumInstance.OptimizeDocument(&requests.OptimizeDocument{
+					Document: doc,
+					Save:     true,
+				})
+				Expect(err).To(BeNil())
+				Expect(OptimizeDocument).To(Not(BeNil()))
+				Expect(OptimizeDocument.Images).To(HaveLen(1))
+
+				image := OptimizeDocument.Images[0]
+				Expect([]int{image.Page, image.Index, image.OriginalWidth, image.OriginalHeight, image.Width, image.Height}).To(Equal([]int{0, 0, 400, 400, 209, 209}))
+				Expect(OptimizeDocument.ImageBytes).To(Equal(image.OriginalSize - image.Size))
+				Expect(OptimizeDocument.BytesSaved).To(Equal(OptimizeDocument.ImageBytes))
+				Expect(OptimizeDocument.FilePath).To(BeNil())
+				Expect(OptimizeDocument.FileBytes).To(Not(BeNil()))
+
+				// Both pages use the same downsampled image.
+				optimizedFile := *OptimizeDocument.FileBytes
+				Expect(bytes.Count(optimizedFile, []byte("/DCTDecode"))).To(Equal(1))
+
+				optimizedDoc, err := PdfiumInstance.FPDF_LoadMemDocument(&requests.FPDF_LoadMemDocument{
+					Data: &optimizedFile,
+				})
+				Expect(err).To(BeNil())
+				defer closeDocument(optimizedDoc.Document)
+
+				for pageIndex := 0; pageIndex < 2; pageIndex++ {
+					FPDFPage_GetObject, err := PdfiumInstance.FPDFPage_GetObject(&requests.FPDFPage_GetObject{
+						Page: requests.Page{
+							ByIndex: &requests.PageByIndex{
+								Document: optimizedDoc.Document,
+								Index:    pageIndex,
+							},
+						},
+						Index: 0,
+					})
+					Expect(err).To(BeNil())
+
+					FPDFImageObj_GetImagePixelSize, err := PdfiumInstance.FPDFImageObj_GetImagePixelSize(&requests.FPDFImageObj_GetImagePixelSize{
+						ImageObject: FPDFPage_GetObject.PageObject,
+					})
+					Expect(err).To(BeNil())
+					Expect(FPDFImageObj_GetImagePixelSize).To(Equal(&responses.FPDFImageObj_GetImagePixelSize{
+						Width:  209,
+						Height: 209,
+					}))
+				}
+			})
+
+			It("only removes the unused objects when saving with OptimizeDocument", func() {
+				_, err := PdfiumInstance.OptimizeDocument(&requests.OptimizeDocument{
+					Document: doc,
+					Save:     true,
+				})
+				Expect(err).To(BeNil())
+
+				// An incremental save keeps the original file.
+				FPDF_SaveAsCopy, err := PdfiumInstance.FPDF_SaveAsCopy(&requests.FPDF_SaveAsCopy{
+					Document: doc,
+					Flags:    requests.SaveFlagIncremental,
+				})
+				Expect(err).To(BeNil())
+				Expect(bytes.HasPrefix(*FPDF_SaveAsCopy.FileBytes, pdfData)).To(BeTrue())
+			})
+		})
+	})
+
+	Context("a PDF file with attachments", func() {
+		var doc references.FPDF_DOCUMENT
+
+		BeforeEach(func() {
+			doc = openDocument("embedded_attachments.pdf")
+		})
+
+		AfterEach(func() {
+			closeDocument(doc)
+		})
+
+		When("OptimizeDocument is called", func() {
+			It("removes the attachments", func() {
+				OptimizeDocument, err := PdfiumInstance.OptimizeDocument(&requests.OptimizeDocument{
+					Document:          doc,
+					RemoveAttachments: true,
+					Save:              true,
+				})
+				Expect(err).To(BeNil())
+				Expect(OptimizeDocument).To(Not(BeNil()))
+				Expect(OptimizeDocument.AttachmentBytes).To(Equal(5873))
+				Expect(OptimizeDocument.BytesSaved).To(Equal(5873))
+
+				optimizedDoc, err := PdfiumInstance.FPDF_LoadMemDocument(&requests.FPDF_LoadMemDocument{
+					Data: OptimizeDocument.FileBytes,
+				})
+				Expect(err).To(BeNil())
+				defer closeDocument(optimizedDoc.Document)
+
+				FPDFDoc_GetAttachmentCount, err := PdfiumInstance.FPDFDoc_GetAttachmentCount(&requests.FPDFDoc_GetAttachmentCount{
+					Document: optimizedDoc.Document,
+				})
+				Expect(err).To(BeNil())
+				Expect(FPDFDoc_GetAttachmentCount.AttachmentCount).To(Equal(0))
+			})
+		})
+	})
+
+	Context("a PDF file with thumbnails", func() {
+		var doc references.FPDF_DOCUMENT
+
+		BeforeEach(func() {
+			doc = openDocument("simple_thumbnail.pdf")
+		})
+
+		AfterEach(func() {
+			closeDocument(doc)
+		})
+
+		When("OptimizeDocument is called", func() {
+			It("removes the thumbnails", func() {
+				originalFile := saveDocument(doc)
+
+				OptimizeDocument, err := PdfiumInstance.OptimizeDocument(&requests.OptimizeDocument{
+					Document:         doc,
+					RemoveThumbnails: true,
+					Save:             true,
+				})
+				Expect(err).To(BeNil())
+				Expect(OptimizeDocument).To(Not(BeNil()))
+				Expect(OptimizeDocument.Images).To(BeEmpty())
+				Expect(OptimizeDocument.ThumbnailBytes).To(Equal(3643))
+				Expect(OptimizeDocument.BytesSaved).To(Equal(3643))
+				Expect(OptimizeDocument.FileBytes).To(Not(BeNil()))
+
+				optimizedFile := *OptimizeDocument.FileBytes
+				Expect(len(optimizedFile)).To(BeNumerically("<", len(originalFile)))
+
+				optimizedDoc, err := PdfiumInstance.FPDF_LoadMemDocument(&requests.FPDF_LoadMemDocument{
+					Data: &optimizedFile,
+				})
+				Expect(err).To(BeNil())
+				defer closeDocument(optimizedDoc.Document)
+
+				FPDFPage_GetRawThumbnailData, err := PdfiumInstance.FPDFPage_GetRawThumbnailData(&requests.FPDFPage_GetRawThumbnailData{
+					Page: requests.Page{
+						ByIndex: &requests.PageByIndex{
+							Document: optimizedDoc.Document,
+							Index:    0,
+						},
+					},
+				})
+				Expect(err).To(BeNil())
+				Expect(FPDFPage_GetRawThumbnailData.RawThumbnail).To(BeEmpty())
+			})
+		})
+	})
+})
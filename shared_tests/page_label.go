@@ -0,0 +1,134 @@
+package shared_tests
+
+import (
+	"io/ioutil"
+
+	"github.com/klippa-app/go-pdfium/references"
+	"github.com/klippa-app/go-pdfium/requests"
+	"github.com/klippa-app/go-pdfium/responses"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("page_label", func() {
+	BeforeEach(func() {
+		Locker.Lock()
+	})
+
+	AfterEach(func() {
+		Locker.Unlock()
+	})
+
+	Context("no document", func() {
+		When("is opened", func() {
+			It("returns an error when calling SetPageLabels", func() {
+				SetPageLabels, err := PdfiumInstance.SetPageLabels(&requests.SetPageLabels{})
+				Expect(err).To(MatchError("document not given"))
+				Expect(SetPageLabels).To(BeNil())
+			})
+		})
+	})
+
+	Context("a PDF file with multiple pages", func() {
+		var doc references.FPDF_DOCUMENT
+
+		BeforeEach(func() {
+			pdfData, err := ioutil.ReadFile(TestDataPath + "/testdata/test_multipage.pdf")
+			Expect(err).To(BeNil())
+
+			newDoc, err := PdfiumInstance.FPDF_LoadMemDocument(&requests.FPDF_LoadMemDocument{
+				Data: &pdfData,
+			})
+			Expect(err).To(BeNil())
+
+			doc = newDoc.Document
+		})
+
+		AfterEach(func() {
+			FPDF_CloseDocument, err := PdfiumInstance.FPDF_CloseDocument(&requests.FPDF_CloseDocument{
+				Document: doc,
+			})
+			Expect(err).To(BeNil())
+			Expect(FPDF_CloseDocument).To(Not(BeNil()))
+		})
+
+		When("SetPageLabels is called", func() {
+			It("returns an error when the first range does not start at the first page", func() {
+				SetPageLabels, err := PdfiumInstance.SetPageLabels(&requests.SetPageLabels{
+					Document: doc,
+					Ranges: []requests.SetPageLabelsRange{
+						{PageIndex: 1, Style: requests.PageLabelStyleDecimal},
+					},
+				})
+				Expect(err).To(MatchError("the first page label range must start at page index 0"))
+				Expect(SetPageLabels).To(BeNil())
+			})
+
+			It("returns an error when a page index is out of range", func() {
+				SetPageLabels, err := PdfiumInstance.SetPageLabels(&requests.SetPageLabels{
+					Document: doc,
+					Ranges: []requests.SetPageLabelsRange{
+						{PageIndex: 0, Style: requests.PageLabelStyleDecimal},
+						{PageIndex: 2, Style: requests.PageLabelStyleDecimal},
+					},
+				})
+				Expect(err).To(MatchError("page index 2 of page label range is out of range, document has 2 pages"))
+				Expect(SetPageLabels).To(BeNil())
+			})
+
+			It("returns an error when the ranges are not ordered", func() {
+				SetPageLabels, err := PdfiumInstance.SetPageLabels(&requests.SetPageLabels{
+					Document: doc,
+					Ranges: []requests.SetPageLabelsRange{
+						{PageIndex: 0, Style: requests.PageLabelStyleDecimal},
+						{PageIndex: 1, Style: requests.PageLabelStyleDecimal},
+						{PageIndex: 1, Style: requests.PageLabelStyleLowerRoman},
+					},
+				})
+				Expect(err).To(MatchError("page label ranges must be ordered by page index, page index 1 comes after page index 1"))
+				Expect(SetPageLabels).To(BeNil())
+			})
+
+			It("returns an error when an invalid style is given", func() {
+				SetPageLabels, err := PdfiumInstance.SetPageLabels(&requests.SetPageLabels{
+					Document: doc,
+					Ranges: []requests.SetPageLabelsRange{
+						{PageIndex: 0, Style: "greek"},
+					},
+				})
+				Expect(err).To(MatchError("invalid page label style greek given"))
+				Expect(SetPageLabels).To(BeNil())
+			})
+
+			It("returns an error when an invalid start number is given", func() {
+				SetPageLabels, err := PdfiumInstance.SetPageLabels(&requests.SetPageLabels{
+					Document: doc,
+					Ranges: []requests.SetPageLabelsRange{
+						{PageIndex: 0, Style: requests.PageLabelStyleDecimal, Start: -1},
+					},
+				})
+				Expect(err).To(MatchError("invalid start number -1 given"))
+				Expect(SetPageLabels).To(BeNil())
+			})
+
+			It("allows the page labels to be set", func() {
+				SetPageLabels, err := PdfiumInstance.SetPageLabels(&requests.SetPageLabels{
+					Document: doc,
+					Ranges: []requests.SetPageLabelsRange{
+						{PageIndex: 0, Style: requests.PageLabelStyleLowerRoman},
+						{PageIndex: 1, Style: requests.PageLabelStyleDecimal, Prefix: "A-"},
+					},
+				})
+				Expect(err).To(BeNil())
+				Expect(SetPageLabels).To(Equal(&responses.SetPageLabels{}))
+
+				FPDF_SaveAsCopy, err := PdfiumInstance.FPDF_SaveAsCopy(&requests.FPDF_SaveAsCopy{
+					Document: doc,
+				})
+				Expect(err).To(BeNil())
+				Expect(FPDF_SaveAsCopy.FileBytes).To(Not(BeNil()))
+			})
+		})
+	})
+})
@@ -0,0 +1,118 @@
+//go:build pdfium_experimental
+// +build pdfium_experimental
+
+package shared_tests
+
+import (
+	"io/ioutil"
+
+	"github.com/klippa-app/go-pdfium/references"
+	"github.com/klippa-app/go-pdfium/requests"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("page_label_experimental", func() {
+	BeforeEach(func() {
+		Locker.Lock()
+	})
+
+	AfterEach(func() {
+		Locker.Unlock()
+	})
+
+	getSavedPageLabels := func(doc references.FPDF_DOCUMENT, flags requests.SaveFlags) []string {
+		FPDF_SaveAsCopy, err := PdfiumInstance.FPDF_SaveAsCopy(&requests.FPDF_SaveAsCopy{
+			Document: doc,
+			Flags:    flags,
+		})
+		Expect(err).To(BeNil())
+
+		savedDoc, err := PdfiumInstance.FPDF_LoadMemDocument(&requests.FPDF_LoadMemDocument{
+			Data: FPDF_SaveAsCopy.FileBytes,
+		})
+		Expect(err).To(BeNil())
+		defer PdfiumInstance.FPDF_CloseDocument(&requests.FPDF_CloseDocument{
+			Document: savedDoc.Document,
+		})
+
+		FPDF_GetPageCount, err := PdfiumInstance.FPDF_GetPageCount(&requests.FPDF_GetPageCount{
+			Document: savedDoc.Document,
+		})
+		Expect(err).To(BeNil())
+
+		labels := []string{}
+		for i := 0; i < FPDF_GetPageCount.PageCount; i++ {
+			FPDF_GetPageLabel, err := PdfiumInstance.FPDF_GetPageLabel(&requests.FPDF_GetPageLabel{
+				Document: savedDoc.Document,
+				Page:     i,
+			})
+			if err != nil {
+				Expect(err).To(MatchError("Could not get label"))
+				labels = append(labels, "")
+				continue
+			}
+			labels = append(labels, FPDF_GetPageLabel.Label)
+		}
+
+		return labels
+	}
+
+	Context("a PDF file with page labels", func() {
+		var doc references.FPDF_DOCUMENT
+
+		BeforeEach(func() {
+			pdfData, err := ioutil.ReadFile(TestDataPath + "/testdata/page_labels.pdf")
+			Expect(err).To(BeNil())
+
+			newDoc, err := PdfiumInstance.FPDF_LoadMemDocument(&requests.FPDF_LoadMemDocument{
+				Data: &pdfData,
+			})
+			Expect(err).To(BeNil())
+
+			doc = newDoc.Document
+		})
+
+		AfterEach(func() {
+			FPDF_CloseDocument, err := PdfiumInstance.FPDF_CloseDocument(&requests.FPDF_CloseDocument{
+				Document: doc,
+			})
+			Expect(err).To(BeNil())
+			Expect(FPDF_CloseDocument).To(Not(BeNil()))
+		})
+
+		When("the page labels are set", func() {
+			BeforeEach(func() {
+				_, err := PdfiumInstance.SetPageLabels(&requests.SetPageLabels{
+					Document: doc,
+					Ranges: []requests.SetPageLabelsRange{
+						{PageIndex: 0, Style: requests.PageLabelStyleLowerRoman},
+						{PageIndex: 2, Style: requests.PageLabelStyleDecimal},
+						{PageIndex: 4, Style: requests.PageLabelStyleDecimal, Prefix: "A-"},
+						{PageIndex: 5, Style: requests.PageLabelStyleUpperLetters, Prefix: "Appendix ", Start: 2},
+						{PageIndex: 6, Prefix: "Back cover"},
+					},
+				})
+				Expect(err).To(BeNil())
+			})
+
+			It("writes the page labels into the saved document", func() {
+				Expect(getSavedPageLabels(doc, 0)).To(Equal([]string{"i", "ii", "1", "2", "A-1", "Appendix B", "Back cover"}))
+			})
+
+			It("writes the page labels into an incrementally saved document", func() {
+				Expect(getSavedPageLabels(doc, requests.SaveFlagIncremental)).To(Equal([]string{"i", "ii", "1", "2", "A-1", "Appendix B", "Back cover"}))
+			})
+		})
+
+		It("removes the page labels", func() {
+			_, err := PdfiumInstance.SetPageLabels(&requests.SetPageLabels{
+				Document: doc,
+			})
+			Expect(err).To(BeNil())
+
+			Expect(getSavedPageLabels(doc, 0)).To(Equal([]string{"", "", "", "", "", "", ""}))
+		})
+	})
+})
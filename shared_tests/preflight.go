@@ -0,0 +1,36 @@
+package shared_tests
+
+import (
+	"github.com/klippa-app/go-pdfium/requests"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("preflight", func() {
+	BeforeEach(func() {
+		Locker.Lock()
+	})
+
+	AfterEach(func() {
+		Locker.Unlock()
+	})
+
+	Context("no document", func() {
+		When("is opened", func() {
+			It("returns an error when calling Preflight", func() {
+				Preflight, err := PdfiumInstance.Preflight(&requests.Preflight{})
+				Expect(err).To(MatchError("document not given"))
+				Expect(Preflight).To(BeNil())
+			})
+
+			It("returns an error when calling Preflight with an invalid profile", func() {
+				Preflight, err := PdfiumInstance.Preflight(&requests.Preflight{
+					Profiles: []requests.PreflightProfile{"pdf/x-1a"},
+				})
+				Expect(err).To(MatchError("invalid preflight profile pdf/x-1a given"))
+				Expect(Preflight).To(BeNil())
+			})
+		})
+	})
+})
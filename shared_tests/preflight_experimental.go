@@ -0,0 +1,288 @@
+//go:build pdfium_experimental
+// +build pdfium_experimental
+
+package shared_tests
+
+import (
+	"github.com/klippa-app/go-pdfium/enums"
+	"github.com/klippa-app/go-pdfium/references"
+	"github.com/klippa-app/go-pdfium/requests"
+	"github.com/klippa-app/go-pdfium/responses"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("preflight_experimental", func() {
+	BeforeEach(func() {
+		Locker.Lock()
+	})
+
+	AfterEach(func() {
+		Locker.Unlock()
+	})
+
+	// failedChecks returns the checks of the profile that did not pass, with
+	// their status.
+	failedChecks := func(profile responses.PreflightProfileResult) map[responses.PreflightCheck]responses.PreflightStatus {
+		checks := map[responses.PreflightCheck]responses.PreflightStatus{}
+		for _, rule := range profile.Rules {
+			if rule.Status != responses.PreflightStatusPass {
+				checks[rule.Check] = rule.Status
+			}
+		}
+		return checks
+	}
+
+	Context("a normal PDF file", func() {
+		var doc references.FPDF_DOCUMENT
+
+		BeforeEach(func() {
+			doc = openDocument("test.pdf")
+		})
+
+		AfterEach(func() {
+			closeDocument(doc)
+		})
+
+		When("Preflight is called", func() {
+			It("returns the information and checks all profiles", func() {
+				Preflight, err := PdfiumInstance.Preflight(&requests.Preflight{
+					Document: doc,
+				})
+				Expect(err).To(BeNil())
+				Expect(Preflight).To(Not(BeNil()))
+				Expect(Preflight.Information).To(Equal(responses.PreflightInformation{
+					FileVersion:      15,
+					CanPrint:         true,
+					Attachments:      []string{},
+					NonEmbeddedFonts: []string{},
+					TransparentPages: []int{},
+					FormType:         enums.FPDF_FORMTYPE_NONE,
+				}))
+				Expect(Preflight.Profiles).To(HaveLen(7))
+
+				passed := map[requests.PreflightProfile]bool{}
+				for _, profile := range Preflight.Profiles {
+					passed[profile.Profile] = profile.Passed
+				}
+				Expect(passed).To(Equal(map[requests.PreflightProfile]bool{
+					requests.PreflightProfilePDFA1A: false,
+					requests.PreflightProfilePDFA1B: true,
+					requests.PreflightProfilePDFA2A: false,
+					requests.PreflightProfilePDFA2B: true,
+					requests.PreflightProfilePDFA3A: false,
+					requests.PreflightProfilePDFA3B: true,
+					requests.PreflightProfilePrint:  true,
+				}))
+			})
+
+			It("returns the rules of the given profile", func() {
+				Preflight, err := PdfiumInstance.Preflight(&requests.Preflight{
+					Document: doc,
+					Profiles: []requests.PreflightProfile{requests.PreflightProfilePDFA1A},
+				})
+				Expect(err).To(BeNil())
+				Expect(Preflight).To(Not(BeNil()))
+				Expect(Preflight.Profiles).To(Equal([]responses.PreflightProfileResult{
+					{
+						Profile: requests.PreflightProfilePDFA1A,
+						Passed:  false,
+						Rules: []responses.PreflightRule{
+							{Check: responses.PreflightCheckVersion, Status: responses.PreflightStatusWarning, Message: "the file version 1.5 is newer than 1.4, the file might use features that are not allowed"},
+							{Check: responses.PreflightCheckEncryption, Status: responses.PreflightStatusPass},
+							{Check: responses.PreflightCheckJavaScript, Status: responses.PreflightStatusPass},
+							{Check: responses.PreflightCheckAttachments, Status: responses.PreflightStatusPass},
+							{Check: responses.PreflightCheckFonts, Status: responses.PreflightStatusPass},
+							{Check: responses.PreflightCheckTransparency, Status: responses.PreflightStatusPass},
+							{Check: responses.PreflightCheckTagged, Status: responses.PreflightStatusFail, Message: "the document is not tagged"},
+							{Check: responses.PreflightCheckForms, Status: responses.PreflightStatusPass},
+						},
+					},
+				}))
+			})
+		})
+	})
+
+	Context("a PDF file with attachments, JavaScript and fonts that are not embedded", func() {
+		var doc references.FPDF_DOCUMENT
+
+		BeforeEach(func() {
+			doc = openDocument("embedded_attachments.pdf")
+		})
+
+		AfterEach(func() {
+			closeDocument(doc)
+		})
+
+		When("Preflight is called", func() {
+			It("fails the profiles", func() {
+				Preflight, err := PdfiumInstance.Preflight(&requests.Preflight{
+					Document: doc,
+					Profiles: []requests.PreflightProfile{requests.PreflightProfilePDFA1B, requests.PreflightProfilePDFA2B, requests.PreflightProfilePDFA3B, requests.PreflightProfilePrint},
+				})
+				Expect(err).To(BeNil())
+				Expect(Preflight).To(Not(BeNil()))
+				Expect(Preflight.Information.JavaScriptActions).To(Equal(1))
+				Expect(Preflight.Information.Attachments).To(Equal([]string{"1.txt", "attached.pdf"}))
+				Expect(Preflight.Information.NonEmbeddedFonts).To(Equal([]string{"LucidaConsole"}))
+				Expect(Preflight.Profiles).To(HaveLen(4))
+
+				Expect(Preflight.Profiles[0].Passed).To(BeFalse())
+				Expect(failedChecks(Preflight.Profiles[0])).To(Equal(map[responses.PreflightCheck]responses.PreflightStatus{
+					responses.PreflightCheckVersion:     responses.PreflightStatusWarning,
+					responses.PreflightCheckJavaScript:  responses.PreflightStatusFail,
+					responses.PreflightCheckAttachments: responses.PreflightStatusFail,
+					responses.PreflightCheckFonts:       responses.PreflightStatusFail,
+				}))
+
+				Expect(Preflight.Profiles[1].Passed).To(BeFalse())
+				Expect(failedChecks(Preflight.Profiles[1])).To(Equal(map[responses.PreflightCheck]responses.PreflightStatus{
+					responses.PreflightCheckJavaScript:  responses.PreflightStatusFail,
+					responses.PreflightCheckAttachments: responses.PreflightStatusWarning,
+					responses.PreflightCheckFonts:       responses.PreflightStatusFail,
+				}))
+
+				Expect(Preflight.Profiles[2].Passed).To(BeFalse())
+				Expect(failedChecks(Preflight.Profiles[2])).To(Equal(map[responses.PreflightCheck]responses.PreflightStatus{
+					responses.PreflightCheckJavaScript: responses.PreflightStatusFail,
+					responses.PreflightCheckFonts:      responses.PreflightStatusFail,
+				}))
+
+				Expect(Preflight.Profiles[3].Passed).To(BeFalse())
+				Expect(failedChecks(Preflight.Profiles[3])).To(Equal(map[responses.PreflightCheck]responses.PreflightStatus{
+					responses.PreflightCheckJavaScript: responses.PreflightStatusWarning,
+					responses.PreflightCheckFonts:      responses.PreflightStatusFail,
+				}))
+			})
+		})
+	})
+
+	Context("a PDF file with transparency", func() {
+		var doc references.FPDF_DOCUMENT
+
+		BeforeEach(func() {
+			doc = openDocument("alpha_channel.pdf")
+		})
+
+		AfterEach(func() {
+			closeDocument(doc)
+		})
+
+		When("Preflight is called", func() {
+			It("only fails PDF/A-1", func() {
+				Preflight, err := PdfiumInstance.Preflight(&requests.Preflight{
+					Document: doc,
+					Profiles: []requests.PreflightProfile{requests.PreflightProfilePDFA1B, requests.PreflightProfilePDFA2B, requests.PreflightProfilePrint},
+				})
+				Expect(err).To(BeNil())
+				Expect(Preflight).To(Not(BeNil()))
+				Expect(Preflight.Information.TransparentPages).To(Equal([]int{0}))
+				Expect(Preflight.Profiles[0].Passed).To(BeFalse())
+				Expect(failedChecks(Preflight.Profiles[0])).To(HaveKeyWithValue(responses.PreflightCheckTransparency, responses.PreflightStatusFail))
+				Expect(Preflight.Profiles[1].Passed).To(BeTrue())
+				Expect(failedChecks(Preflight.Profiles[1])).To(BeEmpty())
+				Expect(Preflight.Profiles[2].Passed).To(BeTrue())
+				Expect(failedChecks(Preflight.Profiles[2])).To(Equal(map[responses.PreflightCheck]responses.PreflightStatus{
+					responses.PreflightCheckTransparency: responses.PreflightStatusWarning,
+				}))
+			})
+		})
+	})
+
+	Context("a tagged PDF file", func() {
+		var doc references.FPDF_DOCUMENT
+
+		BeforeEach(func() {
+			doc = openDocument("tagged_table.pdf")
+		})
+
+		AfterEach(func() {
+			closeDocument(doc)
+		})
+
+		When("Preflight is called", func() {
+			It("passes level A", func() {
+				Preflight, err := PdfiumInstance.Preflight(&requests.Preflight{
+					Document: doc,
+					Profiles: []requests.PreflightProfile{requests.PreflightProfilePDFA2A},
+				})
+				Expect(err).To(BeNil())
+				Expect(Preflight).To(Not(BeNil()))
+				Expect(Preflight.Information.IsTagged).To(BeTrue())
+				Expect(Preflight.Profiles[0].Passed).To(BeTrue())
+				Expect(failedChecks(Preflight.Profiles[0])).To(BeEmpty())
+			})
+		})
+	})
+
+	Context("a PDF file with an XFA form", func() {
+		var doc references.FPDF_DOCUMENT
+
+		BeforeEach(func() {
+			doc = openDocument("simple_xfa.pdf")
+		})
+
+		AfterEach(func() {
+			closeDocument(doc)
+		})
+
+		When("Preflight is called", func() {
+			It("fails PDF/A", func() {
+				Preflight, err := PdfiumInstance.Preflight(&requests.Preflight{
+					Document: doc,
+					Profiles: []requests.PreflightProfile{requests.PreflightProfilePDFA3B, requests.PreflightProfilePrint},
+				})
+				Expect(err).To(BeNil())
+				Expect(Preflight).To(Not(BeNil()))
+				Expect(Preflight.Information.FormType).To(Equal(enums.FPDF_FORMTYPE_XFA_FULL))
+				Expect(Preflight.Profiles[0].Passed).To(BeFalse())
+				Expect(failedChecks(Preflight.Profiles[0])).To(Equal(map[responses.PreflightCheck]responses.PreflightStatus{
+					responses.PreflightCheckForms: responses.PreflightStatusFail,
+				}))
+				Expect(Preflight.Profiles[1].Passed).To(BeTrue())
+				Expect(failedChecks(Preflight.Profiles[1])).To(Equal(map[responses.PreflightCheck]responses.PreflightStatus{
+					responses.PreflightCheckForms: responses.PreflightStatusWarning,
+				}))
+			})
+		})
+	})
+
+	Context("an encrypted PDF file that doesn't allow printing", func() {
+		var doc references.FPDF_DOCUMENT
+
+		BeforeEach(func() {
+			pdfPassword := "123test"
+			doc = openDocumentWithPassword("permissions_none.pdf", &pdfPassword)
+		})
+
+		AfterEach(func() {
+			closeDocument(doc)
+		})
+
+		When("Preflight is called", func() {
+			It("fails all profiles", func() {
+				Preflight, err := PdfiumInstance.Preflight(&requests.Preflight{
+					Document: doc,
+					Profiles: []requests.PreflightProfile{requests.PreflightProfilePDFA2B, requests.PreflightProfilePrint},
+				})
+				Expect(err).To(BeNil())
+				Expect(Preflight).To(Not(BeNil()))
+				Expect(Preflight.Information.IsEncrypted).To(BeTrue())
+				Expect(Preflight.Information.CanPrint).To(BeFalse())
+				Expect(Preflight.Profiles[0].Passed).To(BeFalse())
+				Expect(Preflight.Profiles[0].Rules[1]).To(Equal(responses.PreflightRule{
+					Check:   responses.PreflightCheckEncryption,
+					Status:  responses.PreflightStatusFail,
+					Message: "the document is encrypted",
+				}))
+				Expect(Preflight.Profiles[1].Passed).To(BeFalse())
+				Expect(Preflight.Profiles[1].Rules[0]).To(Equal(responses.PreflightRule{
+					Check:   responses.PreflightCheckEncryption,
+					Status:  responses.PreflightStatusFail,
+					Message: "the permissions of the document don't allow printing",
+				}))
+			})
+		})
+	})
+})
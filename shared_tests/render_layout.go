@@ -0,0 +1,254 @@
+package shared_tests
+
+import (
+	"image/color"
+	"io/ioutil"
+
+	"github.com/klippa-app/go-pdfium/references"
+	"github.com/klippa-app/go-pdfium/requests"
+	"github.com/klippa-app/go-pdfium/responses"
+	"github.com/klippa-app/go-pdfium/structs"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Render layout", func() {
+	BeforeEach(func() {
+		Locker.Lock()
+	})
+
+	AfterEach(func() {
+		Locker.Unlock()
+	})
+
+	Context("a PDF file with page labels", func() {
+		var doc references.FPDF_DOCUMENT
+
+		BeforeEach(func() {
+			pdfData, err := ioutil.ReadFile(TestDataPath + "/testdata/page_labels.pdf")
+			Expect(err).To(BeNil())
+
+			newDoc, err := PdfiumInstance.FPDF_LoadMemDocument(&requests.FPDF_LoadMemDocument{
+				Data: &pdfData,
+			})
+			Expect(err).To(BeNil())
+
+			doc = newDoc.Document
+		})
+
+		AfterEach(func() {
+			FPDF_CloseDocument, err := PdfiumInstance.FPDF_CloseDocument(&requests.FPDF_CloseDocument{
+				Document: doc,
+			})
+			Expect(err).To(BeNil())
+			Expect(FPDF_CloseDocument).To(Not(BeNil()))
+		})
+
+		pagesInDPI := func(count int) []requests.RenderPageInDPI {
+			pages := make([]requests.RenderPageInDPI, count)
+			for i := range pages {
+				pages[i] = requests.RenderPageInDPI{
+					Page: requests.Page{
+						ByIndex: &requests.PageByIndex{
+							Document: doc,
+							Index:    i,
+						},
+					},
+					DPI: 72,
+				}
+			}
+			return pages
+		}
+
+		pagesInPixels := func(count int) []requests.RenderPageInPixels {
+			pages := make([]requests.RenderPageInPixels, count)
+			for i := range pages {
+				pages[i] = requests.RenderPageInPixels{
+					Page: requests.Page{
+						ByIndex: &requests.PageByIndex{
+							Document: doc,
+							Index:    i,
+						},
+					},
+					Width: 100,
+				}
+			}
+			return pages
+		}
+
+		Context("an invalid layout is given", func() {
+			It("returns an error when no columns are given for the grid layout", func() {
+				renderedPages, err := PdfiumInstance.RenderPagesInDPI(&requests.RenderPagesInDPI{
+					Pages: pagesInDPI(2),
+					Layout: requests.RenderPagesLayout{
+						Mode: requests.RenderPagesLayoutModeGrid,
+					},
+				})
+				Expect(err).To(MatchError("no columns given for the grid layout"))
+				Expect(renderedPages).To(BeNil())
+			})
+
+			It("returns an error when an invalid mode is given", func() {
+				renderedPages, err := PdfiumInstance.RenderPagesInPixels(&requests.RenderPagesInPixels{
+					Pages: pagesInPixels(2),
+					Layout: requests.RenderPagesLayout{
+						Mode: "diagonal",
+					},
+				})
+				Expect(err).To(MatchError("invalid layout mode diagonal given"))
+				Expect(renderedPages).To(BeNil())
+			})
+
+			It("returns an error when an invalid label is given", func() {
+				renderedPages, err := PdfiumInstance.RenderPagesInDPI(&requests.RenderPagesInDPI{
+					Pages: pagesInDPI(2),
+					Layout: requests.RenderPagesLayout{
+						Label: "title",
+					},
+				})
+				Expect(err).To(MatchError("invalid layout label title given"))
+				Expect(renderedPages).To(BeNil())
+			})
+
+			It("returns an error when an invalid maximum size is given", func() {
+				renderedPages, err := PdfiumInstance.RenderPagesInDPI(&requests.RenderPagesInDPI{
+					Pages: pagesInDPI(2),
+					Layout: requests.RenderPagesLayout{
+						MaxWidth: -1,
+					},
+				})
+				Expect(err).To(MatchError("invalid maximum width or height given"))
+				Expect(renderedPages).To(BeNil())
+			})
+
+			It("returns an error when an invalid label height is given", func() {
+				renderedPages, err := PdfiumInstance.RenderPagesInDPI(&requests.RenderPagesInDPI{
+					Pages: pagesInDPI(2),
+					Layout: requests.RenderPagesLayout{
+						Label:       requests.RenderPagesLayoutLabelPageNumber,
+						LabelHeight: -20,
+					},
+				})
+				Expect(err).To(MatchError("invalid label height given"))
+				Expect(renderedPages).To(BeNil())
+			})
+
+			It("returns an error when the maximum size leaves no space for the pages", func() {
+				renderedPages, err := PdfiumInstance.RenderPagesInDPI(&requests.RenderPagesInDPI{
+					Pages: pagesInDPI(2),
+					Layout: requests.RenderPagesLayout{
+						Label:     requests.RenderPagesLayoutLabelPageNumber,
+						MaxHeight: 40,
+					},
+				})
+				Expect(err).To(MatchError("maximum height 40 is too small for the padding and labels, it needs to be larger than 40"))
+				Expect(renderedPages).To(BeNil())
+			})
+		})
+
+		Context("the pages are rendered in a grid", func() {
+			It("returns the pages in rows of the given amount of columns with the page labels", func() {
+				renderedPages, err := PdfiumInstance.RenderPagesInDPI(&requests.RenderPagesInDPI{
+					Pages:   pagesInDPI(7),
+					Padding: 10,
+					Layout: requests.RenderPagesLayout{
+						Mode:    requests.RenderPagesLayoutModeGrid,
+						Columns: 2,
+						Label:   requests.RenderPagesLayoutLabelPageLabel,
+						BackgroundColor: &structs.FPDF_COLOR{
+							B: 128,
+							A: 255,
+						},
+					},
+				})
+				Expect(err).To(BeNil())
+				Expect(renderedPages).To(Not(BeNil()))
+				defer renderedPages.Cleanup()
+
+				Expect(renderedPages.Result.Width).To(Equal(1234))
+				Expect(renderedPages.Result.Height).To(Equal(3278))
+				Expect(renderedPages.Result.Pages).To(Equal([]responses.RenderPagesPage{
+					{Page: 0, PointToPixelRatio: 1, Width: 612, Height: 792, X: 0, Y: 0, Label: "i"},
+					{Page: 1, PointToPixelRatio: 1, Width: 612, Height: 792, X: 622, Y: 0, Label: "ii"},
+					{Page: 2, PointToPixelRatio: 1, Width: 612, Height: 792, X: 0, Y: 822, Label: "1"},
+					{Page: 3, PointToPixelRatio: 1, Width: 612, Height: 792, X: 622, Y: 822, Label: "2"},
+					{Page: 4, PointToPixelRatio: 1, Width: 612, Height: 792, X: 0, Y: 1644, Label: "zzA"},
+					{Page: 5, PointToPixelRatio: 1, Width: 612, Height: 792, X: 622, Y: 1644, Label: "zzB"},
+					{Page: 6, PointToPixelRatio: 1, Width: 612, Height: 792, X: 0, Y: 2466, Label: "7"},
+				}))
+
+				// The padding and the empty cell have the background color.
+				Expect(renderedPages.Result.Image.RGBAAt(617, 10)).To(Equal(color.RGBA{B: 128, A: 255}))
+				Expect(renderedPages.Result.Image.RGBAAt(1000, 3000)).To(Equal(color.RGBA{B: 128, A: 255}))
+				Expect(renderedPages.Result.Image.RGBAAt(300, 400)).To(Equal(color.RGBA{R: 255, G: 255, B: 255, A: 255}))
+
+				// The labels are white on a dark background.
+				labelPixels := 0
+				for y := 792; y < 812; y++ {
+					for x := 0; x < 612; x++ {
+						if renderedPages.Result.Image.RGBAAt(x, y) == (color.RGBA{R: 255, G: 255, B: 255, A: 255}) {
+							labelPixels++
+						}
+					}
+				}
+				Expect(labelPixels).To(BeNumerically(">", 0))
+			})
+
+			It("scales the pages down to fit in the maximum size", func() {
+				renderedPages, err := PdfiumInstance.RenderPagesInDPI(&requests.RenderPagesInDPI{
+					Pages:   pagesInDPI(7),
+					Padding: 10,
+					Layout: requests.RenderPagesLayout{
+						Mode:      requests.RenderPagesLayoutModeGrid,
+						Columns:   3,
+						MaxWidth:  300,
+						MaxHeight: 300,
+					},
+				})
+				Expect(err).To(BeNil())
+				Expect(renderedPages).To(Not(BeNil()))
+				defer renderedPages.Cleanup()
+
+				Expect(renderedPages.Result.Width).To(Equal(236))
+				Expect(renderedPages.Result.Height).To(Equal(299))
+				Expect(renderedPages.Result.Pages[0].PointToPixelRatio).To(BeNumerically("~", 0.1178, 0.0001))
+				Expect(renderedPages.Result.Pages[4].X).To(Equal(82))
+				Expect(renderedPages.Result.Pages[4].Y).To(Equal(103))
+				Expect(renderedPages.Result.Pages[6].Y).To(Equal(206))
+			})
+		})
+
+		Context("the pages are rendered in a strip", func() {
+			It("returns the pages next to each other with the page numbers", func() {
+				renderedPages, err := PdfiumInstance.RenderPagesInPixels(&requests.RenderPagesInPixels{
+					Pages:   pagesInPixels(3),
+					Padding: 5,
+					Layout: requests.RenderPagesLayout{
+						Mode:        requests.RenderPagesLayoutModeHorizontal,
+						Label:       requests.RenderPagesLayoutLabelPageNumber,
+						LabelHeight: 30,
+					},
+				})
+				Expect(err).To(BeNil())
+				Expect(renderedPages).To(Not(BeNil()))
+				defer renderedPages.Cleanup()
+
+				Expect(renderedPages.Result.Width).To(Equal(310))
+				Expect(renderedPages.Result.Height).To(Equal(160))
+				Expect(renderedPages.Result.Pages).To(HaveLen(3))
+				for i, page := range renderedPages.Result.Pages {
+					Expect(page.X).To(Equal(i * 105))
+					Expect(page.Y).To(Equal(0))
+					Expect(page.Width).To(Equal(100))
+					Expect(page.Height).To(Equal(130))
+				}
+				Expect(renderedPages.Result.Pages[0].Label).To(Equal("1"))
+				Expect(renderedPages.Result.Pages[2].Label).To(Equal("3"))
+
+				// Without background color the padding is transparent.
+				Expect(renderedPages.Result.Image.RGBAAt(102, 10)).To(Equal(color.RGBA{}))
+			})
+		})
+	})
+})
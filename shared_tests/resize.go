@@ -0,0 +1,295 @@
+package shared_tests
+
+import (
+	"io/ioutil"
+
+	"github.com/klippa-app/go-pdfium/enums"
+	"github.com/klippa-app/go-pdfium/references"
+	"github.com/klippa-app/go-pdfium/requests"
+	"github.com/klippa-app/go-pdfium/responses"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("resize", func() {
+	BeforeEach(func() {
+		Locker.Lock()
+	})
+
+	AfterEach(func() {
+		Locker.Unlock()
+	})
+
+	Context("no document", func() {
+		When("is opened", func() {
+			It("returns an error when calling ResizePages", func() {
+				ResizePages, err := PdfiumInstance.ResizePages(&requests.ResizePages{
+					Width:  612,
+					Height: 792,
+				})
+				Expect(err).To(MatchError("document not given"))
+				Expect(ResizePages).To(BeNil())
+			})
+		})
+	})
+
+	Context("a normal PDF file", func() {
+		var doc references.FPDF_DOCUMENT
+		var page requests.Page
+
+		BeforeEach(func() {
+			pdfData, err := ioutil.ReadFile(TestDataPath + "/testdata/test.pdf")
+			Expect(err).To(BeNil())
+
+			newDoc, err := PdfiumInstance.FPDF_LoadMemDocument(&requests.FPDF_LoadMemDocument{
+				Data: &pdfData,
+			})
+			Expect(err).To(BeNil())
+
+			doc = newDoc.Document
+			page = requests.Page{
+				ByIndex: &requests.PageByIndex{
+					Document: doc,
+					Index:    0,
+				},
+			}
+		})
+
+		AfterEach(func() {
+			FPDF_CloseDocument, err := PdfiumInstance.FPDF_CloseDocument(&requests.FPDF_CloseDocument{
+				Document: doc,
+			})
+			Expect(err).To(BeNil())
+			Expect(FPDF_CloseDocument).To(Not(BeNil()))
+		})
+
+		It("returns an error when no target size is given", func() {
+			ResizePages, err := PdfiumInstance.ResizePages(&requests.ResizePages{
+				Document: doc,
+			})
+			Expect(err).To(MatchError("no valid target size given"))
+			Expect(ResizePages).To(BeNil())
+		})
+
+		It("returns an error when an invalid mode is given", func() {
+			ResizePages, err := PdfiumInstance.ResizePages(&requests.ResizePages{
+				Document: doc,
+				Width:    612,
+				Height:   792,
+				Mode:     "stretch",
+			})
+			Expect(err).To(MatchError("invalid resize mode given"))
+			Expect(ResizePages).To(BeNil())
+		})
+
+		It("resizes an A4 page to letter by fitting the content", func() {
+			ResizePages, err := PdfiumInstance.ResizePages(&requests.ResizePages{
+				Document: doc,
+				Width:    612,
+				Height:   792,
+			})
+			Expect(err).To(BeNil())
+			Expect(ResizePages.Pages).To(HaveLen(1))
+			Expect(ResizePages.Pages[0].Scale).To(BeNumerically("~", 0.9407, 0.0001))
+			Expect(ResizePages.Pages[0].Width).To(Equal(float32(612)))
+			Expect(ResizePages.Pages[0].Height).To(Equal(float32(792)))
+			Expect(ResizePages.Pages[0].Rotation).To(Equal(enums.FPDF_PAGE_ROTATION_NONE))
+
+			FPDFPage_GetMediaBox, err := PdfiumInstance.FPDFPage_GetMediaBox(&requests.FPDFPage_GetMediaBox{
+				Page: page,
+			})
+			Expect(err).To(BeNil())
+			Expect(FPDFPage_GetMediaBox).To(Equal(&responses.FPDFPage_GetMediaBox{
+				Left:   0,
+				Bottom: 0,
+				Right:  612,
+				Top:    792,
+			}))
+
+			FPDFPage_GetCropBox, err := PdfiumInstance.FPDFPage_GetCropBox(&requests.FPDFPage_GetCropBox{
+				Page: page,
+			})
+			Expect(err).To(BeNil())
+			Expect(FPDFPage_GetCropBox).To(Equal(&responses.FPDFPage_GetCropBox{
+				Left:   0,
+				Bottom: 0,
+				Right:  612,
+				Top:    792,
+			}))
+		})
+
+		It("resizes an A4 page to letter by filling the page", func() {
+			ResizePages, err := PdfiumInstance.ResizePages(&requests.ResizePages{
+				Document: doc,
+				Width:    612,
+				Height:   792,
+				Mode:     requests.ResizePagesModeFill,
+			})
+			Expect(err).To(BeNil())
+			Expect(ResizePages.Pages).To(HaveLen(1))
+			Expect(ResizePages.Pages[0].Scale).To(BeNumerically("~", 1.0281, 0.0001))
+		})
+
+		It("does not upscale when that is not allowed", func() {
+			ResizePages, err := PdfiumInstance.ResizePages(&requests.ResizePages{
+				Document:  doc,
+				Width:     841.89,
+				Height:    1190.55,
+				NoUpscale: true,
+			})
+			Expect(err).To(BeNil())
+			Expect(ResizePages.Pages).To(HaveLen(1))
+			Expect(ResizePages.Pages[0].Scale).To(Equal(float64(1)))
+			Expect(ResizePages.Pages[0].Width).To(BeNumerically("~", 841.89, 0.01))
+			Expect(ResizePages.Pages[0].Height).To(BeNumerically("~", 1190.55, 0.01))
+		})
+
+		It("matches the orientation of the page when requested", func() {
+			ResizePages, err := PdfiumInstance.ResizePages(&requests.ResizePages{
+				Document:         doc,
+				Width:            792,
+				Height:           612,
+				MatchOrientation: true,
+			})
+			Expect(err).To(BeNil())
+			Expect(ResizePages.Pages).To(HaveLen(1))
+			Expect(ResizePages.Pages[0].Width).To(Equal(float32(612)))
+			Expect(ResizePages.Pages[0].Height).To(Equal(float32(792)))
+		})
+
+		It("moves the content to the resized page", func() {
+			_, err := PdfiumInstance.ResizePages(&requests.ResizePages{
+				Document: doc,
+				Width:    612,
+				Height:   792,
+			})
+			Expect(err).To(BeNil())
+
+			// Reload the document to make sure the new content is parsed.
+			FPDF_SaveAsCopy, err := PdfiumInstance.FPDF_SaveAsCopy(&requests.FPDF_SaveAsCopy{
+				Document: doc,
+			})
+			Expect(err).To(BeNil())
+
+			newDoc, err := PdfiumInstance.FPDF_LoadMemDocument(&requests.FPDF_LoadMemDocument{
+				Data: FPDF_SaveAsCopy.FileBytes,
+			})
+			Expect(err).To(BeNil())
+
+			CropPages, err := PdfiumInstance.CropPages(&requests.CropPages{
+				Document: newDoc.Document,
+				Mode:     requests.CropPagesModeObjects,
+			})
+			Expect(err).To(BeNil())
+			Expect(CropPages.Pages).To(HaveLen(1))
+			Expect(CropPages.Pages[0].Left).To(BeNumerically("~", 92.0, 1))
+			Expect(CropPages.Pages[0].Bottom).To(BeNumerically("~", 717.0, 1))
+			Expect(CropPages.Pages[0].Right).To(BeNumerically("~", 520.0, 1))
+			Expect(CropPages.Pages[0].Top).To(BeNumerically("~", 750.0, 1))
+
+			FPDF_CloseDocument, err := PdfiumInstance.FPDF_CloseDocument(&requests.FPDF_CloseDocument{
+				Document: newDoc.Document,
+			})
+			Expect(err).To(BeNil())
+			Expect(FPDF_CloseDocument).To(Not(BeNil()))
+		})
+	})
+
+	Context("a PDF file with a rotated page", func() {
+		var doc references.FPDF_DOCUMENT
+		var page requests.Page
+		pageRange := "2"
+
+		BeforeEach(func() {
+			pdfData, err := ioutil.ReadFile(TestDataPath + "/testdata/rectangles_multi_pages.pdf")
+			Expect(err).To(BeNil())
+
+			newDoc, err := PdfiumInstance.FPDF_LoadMemDocument(&requests.FPDF_LoadMemDocument{
+				Data: &pdfData,
+			})
+			Expect(err).To(BeNil())
+
+			doc = newDoc.Document
+			page = requests.Page{
+				ByIndex: &requests.PageByIndex{
+					Document: doc,
+					Index:    1,
+				},
+			}
+		})
+
+		AfterEach(func() {
+			FPDF_CloseDocument, err := PdfiumInstance.FPDF_CloseDocument(&requests.FPDF_CloseDocument{
+				Document: doc,
+			})
+			Expect(err).To(BeNil())
+			Expect(FPDF_CloseDocument).To(Not(BeNil()))
+		})
+
+		It("keeps the page rotation by default", func() {
+			ResizePages, err := PdfiumInstance.ResizePages(&requests.ResizePages{
+				Document:  doc,
+				PageRange: &pageRange,
+				Width:     612,
+				Height:    792,
+			})
+			Expect(err).To(BeNil())
+			Expect(ResizePages).To(Equal(&responses.ResizePages{
+				Pages: []responses.ResizePagesPage{
+					{Page: 1, Scale: 2.448, Width: 612, Height: 792, Rotation: enums.FPDF_PAGE_ROTATION_90_CW},
+				},
+			}))
+
+			FPDFPage_GetMediaBox, err := PdfiumInstance.FPDFPage_GetMediaBox(&requests.FPDFPage_GetMediaBox{
+				Page: page,
+			})
+			Expect(err).To(BeNil())
+			Expect(FPDFPage_GetMediaBox).To(Equal(&responses.FPDFPage_GetMediaBox{
+				Left:   0,
+				Bottom: 0,
+				Right:  792,
+				Top:    612,
+			}))
+
+			FPDFPage_GetRotation, err := PdfiumInstance.FPDFPage_GetRotation(&requests.FPDFPage_GetRotation{
+				Page: page,
+			})
+			Expect(err).To(BeNil())
+			Expect(FPDFPage_GetRotation.PageRotation).To(Equal(enums.FPDF_PAGE_ROTATION_90_CW))
+		})
+
+		It("normalizes the page rotation when requested", func() {
+			ResizePages, err := PdfiumInstance.ResizePages(&requests.ResizePages{
+				Document:          doc,
+				PageRange:         &pageRange,
+				Width:             612,
+				Height:            792,
+				NormalizeRotation: true,
+			})
+			Expect(err).To(BeNil())
+			Expect(ResizePages).To(Equal(&responses.ResizePages{
+				Pages: []responses.ResizePagesPage{
+					{Page: 1, Scale: 2.448, Width: 612, Height: 792, Rotation: enums.FPDF_PAGE_ROTATION_NONE},
+				},
+			}))
+
+			FPDFPage_GetMediaBox, err := PdfiumInstance.FPDFPage_GetMediaBox(&requests.FPDFPage_GetMediaBox{
+				Page: page,
+			})
+			Expect(err).To(BeNil())
+			Expect(FPDFPage_GetMediaBox).To(Equal(&responses.FPDFPage_GetMediaBox{
+				Left:   0,
+				Bottom: 0,
+				Right:  612,
+				Top:    792,
+			}))
+
+			FPDFPage_GetRotation, err := PdfiumInstance.FPDFPage_GetRotation(&requests.FPDFPage_GetRotation{
+				Page: page,
+			})
+			Expect(err).To(BeNil())
+			Expect(FPDFPage_GetRotation.PageRotation).To(Equal(enums.FPDF_PAGE_ROTATION_NONE))
+		})
+	})
+})
@@ -0,0 +1,34 @@
+package shared_tests
+
+import (
+	"github.com/klippa-app/go-pdfium/requests"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("signature", func() {
+	BeforeEach(func() {
+		Locker.Lock()
+	})
+
+	AfterEach(func() {
+		Locker.Unlock()
+	})
+
+	Context("no document", func() {
+		When("is opened", func() {
+			It("returns an error when calling VerifySignatures", func() {
+				VerifySignatures, err := PdfiumInstance.VerifySignatures(&requests.VerifySignatures{})
+				Expect(err).To(MatchError("document not given"))
+				Expect(VerifySignatures).To(BeNil())
+			})
+
+			It("returns an error when calling SignDocument", func() {
+				SignDocument, err := PdfiumInstance.SignDocument(&requests.SignDocument{})
+				Expect(err).To(MatchError("document not given"))
+				Expect(SignDocument).To(BeNil())
+			})
+		})
+	})
+})
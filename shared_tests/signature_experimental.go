@@ -0,0 +1,726 @@
+//go:build pdfium_experimental
+// +build pdfium_experimental
+
+package shared_tests
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"time"
+
+	"github.com/klippa-app/go-pdfium/enums"
+	"github.com/klippa-app/go-pdfium/references"
+	"github.com/klippa-app/go-pdfium/requests"
+	"github.com/klippa-app/go-pdfium/responses"
+	"github.com/klippa-app/go-pdfium/structs"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("signature_experimental", func() {
+	BeforeEach(func() {
+		Locker.Lock()
+	})
+
+	AfterEach(func() {
+		Locker.Unlock()
+	})
+
+	var rootCertificate []byte
+
+	BeforeEach(func() {
+		pemData, err := ioutil.ReadFile(TestDataPath + "/testdata/signature_root.pem")
+		Expect(err).To(BeNil())
+		rootCertificate = pemData
+	})
+
+	// expectSignedSignature checks the parts of the signature of the signed
+	// test files that don't depend on the verification.
+	expectSignedSignature := func(signature responses.VerifySignaturesSignature) {
+		subFilter := "adbe.pkcs7.detached"
+		reason := "Approved"
+		signingTime := "D:20240601120000+00'00'"
+		Expect(signature.Index).To(Equal(0))
+		Expect(signature.SubFilter).To(Equal(&subFilter))
+		Expect(signature.Reason).To(Equal(&reason))
+		Expect(signature.Time).To(Equal(&signingTime))
+		Expect(signature.SigningTime).To(Not(BeNil()))
+		Expect(signature.SigningTime.Equal(time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC))).To(BeTrue())
+		Expect(signature.DocMDPPermission).To(Equal(0))
+		Expect(signature.ByteRange).To(Equal([]int{0, 677, 8871, 331}))
+		Expect(signature.DigestAlgorithm).To(Equal("SHA-256"))
+		Expect(signature.Signer).To(Not(BeNil()))
+		Expect(signature.Signer.Subject).To(Equal("CN=go-pdfium Test Signer,O=go-pdfium"))
+		Expect(signature.Signer.Issuer).To(Equal("CN=go-pdfium Test Root,O=go-pdfium"))
+		Expect(signature.Signer.SerialNumber).To(Equal("2"))
+		Expect(signature.Signer.NotAfter).To(Equal(time.Date(2124, 1, 1, 0, 0, 0, 0, time.UTC)))
+		Expect(signature.Certificates).To(HaveLen(2))
+		Expect(signature.Certificates[0].Raw).To(Equal(signature.Signer.Raw))
+		Expect(signature.Certificates[1].Subject).To(Equal("CN=go-pdfium Test Root,O=go-pdfium"))
+	}
+
+	Context("a signed PDF file", func() {
+		var doc references.FPDF_DOCUMENT
+
+		BeforeEach(func() {
+			pdfData, err := ioutil.ReadFile(TestDataPath + "/testdata/signature_valid.pdf")
+			Expect(err).To(BeNil())
+
+			newDoc, err := PdfiumInstance.FPDF_LoadMemDocument(&requests.FPDF_LoadMemDocument{
+				Data: &pdfData,
+			})
+			Expect(err).To(BeNil())
+
+			doc = newDoc.Document
+		})
+
+		AfterEach(func() {
+			FPDF_CloseDocument, err := PdfiumInstance.FPDF_CloseDocument(&requests.FPDF_CloseDocument{
+				Document: doc,
+			})
+			Expect(err).To(BeNil())
+			Expect(FPDF_CloseDocument).To(Not(BeNil()))
+		})
+
+		When("VerifySignatures is called", func() {
+			It("returns that the signature is valid when the root is trusted", func() {
+				VerifySignatures, err := PdfiumInstance.VerifySignatures(&requests.VerifySignatures{
+					Document:            doc,
+					TrustedCertificates: [][]byte{rootCertificate},
+				})
+				Expect(err).To(BeNil())
+				Expect(VerifySignatures).To(Not(BeNil()))
+				Expect(VerifySignatures.Signatures).To(HaveLen(1))
+
+				signature := VerifySignatures.Signatures[0]
+				expectSignedSignature(signature)
+				Expect(signature.Status).To(Equal(responses.VerifySignaturesStatusValid))
+				Expect(signature.Error).To(Equal(""))
+				Expect(signature.DigestValid).To(BeTrue())
+				Expect(signature.SignatureValid).To(BeTrue())
+				Expect(signature.CertificateTrusted).To(BeTrue())
+				Expect(signature.ModifiedAfterSigning).To(BeFalse())
+			})
+
+			It("accepts trusted certificates as DER", func() {
+				block, _ := pem.Decode(rootCertificate)
+				Expect(block).To(Not(BeNil()))
+
+				VerifySignatures, err := PdfiumInstance.VerifySignatures(&requests.VerifySignatures{
+					Document:            doc,
+					TrustedCertificates: [][]byte{block.Bytes},
+				})
+				Expect(err).To(BeNil())
+				Expect(VerifySignatures).To(Not(BeNil()))
+				Expect(VerifySignatures.Signatures).To(HaveLen(1))
+				Expect(VerifySignatures.Signatures[0].Status).To(Equal(responses.VerifySignaturesStatusValid))
+			})
+
+			It("returns that the signature is untrusted when no roots are given", func() {
+				VerifySignatures, err := PdfiumInstance.VerifySignatures(&requests.VerifySignatures{
+					Document: doc,
+				})
+				Expect(err).To(BeNil())
+				Expect(VerifySignatures).To(Not(BeNil()))
+				Expect(VerifySignatures.Signatures).To(HaveLen(1))
+
+				signature := VerifySignatures.Signatures[0]
+				expectSignedSignature(signature)
+				Expect(signature.Status).To(Equal(responses.VerifySignaturesStatusUntrusted))
+				Expect(signature.Error).To(Equal("the certificate of the signer is not trusted: x509: certificate signed by unknown authority"))
+				Expect(signature.DigestValid).To(BeTrue())
+				Expect(signature.SignatureValid).To(BeTrue())
+				Expect(signature.CertificateTrusted).To(BeFalse())
+			})
+
+			It("returns an error when a trusted certificate is invalid", func() {
+				VerifySignatures, err := PdfiumInstance.VerifySignatures(&requests.VerifySignatures{
+					Document:            doc,
+					TrustedCertificates: [][]byte{rootCertificate, []byte("-----BEGIN PUBLIC KEY-----\n-----END PUBLIC KEY-----\n")},
+				})
+				Expect(err).To(MatchError("could not parse certificate 1: no certificate found in PEM"))
+				Expect(VerifySignatures).To(BeNil())
+			})
+		})
+	})
+
+	Context("a signed PDF file that was opened from a path", func() {
+		var doc references.FPDF_DOCUMENT
+
+		BeforeEach(func() {
+			filePath := TestDataPath + "/testdata/signature_valid.pdf"
+			newDoc, err := PdfiumInstance.FPDF_LoadDocument(&requests.FPDF_LoadDocument{
+				Path: &filePath,
+			})
+			Expect(err).To(BeNil())
+
+			doc = newDoc.Document
+		})
+
+		AfterEach(func() {
+			FPDF_CloseDocument, err := PdfiumInstance.FPDF_CloseDocument(&requests.FPDF_CloseDocument{
+				Document: doc,
+			})
+			Expect(err).To(BeNil())
+			Expect(FPDF_CloseDocument).To(Not(BeNil()))
+		})
+
+		When("VerifySignatures is called", func() {
+			It("returns an error when the file is not given", func() {
+				VerifySignatures, err := PdfiumInstance.VerifySignatures(&requests.VerifySignatures{
+					Document:            doc,
+					TrustedCertificates: [][]byte{rootCertificate},
+				})
+				Expect(err).To(MatchError("the original file of the document is not available, give it in File"))
+				Expect(VerifySignatures).To(BeNil())
+			})
+
+			It("verifies the signature over the given file", func() {
+				pdfData, err := ioutil.ReadFile(TestDataPath + "/testdata/signature_valid.pdf")
+				Expect(err).To(BeNil())
+
+				VerifySignatures, err := PdfiumInstance.VerifySignatures(&requests.VerifySignatures{
+					Document:            doc,
+					File:                &pdfData,
+					TrustedCertificates: [][]byte{rootCertificate},
+				})
+				Expect(err).To(BeNil())
+				Expect(VerifySignatures).To(Not(BeNil()))
+				Expect(VerifySignatures.Signatures).To(HaveLen(1))
+				Expect(VerifySignatures.Signatures[0].Status).To(Equal(responses.VerifySignaturesStatusValid))
+			})
+		})
+	})
+
+	Context("a signed PDF file with an incremental update after signing", func() {
+		var doc references.FPDF_DOCUMENT
+
+		BeforeEach(func() {
+			pdfData, err := ioutil.ReadFile(TestDataPath + "/testdata/signature_incremental_update.pdf")
+			Expect(err).To(BeNil())
+
+			newDoc, err := PdfiumInstance.FPDF_LoadMemDocument(&requests.FPDF_LoadMemDocument{
+				Data: &pdfData,
+			})
+			Expect(err).To(BeNil())
+
+			doc = newDoc.Document
+		})
+
+		AfterEach(func() {
+			FPDF_CloseDocument, err := PdfiumInstance.FPDF_CloseDocument(&requests.FPDF_CloseDocument{
+				Document: doc,
+			})
+			Expect(err).To(BeNil())
+			Expect(FPDF_CloseDocument).To(Not(BeNil()))
+		})
+
+		When("VerifySignatures is called", func() {
+			It("returns that the signature is valid and that the file was modified after signing", func() {
+				VerifySignatures, err := PdfiumInstance.VerifySignatures(&requests.VerifySignatures{
+					Document:            doc,
+					TrustedCertificates: [][]byte{rootCertificate},
+				})
+				Expect(err).To(BeNil())
+				Expect(VerifySignatures).To(Not(BeNil()))
+				Expect(VerifySignatures.Signatures).To(HaveLen(1))
+
+				signature := VerifySignatures.Signatures[0]
+				expectSignedSignature(signature)
+				Expect(signature.Status).To(Equal(responses.VerifySignaturesStatusValid))
+				Expect(signature.DigestValid).To(BeTrue())
+				Expect(signature.SignatureValid).To(BeTrue())
+				Expect(signature.ModifiedAfterSigning).To(BeTrue())
+			})
+		})
+	})
+
+	Context("a signed PDF file that was changed within the signed data", func() {
+		var doc references.FPDF_DOCUMENT
+
+		BeforeEach(func() {
+			pdfData, err := ioutil.ReadFile(TestDataPath + "/testdata/signature_tampered.pdf")
+			Expect(err).To(BeNil())
+
+			newDoc, err := PdfiumInstance.FPDF_LoadMemDocument(&requests.FPDF_LoadMemDocument{
+				Data: &pdfData,
+			})
+			Expect(err).To(BeNil())
+
+			doc = newDoc.Document
+		})
+
+		AfterEach(func() {
+			FPDF_CloseDocument, err := PdfiumInstance.FPDF_CloseDocument(&requests.FPDF_CloseDocument{
+				Document: doc,
+			})
+			Expect(err).To(BeNil())
+			Expect(FPDF_CloseDocument).To(Not(BeNil()))
+		})
+
+		When("VerifySignatures is called", func() {
+			It("returns that the signature is invalid", func() {
+				VerifySignatures, err := PdfiumInstance.VerifySignatures(&requests.VerifySignatures{
+					Document:            doc,
+					TrustedCertificates: [][]byte{rootCertificate},
+				})
+				Expect(err).To(BeNil())
+				Expect(VerifySignatures).To(Not(BeNil()))
+				Expect(VerifySignatures.Signatures).To(HaveLen(1))
+
+				signature := VerifySignatures.Signatures[0]
+				expectSignedSignature(signature)
+				Expect(signature.Status).To(Equal(responses.VerifySignaturesStatusInvalid))
+				Expect(signature.Error).To(Equal("the signed data was changed after signing"))
+				Expect(signature.DigestValid).To(BeFalse())
+				Expect(signature.SignatureValid).To(BeTrue())
+				Expect(signature.ModifiedAfterSigning).To(BeFalse())
+			})
+		})
+	})
+
+	Context("a PDF file with two signatures", func() {
+		var doc references.FPDF_DOCUMENT
+
+		BeforeEach(func() {
+			pdfData, err := ioutil.ReadFile(TestDataPath + "/testdata/two_signatures.pdf")
+			Expect(err).To(BeNil())
+
+			newDoc, err := PdfiumInstance.FPDF_LoadMemDocument(&requests.FPDF_LoadMemDocument{
+				Data: &pdfData,
+			})
+			Expect(err).To(BeNil())
+
+			doc = newDoc.Document
+		})
+
+		AfterEach(func() {
+			FPDF_CloseDocument, err := PdfiumInstance.FPDF_CloseDocument(&requests.FPDF_CloseDocument{
+				Document: doc,
+			})
+			Expect(err).To(BeNil())
+			Expect(FPDF_CloseDocument).To(Not(BeNil()))
+		})
+
+		When("VerifySignatures is called", func() {
+			It("returns that the signatures are invalid", func() {
+				VerifySignatures, err := PdfiumInstance.VerifySignatures(&requests.VerifySignatures{
+					Document: doc,
+				})
+				Expect(err).To(BeNil())
+				Expect(VerifySignatures).To(Not(BeNil()))
+				Expect(VerifySignatures.Signatures).To(HaveLen(2))
+
+				for i, signature := range VerifySignatures.Signatures {
+					Expect(signature.Index).To(Equal(i))
+					Expect(signature.Status).To(Equal(responses.VerifySignaturesStatusInvalid))
+					Expect(signature.Error).To(Equal("byte range gap is not the signature contents"))
+					Expect(signature.Signer).To(BeNil())
+				}
+			})
+		})
+	})
+
+	Context("a PDF file to sign", func() {
+		var doc references.FPDF_DOCUMENT
+		var pdfData []byte
+		var signerKey *ecdsa.PrivateKey
+		var signerCertificate []byte
+
+		BeforeEach(func() {
+			var err error
+			pdfData, err = ioutil.ReadFile(TestDataPath + "/testdata/test.pdf")
+			Expect(err).To(BeNil())
+
+			newDoc, err := PdfiumInstance.FPDF_LoadMemDocument(&requests.FPDF_LoadMemDocument{
+				Data: &pdfData,
+			})
+			Expect(err).To(BeNil())
+
+			doc = newDoc.Document
+
+			signerKey, err = ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+			Expect(err).To(BeNil())
+
+			template := &x509.Certificate{
+				SerialNumber: big.NewInt(1),
+				Subject:      pkix.Name{CommonName: "go-pdfium Test Signer", Organization: []string{"go-pdfium"}},
+				NotBefore:    time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+				NotAfter:     time.Date(2124, 1, 1, 0, 0, 0, 0, time.UTC),
+				KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+				IsCA:         true,
+
+				BasicConstraintsValid: true,
+			}
+
+			certificateDER, err := x509.CreateCertificate(rand.Reader, template, template, signerKey.Public(), signerKey)
+			Expect(err).To(BeNil())
+			signerCertificate = pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: certificateDER})
+		})
+
+		AfterEach(func() {
+			FPDF_CloseDocument, err := PdfiumInstance.FPDF_CloseDocument(&requests.FPDF_CloseDocument{
+				Document: doc,
+			})
+			Expect(err).To(BeNil())
+			Expect(FPDF_CloseDocument).To(Not(BeNil()))
+		})
+
+		// verifySignedFile opens the signed file and verifies its signatures
+		// with the certificate of the signer as trusted certificate.
+		verifySignedFile := func(signedFile []byte) *responses.VerifySignatures {
+			signedDoc, err := PdfiumInstance.FPDF_LoadMemDocument(&requests.FPDF_LoadMemDocument{
+				Data: &signedFile,
+			})
+			Expect(err).To(BeNil())
+			defer PdfiumInstance.FPDF_CloseDocument(&requests.FPDF_CloseDocument{
+				Document: signedDoc.Document,
+			})
+
+			VerifySignatures, err := PdfiumInstance.VerifySignatures(&requests.VerifySignatures{
+				Document:            signedDoc.Document,
+				TrustedCertificates: [][]byte{signerCertificate},
+			})
+			Expect(err).To(BeNil())
+			Expect(VerifySignatures).To(Not(BeNil()))
+
+			return VerifySignatures
+		}
+
+		// privateKey returns the key of the signer as PKCS#8 PEM.
+		privateKey := func() []byte {
+			keyDER, err := x509.MarshalPKCS8PrivateKey(signerKey)
+			Expect(err).To(BeNil())
+			return pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyDER})
+		}
+
+		When("SignDocument is called", func() {
+			It("returns an error when no signer is given", func() {
+				SignDocument, err := PdfiumInstance.SignDocument(&requests.SignDocument{
+					Document:    doc,
+					Certificate: signerCertificate,
+				})
+				Expect(err).To(MatchError("no signer or private key given"))
+				Expect(SignDocument).To(BeNil())
+			})
+
+			It("returns an error when no certificate is given", func() {
+				SignDocument, err := PdfiumInstance.SignDocument(&requests.SignDocument{
+					Document:   doc,
+					PrivateKey: privateKey(),
+				})
+				Expect(err).To(MatchError("no certificate given"))
+				Expect(SignDocument).To(BeNil())
+			})
+
+			It("returns an error when the certificate doesn't belong to the private key", func() {
+				otherKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+				Expect(err).To(BeNil())
+				otherKeyDER, err := x509.MarshalECPrivateKey(otherKey)
+				Expect(err).To(BeNil())
+
+				SignDocument, err := PdfiumInstance.SignDocument(&requests.SignDocument{
+					Document:    doc,
+					PrivateKey:  pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: otherKeyDER}),
+					Certificate: signerCertificate,
+				})
+				Expect(err).To(MatchError("the certificate doesn't belong to the private key"))
+				Expect(SignDocument).To(BeNil())
+			})
+
+			It("returns an error when the page is out of range", func() {
+				SignDocument, err := PdfiumInstance.SignDocument(&requests.SignDocument{
+					Document:    doc,
+					PrivateKey:  privateKey(),
+					Certificate: signerCertificate,
+					Appearance: &requests.SignDocumentAppearance{
+						Page: 1,
+						Rect: structs.FPDF_FS_RECTF{Left: 50, Top: 150, Right: 250, Bottom: 100},
+					},
+				})
+				Expect(err).To(MatchError("page 1 is out of range, document has 1 pages"))
+				Expect(SignDocument).To(BeNil())
+			})
+
+			It("returns an error when a crypto.Signer is used on multi-threaded usage", func() {
+				if TestType != "multi" {
+					Skip("A crypto.Signer is only unsupported on multi-threaded usage")
+				}
+
+				SignDocument, err := PdfiumInstance.SignDocument(&requests.SignDocument{
+					Document:    doc,
+					Signer:      signerKey,
+					Certificate: signerCertificate,
+				})
+				Expect(err).To(MatchError("using a crypto.Signer is not supported on multi-threaded usage, use PrivateKey"))
+				Expect(SignDocument).To(BeNil())
+			})
+
+			It("signs the document with an invisible signature", func() {
+				if TestType == "multi" {
+					Skip("A crypto.Signer is not supported on multi-threaded usage")
+				}
+
+				SignDocument, err := PdfiumInstance.SignDocument(&requests.SignDocument{
+					Document:    doc,
+					Signer:      signerKey,
+					Certificate: signerCertificate,
+					Reason:      "Approved",
+					SigningTime: time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC),
+				})
+				Expect(err).To(BeNil())
+				Expect(SignDocument).To(Not(BeNil()))
+				Expect(SignDocument.FieldName).To(Equal("Signature1"))
+				Expect(SignDocument.FilePath).To(BeNil())
+				Expect(SignDocument.FileBytes).To(Not(BeNil()))
+
+				// The signature is added with an incremental update.
+				signedFile := *SignDocument.FileBytes
+				Expect(signedFile[:len(pdfData)]).To(Equal(pdfData))
+
+				VerifySignatures := verifySignedFile(signedFile)
+				Expect(VerifySignatures.Signatures).To(HaveLen(1))
+
+				subFilter := "adbe.pkcs7.detached"
+				reason := "Approved"
+				signingTime := "D:20240601120000+00'00'"
+				signature := VerifySignatures.Signatures[0]
+				Expect(signature.Status).To(Equal(responses.VerifySignaturesStatusValid))
+				Expect(signature.Error).To(Equal(""))
+				Expect(signature.SubFilter).To(Equal(&subFilter))
+				Expect(signature.Reason).To(Equal(&reason))
+				Expect(signature.Time).To(Equal(&signingTime))
+				Expect(signature.ByteRange).To(Equal(SignDocument.ByteRange))
+				Expect(signature.ModifiedAfterSigning).To(BeFalse())
+				Expect(signature.DigestAlgorithm).To(Equal("SHA-256"))
+				Expect(signature.Signer).To(Not(BeNil()))
+				Expect(signature.Signer.Subject).To(Equal("CN=go-pdfium Test Signer,O=go-pdfium"))
+			})
+
+			It("signs the document with a visible signature with a text and an image", func() {
+				imageData, err := ioutil.ReadFile(TestDataPath + "/testdata/mona_lisa.jpg")
+				Expect(err).To(BeNil())
+
+				SignDocument, err := PdfiumInstance.SignDocument(&requests.SignDocument{
+					Document:    doc,
+					PrivateKey:  privateKey(),
+					Certificate: signerCertificate,
+					FieldName:   "Approval",
+					Reason:      "Approved",
+					Location:    "Amsterdam",
+					Appearance: &requests.SignDocumentAppearance{
+						Page:  0,
+						Rect:  structs.FPDF_FS_RECTF{Left: 50, Top: 150, Right: 300, Bottom: 90},
+						Image: imageData,
+					},
+				})
+				Expect(err).To(BeNil())
+				Expect(SignDocument).To(Not(BeNil()))
+				Expect(SignDocument.FieldName).To(Equal("Approval"))
+				Expect(SignDocument.FileBytes).To(Not(BeNil()))
+
+				VerifySignatures := verifySignedFile(*SignDocument.FileBytes)
+				Expect(VerifySignatures.Signatures).To(HaveLen(1))
+				Expect(VerifySignatures.Signatures[0].Status).To(Equal(responses.VerifySignaturesStatusValid))
+
+				signedFile := *SignDocument.FileBytes
+				signedDoc, err := PdfiumInstance.FPDF_LoadMemDocument(&requests.FPDF_LoadMemDocument{
+					Data: &signedFile,
+				})
+				Expect(err).To(BeNil())
+				defer PdfiumInstance.FPDF_CloseDocument(&requests.FPDF_CloseDocument{
+					Document: signedDoc.Document,
+				})
+
+				// The widget of the signature is on the page.
+				FPDFPage_GetAnnotCount, err := PdfiumInstance.FPDFPage_GetAnnotCount(&requests.FPDFPage_GetAnnotCount{
+					Page: requests.Page{
+						ByIndex: &requests.PageByIndex{
+							Document: signedDoc.Document,
+							Index:    0,
+						},
+					},
+				})
+				Expect(err).To(BeNil())
+				Expect(FPDFPage_GetAnnotCount.Count).To(Equal(1))
+
+				FPDFPage_GetAnnot, err := PdfiumInstance.FPDFPage_GetAnnot(&requests.FPDFPage_GetAnnot{
+					Page: requests.Page{
+						ByIndex: &requests.PageByIndex{
+							Document: signedDoc.Document,
+							Index:    0,
+						},
+					},
+					Index: 0,
+				})
+				Expect(err).To(BeNil())
+
+				FPDFAnnot_GetRect, err := PdfiumInstance.FPDFAnnot_GetRect(&requests.FPDFAnnot_GetRect{
+					Annotation: FPDFPage_GetAnnot.Annotation,
+				})
+				Expect(err).To(BeNil())
+				Expect(FPDFAnnot_GetRect.Rect).To(Equal(structs.FPDF_FS_RECTF{Left: 50, Top: 150, Right: 300, Bottom: 90}))
+
+				FPDFAnnot_GetAP, err := PdfiumInstance.FPDFAnnot_GetAP(&requests.FPDFAnnot_GetAP{
+					Annotation:     FPDFPage_GetAnnot.Annotation,
+					AppearanceMode: enums.FPDF_ANNOT_APPEARANCEMODE_NORMAL,
+				})
+				Expect(err).To(BeNil())
+				Expect(FPDFAnnot_GetAP.Value).To(ContainSubstring("/Im1 Do"))
+
+				_, err = PdfiumInstance.FPDFPage_CloseAnnot(&requests.FPDFPage_CloseAnnot{
+					Annotation: FPDFPage_GetAnnot.Annotation,
+				})
+				Expect(err).To(BeNil())
+			})
+
+			It("keeps the first signature valid when a signed document is signed again", func() {
+				SignDocument, err := PdfiumInstance.SignDocument(&requests.SignDocument{
+					Document:    doc,
+					PrivateKey:  privateKey(),
+					Certificate: signerCertificate,
+				})
+				Expect(err).To(BeNil())
+				Expect(SignDocument).To(Not(BeNil()))
+
+				signedFile := *SignDocument.FileBytes
+				signedDoc, err := PdfiumInstance.FPDF_LoadMemDocument(&requests.FPDF_LoadMemDocument{
+					Data: &signedFile,
+				})
+				Expect(err).To(BeNil())
+				defer PdfiumInstance.FPDF_CloseDocument(&requests.FPDF_CloseDocument{
+					Document: signedDoc.Document,
+				})
+
+				SignDocument, err = PdfiumInstance.SignDocument(&requests.SignDocument{
+					Document:    signedDoc.Document,
+					PrivateKey:  privateKey(),
+					Certificate: signerCertificate,
+					Reason:      "Countersigned",
+				})
+				Expect(err).To(BeNil())
+				Expect(SignDocument).To(Not(BeNil()))
+				Expect(SignDocument.FieldName).To(Equal("Signature2"))
+
+				VerifySignatures := verifySignedFile(*SignDocument.FileBytes)
+				Expect(VerifySignatures.Signatures).To(HaveLen(2))
+				Expect(VerifySignatures.Signatures[0].Status).To(Equal(responses.VerifySignaturesStatusValid))
+				Expect(VerifySignatures.Signatures[0].ModifiedAfterSigning).To(BeTrue())
+				Expect(VerifySignatures.Signatures[1].Status).To(Equal(responses.VerifySignaturesStatusValid))
+				Expect(VerifySignatures.Signatures[1].ModifiedAfterSigning).To(BeFalse())
+			})
+
+			It("keeps the first signature valid when a signed document is signed again after a partial flatten", func() {
+				SignDocument, err := PdfiumInstance.SignDocument(&requests.SignDocument{
+					Document:    doc,
+					PrivateKey:  privateKey(),
+					Certificate: signerCertificate,
+				})
+				Expect(err).To(BeNil())
+				Expect(SignDocument).To(Not(BeNil()))
+
+				signedFile := *SignDocument.FileBytes
+				signedDoc, err := PdfiumInstance.FPDF_LoadMemDocument(&requests.FPDF_LoadMemDocument{
+					Data: &signedFile,
+				})
+				Expect(err).To(BeNil())
+				defer PdfiumInstance.FPDF_CloseDocument(&requests.FPDF_CloseDocument{
+					Document: signedDoc.Document,
+				})
+
+				signedPage := requests.Page{
+					ByIndex: &requests.PageByIndex{
+						Document: signedDoc.Document,
+						Index:    0,
+					},
+				}
+
+				FPDFPage_CreateAnnot, err := PdfiumInstance.FPDFPage_CreateAnnot(&requests.FPDFPage_CreateAnnot{
+					Page:    signedPage,
+					Subtype: enums.FPDF_ANNOT_SUBTYPE_SQUARE,
+				})
+				Expect(err).To(BeNil())
+
+				_, err = PdfiumInstance.FPDFAnnot_SetRect(&requests.FPDFAnnot_SetRect{
+					Annotation: FPDFPage_CreateAnnot.Annotation,
+					Rect:       structs.FPDF_FS_RECTF{Left: 10, Top: 100, Right: 100, Bottom: 10},
+				})
+				Expect(err).To(BeNil())
+
+				_, err = PdfiumInstance.GenerateAnnotationAppearance(&requests.GenerateAnnotationAppearance{
+					Document:   signedDoc.Document,
+					Annotation: FPDFPage_CreateAnnot.Annotation,
+				})
+				Expect(err).To(BeNil())
+
+				_, err = PdfiumInstance.FPDFPage_CloseAnnot(&requests.FPDFPage_CloseAnnot{
+					Annotation: FPDFPage_CreateAnnot.Annotation,
+				})
+				Expect(err).To(BeNil())
+
+				// The signature field is kept, so the annotation is flattened
+				// on a copy of the page, which leaves unused objects behind.
+				FlattenDocument, err := PdfiumInstance.FlattenDocument(&requests.FlattenDocument{
+					Document: signedDoc.Document,
+					Mode:     requests.FlattenDocumentModeAnnotations,
+				})
+				Expect(err).To(BeNil())
+				Expect(FlattenDocument).To(Equal(&responses.FlattenDocument{
+					Pages: []responses.FlattenDocumentPage{
+						{Page: 0, Result: responses.FPDFPage_FlattenResultSuccess, Kept: 1},
+					},
+				}))
+
+				SignDocument, err = PdfiumInstance.SignDocument(&requests.SignDocument{
+					Document:    signedDoc.Document,
+					PrivateKey:  privateKey(),
+					Certificate: signerCertificate,
+					Reason:      "Countersigned",
+				})
+				Expect(err).To(BeNil())
+				Expect(SignDocument).To(Not(BeNil()))
+				Expect(*SignDocument.FileBytes).To(HavePrefix(string(signedFile)))
+
+				VerifySignatures := verifySignedFile(*SignDocument.FileBytes)
+				Expect(VerifySignatures.Signatures).To(HaveLen(2))
+				Expect(VerifySignatures.Signatures[0].Status).To(Equal(responses.VerifySignaturesStatusValid))
+				Expect(VerifySignatures.Signatures[0].ModifiedAfterSigning).To(BeTrue())
+				Expect(VerifySignatures.Signatures[1].Status).To(Equal(responses.VerifySignaturesStatusValid))
+			})
+
+			It("saves the signed document to the given path", func() {
+				tempFile, err := ioutil.TempFile("", "")
+				Expect(err).To(BeNil())
+				tempFile.Close()
+				defer os.Remove(tempFile.Name())
+
+				filePath := tempFile.Name()
+				SignDocument, err := PdfiumInstance.SignDocument(&requests.SignDocument{
+					Document:    doc,
+					PrivateKey:  privateKey(),
+					Certificate: signerCertificate,
+					FilePath:    &filePath,
+				})
+				Expect(err).To(BeNil())
+				Expect(SignDocument).To(Not(BeNil()))
+				Expect(SignDocument.FilePath).To(Equal(&filePath))
+				Expect(SignDocument.FileBytes).To(BeNil())
+
+				signedFile, err := ioutil.ReadFile(filePath)
+				Expect(err).To(BeNil())
+
+				VerifySignatures := verifySignedFile(signedFile)
+				Expect(VerifySignatures.Signatures).To(HaveLen(1))
+				Expect(VerifySignatures.Signatures[0].Status).To(Equal(responses.VerifySignaturesStatusValid))
+			})
+		})
+	})
+})
@@ -0,0 +1,255 @@
+//go:build pdfium_experimental
+// +build pdfium_experimental
+
+package shared_tests
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"io/ioutil"
+
+	"github.com/klippa-app/go-pdfium/enums"
+	"github.com/klippa-app/go-pdfium/references"
+	"github.com/klippa-app/go-pdfium/requests"
+	"github.com/klippa-app/go-pdfium/responses"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("struct_tree_experimental", func() {
+	BeforeEach(func() {
+		Locker.Lock()
+	})
+
+	AfterEach(func() {
+		Locker.Unlock()
+	})
+
+	stringValue := func(value string) *string {
+		return &value
+	}
+
+	numberValue := func(value float32) *float32 {
+		return &value
+	}
+
+	Context("a tagged PDF file with alt text", func() {
+		var doc references.FPDF_DOCUMENT
+
+		BeforeEach(func() {
+			pdfData, err := ioutil.ReadFile(TestDataPath + "/testdata/tagged_alt_text.pdf")
+			Expect(err).To(BeNil())
+
+			newDoc, err := PdfiumInstance.FPDF_LoadMemDocument(&requests.FPDF_LoadMemDocument{
+				Data: &pdfData,
+			})
+			Expect(err).To(BeNil())
+
+			doc = newDoc.Document
+		})
+
+		AfterEach(func() {
+			FPDF_CloseDocument, err := PdfiumInstance.FPDF_CloseDocument(&requests.FPDF_CloseDocument{
+				Document: doc,
+			})
+			Expect(err).To(BeNil())
+			Expect(FPDF_CloseDocument).To(Not(BeNil()))
+		})
+
+		expectedStructTree := &responses.GetStructTree{
+			Pages: []responses.StructTreePage{
+				{
+					Page: 0,
+					Elements: []responses.StructElement{
+						{
+							Type:             "Document",
+							ObjType:          "StructElem",
+							Title:            stringValue("TitleText"),
+							Attributes:       []responses.StructElementAttribute{},
+							MarkedContentIDs: []int{},
+							Children: []responses.StructElement{
+								{
+									Type:    "P",
+									ObjType: "StructElem",
+									Title:   stringValue("symbol: 100k"),
+									Attributes: []responses.StructElementAttribute{
+										{Owner: "Layout", Name: "Placement", Type: enums.FPDF_OBJECT_TYPE_NAME, StringValue: stringValue("Block")},
+									},
+									MarkedContentIDs: []int{},
+									Children: []responses.StructElement{
+										{
+											Type:    "Figure",
+											ObjType: "StructElem",
+											AltText: stringValue("Black Image"),
+											Attributes: []responses.StructElementAttribute{
+												{Owner: "Layout", Name: "BBox", Type: enums.FPDF_OBJECT_TYPE_ARRAY},
+												{Owner: "Layout", Name: "Height", Type: enums.FPDF_OBJECT_TYPE_NUMBER, NumberValue: numberValue(99.9)},
+												{Owner: "Layout", Name: "Placement", Type: enums.FPDF_OBJECT_TYPE_NAME, StringValue: stringValue("Block")},
+												{Owner: "Layout", Name: "Width", Type: enums.FPDF_OBJECT_TYPE_NUMBER, NumberValue: numberValue(99.9)},
+											},
+											MarkedContentIDs: []int{0},
+											Children:         []responses.StructElement{},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		}
+
+		When("GetStructTree is called", func() {
+			It("returns the structure tree of the document", func() {
+				GetStructTree, err := PdfiumInstance.GetStructTree(&requests.GetStructTree{
+					Document: doc,
+				})
+				Expect(err).To(BeNil())
+				Expect(GetStructTree).To(Equal(expectedStructTree))
+			})
+
+			It("returns the structure tree of the given pages", func() {
+				pageRange := "1"
+				GetStructTree, err := PdfiumInstance.GetStructTree(&requests.GetStructTree{
+					Document:  doc,
+					PageRange: &pageRange,
+				})
+				Expect(err).To(BeNil())
+				Expect(GetStructTree).To(Equal(expectedStructTree))
+			})
+
+			It("returns an error when the page range is invalid", func() {
+				pageRange := "2"
+				GetStructTree, err := PdfiumInstance.GetStructTree(&requests.GetStructTree{
+					Document:  doc,
+					PageRange: &pageRange,
+				})
+				Expect(err).To(MatchError("page 2 is out of range, document has 1 pages"))
+				Expect(GetStructTree).To(BeNil())
+			})
+
+			It("returns a structure tree that can be serialized to JSON and XML", func() {
+				GetStructTree, err := PdfiumInstance.GetStructTree(&requests.GetStructTree{
+					Document: doc,
+				})
+				Expect(err).To(BeNil())
+
+				jsonData, err := json.Marshal(GetStructTree)
+				Expect(err).To(BeNil())
+
+				fromJSON := &responses.GetStructTree{}
+				Expect(json.Unmarshal(jsonData, fromJSON)).To(BeNil())
+				Expect(fromJSON).To(Equal(expectedStructTree))
+
+				xmlData, err := xml.Marshal(GetStructTree)
+				Expect(err).To(BeNil())
+				Expect(string(xmlData)).To(ContainSubstring("<Type>Figure</Type><ObjType>StructElem</ObjType><AltText>Black Image</AltText>"))
+
+				fromXML := &responses.GetStructTree{}
+				Expect(xml.Unmarshal(xmlData, fromXML)).To(BeNil())
+				Expect(fromXML.Pages).To(HaveLen(1))
+				Expect(fromXML.Pages[0].Elements[0].Children[0].Children[0].AltText).To(Equal(stringValue("Black Image")))
+				Expect(fromXML.Pages[0].Elements[0].Children[0].Children[0].MarkedContentIDs).To(Equal([]int{0}))
+			})
+		})
+	})
+
+	Context("a tagged PDF file with a table", func() {
+		var doc references.FPDF_DOCUMENT
+
+		BeforeEach(func() {
+			pdfData, err := ioutil.ReadFile(TestDataPath + "/testdata/tagged_table.pdf")
+			Expect(err).To(BeNil())
+
+			newDoc, err := PdfiumInstance.FPDF_LoadMemDocument(&requests.FPDF_LoadMemDocument{
+				Data: &pdfData,
+			})
+			Expect(err).To(BeNil())
+
+			doc = newDoc.Document
+		})
+
+		AfterEach(func() {
+			FPDF_CloseDocument, err := PdfiumInstance.FPDF_CloseDocument(&requests.FPDF_CloseDocument{
+				Document: doc,
+			})
+			Expect(err).To(BeNil())
+			Expect(FPDF_CloseDocument).To(Not(BeNil()))
+		})
+
+		When("GetStructTree is called", func() {
+			It("returns the language, IDs and attributes of the elements", func() {
+				GetStructTree, err := PdfiumInstance.GetStructTree(&requests.GetStructTree{
+					Document: doc,
+				})
+				Expect(err).To(BeNil())
+				Expect(GetStructTree.Pages).To(HaveLen(1))
+				Expect(GetStructTree.Pages[0].Elements).To(HaveLen(1))
+
+				document := GetStructTree.Pages[0].Elements[0]
+				Expect(document.Type).To(Equal("Document"))
+				Expect(document.Lang).To(Equal(stringValue("en-US")))
+				Expect(document.Children).To(HaveLen(1))
+
+				table := document.Children[0]
+				Expect(table.Type).To(Equal("Table"))
+				Expect(table.Lang).To(Equal(stringValue("hu")))
+				Expect(table.ID).To(Equal(stringValue("node12")))
+				Expect(table.Attributes).To(Equal([]responses.StructElementAttribute{
+					{Owner: "Table", Name: "Summary", Type: enums.FPDF_OBJECT_TYPE_STRING, StringValue: stringValue("")},
+				}))
+
+				Expect(table.Children[0].Type).To(Equal("TR"))
+				header := table.Children[0].Children[0]
+				Expect(header.Type).To(Equal("TH"))
+				Expect(header.ID).To(Equal(stringValue("node15")))
+				Expect(header.Attributes).To(Equal([]responses.StructElementAttribute{
+					{Owner: "Table", Name: "Scope", Type: enums.FPDF_OBJECT_TYPE_NAME, StringValue: stringValue("Row")},
+					{Owner: "Table", Name: "ColSpan", Type: enums.FPDF_OBJECT_TYPE_NUMBER, NumberValue: numberValue(2)},
+				}))
+			})
+		})
+	})
+
+	Context("a tagged PDF file with content on two pages", func() {
+		var doc references.FPDF_DOCUMENT
+
+		BeforeEach(func() {
+			pdfData, err := ioutil.ReadFile(TestDataPath + "/testdata/tagged_mcr_multipage.pdf")
+			Expect(err).To(BeNil())
+
+			newDoc, err := PdfiumInstance.FPDF_LoadMemDocument(&requests.FPDF_LoadMemDocument{
+				Data: &pdfData,
+			})
+			Expect(err).To(BeNil())
+
+			doc = newDoc.Document
+		})
+
+		AfterEach(func() {
+			FPDF_CloseDocument, err := PdfiumInstance.FPDF_CloseDocument(&requests.FPDF_CloseDocument{
+				Document: doc,
+			})
+			Expect(err).To(BeNil())
+			Expect(FPDF_CloseDocument).To(Not(BeNil()))
+		})
+
+		When("GetStructTree is called", func() {
+			It("returns the element on every page with the marked content of that page", func() {
+				GetStructTree, err := PdfiumInstance.GetStructTree(&requests.GetStructTree{
+					Document: doc,
+				})
+				Expect(err).To(BeNil())
+				Expect(GetStructTree.Pages).To(HaveLen(2))
+
+				for i, page := range GetStructTree.Pages {
+					Expect(page.Page).To(Equal(i))
+					Expect(page.Elements).To(HaveLen(1))
+					Expect(page.Elements[0].Type).To(Equal("Document"))
+					Expect(page.Elements[0].MarkedContentIDs).To(Equal([]int{0}))
+				}
+			})
+		})
+	})
+})
@@ -0,0 +1,146 @@
+package shared_tests
+
+import (
+	"bytes"
+	"image/png"
+	"io/ioutil"
+	"os"
+
+	"github.com/klippa-app/go-pdfium/references"
+	"github.com/klippa-app/go-pdfium/requests"
+	"github.com/klippa-app/go-pdfium/responses"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("thumbnail", func() {
+	BeforeEach(func() {
+		Locker.Lock()
+	})
+
+	AfterEach(func() {
+		Locker.Unlock()
+	})
+
+	Context("no document", func() {
+		When("is opened", func() {
+			It("returns an error when calling GetPageThumbnail", func() {
+				GetPageThumbnail, err := PdfiumInstance.GetPageThumbnail(&requests.GetPageThumbnail{
+					Page: requests.Page{
+						ByIndex: &requests.PageByIndex{
+							Index: 0,
+						},
+					},
+					MaxWidth: 100,
+				})
+				Expect(err).To(MatchError("document not given"))
+				Expect(GetPageThumbnail).To(BeNil())
+			})
+		})
+	})
+
+	Context("a normal PDF file without thumbnails", func() {
+		var doc references.FPDF_DOCUMENT
+
+		BeforeEach(func() {
+			pdfData, err := ioutil.ReadFile(TestDataPath + "/testdata/test.pdf")
+			Expect(err).To(BeNil())
+
+			newDoc, err := PdfiumInstance.FPDF_LoadMemDocument(&requests.FPDF_LoadMemDocument{
+				Data: &pdfData,
+			})
+			Expect(err).To(BeNil())
+
+			doc = newDoc.Document
+		})
+
+		AfterEach(func() {
+			FPDF_CloseDocument, err := PdfiumInstance.FPDF_CloseDocument(&requests.FPDF_CloseDocument{
+				Document: doc,
+			})
+			Expect(err).To(BeNil())
+			Expect(FPDF_CloseDocument).To(Not(BeNil()))
+		})
+
+		When("GetPageThumbnail is called", func() {
+			It("returns an error when no maximum size is given", func() {
+				GetPageThumbnail, err := PdfiumInstance.GetPageThumbnail(&requests.GetPageThumbnail{
+					Page: requests.Page{
+						ByIndex: &requests.PageByIndex{
+							Document: doc,
+							Index:    0,
+						},
+					},
+					OutputFormat: requests.RenderToFileOutputFormatPNG,
+					OutputTarget: requests.RenderToFileOutputTargetBytes,
+				})
+				Expect(err).To(MatchError("no maximum width or height given"))
+				Expect(GetPageThumbnail).To(BeNil())
+			})
+
+			It("returns an error when an invalid output format is given", func() {
+				GetPageThumbnail, err := PdfiumInstance.GetPageThumbnail(&requests.GetPageThumbnail{
+					Page: requests.Page{
+						ByIndex: &requests.PageByIndex{
+							Document: doc,
+							Index:    0,
+						},
+					},
+					MaxWidth:     100,
+					OutputTarget: requests.RenderToFileOutputTargetBytes,
+				})
+				Expect(err).To(MatchError("invalid output format given"))
+				Expect(GetPageThumbnail).To(BeNil())
+			})
+
+			It("renders the page in the maximum size", func() {
+				GetPageThumbnail, err := PdfiumInstance.GetPageThumbnail(&requests.GetPageThumbnail{
+					Page: requests.Page{
+						ByIndex: &requests.PageByIndex{
+							Document: doc,
+							Index:    0,
+						},
+					},
+					MaxWidth:     100,
+					MaxHeight:    100,
+					OutputFormat: requests.RenderToFileOutputFormatPNG,
+					OutputTarget: requests.RenderToFileOutputTargetBytes,
+				})
+				Expect(err).To(BeNil())
+				Expect(GetPageThumbnail).To(Not(BeNil()))
+				Expect(GetPageThumbnail.IsEmbedded).To(BeFalse())
+				Expect(GetPageThumbnail.Width).To(Equal(71))
+				Expect(GetPageThumbnail.Height).To(Equal(100))
+				Expect(GetPageThumbnail.ImageBytes).To(Not(BeNil()))
+
+				thumbnail, err := png.Decode(bytes.NewReader(*GetPageThumbnail.ImageBytes))
+				Expect(err).To(BeNil())
+				Expect(thumbnail.Bounds().Dx()).To(Equal(71))
+				Expect(thumbnail.Bounds().Dy()).To(Equal(100))
+			})
+
+			It("renders the page into a file", func() {
+				GetPageThumbnail, err := PdfiumInstance.GetPageThumbnail(&requests.GetPageThumbnail{
+					Page: requests.Page{
+						ByIndex: &requests.PageByIndex{
+							Document: doc,
+							Index:    0,
+						},
+					},
+					MaxHeight:    50,
+					OutputFormat: requests.RenderToFileOutputFormatJPG,
+					OutputTarget: requests.RenderToFileOutputTargetFile,
+				})
+				Expect(err).To(BeNil())
+				Expect(GetPageThumbnail).To(Equal(&responses.GetPageThumbnail{
+					ImagePath: GetPageThumbnail.ImagePath,
+					Width:     36,
+					Height:    50,
+				}))
+				Expect(GetPageThumbnail.ImagePath).To(BeAnExistingFile())
+				Expect(os.Remove(GetPageThumbnail.ImagePath)).To(BeNil())
+			})
+		})
+	})
+})
@@ -0,0 +1,89 @@
+//go:build pdfium_experimental
+// +build pdfium_experimental
+
+package shared_tests
+
+import (
+	"io/ioutil"
+
+	"github.com/klippa-app/go-pdfium/references"
+	"github.com/klippa-app/go-pdfium/requests"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("thumbnail_experimental", func() {
+	BeforeEach(func() {
+		Locker.Lock()
+	})
+
+	AfterEach(func() {
+		Locker.Unlock()
+	})
+
+	Context("a PDF file with thumbnails", func() {
+		var doc references.FPDF_DOCUMENT
+
+		BeforeEach(func() {
+			pdfData, err := ioutil.ReadFile(TestDataPath + "/testdata/simple_thumbnail.pdf")
+			Expect(err).To(BeNil())
+
+			newDoc, err := PdfiumInstance.FPDF_LoadMemDocument(&requests.FPDF_LoadMemDocument{
+				Data: &pdfData,
+			})
+			Expect(err).To(BeNil())
+
+			doc = newDoc.Document
+		})
+
+		AfterEach(func() {
+			FPDF_CloseDocument, err := PdfiumInstance.FPDF_CloseDocument(&requests.FPDF_CloseDocument{
+				Document: doc,
+			})
+			Expect(err).To(BeNil())
+			Expect(FPDF_CloseDocument).To(Not(BeNil()))
+		})
+
+		When("GetPageThumbnail is called", func() {
+			It("returns the embedded thumbnail when it is large enough", func() {
+				GetPageThumbnail, err := PdfiumInstance.GetPageThumbnail(&requests.GetPageThumbnail{
+					Page: requests.Page{
+						ByIndex: &requests.PageByIndex{
+							Document: doc,
+							Index:    0,
+						},
+					},
+					MaxWidth:     50,
+					OutputFormat: requests.RenderToFileOutputFormatPNG,
+					OutputTarget: requests.RenderToFileOutputTargetBytes,
+				})
+				Expect(err).To(BeNil())
+				Expect(GetPageThumbnail).To(Not(BeNil()))
+				Expect(GetPageThumbnail.IsEmbedded).To(BeTrue())
+				Expect(GetPageThumbnail.Width).To(Equal(50))
+				Expect(GetPageThumbnail.Height).To(Equal(50))
+				Expect(GetPageThumbnail.ImageBytes).To(Not(BeNil()))
+			})
+
+			It("renders the page when the embedded thumbnail is too small", func() {
+				GetPageThumbnail, err := PdfiumInstance.GetPageThumbnail(&requests.GetPageThumbnail{
+					Page: requests.Page{
+						ByIndex: &requests.PageByIndex{
+							Document: doc,
+							Index:    0,
+						},
+					},
+					MaxWidth:     1000,
+					OutputFormat: requests.RenderToFileOutputFormatPNG,
+					OutputTarget: requests.RenderToFileOutputTargetBytes,
+				})
+				Expect(err).To(BeNil())
+				Expect(GetPageThumbnail).To(Not(BeNil()))
+				Expect(GetPageThumbnail.IsEmbedded).To(BeFalse())
+				Expect(GetPageThumbnail.Width).To(Equal(1000))
+				Expect(GetPageThumbnail.Height).To(Equal(1000))
+			})
+		})
+	})
+})
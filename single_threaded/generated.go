@@ -11,6 +11,118 @@ import (
 	"github.com/klippa-app/go-pdfium/responses"
 )
 
+func (i *pdfiumInstance) AddHeaderFooter(request *requests.AddHeaderFooter) (resp *responses.AddHeaderFooter, err error) {
+	if i.closed {
+		return nil, errors.New("instance is closed")
+	}
+
+	defer func() {
+		if panicError := recover(); panicError != nil {
+			err = fmt.Errorf("panic occurred in %s: %v", "AddHeaderFooter", panicError)
+		}
+	}()
+
+	return i.pdfium.AddHeaderFooter(request)
+}
+
+func (i *pdfiumInstance) CheckAccessibility(request *requests.CheckAccessibility) (resp *responses.CheckAccessibility, err error) {
+	if i.closed {
+		return nil, errors.New("instance is closed")
+	}
+
+	defer func() {
+		if panicError := recover(); panicError != nil {
+			err = fmt.Errorf("panic occurred in %s: %v", "CheckAccessibility", panicError)
+		}
+	}()
+
+	return i.pdfium.CheckAccessibility(request)
+}
+
+func (i *pdfiumInstance) CompareDocuments(request *requests.CompareDocuments) (resp *responses.CompareDocuments, err error) {
+	if i.closed {
+		return nil, errors.New("instance is closed")
+	}
+
+	defer func() {
+		if panicError := recover(); panicError != nil {
+			err = fmt.Errorf("panic occurred in %s: %v", "CompareDocuments", panicError)
+		}
+	}()
+
+	return i.pdfium.CompareDocuments(request)
+}
+
+func (i *pdfiumInstance) CompareDocumentsText(request *requests.CompareDocumentsText) (resp *responses.CompareDocumentsText, err error) {
+	if i.closed {
+		return nil, errors.New("instance is closed")
+	}
+
+	defer func() {
+		if panicError := recover(); panicError != nil {
+			err = fmt.Errorf("panic occurred in %s: %v", "CompareDocumentsText", panicError)
+		}
+	}()
+
+	return i.pdfium.CompareDocumentsText(request)
+}
+
+func (i *pdfiumInstance) CreateTextMarkupAnnotations(request *requests.CreateTextMarkupAnnotations) (resp *responses.CreateTextMarkupAnnotations, err error) {
+	if i.closed {
+		return nil, errors.New("instance is closed")
+	}
+
+	defer func() {
+		if panicError := recover(); panicError != nil {
+			err = fmt.Errorf("panic occurred in %s: %v", "CreateTextMarkupAnnotations", panicError)
+		}
+	}()
+
+	return i.pdfium.CreateTextMarkupAnnotations(request)
+}
+
+func (i *pdfiumInstance) CropPages(request *requests.CropPages) (resp *responses.CropPages, err error) {
+	if i.closed {
+		return nil, errors.New("instance is closed")
+	}
+
+	defer func() {
+		if panicError := recover(); panicError != nil {
+			err = fmt.Errorf("panic occurred in %s: %v", "CropPages", panicError)
+		}
+	}()
+
+	return i.pdfium.CropPages(request)
+}
+
+func (i *pdfiumInstance) ExportAnnotations(request *requests.ExportAnnotations) (resp *responses.ExportAnnotations, err error) {
+	if i.closed {
+		return nil, errors.New("instance is closed")
+	}
+
+	defer func() {
+		if panicError := recover(); panicError != nil {
+			err = fmt.Errorf("panic occurred in %s: %v", "ExportAnnotations", panicError)
+		}
+	}()
+
+	return i.pdfium.ExportAnnotations(request)
+}
+
+func (i *pdfiumInstance) ExportFormData(request *requests.ExportFormData) (resp *responses.ExportFormData, err error) {
+	if i.closed {
+		return nil, errors.New("instance is closed")
+	}
+
+	defer func() {
+		if panicError := recover(); panicError != nil {
+			err = fmt.Errorf("panic occurred in %s: %v", "ExportFormData", panicError)
+		}
+	}()
+
+	return i.pdfium.ExportFormData(request)
+}
+
 func (i *pdfiumInstance) FORM_CanRedo(request *requests.FORM_CanRedo) (resp *responses.FORM_CanRedo, err error) {
 	if i.closed {
 		return nil, errors.New("instance is closed")
@@ -5989,6 +6101,48 @@ func (i *pdfiumInstance) FSDK_SetUnSpObjProcessHandler(request *requests.FSDK_Se
 	return i.pdfium.FSDK_SetUnSpObjProcessHandler(request)
 }
 
+func (i *pdfiumInstance) FillFormFields(request *requests.FillFormFields) (resp *responses.FillFormFields, err error) {
+	if i.closed {
+		return nil, errors.New("instance is closed")
+	}
+
+	defer func() {
+		if panicError := recover(); panicError != nil {
+			err = fmt.Errorf("panic occurred in %s: %v", "FillFormFields", panicError)
+		}
+	}()
+
+	return i.pdfium.FillFormFields(request)
+}
+
+func (i *pdfiumInstance) FlattenDocument(request *requests.FlattenDocument) (resp *responses.FlattenDocument, err error) {
+	if i.closed {
+		return nil, errors.New("instance is closed")
+	}
+
+	defer func() {
+		if panicError := recover(); panicError != nil {
+			err = fmt.Errorf("panic occurred in %s: %v", "FlattenDocument", panicError)
+		}
+	}()
+
+	return i.pdfium.FlattenDocument(request)
+}
+
+func (i *pdfiumInstance) GenerateAnnotationAppearance(request *requests.GenerateAnnotationAppearance) (resp *responses.GenerateAnnotationAppearance, err error) {
+	if i.closed {
+		return nil, errors.New("instance is closed")
+	}
+
+	defer func() {
+		if panicError := recover(); panicError != nil {
+			err = fmt.Errorf("panic occurred in %s: %v", "GenerateAnnotationAppearance", panicError)
+		}
+	}()
+
+	return i.pdfium.GenerateAnnotationAppearance(request)
+}
+
 func (i *pdfiumInstance) GetActionInfo(request *requests.GetActionInfo) (resp *responses.GetActionInfo, err error) {
 	if i.closed {
 		return nil, errors.New("instance is closed")
@@ -6003,6 +6157,20 @@ func (i *pdfiumInstance) GetActionInfo(request *requests.GetActionInfo) (resp *r
 	return i.pdfium.GetActionInfo(request)
 }
 
+func (i *pdfiumInstance) GetAnnotations(request *requests.GetAnnotations) (resp *responses.GetAnnotations, err error) {
+	if i.closed {
+		return nil, errors.New("instance is closed")
+	}
+
+	defer func() {
+		if panicError := recover(); panicError != nil {
+			err = fmt.Errorf("panic occurred in %s: %v", "GetAnnotations", panicError)
+		}
+	}()
+
+	return i.pdfium.GetAnnotations(request)
+}
+
 func (i *pdfiumInstance) GetAttachments(request *requests.GetAttachments) (resp *responses.GetAttachments, err error) {
 	if i.closed {
 		return nil, errors.New("instance is closed")
@@ -6045,6 +6213,34 @@ func (i *pdfiumInstance) GetDestInfo(request *requests.GetDestInfo) (resp *respo
 	return i.pdfium.GetDestInfo(request)
 }
 
+func (i *pdfiumInstance) GetFonts(request *requests.GetFonts) (resp *responses.GetFonts, err error) {
+	if i.closed {
+		return nil, errors.New("instance is closed")
+	}
+
+	defer func() {
+		if panicError := recover(); panicError != nil {
+			err = fmt.Errorf("panic occurred in %s: %v", "GetFonts", panicError)
+		}
+	}()
+
+	return i.pdfium.GetFonts(request)
+}
+
+func (i *pdfiumInstance) GetFormFields(request *requests.GetFormFields) (resp *responses.GetFormFields, err error) {
+	if i.closed {
+		return nil, errors.New("instance is closed")
+	}
+
+	defer func() {
+		if panicError := recover(); panicError != nil {
+			err = fmt.Errorf("panic occurred in %s: %v", "GetFormFields", panicError)
+		}
+	}()
+
+	return i.pdfium.GetFormFields(request)
+}
+
 func (i *pdfiumInstance) GetJavaScriptActions(request *requests.GetJavaScriptActions) (resp *responses.GetJavaScriptActions, err error) {
 	if i.closed {
 		return nil, errors.New("instance is closed")
@@ -6129,6 +6325,62 @@ func (i *pdfiumInstance) GetPageTextStructured(request *requests.GetPageTextStru
 	return i.pdfium.GetPageTextStructured(request)
 }
 
+func (i *pdfiumInstance) GetPageThumbnail(request *requests.GetPageThumbnail) (resp *responses.GetPageThumbnail, err error) {
+	if i.closed {
+		return nil, errors.New("instance is closed")
+	}
+
+	defer func() {
+		if panicError := recover(); panicError != nil {
+			err = fmt.Errorf("panic occurred in %s: %v", "GetPageThumbnail", panicError)
+		}
+	}()
+
+	return i.pdfium.GetPageThumbnail(request)
+}
+
+func (i *pdfiumInstance) GetStructTree(request *requests.GetStructTree) (resp *responses.GetStructTree, err error) {
+	if i.closed {
+		return nil, errors.New("instance is closed")
+	}
+
+	defer func() {
+		if panicError := recover(); panicError != nil {
+			err = fmt.Errorf("panic occurred in %s: %v", "GetStructTree", panicError)
+		}
+	}()
+
+	return i.pdfium.GetStructTree(request)
+}
+
+func (i *pdfiumInstance) ImportAnnotations(request *requests.ImportAnnotations) (resp *responses.ImportAnnotations, err error) {
+	if i.closed {
+		return nil, errors.New("instance is closed")
+	}
+
+	defer func() {
+		if panicError := recover(); panicError != nil {
+			err = fmt.Errorf("panic occurred in %s: %v", "ImportAnnotations", panicError)
+		}
+	}()
+
+	return i.pdfium.ImportAnnotations(request)
+}
+
+func (i *pdfiumInstance) ImportFormData(request *requests.ImportFormData) (resp *responses.ImportFormData, err error) {
+	if i.closed {
+		return nil, errors.New("instance is closed")
+	}
+
+	defer func() {
+		if panicError := recover(); panicError != nil {
+			err = fmt.Errorf("panic occurred in %s: %v", "ImportFormData", panicError)
+		}
+	}()
+
+	return i.pdfium.ImportFormData(request)
+}
+
 func (i *pdfiumInstance) OpenDocument(request *requests.OpenDocument) (resp *responses.OpenDocument, err error) {
 	if i.closed {
 		return nil, errors.New("instance is closed")
@@ -6143,6 +6395,34 @@ func (i *pdfiumInstance) OpenDocument(request *requests.OpenDocument) (resp *res
 	return i.pdfium.OpenDocument(request)
 }
 
+func (i *pdfiumInstance) OptimizeDocument(request *requests.OptimizeDocument) (resp *responses.OptimizeDocument, err error) {
+	if i.closed {
+		return nil, errors.New("instance is closed")
+	}
+
+	defer func() {
+		if panicError := recover(); panicError != nil {
+			err = fmt.Errorf("panic occurred in %s: %v", "OptimizeDocument", panicError)
+		}
+	}()
+
+	return i.pdfium.OptimizeDocument(request)
+}
+
+func (i *pdfiumInstance) Preflight(request *requests.Preflight) (resp *responses.Preflight, err error) {
+	if i.closed {
+		return nil, errors.New("instance is closed")
+	}
+
+	defer func() {
+		if panicError := recover(); panicError != nil {
+			err = fmt.Errorf("panic occurred in %s: %v", "Preflight", panicError)
+		}
+	}()
+
+	return i.pdfium.Preflight(request)
+}
+
 func (i *pdfiumInstance) RenderPageInDPI(request *requests.RenderPageInDPI) (resp *responses.RenderPageInDPI, err error) {
 	if i.closed {
 		return nil, errors.New("instance is closed")
@@ -6212,3 +6492,87 @@ func (i *pdfiumInstance) RenderToFile(request *requests.RenderToFile) (resp *res
 
 	return i.pdfium.RenderToFile(request)
 }
+
+func (i *pdfiumInstance) ResizePages(request *requests.ResizePages) (resp *responses.ResizePages, err error) {
+	if i.closed {
+		return nil, errors.New("instance is closed")
+	}
+
+	defer func() {
+		if panicError := recover(); panicError != nil {
+			err = fmt.Errorf("panic occurred in %s: %v", "ResizePages", panicError)
+		}
+	}()
+
+	return i.pdfium.ResizePages(request)
+}
+
+func (i *pdfiumInstance) SetBookmarks(request *requests.SetBookmarks) (resp *responses.SetBookmarks, err error) {
+	if i.closed {
+		return nil, errors.New("instance is closed")
+	}
+
+	defer func() {
+		if panicError := recover(); panicError != nil {
+			err = fmt.Errorf("panic occurred in %s: %v", "SetBookmarks", panicError)
+		}
+	}()
+
+	return i.pdfium.SetBookmarks(request)
+}
+
+func (i *pdfiumInstance) SetMetaData(request *requests.SetMetaData) (resp *responses.SetMetaData, err error) {
+	if i.closed {
+		return nil, errors.New("instance is closed")
+	}
+
+	defer func() {
+		if panicError := recover(); panicError != nil {
+			err = fmt.Errorf("panic occurred in %s: %v", "SetMetaData", panicError)
+		}
+	}()
+
+	return i.pdfium.SetMetaData(request)
+}
+
+func (i *pdfiumInstance) SetPageLabels(request *requests.SetPageLabels) (resp *responses.SetPageLabels, err error) {
+	if i.closed {
+		return nil, errors.New("instance is closed")
+	}
+
+	defer func() {
+		if panicError := recover(); panicError != nil {
+			err = fmt.Errorf("panic occurred in %s: %v", "SetPageLabels", panicError)
+		}
+	}()
+
+	return i.pdfium.SetPageLabels(request)
+}
+
+func (i *pdfiumInstance) SignDocument(request *requests.SignDocument) (resp *responses.SignDocument, err error) {
+	if i.closed {
+		return nil, errors.New("instance is closed")
+	}
+
+	defer func() {
+		if panicError := recover(); panicError != nil {
+			err = fmt.Errorf("panic occurred in %s: %v", "SignDocument", panicError)
+		}
+	}()
+
+	return i.pdfium.SignDocument(request)
+}
+
+func (i *pdfiumInstance) VerifySignatures(request *requests.VerifySignatures) (resp *responses.VerifySignatures, err error) {
+	if i.closed {
+		return nil, errors.New("instance is closed")
+	}
+
+	defer func() {
+		if panicError := recover(); panicError != nil {
+			err = fmt.Errorf("panic occurred in %s: %v", "VerifySignatures", panicError)
+		}
+	}()
+
+	return i.pdfium.VerifySignatures(request)
+}